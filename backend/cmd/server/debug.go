@@ -0,0 +1,53 @@
+package main
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"net/http/pprof"
+	"time"
+
+	"hr-recruiting/internal/config"
+)
+
+// newDebugServer serves the pprof endpoints on their own port, so they are
+// never reachable through the public router. When a secret is configured,
+// requests must carry it as the HTTP basic auth password. It returns nil
+// when profiling is disabled.
+func newDebugServer(cfg config.DebugConfig) *http.Server {
+	if !cfg.PProfEnabled {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+
+	var handler http.Handler = mux
+	if cfg.PProfSecret != "" {
+		handler = requirePassword(cfg.PProfSecret, mux)
+	}
+
+	return &http.Server{
+		Addr:              ":" + cfg.PProfPort,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+		// CPU profiles and traces stream for as long as the caller asks
+		WriteTimeout: 0,
+	}
+}
+
+// requirePassword rejects requests whose basic auth password is not secret
+func requirePassword(secret string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, password, ok := r.BasicAuth()
+		if !ok || subtle.ConstantTimeCompare([]byte(password), []byte(secret)) != 1 {
+			w.Header().Set("WWW-Authenticate", `Basic realm="pprof"`)
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
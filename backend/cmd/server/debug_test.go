@@ -0,0 +1,67 @@
+package main
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"hr-recruiting/internal/config"
+)
+
+func TestNewDebugServer_DisabledByDefault(t *testing.T) {
+	t.Setenv("PPROF_ENABLED", "")
+	cfg, err := config.Load(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	if err != nil {
+		t.Fatalf("config.Load() error = %v", err)
+	}
+
+	if cfg.Debug.PProfEnabled {
+		t.Fatal("PProfEnabled = true without PPROF_ENABLED, want profiling off by default")
+	}
+	if server := newDebugServer(cfg.Debug); server != nil {
+		t.Fatalf("newDebugServer() = %v with profiling disabled, want nil", server)
+	}
+}
+
+func TestNewDebugServer(t *testing.T) {
+	tests := []struct {
+		name       string
+		secret     string
+		password   string
+		wantStatus int
+	}{
+		{name: "no secret configured", wantStatus: http.StatusOK},
+		{name: "correct password", secret: "pprof-secret", password: "pprof-secret", wantStatus: http.StatusOK},
+		{name: "wrong password", secret: "pprof-secret", password: "guess", wantStatus: http.StatusUnauthorized},
+		{name: "no credentials", secret: "pprof-secret", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newDebugServer(config.DebugConfig{PProfEnabled: true, PProfPort: "6061", PProfSecret: tt.secret})
+			if server == nil || server.Addr != ":6061" {
+				t.Fatalf("newDebugServer() = %v, want a server on :6061", server)
+			}
+			if server.Handler == http.DefaultServeMux {
+				t.Fatal("debug server uses http.DefaultServeMux")
+			}
+
+			for _, path := range []string{"/debug/pprof/", "/debug/pprof/cmdline", "/debug/pprof/symbol"} {
+				req := httptest.NewRequest(http.MethodGet, path, nil)
+				if tt.password != "" {
+					req.SetBasicAuth("admin", tt.password)
+				}
+				rec := httptest.NewRecorder()
+				server.Handler.ServeHTTP(rec, req)
+
+				if rec.Code != tt.wantStatus {
+					t.Fatalf("GET %s status = %d, want %d", path, rec.Code, tt.wantStatus)
+				}
+				if tt.wantStatus == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") != `Basic realm="pprof"` {
+					t.Fatalf("WWW-Authenticate = %q, want the pprof realm", rec.Header().Get("WWW-Authenticate"))
+				}
+			}
+		})
+	}
+}
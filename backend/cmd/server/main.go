@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
@@ -12,14 +13,20 @@ import (
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
-	"github.com/go-chi/cors"
 	"github.com/joho/godotenv"
+	"github.com/oschwald/maxminddb-golang"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
 
 	"hr-recruiting/internal/config"
 	"hr-recruiting/internal/gateway"
 	"hr-recruiting/internal/handlers"
+	handlersv2 "hr-recruiting/internal/handlers/v2"
+	"hr-recruiting/internal/metrics"
 	appMiddleware "hr-recruiting/internal/middleware"
 	"hr-recruiting/internal/services"
+	"hr-recruiting/internal/telemetry"
+	"hr-recruiting/internal/util"
 )
 
 func main() {
@@ -28,19 +35,226 @@ func main() {
 		log.Println("No .env file found, using environment variables")
 	}
 
+	// Structured logging; the standard logger is routed through it as well
+	logConfig := config.LoadLogConfig()
+	logger := appMiddleware.NewLogger(os.Stdout, logConfig.Level, logConfig.Format)
+	slog.SetDefault(logger)
+
 	// Load configuration
-	cfg := config.Load()
+	cfg, err := config.Load(logger)
+	errs := config.Validate(cfg)
+	if err != nil {
+		errs = append([]error{err}, errs...)
+	}
+	if len(errs) > 0 {
+		for _, err := range errs {
+			log.Printf("❌ Invalid configuration: %v", err)
+		}
+		os.Exit(1)
+	}
+
+	// Tracing is disabled unless an OTLP endpoint is configured
+	tracerProvider, shutdownTracing, err := telemetry.Setup(context.Background(), cfg.Telemetry.OTLPEndpoint, cfg.Telemetry.ServiceName)
+	if err != nil {
+		log.Fatalf("❌ Failed to set up tracing: %v", err)
+	}
+
+	var appMetrics *metrics.Metrics
+	if cfg.Metrics.Enabled {
+		appMetrics = metrics.New()
+	}
+
+	// Pagination cursors are signed so clients can't forge offsets
+	if cfg.Server.CursorSecret == "" {
+		log.Println("CURSOR_SECRET not set, pagination cursors will not survive restarts")
+	}
+	util.ConfigureCursors(cfg.Server.CursorSecret, cfg.Server.CursorTTL)
+
+	if cfg.HubHRMS.WebhookSecret == "" {
+		log.Println("HUBHRMS_WEBHOOK_SECRET not set, Hub-HRMS callbacks will be refused")
+	}
 
 	// Initialize services
-	hubHRMSClient := gateway.NewHubHRMSClient(cfg.HubHRMS.URL, cfg.HubHRMS.APIKey)
-	uploadService := services.NewUploadService(cfg.AWS.S3Bucket, cfg.AWS.Region)
+	jwtValidator, err := appMiddleware.NewJWTValidator(cfg.JWT.Secret, cfg.JWT.PublicKeyPath, cfg.JWT.Issuer)
+	if err != nil {
+		log.Fatalf("❌ Failed to configure JWT validation: %v", err)
+	}
+	if cfg.Auth.ClientsFile != "" {
+		cfg.Auth.Clients, err = config.LoadOAuthClients(cfg.Auth.ClientsFile)
+		if err != nil {
+			log.Fatalf("❌ Failed to load OAuth clients: %v", err)
+		}
+	}
+	apiKeys, err := appMiddleware.NewAPIKeyStore(cfg.Auth.APIKeysFile)
+	if err != nil {
+		log.Fatalf("❌ Failed to load API keys: %v", err)
+	}
+	clientOptions := []gateway.ClientOption{
+		gateway.WithIdleConnCheck(cfg.HubHRMS.IdleConnCheckInterval),
+		gateway.WithRetry(cfg.HubHRMS.RetryMaxAttempts, cfg.HubHRMS.RetryBaseDelay),
+		gateway.WithBatching(cfg.HubHRMS.BatchEnabled),
+		gateway.WithMaxConcurrentQueries(cfg.HubHRMS.MaxConcurrentQueries),
+		gateway.WithLogger(logger),
+		gateway.WithTracer(tracerProvider),
+		gateway.WithMetrics(appMetrics),
+		gateway.WithQueryLimits(cfg.GraphQL.MaxDepth, cfg.GraphQL.MaxComplexity),
+		gateway.WithDebugLog(cfg.HubHRMS.DebugLog),
+	}
+	if cfg.GraphQL.AllowlistEnabled {
+		operations, err := gateway.LoadOperationAllowlist(cfg.GraphQL.AllowlistPath)
+		if err != nil {
+			log.Fatalf("❌ Failed to configure GraphQL allowlist: %v", err)
+		}
+		clientOptions = append(clientOptions, gateway.WithOperationAllowlist(operations))
+	}
+	persistedQueries, err := gateway.NewPersistedQueryStore(cfg.HubHRMS.PersistedQueriesFile)
+	if err != nil {
+		log.Fatalf("❌ Failed to load persisted queries: %v", err)
+	}
+	clientOptions = append(clientOptions, gateway.WithPersistedQueries(persistedQueries, cfg.HubHRMS.PersistedQueriesOnly))
+	hubHRMSClient := gateway.NewHubHRMSClientWithOptions(cfg.HubHRMS.URL, cfg.HubHRMS.APIKey, gateway.HubHRMSClientOptions{
+		MaxConnsPerHost:     cfg.HubHRMS.MaxConnsPerHost,
+		MaxIdleConns:        cfg.HubHRMS.MaxIdleConns,
+		MaxIdleConnsPerHost: cfg.HubHRMS.MaxIdleConnsPerHost,
+		IdleConnTimeout:     cfg.HubHRMS.IdleConnTimeout,
+		RequestTimeout:      cfg.HubHRMS.RequestTimeout,
+		TLSHandshakeTimeout: cfg.HubHRMS.TLSHandshakeTimeout,
+	}, clientOptions...)
+	uploadService := services.NewUploadService(cfg.AWS.S3Bucket, cfg.AWS.Region, hubHRMSClient, cfg.Upload.MaxFilesPerCandidate)
 	emailService := services.NewEmailService(cfg.Email.SendGridKey)
+	emailQueue := services.NewEmailQueue(emailService, cfg.Email.WorkerCount, cfg.Email.QueueSize)
+	biasDetector := services.NewBiasDetector()
+	snsVerifier := services.NewSNSVerifier(cfg.AWS.UploadTopicARN)
+	exchangeRateService := services.NewExchangeRateService(cfg.Exchange.APIURL, cfg.Exchange.APIKey)
+	dedupStore, err := services.NewDeduplicationStore(cfg.Cache.RedisURL)
+	if err != nil {
+		log.Fatalf("❌ Failed to configure deduplication store: %v", err)
+	}
+	webhookService := services.NewWebhookService(cfg.Features.WebhookDelivery.Load)
+
+	// Warn about queries an older or newer Hub-HRMS would reject, without
+	// refusing to start or waiting for the schema to load
+	if cfg.HubHRMS.ValidateQueries {
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), cfg.HubHRMS.RequestTimeout)
+			defer cancel()
+			schema, err := hubHRMSClient.Schema(ctx)
+			if err != nil {
+				slog.Warn("skipping query validation", "error", err)
+				return
+			}
+			for _, mismatch := range gateway.ValidateOperations(schema) {
+				slog.Warn("query does not match hub-hrms schema", "operation", mismatch.Operation, "error", mismatch.Err)
+			}
+		}()
+	}
+
+	if appMetrics != nil {
+		factory := promauto.With(appMetrics.Registry())
+		factory.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "hubhrms_pool_open_connections",
+			Help: "Open connections to Hub-HRMS.",
+		}, func() float64 {
+			return float64(hubHRMSClient.PoolStats().OpenConns)
+		})
+		factory.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "hubhrms_pool_active_connections",
+			Help: "Connections to Hub-HRMS serving a request.",
+		}, func() float64 {
+			return float64(hubHRMSClient.ConnectionStats().ActiveConns)
+		})
+		factory.NewGaugeFunc(prometheus.GaugeOpts{
+			Name: "email_queue_depth",
+			Help: "Emails waiting to be sent.",
+		}, func() float64 {
+			return float64(emailQueue.Depth())
+		})
+	}
 	
 	// Initialize handlers
-	jobHandler := handlers.NewJobHandler(hubHRMSClient)
-	applicationHandler := handlers.NewApplicationHandler(hubHRMSClient, uploadService, emailService)
-	analyticsHandler := handlers.NewAnalyticsHandler(hubHRMSClient)
-	healthHandler := handlers.NewHealthHandler(hubHRMSClient)
+	salaryValidator, err := services.LoadSalaryValidator(cfg.Salary.BenchmarkFile)
+	if err != nil {
+		log.Fatalf("❌ Failed to load salary benchmarks: %v", err)
+	}
+	jobHandler := handlers.NewJobHandler(hubHRMSClient, biasDetector, services.NewJobQualityScorer(), salaryValidator, webhookService, handlers.SiteInfo{
+		BaseURL:        cfg.Server.BaseURL,
+		CompanyName:    cfg.Company.Name,
+		CompanyLogoURL: cfg.Company.LogoURL,
+	}, logger)
+	pipelineEvents := services.NewPipelineEventBus()
+	calendarService := services.NewCalendarService(cfg.Email.FromName, cfg.Email.FromEmail)
+	googleCalendar, err := services.NewGoogleCalendarService(context.Background(), cfg.GoogleCalendar.ServiceAccountFile, cfg.GoogleCalendar.CalendarID, cfg.GoogleCalendar.DelegatedUser)
+	if err != nil {
+		log.Fatalf("❌ Failed to load Google Calendar credentials: %v", err)
+	}
+	privacyTokens := util.NewTokenSigner(cfg.Privacy.TokenSecret, cfg.Privacy.TokenTTL)
+	if cfg.Privacy.ShareLinkSecret == "" {
+		log.Println("SHARE_LINK_SECRET not set, application share links will not survive restarts")
+	}
+	shareLinks := services.NewShareLinkService(hubHRMSClient, cfg.Privacy.ShareLinkSecret)
+	var chatNotifiers []services.Notifier
+	if cfg.Slack.WebhookURL != "" {
+		chatNotifiers = append(chatNotifiers, services.NewSlackNotifier(cfg.Slack.WebhookURL, cfg.Slack.Channel, cfg.Slack.NotifyOnStatuses))
+	}
+	if cfg.Teams.WebhookURL != "" {
+		chatNotifiers = append(chatNotifiers, services.NewTeamsNotifier(cfg.Teams.WebhookURL, cfg.Teams.NotifyOnStatuses))
+	}
+	chatNotifications := services.NewNotificationBroadcaster(chatNotifiers...)
+	notificationPreferences := services.NewNotificationPreferenceStore(hubHRMSClient, services.NotificationPreferenceTTL)
+	services.SetPDFConverterPath(cfg.PDF.ConverterPath)
+	skillNormalizer, err := services.LoadSkillNormalizer(cfg.Skills.TaxonomyFile)
+	if err != nil {
+		log.Fatalf("❌ Failed to load skill taxonomy: %v", err)
+	}
+	pipelineStages := services.NewPipelineStageService(hubHRMSClient, services.PipelineStageTTL)
+	applicationHandler := handlers.NewApplicationHandler(handlers.ApplicationHandlerOptions{
+		Client:             hubHRMSClient,
+		UploadService:      uploadService,
+		EmailService:       emailService,
+		EmailQueue:         emailQueue,
+		DedupStore:         dedupStore,
+		DedupWindow:        cfg.Cache.DedupWindow,
+		Webhooks:           webhookService,
+		PipelineEvents:     pipelineEvents,
+		StrictTransitions:  cfg.Workflow.StrictTransitions,
+		Stages:             pipelineStages,
+		Features:           cfg.Features,
+		ScoringConcurrency: cfg.Scoring.Concurrency,
+		PrivacyTokens:      privacyTokens,
+		BaseURL:            cfg.Server.BaseURL,
+		Calendar:           calendarService,
+		GCal:               googleCalendar,
+		Preferences:        notificationPreferences,
+		Skills:             skillNormalizer,
+		ShareLinks:         shareLinks,
+		Chat:               chatNotifications,
+		Logger:             logger,
+	})
+	analyticsHandler := handlers.NewAnalyticsHandler(hubHRMSClient, exchangeRateService, cfg.Exchange.BaseCurrency, pipelineEvents, logger)
+	healthMonitor := services.NewHealthMonitor(hubHRMSClient, services.HealthProbeInterval)
+	healthHandler := handlers.NewHealthHandler(hubHRMSClient, healthMonitor)
+	webhookHandler := handlers.NewWebhookHandler(hubHRMSClient, uploadService, snsVerifier, emailQueue, pipelineEvents, webhookService, cfg.Features, logger)
+	subscriptionHandler := handlers.NewSubscriptionHandler(webhookService)
+	corsManager := config.NewCORSManager(cfg.CORS.AllowedOrigins, config.CORSOverrideFile)
+	auditLog, err := appMiddleware.NewAuditLog(cfg.Audit.LogPath)
+	if err != nil {
+		log.Fatalf("❌ Failed to open audit log: %v", err)
+	}
+	if cfg.Audit.LogPath == "" {
+		log.Println("AUDIT_LOG_PATH not set, audit entries go to stdout")
+	}
+	adminHandler := handlers.NewAdminHandler(emailService, corsManager, cfg.Features, services.NewBlacklistChecker(hubHRMSClient), auditLog, persistedQueries)
+	userHandler := handlers.NewUserHandler(notificationPreferences)
+	skillHandler := handlers.NewSkillHandler(skillNormalizer)
+	candidatePools := services.NewCandidatePoolService(hubHRMSClient)
+	candidatePoolHandler := handlers.NewCandidatePoolHandler(candidatePools, services.NewCampaignService(hubHRMSClient, candidatePools, emailQueue, emailService.Templates()))
+	referralHandler := handlers.NewReferralHandler(hubHRMSClient)
+	pipelineHandler := handlers.NewPipelineHandler(pipelineStages)
+	recruiterHandler := handlers.NewRecruiterHandler(services.NewAssignmentService(hubHRMSClient), chatNotifications, cfg.Server.BaseURL)
+	authHandler := handlers.NewAuthHandler(cfg.Auth.Clients, cfg.JWT.Secret, cfg.JWT.Issuer, cfg.Auth.TokenTTL, apiKeys)
+	jobHandlerV2 := handlersv2.NewJobHandler(hubHRMSClient)
+	applicationHandlerV2 := handlersv2.NewApplicationHandler(hubHRMSClient)
+	candidateHandlerV2 := handlersv2.NewCandidateHandler(hubHRMSClient)
 
 	// Setup router
 	r := chi.NewRouter()
@@ -48,78 +262,266 @@ func main() {
 	// Global middleware
 	r.Use(middleware.RequestID)
 	r.Use(middleware.RealIP)
-	r.Use(middleware.Logger)
+	r.Use(appMiddleware.Tracing(tracerProvider))
+	r.Use(appMiddleware.Metrics(appMetrics))
+	r.Use(appMiddleware.StructuredLogger(logger))
 	r.Use(middleware.Recoverer)
 	r.Use(middleware.Compress(5))
-	r.Use(middleware.Timeout(60 * time.Second))
-
-	// CORS
-	r.Use(cors.Handler(cors.Options{
-		AllowedOrigins:   cfg.CORS.AllowedOrigins,
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
-		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
-		ExposedHeaders:   []string{"Link", "X-Total-Count"},
-		AllowCredentials: true,
-		MaxAge:           300,
-	}))
+
+	// CORS (origins can be changed at runtime by admins)
+	r.Use(corsManager.Handler())
 
 	// Custom middleware
-	r.Use(appMiddleware.AuthMiddleware)
+	r.Use(appMiddleware.AuthMiddleware(jwtValidator, apiKeys))
+	r.Use(appMiddleware.AuditLogger(auditLog))
 
 	// Health check (no auth required)
 	r.Get("/health", healthHandler.Health)
 	r.Get("/health/live", healthHandler.Liveness)
 	r.Get("/health/ready", healthHandler.Readiness)
+	r.Get("/health/metrics", healthHandler.HealthMetrics)
+
+	// Prometheus scrape endpoint (no auth required)
+	if appMetrics != nil {
+		r.Handle(cfg.Metrics.Path, appMetrics.Handler())
+	}
+
+	// S3 upload notifications via SNS (verified by message signature)
+	r.Post("/webhooks/upload/complete", webhookHandler.UploadComplete)
 
 	// GraphQL proxy to Hub-HRMS
 	r.Post("/graphql", hubHRMSClient.ProxyHandler)
 
+	// Rate limiters (by IP for public routes, by user ID once authenticated)
+	applicationLimiter := appMiddleware.NewRateLimiter(cfg.RateLimit.Applications.RPS, cfg.RateLimit.Applications.Burst)
+	uploadLimiter := appMiddleware.NewRateLimiter(cfg.RateLimit.Uploads.RPS, cfg.RateLimit.Uploads.Burst)
+	authenticatedLimiter := appMiddleware.NewRateLimiter(cfg.RateLimit.Authenticated.RPS, cfg.RateLimit.Authenticated.Burst)
+	privacyLimiter := appMiddleware.NewRateLimiter(cfg.RateLimit.Privacy.RPS, cfg.RateLimit.Privacy.Burst)
+
+	// Public job board responses are cached; job mutations evict them
+	jobCache := appMiddleware.NewResponseCache(cfg.Cache.Size, cfg.Cache.JobTTL)
+	evictJob := jobCache.InvalidateOnSuccess(func(r *http.Request) []string {
+		id := chi.URLParam(r, "id")
+		return []string{"/api/v1/jobs", "/api/v1/jobs/" + id, "/api/v1/jobs/" + id + "/schema.json"}
+	})
+
+	// Candidate data and exports may only be read from allowed countries
+	var ipDB *maxminddb.Reader
+	if cfg.GeoRestriction.Enabled {
+		if ipDB, err = maxminddb.Open(cfg.GeoRestriction.MaxMindDBPath); err != nil {
+			log.Fatalf("❌ Failed to load MaxMind DB: %v", err)
+		}
+	}
+	geoRestricted := appMiddleware.GeoRestrict(cfg.GeoRestriction.AllowedCountries, ipDB)
+
+	// Retried mutations replay their first response instead of running twice
+	idempotent := appMiddleware.IdempotencyMiddleware(dedupStore, appMiddleware.IdempotencyTTL, logger)
+
+	// The Atom feed is polled by aggregators and may lag by a few minutes
+	feedCache := appMiddleware.NewResponseCache(1, 5*time.Minute)
+
+	// Search engines crawl the sitemap rarely; an hour's lag is fine
+	sitemapCache := appMiddleware.NewResponseCache(16, time.Hour)
+	r.With(sitemapCache.Middleware).Get("/sitemap.xml", jobHandler.Sitemap)
+
+	// Validated with the rest of the configuration, so the error is moot
+	v1Sunset, _ := time.Parse("2006-01-02", cfg.API.V1SunsetDate)
+
 	// API Routes
 	r.Route("/api/v1", func(r chi.Router) {
+		r.Use(appMiddleware.Deprecation(v1Sunset, "/api/v2"))
+
+		// Machine callers, which authenticate themselves and send no cookies
+		r.Group(func(r chi.Router) {
+			// Callbacks from Hub-HRMS (verified by HMAC signature)
+			r.With(appMiddleware.HubHRMSWebhookVerifier(cfg.HubHRMS.WebhookSecret)).Post("/webhooks/hubhrms", webhookHandler.HubHRMSEvent)
+
+			// Machine-to-machine tokens (OAuth2 client credentials)
+			r.Post("/auth/token", authHandler.IssueToken)
+		})
+
+		// CSRF token for browser clients to echo on public mutations
+		r.Get("/csrf-token", appMiddleware.CSRFToken)
+
 		// Public routes
 		r.Group(func(r chi.Router) {
+			r.Use(appMiddleware.CSRFMiddleware)
+
 			// Jobs
-			r.Get("/jobs", jobHandler.ListJobs)
-			r.Get("/jobs/{id}", jobHandler.GetJob)
+			r.With(appMiddleware.WithTimeout(10*time.Second), jobCache.Middleware).Get("/jobs", jobHandler.ListJobs)
+			r.Get("/jobs/suggest", jobHandler.SuggestJobs)
+			r.With(feedCache.Middleware).Get("/jobs/feed.xml", jobHandler.JobFeed)
+			r.With(appMiddleware.ConditionalGet, appMiddleware.ABVariant, jobCache.Middleware).Get("/jobs/{id}", jobHandler.GetJob)
+			r.With(jobCache.Middleware).Get("/jobs/{id}/schema.json", jobHandler.JobSchema)
+			r.With(appMiddleware.ABVariant).Get("/jobs/by-slug/{slug}", jobHandler.GetJobBySlug)
 			r.Post("/jobs/{id}/view", jobHandler.IncrementView)
 
+			// Applications shared with outside reviewers
+			r.Get("/shared/applications/{token}", applicationHandler.GetSharedApplication)
+
+			// Skills taxonomy for autocomplete
+			r.Get("/skills", skillHandler.ListSkills)
+
+			// Employee referrals
+			r.With(applicationLimiter).Post("/referrals", referralHandler.CreateReferral)
+
 			// Applications (public submission)
-			r.Post("/applications", applicationHandler.SubmitApplication)
+			r.With(appMiddleware.WithTimeout(30*time.Second), applicationLimiter, idempotent, applicationHandler.WithDraft, appMiddleware.ValidateBody("submit_application")).Post("/applications", applicationHandler.SubmitApplication)
+			r.With(applicationLimiter, appMiddleware.ValidateBody("draft_application")).Post("/applications/draft", applicationHandler.SaveDraft)
+			r.Get("/applications/draft/{draftId}", applicationHandler.GetDraft)
+			r.Delete("/applications/draft/{draftId}", applicationHandler.DeleteDraft)
 
 			// File upload (public for candidates)
-			r.Post("/upload/resume", uploadService.UploadResume)
-			r.Post("/upload/presigned-url", uploadService.GetPresignedURL)
+			r.With(uploadLimiter).Post("/upload/resume", uploadService.UploadResume)
+			r.With(uploadLimiter).Post("/upload/presigned-url", uploadService.GetPresignedURL)
+			r.With(uploadLimiter).Post("/upload/attachments", uploadService.UploadApplicationAttachment)
+
+			// Candidate data requests, authorized by an emailed token
+			r.With(geoRestricted, privacyLimiter).Post("/candidates/{id}/data-export-token", applicationHandler.RequestDataExportToken)
+			r.With(geoRestricted, privacyLimiter).Get("/candidates/{id}/data-export", applicationHandler.ExportCandidateData)
+			r.With(privacyLimiter).Delete("/candidates/{id}", applicationHandler.DeleteCandidateData)
 		})
 
 		// Protected routes (require authentication)
 		r.Group(func(r chi.Router) {
 			r.Use(appMiddleware.RequireAuth)
+			r.Use(authenticatedLimiter)
 
 			// Job management (recruiters/admins)
-			r.Post("/jobs", jobHandler.CreateJob)
-			r.Put("/jobs/{id}", jobHandler.UpdateJob)
-			r.Post("/jobs/{id}/publish", jobHandler.PublishJob)
-			r.Post("/jobs/{id}/close", jobHandler.CloseJob)
-			r.Delete("/jobs/{id}", jobHandler.DeleteJob)
-			r.Post("/jobs/generate-description", jobHandler.GenerateDescription)
+			r.With(appMiddleware.RequireRole("recruiter", "admin"), appMiddleware.ValidateBody("create_job"), evictJob).Post("/jobs", jobHandler.CreateJob)
+			r.With(appMiddleware.RequireRole("recruiter", "admin"), evictJob).Put("/jobs/{id}", jobHandler.UpdateJob)
+			r.With(appMiddleware.RequireRole("recruiter", "admin"), evictJob).Post("/jobs/{id}/clone", jobHandler.CloneJob)
+			r.With(appMiddleware.RequireRole("recruiter", "admin"), evictJob).Patch("/jobs/{id}/slug", jobHandler.UpdateJobSlug)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Get("/jobs/{id}/quality-score", jobHandler.GetQualityScore)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Get("/jobs/{id}/readability", jobHandler.GetReadability)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Get("/jobs/{id}/rejection-rules", jobHandler.GetRejectionRules)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Put("/jobs/{id}/rejection-rules", jobHandler.UpdateRejectionRules)
+			r.With(appMiddleware.RequireRole("admin"), evictJob).Post("/jobs/{id}/ab-test", jobHandler.CreateABTest)
+			r.With(appMiddleware.RequireRole("recruiter", "admin"), evictJob).Post("/jobs/{id}/publish", jobHandler.PublishJob)
+			r.With(appMiddleware.RequireRole("recruiter", "admin"), evictJob).Post("/jobs/{id}/close", jobHandler.CloseJob)
+			r.With(appMiddleware.RequireRole("recruiter", "admin"), evictJob).Delete("/jobs/{id}", jobHandler.DeleteJob)
+			r.With(appMiddleware.WithTimeout(120*time.Second)).Post("/jobs/generate-description", jobHandler.GenerateDescription)
 
 			// Application management (recruiters)
 			r.Get("/applications", applicationHandler.ListApplications)
-			r.Get("/applications/{id}", applicationHandler.GetApplication)
-			r.Put("/applications/{id}/status", applicationHandler.UpdateStatus)
+			r.With(geoRestricted, appMiddleware.RequireRole("recruiter", "admin"), appMiddleware.ExtendDeadline(5*time.Minute)).Get("/applications/export", applicationHandler.ExportApplications)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Get("/applications/search", applicationHandler.SearchApplications)
+			r.With(appMiddleware.ConditionalGet).Get("/applications/{id}", applicationHandler.GetApplication)
+			r.Get("/applications/{id}/timeline", applicationHandler.GetApplicationTimeline)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Get("/applications/{id}/experience-score", applicationHandler.GetExperienceScore)
+			r.Get("/applications/{id}/resume", applicationHandler.DownloadResume)
+			r.Get("/applications/{id}/resume-url", applicationHandler.GetResumeURL)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Get("/applications/{id}/resume-text", applicationHandler.GetResumeText)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Post("/applications/{id}/interview", applicationHandler.ScheduleInterview)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Delete("/applications/{id}/interview", applicationHandler.CancelInterview)
+			r.Get("/applications/{id}/interview/ics", applicationHandler.DownloadInterviewICS)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Get("/applications/{id}/report.pdf", applicationHandler.ApplicationReport)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Post("/applications/{id}/share-link", applicationHandler.CreateShareLink)
+			r.With(appMiddleware.RequireRole("recruiter", "admin"), idempotent, appMiddleware.ValidateBody("update_status")).Put("/applications/{id}/status", applicationHandler.UpdateStatus)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Get("/applications/notes/tags", applicationHandler.GetNoteTags)
+			r.Get("/applications/{id}/notes", applicationHandler.GetNotes)
 			r.Post("/applications/{id}/notes", applicationHandler.AddNote)
 			r.Post("/applications/{id}/score", applicationHandler.ScoreApplication)
-			r.Post("/applications/bulk-update", applicationHandler.BulkUpdateStatus)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Post("/applications/{id}/scoring-feedback", applicationHandler.SubmitScoringFeedback)
+			r.With(idempotent).Post("/applications/bulk-update", applicationHandler.BulkUpdateStatus)
+			r.With(appMiddleware.RequireRole("admin")).Post("/applications/{id}/assign", recruiterHandler.AssignApplication)
+			r.With(appMiddleware.RequireRole("admin")).Post("/applications/auto-assign", recruiterHandler.AutoAssign)
+			r.With(appMiddleware.RequireRole("admin")).Get("/recruiters/workload", recruiterHandler.GetWorkload)
+			r.With(appMiddleware.RequireRole("recruiter", "admin"), appMiddleware.WithTimeout(300*time.Second)).Post("/applications/bulk-score", applicationHandler.BulkScoreApplications)
+
+			// Offers
+			r.With(appMiddleware.RequireRole("admin")).Post("/applications/{id}/offer", applicationHandler.SendOffer)
+
+			// Counter offers (approval is for hiring managers)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Post("/applications/{id}/offer/counter", applicationHandler.RecordCounterOffer)
+			r.With(appMiddleware.RequireRole("manager", "admin")).Post("/applications/{id}/offer/counter/{counterId}/approve", applicationHandler.ApproveCounterOffer)
+			r.With(appMiddleware.RequireRole("manager", "admin")).Post("/applications/{id}/offer/counter/{counterId}/reject", applicationHandler.RejectCounterOffer)
 
 			// Analytics (recruiters/admins)
-			r.Get("/analytics/metrics", analyticsHandler.GetMetrics)
+			r.With(appMiddleware.RequireRole("admin")).Get("/analytics/metrics", analyticsHandler.GetMetrics)
+			r.With(appMiddleware.RequireRole("admin")).Get("/analytics/scoring/accuracy", analyticsHandler.GetScoringAccuracy)
 			r.Get("/analytics/jobs/{id}/performance", analyticsHandler.GetJobPerformance)
+			r.Get("/analytics/jobs/{id}/ab-results", analyticsHandler.GetJobABResults)
 			r.Get("/analytics/pipeline", analyticsHandler.GetPipeline)
+			r.With(appMiddleware.ExtendDeadline(30*time.Minute)).Get("/analytics/pipeline/stream", analyticsHandler.StreamPipeline)
 			r.Get("/analytics/trends", analyticsHandler.GetTrends)
+			r.Get("/analytics/salary-ranges", analyticsHandler.GetSalaryRanges)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Get("/analytics/sources", analyticsHandler.GetSources)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Get("/analytics/referrals", analyticsHandler.GetReferrals)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Get("/analytics/departments", analyticsHandler.GetDepartments)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Get("/analytics/departments/{department}", analyticsHandler.GetDepartment)
+			r.With(appMiddleware.RequireRole("recruiter", "admin")).Get("/analytics/candidate-experience", analyticsHandler.GetCandidateExperience)
 
 			// Candidate management
-			r.Get("/candidates/{id}", applicationHandler.GetCandidate)
+			r.With(geoRestricted).Get("/candidates/{id}", applicationHandler.GetCandidate)
+			r.With(geoRestricted).Get("/candidates/{id}/resume", applicationHandler.DownloadCandidateResume)
+			r.With(geoRestricted, appMiddleware.RequireRole("recruiter", "admin")).Get("/candidates/{id}/files", applicationHandler.ListCandidateFiles)
 			r.Put("/candidates/{id}", applicationHandler.UpdateCandidate)
+
+			// Saved candidate pools (recruiters/admins)
+			r.Group(func(r chi.Router) {
+				r.Use(appMiddleware.RequireRole("recruiter", "admin"))
+				r.Post("/candidate-pools", candidatePoolHandler.CreatePool)
+				r.Get("/candidate-pools", candidatePoolHandler.ListPools)
+				r.Get("/candidate-pools/{id}", candidatePoolHandler.GetPool)
+				r.Put("/candidate-pools/{id}", candidatePoolHandler.UpdatePool)
+				r.Delete("/candidate-pools/{id}", candidatePoolHandler.DeletePool)
+				r.Post("/candidate-pools/{id}/refresh", candidatePoolHandler.RefreshPool)
+				r.Post("/candidate-pools/{id}/campaign", candidatePoolHandler.StartCampaign)
+				r.Get("/candidate-pools/{id}/campaigns", candidatePoolHandler.ListCampaigns)
+			})
+
+			// Pipeline stages (changing them is for admins)
+			r.Get("/pipeline/stages", pipelineHandler.GetStages)
+			r.With(appMiddleware.RequireRole("admin")).Put("/pipeline/stages", pipelineHandler.UpdateStages)
+
+			// Signed-in user settings
+			r.Get("/users/me/notifications", userHandler.GetNotificationPreferences)
+			r.Put("/users/me/notifications", userHandler.UpdateNotificationPreferences)
+
+			// A referrer's own referral history
+			r.Get("/referrals/{referrerId}", referralHandler.GetReferrerHistory)
+
+			// Outbound event webhooks
+			r.Get("/webhooks", subscriptionHandler.ListWebhooks)
+			r.Post("/webhooks", subscriptionHandler.RegisterWebhook)
+			r.Delete("/webhooks/{id}", subscriptionHandler.UnregisterWebhook)
+
+			// Admin tooling
+			r.With(appMiddleware.RequireRole("admin")).Post("/admin/email-preview", adminHandler.PreviewEmail)
+			r.With(appMiddleware.RequireRole("admin")).Put("/admin/cors", adminHandler.UpdateCORSOrigins)
+			r.With(appMiddleware.RequireRole("admin")).Get("/admin/features", adminHandler.ListFeatures)
+			r.With(appMiddleware.RequireRole("admin")).Put("/admin/features/{name}", adminHandler.SetFeature)
+			r.With(appMiddleware.RequireRole("admin")).Post("/admin/blacklist", adminHandler.AddToBlacklist)
+			r.With(appMiddleware.RequireRole("admin")).Get("/admin/blacklist", adminHandler.ListBlacklist)
+			r.With(appMiddleware.RequireRole("admin")).Get("/admin/persisted-queries", adminHandler.ListPersistedQueries)
+			r.With(appMiddleware.RequireRole("admin")).Post("/admin/persisted-queries", adminHandler.RegisterPersistedQuery)
+			r.With(appMiddleware.RequireRole("admin")).Delete("/admin/blacklist/{id}", adminHandler.RemoveFromBlacklist)
+			r.With(appMiddleware.RequireRole("admin")).Get("/admin/audit", adminHandler.GetAuditLog)
+			r.With(appMiddleware.RequireRole("admin")).Get("/auth/clients", authHandler.ListClients)
+			r.With(appMiddleware.RequireRole("admin")).Post("/admin/api-keys", authHandler.CreateAPIKey)
+			r.With(appMiddleware.RequireRole("admin")).Delete("/admin/api-keys/{id}", authHandler.RevokeAPIKey)
+		})
+	})
+
+	// Typed API; v1 stays as it is until its sunset date
+	r.Route("/api/v2", func(r chi.Router) {
+		// Public routes
+		r.Group(func(r chi.Router) {
+			r.Use(appMiddleware.CSRFMiddleware)
+
+			r.With(appMiddleware.WithTimeout(10*time.Second)).Get("/jobs", jobHandlerV2.ListJobs)
+			r.With(appMiddleware.ConditionalGet).Get("/jobs/{id}", jobHandlerV2.GetJob)
+		})
+
+		// Protected routes (require authentication)
+		r.Group(func(r chi.Router) {
+			r.Use(appMiddleware.RequireAuth)
+			r.Use(authenticatedLimiter)
+
+			r.Get("/applications", applicationHandlerV2.ListApplications)
+			r.With(appMiddleware.ConditionalGet).Get("/applications/{id}", applicationHandlerV2.GetApplication)
+			r.Get("/candidates/{id}", candidateHandlerV2.GetCandidate)
 		})
 	})
 
@@ -137,6 +539,16 @@ func main() {
 		IdleTimeout:  60 * time.Second,
 	}
 
+	var redirectServer *http.Server
+	if cfg.TLS.Enabled {
+		tlsConfig, certManager, err := newTLSConfig(cfg.TLS)
+		if err != nil {
+			log.Fatalf("❌ Failed to load TLS certificate: %v", err)
+		}
+		server.TLSConfig = tlsConfig
+		redirectServer = newRedirectServer(cfg.TLS, cfg.Server.Port, certManager)
+	}
+
 	// Graceful shutdown
 	done := make(chan os.Signal, 1)
 	signal.Notify(done, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
@@ -146,11 +558,52 @@ func main() {
 		log.Printf("📡 Hub-HRMS endpoint: %s", cfg.HubHRMS.URL)
 		log.Printf("🌍 Environment: %s", cfg.Server.Environment)
 		
-		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		// The certificate comes from TLSConfig, so no files are passed
+		serve := server.ListenAndServe
+		if cfg.TLS.Enabled {
+			serve = func() error { return server.ListenAndServeTLS("", "") }
+		}
+		if err := serve(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("❌ Server failed to start: %v", err)
 		}
 	}()
 
+	if redirectServer != nil {
+		go func() {
+			log.Printf("🔒 Redirecting HTTP on port %s to HTTPS", cfg.TLS.RedirectPort)
+			if err := redirectServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("⚠️  HTTP redirect server failed: %v", err)
+			}
+		}()
+	}
+
+	// Profiling endpoints, on their own port
+	debugServer := newDebugServer(cfg.Debug)
+	if debugServer != nil {
+		if cfg.Debug.PProfSecret == "" {
+			log.Println("PPROF_SECRET not set, pprof endpoints are unauthenticated")
+		}
+		go func() {
+			log.Printf("🔍 pprof listening on port %s", cfg.Debug.PProfPort)
+			if err := debugServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+				log.Printf("⚠️  pprof server failed: %v", err)
+			}
+		}()
+	}
+
+	// Probe Hub-HRMS in the background for /health/metrics
+	healthMonitor.Start()
+
+	// Close jobs past their closing date
+	jobScheduler := services.NewJobScheduler(hubHRMSClient, webhookService, cfg.Scheduler.JobExpirationInterval)
+	jobScheduler.Start()
+
+	var reminderScheduler *services.InterviewReminderScheduler
+	if cfg.Scheduler.InterviewReminderEnabled {
+		reminderScheduler = services.NewInterviewReminderScheduler(hubHRMSClient, emailQueue)
+		reminderScheduler.Start()
+	}
+
 	<-done
 	log.Println("🛑 Server shutting down...")
 
@@ -161,6 +614,50 @@ func main() {
 		log.Fatalf("❌ Server forced to shutdown: %v", err)
 	}
 
+	if redirectServer != nil {
+		if err := redirectServer.Shutdown(ctx); err != nil {
+			log.Printf("⚠️  HTTP redirect server did not stop cleanly: %v", err)
+		}
+	}
+
+	if debugServer != nil {
+		if err := debugServer.Shutdown(ctx); err != nil {
+			log.Printf("⚠️  pprof server did not stop cleanly: %v", err)
+		}
+	}
+
+	if err := healthMonitor.Shutdown(ctx); err != nil {
+		log.Printf("⚠️  Health monitor did not stop cleanly: %v", err)
+	}
+
+	if err := jobScheduler.Shutdown(ctx); err != nil {
+		log.Printf("⚠️  Job scheduler did not stop cleanly: %v", err)
+	}
+
+	if reminderScheduler != nil {
+		if err := reminderScheduler.Shutdown(ctx); err != nil {
+			log.Printf("⚠️  Interview reminder scheduler did not stop cleanly: %v", err)
+		}
+	}
+
+	if err := emailQueue.Shutdown(ctx); err != nil {
+		log.Printf("⚠️  Unsent emails dropped: %v", err)
+	}
+
+	if err := auditLog.Shutdown(ctx); err != nil {
+		log.Printf("⚠️  Audit entries dropped: %v", err)
+	}
+
+	hubHRMSClient.Close()
+
+	if err := dedupStore.Close(); err != nil {
+		log.Printf("⚠️  Deduplication store did not close cleanly: %v", err)
+	}
+
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("⚠️  Failed to flush traces: %v", err)
+	}
+
 	log.Println("✅ Server exited gracefully")
 }
 
@@ -0,0 +1,124 @@
+package main
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+
+	"hr-recruiting/internal/config"
+)
+
+// tlsCipherSuites are the TLS 1.2 suites offered: forward-secret AEAD
+// ciphers only. TLS 1.3 suites are not configurable and are always safe.
+var tlsCipherSuites = []uint16{
+	tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+	tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+	tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+	tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+}
+
+// newTLSConfig serves the certificate in cfg's files, picking up renewed
+// certificates without a restart. With AutoCert, certificates for cfg's
+// domains come from Let's Encrypt instead, and the returned manager must
+// also answer HTTP challenges on port 80 (see newRedirectServer).
+func newTLSConfig(cfg config.TLSConfig) (*tls.Config, *autocert.Manager, error) {
+	if cfg.AutoCert {
+		manager := &autocert.Manager{
+			Prompt:     autocert.AcceptTOS,
+			HostPolicy: autocert.HostWhitelist(cfg.Domains...),
+			Cache:      autocert.DirCache(cfg.AutoCertCacheDir),
+		}
+		tlsConfig := manager.TLSConfig()
+		tlsConfig.MinVersion = tls.VersionTLS12
+		tlsConfig.CipherSuites = tlsCipherSuites
+		return tlsConfig, manager, nil
+	}
+
+	certs := &certReloader{certFile: cfg.CertFile, keyFile: cfg.KeyFile}
+	if _, err := certs.GetCertificate(nil); err != nil {
+		return nil, nil, err
+	}
+
+	return &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		CipherSuites:   tlsCipherSuites,
+		GetCertificate: certs.GetCertificate,
+	}, nil, nil
+}
+
+// certReloader loads a certificate and key from disk, loading them again
+// whenever the certificate file changes, as it does when certbot or a
+// similar tool renews it
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu      sync.Mutex
+	cert    *tls.Certificate
+	modTime time.Time
+}
+
+// GetCertificate returns the current certificate. If a renewed one cannot be
+// loaded, the previous one is kept.
+func (c *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	info, err := os.Stat(c.certFile)
+	if err != nil {
+		if c.cert != nil {
+			return c.cert, nil
+		}
+		return nil, fmt.Errorf("failed to read TLS certificate: %w", err)
+	}
+	if c.cert != nil && !info.ModTime().After(c.modTime) {
+		return c.cert, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(c.certFile, c.keyFile)
+	if err != nil {
+		if c.cert != nil {
+			slog.Warn("failed to reload TLS certificate, keeping the current one", "error", err)
+			c.modTime = info.ModTime()
+			return c.cert, nil
+		}
+		return nil, fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+
+	c.cert, c.modTime = &cert, info.ModTime()
+	return c.cert, nil
+}
+
+// newRedirectServer sends plain HTTP requests to the same URL over HTTPS on
+// httpsPort. A non-nil manager answers its ACME HTTP-01 challenges first.
+func newRedirectServer(cfg config.TLSConfig, httpsPort string, manager *autocert.Manager) *http.Server {
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.Host)
+		if err != nil {
+			host = r.Host
+		}
+		if httpsPort != "443" {
+			host = net.JoinHostPort(host, httpsPort)
+		}
+		http.Redirect(w, r, "https://"+host+r.URL.RequestURI(), http.StatusMovedPermanently)
+	})
+	if manager != nil {
+		handler = manager.HTTPHandler(handler)
+	}
+
+	return &http.Server{
+		Addr:              ":" + cfg.RedirectPort,
+		Handler:           handler,
+		ReadHeaderTimeout: 5 * time.Second,
+		WriteTimeout:      5 * time.Second,
+	}
+}
@@ -0,0 +1,228 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"log"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"hr-recruiting/internal/config"
+)
+
+// writeSelfSignedCert writes a fresh self-signed certificate for localhost
+// and its key to certFile and keyFile, returning the certificate
+func writeSelfSignedCert(t *testing.T, certFile, keyFile string, serial int64) *x509.Certificate {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(serial),
+		Subject:      pkix.Name{CommonName: "localhost"},
+		DNSNames:     []string{"localhost"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+// getHTTPS fetches url trusting only cert, sending localhost as the server
+// name, and returns the certificate the server presented
+func getHTTPS(t *testing.T, url string, cert *x509.Certificate) (*http.Response, *x509.Certificate) {
+	t.Helper()
+	roots := x509.NewCertPool()
+	roots.AddCert(cert)
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{RootCAs: roots, ServerName: "localhost"},
+		DisableKeepAlives: true,
+	}}
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("GET %s: %v", url, err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	return resp, resp.TLS.PeerCertificates[0]
+}
+
+func TestNewTLSConfig_ServesHTTPS(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	cert := writeSelfSignedCert(t, certFile, keyFile, 1)
+
+	tlsConfig, manager, err := newTLSConfig(config.TLSConfig{Enabled: true, CertFile: certFile, KeyFile: keyFile})
+	if err != nil {
+		t.Fatalf("newTLSConfig() error = %v", err)
+	}
+	if manager != nil {
+		t.Fatal("file certificates returned an autocert manager")
+	}
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	server.TLS = tlsConfig
+	// The handshake the TLS 1.1 client fails below is expected
+	server.Config.ErrorLog = log.New(io.Discard, "", 0)
+	server.StartTLS()
+	defer server.Close()
+
+	resp, served := getHTTPS(t, server.URL, cert)
+	if resp.StatusCode != http.StatusOK || !served.Equal(cert) {
+		t.Fatalf("status = %d, served certificate serial %v, want 200 and serial %v", resp.StatusCode, served.SerialNumber, cert.SerialNumber)
+	}
+	if resp.TLS.Version < tls.VersionTLS12 {
+		t.Fatalf("negotiated TLS version %x, want 1.2 or later", resp.TLS.Version)
+	}
+
+	// Clients limited to TLS 1.1 are turned away
+	old := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{
+		InsecureSkipVerify: true,
+		ServerName:         "localhost",
+		MaxVersion:         tls.VersionTLS11,
+	}}}
+	if resp, err := old.Get(server.URL); err == nil {
+		resp.Body.Close()
+		t.Fatal("TLS 1.1 client connected")
+	}
+
+	// A renewed certificate is served without a restart
+	renewed := writeSelfSignedCert(t, certFile, keyFile, 2)
+	future := time.Now().Add(time.Minute)
+	if err := os.Chtimes(certFile, future, future); err != nil {
+		t.Fatal(err)
+	}
+	if _, served := getHTTPS(t, server.URL, renewed); !served.Equal(renewed) {
+		t.Fatalf("served certificate serial %v after renewal, want %v", served.SerialNumber, renewed.SerialNumber)
+	}
+}
+
+func TestNewTLSConfig_MissingCertificate(t *testing.T) {
+	dir := t.TempDir()
+	if _, _, err := newTLSConfig(config.TLSConfig{
+		Enabled:  true,
+		CertFile: filepath.Join(dir, "cert.pem"),
+		KeyFile:  filepath.Join(dir, "key.pem"),
+	}); err == nil {
+		t.Fatal("newTLSConfig() succeeded without certificate files")
+	}
+}
+
+func TestNewTLSConfig_AutoCert(t *testing.T) {
+	tlsConfig, manager, err := newTLSConfig(config.TLSConfig{
+		Enabled:          true,
+		AutoCert:         true,
+		Domains:          []string{"careers.example.com"},
+		AutoCertCacheDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatalf("newTLSConfig() error = %v", err)
+	}
+	if manager == nil {
+		t.Fatal("autocert returned no manager")
+	}
+	if tlsConfig.MinVersion != tls.VersionTLS12 || !slices.Equal(tlsConfig.CipherSuites, tlsCipherSuites) {
+		t.Fatalf("TLS config = min %x, suites %v", tlsConfig.MinVersion, tlsConfig.CipherSuites)
+	}
+	if !slices.Contains(tlsConfig.NextProtos, "acme-tls/1") {
+		t.Fatalf("NextProtos = %v, want the TLS-ALPN-01 protocol", tlsConfig.NextProtos)
+	}
+
+	// Hosts outside Domains are refused before Let's Encrypt is contacted
+	_, err = tlsConfig.GetCertificate(&tls.ClientHelloInfo{ServerName: "attacker.example.net"})
+	if err == nil || !strings.Contains(err.Error(), "not configured") {
+		t.Fatalf("GetCertificate(other host) error = %v, want the host refused", err)
+	}
+}
+
+func TestNewRedirectServer(t *testing.T) {
+	tests := []struct {
+		name      string
+		httpsPort string
+		host      string
+		want      string
+	}{
+		{name: "default port", httpsPort: "443", host: "careers.example.com", want: "https://careers.example.com/api/v1/jobs?page=2"},
+		{name: "custom port", httpsPort: "8443", host: "careers.example.com:8080", want: "https://careers.example.com:8443/api/v1/jobs?page=2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newRedirectServer(config.TLSConfig{RedirectPort: "80"}, tt.httpsPort, nil)
+			if server.Addr != ":80" {
+				t.Fatalf("Addr = %q, want :80", server.Addr)
+			}
+
+			req := httptest.NewRequest(http.MethodGet, "http://"+tt.host+"/api/v1/jobs?page=2", nil)
+			rec := httptest.NewRecorder()
+			server.Handler.ServeHTTP(rec, req)
+			if rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != tt.want {
+				t.Fatalf("redirect = %d %q, want 301 %q", rec.Code, rec.Header().Get("Location"), tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRedirectServer_AutoCertChallenges(t *testing.T) {
+	_, manager, err := newTLSConfig(config.TLSConfig{
+		Enabled:          true,
+		AutoCert:         true,
+		Domains:          []string{"careers.example.com"},
+		AutoCertCacheDir: t.TempDir(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	handler := newRedirectServer(config.TLSConfig{RedirectPort: "80"}, "443", manager).Handler
+
+	// Challenge paths are answered by the manager, not redirected
+	req := httptest.NewRequest(http.MethodGet, "http://careers.example.com/.well-known/acme-challenge/unknown-token", nil)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code == http.StatusMovedPermanently {
+		t.Fatal("ACME challenge was redirected to HTTPS")
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "http://careers.example.com/jobs", nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusMovedPermanently || rec.Header().Get("Location") != "https://careers.example.com/jobs" {
+		t.Fatalf("redirect = %d %q", rec.Code, rec.Header().Get("Location"))
+	}
+}
@@ -1,42 +1,138 @@
 package config
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
 	"os"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 // Config holds all configuration for the application
 type Config struct {
-	Server  ServerConfig
-	HubHRMS HubHRMSConfig
-	AWS     AWSConfig
-	Email   EmailConfig
-	CORS    CORSConfig
+	Server    ServerConfig
+	API       APIConfig
+	TLS       TLSConfig
+	HubHRMS   HubHRMSConfig
+	AWS       AWSConfig
+	Upload    UploadConfig
+	Email     EmailConfig
+	CORS      CORSConfig
+	Exchange  ExchangeConfig
+	JWT       JWTConfig
+	Auth      AuthConfig
+	RateLimit RateLimitConfig
+	Cache     CacheConfig
+	Log       LogConfig
+	Telemetry TelemetryConfig
+	Metrics   MetricsConfig
+	Company   CompanyConfig
+	Scheduler SchedulerConfig
+	Workflow  WorkflowConfig
+	Scoring   ScoringConfig
+	Salary    SalaryConfig
+	Audit     AuditConfig
+	Skills    SkillsConfig
+	PDF       PDFConfig
+	Privacy   PrivacyConfig
+	GraphQL   GraphQLConfig
+	Debug     DebugConfig
+	Slack     SlackConfig
+	Teams     TeamsConfig
+	Features  *FeatureFlags
+
+	GoogleCalendar GoogleCalendarConfig
+	GeoRestriction GeoRestrictionConfig
 }
 
 // ServerConfig holds server configuration
 type ServerConfig struct {
-	Port        string
-	Environment string
+	Port         string
+	Environment  string
+	BaseURL      string
+	CursorSecret string
+	CursorTTL    time.Duration
+}
+
+// APIConfig holds API versioning configuration
+type APIConfig struct {
+	// V1SunsetDate is the YYYY-MM-DD date /api/v1 is to be retired,
+	// announced on every v1 response. Empty when no date is set.
+	V1SunsetDate string
+}
+
+// TLSConfig holds HTTPS configuration
+type TLSConfig struct {
+	Enabled  bool
+	CertFile string
+	KeyFile  string
+	// AutoCert obtains certificates from Let's Encrypt for Domains instead of
+	// reading CertFile and KeyFile
+	AutoCert bool
+	Domains  []string
+	// AutoCertCacheDir keeps issued certificates across restarts
+	AutoCertCacheDir string
+	// RedirectPort is where plain HTTP requests are redirected to HTTPS
+	RedirectPort string
 }
 
 // HubHRMSConfig holds Hub-HRMS integration configuration
 type HubHRMSConfig struct {
-	URL    string
-	APIKey string
+	URL                   string
+	APIKey                string
+	MaxConnsPerHost       int
+	MaxIdleConns          int
+	MaxIdleConnsPerHost   int
+	IdleConnTimeout       time.Duration
+	RequestTimeout        time.Duration
+	TLSHandshakeTimeout   time.Duration
+	IdleConnCheckInterval time.Duration
+	RetryMaxAttempts      int
+	RetryBaseDelay        time.Duration
+	BatchEnabled          bool
+	WebhookSecret         string
+	DebugLog              bool
+	// MaxConcurrentQueries limits how many queries one QueryAll call runs
+	// at once
+	MaxConcurrentQueries int
+	// ValidateQueries checks every query against the Hub-HRMS schema in the
+	// background after startup, logging the ones that do not match
+	ValidateQueries bool
+	// PersistedQueriesFile maps persisted query hashes to the queries the
+	// GraphQL proxy sends in their place
+	PersistedQueriesFile string
+	// PersistedQueriesOnly rejects proxied requests with inline queries
+	PersistedQueriesOnly bool
 }
 
 // AWSConfig holds AWS configuration
 type AWSConfig struct {
-	Region   string
-	S3Bucket string
+	Region         string
+	S3Bucket       string
+	UploadTopicARN string
+}
+
+// UploadConfig holds candidate upload limits
+type UploadConfig struct {
+	// MaxFilesPerCandidate caps the resumes one candidate may upload across
+	// all their submissions
+	MaxFilesPerCandidate int
 }
 
 // EmailConfig holds email service configuration
 type EmailConfig struct {
+	Required    bool
 	SendGridKey string
 	FromEmail   string
 	FromName    string
+	WorkerCount int
+	QueueSize   int
 }
 
 // CORSConfig holds CORS configuration
@@ -44,33 +140,473 @@ type CORSConfig struct {
 	AllowedOrigins []string
 }
 
-// Load loads configuration from environment variables
-func Load() *Config {
-	return &Config{
+// ExchangeConfig holds currency exchange rate configuration
+type ExchangeConfig struct {
+	APIURL       string
+	APIKey       string
+	BaseCurrency string
+}
+
+// JWTConfig holds token validation configuration
+type JWTConfig struct {
+	Secret        string
+	PublicKeyPath string
+	Issuer        string
+}
+
+// AuthConfig holds machine-to-machine authentication configuration
+type AuthConfig struct {
+	ClientsFile string
+	TokenTTL    time.Duration
+	Clients     map[string]OAuthClient
+	APIKeysFile string
+}
+
+// OAuthClient is an integration registered for the client credentials grant.
+// Only a SHA-256 hash of the secret is kept.
+type OAuthClient struct {
+	Name       string `json:"name"`
+	SecretHash string `json:"secretHash"`
+}
+
+// RateLimitConfig holds per-route rate limits
+type RateLimitConfig struct {
+	Applications  RouteLimit
+	Uploads       RouteLimit
+	Authenticated RouteLimit
+	Privacy       RouteLimit
+}
+
+// RouteLimit is a token bucket rate and burst size
+type RouteLimit struct {
+	RPS   float64
+	Burst int
+}
+
+// CacheConfig holds response cache configuration
+type CacheConfig struct {
+	JobTTL      time.Duration
+	Size        int
+	RedisURL    string
+	DedupWindow time.Duration
+}
+
+// LogConfig holds logging configuration
+type LogConfig struct {
+	Level  string
+	Format string
+}
+
+// LoadLogConfig reads the logging configuration on its own, so the logger can
+// be set up before the rest of the config is loaded
+func LoadLogConfig() LogConfig {
+	return LogConfig{
+		Level:  getEnv("LOG_LEVEL", "info"),
+		Format: getEnv("LOG_FORMAT", "json"),
+	}
+}
+
+// TelemetryConfig holds tracing configuration
+type TelemetryConfig struct {
+	OTLPEndpoint string
+	ServiceName  string
+}
+
+// MetricsConfig holds Prometheus metrics configuration
+type MetricsConfig struct {
+	Enabled bool
+	Path    string
+}
+
+// CompanyConfig describes the hiring organization
+type CompanyConfig struct {
+	Name    string
+	LogoURL string
+}
+
+// SchedulerConfig holds background job configuration
+type SchedulerConfig struct {
+	JobExpirationInterval    time.Duration
+	InterviewReminderEnabled bool
+}
+
+// WorkflowConfig holds application workflow configuration
+type WorkflowConfig struct {
+	StrictTransitions bool
+}
+
+// ScoringConfig holds AI scoring configuration
+type ScoringConfig struct {
+	Concurrency int
+}
+
+// SalaryConfig holds salary range validation configuration
+type SalaryConfig struct {
+	// BenchmarkFile is a YAML list of market salary ranges; when empty,
+	// salary ranges are not checked
+	BenchmarkFile string
+}
+
+// AuditConfig holds audit log configuration
+type AuditConfig struct {
+	// LogPath is the audit log file; when empty, entries go to stdout
+	LogPath string
+}
+
+// SkillsConfig holds skill normalization configuration
+type SkillsConfig struct {
+	// TaxonomyFile is a YAML list of canonical skills and their synonyms;
+	// when empty, the built-in taxonomy is used
+	TaxonomyFile string
+}
+
+// PDFConfig holds PDF generation configuration
+type PDFConfig struct {
+	// ConverterPath is the wkhtmltopdf executable; when empty, it is looked
+	// up on PATH and in the WKHTMLTOPDF_PATH directory
+	ConverterPath string
+}
+
+// GraphQLConfig holds limits on queries sent through the GraphQL proxy
+type GraphQLConfig struct {
+	MaxDepth         int
+	MaxComplexity    int
+	AllowlistEnabled bool
+	AllowlistPath    string
+}
+
+// DebugConfig holds profiling configuration
+type DebugConfig struct {
+	PProfEnabled bool
+	PProfPort    string
+	PProfSecret  string
+}
+
+// SlackConfig holds Slack notification configuration
+type SlackConfig struct {
+	// WebhookURL is a Slack incoming webhook; when empty, nothing is posted
+	WebhookURL string
+	Channel    string
+	// NotifyOnStatuses are the application statuses whose changes are posted
+	NotifyOnStatuses []string
+}
+
+// TeamsConfig holds Microsoft Teams notification configuration
+type TeamsConfig struct {
+	// WebhookURL is a Teams incoming webhook; when empty, nothing is posted
+	WebhookURL string
+	// NotifyOnStatuses are the application statuses whose changes are posted
+	NotifyOnStatuses []string
+}
+
+// GoogleCalendarConfig holds Google Calendar configuration
+type GoogleCalendarConfig struct {
+	// ServiceAccountFile is a service account JSON key; when empty, no
+	// calendar events are created
+	ServiceAccountFile string
+	CalendarID         string
+	// DelegatedUser is the Workspace user the service account acts for,
+	// needed for it to invite attendees
+	DelegatedUser string
+}
+
+// GeoRestrictionConfig holds configuration for limiting candidate data to
+// requests from some countries
+type GeoRestrictionConfig struct {
+	Enabled bool
+	// MaxMindDBPath is a MaxMind country database, e.g. GeoLite2-Country.mmdb
+	MaxMindDBPath string
+	// AllowedCountries are ISO 3166-1 alpha-2 codes
+	AllowedCountries []string
+}
+
+// PrivacyConfig holds configuration for candidate data requests
+type PrivacyConfig struct {
+	TokenSecret string
+	TokenTTL    time.Duration
+	// ShareLinkSecret signs application share links
+	ShareLinkSecret string
+}
+
+// Load loads configuration from environment variables, overlaid with values
+// from AWS Secrets Manager when AWS_SECRETS_MANAGER_SECRET_NAME is set. The
+// config is returned even when the secrets cannot be loaded, so that Validate
+// can report everything else that is wrong with it too. Problems that do not
+// stop the server are logged to logger.
+func Load(logger *slog.Logger) (*Config, error) {
+	cfg := &Config{
 		Server: ServerConfig{
-			Port:        getEnv("PORT", "8080"),
-			Environment: getEnv("ENVIRONMENT", "development"),
+			Port:         getEnv("PORT", "8080"),
+			Environment:  getEnv("ENVIRONMENT", "development"),
+			BaseURL:      getEnv("BASE_URL", "http://localhost:5173"),
+			CursorSecret: getEnv("CURSOR_SECRET", ""),
+			CursorTTL:    getEnvDuration("CURSOR_TTL", 24*time.Hour),
+		},
+		API: APIConfig{
+			V1SunsetDate: getEnv("API_V1_SUNSET_DATE", ""),
+		},
+		TLS: TLSConfig{
+			Enabled:      getEnvBool("TLS_ENABLED", false),
+			CertFile:     getEnv("TLS_CERT_FILE", ""),
+			KeyFile:      getEnv("TLS_KEY_FILE", ""),
+			RedirectPort: getEnv("TLS_REDIRECT_PORT", "80"),
+
+			AutoCert:         getEnvBool("TLS_AUTOCERT", false),
+			Domains:          getEnvList("TLS_DOMAINS", ""),
+			AutoCertCacheDir: getEnv("TLS_AUTOCERT_CACHE_DIR", "certs"),
 		},
 		HubHRMS: HubHRMSConfig{
-			URL:    getEnv("HUBHRMS_GRAPHQL_URL", ""),
-			APIKey: getEnv("HUBHRMS_API_KEY", ""),
+			URL:                   getEnv("HUBHRMS_GRAPHQL_URL", ""),
+			APIKey:                getEnv("HUBHRMS_API_KEY", ""),
+			MaxConnsPerHost:       getEnvInt("HUBHRMS_MAX_CONNS_PER_HOST", 50),
+			MaxIdleConns:          getEnvInt("HUBHRMS_MAX_IDLE_CONNS", 100),
+			MaxIdleConnsPerHost:   getEnvInt("HUBHRMS_MAX_IDLE_CONNS_PER_HOST", 10),
+			IdleConnTimeout:       getEnvDuration("HUBHRMS_IDLE_CONN_TIMEOUT", 90*time.Second),
+			RequestTimeout:        getEnvDuration("HUBHRMS_REQUEST_TIMEOUT", 30*time.Second),
+			TLSHandshakeTimeout:   getEnvDuration("HUBHRMS_TLS_HANDSHAKE_TIMEOUT", 10*time.Second),
+			IdleConnCheckInterval: getEnvDuration("HUBHRMS_IDLE_CONN_CHECK_INTERVAL", 30*time.Second),
+			RetryMaxAttempts:      getEnvInt("HUBHRMS_RETRY_MAX_ATTEMPTS", 3),
+			RetryBaseDelay:        getEnvDuration("HUBHRMS_RETRY_BASE_DELAY", 100*time.Millisecond),
+			BatchEnabled:          getEnvBool("HUBHRMS_BATCH_ENABLED", true),
+			MaxConcurrentQueries:  getEnvInt("HUBHRMS_MAX_CONCURRENT_QUERIES", 5),
+			WebhookSecret:         getEnv("HUBHRMS_WEBHOOK_SECRET", ""),
+			DebugLog:              getEnvBool("HUBHRMS_DEBUG_LOG", false),
+			ValidateQueries:       getEnvBool("HUBHRMS_VALIDATE_QUERIES", false),
+			PersistedQueriesFile:  getEnv("HUBHRMS_PERSISTED_QUERIES_FILE", ""),
+			PersistedQueriesOnly:  getEnvBool("HUBHRMS_PERSISTED_QUERIES_ONLY", false),
 		},
 		AWS: AWSConfig{
-			Region:   getEnv("AWS_REGION", "us-east-1"),
-			S3Bucket: getEnv("AWS_S3_BUCKET", "hr-recruiting-resumes"),
+			Region:         getEnv("AWS_REGION", "us-east-1"),
+			S3Bucket:       getEnv("AWS_S3_BUCKET", "hr-recruiting-resumes"),
+			UploadTopicARN: getEnv("AWS_UPLOAD_TOPIC_ARN", ""),
+		},
+		Upload: UploadConfig{
+			MaxFilesPerCandidate: getEnvInt("UPLOAD_MAX_FILES_PER_CANDIDATE", 10),
 		},
 		Email: EmailConfig{
+			Required:    getEnvBool("EMAIL_REQUIRED", false),
 			SendGridKey: getEnv("SENDGRID_API_KEY", ""),
 			FromEmail:   getEnv("EMAIL_FROM", "noreply@company.com"),
 			FromName:    getEnv("EMAIL_FROM_NAME", "HR Recruiting"),
+			WorkerCount: getEnvInt("EMAIL_WORKER_COUNT", 4),
+			QueueSize:   getEnvInt("EMAIL_QUEUE_SIZE", 1000),
 		},
 		CORS: CORSConfig{
-			AllowedOrigins: strings.Split(
-				getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:5173,http://localhost:3000"),
-				",",
-			),
+			AllowedOrigins: loadAllowedOrigins(logger),
+		},
+		Exchange: ExchangeConfig{
+			APIURL:       getEnv("EXCHANGE_RATE_API_URL", "https://openexchangerates.org/api/latest.json"),
+			APIKey:       getEnv("EXCHANGE_RATE_API_KEY", ""),
+			BaseCurrency: getEnv("EXCHANGE_BASE_CURRENCY", "USD"),
+		},
+		JWT: JWTConfig{
+			Secret:        getEnv("JWT_SECRET", ""),
+			PublicKeyPath: getEnv("JWT_PUBLIC_KEY_PATH", ""),
+			Issuer:        getEnv("JWT_ISSUER", ""),
+		},
+		Auth: AuthConfig{
+			ClientsFile: getEnv("AUTH_CLIENTS_FILE", ""),
+			TokenTTL:    getEnvDuration("AUTH_TOKEN_TTL", 15*time.Minute),
+			APIKeysFile: getEnv("AUTH_API_KEYS_FILE", ""),
+		},
+		RateLimit: RateLimitConfig{
+			Applications: RouteLimit{
+				RPS:   getEnvFloat("RATE_LIMIT_APPLICATIONS_RPS", 0.2),
+				Burst: getEnvInt("RATE_LIMIT_APPLICATIONS_BURST", 5),
+			},
+			Uploads: RouteLimit{
+				RPS:   getEnvFloat("RATE_LIMIT_UPLOADS_RPS", 0.5),
+				Burst: getEnvInt("RATE_LIMIT_UPLOADS_BURST", 10),
+			},
+			Authenticated: RouteLimit{
+				RPS:   getEnvFloat("RATE_LIMIT_AUTHENTICATED_RPS", 10),
+				Burst: getEnvInt("RATE_LIMIT_AUTHENTICATED_BURST", 50),
+			},
+			Privacy: RouteLimit{
+				RPS:   getEnvFloat("RATE_LIMIT_PRIVACY_RPS", 0.05),
+				Burst: getEnvInt("RATE_LIMIT_PRIVACY_BURST", 3),
+			},
+		},
+		Cache: CacheConfig{
+			JobTTL:      getEnvDuration("CACHE_JOB_TTL", 60*time.Second),
+			Size:        getEnvInt("CACHE_SIZE", 1000),
+			RedisURL:    getEnv("REDIS_URL", ""),
+			DedupWindow: getEnvDuration("APPLICATION_DEDUP_WINDOW", 24*time.Hour),
+		},
+		Log: LoadLogConfig(),
+		Telemetry: TelemetryConfig{
+			OTLPEndpoint: getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", ""),
+			ServiceName:  getEnv("OTEL_SERVICE_NAME", "hr-recruiting-api"),
+		},
+		Company: CompanyConfig{
+			Name:    getEnv("COMPANY_NAME", "HR Recruiting"),
+			LogoURL: getEnv("COMPANY_LOGO_URL", ""),
+		},
+		Scheduler: SchedulerConfig{
+			JobExpirationInterval:    getEnvDuration("JOB_EXPIRATION_INTERVAL", time.Hour),
+			InterviewReminderEnabled: getEnvBool("INTERVIEW_REMINDER_ENABLED", true),
 		},
+		Workflow: WorkflowConfig{
+			StrictTransitions: getEnvBool("WORKFLOW_STRICT_TRANSITIONS", true),
+		},
+		Scoring: ScoringConfig{
+			Concurrency: getEnvInt("SCORING_CONCURRENCY", 5),
+		},
+		Salary: SalaryConfig{
+			BenchmarkFile: getEnv("SALARY_BENCHMARK_FILE", ""),
+		},
+		Audit: AuditConfig{
+			LogPath: getEnv("AUDIT_LOG_PATH", ""),
+		},
+		Skills: SkillsConfig{
+			TaxonomyFile: getEnv("SKILLS_TAXONOMY_FILE", ""),
+		},
+		PDF: PDFConfig{
+			ConverterPath: getEnv("WKHTMLTOPDF_BIN", ""),
+		},
+		GraphQL: GraphQLConfig{
+			MaxDepth:         getEnvInt("GRAPHQL_MAX_DEPTH", 10),
+			MaxComplexity:    getEnvInt("GRAPHQL_MAX_COMPLEXITY", 500),
+			AllowlistEnabled: getEnvBool("GRAPHQL_ALLOWLIST_ENABLED", false),
+			AllowlistPath:    getEnv("GRAPHQL_ALLOWLIST_PATH", "graphql-allowlist.json"),
+		},
+		Privacy: PrivacyConfig{
+			TokenSecret: getEnv("PRIVACY_TOKEN_SECRET", ""),
+			TokenTTL:    getEnvDuration("PRIVACY_TOKEN_TTL", time.Hour),
+
+			ShareLinkSecret: getEnv("SHARE_LINK_SECRET", ""),
+		},
+		Metrics: MetricsConfig{
+			Enabled: getEnvBool("METRICS_ENABLED", true),
+			Path:    getEnv("METRICS_PATH", "/metrics"),
+		},
+		Debug: DebugConfig{
+			PProfEnabled: getEnvBool("PPROF_ENABLED", false),
+			PProfPort:    getEnv("PPROF_PORT", "6060"),
+			PProfSecret:  getEnv("PPROF_SECRET", ""),
+		},
+		Slack: SlackConfig{
+			WebhookURL:       getEnv("SLACK_WEBHOOK_URL", ""),
+			Channel:          getEnv("SLACK_CHANNEL", ""),
+			NotifyOnStatuses: getEnvList("SLACK_NOTIFY_ON_STATUSES", "OFFER"),
+		},
+		Teams: TeamsConfig{
+			WebhookURL:       getEnv("TEAMS_WEBHOOK_URL", ""),
+			NotifyOnStatuses: getEnvList("TEAMS_NOTIFY_ON_STATUSES", "OFFER"),
+		},
+		Features: loadFeatureFlags(),
+		GoogleCalendar: GoogleCalendarConfig{
+			ServiceAccountFile: getEnv("GOOGLE_CALENDAR_SERVICE_ACCOUNT_FILE", ""),
+			CalendarID:         getEnv("GOOGLE_CALENDAR_ID", "primary"),
+			DelegatedUser:      getEnv("GOOGLE_CALENDAR_DELEGATED_USER", ""),
+		},
+		GeoRestriction: GeoRestrictionConfig{
+			Enabled:          getEnvBool("GEO_RESTRICTION_ENABLED", false),
+			MaxMindDBPath:    getEnv("GEO_RESTRICTION_MAXMIND_DB_PATH", ""),
+			AllowedCountries: getEnvList("GEO_RESTRICTION_ALLOWED_COUNTRIES", ""),
+		},
+	}
+
+	if secretName := getEnv("AWS_SECRETS_MANAGER_SECRET_NAME", ""); secretName != "" {
+		if err := applySecrets(context.Background(), cfg, secretName, logger); err != nil {
+			return cfg, err
+		}
+	}
+	return cfg, nil
+}
+
+// loadAllowedOrigins merges CORS_ALLOWED_ORIGINS with any origins saved to
+// CORSOverrideFile at runtime
+func loadAllowedOrigins(logger *slog.Logger) []string {
+	origins := strings.Split(
+		getEnv("CORS_ALLOWED_ORIGINS", "http://localhost:5173,http://localhost:3000"),
+		",",
+	)
+
+	override, err := loadCORSOverride(CORSOverrideFile)
+	if err != nil {
+		logger.Warn("ignoring CORS origin overrides", "error", err)
+		return origins
 	}
+	return normalizeOrigins(append(origins, override...))
+}
+
+// minJWTSecretLength is the shortest HS256 secret accepted; RFC 7518 requires
+// a key at least as long as the hash output
+const minJWTSecretLength = 32
+
+// awsRegionPattern matches standard, GovCloud and ISO AWS region names
+var awsRegionPattern = regexp.MustCompile(`^[a-z]{2}(-gov|-iso[a-z]?)?-(north|south|east|west|central|northeast|northwest|southeast|southwest)-[0-9]$`)
+
+// Validate checks the settings the server cannot run without and returns
+// every problem found, so they can all be fixed in one go
+func Validate(cfg *Config) []error {
+	var errs []error
+
+	if cfg.HubHRMS.URL == "" {
+		errs = append(errs, errors.New("HUBHRMS_GRAPHQL_URL is required"))
+	} else if parsed, err := url.Parse(cfg.HubHRMS.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		errs = append(errs, fmt.Errorf("HUBHRMS_GRAPHQL_URL %q is not a valid http(s) URL", cfg.HubHRMS.URL))
+	}
+
+	if cfg.AWS.S3Bucket == "" {
+		errs = append(errs, errors.New("AWS_S3_BUCKET is required"))
+	}
+	if !awsRegionPattern.MatchString(cfg.AWS.Region) {
+		errs = append(errs, fmt.Errorf("AWS_REGION %q is not a known AWS region", cfg.AWS.Region))
+	}
+
+	if cfg.Email.Required && cfg.Email.SendGridKey == "" {
+		errs = append(errs, errors.New("SENDGRID_API_KEY is required when EMAIL_REQUIRED is set"))
+	}
+
+	if len(cfg.JWT.Secret) < minJWTSecretLength {
+		errs = append(errs, fmt.Errorf("JWT_SECRET must be at least %d bytes", minJWTSecretLength))
+	}
+
+	if cfg.API.V1SunsetDate != "" {
+		if _, err := time.Parse("2006-01-02", cfg.API.V1SunsetDate); err != nil {
+			errs = append(errs, fmt.Errorf("API_V1_SUNSET_DATE %q is not a YYYY-MM-DD date", cfg.API.V1SunsetDate))
+		}
+	}
+
+	switch {
+	case !cfg.TLS.Enabled:
+	case cfg.TLS.AutoCert && len(cfg.TLS.Domains) == 0:
+		errs = append(errs, errors.New("TLS_DOMAINS is required when TLS_AUTOCERT is set"))
+	case !cfg.TLS.AutoCert && (cfg.TLS.CertFile == "" || cfg.TLS.KeyFile == ""):
+		errs = append(errs, errors.New("TLS_CERT_FILE and TLS_KEY_FILE are required when TLS_ENABLED is set"))
+	}
+
+	if cfg.GeoRestriction.Enabled {
+		if cfg.GeoRestriction.MaxMindDBPath == "" {
+			errs = append(errs, errors.New("GEO_RESTRICTION_MAXMIND_DB_PATH is required when GEO_RESTRICTION_ENABLED is set"))
+		}
+		if len(cfg.GeoRestriction.AllowedCountries) == 0 {
+			errs = append(errs, errors.New("GEO_RESTRICTION_ALLOWED_COUNTRIES is required when GEO_RESTRICTION_ENABLED is set"))
+		}
+	}
+
+	return errs
+}
+
+// LoadOAuthClients reads a JSON object of OAuth clients keyed by client ID
+func LoadOAuthClients(path string) (map[string]OAuthClient, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read OAuth clients: %w", err)
+	}
+
+	var clients map[string]OAuthClient
+	if err := json.Unmarshal(data, &clients); err != nil {
+		return nil, fmt.Errorf("invalid OAuth clients file %s: %w", path, err)
+	}
+	for id, client := range clients {
+		if client.SecretHash == "" {
+			return nil, fmt.Errorf("OAuth client %s has no secretHash", id)
+		}
+	}
+	return clients, nil
 }
 
 func getEnv(key, defaultValue string) string {
@@ -78,4 +614,51 @@ func getEnv(key, defaultValue string) string {
 		return value
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// getEnvList reads a comma-separated list, dropping empty items
+func getEnvList(key, defaultValue string) []string {
+	var list []string
+	for _, item := range strings.Split(getEnv(key, defaultValue), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseBool(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := time.ParseDuration(value); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
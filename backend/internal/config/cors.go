@@ -0,0 +1,156 @@
+package config
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/cors"
+)
+
+// CORSOverrideFile holds origins set at runtime, relative to the working
+// directory. It is merged with CORS_ALLOWED_ORIGINS on startup.
+const CORSOverrideFile = "cors-override.json"
+
+// corsOverride is the on-disk form of CORSOverrideFile
+type corsOverride struct {
+	AllowedOrigins []string `json:"allowedOrigins"`
+}
+
+// CORSManager holds the allowed origins and lets them change while the
+// server is running
+type CORSManager struct {
+	mu           sync.RWMutex
+	origins      []string
+	overridePath string
+}
+
+// NewCORSManager creates a manager for origins. Updates are persisted to
+// overridePath, unless it is empty.
+func NewCORSManager(origins []string, overridePath string) *CORSManager {
+	return &CORSManager{
+		origins:      normalizeOrigins(origins),
+		overridePath: overridePath,
+	}
+}
+
+// Handler returns the CORS middleware, checking each request's origin
+// against the current list
+func (m *CORSManager) Handler() func(http.Handler) http.Handler {
+	return cors.Handler(cors.Options{
+		AllowOriginFunc:  m.allowed,
+		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS", "PATCH"},
+		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token", "Idempotency-Key", "If-None-Match", "X-API-Key"},
+		ExposedHeaders:   []string{"Link", "X-Total-Count", "X-Next-Cursor", "Idempotency-Replayed", "ETag"},
+		AllowCredentials: true,
+		MaxAge:           300,
+	})
+}
+
+// Origins returns a copy of the allowed origins
+func (m *CORSManager) Origins() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]string(nil), m.origins...)
+}
+
+// SetOrigins replaces the allowed origins and persists them. Each origin must
+// be "*" or a scheme and host such as https://careers.example.com, optionally
+// with a "*." wildcard subdomain.
+func (m *CORSManager) SetOrigins(origins []string) error {
+	for _, origin := range origins {
+		if err := validateOrigin(origin); err != nil {
+			return err
+		}
+	}
+	origins = normalizeOrigins(origins)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.overridePath != "" {
+		data, err := json.MarshalIndent(corsOverride{AllowedOrigins: origins}, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(m.overridePath, data, 0o644); err != nil {
+			return fmt.Errorf("failed to save CORS origins: %w", err)
+		}
+	}
+	m.origins = origins
+	return nil
+}
+
+func (m *CORSManager) allowed(r *http.Request, origin string) bool {
+	origin = strings.ToLower(origin)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, allowed := range m.origins {
+		if allowed == "*" || allowed == origin {
+			return true
+		}
+		// https://*.example.com matches any subdomain of example.com
+		if prefix, suffix, ok := strings.Cut(allowed, "*"); ok &&
+			len(origin) > len(prefix)+len(suffix) &&
+			strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrInvalidOrigin is returned for origins that are not "*" or scheme://host
+var ErrInvalidOrigin = errors.New("invalid CORS origin")
+
+func validateOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	parsed, err := url.Parse(strings.Replace(origin, "*.", "wildcard.", 1))
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" ||
+		(parsed.Path != "" && parsed.Path != "/") || parsed.RawQuery != "" || parsed.Fragment != "" {
+		return fmt.Errorf("%w: %q", ErrInvalidOrigin, origin)
+	}
+	return nil
+}
+
+// normalizeOrigins trims, lowercases and de-duplicates origins, dropping
+// empty entries and trailing slashes
+func normalizeOrigins(origins []string) []string {
+	seen := make(map[string]bool, len(origins))
+	normalized := make([]string, 0, len(origins))
+	for _, origin := range origins {
+		origin = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(origin)), "/")
+		if origin == "" || seen[origin] {
+			continue
+		}
+		seen[origin] = true
+		normalized = append(normalized, origin)
+	}
+	return normalized
+}
+
+// loadCORSOverride reads origins saved by a previous SetOrigins. A missing
+// file yields none.
+func loadCORSOverride(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var override corsOverride
+	if err := json.Unmarshal(data, &override); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return override.AllowedOrigins, nil
+}
@@ -0,0 +1,118 @@
+package config
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// preflight sends a CORS preflight from origin through the manager and
+// returns the Access-Control-Allow-Origin it was given
+func preflight(m *CORSManager, origin string) string {
+	handler := m.Handler()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	req := httptest.NewRequest(http.MethodOptions, "/api/v1/jobs", nil)
+	req.Header.Set("Origin", origin)
+	req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Header().Get("Access-Control-Allow-Origin")
+}
+
+func TestCORSManager_Allowed(t *testing.T) {
+	m := NewCORSManager([]string{" https://careers.example.com/ ", "https://*.example.org", "HTTPS://Careers.Example.com"}, "")
+
+	if got := m.Origins(); !slices.Equal(got, []string{"https://careers.example.com", "https://*.example.org"}) {
+		t.Fatalf("Origins() = %v, want trimmed, lowercased and de-duplicated origins", got)
+	}
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{origin: "https://careers.example.com", want: true},
+		{origin: "https://Careers.Example.com", want: true},
+		{origin: "https://jobs.example.org", want: true},
+		{origin: "https://a.b.example.org", want: true},
+		{origin: "https://.example.org"},
+		{origin: "https://example.org"},
+		{origin: "http://careers.example.com"},
+		{origin: "https://evil.com"},
+		{origin: "https://careers.example.com.evil.com"},
+	}
+	for _, tt := range tests {
+		if got := preflight(m, tt.origin) != ""; got != tt.want {
+			t.Errorf("origin %s allowed = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+}
+
+func TestCORSManager_SetOrigins(t *testing.T) {
+	path := filepath.Join(t.TempDir(), CORSOverrideFile)
+	m := NewCORSManager([]string{"https://careers.example.com"}, path)
+
+	if got := preflight(m, "https://new.example.com"); got != "" {
+		t.Fatalf("new origin allowed before the update: %q", got)
+	}
+	if err := m.SetOrigins([]string{"https://careers.example.com", "https://new.example.com/"}); err != nil {
+		t.Fatalf("SetOrigins() error = %v", err)
+	}
+	if got := preflight(m, "https://new.example.com"); got != "https://new.example.com" {
+		t.Fatalf("Access-Control-Allow-Origin = %q after the update, want the new origin", got)
+	}
+
+	// The update survives a restart
+	saved, err := loadCORSOverride(path)
+	if err != nil || !slices.Equal(saved, []string{"https://careers.example.com", "https://new.example.com"}) {
+		t.Fatalf("loadCORSOverride() = %v, %v, want the updated origins", saved, err)
+	}
+
+	t.Run("invalid origins", func(t *testing.T) {
+		for _, origin := range []string{"careers.example.com", "ftp://files.example.com", "https://example.com/path", "https://example.com?q=1", "https://"} {
+			if err := m.SetOrigins([]string{"https://ok.example.com", origin}); !errors.Is(err, ErrInvalidOrigin) {
+				t.Errorf("SetOrigins(%q) error = %v, want %v", origin, err, ErrInvalidOrigin)
+			}
+		}
+		if got := m.Origins(); len(got) != 2 || got[1] != "https://new.example.com" {
+			t.Fatalf("Origins() = %v, want a rejected update to change nothing", got)
+		}
+	})
+}
+
+func TestLoadAllowedOrigins(t *testing.T) {
+	dir := t.TempDir()
+	previous, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(previous) })
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	t.Setenv("CORS_ALLOWED_ORIGINS", "https://careers.example.com,https://admin.example.com")
+
+	if got := loadAllowedOrigins(logger); !slices.Equal(got, []string{"https://careers.example.com", "https://admin.example.com"}) {
+		t.Fatalf("loadAllowedOrigins() = %v without an override file, want the env origins", got)
+	}
+
+	if err := NewCORSManager(nil, CORSOverrideFile).SetOrigins([]string{"https://new.example.com", "https://careers.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"https://careers.example.com", "https://admin.example.com", "https://new.example.com"}
+	if got := loadAllowedOrigins(logger); !slices.Equal(got, want) {
+		t.Fatalf("loadAllowedOrigins() = %v, want %v", got, want)
+	}
+
+	if err := os.WriteFile(CORSOverrideFile, []byte("not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if got := loadAllowedOrigins(logger); len(got) != 2 {
+		t.Fatalf("loadAllowedOrigins() = %v with a corrupt override, want the env origins", got)
+	}
+}
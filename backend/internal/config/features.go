@@ -0,0 +1,58 @@
+package config
+
+import (
+	"errors"
+	"sync/atomic"
+)
+
+// ErrUnknownFeature is returned when toggling a flag that does not exist
+var ErrUnknownFeature = errors.New("unknown feature flag")
+
+// FeatureFlags switch optional behaviour on and off. Flags are read on every
+// use, so admins can toggle them while the server is running.
+type FeatureFlags struct {
+	AIScoring                atomic.Bool
+	EmailNotifications       atomic.Bool
+	WebhookDelivery          atomic.Bool
+	ApplicationDeduplication atomic.Bool
+}
+
+// loadFeatureFlags reads the FEATURE_* environment variables. Every feature
+// is on unless disabled.
+func loadFeatureFlags() *FeatureFlags {
+	f := &FeatureFlags{}
+	f.AIScoring.Store(getEnvBool("FEATURE_AI_SCORING", true))
+	f.EmailNotifications.Store(getEnvBool("FEATURE_EMAIL_NOTIFICATIONS", true))
+	f.WebhookDelivery.Store(getEnvBool("FEATURE_WEBHOOK_DELIVERY", true))
+	f.ApplicationDeduplication.Store(getEnvBool("FEATURE_APPLICATION_DEDUPLICATION", true))
+	return f
+}
+
+// flags names each flag as it appears in the admin API
+func (f *FeatureFlags) flags() map[string]*atomic.Bool {
+	return map[string]*atomic.Bool{
+		"aiScoring":                &f.AIScoring,
+		"emailNotifications":       &f.EmailNotifications,
+		"webhookDelivery":          &f.WebhookDelivery,
+		"applicationDeduplication": &f.ApplicationDeduplication,
+	}
+}
+
+// Snapshot returns the current state of every flag by name
+func (f *FeatureFlags) Snapshot() map[string]bool {
+	snapshot := make(map[string]bool)
+	for name, flag := range f.flags() {
+		snapshot[name] = flag.Load()
+	}
+	return snapshot
+}
+
+// Set turns the named flag on or off
+func (f *FeatureFlags) Set(name string, enabled bool) error {
+	flag, ok := f.flags()[name]
+	if !ok {
+		return ErrUnknownFeature
+	}
+	flag.Store(enabled)
+	return nil
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"errors"
+	"maps"
+	"testing"
+)
+
+func TestLoadFeatureFlags(t *testing.T) {
+	t.Setenv("FEATURE_AI_SCORING", "false")
+	t.Setenv("FEATURE_WEBHOOK_DELIVERY", "true")
+
+	want := map[string]bool{
+		"aiScoring":                false,
+		"emailNotifications":       true,
+		"webhookDelivery":          true,
+		"applicationDeduplication": true,
+	}
+	if got := loadFeatureFlags().Snapshot(); !maps.Equal(got, want) {
+		t.Fatalf("Snapshot() = %v, want %v", got, want)
+	}
+}
+
+func TestFeatureFlags_Set(t *testing.T) {
+	flags := &FeatureFlags{}
+
+	if err := flags.Set("emailNotifications", true); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if !flags.EmailNotifications.Load() || flags.AIScoring.Load() {
+		t.Fatalf("flags = %v, want only emailNotifications on", flags.Snapshot())
+	}
+	if err := flags.Set("emailNotifications", false); err != nil || flags.EmailNotifications.Load() {
+		t.Fatalf("Set(false) = %v, flag = %v", err, flags.EmailNotifications.Load())
+	}
+	if err := flags.Set("darkMode", true); !errors.Is(err, ErrUnknownFeature) {
+		t.Fatalf("Set(darkMode) error = %v, want %v", err, ErrUnknownFeature)
+	}
+}
@@ -0,0 +1,72 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+)
+
+// secretsTimeout bounds the Secrets Manager call made during startup
+const secretsTimeout = 10 * time.Second
+
+// secretFields maps the keys read from Secrets Manager onto the config
+var secretFields = map[string]func(cfg *Config) *string{
+	"HUBHRMS_API_KEY":  func(cfg *Config) *string { return &cfg.HubHRMS.APIKey },
+	"SENDGRID_API_KEY": func(cfg *Config) *string { return &cfg.Email.SendGridKey },
+	"JWT_SECRET":       func(cfg *Config) *string { return &cfg.JWT.Secret },
+}
+
+// LoadSecretsFromAWS fetches a Secrets Manager secret holding a JSON object of
+// key-value pairs. Credentials, region and endpoint come from the default AWS
+// configuration, so AWS_ENDPOINT_URL_SECRETS_MANAGER can point it elsewhere.
+func LoadSecretsFromAWS(ctx context.Context, secretName string) (map[string]string, error) {
+	awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(getEnv("AWS_REGION", "us-east-1")))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	out, err := secretsmanager.NewFromConfig(awsCfg).GetSecretValue(ctx, &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(secretName),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read secret %s: %w", secretName, err)
+	}
+
+	var secrets map[string]string
+	if err := json.Unmarshal([]byte(aws.ToString(out.SecretString)), &secrets); err != nil {
+		return nil, fmt.Errorf("secret %s is not a JSON object of strings: %w", secretName, err)
+	}
+	return secrets, nil
+}
+
+// applySecrets overlays values from the named secret onto cfg. If the secret
+// cannot be read the environment values are kept, unless some are missing, in
+// which case the error is returned and the server cannot start.
+func applySecrets(ctx context.Context, cfg *Config, secretName string, logger *slog.Logger) error {
+	ctx, cancel := context.WithTimeout(ctx, secretsTimeout)
+	defer cancel()
+
+	secrets, err := LoadSecretsFromAWS(ctx, secretName)
+	if err != nil {
+		for key, field := range secretFields {
+			if *field(cfg) == "" {
+				return fmt.Errorf("failed to load secrets from AWS and %s is not set: %w", key, err)
+			}
+		}
+		logger.Warn("failed to load secrets from AWS, using environment values", "secret", secretName, "error", err)
+		return nil
+	}
+
+	for key, field := range secretFields {
+		if value := secrets[key]; value != "" {
+			*field(cfg) = value
+		}
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// fakeSecretsManager serves GetSecretValue over the AWS JSON protocol
+func fakeSecretsManager(t *testing.T, secrets map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if target := r.Header.Get("X-Amz-Target"); target != "secretsmanager.GetSecretValue" {
+			t.Errorf("X-Amz-Target = %q", target)
+		}
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 ") {
+			t.Error("request was not SigV4 signed")
+		}
+
+		var input struct{ SecretId string }
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		w.Header().Set("Content-Type", "application/x-amz-json-1.1")
+		if input.SecretId != "recruiting/prod" || secrets == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"__type":"ResourceNotFoundException","Message":"Secrets Manager can't find the specified secret."}`))
+			return
+		}
+		value, _ := json.Marshal(secrets)
+		json.NewEncoder(w).Encode(map[string]string{"Name": input.SecretId, "SecretString": string(value)})
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("AWS_ENDPOINT_URL_SECRETS_MANAGER", server.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	t.Setenv("AWS_REGION", "eu-west-1")
+	t.Setenv("AWS_MAX_ATTEMPTS", "1")
+	return server
+}
+
+func TestApplySecrets_OverlaysConfig(t *testing.T) {
+	fakeSecretsManager(t, map[string]string{
+		"HUBHRMS_API_KEY": "hub-from-aws",
+		"JWT_SECRET":      "jwt-from-aws",
+	})
+
+	cfg := validConfig()
+	cfg.Email.SendGridKey = "sendgrid-from-env"
+	if err := applySecrets(context.Background(), cfg, "recruiting/prod", slog.Default()); err != nil {
+		t.Fatalf("applySecrets() error = %v", err)
+	}
+	if cfg.HubHRMS.APIKey != "hub-from-aws" || cfg.JWT.Secret != "jwt-from-aws" {
+		t.Fatalf("secrets were not applied: %+v", cfg)
+	}
+	if cfg.Email.SendGridKey != "sendgrid-from-env" {
+		t.Fatalf("SendGridKey = %q, want the environment value", cfg.Email.SendGridKey)
+	}
+}
+
+func TestApplySecrets_FailureKeepsEnvironment(t *testing.T) {
+	fakeSecretsManager(t, nil)
+
+	cfg := validConfig()
+	cfg.HubHRMS.APIKey = "hub-from-env"
+	cfg.Email.SendGridKey = "sendgrid-from-env"
+	var logs bytes.Buffer
+	if err := applySecrets(context.Background(), cfg, "recruiting/prod", slog.New(slog.NewJSONHandler(&logs, nil))); err != nil {
+		t.Fatalf("applySecrets() error = %v, want the environment values to be used", err)
+	}
+	if cfg.HubHRMS.APIKey != "hub-from-env" {
+		t.Fatalf("APIKey = %q", cfg.HubHRMS.APIKey)
+	}
+
+	var record map[string]interface{}
+	if err := json.Unmarshal(logs.Bytes(), &record); err != nil {
+		t.Fatalf("log output %q is not one JSON record: %v", logs.String(), err)
+	}
+	if record["level"] != "WARN" || record["secret"] != "recruiting/prod" || record["error"] == nil {
+		t.Fatalf("logged %v, want a warning naming the secret and the error", record)
+	}
+}
+
+func TestApplySecrets_FailureWithMissingValue(t *testing.T) {
+	fakeSecretsManager(t, nil)
+
+	cfg := validConfig()
+	cfg.HubHRMS.APIKey = "hub-from-env"
+	err := applySecrets(context.Background(), cfg, "recruiting/prod", slog.Default())
+	if err == nil || !strings.Contains(err.Error(), "SENDGRID_API_KEY is not set") || !strings.Contains(err.Error(), "ResourceNotFoundException") {
+		t.Fatalf("applySecrets() error = %v, want the missing key and the AWS error", err)
+	}
+}
@@ -0,0 +1,166 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+// validConfig returns a config that passes Validate
+func validConfig() *Config {
+	cfg := &Config{}
+	cfg.HubHRMS.URL = "https://hub.example.com/graphql"
+	cfg.AWS.S3Bucket = "recruiting-uploads"
+	cfg.AWS.Region = "eu-west-1"
+	cfg.JWT.Secret = strings.Repeat("s", minJWTSecretLength)
+	return cfg
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(cfg *Config)
+		wantErr string
+	}{
+		{
+			name:   "valid config",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name: "valid config with optional settings",
+			modify: func(cfg *Config) {
+				cfg.AWS.Region = "us-gov-west-1"
+				cfg.Email.Required = true
+				cfg.Email.SendGridKey = "SG.key"
+				cfg.API.V1SunsetDate = "2027-01-31"
+				cfg.TLS.Enabled = true
+				cfg.TLS.CertFile = "cert.pem"
+				cfg.TLS.KeyFile = "key.pem"
+				cfg.GeoRestriction.Enabled = true
+				cfg.GeoRestriction.MaxMindDBPath = "GeoLite2-Country.mmdb"
+				cfg.GeoRestriction.AllowedCountries = []string{"DE", "FR"}
+			},
+		},
+		{
+			name: "valid config with autocert",
+			modify: func(cfg *Config) {
+				cfg.TLS.Enabled = true
+				cfg.TLS.AutoCert = true
+				cfg.TLS.Domains = []string{"careers.example.com"}
+			},
+		},
+		{
+			name:    "missing Hub-HRMS URL",
+			modify:  func(cfg *Config) { cfg.HubHRMS.URL = "" },
+			wantErr: "HUBHRMS_GRAPHQL_URL is required",
+		},
+		{
+			name:    "Hub-HRMS URL without scheme",
+			modify:  func(cfg *Config) { cfg.HubHRMS.URL = "hub.example.com/graphql" },
+			wantErr: "HUBHRMS_GRAPHQL_URL",
+		},
+		{
+			name:    "Hub-HRMS URL with other scheme",
+			modify:  func(cfg *Config) { cfg.HubHRMS.URL = "ftp://hub.example.com" },
+			wantErr: "is not a valid http(s) URL",
+		},
+		{
+			name:    "missing S3 bucket",
+			modify:  func(cfg *Config) { cfg.AWS.S3Bucket = "" },
+			wantErr: "AWS_S3_BUCKET is required",
+		},
+		{
+			name:    "missing AWS region",
+			modify:  func(cfg *Config) { cfg.AWS.Region = "" },
+			wantErr: "AWS_REGION",
+		},
+		{
+			name:    "unknown AWS region",
+			modify:  func(cfg *Config) { cfg.AWS.Region = "europe-1" },
+			wantErr: "is not a known AWS region",
+		},
+		{
+			name:    "required email without SendGrid key",
+			modify:  func(cfg *Config) { cfg.Email.Required = true },
+			wantErr: "SENDGRID_API_KEY is required",
+		},
+		{
+			name:    "missing JWT secret",
+			modify:  func(cfg *Config) { cfg.JWT.Secret = "" },
+			wantErr: "JWT_SECRET must be at least 32 bytes",
+		},
+		{
+			name:    "short JWT secret",
+			modify:  func(cfg *Config) { cfg.JWT.Secret = strings.Repeat("s", minJWTSecretLength-1) },
+			wantErr: "JWT_SECRET must be at least 32 bytes",
+		},
+		{
+			name: "missing JWT secret with RS256 public key",
+			modify: func(cfg *Config) {
+				cfg.JWT.Secret = ""
+				cfg.JWT.PublicKeyPath = "jwt.pub"
+			},
+			wantErr: "JWT_SECRET must be at least 32 bytes",
+		},
+		{
+			name:    "malformed sunset date",
+			modify:  func(cfg *Config) { cfg.API.V1SunsetDate = "31/01/2027" },
+			wantErr: "API_V1_SUNSET_DATE",
+		},
+		{
+			name:    "TLS without certificate",
+			modify:  func(cfg *Config) { cfg.TLS.Enabled = true },
+			wantErr: "TLS_CERT_FILE and TLS_KEY_FILE are required",
+		},
+		{
+			name: "autocert without domains",
+			modify: func(cfg *Config) {
+				cfg.TLS.Enabled = true
+				cfg.TLS.AutoCert = true
+			},
+			wantErr: "TLS_DOMAINS is required",
+		},
+		{
+			name: "geo restriction without database",
+			modify: func(cfg *Config) {
+				cfg.GeoRestriction.Enabled = true
+				cfg.GeoRestriction.AllowedCountries = []string{"DE"}
+			},
+			wantErr: "GEO_RESTRICTION_MAXMIND_DB_PATH is required",
+		},
+		{
+			name: "geo restriction without countries",
+			modify: func(cfg *Config) {
+				cfg.GeoRestriction.Enabled = true
+				cfg.GeoRestriction.MaxMindDBPath = "GeoLite2-Country.mmdb"
+			},
+			wantErr: "GEO_RESTRICTION_ALLOWED_COUNTRIES is required",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := validConfig()
+			tt.modify(cfg)
+			errs := Validate(cfg)
+
+			if tt.wantErr == "" {
+				if len(errs) != 0 {
+					t.Fatalf("Validate() = %v, want no errors", errs)
+				}
+				return
+			}
+			if len(errs) != 1 {
+				t.Fatalf("Validate() = %v, want exactly one error containing %q", errs, tt.wantErr)
+			}
+			if !strings.Contains(errs[0].Error(), tt.wantErr) {
+				t.Fatalf("Validate() = %v, want an error containing %q", errs[0], tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidate_ReportsEveryError(t *testing.T) {
+	if errs := Validate(&Config{}); len(errs) != 4 {
+		t.Fatalf("Validate(empty config) = %v, want 4 errors", errs)
+	}
+}
@@ -0,0 +1,101 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// BatchRequest is one query in a batch, identified by a caller-chosen key
+type BatchRequest struct {
+	Key       string
+	Query     string
+	Variables map[string]interface{}
+}
+
+// WithBatching sends Batch calls as a single HTTP request. Hub-HRMS versions
+// without batched GraphQL support need it disabled, in which case the
+// queries run one after another.
+func WithBatching(enabled bool) ClientOption {
+	return func(c *HubHRMSClient) {
+		c.batchEnabled = enabled
+	}
+}
+
+// Batch executes several queries and returns their responses keyed by
+// BatchRequest.Key. The batch is retried as a whole, like Query, so it must
+// not contain mutations.
+func (c *HubHRMSClient) Batch(ctx context.Context, requests []BatchRequest) (map[string]*GraphQLResponse, error) {
+	results := make(map[string]*GraphQLResponse, len(requests))
+
+	if !c.batchEnabled || len(requests) == 1 {
+		for _, req := range requests {
+			resp, err := c.Query(ctx, req.Query, req.Variables)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", req.Key, err)
+			}
+			results[req.Key] = resp
+		}
+		return results, nil
+	}
+
+	operations := make([]string, len(requests))
+	for i, req := range requests {
+		operations[i] = operationName(req.Query)
+	}
+
+	ctx, span := c.tracer.Start(ctx, "hubhrms.batch",
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("graphql.operations", strings.Join(operations, ","))),
+	)
+
+	var responses []GraphQLResponse
+	_, err := c.retry(ctx, func() (*GraphQLResponse, error) {
+		return nil, c.sendBatch(ctx, requests, operations, &responses)
+	})
+	endSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, req := range requests {
+		results[req.Key] = &responses[i]
+	}
+	return results, nil
+}
+
+// sendBatch posts the requests as a JSON array and logs the round trip
+func (c *HubHRMSClient) sendBatch(ctx context.Context, requests []BatchRequest, operations []string, out *[]GraphQLResponse) error {
+	payload := make([]GraphQLRequest, len(requests))
+	for i, req := range requests {
+		payload[i] = GraphQLRequest{Query: req.Query, Variables: req.Variables}
+	}
+
+	start := time.Now()
+	err := c.post(ctx, payload, out)
+	if err == nil && len(*out) != len(requests) {
+		err = permanent(fmt.Errorf("batch returned %d responses for %d requests", len(*out), len(requests)))
+	}
+	duration := time.Since(start)
+
+	c.metrics.ObserveHubHRMSCall("batch", duration)
+
+	attrs := []slog.Attr{
+		slog.String("operation", "batch"),
+		slog.Any("operations", operations),
+		slog.Float64("duration_ms", float64(duration.Microseconds())/1000),
+	}
+	level := slog.LevelInfo
+	if err != nil {
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", err.Error()))
+	}
+	c.logger.LogAttrs(ctx, level, "hubhrms call", attrs...)
+
+	return err
+}
@@ -0,0 +1,169 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchServer is a Hub-HRMS supporting batched GraphQL. It answers each
+// query with its variables under "echo" and records the HTTP requests made,
+// as the number of queries each carried, or 0 for an unbatched query.
+type batchServer struct {
+	mu       sync.Mutex
+	requests []int
+
+	// failFirst answers the first HTTP request with this status
+	failFirst int
+	// dropLast leaves the last response out of batched answers
+	dropLast bool
+}
+
+func (s *batchServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var body json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s.mu.Lock()
+	batched := bytes.HasPrefix(bytes.TrimSpace(body), []byte("["))
+	var reqs []GraphQLRequest
+	if batched {
+		json.Unmarshal(body, &reqs)
+		s.requests = append(s.requests, len(reqs))
+	} else {
+		var req GraphQLRequest
+		json.Unmarshal(body, &req)
+		reqs = []GraphQLRequest{req}
+		s.requests = append(s.requests, 0)
+	}
+	fail := s.failFirst != 0 && len(s.requests) == 1
+	s.mu.Unlock()
+
+	if fail {
+		http.Error(w, "unavailable", s.failFirst)
+		return
+	}
+
+	responses := make([]map[string]interface{}, len(reqs))
+	for i, req := range reqs {
+		responses[i] = map[string]interface{}{"data": map[string]interface{}{"echo": req.Variables}}
+	}
+	if !batched {
+		json.NewEncoder(w).Encode(responses[0])
+		return
+	}
+	if s.dropLast {
+		responses = responses[:len(responses)-1]
+	}
+	json.NewEncoder(w).Encode(responses)
+}
+
+func (s *batchServer) httpRequests() []int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]int(nil), s.requests...)
+}
+
+func newBatchServer(t *testing.T, server *batchServer, opts ...ClientOption) *HubHRMSClient {
+	t.Helper()
+	upstream := httptest.NewServer(server)
+	t.Cleanup(upstream.Close)
+	client := NewHubHRMSClient(upstream.URL, "", opts...)
+	t.Cleanup(client.Close)
+	return client
+}
+
+// testBatch is three queries told apart by their "n" variable
+var testBatch = []BatchRequest{
+	{Key: "metrics", Query: "query GetMetrics { metrics { total } }", Variables: map[string]interface{}{"n": "1"}},
+	{Key: "pipeline", Query: "query GetPipeline { pipeline { stage } }", Variables: map[string]interface{}{"n": "2"}},
+	{Key: "trend", Query: "query GetTrend { trend { day } }", Variables: map[string]interface{}{"n": "3"}},
+}
+
+// checkBatchResults checks every query's response came back under its key
+func checkBatchResults(t *testing.T, results map[string]*GraphQLResponse) {
+	t.Helper()
+	if len(results) != len(testBatch) {
+		t.Fatalf("got %d results, want %d", len(results), len(testBatch))
+	}
+	for _, req := range testBatch {
+		echo, _ := results[req.Key].Data.(map[string]interface{})["echo"].(map[string]interface{})
+		if echo["n"] != req.Variables["n"] {
+			t.Fatalf("results[%s] = %v, want the response to query %v", req.Key, results[req.Key].Data, req.Variables["n"])
+		}
+	}
+}
+
+func TestHubHRMSClient_Batch(t *testing.T) {
+	server := &batchServer{}
+	client := newBatchServer(t, server, WithBatching(true))
+
+	results, err := client.Batch(context.Background(), testBatch)
+	if err != nil {
+		t.Fatalf("Batch() error = %v", err)
+	}
+	checkBatchResults(t, results)
+	if requests := server.httpRequests(); len(requests) != 1 || requests[0] != len(testBatch) {
+		t.Fatalf("HTTP requests = %v, want one carrying all %d queries", requests, len(testBatch))
+	}
+}
+
+func TestHubHRMSClient_Batch_Disabled(t *testing.T) {
+	server := &batchServer{}
+	client := newBatchServer(t, server, WithBatching(false))
+
+	results, err := client.Batch(context.Background(), testBatch)
+	if err != nil {
+		t.Fatalf("Batch() error = %v", err)
+	}
+	checkBatchResults(t, results)
+	if requests := server.httpRequests(); len(requests) != 3 || requests[0] != 0 || requests[1] != 0 || requests[2] != 0 {
+		t.Fatalf("HTTP requests = %v, want three unbatched queries", requests)
+	}
+}
+
+func TestHubHRMSClient_Batch_SingleQuery(t *testing.T) {
+	server := &batchServer{}
+	client := newBatchServer(t, server, WithBatching(true))
+
+	results, err := client.Batch(context.Background(), testBatch[:1])
+	if err != nil || results["metrics"] == nil {
+		t.Fatalf("Batch() = %v, %v", results, err)
+	}
+	if requests := server.httpRequests(); len(requests) != 1 || requests[0] != 0 {
+		t.Fatalf("HTTP requests = %v, want a single unbatched query", requests)
+	}
+}
+
+func TestHubHRMSClient_Batch_RetriedWhole(t *testing.T) {
+	server := &batchServer{failFirst: http.StatusServiceUnavailable}
+	client := newBatchServer(t, server, WithBatching(true), WithRetry(3, time.Millisecond))
+
+	results, err := client.Batch(context.Background(), testBatch)
+	if err != nil {
+		t.Fatalf("Batch() error = %v", err)
+	}
+	checkBatchResults(t, results)
+	if requests := server.httpRequests(); len(requests) != 2 || requests[1] != len(testBatch) {
+		t.Fatalf("HTTP requests = %v, want the whole batch sent twice", requests)
+	}
+}
+
+func TestHubHRMSClient_Batch_MissingResponses(t *testing.T) {
+	server := &batchServer{dropLast: true}
+	client := newBatchServer(t, server, WithBatching(true), WithRetry(3, time.Millisecond))
+
+	if _, err := client.Batch(context.Background(), testBatch); err == nil {
+		t.Fatal("Batch() error = nil, want the missing response reported")
+	}
+	if requests := server.httpRequests(); len(requests) != 1 {
+		t.Fatalf("HTTP requests = %v, want no retry of a malformed answer", requests)
+	}
+}
@@ -0,0 +1,467 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+)
+
+var (
+	// ErrInvalidQuery is returned for documents that cannot be parsed
+	ErrInvalidQuery = errors.New("invalid GraphQL query")
+	// ErrQueryTooDeep is returned when selections nest past the depth limit
+	ErrQueryTooDeep = errors.New("query exceeds maximum depth")
+	// ErrQueryTooComplex is returned when a query selects too many fields
+	ErrQueryTooComplex = errors.New("query exceeds maximum complexity")
+	// ErrOperationNotAllowed is returned for operations missing from the allowlist
+	ErrOperationNotAllowed = errors.New("operation is not allowed")
+)
+
+// WithQueryLimits rejects proxied queries nested deeper than maxDepth or
+// selecting more than maxComplexity fields. Zero disables a limit.
+func WithQueryLimits(maxDepth, maxComplexity int) ClientOption {
+	return func(c *HubHRMSClient) {
+		c.maxDepth = maxDepth
+		c.maxComplexity = maxComplexity
+	}
+}
+
+// WithOperationAllowlist only lets the named operations through the proxy
+func WithOperationAllowlist(operations []string) ClientOption {
+	return func(c *HubHRMSClient) {
+		c.allowlist = make(map[string]bool, len(operations))
+		for _, name := range operations {
+			c.allowlist[name] = true
+		}
+	}
+}
+
+// LoadOperationAllowlist reads a JSON array of operation names
+func LoadOperationAllowlist(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read operation allowlist: %w", err)
+	}
+
+	var operations []string
+	if err := json.Unmarshal(data, &operations); err != nil {
+		return nil, fmt.Errorf("invalid operation allowlist %s: %w", path, err)
+	}
+	return operations, nil
+}
+
+// ValidateComplexity parses query and checks every operation in it against
+// the limits. Depth counts nested field selections, so `{ jobs { id } }` has
+// depth 2; complexity counts every field selected, with fragments expanded
+// where they are spread. Zero disables a limit.
+func ValidateComplexity(query string, maxDepth, maxComplexity int) error {
+	doc, err := parseDocument(query)
+	if err != nil {
+		return err
+	}
+	return doc.validate(maxDepth, maxComplexity)
+}
+
+// checkAllowlist reports whether every operation in query is allowlisted
+func checkAllowlist(query string, allowlist map[string]bool) error {
+	doc, err := parseDocument(query)
+	if err != nil {
+		return err
+	}
+	for _, op := range doc.operations {
+		if op.name == "" {
+			return fmt.Errorf("%w: anonymous operations are not permitted", ErrOperationNotAllowed)
+		}
+		if !allowlist[op.name] {
+			return fmt.Errorf("%w: %s", ErrOperationNotAllowed, op.name)
+		}
+	}
+	return nil
+}
+
+// document is the part of a parsed GraphQL document the limits look at
+type document struct {
+	operations []operation
+	fragments  map[string][]selection
+}
+
+type operation struct {
+	kind       string
+	name       string
+	selections []selection
+}
+
+// selection is a field, an inline fragment, or a fragment spread. name is
+// the field's name in the schema, not its alias.
+type selection struct {
+	field    bool
+	name     string
+	spread   string
+	children []selection
+}
+
+func (d *document) validate(maxDepth, maxComplexity int) error {
+	m := &measurer{
+		doc:           d,
+		maxDepth:      maxDepth,
+		maxComplexity: maxComplexity,
+		measured:      make(map[string]measurement),
+		active:        make(map[string]bool),
+	}
+	for _, op := range d.operations {
+		if _, err := m.measure(op.selections); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// measurement is the depth and field count of a selection set
+type measurement struct {
+	depth      int
+	complexity int
+}
+
+// measurer measures selection sets against the limits. Each fragment is
+// measured once and its measurement reused wherever it is spread, so
+// fragments spreading others several times cannot make measuring cost grow
+// exponentially. Measuring stops at the first limit exceeded.
+type measurer struct {
+	doc           *document
+	maxDepth      int
+	maxComplexity int
+	// measured holds the measurement of each fragment expanded so far
+	measured map[string]measurement
+	// active holds the fragments being expanded, to catch cycles
+	active map[string]bool
+}
+
+// measure returns the depth and field count of a selection set, following
+// fragment spreads
+func (m *measurer) measure(selections []selection) (measurement, error) {
+	var total measurement
+	for _, sel := range selections {
+		var child measurement
+		var err error
+		if sel.spread != "" {
+			child, err = m.fragment(sel.spread)
+		} else {
+			child, err = m.measure(sel.children)
+		}
+		if err != nil {
+			return measurement{}, err
+		}
+		if sel.field {
+			child.depth++
+			child.complexity = addCapped(child.complexity, 1)
+		}
+		total.depth = max(total.depth, child.depth)
+		total.complexity = addCapped(total.complexity, child.complexity)
+
+		if m.maxDepth > 0 && total.depth > m.maxDepth {
+			return measurement{}, fmt.Errorf("%w: limit %d", ErrQueryTooDeep, m.maxDepth)
+		}
+		if m.maxComplexity > 0 && total.complexity > m.maxComplexity {
+			return measurement{}, fmt.Errorf("%w: limit %d", ErrQueryTooComplex, m.maxComplexity)
+		}
+	}
+	return total, nil
+}
+
+// fragment measures the named fragment's selections, once per document
+func (m *measurer) fragment(name string) (measurement, error) {
+	if result, ok := m.measured[name]; ok {
+		return result, nil
+	}
+	if m.active[name] {
+		return measurement{}, fmt.Errorf("%w: fragment %s spreads itself", ErrInvalidQuery, name)
+	}
+	selections, ok := m.doc.fragments[name]
+	if !ok {
+		return measurement{}, fmt.Errorf("%w: unknown fragment %s", ErrInvalidQuery, name)
+	}
+
+	m.active[name] = true
+	result, err := m.measure(selections)
+	delete(m.active, name)
+	if err != nil {
+		return measurement{}, err
+	}
+	m.measured[name] = result
+	return result, nil
+}
+
+// addCapped adds two field counts, saturating instead of overflowing when
+// no complexity limit stops the count first
+func addCapped(a, b int) int {
+	if a > math.MaxInt-b {
+		return math.MaxInt
+	}
+	return a + b
+}
+
+// parser is a recursive descent parser for the executable subset of GraphQL.
+// Arguments, variable definitions and directives are skipped rather than
+// interpreted, since only the shape of the selections matters here.
+type parser struct {
+	tokens []string
+	pos    int
+}
+
+func parseDocument(query string) (*document, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	doc := &document{fragments: make(map[string][]selection)}
+
+	for !p.done() {
+		switch p.peek() {
+		case "{":
+			selections, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, operation{kind: "query", selections: selections})
+		case "query", "mutation", "subscription":
+			op := operation{kind: p.next()}
+			if isName(p.peek()) {
+				op.name = p.next()
+			}
+			if p.peek() == "(" {
+				if err := p.skipBalanced(); err != nil {
+					return nil, err
+				}
+			}
+			if err := p.directives(); err != nil {
+				return nil, err
+			}
+			if op.selections, err = p.selectionSet(); err != nil {
+				return nil, err
+			}
+			doc.operations = append(doc.operations, op)
+		case "fragment":
+			p.next()
+			name := p.next()
+			if !isName(name) || p.next() != "on" || !isName(p.next()) {
+				return nil, fmt.Errorf("%w: malformed fragment definition", ErrInvalidQuery)
+			}
+			if err := p.directives(); err != nil {
+				return nil, err
+			}
+			selections, err := p.selectionSet()
+			if err != nil {
+				return nil, err
+			}
+			doc.fragments[name] = selections
+		default:
+			return nil, fmt.Errorf("%w: unexpected %q", ErrInvalidQuery, p.peek())
+		}
+	}
+
+	if len(doc.operations) == 0 {
+		return nil, fmt.Errorf("%w: no operations", ErrInvalidQuery)
+	}
+	return doc, nil
+}
+
+func (p *parser) selectionSet() ([]selection, error) {
+	if p.next() != "{" {
+		return nil, fmt.Errorf("%w: expected selection set", ErrInvalidQuery)
+	}
+
+	var selections []selection
+	for p.peek() != "}" {
+		if p.done() {
+			return nil, fmt.Errorf("%w: unterminated selection set", ErrInvalidQuery)
+		}
+		sel, err := p.selection()
+		if err != nil {
+			return nil, err
+		}
+		selections = append(selections, sel)
+	}
+	p.next()
+
+	if len(selections) == 0 {
+		return nil, fmt.Errorf("%w: empty selection set", ErrInvalidQuery)
+	}
+	return selections, nil
+}
+
+func (p *parser) selection() (selection, error) {
+	if p.peek() == "..." {
+		p.next()
+		// A named spread, unless it is an inline fragment's type condition
+		if isName(p.peek()) && p.peek() != "on" {
+			name := p.next()
+			return selection{spread: name}, p.directives()
+		}
+		if p.peek() == "on" {
+			p.next()
+			if !isName(p.next()) {
+				return selection{}, fmt.Errorf("%w: malformed type condition", ErrInvalidQuery)
+			}
+		}
+		if err := p.directives(); err != nil {
+			return selection{}, err
+		}
+		children, err := p.selectionSet()
+		return selection{children: children}, err
+	}
+
+	name := p.next()
+	if !isName(name) {
+		return selection{}, fmt.Errorf("%w: expected field name", ErrInvalidQuery)
+	}
+	if p.peek() == ":" {
+		p.next()
+		if name = p.next(); !isName(name) {
+			return selection{}, fmt.Errorf("%w: expected field name after alias", ErrInvalidQuery)
+		}
+	}
+	if p.peek() == "(" {
+		if err := p.skipBalanced(); err != nil {
+			return selection{}, err
+		}
+	}
+	if err := p.directives(); err != nil {
+		return selection{}, err
+	}
+
+	sel := selection{field: true, name: name}
+	if p.peek() == "{" {
+		children, err := p.selectionSet()
+		if err != nil {
+			return selection{}, err
+		}
+		sel.children = children
+	}
+	return sel, nil
+}
+
+func (p *parser) directives() error {
+	for p.peek() == "@" {
+		p.next()
+		if !isName(p.next()) {
+			return fmt.Errorf("%w: expected directive name", ErrInvalidQuery)
+		}
+		if p.peek() == "(" {
+			if err := p.skipBalanced(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// skipBalanced skips from an opening bracket to its matching close
+func (p *parser) skipBalanced() error {
+	depth := 0
+	for !p.done() {
+		switch p.next() {
+		case "(", "[", "{":
+			depth++
+		case ")", "]", "}":
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unbalanced brackets", ErrInvalidQuery)
+}
+
+func (p *parser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *parser) peek() string {
+	if p.done() {
+		return ""
+	}
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
+
+func isName(token string) bool {
+	if token == "" {
+		return false
+	}
+	c := token[0]
+	return c == '_' || (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+// tokenize splits a document into punctuators, names and values. Whitespace,
+// commas and comments are dropped; string and number values become single
+// tokens.
+func tokenize(query string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(query); {
+		c := query[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',':
+			i++
+		case c == '#':
+			for i < len(query) && query[i] != '\n' {
+				i++
+			}
+		case strings.HasPrefix(query[i:], "..."):
+			tokens = append(tokens, "...")
+			i += 3
+		case strings.HasPrefix(query[i:], `"""`):
+			end := strings.Index(query[i+3:], `"""`)
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unterminated block string", ErrInvalidQuery)
+			}
+			tokens = append(tokens, query[i:i+3+end+3])
+			i += 3 + end + 3
+		case c == '"':
+			j := i + 1
+			for j < len(query) && query[j] != '"' && query[j] != '\n' {
+				if query[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(query) || query[j] != '"' {
+				return nil, fmt.Errorf("%w: unterminated string", ErrInvalidQuery)
+			}
+			tokens = append(tokens, query[i:j+1])
+			i = j + 1
+		case strings.IndexByte("!$&()[]{}:=@|", c) >= 0:
+			tokens = append(tokens, string(c))
+			i++
+		case isName(string(c)):
+			j := i + 1
+			for j < len(query) && (isName(string(query[j])) || isDigit(query[j])) {
+				j++
+			}
+			tokens = append(tokens, query[i:j])
+			i = j
+		case c == '-' || isDigit(c):
+			j := i + 1
+			for j < len(query) && (isDigit(query[j]) || strings.IndexByte(".eE+-", query[j]) >= 0) {
+				j++
+			}
+			tokens = append(tokens, query[i:j])
+			i = j
+		default:
+			return nil, fmt.Errorf("%w: unexpected character %q", ErrInvalidQuery, c)
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,181 @@
+package gateway
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestValidateComplexity(t *testing.T) {
+	tests := []struct {
+		name          string
+		query         string
+		maxDepth      int
+		maxComplexity int
+		wantErr       error
+	}{
+		{
+			name:     "at depth limit",
+			query:    `{ jobs { department { name } } }`,
+			maxDepth: 3,
+		},
+		{
+			name:     "over depth limit",
+			query:    `{ jobs { department { manager { name } } } }`,
+			maxDepth: 3,
+			wantErr:  ErrQueryTooDeep,
+		},
+		{
+			name:     "over depth limit through a fragment",
+			query:    `query Q { jobs { ...JobFields } } fragment JobFields on Job { department { name } }`,
+			maxDepth: 2,
+			wantErr:  ErrQueryTooDeep,
+		},
+		{
+			name:          "at complexity limit",
+			query:         `{ jobs { id title } }`,
+			maxComplexity: 3,
+		},
+		{
+			name:          "over complexity limit",
+			query:         `{ jobs { id title status } }`,
+			maxComplexity: 3,
+			wantErr:       ErrQueryTooComplex,
+		},
+		{
+			name:          "fragment counted at each spread",
+			query:         `query Q { a: jobs { ...F } b: jobs { ...F } } fragment F on Job { id title }`,
+			maxComplexity: 5,
+			wantErr:       ErrQueryTooComplex,
+		},
+		{
+			name:    "fragment spreading itself",
+			query:   `query Q { jobs { ...F } } fragment F on Job { id ...F }`,
+			wantErr: ErrInvalidQuery,
+		},
+		{
+			name:    "unknown fragment",
+			query:   `query Q { jobs { ...Missing } }`,
+			wantErr: ErrInvalidQuery,
+		},
+		{
+			name:     "no limits",
+			query:    `{ jobs { department { manager { name } } } }`,
+			maxDepth: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateComplexity(tt.query, tt.maxDepth, tt.maxComplexity)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("ValidateComplexity() error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateComplexity() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// doubleSpreadQuery builds fragments that each spread the next one twice,
+// so the expanded query selects 2^levels leaf fields
+func doubleSpreadQuery(levels int) string {
+	var b strings.Builder
+	b.WriteString("query Q { jobs { ...F0 } }\n")
+	for i := 0; i < levels; i++ {
+		fmt.Fprintf(&b, "fragment F%d on Job { a: node { ...F%d } b: node { ...F%d } }\n", i, i+1, i+1)
+	}
+	fmt.Fprintf(&b, "fragment F%d on Job { id }\n", levels)
+	return b.String()
+}
+
+func TestValidateComplexity_NestedDoubleSpread(t *testing.T) {
+	query := doubleSpreadQuery(60)
+
+	done := make(chan error, 1)
+	go func() {
+		done <- ValidateComplexity(query, 0, 1000)
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrQueryTooComplex) {
+			t.Fatalf("ValidateComplexity() error = %v, want %v", err, ErrQueryTooComplex)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("ValidateComplexity() did not return; fragments are being re-expanded")
+	}
+
+	// Without a complexity limit the count saturates rather than overflowing
+	if err := ValidateComplexity(doubleSpreadQuery(70), 0, 0); err != nil {
+		t.Fatalf("ValidateComplexity() without limits error = %v", err)
+	}
+}
+
+func TestProxyHandler_Limits(t *testing.T) {
+	forwarded := 0
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		forwarded++
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer upstream.Close()
+
+	client := NewHubHRMSClient(upstream.URL, "",
+		WithQueryLimits(3, 10),
+		WithOperationAllowlist([]string{"GetJobs"}),
+	)
+	defer client.Close()
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "allowlisted operation within limits",
+			body:       `{"query":"query GetJobs { jobs { id } }"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "operation missing from allowlist",
+			body:       `{"query":"query GetUsers { users { id } }"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "anonymous operation",
+			body:       `{"query":"{ jobs { id } }"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "allowlisted operation over depth limit",
+			body:       `{"query":"query GetJobs { jobs { department { manager { name } } } }"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "body over size limit",
+			body:       `{"query":"query GetJobs { jobs { id } }","pad":"` + strings.Repeat("x", maxProxyBodySize) + `"}`,
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forwarded = 0
+			req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			client.ProxyHandler(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if wantForwarded := tt.wantStatus == http.StatusOK; (forwarded > 0) != wantForwarded {
+				t.Fatalf("forwarded = %d, want forwarded %v", forwarded, wantForwarded)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package gateway
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ErrorClass is the kind of failure a set of GraphQL errors describes
+type ErrorClass string
+
+// GraphQL error classes
+const (
+	ErrorNotFound   ErrorClass = "NOT_FOUND"
+	ErrorForbidden  ErrorClass = "FORBIDDEN"
+	ErrorValidation ErrorClass = "VALIDATION"
+	ErrorInternal   ErrorClass = "INTERNAL"
+)
+
+// errorCodes maps extensions.code values, as Apollo and Hub-HRMS send them,
+// to a class
+var errorCodes = map[string]ErrorClass{
+	"NOT_FOUND":                 ErrorNotFound,
+	"FORBIDDEN":                 ErrorForbidden,
+	"UNAUTHENTICATED":           ErrorForbidden,
+	"UNAUTHORIZED":              ErrorForbidden,
+	"VALIDATION":                ErrorValidation,
+	"VALIDATION_ERROR":          ErrorValidation,
+	"BAD_USER_INPUT":            ErrorValidation,
+	"BAD_REQUEST":               ErrorValidation,
+	"GRAPHQL_VALIDATION_FAILED": ErrorValidation,
+	"GRAPHQL_PARSE_FAILED":      ErrorValidation,
+	"INTERNAL":                  ErrorInternal,
+	"INTERNAL_SERVER_ERROR":     ErrorInternal,
+}
+
+// errorPatterns classify errors without a known code by their message,
+// checked in order
+var errorPatterns = []struct {
+	class    ErrorClass
+	patterns []string
+}{
+	{ErrorNotFound, []string{"not found", "does not exist", "no such"}},
+	{ErrorForbidden, []string{"forbidden", "not authorized", "unauthorized", "permission", "access denied"}},
+	{ErrorValidation, []string{"invalid", "required", "must be", "validation", "cannot be"}},
+}
+
+// GatewayError is a Hub-HRMS response that failed outright: it carried
+// GraphQL errors and no data
+type GatewayError struct {
+	Class   ErrorClass
+	Message string
+	Errors  []GraphQLError
+}
+
+func (e *GatewayError) Error() string {
+	return "Hub-HRMS " + strings.ToLower(strings.ReplaceAll(string(e.Class), "_", " ")) + " error: " + e.Message
+}
+
+// StatusCode is the HTTP status to answer with for the error's class
+func (e *GatewayError) StatusCode() int {
+	switch e.Class {
+	case ErrorNotFound:
+		return http.StatusNotFound
+	case ErrorForbidden:
+		return http.StatusForbidden
+	case ErrorValidation:
+		return http.StatusBadRequest
+	default:
+		return http.StatusBadGateway
+	}
+}
+
+// ClassifyGraphQLErrors describes errs by their most serious class: an
+// internal error outweighs a forbidden one, which outweighs not found, which
+// outweighs validation. The message is that of the first error in the class.
+func ClassifyGraphQLErrors(errs []GraphQLError) GatewayError {
+	classified := GatewayError{Class: ErrorInternal, Errors: errs}
+	if len(errs) == 0 {
+		return classified
+	}
+
+	rank := map[ErrorClass]int{ErrorValidation: 0, ErrorNotFound: 1, ErrorForbidden: 2, ErrorInternal: 3}
+	best := -1
+	for _, gqlErr := range errs {
+		class := classifyGraphQLError(gqlErr)
+		if rank[class] > best {
+			best = rank[class]
+			classified.Class = class
+			classified.Message = gqlErr.Message
+		}
+	}
+	return classified
+}
+
+// classifyGraphQLError classifies one error by extensions.code, falling back
+// to its message. Anything unrecognised is internal.
+func classifyGraphQLError(gqlErr GraphQLError) ErrorClass {
+	if code, ok := gqlErr.Extensions["code"].(string); ok {
+		if class, ok := errorCodes[strings.ToUpper(code)]; ok {
+			return class
+		}
+	}
+
+	message := strings.ToLower(gqlErr.Message)
+	for _, group := range errorPatterns {
+		for _, pattern := range group.patterns {
+			if strings.Contains(message, pattern) {
+				return group.class
+			}
+		}
+	}
+	return ErrorInternal
+}
+
+// failedResponse returns the error for a response with GraphQL errors and no
+// data. Responses with partial data are left to the caller.
+func failedResponse(resp *GraphQLResponse) *GatewayError {
+	if resp == nil || len(resp.Errors) == 0 || hasData(resp.Data) {
+		return nil
+	}
+	classified := ClassifyGraphQLErrors(resp.Errors)
+	return &classified
+}
+
+// hasData reports whether any top-level field of a response's data is set
+func hasData(data interface{}) bool {
+	fields, ok := data.(map[string]interface{})
+	if !ok {
+		return data != nil
+	}
+	for _, value := range fields {
+		if value != nil {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,142 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// coded is a GraphQL error with extensions.code set
+func coded(message, code string) GraphQLError {
+	return GraphQLError{Message: message, Extensions: map[string]interface{}{"code": code}}
+}
+
+func TestClassifyGraphQLErrors(t *testing.T) {
+	tests := []struct {
+		name        string
+		errs        []GraphQLError
+		wantClass   ErrorClass
+		wantMessage string
+		wantStatus  int
+	}{
+		// By extensions.code
+		{name: "code NOT_FOUND", errs: []GraphQLError{coded("no application", "NOT_FOUND")}, wantClass: ErrorNotFound, wantStatus: http.StatusNotFound},
+		{name: "code FORBIDDEN", errs: []GraphQLError{coded("nope", "FORBIDDEN")}, wantClass: ErrorForbidden, wantStatus: http.StatusForbidden},
+		{name: "code UNAUTHENTICATED", errs: []GraphQLError{coded("nope", "UNAUTHENTICATED")}, wantClass: ErrorForbidden, wantStatus: http.StatusForbidden},
+		{name: "code BAD_USER_INPUT", errs: []GraphQLError{coded("bad", "BAD_USER_INPUT")}, wantClass: ErrorValidation, wantStatus: http.StatusBadRequest},
+		{name: "code GRAPHQL_VALIDATION_FAILED", errs: []GraphQLError{coded("bad", "GRAPHQL_VALIDATION_FAILED")}, wantClass: ErrorValidation, wantStatus: http.StatusBadRequest},
+		{name: "code in lower case", errs: []GraphQLError{coded("bad", "bad_user_input")}, wantClass: ErrorValidation, wantStatus: http.StatusBadRequest},
+		{name: "code INTERNAL_SERVER_ERROR", errs: []GraphQLError{coded("Job not found", "INTERNAL_SERVER_ERROR")}, wantClass: ErrorInternal, wantStatus: http.StatusBadGateway},
+		{name: "unknown code falls back to message", errs: []GraphQLError{coded("Job not found", "E1234")}, wantClass: ErrorNotFound, wantStatus: http.StatusNotFound},
+
+		// By message
+		{name: "not found", errs: []GraphQLError{{Message: "Application app-9 not found"}}, wantClass: ErrorNotFound, wantStatus: http.StatusNotFound},
+		{name: "does not exist", errs: []GraphQLError{{Message: "Job does not exist"}}, wantClass: ErrorNotFound, wantStatus: http.StatusNotFound},
+		{name: "not authorized", errs: []GraphQLError{{Message: "Not Authorized to view salaries"}}, wantClass: ErrorForbidden, wantStatus: http.StatusForbidden},
+		{name: "permission", errs: []GraphQLError{{Message: "missing permission jobs:write"}}, wantClass: ErrorForbidden, wantStatus: http.StatusForbidden},
+		{name: "invalid", errs: []GraphQLError{{Message: "jobId is invalid"}}, wantClass: ErrorValidation, wantStatus: http.StatusBadRequest},
+		{name: "required", errs: []GraphQLError{{Message: "email is required"}}, wantClass: ErrorValidation, wantStatus: http.StatusBadRequest},
+		{name: "must be", errs: []GraphQLError{{Message: "salary must be positive"}}, wantClass: ErrorValidation, wantStatus: http.StatusBadRequest},
+		{name: "unrecognised", errs: []GraphQLError{{Message: "database timeout"}}, wantClass: ErrorInternal, wantStatus: http.StatusBadGateway},
+		{name: "no errors", wantClass: ErrorInternal, wantStatus: http.StatusBadGateway},
+
+		// Several errors take the most serious class
+		{
+			name:      "forbidden outweighs not found",
+			errs:      []GraphQLError{{Message: "candidate not found"}, {Message: "forbidden"}, {Message: "job not found"}},
+			wantClass: ErrorForbidden, wantMessage: "forbidden", wantStatus: http.StatusForbidden,
+		},
+		{
+			name:      "internal outweighs everything",
+			errs:      []GraphQLError{{Message: "email is required"}, {Message: "boom"}, {Message: "forbidden"}},
+			wantClass: ErrorInternal, wantMessage: "boom", wantStatus: http.StatusBadGateway,
+		},
+		{
+			name:      "first message of the class",
+			errs:      []GraphQLError{{Message: "email is required"}, {Message: "phone is invalid"}},
+			wantClass: ErrorValidation, wantMessage: "email is required", wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClassifyGraphQLErrors(tt.errs)
+			if got.Class != tt.wantClass {
+				t.Fatalf("class = %s, want %s", got.Class, tt.wantClass)
+			}
+			if tt.wantMessage == "" && len(tt.errs) > 0 {
+				tt.wantMessage = tt.errs[0].Message
+			}
+			if got.Message != tt.wantMessage || len(got.Errors) != len(tt.errs) {
+				t.Fatalf("error = %+v, want message %q and every error kept", got, tt.wantMessage)
+			}
+			if status := got.StatusCode(); status != tt.wantStatus {
+				t.Fatalf("StatusCode() = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestGatewayError_Error(t *testing.T) {
+	err := &GatewayError{Class: ErrorNotFound, Message: "Job not found"}
+	if got := err.Error(); got != "Hub-HRMS not found error: Job not found" {
+		t.Fatalf("Error() = %q", got)
+	}
+}
+
+func TestHubHRMSClient_GatewayErrors(t *testing.T) {
+	var response string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(response))
+	}))
+	defer upstream.Close()
+	client := NewHubHRMSClient(upstream.URL, "")
+	defer client.Close()
+
+	tests := []struct {
+		name      string
+		response  string
+		wantClass ErrorClass
+	}{
+		{name: "errors without data", response: `{"errors":[{"message":"Job not found"}]}`, wantClass: ErrorNotFound},
+		{name: "errors with null fields", response: `{"data":{"job":null},"errors":[{"message":"forbidden"}]}`, wantClass: ErrorForbidden},
+		{name: "partial data", response: `{"data":{"job":{"id":"job-1"},"stats":null},"errors":[{"message":"stats not found"}]}`},
+		{name: "no errors", response: `{"data":{"job":null}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			response = tt.response
+			for name, call := range map[string]func() (*GraphQLResponse, error){
+				"Query": func() (*GraphQLResponse, error) {
+					return client.Query(context.Background(), "query GetJob { job { id } }", nil)
+				},
+				"Mutate": func() (*GraphQLResponse, error) {
+					return client.Mutate(context.Background(), "mutation M { m }", nil)
+				},
+			} {
+				resp, err := call()
+				var gwErr *GatewayError
+				if tt.wantClass == "" {
+					if err != nil || resp == nil {
+						t.Fatalf("%s() = %v, %v, want the response", name, resp, err)
+					}
+					continue
+				}
+				if !errors.As(err, &gwErr) || gwErr.Class != tt.wantClass || resp != nil {
+					t.Fatalf("%s() = %v, %v, want a %s GatewayError", name, resp, err, tt.wantClass)
+				}
+			}
+		})
+	}
+
+	// The partial response keeps its errors for the caller
+	response = `{"data":{"job":{"id":"job-1"}},"errors":[{"message":"stats not found","path":["stats"]}]}`
+	resp, _ := client.Query(context.Background(), "query GetJob { job { id } }", nil)
+	encoded, _ := json.Marshal(resp)
+	if len(resp.Errors) != 1 || !strings.Contains(string(encoded), `"job-1"`) {
+		t.Fatalf("response = %s, want the data and its error", encoded)
+	}
+}
@@ -4,11 +4,28 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
 	"time"
+
+	"github.com/vektah/gqlparser/v2/ast"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
+
+	"hr-recruiting/internal/metrics"
+	"hr-recruiting/internal/requestid"
 )
 
 // HubHRMSClient is a GraphQL client for Hub-HRMS
@@ -16,6 +33,33 @@ type HubHRMSClient struct {
 	url        string
 	apiKey     string
 	httpClient *http.Client
+	transport  *http.Transport
+	pool       *poolTracker
+
+	idleCheckInterval time.Duration
+	stop              chan struct{}
+	stopOnce          sync.Once
+
+	maxAttempts int
+	retryBase   time.Duration
+
+	batchEnabled         bool
+	maxConcurrentQueries int
+	debugLog             bool
+
+	maxDepth      int
+	maxComplexity int
+	allowlist     map[string]bool
+
+	persisted     *PersistedQueryStore
+	persistedOnly bool
+
+	schemaMu sync.Mutex
+	schema   *ast.Schema
+
+	logger  *slog.Logger
+	tracer  trace.Tracer
+	metrics *metrics.Metrics
 }
 
 // GraphQLRequest represents a GraphQL request
@@ -38,89 +82,435 @@ type GraphQLError struct {
 	Extensions map[string]interface{} `json:"extensions,omitempty"`
 }
 
-// NewHubHRMSClient creates a new Hub-HRMS client
-func NewHubHRMSClient(url, apiKey string) *HubHRMSClient {
-	return &HubHRMSClient{
+// NewHubHRMSClient creates a new Hub-HRMS client with the default connection
+// pool settings
+func NewHubHRMSClient(url, apiKey string, opts ...ClientOption) *HubHRMSClient {
+	return NewHubHRMSClientWithOptions(url, apiKey, HubHRMSClientOptions{}, opts...)
+}
+
+// NewHubHRMSClientWithOptions creates a new Hub-HRMS client whose connection
+// pool is sized by options. Zero fields keep their defaults.
+func NewHubHRMSClientWithOptions(url, apiKey string, options HubHRMSClientOptions, opts ...ClientOption) *HubHRMSClient {
+	options = options.withDefaults()
+	pool := &poolTracker{
+		dialer: &net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		},
+	}
+
+	transport := &http.Transport{
+		DialContext:         pool.dialContext,
+		DisableKeepAlives:   false,
+		MaxIdleConns:        options.MaxIdleConns,
+		MaxIdleConnsPerHost: options.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     options.MaxConnsPerHost,
+		IdleConnTimeout:     options.IdleConnTimeout,
+		TLSHandshakeTimeout: options.TLSHandshakeTimeout,
+	}
+
+	c := &HubHRMSClient{
 		url:    url,
 		apiKey: apiKey,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-			Transport: &http.Transport{
-				MaxIdleConns:        100,
-				MaxIdleConnsPerHost: 10,
-				IdleConnTimeout:     90 * time.Second,
-			},
+			Timeout:   options.RequestTimeout,
+			Transport: &requestIDTransport{base: &activeTransport{base: transport, pool: pool}},
 		},
+		transport:         transport,
+		pool:              pool,
+		idleCheckInterval: 30 * time.Second,
+		stop:              make(chan struct{}),
+		maxAttempts:       1,
+		logger:            slog.Default(),
+		tracer:            otel.Tracer(tracerName),
+
+		maxConcurrentQueries: defaultMaxConcurrentQueries,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	if c.debugLog {
+		c.httpClient.Transport = &debugTransport{base: c.httpClient.Transport, logger: c.logger}
+	}
+
+	if c.idleCheckInterval > 0 {
+		go c.sweepIdleConnections()
+	}
+
+	return c
+}
+
+// WithLogger sets the logger used for outbound GraphQL calls
+func WithLogger(logger *slog.Logger) ClientOption {
+	return func(c *HubHRMSClient) {
+		c.logger = logger
+	}
+}
+
+// tracerName names the instrumentation scope of the client's spans
+const tracerName = "hr-recruiting/internal/gateway"
+
+// WithTracer records a span for every query and mutation using tp rather
+// than the global tracer provider. The trace is propagated to Hub-HRMS either
+// way.
+func WithTracer(tp trace.TracerProvider) ClientOption {
+	return func(c *HubHRMSClient) {
+		c.tracer = tp.Tracer(tracerName)
 	}
 }
 
-// Query executes a GraphQL query
+// WithMetrics records the latency of every GraphQL call
+func WithMetrics(m *metrics.Metrics) ClientOption {
+	return func(c *HubHRMSClient) {
+		c.metrics = m
+	}
+}
+
+// WithDebugLog logs every request and response body sent to and received
+// from Hub-HRMS at debug level. Bodies contain candidate data, so this is for
+// diagnosing integration problems only.
+func WithDebugLog(enabled bool) ClientOption {
+	return func(c *HubHRMSClient) {
+		c.debugLog = enabled
+	}
+}
+
+// requestIDTransport sends the request ID from the request's context to
+// Hub-HRMS, unless the request already names one
+type requestIDTransport struct {
+	base http.RoundTripper
+}
+
+func (t *requestIDTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if requestID := requestid.FromContext(req.Context()); requestID != "" && req.Header.Get(requestid.Header) == "" {
+		req = req.Clone(req.Context())
+		req.Header.Set(requestid.Header, requestID)
+	}
+	return t.base.RoundTrip(req)
+}
+
+// debugLogLimit is the most of a request or response body logged
+const debugLogLimit = 4096
+
+// debugTransport logs the body of each request and response passing through
+// it. Bodies are read in full and replaced, so callers still see them intact.
+type debugTransport struct {
+	base   http.RoundTripper
+	logger *slog.Logger
+}
+
+func (t *debugTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if req.Body != nil {
+		body, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		requestBody = body
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	t.logger.Debug("hubhrms request", "method", req.Method, "url", req.URL.String(), "body", truncateBody(requestBody))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.logger.Debug("hubhrms request failed", "url", req.URL.String(), "error", err)
+		return nil, err
+	}
+
+	responseBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+	t.logger.Debug("hubhrms response", "status", resp.StatusCode, "url", req.URL.String(), "body", truncateBody(responseBody))
+
+	return resp, nil
+}
+
+// truncateBody returns body as a string of at most debugLogLimit bytes
+func truncateBody(body []byte) string {
+	if len(body) > debugLogLimit {
+		return string(body[:debugLogLimit]) + "...(truncated)"
+	}
+	return string(body)
+}
+
+// Query executes a GraphQL query, retrying transient failures when the client
+// was created WithRetry. A response with GraphQL errors and no data is
+// returned as a *GatewayError.
 func (c *HubHRMSClient) Query(ctx context.Context, query string, variables map[string]interface{}) (*GraphQLResponse, error) {
+	ctx, span := c.startSpan(ctx, "hubhrms.query", query)
+
+	resp, err := c.retry(ctx, func() (*GraphQLResponse, error) {
+		return c.execute(ctx, query, variables)
+	})
+	if gwErr := failedResponse(resp); err == nil && gwErr != nil {
+		resp, err = nil, gwErr
+	}
+	endSpan(span, err)
+	return resp, err
+}
+
+// Mutate executes a GraphQL mutation. Mutations are never retried since they
+// are not idempotent. A response with GraphQL errors and no data is returned
+// as a *GatewayError.
+func (c *HubHRMSClient) Mutate(ctx context.Context, mutation string, variables map[string]interface{}) (*GraphQLResponse, error) {
+	ctx, span := c.startSpan(ctx, "hubhrms.mutate", mutation)
+
+	resp, err := c.execute(ctx, mutation, variables)
+	if gwErr := failedResponse(resp); err == nil && gwErr != nil {
+		resp, err = nil, gwErr
+	}
+	endSpan(span, err)
+	return resp, err
+}
+
+// defaultMaxConcurrentQueries is how many QueryAll queries run at once when
+// the client is not created WithMaxConcurrentQueries
+const defaultMaxConcurrentQueries = 5
+
+// NamedQuery is one query run by QueryAll, identified by a caller-chosen name
+type NamedQuery struct {
+	Name      string
+	Query     string
+	Variables map[string]interface{}
+}
+
+// QueryErrors holds the failures of a QueryAll call by query name
+type QueryErrors map[string]error
+
+func (e QueryErrors) Error() string {
+	names := make([]string, 0, len(e))
+	for name := range e {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	messages := make([]string, len(names))
+	for i, name := range names {
+		messages[i] = name + ": " + e[name].Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap exposes the individual failures to errors.Is and errors.As
+func (e QueryErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e))
+	for _, err := range e {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+// WithMaxConcurrentQueries limits how many queries one QueryAll call runs at
+// once
+func WithMaxConcurrentQueries(n int) ClientOption {
+	return func(c *HubHRMSClient) {
+		if n < 1 {
+			n = 1
+		}
+		c.maxConcurrentQueries = n
+	}
+}
+
+// QueryAll runs queries concurrently, each like Query, and returns the
+// responses of those that succeeded keyed by NamedQuery.Name. Unlike Batch,
+// one failed query does not fail the rest: failures holds the error of each
+// query that failed, and err is only set, wrapping failures, when none of
+// them succeeded.
+func (c *HubHRMSClient) QueryAll(ctx context.Context, queries []NamedQuery) (map[string]*GraphQLResponse, QueryErrors, error) {
+	results := make(map[string]*GraphQLResponse, len(queries))
+	failures := make(QueryErrors)
+	var mu sync.Mutex
+
+	var g errgroup.Group
+	g.SetLimit(c.maxConcurrentQueries)
+	for _, query := range queries {
+		g.Go(func() error {
+			resp, err := c.Query(ctx, query.Query, query.Variables)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				failures[query.Name] = err
+			} else {
+				results[query.Name] = resp
+			}
+			// Failures are collected rather than returned, so they do not
+			// stop the other queries
+			return nil
+		})
+	}
+	g.Wait()
+
+	if len(failures) > 0 && len(results) == 0 {
+		return results, failures, fmt.Errorf("all %d queries failed: %w", len(failures), failures)
+	}
+	return results, failures, nil
+}
+
+// startSpan starts a client span describing a GraphQL document
+func (c *HubHRMSClient) startSpan(ctx context.Context, name, document string) (context.Context, trace.Span) {
+	return c.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("graphql.operation", operationName(document)),
+			attribute.String("graphql.document", document),
+		),
+	)
+}
+
+// endSpan records err, if any, as the span's outcome and ends it
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// execute sends a single GraphQL request and logs its outcome
+func (c *HubHRMSClient) execute(ctx context.Context, query string, variables map[string]interface{}) (*GraphQLResponse, error) {
+	start := time.Now()
+	resp, err := c.send(ctx, query, variables)
+	duration := time.Since(start)
+	operation := operationName(query)
+
+	c.metrics.ObserveHubHRMSCall(operation, duration)
+
+	attrs := []slog.Attr{
+		slog.String("operation", operation),
+		slog.Float64("duration_ms", float64(duration.Microseconds())/1000),
+	}
+	level := slog.LevelInfo
+	switch {
+	case err != nil:
+		level = slog.LevelWarn
+		attrs = append(attrs, slog.String("error", err.Error()))
+	case len(resp.Errors) > 0:
+		level = slog.LevelWarn
+		messages := make([]string, len(resp.Errors))
+		for i, gqlErr := range resp.Errors {
+			messages[i] = gqlErr.Message
+		}
+		attrs = append(attrs, slog.Any("errors", messages))
+	}
+	c.logger.LogAttrs(ctx, level, "hubhrms call", attrs...)
+
+	return resp, err
+}
+
+// send performs the HTTP round trip for one GraphQL request
+func (c *HubHRMSClient) send(ctx context.Context, query string, variables map[string]interface{}) (*GraphQLResponse, error) {
 	reqBody := GraphQLRequest{
 		Query:     query,
 		Variables: variables,
 	}
 
-	jsonData, err := json.Marshal(reqBody)
+	var gqlResp GraphQLResponse
+	if err := c.post(ctx, reqBody, &gqlResp); err != nil {
+		return nil, err
+	}
+
+	return &gqlResp, nil
+}
+
+// post sends payload to Hub-HRMS as JSON and decodes the reply into out
+func (c *HubHRMSClient) post(ctx context.Context, payload, out interface{}) error {
+	jsonData, err := json.Marshal(payload)
 	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
+		return permanent(fmt.Errorf("failed to marshal request: %w", err))
 	}
 
 	req, err := http.NewRequestWithContext(ctx, "POST", c.url, bytes.NewBuffer(jsonData))
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return permanent(fmt.Errorf("failed to create request: %w", err))
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	if c.apiKey != "" {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
 	}
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+		return fmt.Errorf("failed to execute request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("Hub-HRMS returned status %d: %s", resp.StatusCode, string(body))
-	}
-
-	var gqlResp GraphQLResponse
-	if err := json.NewDecoder(resp.Body).Decode(&gqlResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
+		return &StatusError{StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	if len(gqlResp.Errors) > 0 {
-		log.Printf("GraphQL errors: %+v", gqlResp.Errors)
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return permanent(fmt.Errorf("failed to decode response: %w", err))
 	}
 
-	return &gqlResp, nil
+	return nil
 }
 
-// Mutate executes a GraphQL mutation
-func (c *HubHRMSClient) Mutate(ctx context.Context, mutation string, variables map[string]interface{}) (*GraphQLResponse, error) {
-	return c.Query(ctx, mutation, variables)
+var operationPattern = regexp.MustCompile(`^\s*(?:query|mutation|subscription)\s+(\w+)`)
+
+// operationName returns the name of the first operation in a document, or
+// "anonymous" when it has none
+func operationName(query string) string {
+	if match := operationPattern.FindStringSubmatch(query); match != nil {
+		return match[1]
+	}
+	return "anonymous"
 }
 
+// maxProxyBodySize caps the GraphQL request bodies ProxyHandler buffers
+const maxProxyBodySize = 1 << 20
+
 // ProxyHandler proxies GraphQL requests to Hub-HRMS
 func (c *HubHRMSClient) ProxyHandler(w http.ResponseWriter, r *http.Request) {
 	// Read request body
-	body, err := io.ReadAll(r.Body)
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxProxyBodySize))
 	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			http.Error(w, "Request body too large", http.StatusRequestEntityTooLarge)
+			return
+		}
 		http.Error(w, "Failed to read request body", http.StatusBadRequest)
 		return
 	}
 	defer r.Body.Close()
 
-	// Parse GraphQL request
-	var gqlReq GraphQLRequest
-	if err := json.Unmarshal(body, &gqlReq); err != nil {
+	// Parse GraphQL request, looking up persisted queries sent by hash
+	body, query, err := c.resolvePersistedQuery(body)
+	if errors.Is(err, ErrPersistedQueryOnly) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if err != nil {
 		http.Error(w, "Invalid GraphQL request", http.StatusBadRequest)
 		return
 	}
 
+	// Reject operations Hub-HRMS should never see. A hash only Hub-HRMS
+	// knows cannot be checked, so it is let through unless there is an
+	// allowlist to enforce.
+	if c.allowlist != nil {
+		if err := checkAllowlist(query, c.allowlist); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+	if query != "" && (c.maxDepth > 0 || c.maxComplexity > 0) {
+		if err := ValidateComplexity(query, c.maxDepth, c.maxComplexity); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
 	// Forward to Hub-HRMS
 	req, err := http.NewRequestWithContext(r.Context(), "POST", c.url, bytes.NewBuffer(body))
 	if err != nil {
@@ -134,6 +524,11 @@ func (c *HubHRMSClient) ProxyHandler(w http.ResponseWriter, r *http.Request) {
 		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", c.apiKey))
 	}
 
+	otel.GetTextMapPropagator().Inject(r.Context(), propagation.HeaderCarrier(req.Header))
+	if requestID := r.Header.Get(requestid.Header); requestID != "" {
+		req.Header.Set(requestid.Header, requestID)
+	}
+
 	// Copy user auth token from original request if present
 	if authHeader := r.Header.Get("Authorization"); authHeader != "" {
 		req.Header.Set("X-User-Token", authHeader)
@@ -142,7 +537,7 @@ func (c *HubHRMSClient) ProxyHandler(w http.ResponseWriter, r *http.Request) {
 	// Execute request
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		log.Printf("Error proxying to Hub-HRMS: %v", err)
+		c.logger.ErrorContext(r.Context(), "proxying to Hub-HRMS failed", "error", err)
 		http.Error(w, "Failed to execute request", http.StatusBadGateway)
 		return
 	}
@@ -161,7 +556,7 @@ func (c *HubHRMSClient) ProxyHandler(w http.ResponseWriter, r *http.Request) {
 
 	// Copy response body
 	if _, err := io.Copy(w, resp.Body); err != nil {
-		log.Printf("Error copying response: %v", err)
+		c.logger.ErrorContext(r.Context(), "copying Hub-HRMS response failed", "error", err)
 	}
 }
 
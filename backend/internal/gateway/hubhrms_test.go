@@ -0,0 +1,342 @@
+package gateway
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"hr-recruiting/internal/requestid"
+)
+
+func TestHubHRMSClient_Tracing(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	var traceparents []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		if len(traceparents) == 1 {
+			w.Write([]byte(`{"data":{"jobs":[]}}`))
+			return
+		}
+		w.Write([]byte(`{"errors":[{"message":"job not found"}]}`))
+	}))
+	defer upstream.Close()
+
+	client := NewHubHRMSClient(upstream.URL, "", WithTracer(tp))
+	defer client.Close()
+
+	if _, err := client.Query(context.Background(), "query GetJobs { jobs { id } }", nil); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if _, err := client.Mutate(context.Background(), "mutation CloseJob { closeJob(id: 1) { id } }", nil); err == nil {
+		t.Fatal("Mutate() error = nil, want the GraphQL error")
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	for i, want := range []struct {
+		name      string
+		operation string
+		status    codes.Code
+	}{
+		{name: "hubhrms.query", operation: "GetJobs", status: codes.Unset},
+		{name: "hubhrms.mutate", operation: "CloseJob", status: codes.Error},
+	} {
+		span := spans[i]
+		operation := ""
+		for _, attr := range span.Attributes() {
+			if attr.Key == "graphql.operation" {
+				operation = attr.Value.AsString()
+			}
+		}
+		if span.Name() != want.name || span.SpanKind() != trace.SpanKindClient || operation != want.operation || span.Status().Code != want.status {
+			t.Errorf("span %d = %q %v operation=%q status=%v, want %q client operation=%q status=%v",
+				i, span.Name(), span.SpanKind(), operation, span.Status().Code, want.name, want.operation, want.status)
+		}
+
+		// Hub-HRMS continues the trace from the client span
+		sc := span.SpanContext()
+		if want := "00-" + sc.TraceID().String() + "-" + sc.SpanID().String() + "-01"; traceparents[i] != want {
+			t.Errorf("request %d traceparent = %q, want %q", i, traceparents[i], want)
+		}
+	}
+}
+
+func TestHubHRMSClient_ForwardsRequestID(t *testing.T) {
+	var got []string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get(requestid.Header))
+		w.Write([]byte(`{"data":{"jobs":[]}}`))
+	}))
+	defer upstream.Close()
+
+	client := NewHubHRMSClient(upstream.URL, "")
+	defer client.Close()
+
+	ctx := requestid.NewContext(context.Background(), "req-123")
+	if _, err := client.Query(ctx, "query GetJobs { jobs { id } }", nil); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if _, err := client.Query(context.Background(), "query GetJobs { jobs { id } }", nil); err != nil {
+		t.Fatalf("Query() error = %v", err)
+	}
+	if len(got) != 2 || got[0] != "req-123" || got[1] != "" {
+		t.Fatalf("%s headers = %q, want the context's ID and then none", requestid.Header, got)
+	}
+}
+
+// debugLogs collects the records of a debug-level JSON logger
+func debugLogs() (*slog.Logger, func() []map[string]interface{}) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	return logger, func() []map[string]interface{} {
+		var records []map[string]interface{}
+		decoder := json.NewDecoder(&buf)
+		for decoder.More() {
+			var record map[string]interface{}
+			if err := decoder.Decode(&record); err != nil {
+				break
+			}
+			records = append(records, record)
+		}
+		return records
+	}
+}
+
+// debugRecord returns the first record with message msg
+func debugRecord(records []map[string]interface{}, msg string) map[string]interface{} {
+	for _, record := range records {
+		if record["msg"] == msg {
+			return record
+		}
+	}
+	return nil
+}
+
+func TestHubHRMSClient_DebugLog(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Variables["large"] == true {
+			w.Write([]byte(`{"data":{"jobs":[{"id":"job-1","description":"` + strings.Repeat("x", 2*debugLogLimit) + `"}]}}`))
+			return
+		}
+		w.Write([]byte(`{"data":{"jobs":[{"id":"job-1"}]}}`))
+	}))
+	defer upstream.Close()
+
+	t.Run("enabled", func(t *testing.T) {
+		logger, records := debugLogs()
+		client := NewHubHRMSClient(upstream.URL, "", WithLogger(logger), WithDebugLog(true))
+		defer client.Close()
+
+		resp, err := client.Query(context.Background(), "query GetJobs { jobs { id } }", map[string]interface{}{"status": "PUBLISHED"})
+		if err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		jobs, _ := resp.Data.(map[string]interface{})["jobs"].([]interface{})
+		if len(jobs) != 1 {
+			t.Fatalf("data = %v, want the job decoded after logging", resp.Data)
+		}
+
+		logged := records()
+		request := debugRecord(logged, "hubhrms request")
+		if request == nil || request["level"] != "DEBUG" || request["method"] != http.MethodPost ||
+			!strings.Contains(request["body"].(string), `"status":"PUBLISHED"`) {
+			t.Fatalf("request record = %v, want the request body at debug level", request)
+		}
+		response := debugRecord(logged, "hubhrms response")
+		if response == nil || response["status"] != float64(http.StatusOK) || response["body"] != `{"data":{"jobs":[{"id":"job-1"}]}}` {
+			t.Fatalf("response record = %v, want the status and response body", response)
+		}
+	})
+
+	t.Run("large bodies truncated", func(t *testing.T) {
+		logger, records := debugLogs()
+		client := NewHubHRMSClient(upstream.URL, "", WithLogger(logger), WithDebugLog(true))
+		defer client.Close()
+
+		resp, err := client.Query(context.Background(), "query GetJobs { jobs { id description } }", map[string]interface{}{"large": true})
+		if err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		jobs, _ := resp.Data.(map[string]interface{})["jobs"].([]interface{})
+		if description, _ := jobs[0].(map[string]interface{})["description"].(string); len(description) != 2*debugLogLimit {
+			t.Fatalf("description is %d bytes, want the full %d", len(description), 2*debugLogLimit)
+		}
+
+		body, _ := debugRecord(records(), "hubhrms response")["body"].(string)
+		if len(body) != debugLogLimit+len("...(truncated)") || !strings.HasSuffix(body, "...(truncated)") {
+			t.Fatalf("logged body is %d bytes, want %d and marked truncated", len(body), debugLogLimit)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		logger, records := debugLogs()
+		client := NewHubHRMSClient(upstream.URL, "", WithLogger(logger))
+		defer client.Close()
+
+		if _, err := client.Query(context.Background(), "query GetJobs { jobs { id } }", nil); err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+		if record := debugRecord(records(), "hubhrms response"); record != nil {
+			t.Fatalf("response logged without debug logging: %v", record)
+		}
+	})
+}
+
+// newQueryAllUpstream answers queries whose operation is in failing with a
+// 500 and the rest with an empty jobs list
+func newQueryAllUpstream(t *testing.T, failing ...string) *httptest.Server {
+	t.Helper()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query string `json:"query"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if slices.Contains(failing, operationName(body.Query)) {
+			http.Error(w, "upstream failure", http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"data":{"jobs":[]}}`))
+	}))
+	t.Cleanup(upstream.Close)
+	return upstream
+}
+
+func TestHubHRMSClient_QueryAll(t *testing.T) {
+	queries := []NamedQuery{
+		{Name: "metrics", Query: "query GetMetrics { jobs { id } }"},
+		{Name: "pipeline", Query: "query GetPipeline { jobs { id } }"},
+		{Name: "trends", Query: "query GetTrends { jobs { id } }"},
+	}
+
+	tests := []struct {
+		name        string
+		failing     []string
+		wantResults []string
+		wantFailed  []string
+		wantErr     bool
+	}{
+		{name: "all succeed", wantResults: []string{"metrics", "pipeline", "trends"}},
+		{
+			name:        "partial failure",
+			failing:     []string{"GetPipeline"},
+			wantResults: []string{"metrics", "trends"},
+			wantFailed:  []string{"pipeline"},
+		},
+		{
+			name:       "all fail",
+			failing:    []string{"GetMetrics", "GetPipeline", "GetTrends"},
+			wantFailed: []string{"metrics", "pipeline", "trends"},
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := NewHubHRMSClient(newQueryAllUpstream(t, tt.failing...).URL, "")
+			defer client.Close()
+
+			results, failures, err := client.QueryAll(context.Background(), queries)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("QueryAll() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := slices.Sorted(maps.Keys(results)); !slices.Equal(got, tt.wantResults) {
+				t.Fatalf("results = %v, want %v", got, tt.wantResults)
+			}
+			if got := slices.Sorted(maps.Keys(failures)); !slices.Equal(got, tt.wantFailed) {
+				t.Fatalf("failures = %v, want %v", got, tt.wantFailed)
+			}
+
+			var status *StatusError
+			for name, queryErr := range failures {
+				if !errors.As(queryErr, &status) || status.StatusCode != http.StatusInternalServerError {
+					t.Fatalf("failure %s = %v, want the upstream status", name, queryErr)
+				}
+			}
+			if tt.wantErr && !errors.As(err, &status) {
+				t.Fatalf("QueryAll() error = %v does not expose the query errors", err)
+			}
+		})
+	}
+}
+
+func TestHubHRMSClient_QueryAllLimit(t *testing.T) {
+	upstream, peak := newSlowUpstream(t, 20*time.Millisecond)
+	client := NewHubHRMSClient(upstream.URL, "", WithMaxConcurrentQueries(3))
+	defer client.Close()
+
+	queries := make([]NamedQuery, 10)
+	for i := range queries {
+		queries[i] = NamedQuery{Name: fmt.Sprintf("q%d", i), Query: "query GetJobs { jobs { id } }"}
+	}
+	results, failures, err := client.QueryAll(context.Background(), queries)
+	if err != nil || len(failures) != 0 || len(results) != len(queries) {
+		t.Fatalf("QueryAll() = %d results, failures %v, error %v", len(results), failures, err)
+	}
+	if got := peak.Load(); got != 3 {
+		t.Fatalf("peak concurrent queries = %d, want 3", got)
+	}
+}
+
+// benchmarkQueries are the dashboard's queries against an upstream that
+// takes 5ms per query
+func benchmarkQueries(b *testing.B, n int) (*HubHRMSClient, []NamedQuery) {
+	upstream, _ := newSlowUpstream(b, 5*time.Millisecond)
+	client := NewHubHRMSClient(upstream.URL, "", WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	b.Cleanup(client.Close)
+
+	queries := make([]NamedQuery, n)
+	for i := range queries {
+		queries[i] = NamedQuery{Name: fmt.Sprintf("q%d", i), Query: "query GetJobs { jobs { id } }"}
+	}
+	return client, queries
+}
+
+func BenchmarkQueries_Sequential(b *testing.B) {
+	client, queries := benchmarkQueries(b, 4)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		for _, query := range queries {
+			if _, err := client.Query(ctx, query.Query, query.Variables); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkQueries_QueryAll(b *testing.B) {
+	client, queries := benchmarkQueries(b, 4)
+	ctx := context.Background()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := client.QueryAll(ctx, queries); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
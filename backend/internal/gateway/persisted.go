@@ -0,0 +1,217 @@
+package gateway
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	// ErrPersistedQueryHashMismatch is returned when a query is registered
+	// under a hash that is not its SHA-256
+	ErrPersistedQueryHashMismatch = errors.New("persisted query hash does not match query")
+	// ErrPersistedQueryOnly is returned for inline queries when only
+	// persisted queries are accepted
+	ErrPersistedQueryOnly = errors.New("only persisted queries are accepted")
+)
+
+// PersistedQuery is a query registered under the hex SHA-256 of its text
+type PersistedQuery struct {
+	Hash  string `json:"sha256Hash"`
+	Query string `json:"query"`
+}
+
+// PersistedQueryStore holds persisted queries in memory, indexed by hash,
+// and writes every change back to its JSON file
+type PersistedQueryStore struct {
+	mu      sync.RWMutex
+	path    string
+	queries map[string]string
+}
+
+// NewPersistedQueryStore loads the queries in path, a JSON object mapping
+// hashes to queries. An empty path gives a store that only lives in memory;
+// a missing file is created on the first change.
+func NewPersistedQueryStore(path string) (*PersistedQueryStore, error) {
+	s := &PersistedQueryStore{path: path, queries: make(map[string]string)}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read persisted queries: %w", err)
+	}
+
+	var queries map[string]string
+	if err := json.Unmarshal(data, &queries); err != nil {
+		return nil, fmt.Errorf("invalid persisted queries file %s: %w", path, err)
+	}
+	for hash, query := range queries {
+		if hashPersistedQuery(query) != strings.ToLower(hash) {
+			return nil, fmt.Errorf("invalid persisted queries file %s: %w: %s", path, ErrPersistedQueryHashMismatch, hash)
+		}
+		s.queries[strings.ToLower(hash)] = query
+	}
+	return s, nil
+}
+
+// Get returns the query registered under hash
+func (s *PersistedQueryStore) Get(hash string) (string, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	query, ok := s.queries[strings.ToLower(hash)]
+	return query, ok
+}
+
+// List returns every persisted query ordered by hash
+func (s *PersistedQueryStore) List() []PersistedQuery {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	queries := make([]PersistedQuery, 0, len(s.queries))
+	for hash, query := range s.queries {
+		queries = append(queries, PersistedQuery{Hash: hash, Query: query})
+	}
+	sort.Slice(queries, func(i, j int) bool { return queries[i].Hash < queries[j].Hash })
+	return queries
+}
+
+// Register persists query and returns its hash. When hash is given it must
+// be the query's SHA-256, as clients compute it.
+func (s *PersistedQueryStore) Register(query, hash string) (string, error) {
+	doc, err := parseDocument(query)
+	if err != nil {
+		return "", err
+	}
+	if len(doc.operations) == 0 {
+		return "", fmt.Errorf("%w: no operation", ErrInvalidQuery)
+	}
+	sum := hashPersistedQuery(query)
+	if hash != "" && strings.ToLower(hash) != sum {
+		return "", ErrPersistedQueryHashMismatch
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if existing, ok := s.queries[sum]; ok && existing == query {
+		return sum, nil
+	}
+	s.queries[sum] = query
+	if err := s.save(); err != nil {
+		delete(s.queries, sum)
+		return "", err
+	}
+	return sum, nil
+}
+
+// save writes the queries to the store's file. Callers hold s.mu.
+func (s *PersistedQueryStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	data, err := json.MarshalIndent(s.queries, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".persisted-queries-*")
+	if err != nil {
+		return fmt.Errorf("failed to save persisted queries: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save persisted queries: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save persisted queries: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save persisted queries: %w", err)
+	}
+	return nil
+}
+
+func hashPersistedQuery(query string) string {
+	sum := sha256.Sum256([]byte(query))
+	return hex.EncodeToString(sum[:])
+}
+
+// WithPersistedQueries resolves Apollo persisted query hashes sent to the
+// proxy from store. With only set, inline queries are rejected and clients
+// must send a hash.
+func WithPersistedQueries(store *PersistedQueryStore, only bool) ClientOption {
+	return func(c *HubHRMSClient) {
+		c.persisted = store
+		c.persistedOnly = only
+	}
+}
+
+// persistedQueryRequest is the part of a proxied request body used by the
+// Apollo persisted query protocol
+type persistedQueryRequest struct {
+	Query      string `json:"query"`
+	Extensions struct {
+		PersistedQuery *struct {
+			Version    int    `json:"version"`
+			Sha256Hash string `json:"sha256Hash"`
+		} `json:"persistedQuery"`
+	} `json:"extensions"`
+}
+
+// resolvePersistedQuery fills in the query text of a request that only
+// carries a persisted query hash, returning the body to forward and the
+// query in it. A hash the store does not know is forwarded as is, with an
+// empty query, as Hub-HRMS may have it registered.
+func (c *HubHRMSClient) resolvePersistedQuery(body []byte) ([]byte, string, error) {
+	var req persistedQueryRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, "", err
+	}
+
+	ref := req.Extensions.PersistedQuery
+	if req.Query != "" {
+		if c.persistedOnly {
+			return nil, "", ErrPersistedQueryOnly
+		}
+		return body, req.Query, nil
+	}
+	if ref == nil || ref.Sha256Hash == "" || c.persisted == nil {
+		return body, "", nil
+	}
+
+	query, ok := c.persisted.Get(ref.Sha256Hash)
+	if !ok {
+		return body, "", nil
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return nil, "", err
+	}
+	encoded, err := json.Marshal(query)
+	if err != nil {
+		return nil, "", err
+	}
+	fields["query"] = encoded
+	body, err = json.Marshal(fields)
+	if err != nil {
+		return nil, "", err
+	}
+	return body, query, nil
+}
@@ -0,0 +1,194 @@
+package gateway
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testPersistedQuery = `query GetJobs { jobs { id title } }`
+
+func TestPersistedQueryStore(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "persisted-queries.json")
+	store, err := NewPersistedQueryStore(path)
+	if err != nil {
+		t.Fatalf("NewPersistedQueryStore() error = %v", err)
+	}
+	hash := hashPersistedQuery(testPersistedQuery)
+
+	t.Run("register", func(t *testing.T) {
+		got, err := store.Register(testPersistedQuery, "")
+		if err != nil || got != hash {
+			t.Fatalf("Register() = %q, %v, want %q", got, err, hash)
+		}
+		// Registering again with the client's upper-case hash is a no-op
+		if got, err := store.Register(testPersistedQuery, strings.ToUpper(hash)); err != nil || got != hash {
+			t.Fatalf("Register() again = %q, %v, want %q", got, err, hash)
+		}
+		if query, ok := store.Get(strings.ToUpper(hash)); !ok || query != testPersistedQuery {
+			t.Fatalf("Get() = %q, %v, want the query", query, ok)
+		}
+		if list := store.List(); len(list) != 1 || list[0] != (PersistedQuery{Hash: hash, Query: testPersistedQuery}) {
+			t.Fatalf("List() = %v", list)
+		}
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		if _, err := store.Register(testPersistedQuery, hashPersistedQuery("query Other { jobs { id } }")); !errors.Is(err, ErrPersistedQueryHashMismatch) {
+			t.Fatalf("Register() with the wrong hash error = %v, want ErrPersistedQueryHashMismatch", err)
+		}
+		if _, err := store.Register("query {", ""); err == nil {
+			t.Fatal("Register() accepted a query that does not parse")
+		}
+		if _, err := store.Register("fragment JobFields on Job { id }", ""); !errors.Is(err, ErrInvalidQuery) {
+			t.Fatalf("Register() without an operation error = %v, want ErrInvalidQuery", err)
+		}
+		if len(store.List()) != 1 {
+			t.Fatalf("List() = %v, want only the first query", store.List())
+		}
+	})
+
+	t.Run("reloaded from file", func(t *testing.T) {
+		reloaded, err := NewPersistedQueryStore(path)
+		if err != nil {
+			t.Fatalf("NewPersistedQueryStore() error = %v", err)
+		}
+		if query, ok := reloaded.Get(hash); !ok || query != testPersistedQuery {
+			t.Fatalf("Get() after reload = %q, %v, want the query", query, ok)
+		}
+	})
+
+	t.Run("edited file", func(t *testing.T) {
+		edited := filepath.Join(t.TempDir(), "persisted-queries.json")
+		data, _ := json.Marshal(map[string]string{hash: "query GetJobs { jobs { id title salary } }"})
+		if err := os.WriteFile(edited, data, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewPersistedQueryStore(edited); !errors.Is(err, ErrPersistedQueryHashMismatch) {
+			t.Fatalf("NewPersistedQueryStore() error = %v, want ErrPersistedQueryHashMismatch", err)
+		}
+	})
+
+	t.Run("in memory", func(t *testing.T) {
+		memory, err := NewPersistedQueryStore("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := memory.Register(testPersistedQuery, ""); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	})
+}
+
+// persistedProxy returns a client proxying to a fake Hub-HRMS, and the
+// bodies Hub-HRMS was sent
+func persistedProxy(t *testing.T, only bool) (*HubHRMSClient, func() []map[string]interface{}) {
+	t.Helper()
+	var bodies []map[string]interface{}
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		var body map[string]interface{}
+		json.Unmarshal(data, &body)
+		bodies = append(bodies, body)
+		w.Write([]byte(`{"data":{"jobs":[]}}`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	store, err := NewPersistedQueryStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Register(testPersistedQuery, ""); err != nil {
+		t.Fatal(err)
+	}
+	client := NewHubHRMSClient(upstream.URL, "", WithPersistedQueries(store, only))
+	t.Cleanup(client.Close)
+	return client, func() []map[string]interface{} { return bodies }
+}
+
+// proxy sends body through the client's GraphQL proxy
+func proxy(client *HubHRMSClient, body string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	client.ProxyHandler(rec, httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(body)))
+	return rec
+}
+
+// persistedBody is a request sending only the hash of a persisted query
+func persistedBody(hash string) string {
+	return `{"operationName":"GetJobs","variables":{"status":"OPEN"},` +
+		`"extensions":{"persistedQuery":{"version":1,"sha256Hash":"` + hash + `"}}}`
+}
+
+func TestProxyHandler_PersistedQueries(t *testing.T) {
+	hash := hashPersistedQuery(testPersistedQuery)
+
+	t.Run("hash found", func(t *testing.T) {
+		client, sent := persistedProxy(t, false)
+		if rec := proxy(client, persistedBody(hash)); rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		body := sent()[0]
+		if body["query"] != testPersistedQuery || body["operationName"] != "GetJobs" {
+			t.Fatalf("forwarded %v, want the query filled in", body)
+		}
+		if variables, _ := body["variables"].(map[string]interface{}); variables["status"] != "OPEN" || body["extensions"] == nil {
+			t.Fatalf("forwarded %v, want the variables and extensions kept", body)
+		}
+	})
+
+	t.Run("hash missing", func(t *testing.T) {
+		client, sent := persistedProxy(t, false)
+		unknown := hashPersistedQuery("query Unknown { jobs { id } }")
+		if rec := proxy(client, persistedBody(unknown)); rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		body := sent()[0]
+		if _, ok := body["query"]; ok {
+			t.Fatalf("forwarded %v, want the hash alone for Hub-HRMS to resolve", body)
+		}
+		ref, _ := body["extensions"].(map[string]interface{})["persistedQuery"].(map[string]interface{})
+		if ref["sha256Hash"] != unknown {
+			t.Fatalf("forwarded %v, want the unknown hash", body)
+		}
+	})
+
+	t.Run("inline query", func(t *testing.T) {
+		client, sent := persistedProxy(t, false)
+		if rec := proxy(client, `{"query":"query GetJob { job(id: 1) { id } }"}`); rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		if len(sent()) != 1 {
+			t.Fatal("inline query was not forwarded")
+		}
+	})
+
+	t.Run("locked down", func(t *testing.T) {
+		client, sent := persistedProxy(t, true)
+
+		rec := proxy(client, `{"query":"query GetJob { job(id: 1) { id } }"}`)
+		if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), ErrPersistedQueryOnly.Error()) {
+			t.Fatalf("inline query = %d %q, want 400", rec.Code, rec.Body)
+		}
+		// Inline text alongside a known hash is still inline
+		rec = proxy(client, `{"query":"`+testPersistedQuery+`","extensions":{"persistedQuery":{"version":1,"sha256Hash":"`+hash+`"}}}`)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("inline query with a hash = %d, want 400", rec.Code)
+		}
+		if len(sent()) != 0 {
+			t.Fatalf("rejected queries were forwarded: %v", sent())
+		}
+
+		if rec := proxy(client, persistedBody(hash)); rec.Code != http.StatusOK {
+			t.Fatalf("persisted query = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		if len(sent()) != 1 || sent()[0]["query"] != testPersistedQuery {
+			t.Fatalf("forwarded %v, want the persisted query", sent())
+		}
+	})
+}
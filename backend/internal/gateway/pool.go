@@ -0,0 +1,211 @@
+package gateway
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ClientOption configures a HubHRMSClient
+type ClientOption func(*HubHRMSClient)
+
+// WithMaxConnsPerHost caps the number of connections to Hub-HRMS so a single
+// slow endpoint cannot exhaust the pool
+func WithMaxConnsPerHost(n int) ClientOption {
+	return func(c *HubHRMSClient) {
+		c.transport.MaxConnsPerHost = n
+	}
+}
+
+// WithIdleConnCheck sets how often idle connections are closed so stale
+// keep-alive connections are not reused after Hub-HRMS drops them
+func WithIdleConnCheck(interval time.Duration) ClientOption {
+	return func(c *HubHRMSClient) {
+		c.idleCheckInterval = interval
+	}
+}
+
+// HubHRMSClientOptions sizes the client's connection pool. Zero fields use
+// the defaults: 50 connections per host, 100 idle connections, 10 per host,
+// a 90 second idle timeout, a 30 second request timeout and a 10 second TLS
+// handshake timeout.
+type HubHRMSClientOptions struct {
+	MaxConnsPerHost     int
+	MaxIdleConns        int
+	MaxIdleConnsPerHost int
+	IdleConnTimeout     time.Duration
+	RequestTimeout      time.Duration
+	TLSHandshakeTimeout time.Duration
+}
+
+func (o HubHRMSClientOptions) withDefaults() HubHRMSClientOptions {
+	if o.MaxConnsPerHost <= 0 {
+		o.MaxConnsPerHost = 50
+	}
+	if o.MaxIdleConns <= 0 {
+		o.MaxIdleConns = 100
+	}
+	if o.MaxIdleConnsPerHost <= 0 {
+		o.MaxIdleConnsPerHost = 10
+	}
+	if o.IdleConnTimeout <= 0 {
+		o.IdleConnTimeout = 90 * time.Second
+	}
+	if o.RequestTimeout <= 0 {
+		o.RequestTimeout = 30 * time.Second
+	}
+	if o.TLSHandshakeTimeout <= 0 {
+		o.TLSHandshakeTimeout = 10 * time.Second
+	}
+	return o
+}
+
+// TransportStats describes how the client's connections are being used
+type TransportStats struct {
+	OpenConns           int64 `json:"openConns"`
+	ActiveConns         int64 `json:"activeConns"`
+	IdleConns           int64 `json:"idleConns"`
+	TotalRequests       int64 `json:"totalRequests"`
+	MaxIdleConns        int   `json:"maxIdleConns"`
+	MaxIdleConnsPerHost int   `json:"maxIdleConnsPerHost"`
+	MaxConnsPerHost     int   `json:"maxConnsPerHost"`
+}
+
+// PoolStats is a snapshot of the Hub-HRMS connection pool
+type PoolStats struct {
+	OpenConns  int64 `json:"openConns"`
+	TotalDials int64 `json:"totalDials"`
+	DialErrors int64 `json:"dialErrors"`
+	IdleSweeps int64 `json:"idleSweeps"`
+}
+
+// poolTracker counts connections dialed by the transport
+type poolTracker struct {
+	dialer     *net.Dialer
+	openConns  atomic.Int64
+	totalDials atomic.Int64
+	dialErrors atomic.Int64
+	idleSweeps atomic.Int64
+
+	activeConns   atomic.Int64
+	totalRequests atomic.Int64
+}
+
+// dialContext dials with a per-connection timeout and tracks the connection
+func (p *poolTracker) dialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	conn, err := p.dialer.DialContext(ctx, network, addr)
+	if err != nil {
+		p.dialErrors.Add(1)
+		return nil, err
+	}
+
+	p.totalDials.Add(1)
+	p.openConns.Add(1)
+	return &trackedConn{Conn: conn, tracker: p}, nil
+}
+
+func (p *poolTracker) stats() PoolStats {
+	return PoolStats{
+		OpenConns:  p.openConns.Load(),
+		TotalDials: p.totalDials.Load(),
+		DialErrors: p.dialErrors.Load(),
+		IdleSweeps: p.idleSweeps.Load(),
+	}
+}
+
+// trackedConn decrements the open connection count exactly once on close
+type trackedConn struct {
+	net.Conn
+	tracker *poolTracker
+	once    sync.Once
+}
+
+func (c *trackedConn) Close() error {
+	c.once.Do(func() {
+		c.tracker.openConns.Add(-1)
+	})
+	return c.Conn.Close()
+}
+
+// activeTransport counts requests in flight. Each holds a connection from
+// when it is sent until its response body is closed.
+type activeTransport struct {
+	base http.RoundTripper
+	pool *poolTracker
+}
+
+func (t *activeTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.pool.totalRequests.Add(1)
+	t.pool.activeConns.Add(1)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		t.pool.activeConns.Add(-1)
+		return nil, err
+	}
+	resp.Body = &activeBody{ReadCloser: resp.Body, pool: t.pool}
+	return resp, nil
+}
+
+// activeBody releases its request's connection exactly once on close
+type activeBody struct {
+	io.ReadCloser
+	pool *poolTracker
+	once sync.Once
+}
+
+func (b *activeBody) Close() error {
+	b.once.Do(func() {
+		b.pool.activeConns.Add(-1)
+	})
+	return b.ReadCloser.Close()
+}
+
+// sweepIdleConnections closes idle connections on every tick until stopped
+func (c *HubHRMSClient) sweepIdleConnections() {
+	ticker := time.NewTicker(c.idleCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			c.transport.CloseIdleConnections()
+			c.pool.idleSweeps.Add(1)
+		case <-c.stop:
+			return
+		}
+	}
+}
+
+// PoolStats returns a snapshot of the connection pool
+func (c *HubHRMSClient) PoolStats() PoolStats {
+	return c.pool.stats()
+}
+
+// ConnectionStats reports open, active and idle connections along with the
+// pool limits
+func (c *HubHRMSClient) ConnectionStats() TransportStats {
+	open := c.pool.openConns.Load()
+	active := c.pool.activeConns.Load()
+	return TransportStats{
+		OpenConns:           open,
+		ActiveConns:         active,
+		IdleConns:           max(open-active, 0),
+		TotalRequests:       c.pool.totalRequests.Load(),
+		MaxIdleConns:        c.transport.MaxIdleConns,
+		MaxIdleConnsPerHost: c.transport.MaxIdleConnsPerHost,
+		MaxConnsPerHost:     c.transport.MaxConnsPerHost,
+	}
+}
+
+// Close stops the idle connection sweeper and closes idle connections
+func (c *HubHRMSClient) Close() {
+	c.stopOnce.Do(func() {
+		close(c.stop)
+	})
+	c.transport.CloseIdleConnections()
+}
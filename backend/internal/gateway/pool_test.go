@@ -0,0 +1,175 @@
+package gateway
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newSlowUpstream starts a Hub-HRMS stand-in that holds each request for
+// delay and records the most requests it saw in flight at once
+func newSlowUpstream(t testing.TB, delay time.Duration) (*httptest.Server, *atomic.Int64) {
+	t.Helper()
+	var inFlight, peak atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			seen := peak.Load()
+			if n <= seen || peak.CompareAndSwap(seen, n) {
+				break
+			}
+		}
+		time.Sleep(delay)
+		w.Write([]byte(`{"data":{"jobs":[]}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &peak
+}
+
+func TestHubHRMSClientOptions_MaxConnsPerHost(t *testing.T) {
+	upstream, peak := newSlowUpstream(t, 20*time.Millisecond)
+	client := NewHubHRMSClientWithOptions(upstream.URL, "", HubHRMSClientOptions{MaxConnsPerHost: 2})
+	defer client.Close()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Query(context.Background(), "query GetJobs { jobs { id } }", nil); err != nil {
+				t.Errorf("Query() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := peak.Load(); got != 2 {
+		t.Fatalf("upstream saw %d requests at once, want the pool's 2", got)
+	}
+	if dials := client.PoolStats().TotalDials; dials > 2 {
+		t.Fatalf("client dialed %d connections, want at most 2", dials)
+	}
+	if got := client.ConnectionStats().MaxConnsPerHost; got != 2 {
+		t.Fatalf("ConnectionStats().MaxConnsPerHost = %d, want 2", got)
+	}
+}
+
+func TestHubHRMSClientOptions_Defaults(t *testing.T) {
+	client := NewHubHRMSClient("http://hub.invalid/graphql", "")
+	defer client.Close()
+
+	stats := client.ConnectionStats()
+	if stats.MaxConnsPerHost != 50 || stats.MaxIdleConns != 100 || stats.MaxIdleConnsPerHost != 10 {
+		t.Fatalf("ConnectionStats() = %+v, want the default pool limits", stats)
+	}
+
+	client = NewHubHRMSClientWithOptions("http://hub.invalid/graphql", "", HubHRMSClientOptions{MaxConnsPerHost: 5}, WithMaxConnsPerHost(8))
+	defer client.Close()
+	if got := client.ConnectionStats().MaxConnsPerHost; got != 8 {
+		t.Fatalf("MaxConnsPerHost = %d, want WithMaxConnsPerHost to override the options", got)
+	}
+}
+
+// waitFor polls cond until it holds or a second has passed
+func waitFor(t *testing.T, cond func() bool) bool {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			return false
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	return true
+}
+
+func TestHubHRMSClient_PoolStats(t *testing.T) {
+	upstream, _ := newSlowUpstream(t, 0)
+	client := NewHubHRMSClient(upstream.URL, "", WithIdleConnCheck(20*time.Millisecond))
+	defer client.Close()
+
+	for i := 0; i < 3; i++ {
+		if _, err := client.Query(context.Background(), "query GetJobs { jobs { id } }", nil); err != nil {
+			t.Fatalf("Query() error = %v", err)
+		}
+	}
+
+	// Sequential requests reuse one keep-alive connection
+	if stats := client.PoolStats(); stats.TotalDials != 1 || stats.DialErrors != 0 {
+		t.Fatalf("PoolStats() = %+v, want one dial", stats)
+	}
+	if stats := client.ConnectionStats(); stats.TotalRequests != 3 || stats.ActiveConns != 0 {
+		t.Fatalf("ConnectionStats() = %+v, want 3 finished requests", stats)
+	}
+
+	// The sweeper closes the idle connection
+	if !waitFor(t, func() bool { return client.PoolStats().OpenConns == 0 && client.PoolStats().IdleSweeps > 0 }) {
+		t.Fatalf("PoolStats() = %+v, want the idle connection swept", client.PoolStats())
+	}
+	if stats := client.ConnectionStats(); stats.IdleConns != 0 {
+		t.Fatalf("ConnectionStats() = %+v after the sweep", stats)
+	}
+
+	// Once closed, the sweeper stops
+	client.Close()
+	client.Close()
+	time.Sleep(10 * time.Millisecond) // let a sweep already under way finish
+	sweeps := client.PoolStats().IdleSweeps
+	time.Sleep(60 * time.Millisecond)
+	if got := client.PoolStats().IdleSweeps; got != sweeps {
+		t.Fatalf("swept %d more times after Close", got-sweeps)
+	}
+}
+
+func TestHubHRMSClient_PoolStats_DialErrors(t *testing.T) {
+	upstream, _ := newSlowUpstream(t, 0)
+	addr := upstream.URL
+	upstream.Close()
+
+	client := NewHubHRMSClient(addr, "", WithIdleConnCheck(0))
+	defer client.Close()
+	if _, err := client.Query(context.Background(), "query GetJobs { jobs { id } }", nil); err == nil {
+		t.Fatal("Query() to a closed server succeeded")
+	}
+	if stats := client.PoolStats(); stats.DialErrors != 1 || stats.TotalDials != 0 || stats.OpenConns != 0 {
+		t.Fatalf("PoolStats() = %+v, want one dial error", stats)
+	}
+	if stats := client.ConnectionStats(); stats.ActiveConns != 0 {
+		t.Fatalf("ConnectionStats() = %+v, want the failed request released", stats)
+	}
+}
+
+// BenchmarkHubHRMSClient_Query measures query throughput against a 1ms
+// upstream as the per-host connection cap grows, reporting how many
+// connections each size dialed
+func BenchmarkHubHRMSClient_Query(b *testing.B) {
+	for _, maxConns := range []int{1, 10, 50} {
+		b.Run(fmt.Sprintf("MaxConnsPerHost=%d", maxConns), func(b *testing.B) {
+			upstream, _ := newSlowUpstream(b, time.Millisecond)
+			client := NewHubHRMSClientWithOptions(upstream.URL, "", HubHRMSClientOptions{
+				MaxConnsPerHost:     maxConns,
+				MaxIdleConnsPerHost: maxConns,
+			}, WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+			defer client.Close()
+
+			b.SetParallelism(8)
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					if _, err := client.Query(context.Background(), "query GetJobs { jobs { id } }", nil); err != nil {
+						b.Error(err)
+					}
+				}
+			})
+			b.ReportMetric(float64(client.PoolStats().TotalDials), "dials")
+		})
+	}
+}
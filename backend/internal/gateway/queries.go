@@ -38,10 +38,135 @@ const (
 		}
 	`
 
+	// GetJobsPageQuery pages through jobs by keyset cursor. "after" is the
+	// "{createdAt}:{id}" position of the last job on the previous page.
+	GetJobsPageQuery = `
+		query GetJobsPage($filters: JobFilters, $first: Int, $after: ID) {
+			jobs(filters: $filters, first: $first, after: $after) {
+				id
+				title
+				department
+				location
+				employmentType
+				experienceLevel
+				salaryRange {
+					min
+					max
+					currency
+				}
+				description
+				requirements
+				responsibilities
+				benefits
+				skills
+				status
+				postedDate
+				closingDate
+				applicationCount
+				viewCount
+				remoteWork
+				urgentHiring
+				createdBy {
+					id
+					name
+				}
+				createdAt
+				updatedAt
+			}
+		}
+	`
+
+	// GetSitemapJobsQuery lists just enough of each job to link to it
+	GetSitemapJobsQuery = `
+		query GetSitemapJobs($filters: JobFilters, $limit: Int, $offset: Int) {
+			jobs(filters: $filters, limit: $limit, offset: $offset) {
+				id
+				slug
+				updatedAt
+			}
+		}
+	`
+
+	// CountJobsQuery backs the optional X-Total-Count header on job listings
+	CountJobsQuery = `
+		query CountJobs($filters: JobFilters) {
+			jobsCount(filters: $filters)
+		}
+	`
+
+	// GetExpiredJobsQuery finds published jobs whose closing date has passed
+	GetExpiredJobsQuery = `
+		query GetExpiredJobs($closingBefore: String!, $limit: Int) {
+			jobs(filters: { status: PUBLISHED, closingBefore: $closingBefore }, limit: $limit) {
+				id
+				title
+				closingDate
+			}
+		}
+	`
+
+	// GetJobApplicationSchemaQuery fetches the custom fields a job's
+	// applications must fill in
+	GetJobApplicationSchemaQuery = `
+		query GetJobApplicationSchema($jobId: ID!) {
+			jobApplicationSchema(jobId: $jobId) {
+				fields {
+					name
+					required
+					type
+					maxLength
+				}
+			}
+		}
+	`
+
 	GetJobQuery = `
 		query GetJob($id: ID!) {
 			job(id: $id) {
 				id
+				slug
+				title
+				department
+				location
+				employmentType
+				experienceLevel
+				salaryRange {
+					min
+					max
+					currency
+				}
+				description
+				requirements
+				responsibilities
+				benefits
+				skills
+				status
+				postedDate
+				closingDate
+				applicationCount
+				viewCount
+				remoteWork
+				urgentHiring
+				createdBy {
+					id
+					name
+					email
+				}
+				createdAt
+				updatedAt
+				variantB {
+					title
+					description
+				}
+			}
+		}
+	`
+
+	GetJobBySlugQuery = `
+		query GetJobBySlug($slug: String!) {
+			jobBySlug(slug: $slug) {
+				id
+				slug
 				title
 				department
 				location
@@ -71,6 +196,10 @@ const (
 				}
 				createdAt
 				updatedAt
+				variantB {
+					title
+					description
+				}
 			}
 		}
 	`
@@ -79,6 +208,7 @@ const (
 		mutation CreateJob($input: JobInput!) {
 			createJob(input: $input) {
 				id
+				slug
 				title
 				status
 				postedDate
@@ -97,6 +227,30 @@ const (
 		}
 	`
 
+	// UpdateJobSlugMutation sets the slug a job is found by at
+	// /jobs/by-slug/{slug}. Hub-HRMS rejects slugs used by another job.
+	UpdateJobSlugMutation = `
+		mutation UpdateJobSlug($id: ID!, $slug: String!) {
+			updateJobSlug(id: $id, slug: $slug) {
+				id
+				slug
+			}
+		}
+	`
+
+	// CreateJobVariantMutation stores an alternative title and description
+	// served to half of a job's visitors
+	CreateJobVariantMutation = `
+		mutation CreateJobVariant($jobId: ID!, $input: JobVariantInput!) {
+			createJobVariant(jobId: $jobId, input: $input) {
+				jobId
+				title
+				description
+				createdAt
+			}
+		}
+	`
+
 	PublishJobMutation = `
 		mutation PublishJob($id: ID!) {
 			publishJob(id: $id) {
@@ -124,13 +278,38 @@ const (
 	`
 
 	IncrementJobViewMutation = `
-		mutation IncrementJobView($id: ID!) {
-			incrementJobView(id: $id) {
+		mutation IncrementJobView($id: ID!, $variant: String) {
+			incrementJobView(id: $id, variant: $variant) {
 				id
 				viewCount
 			}
 		}
 	`
+
+	// GetJobAutoRejectionRulesQuery fetches the rules that reject a job's
+	// applications on submission
+	GetJobAutoRejectionRulesQuery = `
+		query GetJobAutoRejectionRules($id: ID!) {
+			job(id: $id) {
+				id
+				autoRejectionRules {
+					field
+					operator
+					value
+				}
+			}
+		}
+	`
+
+	UpdateJobAutoRejectionRulesMutation = `
+		mutation UpdateJobAutoRejectionRules($jobId: ID!, $rules: [AutoRejectionRuleInput!]!) {
+			updateJobAutoRejectionRules(jobId: $jobId, rules: $rules) {
+				field
+				operator
+				value
+			}
+		}
+	`
 )
 
 // Application Queries
@@ -141,6 +320,17 @@ const (
 				id
 				status
 				appliedDate
+				job {
+					title
+				}
+				attachmentUrls
+				referralId
+				source {
+					utmSource
+					utmMedium
+					utmCampaign
+					utmContent
+				}
 				aiScore {
 					overall
 					insights
@@ -183,8 +373,71 @@ const (
 		}
 	`
 
+	// CountApplicationsQuery backs the total on application listings
+	CountApplicationsQuery = `
+		query CountApplications($filters: ApplicationFilters) {
+			applicationsCount(filters: $filters)
+		}
+	`
+
+	SearchApplicationsQuery = `
+		query SearchApplications($query: String!, $limit: Int, $offset: Int) {
+			searchApplications(query: $query, limit: $limit, offset: $offset) {
+				total
+				items {
+					id
+					job {
+						id
+						title
+						department
+					}
+					candidate {
+						id
+						firstName
+						lastName
+						email
+					}
+					status
+					appliedDate
+					lastUpdated
+					aiScore {
+						overall
+						recommendation
+					}
+					highlights {
+						field
+						snippet
+					}
+				}
+			}
+		}
+	`
+
+	ExportApplicationsQuery = `
+		query ExportApplications($filters: ApplicationFilters, $limit: Int, $offset: Int) {
+			applications(filters: $filters, limit: $limit, offset: $offset) {
+				id
+				job {
+					title
+				}
+				candidate {
+					firstName
+					lastName
+					email
+					location
+					yearsOfExperience
+				}
+				status
+				appliedDate
+				aiScore {
+					overall
+				}
+			}
+		}
+	`
+
 	GetApplicationQuery = `
-		query GetApplication($id: ID!) {
+		query GetApplication($id: ID!, $timelineFilter: TimelineFilterInput) {
 			application(id: $id) {
 				id
 				job {
@@ -233,10 +486,29 @@ const (
 						name
 					}
 					content
+					tags
 					createdAt
 					isInternal
 				}
-				timeline {
+				timeline(filter: $timelineFilter) {
+					id
+					type
+					description
+					performedBy {
+						id
+						name
+					}
+					timestamp
+				}
+			}
+		}
+	`
+
+	GetApplicationTimelineQuery = `
+		query GetApplicationTimeline($id: ID!, $filter: TimelineFilterInput, $limit: Int, $offset: Int) {
+			application(id: $id) {
+				id
+				timeline(filter: $filter, limit: $limit, offset: $offset) {
 					id
 					type
 					description
@@ -250,12 +522,92 @@ const (
 		}
 	`
 
+	// GetApplicationJourneyQuery loads the timeline a candidate experience
+	// score is computed from; status and note are set on status changes
+	GetApplicationJourneyQuery = `
+		query GetApplicationJourney($id: ID!, $timelineLimit: Int) {
+			application(id: $id) {
+				id
+				appliedDate
+				timeline(limit: $timelineLimit) {
+					type
+					status
+					note
+					timestamp
+				}
+			}
+		}
+	`
+
+	// GetApplicationJourneysQuery is GetApplicationJourneyQuery for a page of
+	// applications
+	GetApplicationJourneysQuery = `
+		query GetApplicationJourneys($filters: ApplicationFilters, $limit: Int, $offset: Int, $timelineLimit: Int) {
+			applications(filters: $filters, limit: $limit, offset: $offset) {
+				id
+				appliedDate
+				timeline(limit: $timelineLimit) {
+					type
+					status
+					note
+					timestamp
+				}
+			}
+		}
+	`
+
+	GetApplicationStatusQuery = `
+		query GetApplicationStatus($id: ID!) {
+			application(id: $id) {
+				id
+				status
+				job {
+					id
+				}
+				aiScore {
+					recommendation
+				}
+			}
+		}
+	`
+
+	// GetOfferApplicationQuery loads what an offer letter is addressed to
+	GetOfferApplicationQuery = `
+		query GetOfferApplication($id: ID!) {
+			application(id: $id) {
+				id
+				status
+				candidate {
+					firstName
+					lastName
+					email
+				}
+				job {
+					id
+					title
+					department
+					location
+					employmentType
+				}
+			}
+		}
+	`
+
 	UpdateApplicationStatusMutation = `
-		mutation UpdateApplicationStatus($id: ID!, $status: ApplicationStatus!, $note: String) {
-			updateApplicationStatus(id: $id, status: $status, note: $note) {
+		mutation UpdateApplicationStatus($id: ID!, $status: ApplicationStatus!, $note: String, $offerLetterUrl: String) {
+			updateApplicationStatus(id: $id, status: $status, note: $note, offerLetterUrl: $offerLetterUrl) {
 				id
 				status
 				lastUpdated
+				offerLetterUrl
+				candidate {
+					firstName
+					lastName
+					email
+				}
+				job {
+					title
+				}
 			}
 		}
 	`
@@ -270,10 +622,11 @@ const (
 	`
 
 	AddApplicationNoteMutation = `
-		mutation AddApplicationNote($applicationId: ID!, $content: String!, $isInternal: Boolean) {
-			addApplicationNote(applicationId: $applicationId, content: $content, isInternal: $isInternal) {
+		mutation AddApplicationNote($applicationId: ID!, $content: String!, $isInternal: Boolean, $tags: [String!]) {
+			addApplicationNote(applicationId: $applicationId, content: $content, isInternal: $isInternal, tags: $tags) {
 				id
 				content
+				tags
 				author {
 					id
 					name
@@ -283,16 +636,255 @@ const (
 		}
 	`
 
-	ScoreApplicationMutation = `
-		mutation ScoreApplication($applicationId: ID!) {
-			scoreApplication(applicationId: $applicationId) {
-				overall
-				insights
-				strengths
-				concerns
-				recommendation
-				generatedAt
-			}
+	GetApplicationNotesQuery = `
+		query GetApplicationNotes($applicationId: ID!, $tag: String) {
+			applicationNotes(applicationId: $applicationId, tag: $tag) {
+				id
+				author {
+					id
+					name
+				}
+				content
+				tags
+				createdAt
+				isInternal
+			}
+		}
+	`
+
+	GetNoteTagsQuery = `
+		query GetNoteTags {
+			noteTags
+		}
+	`
+
+	ScoreApplicationMutation = `
+		mutation ScoreApplication($applicationId: ID!) {
+			scoreApplication(applicationId: $applicationId) {
+				overall
+				insights
+				strengths
+				concerns
+				recommendation
+				generatedAt
+			}
+		}
+	`
+)
+
+// Interview Queries
+const (
+	ScheduleInterviewMutation = `
+		mutation ScheduleInterview($applicationId: ID!, $input: InterviewInput!) {
+			scheduleInterview(applicationId: $applicationId, input: $input) {
+				id
+				scheduledAt
+				duration
+				type
+				location
+				interviewers {
+					id
+					name
+					email
+				}
+				application {
+					id
+					candidate {
+						firstName
+						lastName
+						email
+					}
+					job {
+						title
+					}
+				}
+			}
+		}
+	`
+
+	GetApplicationInterviewQuery = `
+		query GetApplicationInterview($id: ID!) {
+			application(id: $id) {
+				id
+				candidate {
+					firstName
+					lastName
+					email
+				}
+				job {
+					title
+				}
+				interview {
+					id
+					scheduledAt
+					duration
+					type
+					location
+					calendarEventId
+					interviewers {
+						id
+						name
+						email
+					}
+				}
+			}
+		}
+	`
+
+	// GetUpcomingInterviewsQuery finds interviews starting in a time window
+	GetUpcomingInterviewsQuery = `
+		query GetUpcomingInterviews($scheduledAfter: String!, $scheduledBefore: String!, $limit: Int) {
+			interviews(filters: { scheduledAfter: $scheduledAfter, scheduledBefore: $scheduledBefore }, limit: $limit) {
+				id
+				scheduledAt
+				duration
+				type
+				location
+				remindersSent
+				interviewers {
+					id
+					name
+					email
+				}
+				application {
+					id
+					candidate {
+						firstName
+						lastName
+						email
+					}
+					job {
+						title
+					}
+				}
+			}
+		}
+	`
+
+	MarkReminderSentMutation = `
+		mutation MarkReminderSent($interviewId: ID!, $reminder: String!) {
+			markInterviewReminderSent(interviewId: $interviewId, reminder: $reminder) {
+				id
+				remindersSent
+			}
+		}
+	`
+
+	UpdateInterviewMutation = `
+		mutation UpdateInterview($interviewId: ID!, $input: InterviewUpdateInput!) {
+			updateInterview(interviewId: $interviewId, input: $input) {
+				id
+				calendarEventId
+			}
+		}
+	`
+
+	CancelInterviewMutation = `
+		mutation CancelInterview($interviewId: ID!) {
+			cancelInterview(interviewId: $interviewId) {
+				id
+				status
+			}
+		}
+	`
+)
+
+// Offer Queries
+const (
+	RecordCounterOfferMutation = `
+		mutation RecordCounterOffer($applicationId: ID!, $input: CounterOfferInput!) {
+			recordCounterOffer(applicationId: $applicationId, input: $input) {
+				id
+				status
+				requestedSalary
+				requestedBenefits
+				candidateNote
+				createdAt
+				application {
+					id
+					candidate {
+						firstName
+						lastName
+						email
+					}
+					job {
+						id
+						title
+						createdBy {
+							name
+							email
+						}
+					}
+					offerHistory {
+						id
+						status
+						requestedSalary
+						requestedBenefits
+						candidateNote
+						respondedBy {
+							id
+							name
+						}
+						responseNote
+						createdAt
+						respondedAt
+					}
+				}
+			}
+		}
+	`
+
+	ApproveCounterOfferMutation = `
+		mutation ApproveCounterOffer($counterOfferId: ID!, $note: String) {
+			approveCounterOffer(counterOfferId: $counterOfferId, note: $note) {
+				id
+				status
+				responseNote
+				respondedAt
+				application {
+					id
+					candidate {
+						firstName
+						lastName
+						email
+					}
+					job {
+						id
+						title
+						createdBy {
+							name
+							email
+						}
+					}
+				}
+			}
+		}
+	`
+
+	RejectCounterOfferMutation = `
+		mutation RejectCounterOffer($counterOfferId: ID!, $note: String) {
+			rejectCounterOffer(counterOfferId: $counterOfferId, note: $note) {
+				id
+				status
+				responseNote
+				respondedAt
+				application {
+					id
+					candidate {
+						firstName
+						lastName
+						email
+					}
+					job {
+						id
+						title
+						createdBy {
+							name
+							email
+						}
+					}
+				}
+			}
 		}
 	`
 )
@@ -371,6 +963,33 @@ const (
 		}
 	`
 
+	// GetApplicationsBySourceQuery counts applications, interviews and hires
+	// per UTM source, medium and campaign
+	GetApplicationsBySourceQuery = `
+		query GetApplicationsBySource($dateRange: DateRangeInput!, $jobId: ID) {
+			applicationsBySource(dateRange: $dateRange, jobId: $jobId) {
+				utmSource
+				utmMedium
+				utmCampaign
+				applications
+				interviewed
+				hired
+			}
+		}
+	`
+
+	// GetJobABResultsQuery counts views and applications for each variant of
+	// a job under an A/B test
+	GetJobABResultsQuery = `
+		query GetJobABResults($jobId: ID!) {
+			jobABResults(jobId: $jobId) {
+				variant
+				views
+				applications
+			}
+		}
+	`
+
 	GetApplicationPipelineQuery = `
 		query GetApplicationPipeline($jobId: ID) {
 			applicationPipeline(jobId: $jobId) {
@@ -390,10 +1009,74 @@ const (
 			}
 		}
 	`
+
+	// GetApplicationTrendQuery counts applications per day, week or month,
+	// one page of points at a time
+	GetApplicationTrendQuery = `
+		query GetApplicationTrend($dateRange: DateRangeInput!, $groupBy: TrendGroupBy!, $limit: Int, $offset: Int) {
+			applicationTrend(dateRange: $dateRange, groupBy: $groupBy, limit: $limit, offset: $offset) {
+				totalPoints
+				points {
+					date
+					value
+				}
+			}
+		}
+	`
+
+	// GetDepartmentMetricsQuery summarizes hiring for each department
+	GetDepartmentMetricsQuery = `
+		query GetDepartmentMetrics($dateRange: DateRangeInput!) {
+			departmentMetrics(dateRange: $dateRange) {
+				department
+				openJobs
+				totalApplications
+				avgTimeToHire
+				conversionRate
+				topCandidateCount
+			}
+		}
+	`
+
+	// GetDepartmentDetailQuery summarizes hiring for one department, with
+	// the number of its applications that reached each pipeline stage
+	GetDepartmentDetailQuery = `
+		query GetDepartmentDetail($department: String!, $dateRange: DateRangeInput!) {
+			departmentDetail(department: $department, dateRange: $dateRange) {
+				department
+				openJobs
+				totalApplications
+				avgTimeToHire
+				conversionRate
+				topCandidateCount
+				funnel {
+					stage
+					count
+				}
+			}
+		}
+	`
 )
 
 // Candidate Queries
 const (
+	// SearchCandidatesByEmailQuery finds candidates registered under an email
+	// address, with the jobs they have applied to
+	SearchCandidatesByEmailQuery = `
+		query SearchCandidatesByEmail($email: String!) {
+			searchCandidates(email: $email) {
+				id
+				email
+				applications {
+					id
+					job {
+						id
+					}
+				}
+			}
+		}
+	`
+
 	GetCandidateQuery = `
 		query GetCandidate($id: ID!) {
 			candidate(id: $id) {
@@ -457,6 +1140,35 @@ const (
 		}
 	`
 
+	ExtractResumeTextMutation = `
+		mutation ExtractResumeText($candidateId: ID!, $resumeUrl: String!) {
+			extractResumeText(candidateId: $candidateId, resumeUrl: $resumeUrl) {
+				id
+				status
+			}
+		}
+	`
+
+	// CountCandidateUploadsQuery counts the files uploaded under a
+	// candidate's email address
+	CountCandidateUploadsQuery = `
+		query CountCandidateUploads($email: String!) {
+			candidateUploadCount(email: $email)
+		}
+	`
+
+	GetCandidateUploadsQuery = `
+		query GetCandidateUploads($email: String!) {
+			candidateUploads(email: $email) {
+				key
+				filename
+				contentType
+				size
+				uploadedAt
+			}
+		}
+	`
+
 	UpdateCandidateProfileMutation = `
 		mutation UpdateCandidateProfile($id: ID!, $input: CandidateProfileInput!) {
 			updateCandidateProfile(id: $id, input: $input) {
@@ -467,4 +1179,421 @@ const (
 			}
 		}
 	`
-)
\ No newline at end of file
+
+	AnonymizeCandidateMutation = `
+		mutation AnonymizeCandidate($id: ID!, $input: CandidateAnonymizationInput!) {
+			anonymizeCandidate(id: $id, input: $input) {
+				id
+				firstName
+				lastName
+				email
+				phone
+				updatedAt
+			}
+		}
+	`
+)
+
+// User Queries
+const (
+	GetNotificationPreferencesQuery = `
+		query GetNotificationPreferences($userId: ID!) {
+			notificationPreferences(userId: $userId) {
+				statusChanges
+				newApplications
+				scoreReady
+				interviewReminders
+			}
+		}
+	`
+
+	UpdateNotificationPreferencesMutation = `
+		mutation UpdateNotificationPreferences($userId: ID!, $input: NotificationPreferencesInput!) {
+			updateNotificationPreferences(userId: $userId, input: $input) {
+				statusChanges
+				newApplications
+				scoreReady
+				interviewReminders
+			}
+		}
+	`
+)
+
+// Recruiter Queries
+const (
+	// GetRecruiterWorkloadQuery lists recruiters with their open applications
+	GetRecruiterWorkloadQuery = `
+		query GetRecruiterWorkload {
+			recruiters {
+				id
+				name
+				email
+				openApplications
+			}
+		}
+	`
+
+	GetUnassignedApplicationsQuery = `
+		query GetUnassignedApplications($limit: Int) {
+			applications(filters: { unassigned: true }, limit: $limit) {
+				id
+				appliedDate
+			}
+		}
+	`
+
+	AssignApplicationMutation = `
+		mutation AssignApplication($applicationId: ID!, $recruiterId: ID!) {
+			assignApplication(applicationId: $applicationId, recruiterId: $recruiterId) {
+				id
+				assignedRecruiter {
+					id
+					name
+					email
+				}
+				candidate {
+					firstName
+					lastName
+				}
+				job {
+					title
+				}
+			}
+		}
+	`
+)
+
+// Candidate Pool Queries
+const (
+	// SearchCandidatesQuery finds candidates matching a saved pool query
+	SearchCandidatesQuery = `
+		query SearchCandidates($filters: CandidateSearchInput!, $limit: Int) {
+			searchCandidates(filters: $filters, limit: $limit) {
+				id
+			}
+		}
+	`
+
+	GetCandidatePoolsQuery = `
+		query GetCandidatePools {
+			candidatePools {
+				id
+				name
+				query
+				candidateIds
+				createdBy
+				updatedAt
+			}
+		}
+	`
+
+	GetCandidatePoolQuery = `
+		query GetCandidatePool($id: ID!) {
+			candidatePool(id: $id) {
+				id
+				name
+				query
+				candidateIds
+				createdBy
+				updatedAt
+			}
+		}
+	`
+
+	CreateCandidatePoolMutation = `
+		mutation CreateCandidatePool($input: CandidatePoolInput!) {
+			createCandidatePool(input: $input) {
+				id
+				name
+				query
+				candidateIds
+				createdBy
+				updatedAt
+			}
+		}
+	`
+
+	UpdateCandidatePoolMutation = `
+		mutation UpdateCandidatePool($id: ID!, $input: CandidatePoolInput!) {
+			updateCandidatePool(id: $id, input: $input) {
+				id
+				name
+				query
+				candidateIds
+				createdBy
+				updatedAt
+			}
+		}
+	`
+
+	DeleteCandidatePoolMutation = `
+		mutation DeleteCandidatePool($id: ID!) {
+			deleteCandidatePool(id: $id)
+		}
+	`
+
+	// GetCampaignRecipientsQuery fetches the contact details of a pool's
+	// candidates for an email campaign
+	GetCampaignRecipientsQuery = `
+		query GetCampaignRecipients($ids: [ID!]!) {
+			candidatesByIds(ids: $ids) {
+				id
+				firstName
+				lastName
+				email
+				emailOptOut
+			}
+		}
+	`
+
+	GetPoolCampaignsQuery = `
+		query GetPoolCampaigns($poolId: ID!) {
+			campaigns(poolId: $poolId) {
+				id
+				poolId
+				subject
+				templateName
+				jobId
+				recipientCount
+				sentCount
+				openedCount
+				createdBy
+				createdAt
+			}
+		}
+	`
+
+	CreateCampaignMutation = `
+		mutation CreateCampaign($input: CampaignInput!) {
+			createCampaign(input: $input) {
+				id
+				poolId
+				subject
+				templateName
+				jobId
+				recipientCount
+				sentCount
+				openedCount
+				createdBy
+				createdAt
+			}
+		}
+	`
+
+	RecordCampaignSentMutation = `
+		mutation RecordCampaignSent($campaignId: ID!, $sentCount: Int!) {
+			recordCampaignSent(campaignId: $campaignId, sentCount: $sentCount) {
+				id
+				sentCount
+			}
+		}
+	`
+)
+
+// Blacklist Queries
+const (
+	CheckBlacklistQuery = `
+		query CheckBlacklist($email: String!) {
+			blacklistEntry(email: $email) {
+				id
+				expiresAt
+			}
+		}
+	`
+
+	GetBlacklistQuery = `
+		query GetBlacklist {
+			blacklist {
+				id
+				email
+				reason
+				expiresAt
+				createdBy
+				createdAt
+			}
+		}
+	`
+
+	BlacklistCandidateMutation = `
+		mutation BlacklistCandidate($input: BlacklistInput!) {
+			blacklistCandidate(input: $input) {
+				id
+				email
+				reason
+				expiresAt
+				createdBy
+				createdAt
+			}
+		}
+	`
+
+	RemoveBlacklistEntryMutation = `
+		mutation RemoveBlacklistEntry($id: ID!) {
+			removeBlacklistEntry(id: $id)
+		}
+	`
+)
+
+// Share Link Queries
+const (
+	CreateShareLinkMutation = `
+		mutation CreateShareLink($input: ShareLinkInput!) {
+			createShareLink(input: $input) {
+				token
+				applicationId
+				expiresAt
+				note
+				createdBy
+			}
+		}
+	`
+
+	GetShareLinkQuery = `
+		query GetShareLink($token: String!) {
+			shareLink(token: $token) {
+				token
+				applicationId
+				expiresAt
+				note
+				createdBy
+			}
+		}
+	`
+)
+
+// Referral Queries
+const (
+	CreateReferralMutation = `
+		mutation CreateReferral($input: ReferralInput!) {
+			createReferral(input: $input) {
+				id
+				jobId
+				referrerId
+				candidateEmail
+				candidateFirstName
+				candidateLastName
+				createdAt
+			}
+		}
+	`
+
+	// GetReferralsQuery lists referrals with the application each led to,
+	// if any
+	GetReferralsQuery = `
+		query GetReferrals($filters: ReferralFilters, $limit: Int, $offset: Int) {
+			referrals(filters: $filters, limit: $limit, offset: $offset) {
+				id
+				referrer {
+					id
+					name
+				}
+				job {
+					id
+					title
+				}
+				candidateEmail
+				candidateFirstName
+				candidateLastName
+				createdAt
+				application {
+					id
+					status
+					appliedDate
+				}
+			}
+		}
+	`
+)
+
+// Pipeline Queries
+const (
+	GetPipelineStagesQuery = `
+		query GetPipelineStages {
+			pipelineStages {
+				id
+				name
+				color
+				order
+				finalStage
+			}
+		}
+	`
+
+	UpdatePipelineStagesMutation = `
+		mutation UpdatePipelineStages($stages: [PipelineStageInput!]!) {
+			updatePipelineStages(stages: $stages) {
+				id
+				name
+				color
+				order
+				finalStage
+			}
+		}
+	`
+)
+
+// Resume Text Queries
+const (
+	GetResumeTextQuery = `
+		query GetResumeText($id: ID!) {
+			application(id: $id) {
+				id
+				resumeUrl
+				resumeText {
+					text
+					extractedAt
+				}
+			}
+		}
+	`
+
+	IndexResumeTextMutation = `
+		mutation IndexResumeText($applicationId: ID!, $text: String!) {
+			indexResumeText(applicationId: $applicationId, text: $text) {
+				text
+				extractedAt
+			}
+		}
+	`
+)
+
+// Scoring Feedback Queries
+const (
+	// SubmitScoringFeedbackMutation records whether a recruiter agreed with an
+	// application's AI score. Automatic feedback is generated from status
+	// changes rather than given by hand.
+	SubmitScoringFeedbackMutation = `
+		mutation SubmitScoringFeedback($input: ScoringFeedbackInput!) {
+			submitScoringFeedback(input: $input) {
+				id
+				applicationId
+				agreesWithScore
+				overrideReason
+				automatic
+				createdAt
+			}
+		}
+	`
+
+	// GetScoringFeedbackQuery lists scoring feedback with the job and
+	// candidate experience it was given for
+	GetScoringFeedbackQuery = `
+		query GetScoringFeedback($dateRange: DateRangeInput!, $limit: Int, $offset: Int) {
+			scoringFeedback(dateRange: $dateRange, limit: $limit, offset: $offset) {
+				id
+				agreesWithScore
+				automatic
+				application {
+					id
+					job {
+						id
+						title
+					}
+					candidate {
+						yearsOfExperience
+					}
+				}
+			}
+		}
+	`
+)
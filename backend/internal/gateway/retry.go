@@ -0,0 +1,118 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// ErrRetriesExhausted is matched by errors.Is when every attempt failed
+var ErrRetriesExhausted = errors.New("retries exhausted")
+
+// RetriesExhaustedError wraps the last error after all attempts failed
+type RetriesExhaustedError struct {
+	Attempts int
+	Last     error
+}
+
+func (e *RetriesExhaustedError) Error() string {
+	return fmt.Sprintf("Hub-HRMS request failed after %d attempts: %v", e.Attempts, e.Last)
+}
+
+// Unwrap exposes both the sentinel and the last error to errors.Is/As
+func (e *RetriesExhaustedError) Unwrap() []error {
+	return []error{ErrRetriesExhausted, e.Last}
+}
+
+// StatusError is returned when Hub-HRMS responds with a non-200 status
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("Hub-HRMS returned status %d: %s", e.StatusCode, e.Body)
+}
+
+// permanentError marks failures that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+func permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// WithRetry retries failed queries up to maxAttempts times in total, with
+// full-jitter exponential backoff starting at base. Mutations are never retried.
+func WithRetry(maxAttempts int, base time.Duration) ClientOption {
+	return func(c *HubHRMSClient) {
+		if maxAttempts < 1 {
+			maxAttempts = 1
+		}
+		c.maxAttempts = maxAttempts
+		c.retryBase = base
+	}
+}
+
+// retry runs fn until it succeeds, fails permanently, the context ends, or
+// the attempts run out
+func (c *HubHRMSClient) retry(ctx context.Context, fn func() (*GraphQLResponse, error)) (*GraphQLResponse, error) {
+	var lastErr error
+	for attempt := 0; attempt < c.maxAttempts; attempt++ {
+		if attempt > 0 {
+			timer := time.NewTimer(backoff(c.retryBase, attempt))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return nil, fmt.Errorf("%w (last error: %v)", ctx.Err(), lastErr)
+			case <-timer.C:
+			}
+		}
+
+		resp, err := fn()
+		if err == nil {
+			return resp, nil
+		}
+		if !retryable(err) || ctx.Err() != nil {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	if c.maxAttempts == 1 {
+		return nil, lastErr
+	}
+	return nil, &RetriesExhaustedError{Attempts: c.maxAttempts, Last: lastErr}
+}
+
+// backoff returns a random delay in [0, base*2^attempt)
+func backoff(base time.Duration, attempt int) time.Duration {
+	ceiling := base << attempt
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int63n(int64(ceiling)))
+}
+
+// retryable reports whether err is worth another attempt
+func retryable(err error) bool {
+	var perm *permanentError
+	if errors.As(err, &perm) {
+		return false
+	}
+
+	var status *StatusError
+	if errors.As(err, &status) {
+		return status.StatusCode == http.StatusTooManyRequests || status.StatusCode >= 500
+	}
+
+	// Transport errors: connection refused, reset, timeouts
+	return true
+}
@@ -0,0 +1,139 @@
+package gateway
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFlakyUpstream answers with statuses in turn, then with a successful
+// response, and counts the requests it serves
+func newFlakyUpstream(t *testing.T, statuses ...int) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := int(requests.Add(1))
+		if n <= len(statuses) {
+			if statuses[n-1] == http.StatusOK {
+				w.Write([]byte(`{"data":`))
+				return
+			}
+			http.Error(w, "unavailable", statuses[n-1])
+			return
+		}
+		w.Write([]byte(`{"data":{"jobs":[]}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestHubHRMSClient_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		statuses     []int
+		wantRequests int32
+		wantErr      bool
+		wantStatus   int
+		wantRetries  bool
+	}{
+		{name: "transient failures", statuses: []int{503, 502}, wantRequests: 3},
+		{name: "rate limited", statuses: []int{429}, wantRequests: 2},
+		{name: "client error", statuses: []int{400}, wantRequests: 1, wantErr: true, wantStatus: 400},
+		{name: "undecodable response", statuses: []int{200}, wantRequests: 1, wantErr: true},
+		{name: "attempts exhausted", statuses: []int{500, 500, 503, 500}, wantRequests: 3, wantErr: true, wantStatus: 503, wantRetries: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			upstream, requests := newFlakyUpstream(t, tt.statuses...)
+			client := NewHubHRMSClient(upstream.URL, "", WithRetry(3, time.Millisecond))
+			defer client.Close()
+
+			_, err := client.Query(context.Background(), "query GetJobs { jobs { id } }", nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Query() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := requests.Load(); got != tt.wantRequests {
+				t.Fatalf("made %d requests, want %d", got, tt.wantRequests)
+			}
+			if !tt.wantErr {
+				return
+			}
+
+			var status *StatusError
+			if tt.wantStatus != 0 && (!errors.As(err, &status) || status.StatusCode != tt.wantStatus) {
+				t.Fatalf("error = %v, want the last status %d", err, tt.wantStatus)
+			}
+			var exhausted *RetriesExhaustedError
+			if errors.Is(err, ErrRetriesExhausted) != tt.wantRetries || errors.As(err, &exhausted) != tt.wantRetries {
+				t.Fatalf("error = %v, retries exhausted = %v", err, errors.Is(err, ErrRetriesExhausted))
+			}
+			if tt.wantRetries && exhausted.Attempts != 3 {
+				t.Fatalf("Attempts = %d, want 3", exhausted.Attempts)
+			}
+		})
+	}
+}
+
+func TestHubHRMSClient_Retry_Mutations(t *testing.T) {
+	upstream, requests := newFlakyUpstream(t, 503)
+	client := NewHubHRMSClient(upstream.URL, "", WithRetry(3, time.Millisecond))
+	defer client.Close()
+
+	_, err := client.Mutate(context.Background(), "mutation CloseJob { closeJob(id: 1) { id } }", nil)
+	if err == nil || requests.Load() != 1 {
+		t.Fatalf("Mutate() made %d requests, err = %v; want one failed attempt", requests.Load(), err)
+	}
+	if errors.Is(err, ErrRetriesExhausted) {
+		t.Fatalf("Mutate() error = %v, want no retries", err)
+	}
+}
+
+func TestHubHRMSClient_Retry_WithoutOption(t *testing.T) {
+	upstream, requests := newFlakyUpstream(t, 503)
+	client := NewHubHRMSClient(upstream.URL, "")
+	defer client.Close()
+
+	_, err := client.Query(context.Background(), "query GetJobs { jobs { id } }", nil)
+	if err == nil || requests.Load() != 1 || errors.Is(err, ErrRetriesExhausted) {
+		t.Fatalf("Query() made %d requests, err = %v; want a single attempt", requests.Load(), err)
+	}
+}
+
+func TestHubHRMSClient_Retry_ContextCancelled(t *testing.T) {
+	upstream, requests := newFlakyUpstream(t, 503, 503, 503, 503)
+	client := NewHubHRMSClient(upstream.URL, "", WithRetry(5, time.Hour))
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := client.Query(ctx, "query GetJobs { jobs { id } }", nil)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Query() error = %v, want the context deadline", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Query() waited %v for a backoff past the deadline", elapsed)
+	}
+	if requests.Load() > 2 {
+		t.Fatalf("made %d requests after the deadline", requests.Load())
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	base := 10 * time.Millisecond
+	for attempt := 1; attempt <= 5; attempt++ {
+		ceiling := base << attempt
+		for i := 0; i < 100; i++ {
+			if d := backoff(base, attempt); d < 0 || d >= ceiling {
+				t.Fatalf("backoff(%v, %d) = %v, want [0, %v)", base, attempt, d, ceiling)
+			}
+		}
+	}
+	if d := backoff(0, 3); d != 0 {
+		t.Fatalf("backoff(0, 3) = %v, want 0", d)
+	}
+}
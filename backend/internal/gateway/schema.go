@@ -0,0 +1,328 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/vektah/gqlparser/v2"
+	"github.com/vektah/gqlparser/v2/ast"
+)
+
+// ErrSchemaMismatch is returned for queries the Hub-HRMS schema rejects
+var ErrSchemaMismatch = errors.New("query does not match schema")
+
+// introspectionQuery fetches everything needed to rebuild the schema as SDL.
+// Type references are unwrapped through seven NON_NULL and LIST wrappers,
+// enough for [[Type!]!]!.
+const introspectionQuery = `
+	query IntrospectSchema {
+		__schema {
+			queryType { name }
+			mutationType { name }
+			subscriptionType { name }
+			types {
+				kind
+				name
+				fields(includeDeprecated: true) {
+					name
+					args { ...InputValue }
+					type { ...TypeRef }
+				}
+				inputFields { ...InputValue }
+				interfaces { ...TypeRef }
+				enumValues(includeDeprecated: true) { name }
+				possibleTypes { ...TypeRef }
+			}
+			directives {
+				name
+				locations
+				args { ...InputValue }
+			}
+		}
+	}
+
+	fragment InputValue on __InputValue {
+		name
+		type { ...TypeRef }
+		defaultValue
+	}
+
+	fragment TypeRef on __Type {
+		kind
+		name
+		ofType { kind name ofType { kind name ofType { kind name ofType {
+			kind name ofType { kind name ofType { kind name ofType { kind name } } }
+		} } } }
+	}
+`
+
+// operations lists every query and mutation sent to Hub-HRMS, so they can
+// be checked against its schema. TestOperations_ListsEveryConstant fails
+// when a constant in queries.go is missing.
+var operations = []string{
+	GetJobsQuery, GetJobsPageQuery, GetSitemapJobsQuery, CountJobsQuery, GetExpiredJobsQuery,
+	GetJobApplicationSchemaQuery, GetJobQuery, GetJobBySlugQuery, CreateJobMutation,
+	UpdateJobMutation, UpdateJobSlugMutation, CreateJobVariantMutation, PublishJobMutation,
+	CloseJobMutation, DeleteJobMutation, IncrementJobViewMutation, GetJobAutoRejectionRulesQuery,
+	UpdateJobAutoRejectionRulesMutation, SubmitApplicationMutation,
+	GetApplicationsQuery, CountApplicationsQuery, SearchApplicationsQuery, ExportApplicationsQuery,
+	GetApplicationQuery, GetApplicationTimelineQuery, GetApplicationJourneyQuery,
+	GetApplicationJourneysQuery, GetApplicationStatusQuery,
+	GetOfferApplicationQuery, UpdateApplicationStatusMutation, BulkUpdateApplicationStatusMutation,
+	AddApplicationNoteMutation, GetApplicationNotesQuery, GetNoteTagsQuery,
+	ScoreApplicationMutation, ScheduleInterviewMutation,
+	GetApplicationInterviewQuery, GetUpcomingInterviewsQuery, MarkReminderSentMutation,
+	UpdateInterviewMutation, CancelInterviewMutation,
+	RecordCounterOfferMutation, ApproveCounterOfferMutation, RejectCounterOfferMutation,
+	GenerateJobDescriptionMutation, GetRecruitmentMetricsQuery, GetJobPerformanceQuery,
+	GetApplicationsBySourceQuery, GetJobABResultsQuery, GetApplicationPipelineQuery,
+	GetApplicationTrendQuery, GetDepartmentMetricsQuery, GetDepartmentDetailQuery,
+	SearchCandidatesByEmailQuery, GetCandidateQuery, ExtractResumeTextMutation,
+	CountCandidateUploadsQuery, GetCandidateUploadsQuery,
+	UpdateCandidateProfileMutation, AnonymizeCandidateMutation, GetNotificationPreferencesQuery,
+	UpdateNotificationPreferencesMutation, GetRecruiterWorkloadQuery, GetUnassignedApplicationsQuery,
+	AssignApplicationMutation, SearchCandidatesQuery, GetCandidatePoolsQuery,
+	GetCandidatePoolQuery, CreateCandidatePoolMutation, UpdateCandidatePoolMutation,
+	DeleteCandidatePoolMutation, GetCampaignRecipientsQuery, GetPoolCampaignsQuery,
+	CreateCampaignMutation, RecordCampaignSentMutation, CheckBlacklistQuery, GetBlacklistQuery, BlacklistCandidateMutation,
+	RemoveBlacklistEntryMutation, CreateShareLinkMutation, GetShareLinkQuery, CreateReferralMutation,
+	GetReferralsQuery, GetPipelineStagesQuery, UpdatePipelineStagesMutation, GetResumeTextQuery,
+	IndexResumeTextMutation, SubmitScoringFeedbackMutation, GetScoringFeedbackQuery,
+}
+
+// introspectionType is a type reference in an introspection result
+type introspectionType struct {
+	Kind   string             `json:"kind"`
+	Name   string             `json:"name"`
+	OfType *introspectionType `json:"ofType"`
+}
+
+// String writes the reference in SDL, such as [Job!]!
+func (t *introspectionType) String() string {
+	switch {
+	case t == nil:
+		return ""
+	case t.Kind == "NON_NULL":
+		return t.OfType.String() + "!"
+	case t.Kind == "LIST":
+		return "[" + t.OfType.String() + "]"
+	}
+	return t.Name
+}
+
+// introspectionInputValue is an argument or input field
+type introspectionInputValue struct {
+	Name         string             `json:"name"`
+	Type         *introspectionType `json:"type"`
+	DefaultValue *string            `json:"defaultValue"`
+}
+
+type introspectionSchema struct {
+	QueryType        *introspectionType `json:"queryType"`
+	MutationType     *introspectionType `json:"mutationType"`
+	SubscriptionType *introspectionType `json:"subscriptionType"`
+	Types            []struct {
+		Kind   string `json:"kind"`
+		Name   string `json:"name"`
+		Fields []struct {
+			Name string                    `json:"name"`
+			Args []introspectionInputValue `json:"args"`
+			Type *introspectionType        `json:"type"`
+		} `json:"fields"`
+		InputFields   []introspectionInputValue `json:"inputFields"`
+		Interfaces    []*introspectionType      `json:"interfaces"`
+		EnumValues    []struct{ Name string }   `json:"enumValues"`
+		PossibleTypes []*introspectionType      `json:"possibleTypes"`
+	} `json:"types"`
+	Directives []struct {
+		Name      string                    `json:"name"`
+		Locations []string                  `json:"locations"`
+		Args      []introspectionInputValue `json:"args"`
+	} `json:"directives"`
+}
+
+// preludeNames are the scalars and directives gqlparser always defines
+var preludeNames = map[string]bool{
+	"Int": true, "Float": true, "String": true, "Boolean": true, "ID": true,
+	"include": true, "skip": true, "deprecated": true, "specifiedBy": true, "defer": true, "oneOf": true,
+}
+
+// LoadSchema fetches the Hub-HRMS schema by introspection and loads it with
+// gqlparser, so queries can be validated against it
+func LoadSchema(ctx context.Context, client *HubHRMSClient) (*ast.Schema, error) {
+	resp, err := client.Query(ctx, introspectionQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to introspect Hub-HRMS schema: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("failed to introspect Hub-HRMS schema: %s", resp.Errors[0].Message)
+	}
+
+	raw, err := json.Marshal(resp.Data)
+	if err != nil {
+		return nil, err
+	}
+	var result struct {
+		Schema introspectionSchema `json:"__schema"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, fmt.Errorf("invalid introspection result: %w", err)
+	}
+	if result.Schema.QueryType == nil {
+		return nil, errors.New("invalid introspection result: no query type")
+	}
+
+	schema, err := gqlparser.LoadSchema(&ast.Source{Name: "hubhrms.graphql", Input: result.Schema.sdl()})
+	if err != nil {
+		return nil, fmt.Errorf("invalid introspection result: %w", err)
+	}
+	return schema, nil
+}
+
+// sdl writes the introspected schema as SDL, leaving out the prelude
+func (s *introspectionSchema) sdl() string {
+	var b strings.Builder
+	b.WriteString("schema {\n\tquery: " + s.QueryType.Name + "\n")
+	if s.MutationType != nil {
+		b.WriteString("\tmutation: " + s.MutationType.Name + "\n")
+	}
+	if s.SubscriptionType != nil {
+		b.WriteString("\tsubscription: " + s.SubscriptionType.Name + "\n")
+	}
+	b.WriteString("}\n")
+
+	for _, t := range s.Types {
+		if strings.HasPrefix(t.Name, "__") || preludeNames[t.Name] {
+			continue
+		}
+		switch t.Kind {
+		case "SCALAR":
+			fmt.Fprintf(&b, "scalar %s\n", t.Name)
+		case "OBJECT", "INTERFACE":
+			keyword := "type"
+			if t.Kind == "INTERFACE" {
+				keyword = "interface"
+			}
+			fmt.Fprintf(&b, "%s %s", keyword, t.Name)
+			for i, iface := range t.Interfaces {
+				if i == 0 {
+					b.WriteString(" implements ")
+				} else {
+					b.WriteString(" & ")
+				}
+				b.WriteString(iface.Name)
+			}
+			b.WriteString(" {\n")
+			for _, field := range t.Fields {
+				// __schema and __type are implied on the query type
+				if strings.HasPrefix(field.Name, "__") {
+					continue
+				}
+				fmt.Fprintf(&b, "\t%s%s: %s\n", field.Name, sdlArguments(field.Args), field.Type)
+			}
+			b.WriteString("}\n")
+		case "UNION":
+			members := make([]string, len(t.PossibleTypes))
+			for i, member := range t.PossibleTypes {
+				members[i] = member.Name
+			}
+			fmt.Fprintf(&b, "union %s = %s\n", t.Name, strings.Join(members, " | "))
+		case "ENUM":
+			fmt.Fprintf(&b, "enum %s {\n", t.Name)
+			for _, value := range t.EnumValues {
+				fmt.Fprintf(&b, "\t%s\n", value.Name)
+			}
+			b.WriteString("}\n")
+		case "INPUT_OBJECT":
+			fmt.Fprintf(&b, "input %s {\n", t.Name)
+			for _, field := range t.InputFields {
+				fmt.Fprintf(&b, "\t%s\n", sdlInputValue(field))
+			}
+			b.WriteString("}\n")
+		}
+	}
+
+	for _, d := range s.Directives {
+		if preludeNames[d.Name] {
+			continue
+		}
+		fmt.Fprintf(&b, "directive @%s%s on %s\n", d.Name, sdlArguments(d.Args), strings.Join(d.Locations, " | "))
+	}
+	return b.String()
+}
+
+// sdlArguments writes an argument list, or nothing when there are none
+func sdlArguments(args []introspectionInputValue) string {
+	if len(args) == 0 {
+		return ""
+	}
+	written := make([]string, len(args))
+	for i, arg := range args {
+		written[i] = sdlInputValue(arg)
+	}
+	return "(" + strings.Join(written, ", ") + ")"
+}
+
+func sdlInputValue(v introspectionInputValue) string {
+	if v.DefaultValue != nil {
+		return fmt.Sprintf("%s: %s = %s", v.Name, v.Type, *v.DefaultValue)
+	}
+	return fmt.Sprintf("%s: %s", v.Name, v.Type)
+}
+
+// Schema returns the Hub-HRMS schema, introspecting it on first use rather
+// than when the client is created. A failed load is not kept, so the next
+// call tries again.
+func (c *HubHRMSClient) Schema(ctx context.Context) (*ast.Schema, error) {
+	c.schemaMu.Lock()
+	defer c.schemaMu.Unlock()
+	if c.schema != nil {
+		return c.schema, nil
+	}
+
+	schema, err := LoadSchema(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	c.schema = schema
+	return schema, nil
+}
+
+// ValidateQuery checks query against schema: its fields, arguments,
+// variables and fragments
+func ValidateQuery(schema *ast.Schema, query string) error {
+	if _, errs := gqlparser.LoadQuery(schema, query); len(errs) > 0 {
+		messages := make([]string, len(errs))
+		for i, err := range errs {
+			messages[i] = err.Message
+		}
+		return fmt.Errorf("%w: %s", ErrSchemaMismatch, strings.Join(messages, "; "))
+	}
+	return nil
+}
+
+// OperationMismatch is a query sent to Hub-HRMS that does not match its
+// schema
+type OperationMismatch struct {
+	Operation string
+	Err       error
+}
+
+// ValidateOperations checks every query and mutation this service sends
+// against schema, returning those that do not match ordered by name
+func ValidateOperations(schema *ast.Schema) []OperationMismatch {
+	var mismatches []OperationMismatch
+	for _, query := range operations {
+		if err := ValidateQuery(schema, query); err != nil {
+			mismatches = append(mismatches, OperationMismatch{Operation: operationName(query), Err: err})
+		}
+	}
+	sort.Slice(mismatches, func(i, j int) bool {
+		return mismatches[i].Operation < mismatches[j].Operation
+	})
+	return mismatches
+}
@@ -0,0 +1,340 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"go/ast"
+	goparser "go/parser"
+	"go/token"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/vektah/gqlparser/v2"
+	gqlast "github.com/vektah/gqlparser/v2/ast"
+	gqlparse "github.com/vektah/gqlparser/v2/parser"
+)
+
+// fakeSDL stands in for the Hub-HRMS schema
+const fakeSDL = `
+scalar DateTime
+
+enum ApplicationStatus { SUBMITTED REVIEWING REJECTED }
+
+interface Node { id: ID! }
+
+type Job implements Node {
+	id: ID!
+	title: String!
+	closingDate: DateTime
+	applications(status: ApplicationStatus, first: Int = 20): [Application!]!
+}
+
+type Application implements Node {
+	id: ID!
+	status: ApplicationStatus!
+}
+
+union SearchResult = Job | Application
+
+input JobFilter { department: String, remote: Boolean = false }
+
+type Query {
+	jobs(filter: JobFilter, limit: Int): [Job!]!
+	job(id: ID!): Job
+	search(text: String!): [SearchResult!]!
+}
+
+type Mutation {
+	closeJob(id: ID!): Job
+}
+
+directive @cached(ttl: Int!) on FIELD
+`
+
+// introspectionTypeRef writes t the way an introspection result does
+func introspectionTypeRef(t *gqlast.Type) map[string]interface{} {
+	switch {
+	case t.NonNull:
+		inner := *t
+		inner.NonNull = false
+		return map[string]interface{}{"kind": "NON_NULL", "name": nil, "ofType": introspectionTypeRef(&inner)}
+	case t.Elem != nil:
+		return map[string]interface{}{"kind": "LIST", "name": nil, "ofType": introspectionTypeRef(t.Elem)}
+	}
+	return map[string]interface{}{"kind": "OBJECT", "name": t.NamedType, "ofType": nil}
+}
+
+func introspectionInputValues(args gqlast.ArgumentDefinitionList) []interface{} {
+	values := []interface{}{}
+	for _, arg := range args {
+		var defaultValue interface{}
+		if arg.DefaultValue != nil {
+			defaultValue = arg.DefaultValue.String()
+		}
+		values = append(values, map[string]interface{}{"name": arg.Name, "type": introspectionTypeRef(arg.Type), "defaultValue": defaultValue})
+	}
+	return values
+}
+
+// introspect answers introspectionQuery for schema, as Hub-HRMS would
+func introspect(schema *gqlast.Schema) map[string]interface{} {
+	named := func(def *gqlast.Definition) interface{} {
+		if def == nil {
+			return nil
+		}
+		return map[string]interface{}{"name": def.Name}
+	}
+
+	types := []interface{}{}
+	for _, def := range schema.Types {
+		t := map[string]interface{}{"kind": string(def.Kind), "name": def.Name}
+		switch def.Kind {
+		case gqlast.Object, gqlast.Interface:
+			fields := []interface{}{}
+			for _, field := range def.Fields {
+				fields = append(fields, map[string]interface{}{
+					"name": field.Name,
+					"args": introspectionInputValues(field.Arguments),
+					"type": introspectionTypeRef(field.Type),
+				})
+			}
+			interfaces := []interface{}{}
+			for _, name := range def.Interfaces {
+				interfaces = append(interfaces, map[string]interface{}{"kind": "INTERFACE", "name": name})
+			}
+			t["fields"], t["interfaces"] = fields, interfaces
+		case gqlast.InputObject:
+			var args gqlast.ArgumentDefinitionList
+			for _, field := range def.Fields {
+				args = append(args, &gqlast.ArgumentDefinition{Name: field.Name, Type: field.Type, DefaultValue: field.DefaultValue})
+			}
+			t["inputFields"] = introspectionInputValues(args)
+		case gqlast.Enum:
+			values := []interface{}{}
+			for _, value := range def.EnumValues {
+				values = append(values, map[string]interface{}{"name": value.Name})
+			}
+			t["enumValues"] = values
+		case gqlast.Union:
+			members := []interface{}{}
+			for _, name := range def.Types {
+				members = append(members, map[string]interface{}{"kind": "OBJECT", "name": name})
+			}
+			t["possibleTypes"] = members
+		}
+		types = append(types, t)
+	}
+
+	directives := []interface{}{}
+	for _, d := range schema.Directives {
+		locations := []string{}
+		for _, location := range d.Locations {
+			locations = append(locations, string(location))
+		}
+		directives = append(directives, map[string]interface{}{"name": d.Name, "locations": locations, "args": introspectionInputValues(d.Arguments)})
+	}
+
+	return map[string]interface{}{"__schema": map[string]interface{}{
+		"queryType":        named(schema.Query),
+		"mutationType":     named(schema.Mutation),
+		"subscriptionType": named(schema.Subscription),
+		"types":            types,
+		"directives":       directives,
+	}}
+}
+
+// newFakeSchemaServer serves fakeSDL by introspection, failing the first
+// failures requests, and counts the requests it gets
+func newFakeSchemaServer(t *testing.T, failures int64) (*HubHRMSClient, *atomic.Int64) {
+	t.Helper()
+	result := introspect(gqlparser.MustLoadSchema(&gqlast.Source{Input: fakeSDL}))
+	var requests atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GraphQLRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Query != introspectionQuery {
+			t.Errorf("unexpected query %s", req.Query)
+		}
+		if requests.Add(1) <= failures {
+			w.Write([]byte(`{"errors":[{"message":"introspection is disabled"}]}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": result})
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewHubHRMSClient(server.URL, "")
+	t.Cleanup(client.Close)
+	return client, &requests
+}
+
+func TestLoadSchema_ValidateQuery(t *testing.T) {
+	client, _ := newFakeSchemaServer(t, 0)
+	schema, err := LoadSchema(context.Background(), client)
+	if err != nil {
+		t.Fatalf("LoadSchema() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		query   string
+		wantErr string
+	}{
+		{
+			name:  "valid query",
+			query: `query GetJobs($remote: Boolean) { jobs(filter: {remote: $remote}) { id title applications(status: REVIEWING) { id status } } }`,
+		},
+		{
+			name:  "fragments, unions and custom directives",
+			query: `query Search { search(text: "go") { ... on Job { ...JobFields } ... on Application { status } } } fragment JobFields on Job { id closingDate @cached(ttl: 60) }`,
+		},
+		{
+			name:  "valid mutation",
+			query: `mutation CloseJob($id: ID!) { closeJob(id: $id) { id } }`,
+		},
+		{
+			name:    "unknown field",
+			query:   `query GetJobs { jobs { id salary } }`,
+			wantErr: `Cannot query field "salary" on type "Job"`,
+		},
+		{
+			name:    "unknown field in a fragment",
+			query:   `query GetJob { job(id: 1) { ...JobFields } } fragment JobFields on Job { department }`,
+			wantErr: `Cannot query field "department" on type "Job"`,
+		},
+		{
+			name:    "unknown argument",
+			query:   `query GetJobs { jobs(status: "open") { id } }`,
+			wantErr: `Unknown argument "status" on field "Query.jobs"`,
+		},
+		{
+			name:    "wrong argument type",
+			query:   `query GetJobs { job(id: 1) { applications(status: HIRED) { id } } }`,
+			wantErr: `Value "HIRED" does not exist in "ApplicationStatus" enum`,
+		},
+		{
+			name:    "missing required argument",
+			query:   `mutation CloseJob { closeJob { id } }`,
+			wantErr: `Field "closeJob" argument "id" of type "ID!" is required`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateQuery(schema, tt.query)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("ValidateQuery() error = %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, ErrSchemaMismatch) || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("ValidateQuery() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestValidateOperations_ReportsKnownBadQuery(t *testing.T) {
+	client, _ := newFakeSchemaServer(t, 0)
+	schema, err := client.Schema(context.Background())
+	if err != nil {
+		t.Fatalf("Schema() error = %v", err)
+	}
+
+	saved := operations
+	t.Cleanup(func() { operations = saved })
+	operations = []string{
+		`query GetJobs { jobs { id title } }`,
+		`query GetJob($id: ID!) { job(id: $id) { id salary } }`,
+		`mutation CloseJob($id: ID!) { closeJob(id: $id) { id } }`,
+		`query CountJobs { jobCount }`,
+	}
+
+	mismatches := ValidateOperations(schema)
+	if len(mismatches) != 2 || mismatches[0].Operation != "CountJobs" || mismatches[1].Operation != "GetJob" {
+		t.Fatalf("ValidateOperations() = %+v, want CountJobs and GetJob", mismatches)
+	}
+	for _, mismatch := range mismatches {
+		if !errors.Is(mismatch.Err, ErrSchemaMismatch) {
+			t.Errorf("%s error = %v, want %v", mismatch.Operation, mismatch.Err, ErrSchemaMismatch)
+		}
+	}
+}
+
+func TestHubHRMSClient_SchemaIsLazy(t *testing.T) {
+	client, requests := newFakeSchemaServer(t, 1)
+	if got := requests.Load(); got != 0 {
+		t.Fatalf("creating the client sent %d requests, want the schema loaded on first use", got)
+	}
+
+	if _, err := client.Schema(context.Background()); err == nil || !strings.Contains(err.Error(), "introspection is disabled") {
+		t.Fatalf("Schema() error = %v, want the introspection error", err)
+	}
+	first, err := client.Schema(context.Background())
+	if err != nil {
+		t.Fatalf("Schema() after a failure error = %v, want it retried", err)
+	}
+	second, err := client.Schema(context.Background())
+	if err != nil || second != first {
+		t.Fatalf("Schema() = %p, %v, want the loaded schema reused", second, err)
+	}
+	if got := requests.Load(); got != 2 {
+		t.Fatalf("introspection requests = %d, want 2", got)
+	}
+}
+
+// TestOperations_ListsEveryConstant keeps operations in step with the
+// Query and Mutation constants declared in queries.go, and checks each one
+// parses
+func TestOperations_ListsEveryConstant(t *testing.T) {
+	file, err := goparser.ParseFile(token.NewFileSet(), "queries.go", nil, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	listed := make(map[string]bool, len(operations))
+	for _, query := range operations {
+		listed[query] = true
+		if _, err := gqlparse.ParseQuery(&gqlast.Source{Input: query}); err != nil {
+			t.Errorf("%s does not parse: %v", operationName(query), err)
+		}
+	}
+
+	constants := 0
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.CONST {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			value := spec.(*ast.ValueSpec)
+			for i, name := range value.Names {
+				if !strings.HasSuffix(name.Name, "Query") && !strings.HasSuffix(name.Name, "Mutation") {
+					continue
+				}
+				lit, ok := value.Values[i].(*ast.BasicLit)
+				if !ok || lit.Kind != token.STRING {
+					t.Errorf("%s is not a string literal", name.Name)
+					continue
+				}
+				query, err := strconv.Unquote(lit.Value)
+				if err != nil {
+					t.Fatal(err)
+				}
+				constants++
+				if !listed[query] {
+					t.Errorf("%s is missing from operations in schema.go", name.Name)
+				}
+			}
+		}
+	}
+	if constants != len(operations) {
+		t.Errorf("queries.go declares %d operations but operations lists %d", constants, len(operations))
+	}
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/config"
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/middleware"
+	"hr-recruiting/internal/services"
+)
+
+// AdminHandler handles administrative tooling requests
+type AdminHandler struct {
+	emailService *services.EmailService
+	cors         *config.CORSManager
+	features     *config.FeatureFlags
+	blacklist    *services.BlacklistChecker
+	auditLog     *middleware.AuditLog
+	persisted    *gateway.PersistedQueryStore
+}
+
+// NewAdminHandler creates a new admin handler
+func NewAdminHandler(
+	emailService *services.EmailService,
+	cors *config.CORSManager,
+	features *config.FeatureFlags,
+	blacklist *services.BlacklistChecker,
+	auditLog *middleware.AuditLog,
+	persisted *gateway.PersistedQueryStore,
+) *AdminHandler {
+	return &AdminHandler{
+		emailService: emailService,
+		cors:         cors,
+		features:     features,
+		blacklist:    blacklist,
+		auditLog:     auditLog,
+		persisted:    persisted,
+	}
+}
+
+// ListFeatures returns the current state of every feature flag
+func (h *AdminHandler) ListFeatures(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"features": h.features.Snapshot(),
+	})
+}
+
+// SetFeature turns a feature flag on or off until the next restart
+func (h *AdminHandler) SetFeature(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+
+	var input struct {
+		Enabled *bool `json:"enabled"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if input.Enabled == nil {
+		respondError(w, http.StatusBadRequest, "enabled is required", nil)
+		return
+	}
+
+	if err := h.features.Set(name, *input.Enabled); errors.Is(err, config.ErrUnknownFeature) {
+		respondError(w, http.StatusNotFound, "Unknown feature flag", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"features": h.features.Snapshot(),
+	})
+}
+
+// UpdateCORSOrigins replaces the allowed CORS origins without a restart
+func (h *AdminHandler) UpdateCORSOrigins(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		AllowedOrigins []string `json:"allowedOrigins"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if len(input.AllowedOrigins) == 0 {
+		respondError(w, http.StatusBadRequest, "At least one origin is required", nil)
+		return
+	}
+
+	err := h.cors.SetOrigins(input.AllowedOrigins)
+	if errors.Is(err, config.ErrInvalidOrigin) {
+		respondError(w, http.StatusBadRequest, "Invalid origin", err)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update CORS origins", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"allowedOrigins": h.cors.Origins(),
+	})
+}
+
+// PreviewEmail renders an email template with sample data and returns the HTML
+func (h *AdminHandler) PreviewEmail(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Template string                 `json:"template"`
+		Data     map[string]interface{} `json:"data"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	templates := h.emailService.Templates()
+	html, err := templates.Render(input.Template, input.Data)
+	if errors.Is(err, services.ErrTemplateNotFound) {
+		respondJSON(w, http.StatusNotFound, ErrorResponse{
+			Error:   http.StatusText(http.StatusNotFound),
+			Message: "Unknown email template",
+			Details: map[string]interface{}{"templates": templates.Names()},
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusUnprocessableEntity, "Failed to render template", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(html))
+}
+
+// AddToBlacklist refuses future applications from an email address, for
+// candidates flagged for fraud or abuse
+func (h *AdminHandler) AddToBlacklist(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Email     string     `json:"email"`
+		Reason    string     `json:"reason"`
+		ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	entry, err := h.blacklist.Add(r.Context(), input.Email, input.Reason, input.ExpiresAt, userID(r.Context()))
+	if errors.Is(err, services.ErrInvalidBlacklistEntry) {
+		respondError(w, http.StatusBadRequest, "Invalid blacklist entry", err)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to blacklist candidate", err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, entry)
+}
+
+// ListBlacklist returns every blacklist entry
+func (h *AdminHandler) ListBlacklist(w http.ResponseWriter, r *http.Request) {
+	entries, err := h.blacklist.List(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch blacklist", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, entries)
+}
+
+// RemoveFromBlacklist accepts applications from an address again
+func (h *AdminHandler) RemoveFromBlacklist(w http.ResponseWriter, r *http.Request) {
+	err := h.blacklist.Remove(r.Context(), chi.URLParam(r, "id"))
+	if errors.Is(err, services.ErrBlacklistEntryNotFound) {
+		respondError(w, http.StatusNotFound, "Blacklist entry not found", nil)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to remove blacklist entry", err)
+		return
+	}
+
+	respondSuccess(w, "Blacklist entry deleted successfully", nil)
+}
+
+// ListPersistedQueries returns the queries the GraphQL proxy resolves from
+// their hashes
+func (h *AdminHandler) ListPersistedQueries(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.persisted.List())
+}
+
+// RegisterPersistedQuery adds a query clients may then send by hash. The
+// hash is optional; when given it must match the query.
+func (h *AdminHandler) RegisterPersistedQuery(w http.ResponseWriter, r *http.Request) {
+	var input gateway.PersistedQuery
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	hash, err := h.persisted.Register(input.Query, input.Hash)
+	if errors.Is(err, gateway.ErrPersistedQueryHashMismatch) || errors.Is(err, gateway.ErrInvalidQuery) {
+		respondError(w, http.StatusBadRequest, "Invalid persisted query", err)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to save persisted query", err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, gateway.PersistedQuery{Hash: hash, Query: input.Query})
+}
+
+// Audit log tail sizes
+const (
+	defaultAuditLines = 100
+	maxAuditLines     = 1000
+)
+
+// GetAuditLog returns the most recent audit log entries, 100 by default or
+// ?lines=N up to 1000
+func (h *AdminHandler) GetAuditLog(w http.ResponseWriter, r *http.Request) {
+	lines := defaultAuditLines
+	if n, err := strconv.Atoi(r.URL.Query().Get("lines")); err == nil && n > 0 {
+		lines = min(n, maxAuditLines)
+	}
+
+	entries, err := h.auditLog.Tail(lines)
+	if errors.Is(err, middleware.ErrAuditLogNotFile) {
+		respondError(w, http.StatusNotFound, "Audit log is written to stdout; set AUDIT_LOG_PATH to read it here", nil)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to read audit log", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"entries": entries,
+	})
+}
@@ -0,0 +1,262 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/config"
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/services"
+)
+
+func TestAdminHandler_PreviewEmail(t *testing.T) {
+	h := NewAdminHandler(services.NewEmailService(""), nil, nil, nil, nil, nil)
+
+	preview := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.PreviewEmail(rec, httptest.NewRequest(http.MethodPost, "/api/v1/admin/email-preview", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := preview(`{"template":"rejection","data":{"CandidateName":"<img src=x onerror=alert(1)>","JobTitle":"Go Engineer"}}`)
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/html; charset=utf-8" {
+		t.Fatalf("status = %d, content type = %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	if html := rec.Body.String(); strings.Contains(html, "<img") || !strings.Contains(html, "Dear &lt;img src=x onerror=alert(1)&gt;,") {
+		t.Fatalf("preview did not escape the candidate name:\n%s", html)
+	}
+
+	rec = preview(`{"template":"missing"}`)
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), `"status_update"`) {
+		t.Fatalf("unknown template: status = %d, body = %s; want 404 listing the templates", rec.Code, rec.Body)
+	}
+
+	if rec := preview(`{"template":`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("invalid body: status = %d, want 400", rec.Code)
+	}
+}
+
+func TestAdminHandler_UpdateCORSOrigins(t *testing.T) {
+	manager := config.NewCORSManager([]string{"https://careers.example.com"}, filepath.Join(t.TempDir(), config.CORSOverrideFile))
+	h := NewAdminHandler(services.NewEmailService(""), manager, nil, nil, nil, nil)
+
+	r := chi.NewRouter()
+	r.Use(manager.Handler())
+	r.Put("/api/v1/admin/cors", h.UpdateCORSOrigins)
+	r.Get("/api/v1/jobs", func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, map[string]interface{}{"jobs": []interface{}{}})
+	})
+
+	listJobs := func() string {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/jobs", nil)
+		req.Header.Set("Origin", "https://new.example.com")
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec.Header().Get("Access-Control-Allow-Origin")
+	}
+	update := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/api/v1/admin/cors", strings.NewReader(body)))
+		return rec
+	}
+
+	if got := listJobs(); got != "" {
+		t.Fatalf("new origin allowed before the update: %q", got)
+	}
+
+	rec := update(`{"allowedOrigins":["https://careers.example.com","https://New.example.com"]}`)
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"allowedOrigins":["https://careers.example.com","https://new.example.com"]}`+"\n" {
+		t.Fatalf("update = %d %s, want the normalized origins", rec.Code, rec.Body)
+	}
+	if got := listJobs(); got != "https://new.example.com" {
+		t.Fatalf("Access-Control-Allow-Origin = %q after the update, want the new origin without a restart", got)
+	}
+
+	for name, body := range map[string]string{
+		"invalid origin": `{"allowedOrigins":["new.example.com"]}`,
+		"no origins":     `{"allowedOrigins":[]}`,
+		"malformed":      `{"allowedOrigins":`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if rec := update(body); rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+			if got := listJobs(); got != "https://new.example.com" {
+				t.Fatal("a rejected update changed the allowed origins")
+			}
+		})
+	}
+}
+
+func TestAdminHandler_Features(t *testing.T) {
+	features := &config.FeatureFlags{}
+	features.EmailNotifications.Store(true)
+	h := NewAdminHandler(services.NewEmailService(""), nil, features, nil, nil, nil)
+
+	r := chi.NewRouter()
+	r.Get("/api/v1/admin/features", h.ListFeatures)
+	r.Put("/api/v1/admin/features/{name}", h.SetFeature)
+	send := func(method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec
+	}
+
+	rec := send(http.MethodGet, "/api/v1/admin/features", "")
+	want := `{"features":{"aiScoring":false,"applicationDeduplication":false,"emailNotifications":true,"webhookDelivery":false}}` + "\n"
+	if rec.Code != http.StatusOK || rec.Body.String() != want {
+		t.Fatalf("list = %d %s, want %s", rec.Code, rec.Body, want)
+	}
+
+	rec = send(http.MethodPut, "/api/v1/admin/features/aiScoring", `{"enabled":true}`)
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"aiScoring":true`) {
+		t.Fatalf("toggle = %d %s, want aiScoring on", rec.Code, rec.Body)
+	}
+	if !features.AIScoring.Load() {
+		t.Fatal("AIScoring was not switched on for the handlers sharing the flags")
+	}
+
+	for name, tt := range map[string]struct {
+		target, body string
+		wantStatus   int
+	}{
+		"unknown flag":    {target: "/api/v1/admin/features/darkMode", body: `{"enabled":true}`, wantStatus: http.StatusNotFound},
+		"missing enabled": {target: "/api/v1/admin/features/aiScoring", body: `{}`, wantStatus: http.StatusBadRequest},
+		"malformed":       {target: "/api/v1/admin/features/aiScoring", body: `{"enabled":`, wantStatus: http.StatusBadRequest},
+	} {
+		t.Run(name, func(t *testing.T) {
+			if rec := send(http.MethodPut, tt.target, tt.body); rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if !features.AIScoring.Load() {
+				t.Fatal("a rejected toggle changed aiScoring")
+			}
+		})
+	}
+}
+
+func TestAdminHandler_Blacklist(t *testing.T) {
+	fake, client := newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+		switch req.Query {
+		case gateway.BlacklistCandidateMutation:
+			input, _ := req.Variables["input"].(map[string]interface{})
+			return map[string]interface{}{"blacklistCandidate": map[string]interface{}{
+				"id": "bl-1", "email": input["email"], "reason": input["reason"], "createdBy": input["createdBy"],
+				"createdAt": "2026-03-01T09:00:00Z",
+			}}
+		case gateway.GetBlacklistQuery:
+			return map[string]interface{}{"blacklist": []interface{}{
+				map[string]interface{}{"id": "bl-1", "email": "fraud@example.com", "reason": "Fraud", "createdAt": "2026-03-01T09:00:00Z"},
+			}}
+		case gateway.RemoveBlacklistEntryMutation:
+			return map[string]interface{}{"removeBlacklistEntry": req.Variables["id"] == "bl-1"}
+		}
+		return map[string]interface{}{}
+	})
+	h := NewAdminHandler(services.NewEmailService(""), nil, nil, services.NewBlacklistChecker(client), nil, nil)
+
+	r := chi.NewRouter()
+	r.Post("/admin/blacklist", h.AddToBlacklist)
+	r.Get("/admin/blacklist", h.ListBlacklist)
+	r.Delete("/admin/blacklist/{id}", h.RemoveFromBlacklist)
+
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{name: "add", method: http.MethodPost, path: "/admin/blacklist", body: `{"email":"Fraud@Example.com","reason":"Fraudulent documents"}`, wantStatus: http.StatusCreated},
+		{name: "add without reason", method: http.MethodPost, path: "/admin/blacklist", body: `{"email":"fraud@example.com"}`, wantStatus: http.StatusBadRequest},
+		{name: "add expiring in the past", method: http.MethodPost, path: "/admin/blacklist", body: `{"email":"fraud@example.com","reason":"Fraud","expiresAt":"2020-01-01T00:00:00Z"}`, wantStatus: http.StatusBadRequest},
+		{name: "list", method: http.MethodGet, path: "/admin/blacklist", wantStatus: http.StatusOK},
+		{name: "remove", method: http.MethodDelete, path: "/admin/blacklist/bl-1", wantStatus: http.StatusOK},
+		{name: "remove missing", method: http.MethodDelete, path: "/admin/blacklist/bl-9", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, asUser(httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)), "admin-1", "admin"))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+		})
+	}
+
+	if fake.sent(gateway.BlacklistCandidateMutation) != 1 {
+		t.Fatal("invalid entries were sent to Hub-HRMS")
+	}
+	fake.mu.Lock()
+	input, _ := fake.requests[0].Variables["input"].(map[string]interface{})
+	fake.mu.Unlock()
+	if input["email"] != "fraud@example.com" || input["createdBy"] != "admin-1" {
+		t.Fatalf("input = %v, want the normalised address added by the caller", input)
+	}
+}
+
+func TestAdminHandler_PersistedQueries(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "persisted-queries.json")
+	store, err := gateway.NewPersistedQueryStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := NewAdminHandler(services.NewEmailService(""), nil, nil, nil, nil, store)
+
+	r := chi.NewRouter()
+	r.Get("/admin/persisted-queries", h.ListPersistedQueries)
+	r.Post("/admin/persisted-queries", h.RegisterPersistedQuery)
+
+	const query = `query GetJobs { jobs { id title } }`
+	sum := sha256.Sum256([]byte(query))
+	hash := hex.EncodeToString(sum[:])
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "register", body: `{"query":"` + query + `"}`, wantStatus: http.StatusCreated},
+		{name: "register with hash", body: `{"query":"` + query + `","sha256Hash":"` + hash + `"}`, wantStatus: http.StatusCreated},
+		{name: "hash mismatch", body: `{"query":"query Other { jobs { id } }","sha256Hash":"` + hash + `"}`, wantStatus: http.StatusBadRequest},
+		{name: "no operation", body: `{"query":"fragment JobFields on Job { id }"}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid body", body: `{`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, asUser(httptest.NewRequest(http.MethodPost, "/admin/persisted-queries", strings.NewReader(tt.body)), "admin-1", "admin"))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus == http.StatusCreated && !strings.Contains(rec.Body.String(), hash) {
+				t.Fatalf("body = %s, want the query's hash", rec.Body)
+			}
+		})
+	}
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, asUser(httptest.NewRequest(http.MethodGet, "/admin/persisted-queries", nil), "admin-1", "admin"))
+	var list []gateway.PersistedQuery
+	json.NewDecoder(rec.Body).Decode(&list)
+	if rec.Code != http.StatusOK || len(list) != 1 || list[0].Hash != hash || list[0].Query != query {
+		t.Fatalf("list = %d %v, want the registered query", rec.Code, list)
+	}
+
+	// Registered queries survive a restart
+	reloaded, err := gateway.NewPersistedQueryStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := reloaded.Get(hash); !ok || got != query {
+		t.Fatalf("reloaded store has %q, %v, want the query", got, ok)
+	}
+}
@@ -1,22 +1,50 @@
 package handlers
 
 import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/services"
 )
 
 // AnalyticsHandler handles analytics-related requests
 type AnalyticsHandler struct {
-	client *gateway.HubHRMSClient
+	client         *gateway.HubHRMSClient
+	exchangeRates  *services.ExchangeRateService
+	baseCurrency   string
+	pipelineEvents *services.PipelineEventBus
+	feedback       *services.ScoringFeedbackService
+	experience     *services.CandidateExperienceService
+	logger         *slog.Logger
 }
 
 // NewAnalyticsHandler creates a new analytics handler
-func NewAnalyticsHandler(client *gateway.HubHRMSClient) *AnalyticsHandler {
-	return &AnalyticsHandler{client: client}
+func NewAnalyticsHandler(
+	client *gateway.HubHRMSClient,
+	exchangeRates *services.ExchangeRateService,
+	baseCurrency string,
+	pipelineEvents *services.PipelineEventBus,
+	logger *slog.Logger,
+) *AnalyticsHandler {
+	return &AnalyticsHandler{
+		client:         client,
+		exchangeRates:  exchangeRates,
+		baseCurrency:   baseCurrency,
+		pipelineEvents: pipelineEvents,
+		feedback:       services.NewScoringFeedbackService(client),
+		experience:     services.NewCandidateExperienceService(client),
+		logger:         logger,
+	}
 }
 
 // GetMetrics returns recruitment metrics
@@ -49,13 +77,43 @@ func (h *AnalyticsHandler) GetMetrics(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	resp, err := h.client.Query(ctx, gateway.GetRecruitmentMetricsQuery, variables)
+	// The dashboard shows the pipeline alongside the metrics; the two are
+	// fetched concurrently and either is shown if the other fails
+	pipelineVariables := make(map[string]interface{})
+	if jobID := r.URL.Query().Get("jobId"); jobID != "" {
+		pipelineVariables["jobId"] = jobID
+	}
+
+	responses, failures, err := h.client.QueryAll(ctx, []gateway.NamedQuery{
+		{Name: "metrics", Query: gateway.GetRecruitmentMetricsQuery, Variables: variables},
+		{Name: "pipeline", Query: gateway.GetApplicationPipelineQuery, Variables: pipelineVariables},
+	})
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to fetch metrics", err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, resp.Data)
+	result := map[string]interface{}{
+		"recruitmentMetrics":  nil,
+		"applicationPipeline": nil,
+	}
+	if resp, ok := responses["metrics"]; ok {
+		result["recruitmentMetrics"] = lookup(resp.Data, "recruitmentMetrics")
+	}
+	if resp, ok := responses["pipeline"]; ok {
+		result["applicationPipeline"] = lookup(resp.Data, "applicationPipeline")
+	}
+
+	if len(failures) > 0 {
+		partial := make(map[string]string, len(failures))
+		for name, queryErr := range failures {
+			h.logger.Warn("failed to fetch dashboard metric", "metric", name, "error", queryErr)
+			partial[name] = queryErr.Error()
+		}
+		result["errors"] = partial
+	}
+
+	respondJSON(w, http.StatusOK, result)
 }
 
 // GetJobPerformance returns performance metrics for a specific job
@@ -81,6 +139,59 @@ func (h *AnalyticsHandler) GetJobPerformance(w http.ResponseWriter, r *http.Requ
 	respondJSON(w, http.StatusOK, resp.Data)
 }
 
+// variantResult is the performance of one variant of a job's A/B test
+type variantResult struct {
+	Variant        string  `json:"variant"`
+	Views          int     `json:"views"`
+	Applications   int     `json:"applications"`
+	ConversionRate float64 `json:"conversionRate"`
+}
+
+// GetJobABResults returns views, applications and view-to-apply conversion
+// for each variant of a job's A/B test
+func (h *AnalyticsHandler) GetJobABResults(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID := chi.URLParam(r, "id")
+
+	resp, err := h.client.Query(ctx, gateway.GetJobABResultsQuery, map[string]interface{}{
+		"jobId": jobID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch A/B test results", err)
+		return
+	}
+
+	rows, _ := lookup(resp.Data, "jobABResults").([]interface{})
+	if len(rows) == 0 {
+		respondError(w, http.StatusNotFound, "Job has no A/B test", nil)
+		return
+	}
+
+	variants := make([]variantResult, 0, len(rows))
+	for _, row := range rows {
+		views, _ := lookup(row, "views").(float64)
+		applications, _ := lookup(row, "applications").(float64)
+
+		result := variantResult{
+			Variant:      strings.ToLower(lookupString(row, "variant")),
+			Views:        int(views),
+			Applications: int(applications),
+		}
+		if views > 0 {
+			result.ConversionRate = applications / views
+		}
+		variants = append(variants, result)
+	}
+	sort.Slice(variants, func(i, j int) bool {
+		return variants[i].Variant < variants[j].Variant
+	})
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"jobId":    jobID,
+		"variants": variants,
+	})
+}
+
 // GetPipeline returns the application pipeline
 func (h *AnalyticsHandler) GetPipeline(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -101,13 +212,90 @@ func (h *AnalyticsHandler) GetPipeline(w http.ResponseWriter, r *http.Request) {
 	respondJSON(w, http.StatusOK, resp.Data)
 }
 
-// GetTrends returns application trends over time
+// pipelineKeepAlive is how often an idle stream sends a comment line, so
+// proxies keep the connection open and disconnects are noticed
+const pipelineKeepAlive = 30 * time.Second
+
+// StreamPipeline streams application status changes as Server-Sent Events
+// until the client disconnects or the request deadline passes, at which point
+// EventSource clients reconnect. An optional jobId query parameter limits the
+// stream to one job.
+func (h *AnalyticsHandler) StreamPipeline(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID := r.URL.Query().Get("jobId")
+	controller := http.NewResponseController(w)
+
+	events, unsubscribe := h.pipelineEvents.Subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	if err := controller.Flush(); err != nil {
+		h.logger.Error("pipeline stream cannot be flushed", "error", err)
+		return
+	}
+
+	keepAlive := time.NewTicker(pipelineKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			if jobID != "" && event.JobID != jobID {
+				continue
+			}
+			data, err := json.Marshal(event)
+			if err != nil {
+				h.logger.Error("failed to encode pipeline event", "error", err)
+				continue
+			}
+			if _, err := fmt.Fprintf(w, "data: %s\n\n", data); err != nil {
+				return
+			}
+		}
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// trendGroupBy maps the granularity parameter to Hub-HRMS's TrendGroupBy
+var trendGroupBy = map[string]string{
+	"daily":   "DAY",
+	"weekly":  "WEEK",
+	"monthly": "MONTH",
+}
+
+// Trend paging limits
+const (
+	defaultTrendPageSize = 100
+	maxTrendPageSize     = 1000
+	// maxDailyTrendRange is the longest range served one point per day
+	maxDailyTrendRange = 2 * 365 * 24 * time.Hour
+)
+
+// GetTrends returns application counts over time, one point per day, week
+// or month as set by ?granularity=. Long ranges are paged with ?page= and
+// ?pageSize=.
 func (h *AnalyticsHandler) GetTrends(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	query := r.URL.Query()
 
 	// Parse date range
-	startDateStr := r.URL.Query().Get("startDate")
-	endDateStr := r.URL.Query().Get("endDate")
+	startDateStr := query.Get("startDate")
+	endDateStr := query.Get("endDate")
 
 	endDate := time.Now()
 	startDate := endDate.AddDate(0, -3, 0) // Default to last 3 months
@@ -122,30 +310,501 @@ func (h *AnalyticsHandler) GetTrends(w http.ResponseWriter, r *http.Request) {
 			endDate = parsed
 		}
 	}
+	if endDate.Before(startDate) {
+		respondError(w, http.StatusBadRequest, "endDate must not be before startDate", nil)
+		return
+	}
+
+	granularity := strings.ToLower(query.Get("granularity"))
+	if granularity == "" {
+		granularity = "daily"
+	}
+	groupBy, ok := trendGroupBy[granularity]
+	if !ok {
+		respondError(w, http.StatusBadRequest, "granularity must be daily, weekly or monthly", nil)
+		return
+	}
+	if granularity == "daily" && endDate.Sub(startDate) > maxDailyTrendRange {
+		respondError(w, http.StatusBadRequest, "Daily trends are limited to a 2 year range; use weekly or monthly granularity", nil)
+		return
+	}
+
+	var err error
+	page, pageSize := 1, defaultTrendPageSize
+	if value := query.Get("page"); value != "" {
+		if page, err = strconv.Atoi(value); err != nil || page < 1 {
+			respondError(w, http.StatusBadRequest, "page must be a positive integer", nil)
+			return
+		}
+	}
+	if value := query.Get("pageSize"); value != "" {
+		if pageSize, err = strconv.Atoi(value); err != nil || pageSize < 1 || pageSize > maxTrendPageSize {
+			respondError(w, http.StatusBadRequest, fmt.Sprintf("pageSize must be between 1 and %d", maxTrendPageSize), nil)
+			return
+		}
+	}
 
 	variables := map[string]interface{}{
 		"dateRange": map[string]string{
 			"start": startDate.Format(time.RFC3339),
 			"end":   endDate.Format(time.RFC3339),
 		},
+		"groupBy": groupBy,
+		"limit":   pageSize,
+		"offset":  (page - 1) * pageSize,
 	}
 
-	resp, err := h.client.Query(ctx, gateway.GetRecruitmentMetricsQuery, variables)
+	resp, err := h.client.Query(ctx, gateway.GetApplicationTrendQuery, variables)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to fetch trends", err)
 		return
 	}
 
-	// Extract just the trend data
-	data := resp.Data.(map[string]interface{})
-	if metrics, ok := data["recruitmentMetrics"].(map[string]interface{}); ok {
-		if trends, ok := metrics["applicationTrend"]; ok {
-			respondJSON(w, http.StatusOK, map[string]interface{}{
-				"applicationTrend": trends,
-			})
-			return
+	points, _ := lookup(resp.Data, "applicationTrend", "points").([]interface{})
+	if points == nil {
+		points = []interface{}{}
+	}
+	totalPoints, _ := lookup(resp.Data, "applicationTrend", "totalPoints").(float64)
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"data":        points,
+		"granularity": granularity,
+		"startDate":   startDate.Format("2006-01-02"),
+		"endDate":     endDate.Format("2006-01-02"),
+		"totalPoints": int(totalPoints),
+		"page":        page,
+		"pageSize":    pageSize,
+	})
+}
+
+// sourceConversion is the application funnel for one UTM source
+type sourceConversion struct {
+	Source         string  `json:"source"`
+	Medium         string  `json:"medium,omitempty"`
+	Campaign       string  `json:"campaign,omitempty"`
+	Applications   int     `json:"applications"`
+	Interviewed    int     `json:"interviewed"`
+	Hired          int     `json:"hired"`
+	InterviewRate  float64 `json:"interviewRate"`
+	ConversionRate float64 `json:"conversionRate"`
+}
+
+// GetSources returns applications per UTM source with the share of each that
+// reached interview and hire, most applications first. Applications without
+// UTM parameters are reported under the "direct" source.
+func (h *AnalyticsHandler) GetSources(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	startDateStr := r.URL.Query().Get("startDate")
+	endDateStr := r.URL.Query().Get("endDate")
+
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -30)
+
+	if startDateStr != "" {
+		if parsed, err := time.Parse("2006-01-02", startDateStr); err == nil {
+			startDate = parsed
+		}
+	}
+	if endDateStr != "" {
+		if parsed, err := time.Parse("2006-01-02", endDateStr); err == nil {
+			endDate = parsed
 		}
 	}
 
-	respondJSON(w, http.StatusOK, resp.Data)
-}
\ No newline at end of file
+	variables := map[string]interface{}{
+		"dateRange": map[string]string{
+			"start": startDate.Format(time.RFC3339),
+			"end":   endDate.Format(time.RFC3339),
+		},
+	}
+	if jobID := r.URL.Query().Get("jobId"); jobID != "" {
+		variables["jobId"] = jobID
+	}
+
+	resp, err := h.client.Query(ctx, gateway.GetApplicationsBySourceQuery, variables)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch application sources", err)
+		return
+	}
+
+	rows, _ := lookup(resp.Data, "applicationsBySource").([]interface{})
+	sources := make([]sourceConversion, 0, len(rows))
+	total := 0
+	for _, row := range rows {
+		applications, _ := lookup(row, "applications").(float64)
+		interviewed, _ := lookup(row, "interviewed").(float64)
+		hired, _ := lookup(row, "hired").(float64)
+
+		source := sourceConversion{
+			Source:       lookupString(row, "utmSource"),
+			Medium:       lookupString(row, "utmMedium"),
+			Campaign:     lookupString(row, "utmCampaign"),
+			Applications: int(applications),
+			Interviewed:  int(interviewed),
+			Hired:        int(hired),
+		}
+		if source.Source == "" {
+			source.Source = "direct"
+		}
+		if applications > 0 {
+			source.InterviewRate = interviewed / applications
+			source.ConversionRate = hired / applications
+		}
+		total += source.Applications
+		sources = append(sources, source)
+	}
+	sort.SliceStable(sources, func(i, j int) bool {
+		return sources[i].Applications > sources[j].Applications
+	})
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"startDate":         startDate.Format("2006-01-02"),
+		"endDate":           endDate.Format("2006-01-02"),
+		"totalApplications": total,
+		"sources":           sources,
+	})
+}
+
+// topReferrers is how many referrers the referral report ranks
+const topReferrers = 10
+
+// GetReferrals returns how many referred candidates applied and were hired,
+// overall and for the most successful referrers
+func (h *AnalyticsHandler) GetReferrals(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	startDateStr := r.URL.Query().Get("startDate")
+	endDateStr := r.URL.Query().Get("endDate")
+
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, -3, 0) // Default to last 3 months
+
+	if startDateStr != "" {
+		if parsed, err := time.Parse("2006-01-02", startDateStr); err == nil {
+			startDate = parsed
+		}
+	}
+	if endDateStr != "" {
+		if parsed, err := time.Parse("2006-01-02", endDateStr); err == nil {
+			endDate = parsed
+		}
+	}
+
+	filters := map[string]interface{}{
+		"dateRange": map[string]string{
+			"start": startDate.Format(time.RFC3339),
+			"end":   endDate.Format(time.RFC3339),
+		},
+	}
+	if jobID := r.URL.Query().Get("jobId"); jobID != "" {
+		filters["jobId"] = jobID
+	}
+
+	referrals, err := fetchReferrals(ctx, h.client, filters)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch referrals", err)
+		return
+	}
+
+	summary := summarizeReferrals(referrals, topReferrers)
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"startDate":    startDate.Format("2006-01-02"),
+		"endDate":      endDate.Format("2006-01-02"),
+		"totals":       summary.Totals,
+		"topReferrers": summary.TopReferrers,
+	})
+}
+
+// GetDepartments returns hiring metrics for each department over a date
+// range, defaulting to the last 30 days
+func (h *AnalyticsHandler) GetDepartments(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	startDateStr := r.URL.Query().Get("startDate")
+	endDateStr := r.URL.Query().Get("endDate")
+
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -30)
+
+	if startDateStr != "" {
+		if parsed, err := time.Parse("2006-01-02", startDateStr); err == nil {
+			startDate = parsed
+		}
+	}
+	if endDateStr != "" {
+		if parsed, err := time.Parse("2006-01-02", endDateStr); err == nil {
+			endDate = parsed
+		}
+	}
+
+	resp, err := h.client.Query(ctx, gateway.GetDepartmentMetricsQuery, map[string]interface{}{
+		"dateRange": map[string]string{
+			"start": startDate.Format(time.RFC3339),
+			"end":   endDate.Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch department metrics", err)
+		return
+	}
+
+	departments, _ := lookup(resp.Data, "departmentMetrics").([]interface{})
+	if departments == nil {
+		departments = []interface{}{}
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"startDate":   startDate.Format("2006-01-02"),
+		"endDate":     endDate.Format("2006-01-02"),
+		"departments": departments,
+	})
+}
+
+// GetScoringAccuracy returns how often recruiters agreed with AI scores over
+// a date range, defaulting to the last 90 days, by job and experience level
+func (h *AnalyticsHandler) GetScoringAccuracy(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	startDateStr := r.URL.Query().Get("startDate")
+	endDateStr := r.URL.Query().Get("endDate")
+
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -90)
+
+	if startDateStr != "" {
+		if parsed, err := time.Parse("2006-01-02", startDateStr); err == nil {
+			startDate = parsed
+		}
+	}
+	if endDateStr != "" {
+		if parsed, err := time.Parse("2006-01-02", endDateStr); err == nil {
+			endDate = parsed
+		}
+	}
+
+	accuracy, err := h.feedback.Accuracy(ctx, startDate, endDate)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch scoring feedback", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"startDate":         startDate.Format("2006-01-02"),
+		"endDate":           endDate.Format("2006-01-02"),
+		"overall":           accuracy.Overall,
+		"byJob":             accuracy.ByJob,
+		"byExperienceLevel": accuracy.ByLevel,
+	})
+}
+
+// GetCandidateExperience aggregates candidate experience for applications
+// made over a date range, defaulting to the last 30 days, optionally for one
+// job
+func (h *AnalyticsHandler) GetCandidateExperience(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	startDateStr := r.URL.Query().Get("startDate")
+	endDateStr := r.URL.Query().Get("endDate")
+
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -30)
+
+	if startDateStr != "" {
+		if parsed, err := time.Parse("2006-01-02", startDateStr); err == nil {
+			startDate = parsed
+		}
+	}
+	if endDateStr != "" {
+		if parsed, err := time.Parse("2006-01-02", endDateStr); err == nil {
+			endDate = parsed
+		}
+	}
+
+	summary, err := h.experience.Summary(ctx, startDate, endDate, r.URL.Query().Get("jobId"))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch application timelines", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"startDate": startDate.Format("2006-01-02"),
+		"endDate":   endDate.Format("2006-01-02"),
+		"summary":   summary,
+	})
+}
+
+// funnelStage is one pipeline stage of a department's hiring funnel
+type funnelStage struct {
+	Stage string `json:"stage"`
+	Count int    `json:"count"`
+	// ConversionRate is the share of the previous stage's applications
+	// that reached this one
+	ConversionRate float64 `json:"conversionRate"`
+}
+
+// GetDepartment returns hiring metrics for one department, including how
+// many applications reached each pipeline stage. The department name is
+// URL-encoded in the path, since names may contain spaces.
+func (h *AnalyticsHandler) GetDepartment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	department, err := url.PathUnescape(chi.URLParam(r, "department"))
+	if err != nil || strings.TrimSpace(department) == "" {
+		respondError(w, http.StatusBadRequest, "Invalid department name", err)
+		return
+	}
+
+	startDateStr := r.URL.Query().Get("startDate")
+	endDateStr := r.URL.Query().Get("endDate")
+
+	endDate := time.Now()
+	startDate := endDate.AddDate(0, 0, -30)
+
+	if startDateStr != "" {
+		if parsed, err := time.Parse("2006-01-02", startDateStr); err == nil {
+			startDate = parsed
+		}
+	}
+	if endDateStr != "" {
+		if parsed, err := time.Parse("2006-01-02", endDateStr); err == nil {
+			endDate = parsed
+		}
+	}
+
+	resp, err := h.client.Query(ctx, gateway.GetDepartmentDetailQuery, map[string]interface{}{
+		"department": department,
+		"dateRange": map[string]string{
+			"start": startDate.Format(time.RFC3339),
+			"end":   endDate.Format(time.RFC3339),
+		},
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch department metrics", err)
+		return
+	}
+
+	detail, ok := lookup(resp.Data, "departmentDetail").(map[string]interface{})
+	if !ok {
+		respondError(w, http.StatusNotFound, "Department not found", nil)
+		return
+	}
+
+	stages, _ := detail["funnel"].([]interface{})
+	funnel := make([]funnelStage, 0, len(stages))
+	for i, stage := range stages {
+		count, _ := lookup(stage, "count").(float64)
+		entry := funnelStage{Stage: lookupString(stage, "stage"), Count: int(count)}
+		if i > 0 && funnel[i-1].Count > 0 {
+			entry.ConversionRate = float64(entry.Count) / float64(funnel[i-1].Count)
+		}
+		funnel = append(funnel, entry)
+	}
+	detail["funnel"] = funnel
+	detail["startDate"] = startDate.Format("2006-01-02")
+	detail["endDate"] = endDate.Format("2006-01-02")
+
+	respondJSON(w, http.StatusOK, detail)
+}
+
+// departmentSalaries summarizes salary ranges for one department
+type departmentSalaries struct {
+	Department string  `json:"department"`
+	Min        float64 `json:"min"`
+	Max        float64 `json:"max"`
+	Median     float64 `json:"median"`
+	JobCount   int     `json:"jobCount"`
+}
+
+// GetSalaryRanges returns salary ranges per department in the base currency
+func (h *AnalyticsHandler) GetSalaryRanges(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	rates, err := h.exchangeRates.Rates(ctx)
+	if err != nil {
+		respondError(w, http.StatusServiceUnavailable, "Exchange rates unavailable", err)
+		return
+	}
+
+	variables := map[string]interface{}{
+		"filters": map[string]interface{}{"status": "PUBLISHED"},
+		"limit":   1000,
+		"offset":  0,
+	}
+
+	resp, err := h.client.Query(ctx, gateway.GetJobsQuery, variables)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch jobs", err)
+		return
+	}
+
+	jobs, _ := lookup(resp.Data, "jobs").([]interface{})
+	byDepartment := make(map[string]*departmentSalaries)
+	midpoints := make(map[string][]float64)
+	skipped := 0
+
+	for _, job := range jobs {
+		minSalary, okMin := lookup(job, "salaryRange", "min").(float64)
+		maxSalary, okMax := lookup(job, "salaryRange", "max").(float64)
+		currency := lookupString(job, "salaryRange", "currency")
+		if !okMin || !okMax || currency == "" {
+			continue
+		}
+
+		minConverted, err := rates.Convert(minSalary, currency, h.baseCurrency)
+		if err != nil {
+			skipped++
+			continue
+		}
+		maxConverted, _ := rates.Convert(maxSalary, currency, h.baseCurrency)
+
+		department := lookupString(job, "department")
+		summary, ok := byDepartment[department]
+		if !ok {
+			summary = &departmentSalaries{Department: department, Min: minConverted, Max: maxConverted}
+			byDepartment[department] = summary
+		}
+		if minConverted < summary.Min {
+			summary.Min = minConverted
+		}
+		if maxConverted > summary.Max {
+			summary.Max = maxConverted
+		}
+		summary.JobCount++
+		midpoints[department] = append(midpoints[department], (minConverted+maxConverted)/2)
+	}
+
+	departments := make([]*departmentSalaries, 0, len(byDepartment))
+	for department, summary := range byDepartment {
+		summary.Median = median(midpoints[department])
+		departments = append(departments, summary)
+	}
+	sort.Slice(departments, func(i, j int) bool {
+		return departments[i].Department < departments[j].Department
+	})
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"baseCurrency": h.baseCurrency,
+		"departments":  departments,
+		"skippedJobs":  skipped,
+		"exchangeRates": map[string]interface{}{
+			"timestamp": rates.Timestamp.Format(time.RFC3339),
+			"source":    rates.Source,
+		},
+	})
+}
+
+// median returns the median of values, or 0 for an empty slice
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
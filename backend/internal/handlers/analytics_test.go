@@ -0,0 +1,647 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/services"
+)
+
+// newTestAnalyticsHandler returns an analytics handler backed by a fake
+// Hub-HRMS and, when ratesJSON is set, an exchange rate API serving it
+func newTestAnalyticsHandler(t *testing.T, respond func(req gateway.GraphQLRequest) interface{}, ratesJSON string) (*AnalyticsHandler, *fakeHubHRMS) {
+	t.Helper()
+	fake, client := newFakeHubHRMS(t, respond)
+
+	ratesURL := ""
+	if ratesJSON != "" {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, ratesJSON)
+		}))
+		t.Cleanup(server.Close)
+		ratesURL = server.URL
+	}
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	handler := NewAnalyticsHandler(client, services.NewExchangeRateService(ratesURL, ""), "EUR", services.NewPipelineEventBus(), logger)
+	return handler, fake
+}
+
+func salaryJob(department string, min, max float64, currency string) map[string]interface{} {
+	return map[string]interface{}{
+		"department":  department,
+		"salaryRange": map[string]interface{}{"min": min, "max": max, "currency": currency},
+	}
+}
+
+func TestAnalyticsHandler_GetSalaryRanges(t *testing.T) {
+	jobs := []interface{}{
+		salaryJob("Engineering", 50000, 70000, "EUR"),
+		salaryJob("Engineering", 80000, 100000, "USD"),
+		salaryJob("Engineering", 40000, 60000, "GBP"),
+		salaryJob("Sales", 30000, 40000, "gbp"),
+		salaryJob("Sales", 1, 2, "XYZ"),
+		map[string]interface{}{"department": "Sales", "salaryRange": map[string]interface{}{"min": 1000}},
+	}
+	handler, _ := newTestAnalyticsHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		return map[string]interface{}{"jobs": jobs}
+	}, `{"base":"USD","timestamp":1791500400,"rates":{"EUR":0.9,"GBP":0.75}}`)
+
+	rec := httptest.NewRecorder()
+	handler.GetSalaryRanges(rec, httptest.NewRequest(http.MethodGet, "/api/v1/analytics/salary-ranges", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+
+	var body struct {
+		BaseCurrency  string               `json:"baseCurrency"`
+		Departments   []departmentSalaries `json:"departments"`
+		SkippedJobs   int                  `json:"skippedJobs"`
+		ExchangeRates map[string]string    `json:"exchangeRates"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if body.BaseCurrency != "EUR" || body.SkippedJobs != 1 || body.ExchangeRates["timestamp"] == "" || body.ExchangeRates["source"] == "" {
+		t.Fatalf("body = %s", rec.Body)
+	}
+	if len(body.Departments) != 2 {
+		t.Fatalf("departments = %+v, want Engineering and Sales", body.Departments)
+	}
+
+	// USD 80-100k is EUR 72-90k, GBP 40-60k is EUR 48-72k; midpoints 60k, 81k and 60k
+	want := []departmentSalaries{
+		{Department: "Engineering", Min: 48000, Max: 90000, Median: 60000, JobCount: 3},
+		{Department: "Sales", Min: 36000, Max: 48000, Median: 42000, JobCount: 1},
+	}
+	for i, got := range body.Departments {
+		if got.Department != want[i].Department || got.JobCount != want[i].JobCount ||
+			math.Abs(got.Min-want[i].Min) > 0.01 || math.Abs(got.Max-want[i].Max) > 0.01 || math.Abs(got.Median-want[i].Median) > 0.01 {
+			t.Errorf("departments[%d] = %+v, want %+v", i, got, want[i])
+		}
+	}
+}
+
+func TestAnalyticsHandler_GetSalaryRanges_NoRates(t *testing.T) {
+	handler, fake := newTestAnalyticsHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		return map[string]interface{}{"jobs": []interface{}{}}
+	}, "")
+
+	rec := httptest.NewRecorder()
+	handler.GetSalaryRanges(rec, httptest.NewRequest(http.MethodGet, "/api/v1/analytics/salary-ranges", nil))
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503 without exchange rates", rec.Code)
+	}
+	if fake.sent(gateway.GetJobsQuery) != 0 {
+		t.Fatal("jobs were fetched without exchange rates")
+	}
+}
+
+func TestMedian(t *testing.T) {
+	tests := []struct {
+		values []float64
+		want   float64
+	}{
+		{values: nil, want: 0},
+		{values: []float64{3}, want: 3},
+		{values: []float64{5, 1, 3}, want: 3},
+		{values: []float64{4, 1, 3, 2}, want: 2.5},
+	}
+	for _, tt := range tests {
+		if got := median(tt.values); got != tt.want {
+			t.Errorf("median(%v) = %v, want %v", tt.values, got, tt.want)
+		}
+	}
+}
+
+// flushRecorder is a ResponseRecorder that signals each flush, so a test
+// knows when a streamed event has been written
+type flushRecorder struct {
+	*httptest.ResponseRecorder
+	flushed chan struct{}
+}
+
+func (r *flushRecorder) Flush() {
+	r.ResponseRecorder.Flush()
+	r.flushed <- struct{}{}
+}
+
+func TestAnalyticsHandler_StreamPipeline(t *testing.T) {
+	h, _ := newTestAnalyticsHandler(t, func(gateway.GraphQLRequest) interface{} { return map[string]interface{}{} }, "")
+
+	ctx, disconnect := context.WithCancel(context.Background())
+	defer disconnect()
+	rec := &flushRecorder{ResponseRecorder: httptest.NewRecorder(), flushed: make(chan struct{})}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/analytics/pipeline/stream?jobId=job-1", nil).WithContext(ctx)
+		h.StreamPipeline(rec, req)
+	}()
+
+	// The headers are flushed once the handler has subscribed
+	<-rec.flushed
+
+	h.pipelineEvents.Publish(services.PipelineEvent{ApplicationID: "app-1", OldStatus: "APPLIED", NewStatus: "SCREENING", JobID: "job-1"})
+	<-rec.flushed
+	// Events for other jobs are filtered out of the stream, without a flush
+	h.pipelineEvents.Publish(services.PipelineEvent{ApplicationID: "app-2", NewStatus: "OFFER", JobID: "job-2"})
+	h.pipelineEvents.Publish(services.PipelineEvent{ApplicationID: "app-3", NewStatus: "HIRED", JobID: "job-1"})
+	<-rec.flushed
+
+	disconnect()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("stream did not end when the client disconnected")
+	}
+
+	if got := rec.Header().Get("Content-Type"); got != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", got)
+	}
+	if got := rec.Header().Get("Cache-Control"); got != "no-cache" {
+		t.Fatalf("Cache-Control = %q, want no-cache", got)
+	}
+	want := `data: {"applicationId":"app-1","oldStatus":"APPLIED","newStatus":"SCREENING","jobId":"job-1"}` + "\n\n" +
+		`data: {"applicationId":"app-3","newStatus":"HIRED","jobId":"job-1"}` + "\n\n"
+	if got := rec.Body.String(); got != want {
+		t.Fatalf("stream = %q, want %q", got, want)
+	}
+}
+
+func TestAnalyticsHandler_GetSources(t *testing.T) {
+	h, fake := newTestAnalyticsHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		return map[string]interface{}{"applicationsBySource": []interface{}{
+			map[string]interface{}{"utmSource": "indeed", "utmMedium": "jobboard", "applications": 20, "interviewed": 5, "hired": 1},
+			map[string]interface{}{"utmSource": "", "applications": 10, "interviewed": 4, "hired": 2},
+			map[string]interface{}{"utmSource": "linkedin", "utmMedium": "social", "utmCampaign": "spring", "applications": 40, "interviewed": 10, "hired": 4},
+			map[string]interface{}{"utmSource": "email", "applications": 0, "interviewed": 0, "hired": 0},
+		}}
+	}, "")
+
+	rec := httptest.NewRecorder()
+	h.GetSources(rec, httptest.NewRequest(http.MethodGet, "/api/v1/analytics/sources?startDate=2026-01-01&endDate=2026-03-31&jobId=job-1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	var body struct {
+		StartDate         string             `json:"startDate"`
+		EndDate           string             `json:"endDate"`
+		TotalApplications int                `json:"totalApplications"`
+		Sources           []sourceConversion `json:"sources"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.StartDate != "2026-01-01" || body.EndDate != "2026-03-31" || body.TotalApplications != 70 {
+		t.Fatalf("range %s to %s, total %d; want 2026-01-01 to 2026-03-31 and 70", body.StartDate, body.EndDate, body.TotalApplications)
+	}
+
+	// Sorted by applications, with unattributed applications as direct
+	want := []sourceConversion{
+		{Source: "linkedin", Medium: "social", Campaign: "spring", Applications: 40, Interviewed: 10, Hired: 4, InterviewRate: 0.25, ConversionRate: 0.1},
+		{Source: "indeed", Medium: "jobboard", Applications: 20, Interviewed: 5, Hired: 1, InterviewRate: 0.25, ConversionRate: 0.05},
+		{Source: "direct", Applications: 10, Interviewed: 4, Hired: 2, InterviewRate: 0.4, ConversionRate: 0.2},
+		{Source: "email"},
+	}
+	if len(body.Sources) != len(want) {
+		t.Fatalf("sources = %+v, want %+v", body.Sources, want)
+	}
+	for i := range want {
+		if body.Sources[i] != want[i] {
+			t.Fatalf("sources[%d] = %+v, want %+v", i, body.Sources[i], want[i])
+		}
+	}
+
+	fake.mu.Lock()
+	variables := fake.requests[0].Variables
+	fake.mu.Unlock()
+	dateRange, _ := variables["dateRange"].(map[string]interface{})
+	if variables["jobId"] != "job-1" || dateRange["start"] != "2026-01-01T00:00:00Z" || dateRange["end"] != "2026-03-31T00:00:00Z" {
+		t.Fatalf("variables = %v, want jobId and the date range passed through", variables)
+	}
+}
+
+func TestAnalyticsHandler_GetJobABResults(t *testing.T) {
+	h, _ := newTestAnalyticsHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Variables["jobId"] != "job-1" {
+			return map[string]interface{}{"jobABResults": []interface{}{}}
+		}
+		return map[string]interface{}{"jobABResults": []interface{}{
+			map[string]interface{}{"variant": "B", "views": 200, "applications": 30},
+			map[string]interface{}{"variant": "A", "views": 0, "applications": 0},
+		}}
+	}, "")
+	r := chi.NewRouter()
+	r.Get("/analytics/jobs/{id}/ab-results", h.GetJobABResults)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/analytics/jobs/job-1/ab-results", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	var response struct {
+		JobID    string          `json:"jobId"`
+		Variants []variantResult `json:"variants"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	want := []variantResult{
+		{Variant: "a", Views: 0, Applications: 0, ConversionRate: 0},
+		{Variant: "b", Views: 200, Applications: 30, ConversionRate: 0.15},
+	}
+	if response.JobID != "job-1" || len(response.Variants) != 2 || response.Variants[0] != want[0] || response.Variants[1] != want[1] {
+		t.Fatalf("response = %+v, want %+v", response, want)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/analytics/jobs/job-2/ab-results", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status for a job without a test = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+func TestAnalyticsHandler_GetTrends(t *testing.T) {
+	h, fake := newTestAnalyticsHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.GetApplicationTrendQuery {
+			return map[string]interface{}{}
+		}
+		return map[string]interface{}{"applicationTrend": map[string]interface{}{
+			"points": []interface{}{
+				map[string]interface{}{"date": "2024-01-01", "count": 4.0},
+				map[string]interface{}{"date": "2024-01-02", "count": 7.0},
+			},
+			"totalPoints": 731.0,
+		}}
+	}, "")
+
+	getTrends := func(query string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.GetTrends(rec, httptest.NewRequest(http.MethodGet, "/api/v1/analytics/trends?"+query, nil))
+		return rec
+	}
+	lastVariables := func() map[string]interface{} {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		return fake.requests[len(fake.requests)-1].Variables
+	}
+
+	t.Run("defaults", func(t *testing.T) {
+		rec := getTrends("")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		var body struct {
+			Data        []map[string]interface{} `json:"data"`
+			Granularity string                   `json:"granularity"`
+			StartDate   string                   `json:"startDate"`
+			EndDate     string                   `json:"endDate"`
+			TotalPoints int                      `json:"totalPoints"`
+			Page        int                      `json:"page"`
+			PageSize    int                      `json:"pageSize"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		now := time.Now()
+		if body.Granularity != "daily" || body.Page != 1 || body.PageSize != defaultTrendPageSize ||
+			body.EndDate != now.Format("2006-01-02") || body.StartDate != now.AddDate(0, -3, 0).Format("2006-01-02") {
+			t.Fatalf("body = %+v, want daily points for the last 3 months, first page", body)
+		}
+		if len(body.Data) != 2 || body.TotalPoints != 731 {
+			t.Fatalf("body = %+v, want the points and their total", body)
+		}
+
+		variables := lastVariables()
+		if variables["groupBy"] != "DAY" || variables["limit"] != float64(defaultTrendPageSize) || variables["offset"] != 0.0 {
+			t.Fatalf("variables = %v, want DAY, the default page size and no offset", variables)
+		}
+	})
+
+	t.Run("granularity and page", func(t *testing.T) {
+		for granularity, groupBy := range map[string]string{"weekly": "WEEK", "MONTHLY": "MONTH", "daily": "DAY"} {
+			rec := getTrends("startDate=2024-01-01&endDate=2024-12-31&granularity=" + granularity + "&page=3&pageSize=50")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("%s: status = %d, want 200: %s", granularity, rec.Code, rec.Body)
+			}
+			variables := lastVariables()
+			if variables["groupBy"] != groupBy || variables["limit"] != 50.0 || variables["offset"] != 100.0 {
+				t.Fatalf("%s: variables = %v, want %s from offset 100", granularity, variables, groupBy)
+			}
+		}
+	})
+
+	tests := []struct {
+		name     string
+		query    string
+		wantBody string
+	}{
+		{name: "unknown granularity", query: "granularity=hourly", wantBody: "granularity must be daily, weekly or monthly"},
+		{name: "daily over two years", query: "startDate=2020-01-01&endDate=2024-01-01", wantBody: "Daily trends are limited to a 2 year range"},
+		{name: "default granularity over two years", query: "startDate=2020-01-01&endDate=2024-01-01&granularity=", wantBody: "limited to a 2 year range"},
+		{name: "end before start", query: "startDate=2024-02-01&endDate=2024-01-01", wantBody: "endDate must not be before startDate"},
+		{name: "page zero", query: "page=0", wantBody: "page must be a positive integer"},
+		{name: "page size too large", query: "pageSize=1001", wantBody: "pageSize must be between 1 and 1000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := fake.sent(gateway.GetApplicationTrendQuery)
+			rec := getTrends(tt.query)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400", rec.Code)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("body = %s, want %q", rec.Body, tt.wantBody)
+			}
+			if fake.sent(gateway.GetApplicationTrendQuery) != before {
+				t.Fatal("invalid request was sent to Hub-HRMS")
+			}
+		})
+	}
+
+	t.Run("weekly over two years", func(t *testing.T) {
+		if rec := getTrends("startDate=2020-01-01&endDate=2024-01-01&granularity=weekly"); rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+	})
+}
+
+func TestAnalyticsHandler_GetDepartments(t *testing.T) {
+	h, fake := newTestAnalyticsHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.GetDepartmentMetricsQuery {
+			return map[string]interface{}{}
+		}
+		return map[string]interface{}{"departmentMetrics": []interface{}{
+			map[string]interface{}{
+				"department": "Engineering", "openJobs": 12.0, "totalApplications": 340.0,
+				"avgTimeToHire": 28.5, "conversionRate": 0.04, "topCandidateCount": 17.0,
+			},
+			map[string]interface{}{
+				"department": "Data Science", "openJobs": 3.0, "totalApplications": 85.0,
+				"avgTimeToHire": 35.0, "conversionRate": 0.02, "topCandidateCount": 4.0,
+			},
+		}}
+	}, "")
+
+	rec := httptest.NewRecorder()
+	h.GetDepartments(rec, httptest.NewRequest(http.MethodGet, "/api/v1/analytics/departments?startDate=2024-01-01&endDate=2024-06-30", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+
+	var body struct {
+		StartDate   string `json:"startDate"`
+		EndDate     string `json:"endDate"`
+		Departments []struct {
+			Department        string  `json:"department"`
+			OpenJobs          int     `json:"openJobs"`
+			TotalApplications int     `json:"totalApplications"`
+			AvgTimeToHire     float64 `json:"avgTimeToHire"`
+			ConversionRate    float64 `json:"conversionRate"`
+			TopCandidateCount int     `json:"topCandidateCount"`
+		} `json:"departments"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.StartDate != "2024-01-01" || body.EndDate != "2024-06-30" || len(body.Departments) != 2 {
+		t.Fatalf("body = %+v, want both departments over the requested range", body)
+	}
+	engineering := body.Departments[0]
+	if engineering.Department != "Engineering" || engineering.OpenJobs != 12 || engineering.TotalApplications != 340 ||
+		engineering.AvgTimeToHire != 28.5 || engineering.ConversionRate != 0.04 || engineering.TopCandidateCount != 17 {
+		t.Fatalf("engineering = %+v", engineering)
+	}
+
+	fake.mu.Lock()
+	dateRange, _ := fake.requests[0].Variables["dateRange"].(map[string]interface{})
+	fake.mu.Unlock()
+	if dateRange["start"] != "2024-01-01T00:00:00Z" || dateRange["end"] != "2024-06-30T00:00:00Z" {
+		t.Fatalf("dateRange = %v, want the requested range", dateRange)
+	}
+
+	t.Run("no departments", func(t *testing.T) {
+		h, _ := newTestAnalyticsHandler(t, func(req gateway.GraphQLRequest) interface{} {
+			return map[string]interface{}{"departmentMetrics": nil}
+		}, "")
+		rec := httptest.NewRecorder()
+		h.GetDepartments(rec, httptest.NewRequest(http.MethodGet, "/api/v1/analytics/departments", nil))
+		if !strings.Contains(rec.Body.String(), `"departments":[]`) {
+			t.Fatalf("body = %s, want an empty list", rec.Body)
+		}
+	})
+}
+
+func TestAnalyticsHandler_GetDepartment(t *testing.T) {
+	h, fake := newTestAnalyticsHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.GetDepartmentDetailQuery {
+			return map[string]interface{}{}
+		}
+		department, _ := req.Variables["department"].(string)
+		if department == "Legal" {
+			return map[string]interface{}{"departmentDetail": nil}
+		}
+		return map[string]interface{}{"departmentDetail": map[string]interface{}{
+			"department": department, "openJobs": 3.0, "totalApplications": 200.0,
+			"funnel": []interface{}{
+				map[string]interface{}{"stage": "APPLIED", "count": 200.0},
+				map[string]interface{}{"stage": "SCREENING", "count": 80.0},
+				map[string]interface{}{"stage": "INTERVIEW", "count": 20.0},
+				map[string]interface{}{"stage": "OFFER", "count": 0.0},
+				map[string]interface{}{"stage": "HIRED", "count": 0.0},
+			},
+		}}
+	}, "")
+
+	r := chi.NewRouter()
+	r.Get("/analytics/departments/{department}", h.GetDepartment)
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get("/analytics/departments/Data%20Science?startDate=2024-01-01&endDate=2024-06-30")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		Department string        `json:"department"`
+		StartDate  string        `json:"startDate"`
+		EndDate    string        `json:"endDate"`
+		Funnel     []funnelStage `json:"funnel"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Department != "Data Science" || body.StartDate != "2024-01-01" || body.EndDate != "2024-06-30" {
+		t.Fatalf("body = %+v, want Data Science over the requested range", body)
+	}
+	want := []funnelStage{
+		{Stage: "APPLIED", Count: 200},
+		{Stage: "SCREENING", Count: 80, ConversionRate: 0.4},
+		{Stage: "INTERVIEW", Count: 20, ConversionRate: 0.25},
+		{Stage: "OFFER", Count: 0, ConversionRate: 0},
+		// Nothing reached the previous stage, so there is no rate
+		{Stage: "HIRED", Count: 0, ConversionRate: 0},
+	}
+	if !reflect.DeepEqual(body.Funnel, want) {
+		t.Fatalf("funnel = %+v, want %+v", body.Funnel, want)
+	}
+
+	t.Run("encoded names", func(t *testing.T) {
+		for path, want := range map[string]string{
+			"/analytics/departments/R%26D":             "R&D",
+			"/analytics/departments/Sales%2FMarketing": "Sales/Marketing",
+		} {
+			if rec := get(path); rec.Code != http.StatusOK {
+				t.Fatalf("GET %s: status = %d, want 200", path, rec.Code)
+			}
+			fake.mu.Lock()
+			department := fake.requests[len(fake.requests)-1].Variables["department"]
+			fake.mu.Unlock()
+			if department != want {
+				t.Fatalf("GET %s: department = %v, want %q", path, department, want)
+			}
+		}
+	})
+
+	t.Run("unknown department", func(t *testing.T) {
+		if rec := get("/analytics/departments/Legal"); rec.Code != http.StatusNotFound {
+			t.Fatalf("status = %d, want 404", rec.Code)
+		}
+	})
+
+	t.Run("blank name", func(t *testing.T) {
+		if rec := get("/analytics/departments/%20"); rec.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want 400", rec.Code)
+		}
+	})
+}
+
+func TestAnalyticsHandler_GetScoringAccuracy(t *testing.T) {
+	record := func(agrees bool, jobID string, years float64) map[string]interface{} {
+		return map[string]interface{}{
+			"agreesWithScore": agrees,
+			"application": map[string]interface{}{
+				"job":       map[string]interface{}{"id": jobID, "title": "Backend Engineer"},
+				"candidate": map[string]interface{}{"yearsOfExperience": years},
+			},
+		}
+	}
+	h, fake := newTestAnalyticsHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.GetScoringFeedbackQuery {
+			return map[string]interface{}{}
+		}
+		return map[string]interface{}{"scoringFeedback": []interface{}{
+			record(true, "job-1", 1), record(false, "job-1", 7), record(true, "job-1", 8), record(true, "job-2", 12),
+		}}
+	}, "")
+
+	rec := httptest.NewRecorder()
+	h.GetScoringAccuracy(rec, httptest.NewRequest(http.MethodGet, "/api/v1/analytics/scoring/accuracy?startDate=2026-01-01&endDate=2026-06-30", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+
+	var body struct {
+		StartDate         string                           `json:"startDate"`
+		EndDate           string                           `json:"endDate"`
+		Overall           services.ScoringAgreement        `json:"overall"`
+		ByJob             []services.JobScoringAgreement   `json:"byJob"`
+		ByExperienceLevel []services.LevelScoringAgreement `json:"byExperienceLevel"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.StartDate != "2026-01-01" || body.EndDate != "2026-06-30" || body.Overall.Feedback != 4 || body.Overall.AgreementRate != 0.75 {
+		t.Fatalf("body = %+v, want four pieces of feedback, three agreeing", body)
+	}
+	if len(body.ByJob) != 2 || body.ByJob[0].JobID != "job-1" || body.ByJob[0].Agreed != 2 || body.ByJob[0].Feedback != 3 {
+		t.Fatalf("byJob = %+v, want job-1 first with two of three agreeing", body.ByJob)
+	}
+	if len(body.ByExperienceLevel) != 3 || body.ByExperienceLevel[1].Level != "senior" || body.ByExperienceLevel[1].AgreementRate != 0.5 {
+		t.Fatalf("byExperienceLevel = %+v, want junior, senior and lead", body.ByExperienceLevel)
+	}
+
+	fake.mu.Lock()
+	dateRange, _ := fake.requests[0].Variables["dateRange"].(map[string]interface{})
+	fake.mu.Unlock()
+	if dateRange["start"] != "2026-01-01T00:00:00Z" || dateRange["end"] != "2026-06-30T00:00:00Z" {
+		t.Fatalf("dateRange = %v, want the requested range", dateRange)
+	}
+}
+
+func TestAnalyticsHandler_GetCandidateExperience(t *testing.T) {
+	journey := func(firstAction, closed string, note string) map[string]interface{} {
+		return map[string]interface{}{
+			"id":          "app",
+			"appliedDate": "2026-03-02T09:00:00Z",
+			"timeline": []interface{}{
+				map[string]interface{}{"type": "STATUS_CHANGE", "status": "SCREENING", "note": note, "timestamp": firstAction},
+				map[string]interface{}{"type": "STATUS_CHANGE", "status": "REJECTED", "note": "Role filled", "timestamp": closed},
+			},
+		}
+	}
+	h, fake := newTestAnalyticsHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.GetApplicationJourneysQuery {
+			return map[string]interface{}{}
+		}
+		// One answered in two hours and closed in ten days, one answered
+		// in ten days and closed in thirty
+		return map[string]interface{}{"applications": []interface{}{
+			journey("2026-03-02T11:00:00Z", "2026-03-12T09:00:00Z", "Strong CV"),
+			journey("2026-03-12T09:00:00Z", "2026-04-01T09:00:00Z", ""),
+		}}
+	}, "")
+
+	rec := httptest.NewRecorder()
+	h.GetCandidateExperience(rec, httptest.NewRequest(http.MethodGet, "/api/v1/analytics/candidate-experience?startDate=2026-03-01&endDate=2026-03-31&jobId=job-1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+
+	var body struct {
+		StartDate string                              `json:"startDate"`
+		EndDate   string                              `json:"endDate"`
+		Summary   services.CandidateExperienceSummary `json:"summary"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	want := services.CandidateExperienceSummary{
+		Applications:                  2,
+		Responded:                     2,
+		TimeToFirstActionP50Hours:     2,
+		TimeToFirstActionP95Hours:     240,
+		StatusChangesWithNotesPercent: 75,
+		AveragePipelineDays:           20,
+		AverageScore:                  body.Summary.AverageScore,
+	}
+	if body.StartDate != "2026-03-01" || body.EndDate != "2026-03-31" || body.Summary != want {
+		t.Fatalf("body = %+v, want %+v over the requested range", body, want)
+	}
+	if body.Summary.AverageScore <= 50 || body.Summary.AverageScore >= 100 {
+		t.Fatalf("average score = %v, want between the fast and slow journeys", body.Summary.AverageScore)
+	}
+
+	fake.mu.Lock()
+	filters, _ := fake.requests[0].Variables["filters"].(map[string]interface{})
+	fake.mu.Unlock()
+	if filters["jobId"] != "job-1" || filters["dateFrom"] != "2026-03-01" || filters["dateTo"] != "2026-03-31" {
+		t.Fatalf("filters = %v, want the job and range", filters)
+	}
+}
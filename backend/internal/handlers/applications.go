@@ -1,33 +1,140 @@
 package handlers
 
 import (
+	"context"
+	"encoding/csv"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
 
+	"hr-recruiting/internal/config"
 	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/middleware"
 	"hr-recruiting/internal/services"
+	"hr-recruiting/internal/util"
 )
 
 // ApplicationHandler handles application-related requests
 type ApplicationHandler struct {
-	client        *gateway.HubHRMSClient
-	uploadService *services.UploadService
-	emailService  *services.EmailService
+	client         *gateway.HubHRMSClient
+	uploadService  *services.UploadService
+	emailService   *services.EmailService
+	emailQueue     services.EmailEnqueuer
+	dedupStore     services.DeduplicationStore
+	dedupWindow    time.Duration
+	webhooks       *services.WebhookService
+	pipelineEvents *services.PipelineEventBus
+
+	strictTransitions  bool
+	stages             *services.PipelineStageService
+	features           *config.FeatureFlags
+	scoringConcurrency int
+
+	privacyTokens *util.TokenSigner
+	baseURL       string
+	calendar      *services.CalendarService
+	gcal          *services.GoogleCalendarService
+	preferences   *services.NotificationPreferenceStore
+	skills        *services.SkillNormalizer
+	shareLinks    *services.ShareLinkService
+	chat          *services.NotificationBroadcaster
+	resumes       *services.ResumeParser
+	validator     *services.ApplicationValidator
+	duplicates    *services.CandidateDuplicateChecker
+	blacklist     *services.BlacklistChecker
+	rejections    *services.RuleEngine
+	drafts        *services.ApplicationDraftStore
+	feedback      *services.ScoringFeedbackService
+	experience    *services.CandidateExperienceService
+	logger        *slog.Logger
+}
+
+// ApplicationHandlerOptions holds the dependencies of an ApplicationHandler
+type ApplicationHandlerOptions struct {
+	Client         *gateway.HubHRMSClient
+	UploadService  *services.UploadService
+	EmailService   *services.EmailService
+	EmailQueue     *services.EmailQueue
+	DedupStore     services.DeduplicationStore
+	DedupWindow    time.Duration
+	Webhooks       *services.WebhookService
+	PipelineEvents *services.PipelineEventBus
+
+	// StrictTransitions rejects status changes the pipeline does not allow
+	StrictTransitions bool
+	Stages            *services.PipelineStageService
+	Features          *config.FeatureFlags
+	// ScoringConcurrency bounds parallel scoring in bulk requests (minimum 1)
+	ScoringConcurrency int
+
+	PrivacyTokens *util.TokenSigner
+	// BaseURL is the recruiting app's address, used to link to applications
+	BaseURL     string
+	Calendar    *services.CalendarService
+	GCal        *services.GoogleCalendarService
+	Preferences *services.NotificationPreferenceStore
+	Skills      *services.SkillNormalizer
+	ShareLinks  *services.ShareLinkService
+	Chat        *services.NotificationBroadcaster
+	// Logger receives failures the client is not told about (default
+	// slog.Default())
+	Logger *slog.Logger
 }
 
 // NewApplicationHandler creates a new application handler
-func NewApplicationHandler(
-	client *gateway.HubHRMSClient,
-	uploadService *services.UploadService,
-	emailService *services.EmailService,
-) *ApplicationHandler {
+func NewApplicationHandler(options ApplicationHandlerOptions) *ApplicationHandler {
+	if options.ScoringConcurrency < 1 {
+		options.ScoringConcurrency = 1
+	}
+	if options.Logger == nil {
+		options.Logger = slog.Default()
+	}
+
 	return &ApplicationHandler{
-		client:        client,
-		uploadService: uploadService,
-		emailService:  emailService,
+		client:         options.Client,
+		uploadService:  options.UploadService,
+		emailService:   options.EmailService,
+		emailQueue:     options.EmailQueue,
+		dedupStore:     options.DedupStore,
+		dedupWindow:    options.DedupWindow,
+		webhooks:       options.Webhooks,
+		pipelineEvents: options.PipelineEvents,
+
+		strictTransitions:  options.StrictTransitions,
+		stages:             options.Stages,
+		features:           options.Features,
+		scoringConcurrency: options.ScoringConcurrency,
+
+		privacyTokens: options.PrivacyTokens,
+		baseURL:       strings.TrimSuffix(options.BaseURL, "/"),
+		calendar:      options.Calendar,
+		gcal:          options.GCal,
+		preferences:   options.Preferences,
+		skills:        options.Skills,
+		shareLinks:    options.ShareLinks,
+		chat:          options.Chat,
+		resumes:       services.NewResumeParser(options.Client, options.UploadService),
+		validator:     services.NewApplicationValidator(options.Client),
+		duplicates:    services.NewCandidateDuplicateChecker(options.Client),
+		blacklist:     services.NewBlacklistChecker(options.Client),
+		rejections:    services.NewRuleEngine(options.Client),
+		drafts:        services.NewApplicationDraftStore(options.DedupStore, services.ApplicationDraftTTL),
+		feedback:      services.NewScoringFeedbackService(options.Client),
+		experience:    services.NewCandidateExperienceService(options.Client),
+		logger:        options.Logger,
 	}
 }
 
@@ -42,13 +149,37 @@ func (h *ApplicationHandler) SubmitApplication(w http.ResponseWriter, r *http.Re
 	}
 	defer r.Body.Close()
 
-	// Validate required fields
-	requiredFields := []string{"jobId", "firstName", "lastName", "email", "phone", "resumeUrl", "currentLocation", "availability"}
-	for _, field := range requiredFields {
-		if _, ok := input[field]; !ok {
-			respondError(w, http.StatusBadRequest, "Missing required field: "+field, nil)
-			return
-		}
+	// WithDraft has already merged in the saved draft
+	draftID, _ := input["draftId"].(string)
+	delete(input, "draftId")
+
+	// Refuse blacklisted applicants without saying why
+	email, _ := input["email"].(string)
+	blocked, err := h.blacklist.IsBlocked(ctx, email)
+	if err != nil {
+		// Fail open: a lost application is worse than a screening miss
+		h.logger.Warn("blacklist check failed", "error", err)
+	}
+	if blocked {
+		respondError(w, http.StatusForbidden, "Applications from this email address are not accepted", nil)
+		return
+	}
+
+	// Validate against the job's application schema
+	jobID, _ := input["jobId"].(string)
+	schema, err := h.validator.Schema(ctx, jobID)
+	if err != nil {
+		// The base fields are still checked, so the job's extras are skipped
+		h.logger.Warn("failed to load application schema", "job_id", jobID, "error", err)
+	}
+	if errs := h.validator.ValidateAgainstSchema(schema, input); len(errs) > 0 {
+		respondJSON(w, http.StatusUnprocessableEntity, ErrorResponse{
+			Error:   http.StatusText(http.StatusUnprocessableEntity),
+			Message: "Application is missing required fields or has invalid values",
+			Details: errs,
+			Status:  http.StatusUnprocessableEntity,
+		})
+		return
 	}
 
 	// Set default values
@@ -56,6 +187,63 @@ func (h *ApplicationHandler) SubmitApplication(w http.ResponseWriter, r *http.Re
 		input["willingToRelocate"] = false
 	}
 
+	// Record skills under their canonical names
+	if skills, ok := input["skills"].([]interface{}); ok {
+		names := make([]string, 0, len(skills))
+		for _, skill := range skills {
+			if name, ok := skill.(string); ok {
+				names = append(names, name)
+			}
+		}
+		input["skills"] = h.skills.NormalizeAll(names)
+	}
+
+	// Reject repeat submissions of the same candidate to the same job
+	email = services.NormalizeEmail(email)
+	input["email"] = email
+	dedupKey := services.ApplicationDedupKey(jobID, email)
+	deduplicate := h.features.ApplicationDeduplication.Load()
+	var existingID string
+	var found bool
+	if deduplicate {
+		existingID, found, err = h.dedupStore.Get(ctx, dedupKey)
+		if err != nil {
+			// Fail open: a duplicate is better than a lost application
+			h.logger.Warn("deduplication lookup failed", "error", err)
+		}
+	}
+	if found {
+		respondJSON(w, http.StatusConflict, ErrorResponse{
+			Error:   http.StatusText(http.StatusConflict),
+			Message: "An application for this job has already been submitted",
+			Details: map[string]string{"existingApplicationId": existingID},
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+
+	// Attach the application to the candidate's existing record, if any
+	match, err := h.duplicates.Check(ctx, email, jobID)
+	if err != nil {
+		// Fail open: a duplicate candidate is better than a lost application
+		h.logger.Warn("candidate duplicate check failed", "error", err)
+	}
+	if deduplicate && match != nil && match.ExistingApplicationID != "" {
+		respondJSON(w, http.StatusConflict, ErrorResponse{
+			Error:   http.StatusText(http.StatusConflict),
+			Message: "An application for this job has already been submitted",
+			Details: map[string]string{"existingApplicationId": match.ExistingApplicationID},
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+	if match != nil {
+		input["candidateId"] = match.CandidateID
+	}
+	if source := extractSource(input); source != nil {
+		input["source"] = source
+	}
+
 	variables := map[string]interface{}{
 		"input": input,
 	}
@@ -66,28 +254,152 @@ func (h *ApplicationHandler) SubmitApplication(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	firstName, _ := input["firstName"].(string)
+	lastName, _ := input["lastName"].(string)
+	if applicationID := lookupString(resp.Data, "submitApplication", "id"); applicationID != "" {
+		if deduplicate {
+			if err := h.dedupStore.Set(ctx, dedupKey, applicationID, h.dedupWindow); err != nil {
+				h.logger.Warn("failed to record deduplication key", "error", err)
+			}
+		}
+
+		h.webhooks.Publish(services.EventApplicationSubmitted, services.ApplicationEventData{
+			ApplicationID: applicationID,
+			JobID:         jobID,
+			Status:        lookupString(resp.Data, "submitApplication", "status"),
+		})
+
+		h.chat.Notify(services.ApplicationNotification{
+			Event:         services.ChatNewApplication,
+			ApplicationID: applicationID,
+			CandidateName: strings.TrimSpace(firstName + " " + lastName),
+			JobTitle:      lookupString(resp.Data, "submitApplication", "job", "title"),
+			Status:        lookupString(resp.Data, "submitApplication", "status"),
+			URL:           h.applicationURL(applicationID),
+		})
+
+		resumeURL, _ := input["resumeUrl"].(string)
+		if key, ok := h.uploadService.KeyFromURL(resumeURL); ok {
+			go h.indexResume(applicationID, key)
+		}
+
+		go h.autoReject(applicationID, jobID, lookupString(resp.Data, "submitApplication", "status"), input)
+
+		if draftID != "" {
+			if err := h.drafts.Delete(ctx, draftID); err != nil {
+				h.logger.Warn("failed to delete draft", "draft_id", draftID, "error", err)
+			}
+		}
+	}
+
 	// Send confirmation email asynchronously
-	go h.emailService.SendApplicationConfirmation(
-		input["email"].(string),
-		input["firstName"].(string),
-		input["jobId"].(string),
-	)
+	h.notify(ctx, services.NotifyNewApplications, services.ApplicationConfirmationEmail(email, firstName, jobID))
 
 	respondJSON(w, http.StatusCreated, resp.Data)
 }
 
+// notify queues a notification email unless notifications are switched off
+// or the signed-in user has disabled emails of this type. Emails a candidate
+// explicitly asked for, such as data exports, bypass this.
+func (h *ApplicationHandler) notify(ctx context.Context, kind services.NotificationType, job services.EmailJob) {
+	if !h.features.EmailNotifications.Load() {
+		return
+	}
+
+	if id := userID(ctx); id != "" {
+		// Callers may notify after the response is sent
+		preference, err := h.preferences.Get(context.WithoutCancel(ctx), id)
+		if err != nil {
+			// Fail open: an unwanted email is better than a missed one
+			h.logger.Warn("failed to load notification preferences", "user_id", id, "error", err)
+		} else if !preference.Allows(kind) {
+			return
+		}
+	}
+
+	h.emailQueue.Enqueue(job)
+}
+
+// utmFields are the campaign parameters recorded as an application's source
+var utmFields = []string{"utmSource", "utmMedium", "utmCampaign", "utmContent"}
+
+// extractSource removes the UTM fields from a submission and returns those
+// that are set as the application's source, or nil if none are
+func extractSource(input map[string]interface{}) map[string]interface{} {
+	var source map[string]interface{}
+	for _, field := range utmFields {
+		value, _ := input[field].(string)
+		delete(input, field)
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		if source == nil {
+			source = make(map[string]interface{})
+		}
+		source[field] = value
+	}
+	return source
+}
+
 // ListApplications returns a list of applications
 func (h *ApplicationHandler) ListApplications(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
+	// Parse pagination
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid pagination cursor", err)
+		return
+	}
+
+	variables := map[string]interface{}{
+		"limit":  limit,
+		"offset": offset,
+	}
+	if filters := applicationFilters(r); len(filters) > 0 {
+		variables["filters"] = filters
+	}
+
+	resp, err := h.client.Query(ctx, gateway.GetApplicationsQuery, variables)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch applications", err)
+		return
+	}
+
+	countResp, err := h.client.Query(ctx, gateway.CountApplicationsQuery, map[string]interface{}{
+		"filters": variables["filters"],
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to count applications", err)
+		return
+	}
+
+	items, _ := lookup(resp.Data, "applications").([]interface{})
+	if items == nil {
+		items = []interface{}{}
+	}
+	total := extractCount(countResp.Data, "applicationsCount")
+
+	setNextCursor(w, items, limit, offset)
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+
+	respondJSON(w, http.StatusOK, PaginatedResponse{
+		Items:   items,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: offset+len(items) < total,
+	})
+}
+
+// applicationFilters builds Hub-HRMS application filters from the query string
+func applicationFilters(r *http.Request) map[string]interface{} {
 	// Parse query parameters
 	jobID := r.URL.Query().Get("jobId")
 	status := r.URL.Query().Get("status")
 	dateFrom := r.URL.Query().Get("dateFrom")
 	dateTo := r.URL.Query().Get("dateTo")
 	minScoreStr := r.URL.Query().Get("minScore")
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
 
 	// Build filters
 	filters := make(map[string]interface{})
@@ -109,50 +421,172 @@ func (h *ApplicationHandler) ListApplications(w http.ResponseWriter, r *http.Req
 		}
 	}
 
-	// Parse pagination
-	limit := 20
-	offset := 0
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
+	return filters
+}
+
+// Application search limits
+const (
+	minSearchQueryLength = 3
+	maxSearchResults     = 50
+)
+
+// SearchResponse is a page of full-text search results. Highlights maps an
+// application ID to excerpts of its notes and cover letter around the
+// matched terms.
+type SearchResponse struct {
+	Items      []interface{}       `json:"items"`
+	Total      int                 `json:"total"`
+	Highlights map[string][]string `json:"highlights"`
+}
+
+// SearchApplications runs a full-text search over application notes and
+// cover letters
+func (h *ApplicationHandler) SearchApplications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if len([]rune(query)) < minSearchQueryLength {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("q must be at least %d characters", minSearchQueryLength), nil)
+		return
 	}
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
-		}
+
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid pagination cursor", err)
+		return
+	}
+	if limit > maxSearchResults {
+		limit = maxSearchResults
 	}
 
-	variables := map[string]interface{}{
+	resp, err := h.client.Query(ctx, gateway.SearchApplicationsQuery, map[string]interface{}{
+		"query":  query,
 		"limit":  limit,
 		"offset": offset,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to search applications", err)
+		return
+	}
+
+	result := SearchResponse{
+		Items:      []interface{}{},
+		Highlights: make(map[string][]string),
+	}
+	if total, ok := lookup(resp.Data, "searchApplications", "total").(float64); ok {
+		result.Total = int(total)
+	}
+	items, _ := lookup(resp.Data, "searchApplications", "items").([]interface{})
+	for _, item := range items {
+		application, ok := item.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		highlights, _ := application["highlights"].([]interface{})
+		delete(application, "highlights")
+
+		id := lookupString(application, "id")
+		for _, highlight := range highlights {
+			if snippet := lookupString(highlight, "snippet"); snippet != "" {
+				result.Highlights[id] = append(result.Highlights[id], snippet)
+			}
+		}
+		result.Items = append(result.Items, application)
+	}
+
+	setNextCursor(w, items, limit, offset)
+
+	respondJSON(w, http.StatusOK, result)
+}
+
+// maxExportRows caps a single CSV export
+const maxExportRows = 10000
+
+// exportColumns is the CSV header row
+var exportColumns = []string{
+	"ID", "Candidate Name", "Email", "Job Title", "Status",
+	"Applied Date", "AI Score", "Location", "Years of Experience",
+}
+
+// ExportApplications downloads applications matching the list filters as CSV
+func (h *ApplicationHandler) ExportApplications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	variables := map[string]interface{}{
+		"limit":  maxExportRows,
+		"offset": 0,
 	}
-	if len(filters) > 0 {
+	if filters := applicationFilters(r); len(filters) > 0 {
 		variables["filters"] = filters
 	}
 
-	resp, err := h.client.Query(ctx, gateway.GetApplicationsQuery, variables)
+	resp, err := h.client.Query(ctx, gateway.ExportApplicationsQuery, variables)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to fetch applications", err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, resp.Data)
+	applications, _ := lookup(resp.Data, "applications").([]interface{})
+
+	filename := fmt.Sprintf("applications-%s.csv", time.Now().Format("2006-01-02"))
+	w.Header().Set("Content-Type", "text/csv")
+	w.Header().Set("Content-Disposition", "attachment; filename="+filename)
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	writer.Write(exportColumns)
+	for _, app := range applications {
+		name := strings.TrimSpace(lookupString(app, "candidate", "firstName") + " " + lookupString(app, "candidate", "lastName"))
+		writer.Write([]string{
+			lookupString(app, "id"),
+			name,
+			lookupString(app, "candidate", "email"),
+			lookupString(app, "job", "title"),
+			lookupString(app, "status"),
+			lookupString(app, "appliedDate"),
+			csvNumber(lookup(app, "aiScore", "overall")),
+			lookupString(app, "candidate", "location"),
+			csvNumber(lookup(app, "candidate", "yearsOfExperience")),
+		})
+	}
+	writer.Flush()
+
+	if err := writer.Error(); err != nil {
+		h.logger.Error("failed to write applications export", "error", err)
+	}
+}
+
+// csvNumber formats a JSON number for a CSV cell, or "" when absent
+func csvNumber(value interface{}) string {
+	number, ok := value.(float64)
+	if !ok {
+		return ""
+	}
+	return strconv.FormatFloat(number, 'f', -1, 64)
 }
 
 // GetApplication returns a single application by ID
 func (h *ApplicationHandler) GetApplication(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	appID := chi.URLParam(r, "id")
-	
+
 	if appID == "" {
 		respondError(w, http.StatusBadRequest, "Application ID is required", nil)
 		return
 	}
 
+	filter, err := timelineFilter(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid timeline filter", err)
+		return
+	}
+
 	variables := map[string]interface{}{
 		"id": appID,
 	}
+	if len(filter) > 0 {
+		variables["timelineFilter"] = filter
+	}
 
 	resp, err := h.client.Query(ctx, gateway.GetApplicationQuery, variables)
 	if err != nil {
@@ -165,159 +599,1508 @@ func (h *ApplicationHandler) GetApplication(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	h.addResumeKey(lookup(resp.Data, "application"))
+	h.addResumeKey(lookup(resp.Data, "application", "candidate"))
+
 	respondJSON(w, http.StatusOK, resp.Data)
 }
 
-// UpdateStatus updates an application's status
-func (h *ApplicationHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+// GetApplicationTimeline returns a page of an application's timeline,
+// accepting the same filters as GetApplication
+func (h *ApplicationHandler) GetApplicationTimeline(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	appID := chi.URLParam(r, "id")
-	
-	if appID == "" {
-		respondError(w, http.StatusBadRequest, "Application ID is required", nil)
-		return
-	}
 
-	var input struct {
-		Status string `json:"status"`
-		Note   string `json:"note,omitempty"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+	filter, err := timelineFilter(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid timeline filter", err)
 		return
 	}
-	defer r.Body.Close()
 
-	if input.Status == "" {
-		respondError(w, http.StatusBadRequest, "Status is required", nil)
+	limit, offset, err := parsePagination(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid pagination cursor", err)
 		return
 	}
 
 	variables := map[string]interface{}{
 		"id":     appID,
-		"status": input.Status,
+		"limit":  limit,
+		"offset": offset,
 	}
-	if input.Note != "" {
-		variables["note"] = input.Note
+	if len(filter) > 0 {
+		variables["filter"] = filter
 	}
 
-	resp, err := h.client.Mutate(ctx, gateway.UpdateApplicationStatusMutation, variables)
+	resp, err := h.client.Query(ctx, gateway.GetApplicationTimelineQuery, variables)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to update application status", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch application timeline", err)
+		return
+	}
+
+	application := lookup(resp.Data, "application")
+	if application == nil {
+		respondError(w, http.StatusNotFound, "Application not found", nil)
 		return
 	}
 
-	// Send status update email asynchronously
-	go h.emailService.SendStatusUpdate(appID, input.Status)
+	setNextCursor(w, lookup(application, "timeline"), limit, offset)
 
-	respondJSON(w, http.StatusOK, resp.Data)
+	respondJSON(w, http.StatusOK, application)
 }
 
-// BulkUpdateStatus updates multiple applications' status
-func (h *ApplicationHandler) BulkUpdateStatus(w http.ResponseWriter, r *http.Request) {
+// GetExperienceScore rates how well an application's candidate has been
+// treated, from how quickly recruiters acted and explained their decisions
+func (h *ApplicationHandler) GetExperienceScore(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
+	appID := chi.URLParam(r, "id")
 
-	var input struct {
-		IDs    []string `json:"ids"`
-		Status string   `json:"status"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+	if appID == "" {
+		respondError(w, http.StatusBadRequest, "Application ID is required", nil)
 		return
 	}
-	defer r.Body.Close()
 
-	if len(input.IDs) == 0 {
-		respondError(w, http.StatusBadRequest, "Application IDs are required", nil)
+	score, err := h.experience.Score(ctx, appID)
+	if errors.Is(err, services.ErrApplicationNotFound) {
+		respondError(w, http.StatusNotFound, "Application not found", nil)
 		return
 	}
-	if input.Status == "" {
-		respondError(w, http.StatusBadRequest, "Status is required", nil)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to compute experience score", err)
 		return
 	}
 
-	variables := map[string]interface{}{
-		"ids":    input.IDs,
-		"status": input.Status,
-	}
+	respondJSON(w, http.StatusOK, score)
+}
 
-	resp, err := h.client.Mutate(ctx, gateway.BulkUpdateApplicationStatusMutation, variables)
+// timelineFilter builds a Hub-HRMS timeline filter from the timelineFrom,
+// timelineTo and timelineTypes query parameters. Dates are ISO 8601, either
+// a plain date or a full timestamp; types are comma-separated event types
+// such as STATUS_CHANGE,NOTE_ADDED.
+func timelineFilter(r *http.Request) (map[string]interface{}, error) {
+	query := r.URL.Query()
+	filter := make(map[string]interface{})
+
+	from, err := timelineBound(query.Get("timelineFrom"))
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to update application statuses", err)
-		return
+		return nil, errors.New("timelineFrom must be an ISO 8601 date")
+	}
+	to, err := timelineBound(query.Get("timelineTo"))
+	if err != nil {
+		return nil, errors.New("timelineTo must be an ISO 8601 date")
+	}
+	if !from.IsZero() {
+		filter["from"] = query.Get("timelineFrom")
+	}
+	if !to.IsZero() {
+		filter["to"] = query.Get("timelineTo")
+	}
+	if !from.IsZero() && !to.IsZero() && to.Before(from) {
+		return nil, errors.New("timelineTo must not be before timelineFrom")
 	}
 
-	respondJSON(w, http.StatusOK, resp.Data)
+	var types []string
+	for _, eventType := range strings.Split(query.Get("timelineTypes"), ",") {
+		if eventType = strings.ToUpper(strings.TrimSpace(eventType)); eventType != "" {
+			types = append(types, eventType)
+		}
+	}
+	if len(types) > 0 {
+		filter["types"] = uniqueStrings(types)
+	}
+
+	return filter, nil
 }
 
-// AddNote adds a note to an application
-func (h *ApplicationHandler) AddNote(w http.ResponseWriter, r *http.Request) {
+// timelineBound parses a date (2006-01-02) or RFC 3339 timestamp, returning
+// the zero time when value is empty
+func timelineBound(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// DownloadResume streams an application's resume through the API so the
+// bucket is never exposed to the caller
+func (h *ApplicationHandler) DownloadResume(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	appID := chi.URLParam(r, "id")
-	
+
 	if appID == "" {
 		respondError(w, http.StatusBadRequest, "Application ID is required", nil)
 		return
 	}
 
-	var input struct {
-		Content    string `json:"content"`
-		IsInternal bool   `json:"isInternal"`
-	}
-	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+	resp, err := h.client.Query(ctx, gateway.GetApplicationQuery, map[string]interface{}{
+		"id": appID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch application", err)
 		return
 	}
-	defer r.Body.Close()
 
-	if input.Content == "" {
-		respondError(w, http.StatusBadRequest, "Note content is required", nil)
+	application := lookup(resp.Data, "application")
+	if application == nil {
+		respondError(w, http.StatusNotFound, "Application not found", nil)
 		return
 	}
 
-	variables := map[string]interface{}{
-		"applicationId": appID,
-		"content":       input.Content,
-		"isInternal":    input.IsInternal,
-	}
-
-	resp, err := h.client.Mutate(ctx, gateway.AddApplicationNoteMutation, variables)
-	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to add note", err)
-		return
+	resumeURL := lookupString(application, "resumeUrl")
+	if resumeURL == "" {
+		resumeURL = lookupString(application, "candidate", "resumeUrl")
 	}
-
-	respondJSON(w, http.StatusCreated, resp.Data)
+	h.serveResume(w, r, resumeURL)
 }
 
-// ScoreApplication triggers AI scoring for an application
-func (h *ApplicationHandler) ScoreApplication(w http.ResponseWriter, r *http.Request) {
+// resumeURLTTL is how long a presigned resume link stays valid
+const resumeURLTTL = 15 * time.Minute
+
+// GetResumeURL returns a short-lived presigned link for opening an
+// application's resume directly from S3
+func (h *ApplicationHandler) GetResumeURL(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	appID := chi.URLParam(r, "id")
-	
+
 	if appID == "" {
 		respondError(w, http.StatusBadRequest, "Application ID is required", nil)
 		return
 	}
 
-	variables := map[string]interface{}{
-		"applicationId": appID,
-	}
-
-	resp, err := h.client.Mutate(ctx, gateway.ScoreApplicationMutation, variables)
+	resp, err := h.client.Query(ctx, gateway.GetApplicationQuery, map[string]interface{}{
+		"id": appID,
+	})
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to score application", err)
+		respondError(w, http.StatusInternalServerError, "Failed to fetch application", err)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, resp.Data)
-}
+	application := lookup(resp.Data, "application")
+	if application == nil {
+		respondError(w, http.StatusNotFound, "Application not found", nil)
+		return
+	}
+
+	key, ok := h.uploadService.KeyFromURL(lookupString(application, "resumeUrl"))
+	if !ok {
+		respondError(w, http.StatusNotFound, "No resume on file", nil)
+		return
+	}
+
+	expiresAt := time.Now().Add(resumeURLTTL)
+	presignedURL, err := h.uploadService.GetPresignedDownloadURL(ctx, key, resumeURLTTL)
+	if errors.Is(err, services.ErrInvalidResumeKey) {
+		respondError(w, http.StatusUnprocessableEntity, "Resume is not stored in the resumes folder", err)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to generate resume URL", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"url":       presignedURL,
+		"expiresAt": expiresAt.UTC().Format(time.RFC3339),
+	})
+}
+
+// indexResume extracts the text of a new application's resume and stores it
+// in Hub-HRMS for search
+func (h *ApplicationHandler) indexResume(applicationID, key string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if _, err := h.resumes.IndexResume(ctx, applicationID, key); err != nil {
+		h.logger.Warn("failed to index resume text", "application_id", applicationID, "error", err)
+	}
+}
+
+// autoReject rejects a new application that matches one of its job's
+// auto-rejection rules and emails the candidate. The application is kept
+// when the rules cannot be loaded.
+func (h *ApplicationHandler) autoReject(applicationID, jobID, status string, application map[string]interface{}) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	rules, err := h.rejections.Rules(ctx, jobID)
+	if err != nil {
+		h.logger.Warn("failed to load auto-rejection rules", "job_id", jobID, "error", err)
+		return
+	}
+	rule, matched := h.rejections.Evaluate(rules, application)
+	if !matched {
+		return
+	}
+
+	resp, err := h.client.Mutate(ctx, gateway.UpdateApplicationStatusMutation, map[string]interface{}{
+		"id":     applicationID,
+		"status": "REJECTED",
+		"note":   "Automatically rejected: " + rule.String(),
+	})
+	if err != nil {
+		h.logger.Error("failed to auto-reject application", "application_id", applicationID, "error", err)
+		return
+	}
+	if len(resp.Errors) > 0 {
+		h.logger.Error("failed to auto-reject application", "application_id", applicationID, "error", resp.Errors[0].Message)
+		return
+	}
+	h.logger.Info("auto-rejected application", "application_id", applicationID, "rule", rule.String())
+
+	updated := lookup(resp.Data, "updateApplicationStatus")
+	if email := lookupString(updated, "candidate", "email"); email != "" {
+		h.notify(ctx, services.NotifyStatusChanges, services.RejectionEmail(
+			email,
+			lookupString(updated, "candidate", "firstName"),
+			lookupString(updated, "job", "title"),
+		))
+	}
+
+	h.webhooks.Publish(services.EventApplicationStatusChanged, services.ApplicationEventData{
+		ApplicationID: applicationID,
+		JobID:         jobID,
+		Status:        "REJECTED",
+	})
+	h.pipelineEvents.Publish(services.PipelineEvent{
+		ApplicationID: applicationID,
+		OldStatus:     status,
+		NewStatus:     "REJECTED",
+		JobID:         jobID,
+	})
+}
+
+// GetResumeText returns the text extracted from an application's resume.
+// Resumes submitted before extraction was added are extracted and indexed
+// on first request.
+func (h *ApplicationHandler) GetResumeText(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	appID := chi.URLParam(r, "id")
+
+	resp, err := h.client.Query(ctx, gateway.GetResumeTextQuery, map[string]interface{}{
+		"id": appID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch application", err)
+		return
+	}
+
+	application := lookup(resp.Data, "application")
+	if application == nil {
+		respondError(w, http.StatusNotFound, "Application not found", nil)
+		return
+	}
+
+	if extracted := lookup(application, "resumeText"); extracted != nil {
+		respondJSON(w, http.StatusOK, map[string]interface{}{
+			"applicationId": appID,
+			"text":          lookupString(extracted, "text"),
+			"extractedAt":   lookupString(extracted, "extractedAt"),
+		})
+		return
+	}
+
+	key, ok := h.uploadService.KeyFromURL(lookupString(application, "resumeUrl"))
+	if !ok {
+		respondError(w, http.StatusNotFound, "No resume on file", nil)
+		return
+	}
+
+	text, err := h.resumes.IndexResume(ctx, appID, key)
+	switch {
+	case errors.Is(err, services.ErrEncryptedPDF):
+		respondError(w, http.StatusUnprocessableEntity, "Resume is password protected and its text cannot be read", err)
+		return
+	case errors.Is(err, services.ErrInvalidPDF):
+		respondError(w, http.StatusUnprocessableEntity, "Resume is not a readable PDF", err)
+		return
+	case err != nil:
+		respondError(w, http.StatusBadGateway, "Failed to extract resume text", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"applicationId": appID,
+		"text":          text,
+		"extractedAt":   time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// addResumeKey records the S3 key of a record's resume alongside its URL, so
+// clients can fetch it through the download proxy
+func (h *ApplicationHandler) addResumeKey(record interface{}) {
+	m, ok := record.(map[string]interface{})
+	if !ok {
+		return
+	}
+	if key, ok := h.uploadService.KeyFromURL(lookupString(m, "resumeUrl")); ok {
+		m["resumeKey"] = key
+	}
+}
+
+// serveResume sends the stored resume at resumeURL as an attachment
+func (h *ApplicationHandler) serveResume(w http.ResponseWriter, r *http.Request, resumeURL string) {
+	key, ok := h.uploadService.KeyFromURL(resumeURL)
+	if !ok {
+		respondError(w, http.StatusNotFound, "No resume on file", nil)
+		return
+	}
+
+	file, contentType, err := h.uploadService.OpenResume(r.Context(), key)
+	if err != nil {
+		respondError(w, http.StatusBadGateway, "Failed to fetch resume", err)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to read resume", err)
+		return
+	}
+
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", path.Base(key)))
+	w.Header().Set("Cache-Control", "private, no-store")
+	http.ServeContent(w, r, path.Base(key), info.ModTime(), file)
+}
+
+// UpdateStatus updates an application's status
+func (h *ApplicationHandler) UpdateStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	appID := chi.URLParam(r, "id")
+
+	if appID == "" {
+		respondError(w, http.StatusBadRequest, "Application ID is required", nil)
+		return
+	}
+
+	var input struct {
+		Status string `json:"status"`
+		Note   string `json:"note,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if input.Status == "" {
+		respondError(w, http.StatusBadRequest, "Status is required", nil)
+		return
+	}
+
+	// The current status is needed to validate the transition and to report
+	// it on the pipeline stream; only strict mode treats a failed fetch as fatal
+	var previous interface{}
+	current, err := h.client.Query(ctx, gateway.GetApplicationStatusQuery, map[string]interface{}{"id": appID})
+	if err == nil {
+		previous = lookup(current.Data, "application")
+	}
+
+	if h.strictTransitions {
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to fetch application", err)
+			return
+		}
+		if previous == nil {
+			respondError(w, http.StatusNotFound, "Application not found", nil)
+			return
+		}
+
+		if !h.checkTransition(w, r, lookupString(previous, "status"), input.Status) {
+			return
+		}
+	} else {
+		if err != nil {
+			h.logger.Warn("failed to fetch current application status", "application_id", appID, "error", err)
+		}
+		// Without strict transitions any move is allowed, but only to a
+		// stage of the pipeline
+		if !h.checkStage(w, r, input.Status) {
+			return
+		}
+	}
+
+	variables := map[string]interface{}{
+		"id":     appID,
+		"status": input.Status,
+	}
+	if input.Note != "" {
+		variables["note"] = input.Note
+	}
+
+	resp, err := h.client.Mutate(ctx, gateway.UpdateApplicationStatusMutation, variables)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update application status", err)
+		return
+	}
+
+	// Send status update email asynchronously. The recruiter making the
+	// change decides whether status emails go out, the candidate's included.
+	updated := lookup(resp.Data, "updateApplicationStatus")
+	if email := lookupString(updated, "candidate", "email"); email != "" {
+		h.notify(ctx, services.NotifyStatusChanges, services.StatusUpdateEmail(
+			email,
+			lookupString(updated, "candidate", "firstName"),
+			lookupString(updated, "job", "title"),
+			input.Status,
+		))
+	}
+
+	h.chat.Notify(services.ApplicationNotification{
+		Event:         services.ChatStatusChanged,
+		ApplicationID: appID,
+		CandidateName: strings.TrimSpace(lookupString(updated, "candidate", "firstName") + " " + lookupString(updated, "candidate", "lastName")),
+		JobTitle:      lookupString(updated, "job", "title"),
+		Status:        input.Status,
+		URL:           h.applicationURL(appID),
+	})
+
+	h.webhooks.Publish(services.EventApplicationStatusChanged, services.ApplicationEventData{
+		ApplicationID: appID,
+		Status:        input.Status,
+	})
+
+	h.pipelineEvents.Publish(services.PipelineEvent{
+		ApplicationID: appID,
+		OldStatus:     lookupString(previous, "status"),
+		NewStatus:     input.Status,
+		JobID:         lookupString(previous, "job", "id"),
+	})
+
+	if recommendation := lookupString(previous, "aiScore", "recommendation"); services.ContradictsRecommendation(recommendation, input.Status) {
+		go h.recordScoreOverride(appID, recommendation, input.Status, userID(ctx))
+	}
+
+	respondJSON(w, http.StatusOK, resp.Data)
+}
+
+// checkTransition validates a status change against the configured pipeline,
+// responding with the reason and returning false if it is not allowed
+func (h *ApplicationHandler) checkTransition(w http.ResponseWriter, r *http.Request, from, to string) bool {
+	err := h.stages.ValidateTransition(r.Context(), from, to)
+	switch {
+	case errors.Is(err, services.ErrUnknownStatus) || errors.Is(err, services.ErrInvalidTransition):
+		respondError(w, http.StatusUnprocessableEntity, "Invalid status transition", err)
+		return false
+	case err != nil:
+		respondError(w, http.StatusInternalServerError, "Failed to load pipeline stages", err)
+		return false
+	}
+	return true
+}
+
+// checkStage validates a status against the configured pipeline, responding
+// with the reason and returning false if it is not one of its stages
+func (h *ApplicationHandler) checkStage(w http.ResponseWriter, r *http.Request, status string) bool {
+	err := h.stages.ValidateStage(r.Context(), status)
+	switch {
+	case errors.Is(err, services.ErrUnknownStatus):
+		respondError(w, http.StatusUnprocessableEntity, "Unknown application status", err)
+		return false
+	case err != nil:
+		respondError(w, http.StatusInternalServerError, "Failed to load pipeline stages", err)
+		return false
+	}
+	return true
+}
+
+// applicationURL links to an application in the recruiting app
+func (h *ApplicationHandler) applicationURL(appID string) string {
+	return applicationLink(h.baseURL, appID)
+}
+
+// applicationLink links to an application in the recruiting app at baseURL
+func applicationLink(baseURL, appID string) string {
+	return baseURL + "/applications/" + url.PathEscape(appID)
+}
+
+// maxInterviewDuration bounds how long a single interview can be booked for
+const maxInterviewDuration = 8 * time.Hour
+
+// ScheduleInterview books an interview for an application and sends calendar
+// invitations to the candidate and the interviewers
+func (h *ApplicationHandler) ScheduleInterview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	appID := chi.URLParam(r, "id")
+
+	if appID == "" {
+		respondError(w, http.StatusBadRequest, "Application ID is required", nil)
+		return
+	}
+
+	var input struct {
+		ScheduledAt    time.Time `json:"scheduledAt"`
+		Duration       int       `json:"duration"` // minutes
+		Type           string    `json:"type"`
+		InterviewerIDs []string  `json:"interviewerIds"`
+		Location       string    `json:"location"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	duration := time.Duration(input.Duration) * time.Minute
+	switch {
+	case input.ScheduledAt.IsZero():
+		respondError(w, http.StatusBadRequest, "scheduledAt is required", nil)
+		return
+	case !input.ScheduledAt.After(time.Now()):
+		respondError(w, http.StatusBadRequest, "scheduledAt must be in the future", nil)
+		return
+	case duration <= 0 || duration > maxInterviewDuration:
+		respondError(w, http.StatusBadRequest, "duration must be between 1 and 480 minutes", nil)
+		return
+	case input.Type == "":
+		respondError(w, http.StatusBadRequest, "type is required", nil)
+		return
+	case len(input.InterviewerIDs) == 0:
+		respondError(w, http.StatusBadRequest, "At least one interviewer is required", nil)
+		return
+	}
+
+	variables := map[string]interface{}{
+		"applicationId": appID,
+		"input": map[string]interface{}{
+			"scheduledAt":    input.ScheduledAt.UTC().Format(time.RFC3339),
+			"duration":       input.Duration,
+			"type":           strings.ToUpper(input.Type),
+			"interviewerIds": input.InterviewerIDs,
+			"location":       input.Location,
+		},
+	}
+
+	resp, err := h.client.Mutate(ctx, gateway.ScheduleInterviewMutation, variables)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to schedule interview", err)
+		return
+	}
+
+	interview := lookup(resp.Data, "scheduleInterview")
+	application := lookup(interview, "application")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		h.sendInterviewInvitations(ctx, interview, application)
+	}()
+	eventID := h.createCalendarEvent(ctx, interview, application)
+	wg.Wait()
+	if fields, ok := interview.(map[string]interface{}); ok && eventID != "" {
+		fields["calendarEventId"] = eventID
+	}
+
+	h.chat.Notify(services.ApplicationNotification{
+		Event:         services.ChatInterviewScheduled,
+		ApplicationID: appID,
+		CandidateName: strings.TrimSpace(lookupString(application, "candidate", "firstName") + " " + lookupString(application, "candidate", "lastName")),
+		JobTitle:      lookupString(application, "job", "title"),
+		InterviewAt:   input.ScheduledAt,
+		URL:           h.applicationURL(appID),
+	})
+
+	respondJSON(w, http.StatusCreated, resp.Data)
+}
+
+// gcalTimeout bounds adding an interview to Google Calendar and saving the
+// event ID, so a slow calendar does not hold up scheduling
+const gcalTimeout = 10 * time.Second
+
+// createCalendarEvent adds a scheduled interview to Google Calendar and saves
+// the event ID on the interview, returning it. Failures are logged; the
+// interview stands without a calendar event.
+func (h *ApplicationHandler) createCalendarEvent(ctx context.Context, interview, application interface{}) string {
+	if !h.gcal.Enabled() {
+		return ""
+	}
+	interviewID := lookupString(interview, "id")
+	event, ok := h.interviewEvent(interview, application)
+	if !ok {
+		h.logger.Warn("skipping calendar event with an invalid schedule", "interview_id", interviewID)
+		return ""
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, gcalTimeout)
+	defer cancel()
+
+	eventID, err := h.gcal.CreateInterviewEvent(ctx, event)
+	if err != nil {
+		h.logger.Warn("failed to add interview to Google Calendar", "interview_id", interviewID, "error", err)
+		return ""
+	}
+
+	resp, err := h.client.Mutate(ctx, gateway.UpdateInterviewMutation, map[string]interface{}{
+		"interviewId": interviewID,
+		"input":       map[string]interface{}{"calendarEventId": eventID},
+	})
+	if err == nil && len(resp.Errors) > 0 {
+		err = fmt.Errorf("%s", resp.Errors[0].Message)
+	}
+	if err != nil {
+		h.logger.Warn("failed to save calendar event", "event_id", eventID, "interview_id", interviewID, "error", err)
+	}
+	return eventID
+}
+
+// CancelInterview cancels an application's scheduled interview and removes
+// it from Google Calendar
+func (h *ApplicationHandler) CancelInterview(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	appID := chi.URLParam(r, "id")
+
+	if appID == "" {
+		respondError(w, http.StatusBadRequest, "Application ID is required", nil)
+		return
+	}
+
+	resp, err := h.client.Query(ctx, gateway.GetApplicationInterviewQuery, map[string]interface{}{
+		"id": appID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch interview", err)
+		return
+	}
+
+	interview := lookup(resp.Data, "application", "interview")
+	if interview == nil {
+		respondError(w, http.StatusNotFound, "No interview scheduled for this application", nil)
+		return
+	}
+	interviewID := lookupString(interview, "id")
+
+	resp, err = h.client.Mutate(ctx, gateway.CancelInterviewMutation, map[string]interface{}{
+		"interviewId": interviewID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to cancel interview", err)
+		return
+	}
+	if len(resp.Errors) > 0 {
+		respondError(w, http.StatusBadRequest, resp.Errors[0].Message, nil)
+		return
+	}
+
+	if eventID := lookupString(interview, "calendarEventId"); eventID != "" && h.gcal.Enabled() {
+		if err := h.gcal.CancelEvent(ctx, eventID); err != nil {
+			h.logger.Warn("failed to remove interview from Google Calendar", "interview_id", interviewID, "error", err)
+		}
+	}
+
+	respondJSON(w, http.StatusOK, resp.Data)
+}
+
+// DownloadInterviewICS returns the calendar file for an application's
+// scheduled interview
+func (h *ApplicationHandler) DownloadInterviewICS(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	appID := chi.URLParam(r, "id")
+
+	if appID == "" {
+		respondError(w, http.StatusBadRequest, "Application ID is required", nil)
+		return
+	}
+
+	resp, err := h.client.Query(ctx, gateway.GetApplicationInterviewQuery, map[string]interface{}{
+		"id": appID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch interview", err)
+		return
+	}
+
+	application := lookup(resp.Data, "application")
+	interview := lookup(application, "interview")
+	if interview == nil {
+		respondError(w, http.StatusNotFound, "No interview scheduled for this application", nil)
+		return
+	}
+
+	event, ok := h.interviewEvent(interview, application)
+	if !ok {
+		respondError(w, http.StatusBadGateway, "Interview has an invalid schedule", nil)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8; method=REQUEST")
+	w.Header().Set("Content-Disposition", "attachment; filename=interview.ics")
+	w.WriteHeader(http.StatusOK)
+	w.Write(h.calendar.GenerateICS(event))
+}
+
+// reportTimeout bounds fetching an application and converting its report
+// to PDF
+const reportTimeout = 30 * time.Second
+
+// ApplicationReport returns a PDF summary of an application for sharing with
+// hiring managers. Internal notes are only included for admins.
+func (h *ApplicationHandler) ApplicationReport(w http.ResponseWriter, r *http.Request) {
+	ctx, cancel := context.WithTimeout(r.Context(), reportTimeout)
+	defer cancel()
+	appID := chi.URLParam(r, "id")
+
+	if appID == "" {
+		respondError(w, http.StatusBadRequest, "Application ID is required", nil)
+		return
+	}
+
+	resp, err := h.client.Query(ctx, gateway.GetApplicationQuery, map[string]interface{}{
+		"id": appID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch application", err)
+		return
+	}
+
+	application := lookup(resp.Data, "application")
+	if application == nil {
+		respondError(w, http.StatusNotFound, "Application not found", nil)
+		return
+	}
+
+	pdf, err := services.GenerateApplicationReportPDF(ctx, applicationReport(application, middleware.HasRole(r.Context(), "admin")))
+	if errors.Is(err, context.DeadlineExceeded) {
+		respondError(w, http.StatusGatewayTimeout, "Timed out generating report", err)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to generate report", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/pdf")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "application-"+appID+".pdf"))
+	w.Header().Set("Content-Length", strconv.Itoa(len(pdf)))
+	w.WriteHeader(http.StatusOK)
+	w.Write(pdf)
+}
+
+// applicationReport collects the candidate, job, AI score, notes and timeline
+// of an application returned by GetApplicationQuery. Internal notes are left
+// out unless includeInternal is set.
+func applicationReport(application interface{}, includeInternal bool) services.ApplicationReport {
+	candidateName := strings.TrimSpace(lookupString(application, "candidate", "firstName") + " " +
+		lookupString(application, "candidate", "lastName"))
+	report := services.ApplicationReport{
+		CandidateName: candidateName,
+		Summary: []services.ReportField{
+			{Label: "Position", Value: lookupString(application, "job", "title")},
+			{Label: "Status", Value: lookupString(application, "status")},
+			{Label: "Applied", Value: reportDate(lookupString(application, "appliedDate"))},
+			{Label: "Last updated", Value: reportDate(lookupString(application, "lastUpdated"))},
+		},
+		Candidate: []services.ReportField{
+			{Label: "Name", Value: candidateName},
+			{Label: "Email", Value: lookupString(application, "candidate", "email")},
+			{Label: "Phone", Value: lookupString(application, "candidate", "phone")},
+			{Label: "Location", Value: firstNonEmpty(lookupString(application, "currentLocation"), lookupString(application, "candidate", "location"))},
+			{Label: "Willing to relocate", Value: reportBool(lookup(application, "willingToRelocate"))},
+			{Label: "Years of experience", Value: csvNumber(lookup(application, "yearsOfExperience"))},
+			{Label: "Expected salary", Value: csvNumber(lookup(application, "expectedSalary"))},
+			{Label: "Availability", Value: lookupString(application, "availability")},
+			{Label: "LinkedIn", Value: firstNonEmpty(lookupString(application, "linkedinUrl"), lookupString(application, "candidate", "linkedinUrl"))},
+			{Label: "Portfolio", Value: firstNonEmpty(lookupString(application, "portfolioUrl"), lookupString(application, "candidate", "portfolioUrl"))},
+		},
+		Job: []services.ReportField{
+			{Label: "Title", Value: lookupString(application, "job", "title")},
+			{Label: "Department", Value: lookupString(application, "job", "department")},
+			{Label: "Location", Value: lookupString(application, "job", "location")},
+		},
+		Requirements: reportStrings(lookup(application, "job", "requirements")),
+		CoverLetter:  strings.TrimSpace(lookupString(application, "coverLetter")),
+	}
+
+	if score := lookup(application, "aiScore"); score != nil {
+		report.Score = &services.ReportScore{
+			Fields: []services.ReportField{
+				{Label: "Overall", Value: csvNumber(lookup(score, "overall"))},
+				{Label: "Recommendation", Value: lookupString(score, "recommendation")},
+				{Label: "Generated", Value: reportDate(lookupString(score, "generatedAt"))},
+			},
+			Lists: []services.ReportList{
+				{Label: "Strengths", Items: reportStrings(lookup(score, "strengths"))},
+				{Label: "Concerns", Items: reportStrings(lookup(score, "concerns"))},
+				{Label: "Insights", Items: reportStrings(lookup(score, "insights"))},
+			},
+		}
+	}
+
+	notes, _ := lookup(application, "notes").([]interface{})
+	for _, note := range notes {
+		internal, _ := lookup(note, "isInternal").(bool)
+		if internal && !includeInternal {
+			continue
+		}
+		report.Notes = append(report.Notes, services.ReportNote{
+			Author:   lookupString(note, "author", "name"),
+			Date:     reportDate(lookupString(note, "createdAt")),
+			Content:  strings.TrimSpace(lookupString(note, "content")),
+			Internal: internal,
+		})
+	}
+
+	timeline, _ := lookup(application, "timeline").([]interface{})
+	for _, event := range timeline {
+		report.Timeline = append(report.Timeline, services.ReportEvent{
+			Date:        reportDate(lookupString(event, "timestamp")),
+			Description: lookupString(event, "description"),
+			PerformedBy: lookupString(event, "performedBy", "name"),
+		})
+	}
+
+	return report
+}
+
+// reportStrings returns the strings in a list field, skipping other values
+func reportStrings(value interface{}) []string {
+	items, _ := value.([]interface{})
+	var texts []string
+	for _, item := range items {
+		if text, ok := item.(string); ok {
+			texts = append(texts, text)
+		}
+	}
+	return texts
+}
+
+// reportDate formats an RFC 3339 timestamp for the PDF report, passing other
+// values through unchanged
+func reportDate(value string) string {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return t.Format("Jan 2, 2006 15:04 MST")
+}
+
+// firstNonEmpty returns the first value that is not empty
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func reportBool(value interface{}) string {
+	b, ok := value.(bool)
+	switch {
+	case !ok:
+		return ""
+	case b:
+		return "Yes"
+	default:
+		return "No"
+	}
+}
+
+// Share link lifetimes
+const (
+	defaultShareLinkTTL = 7 * 24 * time.Hour
+	maxShareLinkTTL     = 30 * 24 * time.Hour
+)
+
+// CreateShareLink issues a time-limited link for reviewers outside the
+// recruiting system to view an application
+func (h *ApplicationHandler) CreateShareLink(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	appID := chi.URLParam(r, "id")
+
+	var input struct {
+		ExpiresIn string `json:"expiresIn"`
+		Note      string `json:"note,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	ttl := defaultShareLinkTTL
+	if input.ExpiresIn != "" {
+		var err error
+		if ttl, err = parseExpiresIn(input.ExpiresIn); err != nil || ttl <= 0 || ttl > maxShareLinkTTL {
+			respondError(w, http.StatusBadRequest, "expiresIn must be a duration such as \"7d\" or \"48h\", up to 30d", err)
+			return
+		}
+	}
+
+	resp, err := h.client.Query(ctx, gateway.GetApplicationStatusQuery, map[string]interface{}{"id": appID})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch application", err)
+		return
+	}
+	if lookup(resp.Data, "application") == nil {
+		respondError(w, http.StatusNotFound, "Application not found", nil)
+		return
+	}
+
+	link, err := h.shareLinks.Create(ctx, appID, ttl, input.Note, userID(ctx))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create share link", err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"url":       h.baseURL + "/api/v1/shared/applications/" + link.Token,
+		"expiresAt": link.ExpiresAt,
+	})
+}
+
+// GetSharedApplication shows the application a share link grants access to,
+// without internal notes or resume storage URLs
+func (h *ApplicationHandler) GetSharedApplication(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	link, err := h.shareLinks.Resolve(ctx, chi.URLParam(r, "token"))
+	switch {
+	case errors.Is(err, services.ErrShareLinkNotFound):
+		respondError(w, http.StatusUnauthorized, "Invalid share link", nil)
+		return
+	case errors.Is(err, services.ErrShareLinkExpired):
+		respondError(w, http.StatusUnauthorized, "Share link has expired", nil)
+		return
+	case err != nil:
+		respondError(w, http.StatusInternalServerError, "Failed to fetch share link", err)
+		return
+	}
+
+	resp, err := h.client.Query(ctx, gateway.GetApplicationQuery, map[string]interface{}{"id": link.ApplicationID})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch application", err)
+		return
+	}
+	application, ok := lookup(resp.Data, "application").(map[string]interface{})
+	if !ok {
+		respondError(w, http.StatusNotFound, "Application not found", nil)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"application": sharedApplicationView(application),
+		"note":        link.Note,
+		"expiresAt":   link.ExpiresAt,
+	})
+}
+
+// sharedApplicationView removes internal notes and resume storage URLs from
+// an application returned by GetApplicationQuery
+func sharedApplicationView(application map[string]interface{}) map[string]interface{} {
+	delete(application, "resumeUrl")
+	if candidate, ok := application["candidate"].(map[string]interface{}); ok {
+		delete(candidate, "resumeUrl")
+	}
+
+	notes, _ := application["notes"].([]interface{})
+	shared := make([]interface{}, 0, len(notes))
+	for _, note := range notes {
+		if internal, _ := lookup(note, "isInternal").(bool); !internal {
+			shared = append(shared, note)
+		}
+	}
+	application["notes"] = shared
+	return application
+}
+
+// parseExpiresIn parses a Go duration, also accepting whole days such as "7d"
+func parseExpiresIn(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil {
+			return 0, fmt.Errorf("invalid duration %q", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	return time.ParseDuration(value)
+}
+
+// interviewEvent builds the calendar event for an interview returned by
+// Hub-HRMS, inviting the candidate and every interviewer
+func (h *ApplicationHandler) interviewEvent(interview, application interface{}) (services.InterviewEvent, bool) {
+	start, err := time.Parse(time.RFC3339, lookupString(interview, "scheduledAt"))
+	if err != nil {
+		return services.InterviewEvent{}, false
+	}
+	minutes, _ := lookup(interview, "duration").(float64)
+
+	candidateName := strings.TrimSpace(lookupString(application, "candidate", "firstName") + " " +
+		lookupString(application, "candidate", "lastName"))
+	jobTitle := lookupString(application, "job", "title")
+
+	event := services.InterviewEvent{
+		UID:         lookupString(interview, "id") + "@hr-recruiting",
+		Summary:     fmt.Sprintf("Interview: %s - %s", candidateName, jobTitle),
+		Description: fmt.Sprintf("Interview with %s for the %s position (%s).", candidateName, jobTitle, strings.ToLower(lookupString(interview, "type"))),
+		Location:    lookupString(interview, "location"),
+		Start:       start,
+		Duration:    time.Duration(minutes) * time.Minute,
+	}
+
+	if email := lookupString(application, "candidate", "email"); email != "" {
+		event.Attendees = append(event.Attendees, services.Attendee{Name: candidateName, Email: email})
+	}
+	interviewers, _ := lookup(interview, "interviewers").([]interface{})
+	for _, interviewer := range interviewers {
+		if email := lookupString(interviewer, "email"); email != "" {
+			event.Attendees = append(event.Attendees, services.Attendee{Name: lookupString(interviewer, "name"), Email: email})
+		}
+	}
+
+	return event, true
+}
+
+// sendInterviewInvitations queues the invitation emails, each carrying the
+// calendar file
+func (h *ApplicationHandler) sendInterviewInvitations(ctx context.Context, interview, application interface{}) {
+	event, ok := h.interviewEvent(interview, application)
+	if !ok {
+		h.logger.Warn("skipping invitations with an invalid schedule", "interview_id", lookupString(interview, "id"))
+		return
+	}
+
+	ics := h.calendar.GenerateICS(event)
+	interviewDate := event.Start.UTC().Format(services.InterviewDateFormat)
+	firstName := lookupString(application, "candidate", "firstName")
+	candidateName := strings.TrimSpace(firstName + " " + lookupString(application, "candidate", "lastName"))
+	jobTitle := lookupString(application, "job", "title")
+
+	if email := lookupString(application, "candidate", "email"); email != "" {
+		h.notify(ctx, services.NotifyInterviewReminders, services.InterviewInvitationEmail(email, firstName, jobTitle, interviewDate, ics))
+	}
+
+	interviewers, _ := lookup(interview, "interviewers").([]interface{})
+	for _, interviewer := range interviewers {
+		if email := lookupString(interviewer, "email"); email != "" {
+			h.notify(ctx, services.NotifyInterviewReminders, services.InterviewerInvitationEmail(email, lookupString(interviewer, "name"), candidateName, jobTitle, interviewDate, ics))
+		}
+	}
+}
+
+// BulkUpdateStatus updates multiple applications' status
+func (h *ApplicationHandler) BulkUpdateStatus(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var input struct {
+		IDs    []string `json:"ids"`
+		Status string   `json:"status"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if len(input.IDs) == 0 {
+		respondError(w, http.StatusBadRequest, "Application IDs are required", nil)
+		return
+	}
+	if input.Status == "" {
+		respondError(w, http.StatusBadRequest, "Status is required", nil)
+		return
+	}
+
+	previous := h.currentStatuses(ctx, input.IDs)
+
+	variables := map[string]interface{}{
+		"ids":    input.IDs,
+		"status": input.Status,
+	}
+
+	resp, err := h.client.Mutate(ctx, gateway.BulkUpdateApplicationStatusMutation, variables)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update application statuses", err)
+		return
+	}
+
+	updated, _ := lookup(resp.Data, "bulkUpdateApplicationStatus").([]interface{})
+	for _, item := range updated {
+		id := lookupString(item, "id")
+		if id == "" {
+			continue
+		}
+		h.pipelineEvents.Publish(services.PipelineEvent{
+			ApplicationID: id,
+			OldStatus:     lookupString(previous[id], "status"),
+			NewStatus:     lookupString(item, "status"),
+			JobID:         lookupString(previous[id], "job", "id"),
+		})
+	}
+
+	respondJSON(w, http.StatusOK, resp.Data)
+}
+
+// currentStatuses fetches the status and job of each application in a single
+// batch. It is best effort: on failure the map is empty and events go out
+// without the previous status.
+func (h *ApplicationHandler) currentStatuses(ctx context.Context, ids []string) map[string]interface{} {
+	requests := make([]gateway.BatchRequest, len(ids))
+	for i, id := range ids {
+		requests[i] = gateway.BatchRequest{
+			Key:       id,
+			Query:     gateway.GetApplicationStatusQuery,
+			Variables: map[string]interface{}{"id": id},
+		}
+	}
+
+	statuses := make(map[string]interface{}, len(ids))
+	responses, err := h.client.Batch(ctx, requests)
+	if err != nil {
+		h.logger.Warn("failed to fetch current application statuses", "error", err)
+		return statuses
+	}
+	for id, resp := range responses {
+		statuses[id] = lookup(resp.Data, "application")
+	}
+	return statuses
+}
+
+// maxBulkScore caps the applications scored by one bulk request
+const maxBulkScore = 100
+
+// BulkScoringResult reports which applications a bulk request scored
+type BulkScoringResult struct {
+	Succeeded []string           `json:"succeeded"`
+	Failed    []BulkScoringError `json:"failed"`
+}
+
+// BulkScoringError explains why one application could not be scored
+type BulkScoringError struct {
+	ApplicationID string `json:"applicationId"`
+	Error         string `json:"error"`
+}
+
+// bulkScoringProgress is streamed as each application finishes when the
+// client accepts text/event-stream
+type bulkScoringProgress struct {
+	ApplicationID string `json:"applicationId"`
+	Succeeded     bool   `json:"succeeded"`
+	Error         string `json:"error,omitempty"`
+	Completed     int    `json:"completed"`
+	Total         int    `json:"total"`
+}
+
+// BulkScoreApplications runs AI scoring for up to maxBulkScore applications,
+// scoring several at once. When jobId is given, applications for other jobs
+// are reported as failures. Clients that accept text/event-stream receive a
+// progress event per application followed by a complete event with the
+// result.
+func (h *ApplicationHandler) BulkScoreApplications(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var input struct {
+		IDs   []string `json:"ids"`
+		JobID string   `json:"jobId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	ids := uniqueStrings(input.IDs)
+	if len(ids) == 0 {
+		respondError(w, http.StatusBadRequest, "Application IDs are required", nil)
+		return
+	}
+	if len(ids) > maxBulkScore {
+		respondError(w, http.StatusBadRequest, fmt.Sprintf("At most %d applications can be scored at once", maxBulkScore), nil)
+		return
+	}
+	if !h.features.AIScoring.Load() {
+		respondError(w, http.StatusServiceUnavailable, "AI scoring is disabled", nil)
+		return
+	}
+
+	if !strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		respondJSON(w, http.StatusOK, h.scoreApplications(ctx, ids, input.JobID, nil))
+		return
+	}
+
+	controller := http.NewResponseController(w)
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	controller.Flush()
+
+	writeEvent := func(name string, payload interface{}) {
+		data, err := json.Marshal(payload)
+		if err != nil {
+			h.logger.Error("failed to encode bulk scoring event", "event", name, "error", err)
+			return
+		}
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", name, data)
+		controller.Flush()
+	}
+
+	result := h.scoreApplications(ctx, ids, input.JobID, func(progress bulkScoringProgress) {
+		writeEvent("progress", progress)
+	})
+	writeEvent("complete", result)
+}
+
+// scoreApplications scores ids with at most scoringConcurrency mutations in
+// flight. progress, if set, is called after each application, one call at a
+// time.
+func (h *ApplicationHandler) scoreApplications(ctx context.Context, ids []string, jobID string, progress func(bulkScoringProgress)) BulkScoringResult {
+	var applications map[string]interface{}
+	if jobID != "" {
+		applications = h.currentStatuses(ctx, ids)
+	}
+
+	result := BulkScoringResult{Succeeded: []string{}, Failed: []BulkScoringError{}}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, h.scoringConcurrency)
+
+	for _, id := range ids {
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			var err error
+			if jobID != "" && lookupString(applications[id], "job", "id") != jobID {
+				err = fmt.Errorf("application is not for job %s", jobID)
+			} else {
+				err = h.scoreApplication(ctx, id)
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+
+			update := bulkScoringProgress{ApplicationID: id, Succeeded: err == nil, Total: len(ids)}
+			if err != nil {
+				h.logger.Warn("failed to score application", "application_id", id, "error", err)
+				result.Failed = append(result.Failed, BulkScoringError{ApplicationID: id, Error: err.Error()})
+				update.Error = err.Error()
+			} else {
+				result.Succeeded = append(result.Succeeded, id)
+			}
+			update.Completed = len(result.Succeeded) + len(result.Failed)
+			if progress != nil {
+				progress(update)
+			}
+		}(id)
+	}
+
+	wg.Wait()
+	return result
+}
+
+// scoreApplication runs AI scoring for one application, treating GraphQL
+// errors and a missing result as failures
+func (h *ApplicationHandler) scoreApplication(ctx context.Context, id string) error {
+	resp, err := h.client.Mutate(ctx, gateway.ScoreApplicationMutation, map[string]interface{}{
+		"applicationId": id,
+	})
+	switch {
+	case err != nil:
+		return err
+	case len(resp.Errors) > 0:
+		return errors.New(resp.Errors[0].Message)
+	case lookup(resp.Data, "scoreApplication") == nil:
+		return errors.New("application not found")
+	}
+	return nil
+}
+
+// uniqueStrings drops empty and repeated values, keeping the first occurrence
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if value == "" || seen[value] {
+			continue
+		}
+		seen[value] = true
+		unique = append(unique, value)
+	}
+	return unique
+}
+
+// AddNote adds a note to an application
+func (h *ApplicationHandler) AddNote(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	appID := chi.URLParam(r, "id")
+
+	if appID == "" {
+		respondError(w, http.StatusBadRequest, "Application ID is required", nil)
+		return
+	}
+
+	var input struct {
+		Content    string   `json:"content"`
+		IsInternal bool     `json:"isInternal"`
+		Tags       []string `json:"tags"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if input.Content == "" {
+		respondError(w, http.StatusBadRequest, "Note content is required", nil)
+		return
+	}
+
+	tags, err := normalizeNoteTags(input.Tags)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid note tags", err)
+		return
+	}
+
+	variables := map[string]interface{}{
+		"applicationId": appID,
+		"content":       input.Content,
+		"isInternal":    input.IsInternal,
+		"tags":          tags,
+	}
+
+	resp, err := h.client.Mutate(ctx, gateway.AddApplicationNoteMutation, variables)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to add note", err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, resp.Data)
+}
+
+// Note tag limits
+const (
+	maxNoteTags      = 10
+	maxNoteTagLength = 32
+)
+
+// noteTagPattern is the form tags are stored in: lower case words joined by
+// hyphens, such as culture-fit
+var noteTagPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// normalizeNoteTags lower-cases and trims tags, turning spaces into hyphens
+// and dropping blanks and repeats
+func normalizeNoteTags(tags []string) ([]string, error) {
+	normalized := []string{}
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+		if tag == "" || seen[tag] {
+			continue
+		}
+		if len(tag) > maxNoteTagLength || !noteTagPattern.MatchString(tag) {
+			return nil, fmt.Errorf("tag %q must be at most %d letters, digits and hyphens", tag, maxNoteTagLength)
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	if len(normalized) > maxNoteTags {
+		return nil, fmt.Errorf("a note can have at most %d tags", maxNoteTags)
+	}
+	return normalized, nil
+}
+
+// GetNotes lists an application's notes, optionally only those with the tag
+// given in ?tag=
+func (h *ApplicationHandler) GetNotes(w http.ResponseWriter, r *http.Request) {
+	variables := map[string]interface{}{
+		"applicationId": chi.URLParam(r, "id"),
+	}
+	if tag := r.URL.Query().Get("tag"); tag != "" {
+		variables["tag"] = strings.Join(strings.Fields(strings.ToLower(tag)), "-")
+	}
+
+	resp, err := h.client.Query(r.Context(), gateway.GetApplicationNotesQuery, variables)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch notes", err)
+		return
+	}
+
+	notes, _ := lookup(resp.Data, "applicationNotes").([]interface{})
+	if notes == nil {
+		notes = []interface{}{}
+	}
+	respondJSON(w, http.StatusOK, map[string]interface{}{"notes": notes})
+}
+
+// GetNoteTags lists every tag used on notes, sorted, for autocomplete
+func (h *ApplicationHandler) GetNoteTags(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.Query(r.Context(), gateway.GetNoteTagsQuery, nil)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch note tags", err)
+		return
+	}
+
+	// Tags saved before normalization may differ only in case or spacing
+	raw, _ := lookup(resp.Data, "noteTags").([]interface{})
+	names := make([]string, 0, len(raw))
+	for _, tag := range raw {
+		if name, ok := tag.(string); ok {
+			names = append(names, name)
+		}
+	}
+	tags := uniqueNoteTags(names)
+	respondJSON(w, http.StatusOK, map[string]interface{}{"tags": tags})
+}
+
+// uniqueNoteTags normalizes tags, dropping any that cannot be normalized,
+// and returns each distinct one once in sorted order
+func uniqueNoteTags(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	tags := []string{}
+	for _, name := range names {
+		normalized, err := normalizeNoteTags([]string{name})
+		if err != nil || len(normalized) == 0 || seen[normalized[0]] {
+			continue
+		}
+		seen[normalized[0]] = true
+		tags = append(tags, normalized[0])
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// ScoreApplication triggers AI scoring for an application
+func (h *ApplicationHandler) ScoreApplication(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	appID := chi.URLParam(r, "id")
+
+	if appID == "" {
+		respondError(w, http.StatusBadRequest, "Application ID is required", nil)
+		return
+	}
+	if !h.features.AIScoring.Load() {
+		respondError(w, http.StatusServiceUnavailable, "AI scoring is disabled", nil)
+		return
+	}
+
+	variables := map[string]interface{}{
+		"applicationId": appID,
+	}
+
+	resp, err := h.client.Mutate(ctx, gateway.ScoreApplicationMutation, variables)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to score application", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, resp.Data)
+}
 
 // GetCandidate returns candidate information
 func (h *ApplicationHandler) GetCandidate(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	candidateID := chi.URLParam(r, "id")
-	
+
 	if candidateID == "" {
 		respondError(w, http.StatusBadRequest, "Candidate ID is required", nil)
 		return
@@ -338,14 +2121,71 @@ func (h *ApplicationHandler) GetCandidate(w http.ResponseWriter, r *http.Request
 		return
 	}
 
+	h.addResumeKey(lookup(resp.Data, "candidate"))
+
 	respondJSON(w, http.StatusOK, resp.Data)
 }
 
+// DownloadCandidateResume streams the resume on a candidate's profile
+func (h *ApplicationHandler) DownloadCandidateResume(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	candidateID := chi.URLParam(r, "id")
+
+	if candidateID == "" {
+		respondError(w, http.StatusBadRequest, "Candidate ID is required", nil)
+		return
+	}
+
+	resp, err := h.client.Query(ctx, gateway.GetCandidateQuery, map[string]interface{}{
+		"id": candidateID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch candidate", err)
+		return
+	}
+
+	candidate := lookup(resp.Data, "candidate")
+	if candidate == nil {
+		respondError(w, http.StatusNotFound, "Candidate not found", nil)
+		return
+	}
+
+	h.serveResume(w, r, lookupString(candidate, "resumeUrl"))
+}
+
+// ListCandidateFiles returns the files a candidate has uploaded
+func (h *ApplicationHandler) ListCandidateFiles(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	candidateID := chi.URLParam(r, "id")
+
+	resp, err := h.client.Query(ctx, gateway.GetCandidateQuery, map[string]interface{}{
+		"id": candidateID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch candidate", err)
+		return
+	}
+
+	email := lookupString(resp.Data, "candidate", "email")
+	if email == "" {
+		respondError(w, http.StatusNotFound, "Candidate not found", nil)
+		return
+	}
+
+	files, err := h.uploadService.ListFiles(ctx, email)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to list candidate files", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"files": files})
+}
+
 // UpdateCandidate updates candidate profile
 func (h *ApplicationHandler) UpdateCandidate(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 	candidateID := chi.URLParam(r, "id")
-	
+
 	if candidateID == "" {
 		respondError(w, http.StatusBadRequest, "Candidate ID is required", nil)
 		return
@@ -370,4 +2210,228 @@ func (h *ApplicationHandler) UpdateCandidate(w http.ResponseWriter, r *http.Requ
 	}
 
 	respondJSON(w, http.StatusOK, resp.Data)
-}
\ No newline at end of file
+}
+
+// candidateDataPurpose scopes privacy tokens to a candidate's own data requests
+const candidateDataPurpose = "candidate-data"
+
+// RequestDataExportToken emails a candidate a short-lived link to download
+// their personal data. The response is the same whether or not the candidate
+// exists, so the endpoint cannot be used to probe for candidates.
+func (h *ApplicationHandler) RequestDataExportToken(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	candidateID := chi.URLParam(r, "id")
+
+	if candidateID == "" {
+		respondError(w, http.StatusBadRequest, "Candidate ID is required", nil)
+		return
+	}
+
+	resp, err := h.client.Query(ctx, gateway.GetCandidateQuery, map[string]interface{}{
+		"id": candidateID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch candidate", err)
+		return
+	}
+
+	if email := lookupString(resp.Data, "candidate", "email"); email != "" {
+		token := h.privacyTokens.Sign(candidateID, candidateDataPurpose)
+		link := fmt.Sprintf("%s/api/v1/candidates/%s/data-export?token=%s",
+			h.baseURL, url.PathEscape(candidateID), url.QueryEscape(token))
+		firstName := lookupString(resp.Data, "candidate", "firstName")
+
+		if err := h.emailQueue.Enqueue(services.DataExportLinkEmail(email, firstName, link, h.privacyTokens.TTL())); err != nil {
+			h.logger.Error("failed to queue data export email", "error", err)
+		}
+	}
+
+	respondJSON(w, http.StatusAccepted, map[string]interface{}{
+		"message": "If this candidate exists, a download link has been sent to their email address",
+	})
+}
+
+// ExportCandidateData returns everything held about a candidate as a JSON
+// download. Access is granted by a token from RequestDataExportToken.
+func (h *ApplicationHandler) ExportCandidateData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	candidateID, ok := h.authorizeCandidate(w, r)
+	if !ok {
+		return
+	}
+
+	candidate, err := h.client.Query(ctx, gateway.GetCandidateQuery, map[string]interface{}{
+		"id": candidateID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch candidate", err)
+		return
+	}
+	if lookup(candidate.Data, "candidate") == nil {
+		respondError(w, http.StatusNotFound, "Candidate not found", nil)
+		return
+	}
+
+	applications, err := h.candidateApplications(ctx, candidateID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch applications", err)
+		return
+	}
+
+	w.Header().Set("Content-Disposition", "attachment; filename=my-data.json")
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"candidate":    lookup(candidate.Data, "candidate"),
+		"applications": applications,
+		"exportedAt":   time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// retainedStatuses are application stages whose records must be kept for
+// legal reasons, blocking erasure
+var retainedStatuses = map[string]bool{
+	"OFFER": true,
+	"HIRED": true,
+}
+
+// DeleteCandidateData erases a candidate's personal data: contact details are
+// replaced with placeholders in Hub-HRMS and uploaded resumes are deleted.
+// Access is granted by the same token as ExportCandidateData.
+func (h *ApplicationHandler) DeleteCandidateData(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	candidateID, ok := h.authorizeCandidate(w, r)
+	if !ok {
+		return
+	}
+
+	candidate, err := h.client.Query(ctx, gateway.GetCandidateQuery, map[string]interface{}{
+		"id": candidateID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch candidate", err)
+		return
+	}
+	if lookup(candidate.Data, "candidate") == nil {
+		respondError(w, http.StatusNotFound, "Candidate not found", nil)
+		return
+	}
+
+	// Every application is checked, so one at the offer stage cannot hide
+	// beyond the first page
+	applications, err := h.candidateApplications(ctx, candidateID)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch applications", err)
+		return
+	}
+
+	resumeURLs := []string{lookupString(candidate.Data, "candidate", "resumeUrl")}
+	for _, application := range applications {
+		if retainedStatuses[lookupString(application, "status")] {
+			respondError(w, http.StatusConflict,
+				"Your data cannot be deleted while an application is at the offer or hired stage, as we are legally required to keep these records", nil)
+			return
+		}
+		resumeURLs = append(resumeURLs, lookupString(application, "resumeUrl"))
+	}
+
+	// Read before anonymizing, since the confirmation goes to the old address
+	email := lookupString(candidate.Data, "candidate", "email")
+	firstName := lookupString(candidate.Data, "candidate", "firstName")
+
+	_, err = h.client.Mutate(ctx, gateway.AnonymizeCandidateMutation, map[string]interface{}{
+		"id": candidateID,
+		"input": map[string]interface{}{
+			"firstName": "Deleted",
+			"lastName":  "Candidate",
+			"email":     fmt.Sprintf("deleted-%s@anonymized.invalid", candidateID),
+			"phone":     "",
+		},
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to erase candidate data", err)
+		return
+	}
+
+	deleted := make(map[string]bool)
+	for _, resumeURL := range resumeURLs {
+		key, ok := h.uploadService.KeyFromURL(resumeURL)
+		if !ok || deleted[key] {
+			continue
+		}
+		deleted[key] = true
+		if err := h.uploadService.DeleteFile(ctx, key); err != nil {
+			h.logger.Error("failed to delete resume of erased candidate", "key", key, "candidate_id", candidateID, "error", err)
+		}
+	}
+
+	requestID := uuid.New().String()
+	h.logger.Info("erased candidate personal data", "candidate_id", candidateID, "request_id", requestID)
+
+	if email != "" {
+		if err := h.emailQueue.Enqueue(services.ErasureConfirmationEmail(email, firstName, requestID)); err != nil {
+			h.logger.Error("failed to queue erasure confirmation email", "error", err)
+		}
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"requestId":   requestID,
+		"candidateId": candidateID,
+		"erasedAt":    time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// candidateApplicationPageSize is how many of a candidate's applications are
+// fetched per query
+const candidateApplicationPageSize = 100
+
+// candidateApplications pages through every application of a candidate
+func (h *ApplicationHandler) candidateApplications(ctx context.Context, candidateID string) ([]interface{}, error) {
+	applications := []interface{}{}
+	for offset := 0; ; offset += candidateApplicationPageSize {
+		resp, err := h.client.Query(ctx, gateway.GetApplicationsQuery, map[string]interface{}{
+			"filters": map[string]interface{}{"candidateId": candidateID},
+			"limit":   candidateApplicationPageSize,
+			"offset":  offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		page, _ := lookup(resp.Data, "applications").([]interface{})
+		applications = append(applications, page...)
+		if len(page) < candidateApplicationPageSize {
+			return applications, nil
+		}
+	}
+}
+
+// authorizeCandidate checks the privacy token sent with r against the
+// candidate in the URL, writing a 401 if it does not match
+func (h *ApplicationHandler) authorizeCandidate(w http.ResponseWriter, r *http.Request) (string, bool) {
+	candidateID := chi.URLParam(r, "id")
+	if candidateID == "" {
+		respondError(w, http.StatusBadRequest, "Candidate ID is required", nil)
+		return "", false
+	}
+
+	token := r.URL.Query().Get("token")
+	if token == "" {
+		token = r.Header.Get("X-Privacy-Token")
+	}
+	if token == "" {
+		respondError(w, http.StatusUnauthorized, "A data request token is required", nil)
+		return "", false
+	}
+
+	if err := h.privacyTokens.Verify(token, candidateID, candidateDataPurpose); err != nil {
+		message := "Invalid data request token"
+		if errors.Is(err, util.ErrExpiredToken) {
+			message = "Data request token has expired"
+		}
+		respondError(w, http.StatusUnauthorized, message, err)
+		return "", false
+	}
+
+	return candidateID, true
+}
@@ -0,0 +1,2683 @@
+package handlers
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/config"
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/middleware"
+	"hr-recruiting/internal/services"
+	"hr-recruiting/internal/util"
+)
+
+// newTestApplicationHandler returns an ApplicationHandler talking to a fake
+// Hub-HRMS that answers with respond, recording emails instead of sending
+// them. Requests respond has no answer for should return an empty map.
+func newTestApplicationHandler(t *testing.T, respond func(gateway.GraphQLRequest) interface{}) (*ApplicationHandler, *fakeHubHRMS, *recordingEmailQueue) {
+	t.Helper()
+	fake, client := newFakeHubHRMS(t, respond)
+
+	dedupStore := services.NewMemoryDeduplicationStore()
+	t.Cleanup(func() { dedupStore.Close() })
+	skills, err := services.LoadSkillNormalizer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	gcal, err := services.NewGoogleCalendarService(context.Background(), "", "primary", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h := NewApplicationHandler(ApplicationHandlerOptions{
+		Client:         client,
+		UploadService:  services.NewUploadService("resumes", "eu-west-1", client, 10),
+		EmailService:   services.NewEmailService(""),
+		DedupStore:     dedupStore,
+		DedupWindow:    time.Hour,
+		Webhooks:       services.NewWebhookService(func() bool { return false }),
+		PipelineEvents: services.NewPipelineEventBus(),
+		Stages:         services.NewPipelineStageService(client, time.Minute),
+		Features:       &config.FeatureFlags{},
+		PrivacyTokens:  util.NewTokenSigner("test-secret", time.Hour),
+		BaseURL:        "https://careers.example.com/",
+		Calendar:       services.NewCalendarService("Recruiting", "recruiting@example.com"),
+		GCal:           gcal,
+		Preferences:    services.NewNotificationPreferenceStore(client, time.Minute),
+		Skills:         skills,
+		ShareLinks:     services.NewShareLinkService(client, "test-secret"),
+		Chat:           services.NewNotificationBroadcaster(),
+	})
+	emails := &recordingEmailQueue{}
+	h.emailQueue = emails
+	return h, fake, emails
+}
+
+// submitApplicationFake accepts every submission, numbering the
+// applications it creates
+func submitApplicationFake() func(gateway.GraphQLRequest) interface{} {
+	submitted := 0
+	return func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.SubmitApplicationMutation {
+			return map[string]interface{}{}
+		}
+		submitted++
+		return map[string]interface{}{"submitApplication": map[string]interface{}{
+			"id":     fmt.Sprintf("app-%d", submitted),
+			"status": "NEW",
+			"job":    map[string]interface{}{"title": "Backend Engineer"},
+		}}
+	}
+}
+
+// testApplication is a submission with every base field filled in
+func testApplication(email string) string {
+	application, _ := json.Marshal(map[string]interface{}{
+		"jobId":           "job-1",
+		"firstName":       "Ada",
+		"lastName":        "Lovelace",
+		"email":           email,
+		"phone":           "+44 20 7946 0000",
+		"resumeUrl":       "https://cdn.example.com/ada.pdf",
+		"currentLocation": "London",
+		"availability":    "immediately",
+	})
+	return string(application)
+}
+
+func TestApplicationHandler_SubmitApplication_Duplicate(t *testing.T) {
+	submit := func(h *ApplicationHandler, email string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.SubmitApplication(rec, httptest.NewRequest(http.MethodPost, "/applications", strings.NewReader(testApplication(email))))
+		return rec
+	}
+
+	t.Run("repeat submission rejected", func(t *testing.T) {
+		h, fake, _ := newTestApplicationHandler(t, submitApplicationFake())
+		h.features.ApplicationDeduplication.Store(true)
+
+		if rec := submit(h, "ada@example.com"); rec.Code != http.StatusCreated {
+			t.Fatalf("first submission status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+		}
+		rec := submit(h, " Ada@Example.com")
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("repeat submission status = %d, want %d", rec.Code, http.StatusConflict)
+		}
+		var body struct {
+			Details map[string]string `json:"details"`
+		}
+		json.NewDecoder(rec.Body).Decode(&body)
+		if body.Details["existingApplicationId"] != "app-1" {
+			t.Fatalf("details = %v, want existingApplicationId app-1", body.Details)
+		}
+		if sent := fake.sent(gateway.SubmitApplicationMutation); sent != 1 {
+			t.Fatalf("application submitted to Hub-HRMS %d times, want 1", sent)
+		}
+	})
+
+	t.Run("deduplication disabled", func(t *testing.T) {
+		h, fake, _ := newTestApplicationHandler(t, submitApplicationFake())
+
+		for i := 0; i < 2; i++ {
+			if rec := submit(h, "ada@example.com"); rec.Code != http.StatusCreated {
+				t.Fatalf("submission %d status = %d, want %d", i+1, rec.Code, http.StatusCreated)
+			}
+		}
+		if sent := fake.sent(gateway.SubmitApplicationMutation); sent != 2 {
+			t.Fatalf("application submitted to Hub-HRMS %d times, want 2", sent)
+		}
+	})
+}
+
+// reportApplicationFake answers GetApplicationQuery for application app-1,
+// which has a shared and an internal note
+func reportApplicationFake(req gateway.GraphQLRequest) interface{} {
+	if req.Query != gateway.GetApplicationQuery || req.Variables["id"] != "app-1" {
+		return map[string]interface{}{"application": nil}
+	}
+	return map[string]interface{}{"application": map[string]interface{}{
+		"id":          "app-1",
+		"status":      "INTERVIEW",
+		"appliedDate": "2026-02-27T10:00:00Z",
+		"candidate":   map[string]interface{}{"firstName": "Ada", "lastName": "Lovelace", "email": "ada@example.com"},
+		"job":         map[string]interface{}{"title": "Backend Engineer", "requirements": []interface{}{"Go"}},
+		"aiScore":     map[string]interface{}{"overall": 87, "strengths": []interface{}{"Distributed systems"}},
+		"notes": []interface{}{
+			map[string]interface{}{"content": "Great take-home exercise", "isInternal": false, "author": map[string]interface{}{"name": "Grace Hopper"}},
+			map[string]interface{}{"content": "Salary expectations above band", "isInternal": true, "author": map[string]interface{}{"name": "Grace Hopper"}},
+		},
+		"timeline": []interface{}{
+			map[string]interface{}{"timestamp": "2026-02-27T10:00:00Z", "description": "Application submitted"},
+		},
+	}}
+}
+
+func TestApplicationHandler_ApplicationReport(t *testing.T) {
+	fakePDFConverter(t)
+	h, _, _ := newTestApplicationHandler(t, reportApplicationFake)
+
+	// The route as the server mounts it
+	r := chi.NewRouter()
+	r.With(middleware.RequireRole("recruiter", "admin")).Get("/applications/{id}/report.pdf", h.ApplicationReport)
+
+	get := func(id string, roles ...string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/applications/"+id+"/report.pdf", nil)
+		if roles != nil {
+			req = asUser(req, "user-1", roles...)
+		}
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("recruiter", func(t *testing.T) {
+		rec := get("app-1", "recruiter")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		if got := rec.Header().Get("Content-Type"); got != "application/pdf" {
+			t.Fatalf("Content-Type = %q, want application/pdf", got)
+		}
+		if got := rec.Header().Get("Content-Disposition"); got != `attachment; filename="application-app-1.pdf"` {
+			t.Fatalf("Content-Disposition = %q", got)
+		}
+		if got := rec.Header().Get("Content-Length"); got != fmt.Sprint(rec.Body.Len()) {
+			t.Fatalf("Content-Length = %s, body is %d bytes", got, rec.Body.Len())
+		}
+
+		report := rec.Body.String()
+		if !strings.HasPrefix(report, "%PDF-") {
+			t.Fatalf("body = %.40q, want a PDF", report)
+		}
+		for _, want := range []string{"Application Report: Ada Lovelace", "Backend Engineer", "Distributed systems", "Great take-home exercise", "Application submitted"} {
+			if !strings.Contains(report, want) {
+				t.Errorf("report is missing %q", want)
+			}
+		}
+		if strings.Contains(report, "Salary expectations above band") || strings.Contains(report, "(internal)") {
+			t.Fatal("report for a recruiter includes an internal note")
+		}
+	})
+
+	t.Run("admin sees internal notes", func(t *testing.T) {
+		rec := get("app-1", "admin")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		if report := rec.Body.String(); !strings.Contains(report, "Salary expectations above band") || !strings.Contains(report, "(internal)") {
+			t.Fatal("report for an admin is missing the internal note")
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		for _, tt := range []struct {
+			name       string
+			id         string
+			roles      []string
+			wantStatus int
+		}{
+			{name: "anonymous", id: "app-1", wantStatus: http.StatusUnauthorized},
+			{name: "candidate", id: "app-1", roles: []string{"candidate"}, wantStatus: http.StatusForbidden},
+			{name: "unknown application", id: "app-2", roles: []string{"recruiter"}, wantStatus: http.StatusNotFound},
+		} {
+			if rec := get(tt.id, tt.roles...); rec.Code != tt.wantStatus {
+				t.Errorf("%s: status = %d, want %d", tt.name, rec.Code, tt.wantStatus)
+			}
+		}
+	})
+}
+
+// candidateDataFake answers the candidate and application queries for
+// candidate cand-1, who has applied twice
+func candidateDataFake(req gateway.GraphQLRequest) interface{} {
+	switch req.Query {
+	case gateway.GetCandidateQuery:
+		if req.Variables["id"] != "cand-1" {
+			return map[string]interface{}{"candidate": nil}
+		}
+		return map[string]interface{}{"candidate": map[string]interface{}{
+			"id": "cand-1", "firstName": "Ada", "lastName": "Lovelace", "email": "ada@example.com",
+		}}
+	case gateway.GetApplicationsQuery:
+		filters, _ := req.Variables["filters"].(map[string]interface{})
+		if filters["candidateId"] != "cand-1" {
+			return map[string]interface{}{"applications": []interface{}{}}
+		}
+		return map[string]interface{}{"applications": []interface{}{
+			map[string]interface{}{"id": "app-1", "status": "REJECTED"},
+			map[string]interface{}{"id": "app-2", "status": "SCREENING"},
+		}}
+	}
+	return map[string]interface{}{}
+}
+
+func TestApplicationHandler_CandidateDataExport(t *testing.T) {
+	h, fake, emails := newTestApplicationHandler(t, candidateDataFake)
+
+	// The routes as the server mounts them
+	r := chi.NewRouter()
+	r.Post("/api/v1/candidates/{id}/data-export-token", h.RequestDataExportToken)
+	r.Get("/api/v1/candidates/{id}/data-export", h.ExportCandidateData)
+
+	export := func(candidateID, token string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/candidates/"+candidateID+"/data-export?token="+url.QueryEscape(token), nil))
+		return rec
+	}
+
+	var token string
+	t.Run("request token", func(t *testing.T) {
+		for _, id := range []string{"cand-1", "cand-unknown"} {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/candidates/"+id+"/data-export-token", nil))
+			if rec.Code != http.StatusAccepted {
+				t.Fatalf("%s: status = %d, want %d", id, rec.Code, http.StatusAccepted)
+			}
+		}
+
+		// Only the existing candidate is emailed, though both get the same answer
+		jobs := emails.enqueued()
+		if len(jobs) != 1 || jobs[0].To != "ada@example.com" {
+			t.Fatalf("emails = %+v, want one to the candidate", jobs)
+		}
+		link, err := url.Parse(jobs[0].Data["Link"].(string))
+		if err != nil || link.Host != "careers.example.com" || link.Path != "/api/v1/candidates/cand-1/data-export" {
+			t.Fatalf("link = %v", jobs[0].Data["Link"])
+		}
+		token = link.Query().Get("token")
+	})
+
+	t.Run("valid token", func(t *testing.T) {
+		rec := export("cand-1", token)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		if got := rec.Header().Get("Content-Disposition"); got != "attachment; filename=my-data.json" {
+			t.Fatalf("Content-Disposition = %q", got)
+		}
+		var body struct {
+			Candidate    map[string]interface{}   `json:"candidate"`
+			Applications []map[string]interface{} `json:"applications"`
+			ExportedAt   string                   `json:"exportedAt"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if body.Candidate["email"] != "ada@example.com" || len(body.Applications) != 2 || body.ExportedAt == "" {
+			t.Fatalf("export = %+v", body)
+		}
+
+		// The token may also be sent as a header
+		rec = httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/candidates/cand-1/data-export", nil)
+		req.Header.Set("X-Privacy-Token", token)
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("header token: status = %d, want %d", rec.Code, http.StatusOK)
+		}
+	})
+
+	t.Run("rejected tokens", func(t *testing.T) {
+		exports := fake.sent(gateway.GetApplicationsQuery)
+		for _, tt := range []struct {
+			name        string
+			candidateID string
+			token       string
+			wantMessage string
+		}{
+			{name: "expired", candidateID: "cand-1", token: util.NewTokenSigner("test-secret", -time.Second).Sign("cand-1", candidateDataPurpose), wantMessage: "expired"},
+			{name: "tampered", candidateID: "cand-1", token: "f" + token[1:], wantMessage: "Invalid"},
+			{name: "other secret", candidateID: "cand-1", token: util.NewTokenSigner("other-secret", time.Hour).Sign("cand-1", candidateDataPurpose), wantMessage: "Invalid"},
+			{name: "other candidate", candidateID: "cand-2", token: token, wantMessage: "Invalid"},
+			{name: "missing", candidateID: "cand-1", token: "", wantMessage: "required"},
+		} {
+			rec := export(tt.candidateID, tt.token)
+			if rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), tt.wantMessage) {
+				t.Errorf("%s: status = %d, body = %s, want 401 saying %q", tt.name, rec.Code, rec.Body, tt.wantMessage)
+			}
+		}
+		if sent := fake.sent(gateway.GetApplicationsQuery); sent != exports {
+			t.Fatal("data was fetched for a rejected token")
+		}
+	})
+}
+
+// erasureFake answers the queries DeleteCandidateData makes for candidate
+// cand-1, whose applications have statuses, and keeps the anonymization
+// variables. Applications are paged as Hub-HRMS pages them.
+func erasureFake(anonymized chan<- map[string]interface{}, statuses ...string) func(gateway.GraphQLRequest) interface{} {
+	return func(req gateway.GraphQLRequest) interface{} {
+		switch req.Query {
+		case gateway.GetCandidateQuery:
+			return map[string]interface{}{"candidate": map[string]interface{}{
+				"id": "cand-1", "firstName": "Ada", "email": "ada@example.com",
+				"resumeUrl": "https://resumes.s3.amazonaws.com/resumes/cand-1/cv.pdf",
+			}}
+		case gateway.GetApplicationsQuery:
+			limit, _ := req.Variables["limit"].(float64)
+			offset, _ := req.Variables["offset"].(float64)
+			page := []interface{}{}
+			for i := int(offset); i < len(statuses) && i < int(offset+limit); i++ {
+				page = append(page, map[string]interface{}{
+					"id":        fmt.Sprintf("app-%d", i),
+					"status":    statuses[i],
+					"resumeUrl": fmt.Sprintf("https://resumes.s3.amazonaws.com/resumes/cand-1/cv-%d.pdf", i%2),
+				})
+			}
+			return map[string]interface{}{"applications": page}
+		case gateway.AnonymizeCandidateMutation:
+			anonymized <- req.Variables
+			return map[string]interface{}{"anonymizeCandidate": map[string]interface{}{"id": req.Variables["id"]}}
+		}
+		return map[string]interface{}{}
+	}
+}
+
+func TestApplicationHandler_DeleteCandidateData(t *testing.T) {
+	// More applications than fit on one page
+	statuses := make([]string, candidateApplicationPageSize+1)
+	for i := range statuses {
+		statuses[i] = "REJECTED"
+	}
+
+	erase := func(h *ApplicationHandler, token string) *httptest.ResponseRecorder {
+		r := chi.NewRouter()
+		r.Delete("/api/v1/candidates/{id}", h.DeleteCandidateData)
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/candidates/cand-1", nil)
+		req.Header.Set("X-Privacy-Token", token)
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("erases personal data", func(t *testing.T) {
+		objects := newFakeS3(t)
+		anonymized := make(chan map[string]interface{}, 1)
+		h, fake, emails := newTestApplicationHandler(t, erasureFake(anonymized, statuses...))
+
+		rec := erase(h, h.privacyTokens.Sign("cand-1", candidateDataPurpose))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		var body map[string]string
+		json.NewDecoder(rec.Body).Decode(&body)
+		if body["requestId"] == "" || body["candidateId"] != "cand-1" {
+			t.Fatalf("response = %v", body)
+		}
+		if pages := fake.sent(gateway.GetApplicationsQuery); pages != 2 {
+			t.Fatalf("fetched %d pages of applications, want 2", pages)
+		}
+
+		input, _ := (<-anonymized)["input"].(map[string]interface{})
+		if input["email"] != "deleted-cand-1@anonymized.invalid" || input["firstName"] != "Deleted" || input["phone"] != "" {
+			t.Fatalf("anonymized input = %v", input)
+		}
+
+		var deleted []string
+		for _, object := range objects() {
+			if object.method != http.MethodDelete {
+				t.Fatalf("unexpected S3 request %s %s", object.method, object.key)
+			}
+			deleted = append(deleted, object.key)
+		}
+		if strings.Join(deleted, ",") != "/resumes/resumes/cand-1/cv.pdf,/resumes/resumes/cand-1/cv-0.pdf,/resumes/resumes/cand-1/cv-1.pdf" {
+			t.Fatalf("deleted %v, want each resume once", deleted)
+		}
+
+		jobs := emails.enqueued()
+		if len(jobs) != 1 || jobs[0].To != "ada@example.com" || jobs[0].Data["RequestID"] != body["requestId"] {
+			t.Fatalf("emails = %+v, want a confirmation to the old address", jobs)
+		}
+	})
+
+	t.Run("active offer blocks erasure", func(t *testing.T) {
+		objects := newFakeS3(t)
+		anonymized := make(chan map[string]interface{}, 1)
+		// The offer is on the second page
+		h, fake, emails := newTestApplicationHandler(t, erasureFake(anonymized, append(statuses, "OFFER")...))
+
+		rec := erase(h, h.privacyTokens.Sign("cand-1", candidateDataPurpose))
+		if rec.Code != http.StatusConflict || !strings.Contains(rec.Body.String(), "legally required") {
+			t.Fatalf("status = %d, body = %s, want 409 explaining the records are kept", rec.Code, rec.Body)
+		}
+		if fake.sent(gateway.AnonymizeCandidateMutation) != 0 || len(objects()) != 0 || len(emails.enqueued()) != 0 {
+			t.Fatal("candidate with an offer was erased")
+		}
+	})
+
+	t.Run("requires a token", func(t *testing.T) {
+		anonymized := make(chan map[string]interface{}, 1)
+		h, fake, _ := newTestApplicationHandler(t, erasureFake(anonymized))
+		if rec := erase(h, h.privacyTokens.Sign("cand-2", candidateDataPurpose)); rec.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+		if fake.sent(gateway.AnonymizeCandidateMutation) != 0 {
+			t.Fatal("candidate erased with another candidate's token")
+		}
+	})
+}
+
+func TestApplicationHandler_ExportApplications(t *testing.T) {
+	applications := []interface{}{
+		map[string]interface{}{
+			"id": "app-1", "status": "INTERVIEW", "appliedDate": "2026-10-01",
+			"candidate": map[string]interface{}{
+				"firstName": "Ada", "lastName": `Lovelace, "Countess"`, "email": "ada@example.com",
+				"location": "London,\nUK", "yearsOfExperience": 12.0,
+			},
+			"job":     map[string]interface{}{"title": "Analyst, Engines"},
+			"aiScore": map[string]interface{}{"overall": 87.5},
+		},
+		map[string]interface{}{
+			"id": "app-2", "status": "NEW",
+			"candidate": map[string]interface{}{"firstName": "Grace", "email": "grace@example.com"},
+			"job":       map[string]interface{}{"title": "Engineer"},
+		},
+	}
+	h, fake, _ := newTestApplicationHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query == gateway.ExportApplicationsQuery {
+			return map[string]interface{}{"applications": applications}
+		}
+		return map[string]interface{}{}
+	})
+
+	rec := httptest.NewRecorder()
+	h.ExportApplications(rec, httptest.NewRequest(http.MethodGet, "/api/v1/applications/export?status=INTERVIEW&minScore=80", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/csv" {
+		t.Fatalf("status = %d, content type = %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	wantDisposition := "attachment; filename=applications-" + time.Now().Format("2006-01-02") + ".csv"
+	if got := rec.Header().Get("Content-Disposition"); got != wantDisposition {
+		t.Fatalf("Content-Disposition = %q, want %q", got, wantDisposition)
+	}
+
+	raw := rec.Body.String()
+	if !strings.Contains(raw, `"Ada Lovelace, ""Countess"""`) || !strings.Contains(raw, "\"London,\nUK\"") {
+		t.Fatalf("commas, quotes and newlines were not quoted:\n%s", raw)
+	}
+
+	rows, err := csv.NewReader(strings.NewReader(raw)).ReadAll()
+	if err != nil {
+		t.Fatalf("export is not valid CSV: %v", err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want the header and 2 applications", len(rows))
+	}
+	if !slices.Equal(rows[0], exportColumns) || len(exportColumns) != 9 {
+		t.Fatalf("header = %q", rows[0])
+	}
+	want := [][]string{
+		{"app-1", `Ada Lovelace, "Countess"`, "ada@example.com", "Analyst, Engines", "INTERVIEW", "2026-10-01", "87.5", "London,\nUK", "12"},
+		{"app-2", "Grace", "grace@example.com", "Engineer", "NEW", "", "", "", ""},
+	}
+	for i, row := range rows[1:] {
+		if !slices.Equal(row, want[i]) {
+			t.Errorf("row %d = %q, want %q", i+1, row, want[i])
+		}
+	}
+
+	// The list filters and row cap are passed to Hub-HRMS
+	fake.mu.Lock()
+	variables := fake.requests[len(fake.requests)-1].Variables
+	fake.mu.Unlock()
+	filters, _ := variables["filters"].(map[string]interface{})
+	if variables["limit"] != float64(maxExportRows) || filters["status"] != "INTERVIEW" || filters["minScore"] != 80.0 {
+		t.Fatalf("variables = %v", variables)
+	}
+}
+
+func TestApplicationHandler_UpdateStatus_Transitions(t *testing.T) {
+	tests := []struct {
+		name       string
+		strict     bool
+		current    string
+		status     string
+		wantStatus int
+	}{
+		{name: "strict allowed", strict: true, current: "INTERVIEW", status: "OFFER", wantStatus: http.StatusOK},
+		{name: "strict rejection", strict: true, current: "INTERVIEW", status: "REJECTED", wantStatus: http.StatusOK},
+		{name: "strict skipped stage", strict: true, current: "INTERVIEW", status: "HIRED", wantStatus: http.StatusUnprocessableEntity},
+		{name: "strict from final", strict: true, current: "REJECTED", status: "SCREENING", wantStatus: http.StatusUnprocessableEntity},
+		{name: "strict unknown status", strict: true, current: "APPLIED", status: "ARCHIVED", wantStatus: http.StatusUnprocessableEntity},
+		{name: "strict missing application", strict: true, status: "SCREENING", wantStatus: http.StatusNotFound},
+		{name: "lenient skipped stage", current: "INTERVIEW", status: "HIRED", wantStatus: http.StatusOK},
+		{name: "lenient unknown status", current: "APPLIED", status: "ARCHIVED", wantStatus: http.StatusUnprocessableEntity},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake, _ := newTestApplicationHandler(t, func(req gateway.GraphQLRequest) interface{} {
+				switch req.Query {
+				case gateway.GetApplicationStatusQuery:
+					if tt.current == "" {
+						return map[string]interface{}{"application": nil}
+					}
+					return map[string]interface{}{"application": map[string]interface{}{"id": "app-1", "status": tt.current}}
+				case gateway.UpdateApplicationStatusMutation:
+					return map[string]interface{}{"updateApplicationStatus": map[string]interface{}{"id": "app-1", "status": req.Variables["status"]}}
+				}
+				return map[string]interface{}{}
+			})
+			h.strictTransitions = tt.strict
+
+			r := chi.NewRouter()
+			r.Patch("/applications/{id}/status", h.UpdateStatus)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/applications/app-1/status", strings.NewReader(`{"status":"`+tt.status+`"}`)))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d; body = %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			updated := fake.sent(gateway.UpdateApplicationStatusMutation)
+			if (rec.Code == http.StatusOK) != (updated == 1) {
+				t.Fatalf("status mutation sent %d times for a %d response", updated, rec.Code)
+			}
+			if rec.Code == http.StatusUnprocessableEntity && !strings.Contains(rec.Body.String(), `"details":"`) {
+				t.Fatalf("body = %s, want the reason in details", rec.Body)
+			}
+		})
+	}
+}
+
+// newFakeS3Objects serves objects, keyed by bucket path, to GetObject and
+// counts the downloads
+func newFakeS3Objects(t *testing.T, objects map[string]s3Object) *atomic.Int32 {
+	t.Helper()
+	var downloads atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		object, ok := objects[r.URL.Path]
+		if r.Method != http.MethodGet || !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<Error><Code>NoSuchKey</Code></Error>`)
+			return
+		}
+		downloads.Add(1)
+		w.Header().Set("Content-Type", object.contentType)
+		w.Write(object.body)
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("AWS_ENDPOINT_URL_S3", server.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	// Keep the resume disk cache to this test
+	t.Setenv("TMPDIR", t.TempDir())
+	return &downloads
+}
+
+func TestApplicationHandler_DownloadResume(t *testing.T) {
+	docx := "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	downloads := newFakeS3Objects(t, map[string]s3Object{
+		"/resumes/resumes/2026/10/ada.pdf":  {contentType: "application/pdf", body: []byte("%PDF-1.4 ada")},
+		"/resumes/resumes/2026/10/bob.docx": {contentType: docx, body: []byte("PK bob")},
+	})
+	h, _, _ := newTestApplicationHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		switch {
+		case req.Query == gateway.GetApplicationQuery && req.Variables["id"] == "app-1":
+			return map[string]interface{}{"application": map[string]interface{}{
+				"id": "app-1", "resumeUrl": "https://resumes.s3.amazonaws.com/resumes/2026/10/ada.pdf",
+			}}
+		case req.Query == gateway.GetApplicationQuery && req.Variables["id"] == "app-2":
+			// Only the candidate profile has a resume
+			return map[string]interface{}{"application": map[string]interface{}{
+				"id": "app-2", "candidate": map[string]interface{}{"resumeUrl": "https://resumes.s3.amazonaws.com/resumes/2026/10/bob.docx"},
+			}}
+		case req.Query == gateway.GetApplicationQuery && req.Variables["id"] == "app-3":
+			return map[string]interface{}{"application": map[string]interface{}{
+				"id": "app-3", "resumeUrl": "https://elsewhere.example.com/resume.pdf",
+			}}
+		case req.Query == gateway.GetCandidateQuery && req.Variables["id"] == "cand-2":
+			return map[string]interface{}{"candidate": map[string]interface{}{
+				"id": "cand-2", "resumeUrl": "https://resumes.s3.amazonaws.com/resumes/2026/10/bob.docx",
+			}}
+		}
+		return map[string]interface{}{"application": nil, "candidate": nil}
+	})
+
+	// The routes as the server mounts them
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Use(middleware.RequireAuth)
+		r.Get("/applications/{id}/resume", h.DownloadResume)
+		r.Get("/candidates/{id}/resume", h.DownloadCandidateResume)
+	})
+	download := func(path string, authenticated bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if authenticated {
+			req = asUser(req, "user-1", "recruiter")
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	tests := []struct {
+		path            string
+		wantType        string
+		wantDisposition string
+		wantBody        string
+	}{
+		{path: "/applications/app-1/resume", wantType: "application/pdf", wantDisposition: `attachment; filename="ada.pdf"`, wantBody: "%PDF-1.4 ada"},
+		{path: "/applications/app-2/resume", wantType: docx, wantDisposition: `attachment; filename="bob.docx"`, wantBody: "PK bob"},
+		{path: "/candidates/cand-2/resume", wantType: docx, wantDisposition: `attachment; filename="bob.docx"`, wantBody: "PK bob"},
+	}
+	for _, tt := range tests {
+		rec := download(tt.path, true)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s: status = %d, body = %s", tt.path, rec.Code, rec.Body)
+		}
+		if rec.Header().Get("Content-Type") != tt.wantType || rec.Header().Get("Content-Disposition") != tt.wantDisposition {
+			t.Fatalf("GET %s: headers = %v", tt.path, rec.Header())
+		}
+		if rec.Body.String() != tt.wantBody {
+			t.Fatalf("GET %s: body = %q, want %q", tt.path, rec.Body, tt.wantBody)
+		}
+	}
+	// bob.docx was served from the disk cache the second time
+	if got := downloads.Load(); got != 2 {
+		t.Fatalf("S3 served %d downloads, want 2", got)
+	}
+
+	for path, want := range map[string]int{
+		"/applications/app-3/resume":   http.StatusNotFound,
+		"/applications/app-404/resume": http.StatusNotFound,
+		"/candidates/cand-404/resume":  http.StatusNotFound,
+	} {
+		if rec := download(path, true); rec.Code != want {
+			t.Errorf("GET %s: status = %d, want %d", path, rec.Code, want)
+		}
+	}
+	for _, path := range []string{"/applications/app-1/resume", "/candidates/cand-2/resume"} {
+		if rec := download(path, false); rec.Code != http.StatusUnauthorized {
+			t.Errorf("anonymous GET %s: status = %d, want 401", path, rec.Code)
+		}
+	}
+}
+
+func TestApplicationHandler_GetResumeURL(t *testing.T) {
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	resumeURLs := map[string]string{
+		"app-1": "https://resumes.s3.amazonaws.com/resumes/2026/10/ada.pdf",
+		"app-2": "https://resumes.s3.amazonaws.com/offer-letters/app-2/offer.pdf",
+		"app-3": "",
+	}
+	h, _, _ := newTestApplicationHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		id, _ := req.Variables["id"].(string)
+		resumeURL, ok := resumeURLs[id]
+		if req.Query != gateway.GetApplicationQuery || !ok {
+			return map[string]interface{}{"application": nil}
+		}
+		return map[string]interface{}{"application": map[string]interface{}{"id": id, "resumeUrl": resumeURL}}
+	})
+
+	r := chi.NewRouter()
+	r.With(middleware.RequireAuth).Get("/applications/{id}/resume-url", h.GetResumeURL)
+	get := func(id string, authenticated bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/applications/"+id+"/resume-url", nil)
+		if authenticated {
+			req = asUser(req, "user-1", "recruiter")
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get("app-1", true)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		URL       string    `json:"url"`
+		ExpiresAt time.Time `json:"expiresAt"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(body.URL, "/resumes/2026/10/ada.pdf?") || !strings.Contains(body.URL, "X-Amz-Expires=900") {
+		t.Fatalf("url = %s, want a 15 minute presigned link to the resume", body.URL)
+	}
+	if until := time.Until(body.ExpiresAt); until < 14*time.Minute || until > 15*time.Minute {
+		t.Fatalf("expiresAt = %v, want 15 minutes from now", body.ExpiresAt)
+	}
+
+	for id, want := range map[string]int{
+		"app-2":   http.StatusUnprocessableEntity,
+		"app-3":   http.StatusNotFound,
+		"app-404": http.StatusNotFound,
+	} {
+		if rec := get(id, true); rec.Code != want {
+			t.Errorf("%s: status = %d, want %d", id, rec.Code, want)
+		}
+	}
+	if rec := get("app-1", false); rec.Code != http.StatusUnauthorized {
+		t.Fatalf("anonymous: status = %d, want 401", rec.Code)
+	}
+}
+
+// interviewFake answers scheduling and interview lookups with an interview
+// starting at start, or with no interview when start is empty
+func interviewFake(start string) func(gateway.GraphQLRequest) interface{} {
+	application := map[string]interface{}{
+		"id":        "app-1",
+		"candidate": map[string]interface{}{"firstName": "Ada", "lastName": "Lovelace", "email": "ada@example.com"},
+		"job":       map[string]interface{}{"title": "Backend Engineer"},
+	}
+	interview := map[string]interface{}{
+		"id":          "int-1",
+		"scheduledAt": start,
+		"duration":    90,
+		"type":        "VIDEO",
+		"location":    "https://meet.example.com/int-1",
+		"interviewers": []interface{}{
+			map[string]interface{}{"id": "user-2", "name": "Grace Hopper", "email": "grace@example.com"},
+			map[string]interface{}{"id": "user-3", "name": "Alan Turing", "email": "alan@example.com"},
+		},
+	}
+	return func(req gateway.GraphQLRequest) interface{} {
+		switch req.Query {
+		case gateway.ScheduleInterviewMutation:
+			scheduled := maps.Clone(interview)
+			scheduled["application"] = application
+			return map[string]interface{}{"scheduleInterview": scheduled}
+		case gateway.GetApplicationInterviewQuery:
+			found := maps.Clone(application)
+			if start != "" {
+				found["interview"] = interview
+			}
+			return map[string]interface{}{"application": found}
+		}
+		return map[string]interface{}{}
+	}
+}
+
+// checkInterviewICS checks an interview's calendar file for the start,
+// duration and every attendee
+func checkInterviewICS(t *testing.T, ics []byte) {
+	t.Helper()
+	unfolded := strings.ReplaceAll(string(ics), "\r\n ", "")
+	for _, want := range []string{
+		"UID:int-1@hr-recruiting\r\n",
+		"DTSTART:20300102T150000Z\r\n",
+		"DURATION:PT1H30M\r\n",
+		"SUMMARY:Interview: Ada Lovelace - Backend Engineer\r\n",
+		`ATTENDEE;CN="Ada Lovelace";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:ada@example.com` + "\r\n",
+		`ATTENDEE;CN="Grace Hopper";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:grace@example.com` + "\r\n",
+		`ATTENDEE;CN="Alan Turing";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:alan@example.com` + "\r\n",
+	} {
+		if !strings.Contains(unfolded, want) {
+			t.Errorf("ICS missing %q:\n%s", strings.TrimSpace(want), ics)
+		}
+	}
+}
+
+func TestApplicationHandler_ScheduleInterview(t *testing.T) {
+	const start = "2030-01-02T15:00:00Z"
+
+	schedule := func(t *testing.T, body string) (*httptest.ResponseRecorder, *fakeHubHRMS, *recordingEmailQueue) {
+		h, fake, emails := newTestApplicationHandler(t, interviewFake(start))
+		h.features.EmailNotifications.Store(true)
+		r := chi.NewRouter()
+		r.Post("/applications/{id}/interview", h.ScheduleInterview)
+
+		req := asUser(httptest.NewRequest(http.MethodPost, "/applications/app-1/interview", strings.NewReader(body)), "user-1", "recruiter")
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+		return w, fake, emails
+	}
+
+	t.Run("sends invitations with the calendar file", func(t *testing.T) {
+		w, fake, emails := schedule(t, `{"scheduledAt":"2030-01-02T16:00:00+01:00","duration":90,"type":"video","interviewerIds":["user-2","user-3"]}`)
+		if w.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusCreated, w.Body)
+		}
+
+		fake.mu.Lock()
+		var input map[string]interface{}
+		for _, req := range fake.requests {
+			if req.Query == gateway.ScheduleInterviewMutation {
+				input, _ = req.Variables["input"].(map[string]interface{})
+			}
+		}
+		fake.mu.Unlock()
+		if input["scheduledAt"] != start || input["type"] != "VIDEO" {
+			t.Fatalf("mutation input = %v, want scheduledAt %s and type VIDEO", input, start)
+		}
+
+		jobs := emails.enqueued()
+		templates := map[string]string{}
+		for _, job := range jobs {
+			templates[job.To] = job.Template
+			if len(job.Attachments) != 1 {
+				t.Fatalf("email to %s has %d attachments, want 1", job.To, len(job.Attachments))
+			}
+			attachment := job.Attachments[0]
+			if attachment.Filename != "invite.ics" || attachment.ContentType != "text/calendar; method=REQUEST" {
+				t.Fatalf("attachment = %s (%s), want invite.ics (text/calendar; method=REQUEST)", attachment.Filename, attachment.ContentType)
+			}
+			checkInterviewICS(t, attachment.Content)
+		}
+		want := map[string]string{
+			"ada@example.com":   "interview_invitation",
+			"grace@example.com": "interviewer_invitation",
+			"alan@example.com":  "interviewer_invitation",
+		}
+		if len(jobs) != len(want) || !maps.Equal(templates, want) {
+			t.Fatalf("emails = %v, want %v", templates, want)
+		}
+	})
+
+	for name, body := range map[string]string{
+		"missing scheduledAt":  `{"duration":60,"type":"video","interviewerIds":["user-2"]}`,
+		"scheduledAt in past":  `{"scheduledAt":"2020-01-02T15:00:00Z","duration":60,"type":"video","interviewerIds":["user-2"]}`,
+		"zero duration":        `{"scheduledAt":"2030-01-02T15:00:00Z","duration":0,"type":"video","interviewerIds":["user-2"]}`,
+		"duration over 8h":     `{"scheduledAt":"2030-01-02T15:00:00Z","duration":481,"type":"video","interviewerIds":["user-2"]}`,
+		"missing type":         `{"scheduledAt":"2030-01-02T15:00:00Z","duration":60,"interviewerIds":["user-2"]}`,
+		"missing interviewers": `{"scheduledAt":"2030-01-02T15:00:00Z","duration":60,"type":"video"}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			w, fake, emails := schedule(t, body)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusBadRequest, w.Body)
+			}
+			if n := fake.sent(gateway.ScheduleInterviewMutation); n != 0 {
+				t.Fatalf("ScheduleInterviewMutation sent %d times, want 0", n)
+			}
+			if jobs := emails.enqueued(); len(jobs) != 0 {
+				t.Fatalf("enqueued %d emails, want 0", len(jobs))
+			}
+		})
+	}
+}
+
+func TestApplicationHandler_DownloadInterviewICS(t *testing.T) {
+	download := func(t *testing.T, start string) *httptest.ResponseRecorder {
+		h, _, _ := newTestApplicationHandler(t, interviewFake(start))
+		r := chi.NewRouter()
+		r.Get("/applications/{id}/interview/ics", h.DownloadInterviewICS)
+
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, asUser(httptest.NewRequest(http.MethodGet, "/applications/app-1/interview/ics", nil), "user-1", "recruiter"))
+		return w
+	}
+
+	t.Run("calendar file", func(t *testing.T) {
+		w := download(t, "2030-01-02T15:00:00Z")
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", w.Code, http.StatusOK, w.Body)
+		}
+		if got := w.Header().Get("Content-Type"); got != "text/calendar; charset=utf-8; method=REQUEST" {
+			t.Fatalf("Content-Type = %q", got)
+		}
+		if got := w.Header().Get("Content-Disposition"); got != "attachment; filename=interview.ics" {
+			t.Fatalf("Content-Disposition = %q", got)
+		}
+		checkInterviewICS(t, w.Body.Bytes())
+	})
+
+	t.Run("no interview", func(t *testing.T) {
+		if w := download(t, ""); w.Code != http.StatusNotFound {
+			t.Fatalf("status = %d, want %d", w.Code, http.StatusNotFound)
+		}
+	})
+}
+
+func TestApplicationHandler_SubmitApplication_CustomFields(t *testing.T) {
+	submitted := submitApplicationFake()
+	h, fake, _ := newTestApplicationHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query == gateway.GetJobApplicationSchemaQuery {
+			return map[string]interface{}{"jobApplicationSchema": map[string]interface{}{
+				"fields": []interface{}{
+					map[string]interface{}{"name": "githubUrl", "required": true, "type": "url"},
+					map[string]interface{}{"name": "territory", "required": false, "type": "string", "maxLength": 10},
+				},
+			}}
+		}
+		return submitted(req)
+	})
+
+	submit := func(extra map[string]interface{}, remove ...string) *httptest.ResponseRecorder {
+		var input map[string]interface{}
+		json.Unmarshal([]byte(testApplication("ada@example.com")), &input)
+		for field, value := range extra {
+			input[field] = value
+		}
+		for _, field := range remove {
+			delete(input, field)
+		}
+		body, _ := json.Marshal(input)
+		rec := httptest.NewRecorder()
+		h.SubmitApplication(rec, httptest.NewRequest(http.MethodPost, "/applications", strings.NewReader(string(body))))
+		return rec
+	}
+
+	t.Run("every field error is returned", func(t *testing.T) {
+		rec := submit(map[string]interface{}{"territory": "Asia Pacific"}, "phone")
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusUnprocessableEntity, rec.Body)
+		}
+		var body struct {
+			Details []services.FieldError `json:"details"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		want := []services.FieldError{
+			{Field: "phone", Message: "is required"},
+			{Field: "githubUrl", Message: "is required"},
+			{Field: "territory", Message: "must be at most 10 characters"},
+		}
+		if !slices.Equal(body.Details, want) {
+			t.Fatalf("details = %v, want %v", body.Details, want)
+		}
+		if n := fake.sent(gateway.SubmitApplicationMutation); n != 0 {
+			t.Fatalf("SubmitApplicationMutation sent %d times, want 0", n)
+		}
+	})
+
+	t.Run("optional custom field omitted", func(t *testing.T) {
+		rec := submit(map[string]interface{}{"githubUrl": "https://github.com/ada"})
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+		}
+	})
+}
+
+func TestApplicationHandler_SubmitApplication_ExistingCandidate(t *testing.T) {
+	// Hub-HRMS knows Ada, who has already applied to job-1
+	candidateFake := func() func(gateway.GraphQLRequest) interface{} {
+		submitted := submitApplicationFake()
+		return func(req gateway.GraphQLRequest) interface{} {
+			if req.Query != gateway.SearchCandidatesByEmailQuery {
+				return submitted(req)
+			}
+			if req.Variables["email"] != "ada@example.com" {
+				return map[string]interface{}{"searchCandidates": []interface{}{}}
+			}
+			return map[string]interface{}{"searchCandidates": []interface{}{map[string]interface{}{
+				"id":    "cand-1",
+				"email": "Ada@Example.com",
+				"applications": []interface{}{
+					map[string]interface{}{"id": "app-7", "job": map[string]interface{}{"id": "job-1"}},
+				},
+			}}}
+		}
+	}
+	submit := func(h *ApplicationHandler, jobID, email string) *httptest.ResponseRecorder {
+		var input map[string]interface{}
+		json.Unmarshal([]byte(testApplication(email)), &input)
+		input["jobId"] = jobID
+		body, _ := json.Marshal(input)
+		rec := httptest.NewRecorder()
+		h.SubmitApplication(rec, httptest.NewRequest(http.MethodPost, "/applications", strings.NewReader(string(body))))
+		return rec
+	}
+	submittedCandidate := func(fake *fakeHubHRMS) (interface{}, bool) {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		for _, req := range fake.requests {
+			if req.Query == gateway.SubmitApplicationMutation {
+				input, _ := req.Variables["input"].(map[string]interface{})
+				candidateID, ok := input["candidateId"]
+				return candidateID, ok
+			}
+		}
+		return nil, false
+	}
+
+	t.Run("already applied", func(t *testing.T) {
+		h, fake, _ := newTestApplicationHandler(t, candidateFake())
+		h.features.ApplicationDeduplication.Store(true)
+
+		rec := submit(h, "job-1", " ADA@example.com")
+		if rec.Code != http.StatusConflict {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusConflict, rec.Body)
+		}
+		var body struct {
+			Details map[string]string `json:"details"`
+		}
+		json.NewDecoder(rec.Body).Decode(&body)
+		if body.Details["existingApplicationId"] != "app-7" {
+			t.Fatalf("details = %v, want existingApplicationId app-7", body.Details)
+		}
+		if n := fake.sent(gateway.SubmitApplicationMutation); n != 0 {
+			t.Fatalf("SubmitApplicationMutation sent %d times, want 0", n)
+		}
+	})
+
+	t.Run("existing candidate applying to another job", func(t *testing.T) {
+		h, fake, _ := newTestApplicationHandler(t, candidateFake())
+		h.features.ApplicationDeduplication.Store(true)
+
+		if rec := submit(h, "job-2", "Ada@example.COM"); rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+		}
+		if candidateID, _ := submittedCandidate(fake); candidateID != "cand-1" {
+			t.Fatalf("candidateId = %v, want cand-1", candidateID)
+		}
+	})
+
+	t.Run("new candidate", func(t *testing.T) {
+		h, fake, _ := newTestApplicationHandler(t, candidateFake())
+		h.features.ApplicationDeduplication.Store(true)
+
+		if rec := submit(h, "job-1", "grace@example.com"); rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+		}
+		if candidateID, ok := submittedCandidate(fake); ok {
+			t.Fatalf("candidateId = %v, want none for a new candidate", candidateID)
+		}
+	})
+}
+
+// statusFake answers status lookups with each application's current status,
+// all on job-1, and applies status mutations
+func statusFake(current map[string]string) func(gateway.GraphQLRequest) interface{} {
+	return func(req gateway.GraphQLRequest) interface{} {
+		switch req.Query {
+		case gateway.GetApplicationStatusQuery:
+			id, _ := req.Variables["id"].(string)
+			return map[string]interface{}{"application": map[string]interface{}{
+				"id": id, "status": current[id], "job": map[string]interface{}{"id": "job-1"},
+			}}
+		case gateway.UpdateApplicationStatusMutation:
+			return map[string]interface{}{"updateApplicationStatus": map[string]interface{}{
+				"id": req.Variables["id"], "status": req.Variables["status"],
+			}}
+		case gateway.BulkUpdateApplicationStatusMutation:
+			ids, _ := req.Variables["ids"].([]interface{})
+			updated := make([]interface{}, len(ids))
+			for i, id := range ids {
+				updated[i] = map[string]interface{}{"id": id, "status": req.Variables["status"]}
+			}
+			return map[string]interface{}{"bulkUpdateApplicationStatus": updated}
+		}
+		return map[string]interface{}{}
+	}
+}
+
+// receivedEvents drains the pipeline events already published to events
+func receivedEvents(events <-chan services.PipelineEvent) []services.PipelineEvent {
+	var received []services.PipelineEvent
+	for len(events) > 0 {
+		received = append(received, <-events)
+	}
+	slices.SortFunc(received, func(a, b services.PipelineEvent) int { return strings.Compare(a.ApplicationID, b.ApplicationID) })
+	return received
+}
+
+func TestApplicationHandler_PublishesPipelineEvents(t *testing.T) {
+	current := map[string]string{"app-1": "APPLIED", "app-2": "SCREENING"}
+
+	t.Run("UpdateStatus", func(t *testing.T) {
+		h, _, _ := newTestApplicationHandler(t, statusFake(current))
+		events, unsubscribe := h.pipelineEvents.Subscribe()
+		defer unsubscribe()
+
+		r := chi.NewRouter()
+		r.Patch("/applications/{id}/status", h.UpdateStatus)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/applications/app-1/status", strings.NewReader(`{"status":"SCREENING"}`)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+
+		want := []services.PipelineEvent{{ApplicationID: "app-1", OldStatus: "APPLIED", NewStatus: "SCREENING", JobID: "job-1"}}
+		if got := receivedEvents(events); !slices.Equal(got, want) {
+			t.Fatalf("events = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("BulkUpdateStatus", func(t *testing.T) {
+		h, _, _ := newTestApplicationHandler(t, statusFake(current))
+		events, unsubscribe := h.pipelineEvents.Subscribe()
+		defer unsubscribe()
+
+		rec := httptest.NewRecorder()
+		h.BulkUpdateStatus(rec, httptest.NewRequest(http.MethodPost, "/applications/bulk-status", strings.NewReader(`{"ids":["app-1","app-2"],"status":"REJECTED"}`)))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+
+		want := []services.PipelineEvent{
+			{ApplicationID: "app-1", OldStatus: "APPLIED", NewStatus: "REJECTED", JobID: "job-1"},
+			{ApplicationID: "app-2", OldStatus: "SCREENING", NewStatus: "REJECTED", JobID: "job-1"},
+		}
+		if got := receivedEvents(events); !slices.Equal(got, want) {
+			t.Fatalf("events = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("failed update publishes nothing", func(t *testing.T) {
+		h, _, _ := newTestApplicationHandler(t, statusFake(current))
+		events, unsubscribe := h.pipelineEvents.Subscribe()
+		defer unsubscribe()
+
+		rec := httptest.NewRecorder()
+		h.BulkUpdateStatus(rec, httptest.NewRequest(http.MethodPost, "/applications/bulk-status", strings.NewReader(`{"ids":[],"status":"REJECTED"}`)))
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+		if got := receivedEvents(events); len(got) != 0 {
+			t.Fatalf("events = %+v, want none", got)
+		}
+	})
+}
+
+func TestApplicationHandler_DisabledFeaturesSkipped(t *testing.T) {
+	t.Run("AI scoring", func(t *testing.T) {
+		for _, enabled := range []bool{false, true} {
+			h, fake, _ := newTestApplicationHandler(t, func(req gateway.GraphQLRequest) interface{} {
+				if req.Query == gateway.ScoreApplicationMutation {
+					return map[string]interface{}{"scoreApplication": map[string]interface{}{"id": "app-1"}}
+				}
+				return map[string]interface{}{}
+			})
+			h.features.AIScoring.Store(enabled)
+
+			r := chi.NewRouter()
+			r.Post("/applications/{id}/score", h.ScoreApplication)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/applications/app-1/score", nil))
+
+			scored := fake.sent(gateway.ScoreApplicationMutation)
+			if enabled && (rec.Code != http.StatusOK || scored != 1) {
+				t.Fatalf("enabled: status = %d, scored %d times, want 200 and one score", rec.Code, scored)
+			}
+			if !enabled && (rec.Code != http.StatusServiceUnavailable || scored != 0) {
+				t.Fatalf("disabled: status = %d, scored %d times, want 503 and no score", rec.Code, scored)
+			}
+		}
+	})
+
+	t.Run("email notifications", func(t *testing.T) {
+		for _, enabled := range []bool{false, true} {
+			h, fake, emails := newTestApplicationHandler(t, submitApplicationFake())
+			h.features.EmailNotifications.Store(enabled)
+
+			rec := httptest.NewRecorder()
+			h.SubmitApplication(rec, httptest.NewRequest(http.MethodPost, "/applications", strings.NewReader(testApplication("ada@example.com"))))
+			if rec.Code != http.StatusCreated || fake.sent(gateway.SubmitApplicationMutation) != 1 {
+				t.Fatalf("status = %d, want the application submitted either way", rec.Code)
+			}
+
+			sent := emails.enqueued()
+			if enabled && (len(sent) != 1 || sent[0].Template != "application_confirmation") {
+				t.Fatalf("enabled: enqueued %v, want the confirmation", sent)
+			}
+			if !enabled && len(sent) != 0 {
+				t.Fatalf("disabled: enqueued %d emails, want none", len(sent))
+			}
+		}
+	})
+}
+
+// scoringFake scores every application except those in missing, recording
+// the most scoring mutations it had in flight at once. Applications are all
+// for job-1 except app-other.
+func scoringFake(maxInFlight *atomic.Int32, missing ...string) func(gateway.GraphQLRequest) interface{} {
+	var inFlight atomic.Int32
+	return func(req gateway.GraphQLRequest) interface{} {
+		switch req.Query {
+		case gateway.GetApplicationStatusQuery:
+			id, _ := req.Variables["id"].(string)
+			jobID := "job-1"
+			if id == "app-other" {
+				jobID = "job-2"
+			}
+			return map[string]interface{}{"application": map[string]interface{}{"id": id, "job": map[string]interface{}{"id": jobID}}}
+		case gateway.ScoreApplicationMutation:
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for {
+				highest := maxInFlight.Load()
+				if n <= highest || maxInFlight.CompareAndSwap(highest, n) {
+					break
+				}
+			}
+			time.Sleep(20 * time.Millisecond)
+
+			id, _ := req.Variables["applicationId"].(string)
+			if slices.Contains(missing, id) {
+				return map[string]interface{}{"scoreApplication": nil}
+			}
+			return map[string]interface{}{"scoreApplication": map[string]interface{}{"id": id}}
+		}
+		return map[string]interface{}{}
+	}
+}
+
+// bulkScore posts ids, and jobID if set, to BulkScoreApplications
+func bulkScore(h *ApplicationHandler, ids []string, jobID, accept string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(map[string]interface{}{"ids": ids, "jobId": jobID})
+	req := httptest.NewRequest(http.MethodPost, "/applications/bulk-score", strings.NewReader(string(body)))
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	h.BulkScoreApplications(rec, req)
+	return rec
+}
+
+func TestApplicationHandler_BulkScoreApplications(t *testing.T) {
+	t.Run("concurrency limit", func(t *testing.T) {
+		var maxInFlight atomic.Int32
+		h, fake, _ := newTestApplicationHandler(t, scoringFake(&maxInFlight))
+		h.features.AIScoring.Store(true)
+		h.scoringConcurrency = 3
+
+		ids := make([]string, 12)
+		for i := range ids {
+			ids[i] = fmt.Sprintf("app-%d", i+1)
+		}
+		rec := bulkScore(h, ids, "", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		if got := maxInFlight.Load(); got != 3 {
+			t.Fatalf("at most %d scoring mutations in flight, want 3", got)
+		}
+		if n := fake.sent(gateway.ScoreApplicationMutation); n != len(ids) {
+			t.Fatalf("scored %d applications, want %d", n, len(ids))
+		}
+	})
+
+	t.Run("partial failures", func(t *testing.T) {
+		var maxInFlight atomic.Int32
+		h, _, _ := newTestApplicationHandler(t, scoringFake(&maxInFlight, "app-2"))
+		h.features.AIScoring.Store(true)
+		h.scoringConcurrency = 5
+
+		rec := bulkScore(h, []string{"app-1", "app-2", "app-other", "app-3", "app-1", ""}, "job-1", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		var result BulkScoringResult
+		if err := json.NewDecoder(rec.Body).Decode(&result); err != nil {
+			t.Fatal(err)
+		}
+		slices.Sort(result.Succeeded)
+		slices.SortFunc(result.Failed, func(a, b BulkScoringError) int { return strings.Compare(a.ApplicationID, b.ApplicationID) })
+
+		if !slices.Equal(result.Succeeded, []string{"app-1", "app-3"}) {
+			t.Fatalf("succeeded = %v, want app-1 and app-3 once each", result.Succeeded)
+		}
+		want := []BulkScoringError{
+			{ApplicationID: "app-2", Error: "application not found"},
+			{ApplicationID: "app-other", Error: "application is not for job job-1"},
+		}
+		if !slices.Equal(result.Failed, want) {
+			t.Fatalf("failed = %+v, want %+v", result.Failed, want)
+		}
+	})
+
+	t.Run("streamed progress", func(t *testing.T) {
+		var maxInFlight atomic.Int32
+		h, _, _ := newTestApplicationHandler(t, scoringFake(&maxInFlight, "app-2"))
+		h.features.AIScoring.Store(true)
+
+		rec := bulkScore(h, []string{"app-1", "app-2", "app-3"}, "", "text/event-stream")
+		if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "text/event-stream" {
+			t.Fatalf("response = %d %q, want an event stream", rec.Code, rec.Header().Get("Content-Type"))
+		}
+
+		events := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n\n"), "\n\n")
+		if len(events) != 4 {
+			t.Fatalf("got %d events, want 3 progress events and complete:\n%s", len(events), rec.Body)
+		}
+		for i, event := range events[:3] {
+			name, data, _ := strings.Cut(event, "\n")
+			var progress bulkScoringProgress
+			json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &progress)
+			if name != "event: progress" || progress.Completed != i+1 || progress.Total != 3 {
+				t.Fatalf("event %d = %q, want progress %d of 3", i+1, event, i+1)
+			}
+			if progress.Succeeded != (progress.ApplicationID != "app-2") {
+				t.Fatalf("event %d = %+v, want only app-2 to fail", i+1, progress)
+			}
+		}
+		name, data, _ := strings.Cut(events[3], "\n")
+		var result BulkScoringResult
+		json.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), &result)
+		if name != "event: complete" || len(result.Succeeded) != 2 || len(result.Failed) != 1 {
+			t.Fatalf("last event = %q, want complete with 2 scored and 1 failed", events[3])
+		}
+	})
+
+	t.Run("validation", func(t *testing.T) {
+		tooMany := make([]string, maxBulkScore+1)
+		for i := range tooMany {
+			tooMany[i] = fmt.Sprintf("app-%d", i)
+		}
+		// Repeats count once towards the limit
+		atLimit := append(slices.Clone(tooMany[:maxBulkScore]), "app-0", "app-1")
+
+		tests := []struct {
+			name       string
+			ids        []string
+			disabled   bool
+			wantStatus int
+		}{
+			{name: "too many", ids: tooMany, wantStatus: http.StatusBadRequest},
+			{name: "at the limit with repeats", ids: atLimit, wantStatus: http.StatusOK},
+			{name: "no ids", ids: []string{"", ""}, wantStatus: http.StatusBadRequest},
+			{name: "scoring disabled", ids: []string{"app-1"}, disabled: true, wantStatus: http.StatusServiceUnavailable},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				h, fake, _ := newTestApplicationHandler(t, func(req gateway.GraphQLRequest) interface{} {
+					return map[string]interface{}{"scoreApplication": map[string]interface{}{"id": req.Variables["applicationId"]}}
+				})
+				h.features.AIScoring.Store(!tt.disabled)
+				h.scoringConcurrency = 20
+
+				rec := bulkScore(h, tt.ids, "", "")
+				if rec.Code != tt.wantStatus {
+					t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+				}
+				if n := fake.sent(gateway.ScoreApplicationMutation); tt.wantStatus != http.StatusOK && n != 0 {
+					t.Fatalf("scored %d applications for a rejected request", n)
+				}
+			})
+		}
+	})
+}
+
+func TestApplicationHandler_SubmitApplication_Source(t *testing.T) {
+	submittedInput := func(fake *fakeHubHRMS) map[string]interface{} {
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		for _, req := range fake.requests {
+			if req.Query == gateway.SubmitApplicationMutation {
+				input, _ := req.Variables["input"].(map[string]interface{})
+				return input
+			}
+		}
+		return nil
+	}
+	submit := func(t *testing.T, utm map[string]interface{}) map[string]interface{} {
+		h, fake, _ := newTestApplicationHandler(t, submitApplicationFake())
+		var input map[string]interface{}
+		json.Unmarshal([]byte(testApplication("ada@example.com")), &input)
+		maps.Copy(input, utm)
+		body, _ := json.Marshal(input)
+
+		rec := httptest.NewRecorder()
+		h.SubmitApplication(rec, httptest.NewRequest(http.MethodPost, "/applications", strings.NewReader(string(body))))
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+		}
+		return submittedInput(fake)
+	}
+
+	t.Run("UTM fields forwarded", func(t *testing.T) {
+		input := submit(t, map[string]interface{}{
+			"utmSource":   "linkedin",
+			"utmMedium":   "social",
+			"utmCampaign": "spring-hiring",
+		})
+		want := map[string]interface{}{"utmSource": "linkedin", "utmMedium": "social", "utmCampaign": "spring-hiring"}
+		if source, _ := input["source"].(map[string]interface{}); !maps.Equal(source, want) {
+			t.Fatalf("source = %v, want %v", input["source"], want)
+		}
+		for _, field := range utmFields {
+			if _, ok := input[field]; ok {
+				t.Fatalf("%s sent at the top level as well as in source", field)
+			}
+		}
+	})
+
+	t.Run("no UTM fields", func(t *testing.T) {
+		if input := submit(t, nil); input["source"] != nil {
+			t.Fatalf("source = %v, want none for a direct application", input["source"])
+		}
+	})
+}
+
+// timelineFake serves app-1 with six timeline events, applying the timeline
+// filter and page it is sent the way Hub-HRMS does
+func timelineFake(req gateway.GraphQLRequest) interface{} {
+	if req.Variables["id"] != "app-1" {
+		return map[string]interface{}{"application": nil}
+	}
+	events := []struct{ eventType, timestamp string }{
+		{"APPLICATION_SUBMITTED", "2026-01-05T09:00:00Z"},
+		{"STATUS_CHANGE", "2026-01-12T10:00:00Z"},
+		{"NOTE_ADDED", "2026-01-20T11:00:00Z"},
+		{"INTERVIEW_SCHEDULED", "2026-02-02T12:00:00Z"},
+		{"STATUS_CHANGE", "2026-02-10T13:00:00Z"},
+		{"NOTE_ADDED", "2026-03-01T14:00:00Z"},
+	}
+
+	filterKey := "timelineFilter"
+	if req.Query == gateway.GetApplicationTimelineQuery {
+		filterKey = "filter"
+	}
+	filter, _ := req.Variables[filterKey].(map[string]interface{})
+	bound := func(key string) time.Time {
+		value, _ := filter[key].(string)
+		if t, err := time.Parse("2006-01-02", value); err == nil {
+			return t
+		}
+		t, _ := time.Parse(time.RFC3339, value)
+		return t
+	}
+	from, to := bound("from"), bound("to")
+	types, _ := filter["types"].([]interface{})
+
+	timeline := []interface{}{}
+	for i, event := range events {
+		timestamp, _ := time.Parse(time.RFC3339, event.timestamp)
+		if (!from.IsZero() && timestamp.Before(from)) || (!to.IsZero() && timestamp.After(to)) ||
+			(len(types) > 0 && !slices.Contains(types, interface{}(event.eventType))) {
+			continue
+		}
+		timeline = append(timeline, map[string]interface{}{
+			"id": fmt.Sprintf("evt-%d", i+1), "type": event.eventType, "timestamp": event.timestamp,
+		})
+	}
+
+	if limit, ok := req.Variables["limit"].(float64); ok {
+		offset, _ := req.Variables["offset"].(float64)
+		start := min(int(offset), len(timeline))
+		timeline = timeline[start:min(start+int(limit), len(timeline))]
+	}
+	return map[string]interface{}{"application": map[string]interface{}{"id": "app-1", "timeline": timeline}}
+}
+
+// timelineIDs returns the IDs of the timeline events in application
+func timelineIDs(application interface{}) []string {
+	events, _ := lookup(application, "timeline").([]interface{})
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = lookupString(event, "id")
+	}
+	return ids
+}
+
+func TestApplicationHandler_GetApplication_TimelineFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		want   []string
+		filter map[string]interface{}
+	}{
+		{name: "unfiltered", query: "", want: []string{"evt-1", "evt-2", "evt-3", "evt-4", "evt-5", "evt-6"}},
+		{
+			name:   "from date",
+			query:  "timelineFrom=2026-02-01",
+			want:   []string{"evt-4", "evt-5", "evt-6"},
+			filter: map[string]interface{}{"from": "2026-02-01"},
+		},
+		{
+			name:   "date range",
+			query:  "timelineFrom=2026-01-10&timelineTo=2026-02-05T00:00:00Z",
+			want:   []string{"evt-2", "evt-3", "evt-4"},
+			filter: map[string]interface{}{"from": "2026-01-10", "to": "2026-02-05T00:00:00Z"},
+		},
+		{
+			name:   "types",
+			query:  "timelineTypes=status_change,+NOTE_ADDED,STATUS_CHANGE",
+			want:   []string{"evt-2", "evt-3", "evt-5", "evt-6"},
+			filter: map[string]interface{}{"types": []interface{}{"STATUS_CHANGE", "NOTE_ADDED"}},
+		},
+		{
+			name:  "types and dates",
+			query: "timelineTypes=STATUS_CHANGE&timelineFrom=2026-02-01",
+			want:  []string{"evt-5"},
+			filter: map[string]interface{}{
+				"from": "2026-02-01", "types": []interface{}{"STATUS_CHANGE"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake, _ := newTestApplicationHandler(t, timelineFake)
+			r := chi.NewRouter()
+			r.Get("/applications/{id}", h.GetApplication)
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/applications/app-1?"+tt.query, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+			var response map[string]interface{}
+			json.Unmarshal(rec.Body.Bytes(), &response)
+			if got := timelineIDs(response["application"]); !slices.Equal(got, tt.want) {
+				t.Fatalf("timeline = %v, want %v", got, tt.want)
+			}
+
+			fake.mu.Lock()
+			filter, sent := fake.requests[0].Variables["timelineFilter"]
+			fake.mu.Unlock()
+			if tt.filter == nil && sent {
+				t.Fatalf("timelineFilter = %v, want none sent without filter parameters", filter)
+			}
+			if tt.filter != nil && fmt.Sprint(filter) != fmt.Sprint(tt.filter) {
+				t.Fatalf("timelineFilter = %v, want %v", filter, tt.filter)
+			}
+		})
+	}
+}
+
+func TestApplicationHandler_GetApplication_InvalidTimelineFilter(t *testing.T) {
+	for _, query := range []string{
+		"timelineFrom=01/02/2026",
+		"timelineTo=yesterday",
+		"timelineFrom=2026-03-01&timelineTo=2026-02-01",
+	} {
+		t.Run(query, func(t *testing.T) {
+			h, fake, _ := newTestApplicationHandler(t, timelineFake)
+			r := chi.NewRouter()
+			r.Get("/applications/{id}", h.GetApplication)
+			r.Get("/applications/{id}/timeline", h.GetApplicationTimeline)
+
+			for _, path := range []string{"/applications/app-1?", "/applications/app-1/timeline?"} {
+				rec := httptest.NewRecorder()
+				r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path+query, nil))
+				if rec.Code != http.StatusBadRequest {
+					t.Fatalf("GET %s status = %d, want %d", path+query, rec.Code, http.StatusBadRequest)
+				}
+			}
+			if len(fake.requests) != 0 {
+				t.Fatal("invalid filter was sent to Hub-HRMS")
+			}
+		})
+	}
+}
+
+func TestApplicationHandler_GetApplicationTimeline(t *testing.T) {
+	h, _, _ := newTestApplicationHandler(t, timelineFake)
+	r := chi.NewRouter()
+	r.Get("/applications/{id}/timeline", h.GetApplicationTimeline)
+
+	get := func(query string) (*httptest.ResponseRecorder, []string) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/applications/app-1/timeline?"+query, nil))
+		var application map[string]interface{}
+		json.Unmarshal(rec.Body.Bytes(), &application)
+		return rec, timelineIDs(application)
+	}
+
+	rec, first := get("limit=2")
+	if rec.Code != http.StatusOK || !slices.Equal(first, []string{"evt-1", "evt-2"}) {
+		t.Fatalf("first page = %d %v, want evt-1 and evt-2", rec.Code, first)
+	}
+	cursor := rec.Header().Get("X-Next-Cursor")
+	if cursor == "" {
+		t.Fatal("full page has no X-Next-Cursor")
+	}
+
+	rec, second := get("limit=2&cursor=" + url.QueryEscape(cursor))
+	if !slices.Equal(second, []string{"evt-3", "evt-4"}) {
+		t.Fatalf("second page = %v, want evt-3 and evt-4", second)
+	}
+
+	// Filtering shrinks the timeline the pages come from
+	rec, filtered := get("limit=2&timelineTypes=NOTE_ADDED,INTERVIEW_SCHEDULED")
+	if !slices.Equal(filtered, []string{"evt-3", "evt-4"}) {
+		t.Fatalf("filtered page = %v, want evt-3 and evt-4", filtered)
+	}
+	rec, last := get("limit=2&offset=2&timelineTypes=NOTE_ADDED,INTERVIEW_SCHEDULED")
+	if !slices.Equal(last, []string{"evt-6"}) || rec.Header().Get("X-Next-Cursor") != "" {
+		t.Fatalf("last page = %v with cursor %q, want evt-6 and no cursor", last, rec.Header().Get("X-Next-Cursor"))
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/applications/app-9/timeline", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status for a missing application = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+}
+
+// searchFake answers application searches with two matches, the first with
+// two highlighted excerpts and the second with none
+func searchFake(req gateway.GraphQLRequest) interface{} {
+	return map[string]interface{}{"searchApplications": map[string]interface{}{
+		"total": 12,
+		"items": []interface{}{
+			map[string]interface{}{
+				"id": "app-1", "status": "SCREENING",
+				"highlights": []interface{}{
+					map[string]interface{}{"field": "coverLetter", "snippet": "I have shipped <em>Kubernetes</em> operators"},
+					map[string]interface{}{"field": "notes", "snippet": "Strong <em>Kubernetes</em> background"},
+				},
+			},
+			map[string]interface{}{"id": "app-2", "status": "APPLIED", "highlights": []interface{}{}},
+		},
+	}}
+}
+
+func TestApplicationHandler_SearchApplications(t *testing.T) {
+	h, fake, _ := newTestApplicationHandler(t, searchFake)
+
+	rec := httptest.NewRecorder()
+	h.SearchApplications(rec, httptest.NewRequest(http.MethodGet, "/applications/search?q=+kubernetes+&limit=80", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	var response struct {
+		Items      []map[string]interface{} `json:"items"`
+		Total      int                      `json:"total"`
+		Highlights map[string][]string      `json:"highlights"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	if response.Total != 12 || len(response.Items) != 2 || response.Items[0]["id"] != "app-1" {
+		t.Fatalf("response = %s, want both matches and the total", rec.Body)
+	}
+	if _, ok := response.Items[0]["highlights"]; ok {
+		t.Fatal("highlights left on the items")
+	}
+	want := []string{"I have shipped <em>Kubernetes</em> operators", "Strong <em>Kubernetes</em> background"}
+	if !slices.Equal(response.Highlights["app-1"], want) {
+		t.Fatalf("highlights[app-1] = %v, want %v", response.Highlights["app-1"], want)
+	}
+	if _, ok := response.Highlights["app-2"]; ok {
+		t.Fatalf("highlights = %v, want none for app-2", response.Highlights)
+	}
+
+	fake.mu.Lock()
+	variables := fake.requests[0].Variables
+	fake.mu.Unlock()
+	if variables["query"] != "kubernetes" || variables["limit"] != float64(maxSearchResults) || variables["offset"] != float64(0) {
+		t.Fatalf("variables = %v, want the trimmed query capped at %d results", variables, maxSearchResults)
+	}
+}
+
+func TestApplicationHandler_SearchApplications_MinimumLength(t *testing.T) {
+	tests := []struct {
+		q          string
+		wantStatus int
+	}{
+		{q: "", wantStatus: http.StatusBadRequest},
+		{q: "go", wantStatus: http.StatusBadRequest},
+		{q: "  go  ", wantStatus: http.StatusBadRequest},
+		{q: "ab", wantStatus: http.StatusBadRequest},
+		{q: "çé", wantStatus: http.StatusBadRequest},
+		{q: "çéñ", wantStatus: http.StatusOK},
+		{q: "sql", wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.q, func(t *testing.T) {
+			h, fake, _ := newTestApplicationHandler(t, searchFake)
+			rec := httptest.NewRecorder()
+			h.SearchApplications(rec, httptest.NewRequest(http.MethodGet, "/applications/search?q="+url.QueryEscape(tt.q), nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusBadRequest && fake.sent(gateway.SearchApplicationsQuery) != 0 {
+				t.Fatal("short query was sent to Hub-HRMS")
+			}
+		})
+	}
+}
+
+func TestApplicationHandler_SubmitApplication_Blacklist(t *testing.T) {
+	submitted := submitApplicationFake()
+	respond := func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.CheckBlacklistQuery {
+			return submitted(req)
+		}
+		switch req.Variables["email"] {
+		case "fraud@example.com":
+			return map[string]interface{}{"blacklistEntry": map[string]interface{}{"id": "bl-1"}}
+		case "outage@example.com":
+			return &gateway.GraphQLResponse{Errors: []gateway.GraphQLError{{Message: "blacklist unavailable"}}}
+		}
+		return map[string]interface{}{"blacklistEntry": nil}
+	}
+
+	tests := []struct {
+		name       string
+		email      string
+		wantStatus int
+	}{
+		{name: "blocked", email: "fraud@example.com", wantStatus: http.StatusForbidden},
+		{name: "blocked in another case", email: "FRAUD@example.com", wantStatus: http.StatusForbidden},
+		{name: "not blocked", email: "ada@example.com", wantStatus: http.StatusCreated},
+		{name: "check failing lets the application through", email: "outage@example.com", wantStatus: http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake, emails := newTestApplicationHandler(t, respond)
+			h.features.EmailNotifications.Store(true)
+
+			rec := httptest.NewRecorder()
+			h.SubmitApplication(rec, httptest.NewRequest(http.MethodPost, "/applications", strings.NewReader(testApplication(tt.email))))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusForbidden {
+				return
+			}
+
+			if fake.sent(gateway.SubmitApplicationMutation) != 0 || len(emails.enqueued()) != 0 {
+				t.Fatal("blocked application was submitted")
+			}
+			var response ErrorResponse
+			json.Unmarshal(rec.Body.Bytes(), &response)
+			if response.Message != "Applications from this email address are not accepted" ||
+				strings.Contains(strings.ToLower(rec.Body.String()), "blacklist") {
+				t.Fatalf("response = %s, want a generic refusal", rec.Body)
+			}
+		})
+	}
+}
+
+func TestApplicationHandler_ListApplications_Envelope(t *testing.T) {
+	applications := func(ids ...string) []interface{} {
+		items := []interface{}{}
+		for _, id := range ids {
+			items = append(items, map[string]interface{}{"id": id, "status": "APPLIED"})
+		}
+		return items
+	}
+
+	tests := []struct {
+		name        string
+		query       string
+		list        interface{}
+		count       interface{}
+		wantIDs     []string
+		wantTotal   int
+		wantLimit   int
+		wantOffset  int
+		wantHasMore bool
+	}{
+		{
+			name: "first page", query: "limit=2",
+			list: applications("app-1", "app-2"), count: 5,
+			wantIDs: []string{"app-1", "app-2"}, wantTotal: 5, wantLimit: 2, wantHasMore: true,
+		},
+		{
+			name: "last page", query: "limit=2&offset=4",
+			list: applications("app-5"), count: 5,
+			wantIDs: []string{"app-5"}, wantTotal: 5, wantLimit: 2, wantOffset: 4,
+		},
+		{
+			name: "default limit", query: "",
+			list: applications(), count: 0,
+			wantIDs: []string{}, wantLimit: 20,
+		},
+		{
+			name: "mistyped count", query: "limit=2",
+			list: applications("app-1"), count: "five",
+			wantIDs: []string{"app-1"}, wantLimit: 2,
+		},
+		{
+			name: "mistyped list", query: "limit=2",
+			list: map[string]interface{}{"nodes": applications("app-1")}, count: nil,
+			wantIDs: []string{}, wantLimit: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _, _ := newTestApplicationHandler(t, func(req gateway.GraphQLRequest) interface{} {
+				if req.Query == gateway.CountApplicationsQuery {
+					return map[string]interface{}{"applicationsCount": tt.count}
+				}
+				return map[string]interface{}{"applications": tt.list}
+			})
+
+			rec := httptest.NewRecorder()
+			h.ListApplications(rec, httptest.NewRequest(http.MethodGet, "/applications?"+tt.query, nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+
+			var envelope map[string]json.RawMessage
+			json.Unmarshal(rec.Body.Bytes(), &envelope)
+			if _, ok := envelope["items"]; !ok {
+				t.Fatalf("body = %s, want an items array", rec.Body)
+			}
+			var page PaginatedResponse
+			json.Unmarshal(rec.Body.Bytes(), &page)
+			items, _ := page.Items.([]interface{})
+			ids := []string{}
+			for _, item := range items {
+				ids = append(ids, lookupString(item, "id"))
+			}
+			if !slices.Equal(ids, tt.wantIDs) || page.Total != tt.wantTotal || page.Limit != tt.wantLimit ||
+				page.Offset != tt.wantOffset || page.HasMore != tt.wantHasMore {
+				t.Fatalf("page = %s, want items %v, total %d, limit %d, offset %d, hasMore %v",
+					rec.Body, tt.wantIDs, tt.wantTotal, tt.wantLimit, tt.wantOffset, tt.wantHasMore)
+			}
+			if got := rec.Header().Get("X-Total-Count"); got != fmt.Sprint(tt.wantTotal) {
+				t.Fatalf("X-Total-Count = %q, want %d", got, tt.wantTotal)
+			}
+		})
+	}
+}
+
+// shareLinkFake stores share links and serves app-1 with an internal and a
+// shared note
+func shareLinkFake() func(gateway.GraphQLRequest) interface{} {
+	var mu sync.Mutex
+	links := map[string]interface{}{}
+	return func(req gateway.GraphQLRequest) interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch req.Query {
+		case gateway.CreateShareLinkMutation:
+			input, _ := req.Variables["input"].(map[string]interface{})
+			links[input["token"].(string)] = input
+			return map[string]interface{}{"createShareLink": input}
+		case gateway.GetShareLinkQuery:
+			return map[string]interface{}{"shareLink": links[req.Variables["token"].(string)]}
+		case gateway.GetApplicationStatusQuery, gateway.GetApplicationQuery:
+			if req.Variables["id"] != "app-1" {
+				return map[string]interface{}{"application": nil}
+			}
+			return map[string]interface{}{"application": map[string]interface{}{
+				"id":        "app-1",
+				"status":    "INTERVIEW",
+				"resumeUrl": "https://resumes.s3.amazonaws.com/ada.pdf",
+				"candidate": map[string]interface{}{"firstName": "Ada", "resumeUrl": "https://resumes.s3.amazonaws.com/ada.pdf"},
+				"notes": []interface{}{
+					map[string]interface{}{"id": "note-1", "content": "Salary expectations are high", "isInternal": true},
+					map[string]interface{}{"id": "note-2", "content": "Strong system design round", "isInternal": false},
+				},
+			}}
+		}
+		return map[string]interface{}{}
+	}
+}
+
+func TestApplicationHandler_ShareLinks(t *testing.T) {
+	h, fake, _ := newTestApplicationHandler(t, shareLinkFake())
+	r := chi.NewRouter()
+	r.Post("/api/v1/applications/{id}/share-link", h.CreateShareLink)
+	r.Get("/api/v1/shared/applications/{token}", h.GetSharedApplication)
+
+	share := func(appID, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/applications/"+appID+"/share-link", strings.NewReader(body))
+		r.ServeHTTP(rec, asUser(req, "user-1", "recruiter"))
+		return rec
+	}
+	view := func(token string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/shared/applications/"+token, nil))
+		return rec
+	}
+
+	rec := share("app-1", `{"expiresIn":"3d","note":"Please review before Friday"}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+	}
+	var created struct {
+		URL       string    `json:"url"`
+		ExpiresAt time.Time `json:"expiresAt"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &created)
+	token, ok := strings.CutPrefix(created.URL, "https://careers.example.com/api/v1/shared/applications/")
+	if !ok || token == "" {
+		t.Fatalf("url = %q, want a shared application URL", created.URL)
+	}
+	if until := time.Until(created.ExpiresAt); until < 71*time.Hour || until > 72*time.Hour {
+		t.Fatalf("expiresAt = %v, want 3 days from now", created.ExpiresAt)
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		rec := view(token)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		var response struct {
+			Application map[string]interface{} `json:"application"`
+			Note        string                 `json:"note"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		notes, _ := response.Application["notes"].([]interface{})
+		if response.Application["id"] != "app-1" || response.Note != "Please review before Friday" ||
+			len(notes) != 1 || lookupString(notes[0], "id") != "note-2" {
+			t.Fatalf("response = %s, want the application without internal notes", rec.Body)
+		}
+		if strings.Contains(rec.Body.String(), "resumeUrl") || strings.Contains(rec.Body.String(), "Salary expectations") {
+			t.Fatalf("response = %s, want resume URLs and internal notes hidden", rec.Body)
+		}
+	})
+
+	t.Run("tampered token", func(t *testing.T) {
+		tampered := []byte(token)
+		tampered[len(tampered)-1] ^= 1
+		for _, bad := range []string{string(tampered), token[:32], "not-a-token"} {
+			if rec := view(bad); rec.Code != http.StatusUnauthorized {
+				t.Fatalf("status for %q = %d, want %d", bad, rec.Code, http.StatusUnauthorized)
+			}
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		expired, err := h.shareLinks.Create(context.Background(), "app-1", -time.Minute, "", "user-1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		rec := view(expired.Token)
+		if rec.Code != http.StatusUnauthorized || !strings.Contains(rec.Body.String(), "Share link has expired") {
+			t.Fatalf("response = %d %s, want the link refused as expired", rec.Code, rec.Body)
+		}
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		before := fake.sent(gateway.CreateShareLinkMutation)
+		tests := []struct {
+			appID      string
+			body       string
+			wantStatus int
+		}{
+			{appID: "app-1", body: `{"expiresIn":"31d"}`, wantStatus: http.StatusBadRequest},
+			{appID: "app-1", body: `{"expiresIn":"-1h"}`, wantStatus: http.StatusBadRequest},
+			{appID: "app-1", body: `{"expiresIn":"a week"}`, wantStatus: http.StatusBadRequest},
+			{appID: "app-1", body: `{`, wantStatus: http.StatusBadRequest},
+			{appID: "app-9", body: `{}`, wantStatus: http.StatusNotFound},
+		}
+		for _, tt := range tests {
+			if rec := share(tt.appID, tt.body); rec.Code != tt.wantStatus {
+				t.Fatalf("share %s %s: status = %d, want %d", tt.appID, tt.body, rec.Code, tt.wantStatus)
+			}
+		}
+		if fake.sent(gateway.CreateShareLinkMutation) != before {
+			t.Fatal("invalid share link was saved")
+		}
+	})
+
+	t.Run("default lifetime", func(t *testing.T) {
+		rec := share("app-1", `{}`)
+		json.Unmarshal(rec.Body.Bytes(), &created)
+		if until := time.Until(created.ExpiresAt); rec.Code != http.StatusCreated || until < defaultShareLinkTTL-time.Minute || until > defaultShareLinkTTL {
+			t.Fatalf("response = %d %s, want a link lasting 7 days", rec.Code, rec.Body)
+		}
+	})
+}
+
+func TestApplicationHandler_SubmitApplication_Attachments(t *testing.T) {
+	h, fake, _ := newTestApplicationHandler(t, submitApplicationFake())
+
+	submit := func(attachmentURLs []interface{}) *httptest.ResponseRecorder {
+		var input map[string]interface{}
+		json.Unmarshal([]byte(testApplication("ada@example.com")), &input)
+		input["attachmentUrls"] = attachmentURLs
+		body, _ := json.Marshal(input)
+		rec := httptest.NewRecorder()
+		h.SubmitApplication(rec, httptest.NewRequest(http.MethodPost, "/applications", strings.NewReader(string(body))))
+		return rec
+	}
+
+	t.Run("forwarded with the submission", func(t *testing.T) {
+		urls := []interface{}{"https://cdn.example.com/portfolio.pdf", "https://cdn.example.com/sample.png"}
+		if rec := submit(urls); rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+		}
+
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		for _, req := range fake.requests {
+			if req.Query != gateway.SubmitApplicationMutation {
+				continue
+			}
+			input, _ := req.Variables["input"].(map[string]interface{})
+			if got, _ := input["attachmentUrls"].([]interface{}); !slices.Equal(got, urls) {
+				t.Fatalf("attachmentUrls = %v, want %v", input["attachmentUrls"], urls)
+			}
+			return
+		}
+		t.Fatal("SubmitApplicationMutation not sent")
+	})
+
+	t.Run("more than five rejected", func(t *testing.T) {
+		urls := make([]interface{}, services.MaxApplicationAttachments+1)
+		for i := range urls {
+			urls[i] = fmt.Sprintf("https://cdn.example.com/%d.pdf", i)
+		}
+		rec := submit(urls)
+		if rec.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusUnprocessableEntity, rec.Body)
+		}
+		if !strings.Contains(rec.Body.String(), "attachmentUrls") {
+			t.Fatalf("body = %s, want an attachmentUrls error", rec.Body)
+		}
+	})
+}
+
+func TestApplicationHandler_UpdateStatus_Slack(t *testing.T) {
+	updateStatus := func(t *testing.T, webhookStatus int, status string) (*httptest.ResponseRecorder, <-chan map[string]interface{}) {
+		t.Helper()
+		messages := make(chan map[string]interface{}, 1)
+		webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var message map[string]interface{}
+			json.NewDecoder(r.Body).Decode(&message)
+			messages <- message
+			w.WriteHeader(webhookStatus)
+		}))
+		t.Cleanup(webhook.Close)
+
+		h, _, _ := newTestApplicationHandler(t, func(req gateway.GraphQLRequest) interface{} {
+			if req.Query == gateway.UpdateApplicationStatusMutation {
+				return map[string]interface{}{"updateApplicationStatus": map[string]interface{}{
+					"id":        "app-1",
+					"status":    req.Variables["status"],
+					"candidate": map[string]interface{}{"firstName": "Ada", "lastName": "Lovelace"},
+					"job":       map[string]interface{}{"title": "Backend Engineer"},
+				}}
+			}
+			return map[string]interface{}{}
+		})
+		h.chat = services.NewNotificationBroadcaster(services.NewSlackNotifier(webhook.URL, "", []string{"OFFER"}))
+
+		r := chi.NewRouter()
+		r.Patch("/applications/{id}/status", h.UpdateStatus)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/applications/app-1/status", strings.NewReader(`{"status":"`+status+`"}`)))
+		return rec, messages
+	}
+
+	t.Run("notified status", func(t *testing.T) {
+		rec, messages := updateStatus(t, http.StatusOK, "OFFER")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		select {
+		case message := <-messages:
+			posted, _ := json.Marshal(message)
+			for _, want := range []string{"Ada Lovelace", "Backend Engineer", "OFFER", "https://careers.example.com/applications/app-1"} {
+				if !strings.Contains(string(posted), want) {
+					t.Errorf("message %s does not mention %q", posted, want)
+				}
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("no Slack message posted")
+		}
+	})
+
+	t.Run("other status", func(t *testing.T) {
+		rec, messages := updateStatus(t, http.StatusOK, "SCREENING")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		select {
+		case message := <-messages:
+			t.Fatalf("unexpected Slack message %v", message)
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+
+	t.Run("Slack failure", func(t *testing.T) {
+		rec, messages := updateStatus(t, http.StatusInternalServerError, "OFFER")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200 despite Slack failing: %s", rec.Code, rec.Body)
+		}
+		select {
+		case <-messages:
+		case <-time.After(5 * time.Second):
+			t.Fatal("no Slack message attempted")
+		}
+	})
+}
+
+func TestApplicationHandler_GetResumeText(t *testing.T) {
+	newFakeS3Objects(t, map[string]s3Object{
+		"/resumes/resumes/2026/10/ada.pdf": {
+			contentType: "application/pdf",
+			body:        []byte("%PDF-1.4\n4 0 obj\n<< /Length 30 >>\nstream\nBT (Ada Lovelace) Tj ET\nendstream\nendobj\n"),
+		},
+		"/resumes/resumes/2026/10/locked.pdf": {
+			contentType: "application/pdf",
+			body:        []byte("%PDF-1.4\n4 0 obj\n<< /Length 30 >>\nstream\nBT (Ada Lovelace) Tj ET\nendstream\nendobj\ntrailer\n<< /Encrypt 5 0 R >>\n"),
+		},
+	})
+	applications := map[string]map[string]interface{}{
+		"app-1": {"id": "app-1", "resumeText": map[string]interface{}{"text": "Indexed text", "extractedAt": "2026-10-01T09:00:00Z"}},
+		"app-2": {"id": "app-2", "resumeUrl": "https://resumes.s3.amazonaws.com/resumes/2026/10/ada.pdf"},
+		"app-3": {"id": "app-3", "resumeUrl": "https://resumes.s3.amazonaws.com/resumes/2026/10/locked.pdf"},
+		"app-4": {"id": "app-4", "resumeUrl": "https://elsewhere.example.com/resume.pdf"},
+	}
+	h, fake, _ := newTestApplicationHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		switch req.Query {
+		case gateway.GetResumeTextQuery:
+			if application, ok := applications[req.Variables["id"].(string)]; ok {
+				return map[string]interface{}{"application": application}
+			}
+			return map[string]interface{}{"application": nil}
+		case gateway.IndexResumeTextMutation:
+			return map[string]interface{}{"indexResumeText": map[string]interface{}{"text": req.Variables["text"]}}
+		}
+		return map[string]interface{}{}
+	})
+
+	r := chi.NewRouter()
+	r.Get("/applications/{id}/resume-text", h.GetResumeText)
+
+	tests := []struct {
+		id         string
+		wantStatus int
+		wantText   string
+	}{
+		{id: "app-1", wantStatus: http.StatusOK, wantText: "Indexed text"},
+		{id: "app-2", wantStatus: http.StatusOK, wantText: "Ada Lovelace"},
+		{id: "app-3", wantStatus: http.StatusUnprocessableEntity},
+		{id: "app-4", wantStatus: http.StatusNotFound},
+		{id: "app-5", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/applications/"+tt.id+"/resume-text", nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var body struct {
+				ApplicationID string `json:"applicationId"`
+				Text          string `json:"text"`
+				ExtractedAt   string `json:"extractedAt"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			if body.ApplicationID != tt.id || body.Text != tt.wantText || body.ExtractedAt == "" {
+				t.Fatalf("body = %+v, want %q", body, tt.wantText)
+			}
+		})
+	}
+
+	// Only the resume without indexed text was extracted and indexed
+	if n := fake.sent(gateway.IndexResumeTextMutation); n != 1 {
+		t.Fatalf("IndexResumeTextMutation sent %d times, want 1", n)
+	}
+}
+
+// recordingNotifier records the chat notifications it is given
+type recordingNotifier struct {
+	mu            sync.Mutex
+	notifications []services.ApplicationNotification
+}
+
+func (n *recordingNotifier) Notify(notification services.ApplicationNotification) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.notifications = append(n.notifications, notification)
+}
+
+func TestApplicationHandler_SubmitApplication_Chat(t *testing.T) {
+	h, _, _ := newTestApplicationHandler(t, submitApplicationFake())
+	slack, teams := &recordingNotifier{}, &recordingNotifier{}
+	h.chat = services.NewNotificationBroadcaster(slack, teams)
+
+	rec := httptest.NewRecorder()
+	h.SubmitApplication(rec, httptest.NewRequest(http.MethodPost, "/applications", strings.NewReader(testApplication("ada@example.com"))))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+	}
+
+	want := []services.ApplicationNotification{{
+		Event:         services.ChatNewApplication,
+		ApplicationID: "app-1",
+		CandidateName: "Ada Lovelace",
+		JobTitle:      "Backend Engineer",
+		Status:        "NEW",
+		URL:           "https://careers.example.com/applications/app-1",
+	}}
+	for name, notifier := range map[string]*recordingNotifier{"slack": slack, "teams": teams} {
+		notifier.mu.Lock()
+		got := notifier.notifications
+		notifier.mu.Unlock()
+		if !slices.Equal(got, want) {
+			t.Errorf("%s got %+v, want %+v", name, got, want)
+		}
+	}
+}
+
+func TestNormalizeNoteTags(t *testing.T) {
+	tests := []struct {
+		name    string
+		tags    []string
+		want    []string
+		wantErr bool
+	}{
+		{name: "none", want: []string{}},
+		{name: "already normalized", tags: []string{"technical", "culture-fit"}, want: []string{"technical", "culture-fit"}},
+		{name: "case and spacing", tags: []string{" Technical ", "Culture  Fit"}, want: []string{"technical", "culture-fit"}},
+		{name: "blanks and repeats dropped", tags: []string{"technical", "", " ", "TECHNICAL"}, want: []string{"technical"}},
+		{name: "punctuation", tags: []string{"c++"}, wantErr: true},
+		{name: "too long", tags: []string{strings.Repeat("a", maxNoteTagLength+1)}, wantErr: true},
+		{name: "too many", tags: []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := normalizeNoteTags(tt.tags)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("normalizeNoteTags() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !slices.Equal(got, tt.want) {
+				t.Fatalf("normalizeNoteTags() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplicationHandler_NoteTags(t *testing.T) {
+	notes := []interface{}{
+		map[string]interface{}{"id": "note-1", "content": "Strong Go skills", "tags": []interface{}{"technical"}},
+		map[string]interface{}{"id": "note-2", "content": "Great with the team", "tags": []interface{}{"culture-fit"}},
+		map[string]interface{}{"id": "note-3", "content": "Solid system design", "tags": []interface{}{"technical", "culture-fit"}},
+	}
+	h, fake, _ := newTestApplicationHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		switch req.Query {
+		case gateway.AddApplicationNoteMutation:
+			return map[string]interface{}{"addApplicationNote": map[string]interface{}{"id": "note-4", "tags": req.Variables["tags"]}}
+		case gateway.GetApplicationNotesQuery:
+			tag, _ := req.Variables["tag"].(string)
+			matching := []interface{}{}
+			for _, note := range notes {
+				tags, _ := note.(map[string]interface{})["tags"].([]interface{})
+				if tag == "" || slices.Contains(tags, interface{}(tag)) {
+					matching = append(matching, note)
+				}
+			}
+			return map[string]interface{}{"applicationNotes": matching}
+		case gateway.GetNoteTagsQuery:
+			// Tags saved before normalization repeat in other forms
+			return map[string]interface{}{"noteTags": []interface{}{"technical", "Culture Fit", "culture-fit", " TECHNICAL", "c++", "behavioural"}}
+		}
+		return map[string]interface{}{}
+	})
+
+	r := chi.NewRouter()
+	r.Post("/applications/{id}/notes", h.AddNote)
+	r.Get("/applications/{id}/notes", h.GetNotes)
+	r.Get("/applications/notes/tags", h.GetNoteTags)
+	serve := func(method, target, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(method, target, strings.NewReader(body)))
+		return rec
+	}
+
+	t.Run("add with tags", func(t *testing.T) {
+		rec := serve(http.MethodPost, "/applications/app-1/notes", `{"content":"Strong Go skills","isInternal":true,"tags":["Technical","culture fit","technical"]}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+		}
+		fake.mu.Lock()
+		variables := fake.requests[len(fake.requests)-1].Variables
+		fake.mu.Unlock()
+		if tags, _ := variables["tags"].([]interface{}); !slices.Equal(tags, []interface{}{"technical", "culture-fit"}) {
+			t.Fatalf("tags = %v, want the normalized tags", variables["tags"])
+		}
+	})
+
+	t.Run("add with an invalid tag", func(t *testing.T) {
+		before := fake.sent(gateway.AddApplicationNoteMutation)
+		rec := serve(http.MethodPost, "/applications/app-1/notes", `{"content":"Knows C++","tags":["c++"]}`)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+		if fake.sent(gateway.AddApplicationNoteMutation) != before {
+			t.Fatal("note with an invalid tag was saved")
+		}
+	})
+
+	noteIDs := func(t *testing.T, rec *httptest.ResponseRecorder) []string {
+		t.Helper()
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		var body struct {
+			Notes []struct {
+				ID string `json:"id"`
+			} `json:"notes"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		ids := make([]string, len(body.Notes))
+		for i, note := range body.Notes {
+			ids[i] = note.ID
+		}
+		return ids
+	}
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{query: "", want: []string{"note-1", "note-2", "note-3"}},
+		{query: "?tag=technical", want: []string{"note-1", "note-3"}},
+		{query: "?tag=Culture+Fit", want: []string{"note-2", "note-3"}},
+		{query: "?tag=unused", want: []string{}},
+	}
+	for _, tt := range tests {
+		t.Run("list"+tt.query, func(t *testing.T) {
+			if got := noteIDs(t, serve(http.MethodGet, "/applications/app-1/notes"+tt.query, "")); !slices.Equal(got, tt.want) {
+				t.Fatalf("notes = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("distinct tags", func(t *testing.T) {
+		rec := serve(http.MethodGet, "/applications/notes/tags", "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+		var body struct {
+			Tags []string `json:"tags"`
+		}
+		if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+			t.Fatal(err)
+		}
+		if want := []string{"behavioural", "culture-fit", "technical"}; !slices.Equal(body.Tags, want) {
+			t.Fatalf("tags = %q, want %q", body.Tags, want)
+		}
+	})
+}
+
+// autoRejectionFake accepts every submission and gives every job rules
+func autoRejectionFake(rules ...interface{}) func(gateway.GraphQLRequest) interface{} {
+	submit := submitApplicationFake()
+	return func(req gateway.GraphQLRequest) interface{} {
+		switch req.Query {
+		case gateway.GetJobAutoRejectionRulesQuery:
+			return map[string]interface{}{"job": map[string]interface{}{"id": req.Variables["id"], "autoRejectionRules": rules}}
+		case gateway.UpdateApplicationStatusMutation:
+			return map[string]interface{}{"updateApplicationStatus": map[string]interface{}{
+				"id":        req.Variables["id"],
+				"status":    req.Variables["status"],
+				"candidate": map[string]interface{}{"firstName": "Ada", "lastName": "Lovelace", "email": "ada@example.com"},
+				"job":       map[string]interface{}{"title": "Backend Engineer"},
+			}}
+		}
+		return submit(req)
+	}
+}
+
+// waitForQuery waits for Hub-HRMS to be sent query, since some are sent
+// after the response
+func waitForQuery(t *testing.T, fake *fakeHubHRMS, query string) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for fake.sent(query) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("query was never sent to Hub-HRMS")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestApplicationHandler_SubmitApplication_AutoReject(t *testing.T) {
+	submit := func(t *testing.T, h *ApplicationHandler) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.SubmitApplication(rec, httptest.NewRequest(http.MethodPost, "/applications", strings.NewReader(testApplication("ada@example.com"))))
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+		}
+	}
+	rejections := func(jobs []services.EmailJob) []services.EmailJob {
+		var rejected []services.EmailJob
+		for _, job := range jobs {
+			if job.Template == "rejection" {
+				rejected = append(rejected, job)
+			}
+		}
+		return rejected
+	}
+
+	t.Run("rule matches", func(t *testing.T) {
+		h, fake, emails := newTestApplicationHandler(t, autoRejectionFake(
+			map[string]interface{}{"field": "currentLocation", "operator": "not_in", "value": []interface{}{"Berlin", "Paris"}},
+		))
+		h.features.EmailNotifications.Store(true)
+		events, unsubscribe := h.pipelineEvents.Subscribe()
+		defer unsubscribe()
+
+		submit(t, h)
+
+		// The confirmation and the rejection
+		rejected := rejections(waitForEmails(t, emails, 2))
+		if len(rejected) != 1 || rejected[0].To != "ada@example.com" || rejected[0].Data["JobTitle"] != "Backend Engineer" {
+			t.Fatalf("rejection emails = %+v, want one to the candidate", rejected)
+		}
+
+		fake.mu.Lock()
+		var update map[string]interface{}
+		for _, req := range fake.requests {
+			if req.Query == gateway.UpdateApplicationStatusMutation {
+				update = req.Variables
+			}
+		}
+		fake.mu.Unlock()
+		if update["id"] != "app-1" || update["status"] != "REJECTED" || !strings.Contains(fmt.Sprint(update["note"]), "currentLocation not_in") {
+			t.Fatalf("status update = %v, want app-1 rejected with the rule noted", update)
+		}
+
+		for {
+			select {
+			case event := <-events:
+				if event.NewStatus != "REJECTED" {
+					continue
+				}
+				if event.ApplicationID != "app-1" || event.OldStatus != "NEW" || event.JobID != "job-1" {
+					t.Fatalf("event = %+v, want app-1 moved from NEW", event)
+				}
+				return
+			case <-time.After(2 * time.Second):
+				t.Fatal("no pipeline event for the rejection")
+			}
+		}
+	})
+
+	t.Run("no rule matches", func(t *testing.T) {
+		h, fake, emails := newTestApplicationHandler(t, autoRejectionFake(
+			map[string]interface{}{"field": "currentLocation", "operator": "in", "value": []interface{}{"Berlin"}},
+			map[string]interface{}{"field": "yearsOfExperience", "operator": "lt", "value": 3},
+		))
+		h.features.EmailNotifications.Store(true)
+
+		submit(t, h)
+		waitForQuery(t, fake, gateway.GetJobAutoRejectionRulesQuery)
+		time.Sleep(50 * time.Millisecond)
+
+		if fake.sent(gateway.UpdateApplicationStatusMutation) != 0 {
+			t.Fatal("application was rejected without a matching rule")
+		}
+		if rejected := rejections(emails.enqueued()); len(rejected) != 0 {
+			t.Fatalf("rejection emails = %+v, want none", rejected)
+		}
+	})
+
+	t.Run("no rules", func(t *testing.T) {
+		h, fake, _ := newTestApplicationHandler(t, autoRejectionFake())
+
+		submit(t, h)
+		waitForQuery(t, fake, gateway.GetJobAutoRejectionRulesQuery)
+		time.Sleep(50 * time.Millisecond)
+
+		if fake.sent(gateway.UpdateApplicationStatusMutation) != 0 {
+			t.Fatal("application was rejected without rules")
+		}
+	})
+}
+
+// scoringFeedbackSent returns the inputs of the scoring feedback sent to fake
+func scoringFeedbackSent(fake *fakeHubHRMS) []map[string]interface{} {
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	var inputs []map[string]interface{}
+	for _, req := range fake.requests {
+		if req.Query == gateway.SubmitScoringFeedbackMutation {
+			input, _ := req.Variables["input"].(map[string]interface{})
+			inputs = append(inputs, input)
+		}
+	}
+	return inputs
+}
+
+func TestApplicationHandler_UpdateStatus_ScoringFeedback(t *testing.T) {
+	tests := []struct {
+		name           string
+		recommendation string
+		status         string
+		wantFeedback   bool
+	}{
+		{name: "advanced against do not proceed", recommendation: services.RecommendationDoNotProceed, status: "INTERVIEW", wantFeedback: true},
+		{name: "hired against do not proceed", recommendation: services.RecommendationDoNotProceed, status: "HIRED", wantFeedback: true},
+		{name: "rejected against proceed", recommendation: services.RecommendationProceed, status: "REJECTED", wantFeedback: true},
+		{name: "rejected against strong proceed", recommendation: services.RecommendationStrongProceed, status: "REJECTED", wantFeedback: true},
+		{name: "rejected as recommended", recommendation: services.RecommendationDoNotProceed, status: "REJECTED"},
+		{name: "advanced as recommended", recommendation: services.RecommendationStrongProceed, status: "INTERVIEW"},
+		{name: "withdrawn", recommendation: services.RecommendationProceed, status: "WITHDRAWN"},
+		{name: "not scored", status: "REJECTED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake, _ := newTestApplicationHandler(t, func(req gateway.GraphQLRequest) interface{} {
+				switch req.Query {
+				case gateway.GetApplicationStatusQuery:
+					application := map[string]interface{}{"id": "app-1", "status": "SCREENING"}
+					if tt.recommendation != "" {
+						application["aiScore"] = map[string]interface{}{"recommendation": tt.recommendation}
+					}
+					return map[string]interface{}{"application": application}
+				case gateway.UpdateApplicationStatusMutation:
+					return map[string]interface{}{"updateApplicationStatus": map[string]interface{}{"id": "app-1", "status": req.Variables["status"]}}
+				case gateway.SubmitScoringFeedbackMutation:
+					return map[string]interface{}{"submitScoringFeedback": map[string]interface{}{"id": "feedback-1"}}
+				}
+				return map[string]interface{}{}
+			})
+
+			r := chi.NewRouter()
+			r.Patch("/applications/{id}/status", h.UpdateStatus)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, asUser(httptest.NewRequest(http.MethodPatch, "/applications/app-1/status", strings.NewReader(`{"status":"`+tt.status+`"}`)), "user-7", "recruiter"))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+			}
+
+			if !tt.wantFeedback {
+				time.Sleep(50 * time.Millisecond)
+				if sent := scoringFeedbackSent(fake); len(sent) != 0 {
+					t.Fatalf("feedback sent = %v, want none", sent)
+				}
+				return
+			}
+			waitForQuery(t, fake, gateway.SubmitScoringFeedbackMutation)
+			sent := scoringFeedbackSent(fake)
+			if len(sent) != 1 {
+				t.Fatalf("feedback sent %d times, want once", len(sent))
+			}
+			input := sent[0]
+			if input["applicationId"] != "app-1" || input["agreesWithScore"] != false || input["automatic"] != true || input["submittedBy"] != "user-7" {
+				t.Fatalf("feedback = %v, want automatic disagreement from the recruiter", input)
+			}
+			if reason, _ := input["overrideReason"].(string); !strings.Contains(reason, tt.status) || !strings.Contains(reason, tt.recommendation) {
+				t.Fatalf("overrideReason = %q, want the status and the recommendation", reason)
+			}
+		})
+	}
+}
+
+func TestApplicationHandler_SubmitScoringFeedback(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantInput  map[string]interface{}
+	}{
+		{
+			name: "agrees", body: `{"agreesWithScore":true}`, wantStatus: http.StatusCreated,
+			wantInput: map[string]interface{}{"applicationId": "app-1", "agreesWithScore": true, "automatic": false, "submittedBy": "user-7"},
+		},
+		{
+			name: "disagrees with a reason", body: `{"agreesWithScore":false,"overrideReason":"  Strong open source work "}`, wantStatus: http.StatusCreated,
+			wantInput: map[string]interface{}{"applicationId": "app-1", "agreesWithScore": false, "automatic": false, "overrideReason": "Strong open source work", "submittedBy": "user-7"},
+		},
+		{name: "disagrees without a reason", body: `{"agreesWithScore":false,"overrideReason":" "}`, wantStatus: http.StatusBadRequest},
+		{name: "no verdict", body: `{"overrideReason":"Strong open source work"}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid body", body: `{`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake, _ := newTestApplicationHandler(t, func(req gateway.GraphQLRequest) interface{} {
+				return map[string]interface{}{"submitScoringFeedback": map[string]interface{}{"id": "feedback-1"}}
+			})
+
+			r := chi.NewRouter()
+			r.Post("/applications/{id}/scoring-feedback", h.SubmitScoringFeedback)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, asUser(httptest.NewRequest(http.MethodPost, "/applications/app-1/scoring-feedback", strings.NewReader(tt.body)), "user-7", "recruiter"))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+
+			sent := scoringFeedbackSent(fake)
+			if tt.wantInput == nil {
+				if len(sent) != 0 {
+					t.Fatalf("feedback sent = %v, want none", sent)
+				}
+				return
+			}
+			if len(sent) != 1 || !maps.Equal(sent[0], tt.wantInput) {
+				t.Fatalf("feedback sent = %v, want %v", sent, tt.wantInput)
+			}
+		})
+	}
+}
+
+func TestApplicationHandler_GetExperienceScore(t *testing.T) {
+	h, _, _ := newTestApplicationHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.GetApplicationJourneyQuery || req.Variables["id"] != "app-1" {
+			return map[string]interface{}{"application": nil}
+		}
+		// Acted on after ten days and still open
+		return map[string]interface{}{"application": map[string]interface{}{
+			"id":          "app-1",
+			"appliedDate": "2026-03-02T09:00:00Z",
+			"timeline": []interface{}{
+				map[string]interface{}{"type": "STATUS_CHANGE", "status": "SCREENING", "timestamp": "2026-03-12T09:00:00Z"},
+			},
+		}}
+	})
+	r := chi.NewRouter()
+	r.Get("/applications/{id}/experience-score", h.GetExperienceScore)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, asUser(httptest.NewRequest(http.MethodGet, "/applications/app-1/experience-score", nil), "user-7", "recruiter"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var score services.CandidateExperienceScore
+	json.Unmarshal(rec.Body.Bytes(), &score)
+	details := score.Details
+	if details["timeToFirstActionHours"] != 240.0 || details["respondedWithinTarget"] != false ||
+		details["statusChangesWithoutNotes"] != 1.0 || details["completed"] != false {
+		t.Fatalf("details = %v, want a first action after 240 hours without a note", details)
+	}
+	if score.Score <= 0 || score.Score >= 50 {
+		t.Fatalf("score = %d, want a slow response scored low", score.Score)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, asUser(httptest.NewRequest(http.MethodGet, "/applications/app-9/experience-score", nil), "user-7", "recruiter"))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("missing application status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/config"
+	"hr-recruiting/internal/middleware"
+)
+
+// clientCredentialsGrant is the only OAuth2 grant the token endpoint issues
+const clientCredentialsGrant = "client_credentials"
+
+// machineRoles are the roles carried by tokens issued to integrations
+var machineRoles = []string{"api"}
+
+// AuthHandler issues tokens to registered machine clients and manages API keys
+type AuthHandler struct {
+	clients  map[string]config.OAuthClient
+	secret   []byte
+	issuer   string
+	tokenTTL time.Duration
+	apiKeys  *middleware.APIKeyStore
+}
+
+// NewAuthHandler creates a new auth handler. Tokens are signed with the same
+// HMAC secret AuthMiddleware validates against.
+func NewAuthHandler(
+	clients map[string]config.OAuthClient,
+	secret, issuer string,
+	tokenTTL time.Duration,
+	apiKeys *middleware.APIKeyStore,
+) *AuthHandler {
+	return &AuthHandler{
+		clients:  clients,
+		secret:   []byte(secret),
+		issuer:   issuer,
+		tokenTTL: tokenTTL,
+		apiKeys:  apiKeys,
+	}
+}
+
+// IssueToken implements the OAuth2 client credentials grant. Credentials are
+// accepted as JSON or as a form-encoded body.
+func (h *AuthHandler) IssueToken(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+		GrantType    string `json:"grant_type"`
+	}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/x-www-form-urlencoded") {
+		if err := r.ParseForm(); err != nil {
+			respondOAuthError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+			return
+		}
+		input.ClientID = r.PostForm.Get("client_id")
+		input.ClientSecret = r.PostForm.Get("client_secret")
+		input.GrantType = r.PostForm.Get("grant_type")
+	} else if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondOAuthError(w, http.StatusBadRequest, "invalid_request", "Invalid request body")
+		return
+	}
+	defer r.Body.Close()
+
+	if input.GrantType != clientCredentialsGrant {
+		respondOAuthError(w, http.StatusBadRequest, "unsupported_grant_type", "Only the client_credentials grant is supported")
+		return
+	}
+	if input.ClientID == "" || input.ClientSecret == "" {
+		respondOAuthError(w, http.StatusBadRequest, "invalid_request", "client_id and client_secret are required")
+		return
+	}
+	if !h.authenticate(input.ClientID, input.ClientSecret) {
+		respondOAuthError(w, http.StatusUnauthorized, "invalid_client", "Invalid client credentials")
+		return
+	}
+
+	now := time.Now()
+	token, err := middleware.SignHS256(middleware.Claims{
+		Subject:   input.ClientID,
+		Issuer:    h.issuer,
+		Roles:     machineRoles,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(h.tokenTTL).Unix(),
+	}, h.secret)
+	if err != nil {
+		respondError(w, http.StatusServiceUnavailable, "Token issuance is not configured", err)
+		return
+	}
+
+	// Token responses must not be cached (RFC 6749 section 5.1)
+	w.Header().Set("Cache-Control", "no-store")
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"access_token": token,
+		"token_type":   "Bearer",
+		"expires_in":   int(h.tokenTTL.Seconds()),
+	})
+}
+
+// ListClients returns the registered clients, without their secrets
+func (h *AuthHandler) ListClients(w http.ResponseWriter, r *http.Request) {
+	clients := make([]map[string]interface{}, 0, len(h.clients))
+	for id, client := range h.clients {
+		clients = append(clients, map[string]interface{}{
+			"clientId": id,
+			"name":     client.Name,
+			"roles":    machineRoles,
+		})
+	}
+	sort.Slice(clients, func(i, j int) bool {
+		return clients[i]["clientId"].(string) < clients[j]["clientId"].(string)
+	})
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"clients": clients})
+}
+
+// CreateAPIKey issues a new API key. The key is only ever shown in this
+// response.
+func (h *AuthHandler) CreateAPIKey(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Name      string   `json:"name"`
+		Roles     []string `json:"roles"`
+		RateLimit float64  `json:"rateLimit"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if input.Name == "" {
+		respondError(w, http.StatusBadRequest, "Name is required", nil)
+		return
+	}
+	if input.RateLimit < 0 {
+		respondError(w, http.StatusBadRequest, "rateLimit cannot be negative", nil)
+		return
+	}
+
+	key, plaintext, err := h.apiKeys.Create(input.Name, input.Roles, input.RateLimit)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create API key", err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"id":        key.ID,
+		"name":      key.Name,
+		"roles":     key.Roles,
+		"rateLimit": key.RateLimit,
+		"createdAt": key.CreatedAt,
+		"key":       plaintext,
+	})
+}
+
+// RevokeAPIKey deletes an API key so it stops authenticating immediately
+func (h *AuthHandler) RevokeAPIKey(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	err := h.apiKeys.Revoke(id)
+	if errors.Is(err, middleware.ErrAPIKeyNotFound) {
+		respondError(w, http.StatusNotFound, "API key not found", nil)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to revoke API key", err)
+		return
+	}
+
+	respondSuccess(w, "API key revoked successfully", nil)
+}
+
+// authenticate checks secret against the client's stored hash in constant time
+func (h *AuthHandler) authenticate(clientID, secret string) bool {
+	client, ok := h.clients[clientID]
+	if !ok {
+		return false
+	}
+	sum := sha256.Sum256([]byte(secret))
+	given := hex.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(given), []byte(strings.ToLower(client.SecretHash))) == 1
+}
+
+// respondOAuthError writes an error whose error field carries the OAuth2
+// error code, so standard OAuth clients can interpret it
+func respondOAuthError(w http.ResponseWriter, status int, code, message string) {
+	respondJSON(w, status, ErrorResponse{
+		Error:   code,
+		Message: message,
+		Status:  status,
+	})
+}
@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/config"
+	"hr-recruiting/internal/middleware"
+)
+
+const (
+	testTokenSecret  = "token-signing-secret"
+	testTokenIssuer  = "hr-recruiting"
+	testClientSecret = "s3cret-for-ci"
+)
+
+// newTestAuthHandler returns an auth handler with the ci and hr-tool clients
+// registered, both using testClientSecret
+func newTestAuthHandler(t *testing.T) *AuthHandler {
+	t.Helper()
+	sum := sha256.Sum256([]byte(testClientSecret))
+	hash := hex.EncodeToString(sum[:])
+	return NewAuthHandler(map[string]config.OAuthClient{
+		"ci":      {Name: "CI pipeline", SecretHash: hash},
+		"hr-tool": {Name: "HR tool", SecretHash: strings.ToUpper(hash)},
+	}, testTokenSecret, testTokenIssuer, 15*time.Minute, nil)
+}
+
+// requestToken posts body to the token endpoint as contentType
+func requestToken(h *AuthHandler, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/auth/token", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+	h.IssueToken(rec, req)
+	return rec
+}
+
+func TestAuthHandler_IssueToken(t *testing.T) {
+	h := newTestAuthHandler(t)
+	validator, err := middleware.NewJWTValidator(testTokenSecret, "", testTokenIssuer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		clientID    string
+	}{
+		{
+			name:        "JSON credentials",
+			contentType: "application/json",
+			body:        `{"client_id":"ci","client_secret":"` + testClientSecret + `","grant_type":"client_credentials"}`,
+			clientID:    "ci",
+		},
+		{
+			name:        "form credentials",
+			contentType: "application/x-www-form-urlencoded",
+			body:        url.Values{"client_id": {"hr-tool"}, "client_secret": {testClientSecret}, "grant_type": {"client_credentials"}}.Encode(),
+			clientID:    "hr-tool",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := time.Now().Unix()
+			rec := requestToken(h, tt.contentType, tt.body)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+			if got := rec.Header().Get("Cache-Control"); got != "no-store" {
+				t.Fatalf("Cache-Control = %q, want no-store", got)
+			}
+
+			var body struct {
+				AccessToken string `json:"access_token"`
+				TokenType   string `json:"token_type"`
+				ExpiresIn   int    `json:"expires_in"`
+			}
+			json.NewDecoder(rec.Body).Decode(&body)
+			if body.TokenType != "Bearer" || body.ExpiresIn != 900 {
+				t.Fatalf("token_type = %q, expires_in = %d, want Bearer and 900", body.TokenType, body.ExpiresIn)
+			}
+
+			claims, err := validator.Validate(body.AccessToken)
+			if err != nil {
+				t.Fatalf("issued token does not validate: %v", err)
+			}
+			if claims.Subject != tt.clientID || !slices.Equal(claims.Roles, []string{"api"}) {
+				t.Fatalf("claims = %+v, want sub %s and roles [api]", claims, tt.clientID)
+			}
+			if claims.ExpiresAt < before+900 || claims.ExpiresAt > time.Now().Unix()+900 {
+				t.Fatalf("exp = %d, want 15 minutes from now", claims.ExpiresAt)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_IssueToken_Rejected(t *testing.T) {
+	h := newTestAuthHandler(t)
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantError  string
+	}{
+		{
+			name:       "bad secret",
+			body:       `{"client_id":"ci","client_secret":"wrong","grant_type":"client_credentials"}`,
+			wantStatus: http.StatusUnauthorized,
+			wantError:  "invalid_client",
+		},
+		{
+			name:       "unknown client",
+			body:       `{"client_id":"intruder","client_secret":"` + testClientSecret + `","grant_type":"client_credentials"}`,
+			wantStatus: http.StatusUnauthorized,
+			wantError:  "invalid_client",
+		},
+		{
+			name:       "unsupported grant type",
+			body:       `{"client_id":"ci","client_secret":"` + testClientSecret + `","grant_type":"password"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "unsupported_grant_type",
+		},
+		{
+			name:       "missing secret",
+			body:       `{"client_id":"ci","grant_type":"client_credentials"}`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid_request",
+		},
+		{
+			name:       "malformed body",
+			body:       `{"client_id":`,
+			wantStatus: http.StatusBadRequest,
+			wantError:  "invalid_request",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := requestToken(h, "application/json", tt.body)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			var body ErrorResponse
+			json.NewDecoder(rec.Body).Decode(&body)
+			if body.Error != tt.wantError {
+				t.Fatalf("error = %q, want %q", body.Error, tt.wantError)
+			}
+			if strings.Contains(rec.Body.String(), "access_token") {
+				t.Fatalf("body = %s, want no token", rec.Body)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_MachineTokenAuthenticates(t *testing.T) {
+	h := newTestAuthHandler(t)
+	validator, err := middleware.NewJWTValidator(testTokenSecret, "", testTokenIssuer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := requestToken(h, "application/json", `{"client_id":"ci","client_secret":"`+testClientSecret+`","grant_type":"client_credentials"}`)
+	var body struct {
+		AccessToken string `json:"access_token"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+
+	r := chi.NewRouter()
+	r.Use(middleware.AuthMiddleware(validator, nil))
+	r.With(middleware.RequireAuth).Get("/api/v1/applications", func(w http.ResponseWriter, r *http.Request) {
+		respondJSON(w, http.StatusOK, map[string]interface{}{"caller": userID(r.Context()), "roles": middleware.GetUserRoles(r.Context())})
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/applications", nil)
+	req.Header.Set("Authorization", "Bearer "+body.AccessToken)
+	protected := httptest.NewRecorder()
+	r.ServeHTTP(protected, req)
+	if protected.Code != http.StatusOK || protected.Body.String() != `{"caller":"ci","roles":["api"]}`+"\n" {
+		t.Fatalf("protected endpoint = %d %s, want the ci client with the api role", protected.Code, protected.Body)
+	}
+}
+
+func TestAuthHandler_ListClients(t *testing.T) {
+	rec := httptest.NewRecorder()
+	newTestAuthHandler(t).ListClients(rec, httptest.NewRequest(http.MethodGet, "/api/v1/auth/clients", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	want := `{"clients":[{"clientId":"ci","name":"CI pipeline","roles":["api"]},{"clientId":"hr-tool","name":"HR tool","roles":["api"]}]}` + "\n"
+	if rec.Body.String() != want {
+		t.Fatalf("body = %s, want %s", rec.Body, want)
+	}
+}
@@ -0,0 +1,114 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/services"
+)
+
+// maxDraftBodySize caps the application bodies WithDraft buffers
+const maxDraftBodySize = 1 << 20
+
+// WithDraft completes a submitted application that names a saved draft with
+// the draft's fields, ahead of body validation. Submitted fields win over
+// saved ones; draftId is left in the body for the handler.
+func (h *ApplicationHandler) WithDraft(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxDraftBodySize))
+		r.Body.Close()
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			respondError(w, http.StatusRequestEntityTooLarge, "Request body is too large", nil)
+			return
+		}
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Failed to read request body", err)
+			return
+		}
+
+		var input map[string]interface{}
+		if json.Unmarshal(body, &input) == nil {
+			if draftID, _ := input["draftId"].(string); draftID != "" {
+				draft, err := h.drafts.Get(r.Context(), draftID)
+				if errors.Is(err, services.ErrDraftNotFound) {
+					respondError(w, http.StatusNotFound, "Draft not found or expired", nil)
+					return
+				}
+				if err != nil {
+					respondError(w, http.StatusInternalServerError, "Failed to load draft", err)
+					return
+				}
+				if body, err = json.Marshal(services.MergeDraft(draft.Data, input)); err != nil {
+					respondError(w, http.StatusInternalServerError, "Failed to load draft", err)
+					return
+				}
+			}
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// SaveDraft saves a partly completed application so the candidate can
+// finish it later. A body with the draftId of a saved draft replaces it.
+func (h *ApplicationHandler) SaveDraft(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var input map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	draftID, _ := input["draftId"].(string)
+	delete(input, "draftId")
+
+	draft, err := h.drafts.Save(ctx, draftID, input)
+	if errors.Is(err, services.ErrDraftNotFound) {
+		respondError(w, http.StatusNotFound, "Draft not found or expired", nil)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to save draft", err)
+		return
+	}
+
+	status := http.StatusCreated
+	if draftID != "" {
+		status = http.StatusOK
+	}
+	respondJSON(w, status, draft)
+}
+
+// GetDraft returns a saved application draft
+func (h *ApplicationHandler) GetDraft(w http.ResponseWriter, r *http.Request) {
+	draft, err := h.drafts.Get(r.Context(), chi.URLParam(r, "draftId"))
+	if errors.Is(err, services.ErrDraftNotFound) {
+		respondError(w, http.StatusNotFound, "Draft not found or expired", nil)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch draft", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, draft)
+}
+
+// DeleteDraft discards a saved application draft
+func (h *ApplicationHandler) DeleteDraft(w http.ResponseWriter, r *http.Request) {
+	if err := h.drafts.Delete(r.Context(), chi.URLParam(r, "draftId")); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to delete draft", err)
+		return
+	}
+
+	respondSuccess(w, "Draft deleted successfully", nil)
+}
@@ -0,0 +1,130 @@
+package handlers
+
+import (
+	"encoding/json"
+	"maps"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/middleware"
+	"hr-recruiting/internal/services"
+)
+
+func TestApplicationHandler_Drafts(t *testing.T) {
+	h, fake, _ := newTestApplicationHandler(t, submitApplicationFake())
+	r := chi.NewRouter()
+	r.With(h.WithDraft, middleware.ValidateBody("submit_application")).Post("/applications", h.SubmitApplication)
+	r.With(middleware.ValidateBody("draft_application")).Post("/applications/draft", h.SaveDraft)
+	r.Get("/applications/draft/{draftId}", h.GetDraft)
+	r.Delete("/applications/draft/{draftId}", h.DeleteDraft)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(body)))
+		return rec
+	}
+	save := func(body string) services.ApplicationDraft {
+		t.Helper()
+		rec := do(http.MethodPost, "/applications/draft", body)
+		if rec.Code != http.StatusCreated && rec.Code != http.StatusOK {
+			t.Fatalf("save status = %d: %s", rec.Code, rec.Body)
+		}
+		var draft services.ApplicationDraft
+		json.Unmarshal(rec.Body.Bytes(), &draft)
+		return draft
+	}
+
+	t.Run("save and fetch", func(t *testing.T) {
+		rec := do(http.MethodPost, "/applications/draft", `{"jobId":"job-1","firstName":"Ada"}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want 201: %s", rec.Code, rec.Body)
+		}
+		var draft services.ApplicationDraft
+		json.Unmarshal(rec.Body.Bytes(), &draft)
+		if draft.ID == "" || draft.ExpiresAt.IsZero() {
+			t.Fatalf("draft = %+v, want an id and expiry", draft)
+		}
+
+		// Saving with the draftId replaces it
+		rec = do(http.MethodPost, "/applications/draft", `{"draftId":"`+draft.ID+`","jobId":"job-1","firstName":"Ada","lastName":"Lovelace"}`)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("update status = %d, want 200: %s", rec.Code, rec.Body)
+		}
+
+		rec = do(http.MethodGet, "/applications/draft/"+draft.ID, "")
+		var got services.ApplicationDraft
+		json.Unmarshal(rec.Body.Bytes(), &got)
+		want := map[string]interface{}{"jobId": "job-1", "firstName": "Ada", "lastName": "Lovelace"}
+		if rec.Code != http.StatusOK || got.ID != draft.ID || !maps.Equal(got.Data, want) {
+			t.Fatalf("draft = %d %+v, want %v", rec.Code, got, want)
+		}
+	})
+
+	t.Run("invalid draft", func(t *testing.T) {
+		if rec := do(http.MethodPost, "/applications/draft", `{"email":"not an email"}`); rec.Code != http.StatusUnprocessableEntity {
+			t.Fatalf("status = %d, want 422: %s", rec.Code, rec.Body)
+		}
+		if rec := do(http.MethodPost, "/applications/draft", `{"draftId":"0b9f7c1e-2f4a-4c55-9d6e-0b4b2b7f8a11"}`); rec.Code != http.StatusNotFound {
+			t.Fatalf("unknown draftId status = %d, want 404", rec.Code)
+		}
+	})
+
+	t.Run("submit merges the draft", func(t *testing.T) {
+		draft := save(`{"jobId":"job-1","firstName":"Ada","lastName":"Lovelace","phone":"+44 20 7946 0000","currentLocation":"Paris","availability":"immediately"}`)
+
+		// The submission completes the draft and overrides its location
+		rec := do(http.MethodPost, "/applications", `{"draftId":"`+draft.ID+`","email":"ada@example.com","resumeUrl":"https://cdn.example.com/ada.pdf","currentLocation":"London"}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want 201: %s", rec.Code, rec.Body)
+		}
+
+		fake.mu.Lock()
+		var input map[string]interface{}
+		for _, req := range fake.requests {
+			if req.Query == gateway.SubmitApplicationMutation {
+				input, _ = req.Variables["input"].(map[string]interface{})
+			}
+		}
+		fake.mu.Unlock()
+		if input["firstName"] != "Ada" || input["phone"] != "+44 20 7946 0000" || input["email"] != "ada@example.com" || input["currentLocation"] != "London" {
+			t.Fatalf("submitted %v, want the draft merged under the submission", input)
+		}
+		if _, ok := input["draftId"]; ok {
+			t.Fatalf("submitted %v, want draftId left out", input)
+		}
+
+		if rec := do(http.MethodGet, "/applications/draft/"+draft.ID, ""); rec.Code != http.StatusNotFound {
+			t.Fatalf("draft after submission = %d, want 404", rec.Code)
+		}
+	})
+
+	t.Run("submit with an expired draft", func(t *testing.T) {
+		submitted := fake.sent(gateway.SubmitApplicationMutation)
+		rec := do(http.MethodPost, "/applications", `{"draftId":"0b9f7c1e-2f4a-4c55-9d6e-0b4b2b7f8a11","email":"ada@example.com"}`)
+		if rec.Code != http.StatusNotFound {
+			t.Fatalf("status = %d, want 404: %s", rec.Code, rec.Body)
+		}
+		if fake.sent(gateway.SubmitApplicationMutation) != submitted {
+			t.Fatal("application was submitted without its draft")
+		}
+	})
+
+	t.Run("discard", func(t *testing.T) {
+		draft := save(`{"jobId":"job-1"}`)
+		if rec := do(http.MethodDelete, "/applications/draft/"+draft.ID, ""); rec.Code != http.StatusOK {
+			t.Fatalf("delete status = %d, want 200", rec.Code)
+		}
+		if rec := do(http.MethodGet, "/applications/draft/"+draft.ID, ""); rec.Code != http.StatusNotFound {
+			t.Fatalf("draft after delete = %d, want 404", rec.Code)
+		}
+		// Discarding twice is harmless
+		if rec := do(http.MethodDelete, "/applications/draft/"+draft.ID, ""); rec.Code != http.StatusOK {
+			t.Fatalf("second delete status = %d, want 200", rec.Code)
+		}
+	})
+}
@@ -7,16 +7,18 @@ import (
 	"time"
 
 	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/services"
 )
 
 // HealthHandler handles health check requests
 type HealthHandler struct {
-	client *gateway.HubHRMSClient
+	client  *gateway.HubHRMSClient
+	monitor *services.HealthMonitor
 }
 
 // NewHealthHandler creates a new health handler
-func NewHealthHandler(client *gateway.HubHRMSClient) *HealthHandler {
-	return &HealthHandler{client: client}
+func NewHealthHandler(client *gateway.HubHRMSClient, monitor *services.HealthMonitor) *HealthHandler {
+	return &HealthHandler{client: client, monitor: monitor}
 }
 
 // Health returns the overall health status
@@ -38,14 +40,33 @@ func (h *HealthHandler) Health(w http.ResponseWriter, r *http.Request) {
 		health["checks"].(map[string]interface{})["hubhrms_error"] = err.Error()
 		health["status"] = "degraded"
 		w.WriteHeader(http.StatusServiceUnavailable)
+	} else if monitored := h.monitor.Snapshot(); monitored.Degraded() {
+		// Reachable, but recent probes have been too slow to rely on
+		health["checks"].(map[string]interface{})["hubhrms"] = "degraded"
+		health["checks"].(map[string]interface{})["hubhrms_p99_ms"] = monitored.P99Ms
+		health["status"] = "degraded"
 	} else {
 		health["checks"].(map[string]interface{})["hubhrms"] = "healthy"
 	}
 
+	health["hubhrms_pool"] = h.client.PoolStats()
+	health["hubhrms_connections"] = h.client.ConnectionStats()
+
 	w.Header().Set("Content-Type", "application/json")
 	json.NewEncoder(w).Encode(health)
 }
 
+// HealthMetrics reports the background monitor's view of Hub-HRMS: the
+// latest probe and latency percentiles over recent ones
+func (h *HealthHandler) HealthMetrics(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"uptime":  int64(h.monitor.Uptime().Seconds()),
+		"hubhrms": h.monitor.Snapshot(),
+	})
+}
+
 // Liveness is a simple liveness probe
 func (h *HealthHandler) Liveness(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "application/json")
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/services"
+)
+
+func TestHealthHandler_HealthMetrics(t *testing.T) {
+	_, client := newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+		return map[string]interface{}{"__typename": "Query"}
+	})
+	monitor := services.NewHealthMonitor(client, time.Hour)
+	monitor.Start()
+	t.Cleanup(func() { monitor.Shutdown(context.Background()) })
+	h := NewHealthHandler(client, monitor)
+
+	// Start probes straight away
+	deadline := time.Now().Add(5 * time.Second)
+	for monitor.Snapshot().LastCheckAt.IsZero() {
+		if time.Now().After(deadline) {
+			t.Fatal("monitor never probed Hub-HRMS")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	rec := httptest.NewRecorder()
+	h.HealthMetrics(rec, httptest.NewRequest(http.MethodGet, "/health/metrics", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", rec.Code)
+	}
+	var body struct {
+		Uptime  *int64                  `json:"uptime"`
+		HubHRMS *services.HubHRMSHealth `json:"hubhrms"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.Uptime == nil || body.HubHRMS == nil {
+		t.Fatalf("body = %+v, want uptime and hubhrms", body)
+	}
+	if !body.HubHRMS.Healthy || body.HubHRMS.ConsecutiveFailures != 0 || body.HubHRMS.P50Ms <= 0 || body.HubHRMS.LastCheckAt.IsZero() {
+		t.Fatalf("hubhrms = %+v, want a healthy probe with its latency", body.HubHRMS)
+	}
+
+	// A reachable Hub-HRMS with fast probes is healthy
+	rec = httptest.NewRecorder()
+	h.Health(rec, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var health struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks"`
+	}
+	json.NewDecoder(rec.Body).Decode(&health)
+	if rec.Code != http.StatusOK || health.Status != "healthy" || health.Checks["hubhrms"] != "healthy" {
+		t.Fatalf("health = %d %+v, want healthy", rec.Code, health)
+	}
+}
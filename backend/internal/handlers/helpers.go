@@ -2,8 +2,13 @@ package handlers
 
 import (
 	"encoding/json"
-	"log"
+	"errors"
+	"log/slog"
 	"net/http"
+	"strconv"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/util"
 )
 
 // ErrorResponse represents an error response
@@ -20,12 +25,19 @@ func respondJSON(w http.ResponseWriter, status int, data interface{}) {
 	w.WriteHeader(status)
 	
 	if err := json.NewEncoder(w).Encode(data); err != nil {
-		log.Printf("Failed to encode JSON response: %v", err)
+		slog.Error("failed to encode JSON response", "error", err)
 	}
 }
 
-// respondError writes an error response
+// respondError writes an error response. A server error caused by a failed
+// Hub-HRMS call is answered with the status its GraphQL errors map to, so a
+// missing record is a 404 rather than a 500.
 func respondError(w http.ResponseWriter, status int, message string, err error) {
+	var gwErr *gateway.GatewayError
+	if (status == http.StatusInternalServerError || status == http.StatusBadGateway) && errors.As(err, &gwErr) {
+		status = gwErr.StatusCode()
+	}
+
 	response := ErrorResponse{
 		Error:   http.StatusText(status),
 		Message: message,
@@ -34,12 +46,28 @@ func respondError(w http.ResponseWriter, status int, message string, err error)
 	
 	if err != nil {
 		response.Details = err.Error()
-		log.Printf("Error: %s - %v", message, err)
+		slog.Warn("request failed", "status", status, "message", message, "error", err)
 	}
 	
 	respondJSON(w, status, response)
 }
 
+// RespondJSON writes a JSON response. It is respondJSON for the versioned
+// handler packages, so every API version encodes responses the same way.
+func RespondJSON(w http.ResponseWriter, status int, data interface{}) {
+	respondJSON(w, status, data)
+}
+
+// RespondError writes an error response like respondError
+func RespondError(w http.ResponseWriter, status int, message string, err error) {
+	respondError(w, status, message, err)
+}
+
+// ParsePagination reads limit and offset like parsePagination
+func ParsePagination(r *http.Request) (limit, offset int, err error) {
+	return parsePagination(r)
+}
+
 // respondSuccess writes a success response with a message
 func respondSuccess(w http.ResponseWriter, message string, data interface{}) {
 	response := map[string]interface{}{
@@ -52,4 +80,84 @@ func respondSuccess(w http.ResponseWriter, message string, data interface{}) {
 	}
 	
 	respondJSON(w, http.StatusOK, response)
-}
\ No newline at end of file
+}
+
+// lookupString walks nested response data by key and returns the string at
+// the end of the path, or "" if any step is missing or has the wrong shape
+func lookupString(data interface{}, path ...string) string {
+	value, _ := lookup(data, path...).(string)
+	return value
+}
+
+// lookup walks nested response data by key and returns the value at the end
+// of the path, or nil if any step is missing
+func lookup(data interface{}, path ...string) interface{} {
+	current := data
+	for _, key := range path {
+		m, ok := current.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		current = m[key]
+	}
+	return current
+}
+
+// PaginatedResponse is the envelope list endpoints return. Total is the
+// number of matching items across all pages, when the endpoint counts them.
+// Keyset-paginated lists set NextCursor instead of advancing Offset.
+type PaginatedResponse struct {
+	Items      interface{} `json:"items"`
+	Total      int         `json:"total,omitempty"`
+	Limit      int         `json:"limit"`
+	Offset     int         `json:"offset"`
+	HasMore    bool        `json:"hasMore"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+}
+
+// extractCount reads a count from response data, returning 0 when data is
+// not an object or the field is missing or not a number
+func extractCount(data interface{}, field string) int {
+	switch count := lookup(data, field).(type) {
+	case float64:
+		return int(count)
+	case int:
+		return count
+	}
+	return 0
+}
+
+// parsePagination reads limit and offset from the query string. A signed
+// cursor, when present, takes precedence over a raw offset.
+func parsePagination(r *http.Request) (limit, offset int, err error) {
+	limit = 20
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
+	}
+
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		value, err := util.DecodeCursor(cursor)
+		if err != nil {
+			return 0, 0, err
+		}
+		if offset, err = strconv.Atoi(value); err != nil || offset < 0 {
+			return 0, 0, util.ErrInvalidCursor
+		}
+		return limit, offset, nil
+	}
+
+	if o, err := strconv.Atoi(r.URL.Query().Get("offset")); err == nil && o >= 0 {
+		offset = o
+	}
+	return limit, offset, nil
+}
+
+// setNextCursor advertises the cursor for the next page when the current page
+// came back full
+func setNextCursor(w http.ResponseWriter, items interface{}, limit, offset int) {
+	list, ok := items.([]interface{})
+	if !ok || len(list) < limit {
+		return
+	}
+	w.Header().Set("X-Next-Cursor", util.EncodeCursor(strconv.Itoa(offset+len(list))))
+}
@@ -0,0 +1,196 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/SebastiaanKlippert/go-wkhtmltopdf"
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/middleware"
+	"hr-recruiting/internal/services"
+)
+
+// fakeHubHRMS answers GraphQL requests with the data respond returns for
+// them, or with the whole response when respond returns a
+// *gateway.GraphQLResponse, and records the requests it was sent
+type fakeHubHRMS struct {
+	mu       sync.Mutex
+	requests []gateway.GraphQLRequest
+	respond  func(req gateway.GraphQLRequest) interface{}
+}
+
+// newFakeHubHRMS starts a fake Hub-HRMS and returns a client for it
+func newFakeHubHRMS(t *testing.T, respond func(req gateway.GraphQLRequest) interface{}) (*fakeHubHRMS, *gateway.HubHRMSClient) {
+	t.Helper()
+	fake := &fakeHubHRMS{respond: respond}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req gateway.GraphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, req)
+		fake.mu.Unlock()
+
+		data := fake.respond(req)
+		if resp, ok := data.(*gateway.GraphQLResponse); ok {
+			json.NewEncoder(w).Encode(resp)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	t.Cleanup(server.Close)
+
+	client := gateway.NewHubHRMSClient(server.URL, "")
+	t.Cleanup(client.Close)
+	return fake, client
+}
+
+// sent counts the requests made with query
+func (f *fakeHubHRMS) sent(query string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	count := 0
+	for _, req := range f.requests {
+		if req.Query == query {
+			count++
+		}
+	}
+	return count
+}
+
+// recordingEmailQueue keeps the emails enqueued instead of sending them
+type recordingEmailQueue struct {
+	mu   sync.Mutex
+	jobs []services.EmailJob
+}
+
+func (q *recordingEmailQueue) Enqueue(job services.EmailJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.jobs = append(q.jobs, job)
+	return nil
+}
+
+func (q *recordingEmailQueue) enqueued() []services.EmailJob {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return append([]services.EmailJob(nil), q.jobs...)
+}
+
+// asUser returns r as sent by an authenticated user with id and roles
+func asUser(r *http.Request, id string, roles ...string) *http.Request {
+	return r.WithContext(middleware.WithUser(r.Context(), map[string]interface{}{
+		"id":    id,
+		"roles": roles,
+	}))
+}
+
+// fakePDFConverter installs a wkhtmltopdf stand-in for the rest of the test
+// that writes a PDF header followed by the HTML it was given
+func fakePDFConverter(t *testing.T) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "wkhtmltopdf")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\nprintf '%%PDF-1.4\\n'; cat\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	previous := wkhtmltopdf.GetPath()
+	services.SetPDFConverterPath(path)
+	t.Cleanup(func() { wkhtmltopdf.SetPath(previous) })
+}
+
+func TestExtractCount(t *testing.T) {
+	tests := []struct {
+		name string
+		data interface{}
+		want int
+	}{
+		{name: "decoded number", data: map[string]interface{}{"jobsCount": float64(42)}, want: 42},
+		{name: "int", data: map[string]interface{}{"jobsCount": 7}, want: 7},
+		{name: "nil data", data: nil, want: 0},
+		{name: "data not an object", data: []interface{}{float64(42)}, want: 0},
+		{name: "missing field", data: map[string]interface{}{"applicationsCount": float64(3)}, want: 0},
+		{name: "null field", data: map[string]interface{}{"jobsCount": nil}, want: 0},
+		{name: "string", data: map[string]interface{}{"jobsCount": "42"}, want: 0},
+		{name: "object", data: map[string]interface{}{"jobsCount": map[string]interface{}{"total": float64(42)}}, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := extractCount(tt.data, "jobsCount"); got != tt.want {
+				t.Fatalf("extractCount() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRespondError(t *testing.T) {
+	gatewayError := func(class gateway.ErrorClass) error {
+		return fmt.Errorf("failed to fetch job: %w", &gateway.GatewayError{Class: class, Message: "from Hub-HRMS"})
+	}
+
+	tests := []struct {
+		name       string
+		status     int
+		err        error
+		wantStatus int
+	}{
+		{name: "not found", status: http.StatusInternalServerError, err: gatewayError(gateway.ErrorNotFound), wantStatus: http.StatusNotFound},
+		{name: "forbidden", status: http.StatusInternalServerError, err: gatewayError(gateway.ErrorForbidden), wantStatus: http.StatusForbidden},
+		{name: "validation", status: http.StatusInternalServerError, err: gatewayError(gateway.ErrorValidation), wantStatus: http.StatusBadRequest},
+		{name: "internal", status: http.StatusInternalServerError, err: gatewayError(gateway.ErrorInternal), wantStatus: http.StatusBadGateway},
+		{name: "bad gateway remapped", status: http.StatusBadGateway, err: gatewayError(gateway.ErrorNotFound), wantStatus: http.StatusNotFound},
+		// A status the handler chose deliberately is kept
+		{name: "client error kept", status: http.StatusConflict, err: gatewayError(gateway.ErrorNotFound), wantStatus: http.StatusConflict},
+		{name: "other errors kept", status: http.StatusInternalServerError, err: errors.New("connection refused"), wantStatus: http.StatusInternalServerError},
+		{name: "no error", status: http.StatusNotFound, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			respondError(rec, tt.status, "Failed to fetch job", tt.err)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			var body ErrorResponse
+			if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+				t.Fatal(err)
+			}
+			if body.Status != tt.wantStatus || body.Error != http.StatusText(tt.wantStatus) || body.Message != "Failed to fetch job" {
+				t.Fatalf("body = %+v, want status %d", body, tt.wantStatus)
+			}
+			if tt.err == nil && body.Details != nil || tt.err != nil && body.Details != tt.err.Error() {
+				t.Fatalf("details = %v, want %v", body.Details, tt.err)
+			}
+		})
+	}
+}
+
+func TestRespondError_FromHubHRMS(t *testing.T) {
+	// Through a handler, as a failed Hub-HRMS call reaches it
+	h, _ := newTestJobHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		return &gateway.GraphQLResponse{Errors: []gateway.GraphQLError{{
+			Message:    "Job job-9 not found",
+			Extensions: map[string]interface{}{"code": "NOT_FOUND"},
+		}}}
+	})
+	r := chi.NewRouter()
+	r.Get("/jobs/{id}", h.GetJob)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs/job-9", nil))
+	if rec.Code != http.StatusNotFound || !strings.Contains(rec.Body.String(), "Job job-9 not found") {
+		t.Fatalf("response = %d %s, want 404 with the Hub-HRMS message", rec.Code, rec.Body)
+	}
+}
@@ -1,23 +1,91 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"log/slog"
 	"net/http"
+	"net/url"
+	"sort"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 
 	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/services"
+	"hr-recruiting/internal/util"
 )
 
 // JobHandler handles job-related requests
 type JobHandler struct {
-	client *gateway.HubHRMSClient
+	client       *gateway.HubHRMSClient
+	biasDetector *services.BiasDetector
+	quality      *services.JobQualityScorer
+	salaries     *services.SalaryValidator
+	webhooks     *services.WebhookService
+	rejections   *services.RuleEngine
+	site         SiteInfo
+	logger       *slog.Logger
+
+	suggestMu    sync.Mutex
+	suggestCache map[string]cachedSuggestions
+}
+
+// SiteInfo describes the public careers site that links and structured data
+// point at
+type SiteInfo struct {
+	BaseURL        string
+	CompanyName    string
+	CompanyLogoURL string
+}
+
+// JobSuggestion is a search autocomplete suggestion
+type JobSuggestion struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// cachedSuggestions holds suggestions for one prefix until expiresAt
+type cachedSuggestions struct {
+	suggestions []JobSuggestion
+	expiresAt   time.Time
 }
 
+const (
+	maxSuggestions     = 10
+	suggestTimeout     = 100 * time.Millisecond
+	suggestionCacheTTL = 5 * time.Minute
+)
+
 // NewJobHandler creates a new job handler
-func NewJobHandler(client *gateway.HubHRMSClient) *JobHandler {
-	return &JobHandler{client: client}
+func NewJobHandler(
+	client *gateway.HubHRMSClient,
+	biasDetector *services.BiasDetector,
+	quality *services.JobQualityScorer,
+	salaries *services.SalaryValidator,
+	webhooks *services.WebhookService,
+	site SiteInfo,
+	logger *slog.Logger,
+) *JobHandler {
+	site.BaseURL = strings.TrimSuffix(site.BaseURL, "/")
+
+	return &JobHandler{
+		client:       client,
+		biasDetector: biasDetector,
+		quality:      quality,
+		salaries:     salaries,
+		webhooks:     webhooks,
+		rejections:   services.NewRuleEngine(client),
+		site:         site,
+		logger:       logger,
+		suggestCache: make(map[string]cachedSuggestions),
+	}
 }
 
 // ListJobs returns a list of jobs
@@ -32,8 +100,6 @@ func (h *JobHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
 	experienceLevel := r.URL.Query().Get("experienceLevel")
 	remoteStr := r.URL.Query().Get("remote")
 	status := r.URL.Query().Get("status")
-	limitStr := r.URL.Query().Get("limit")
-	offsetStr := r.URL.Query().Get("offset")
 
 	// Build filters
 	filters := make(map[string]interface{})
@@ -65,38 +131,84 @@ func (h *JobHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
 
 	// Parse pagination
 	limit := 20
-	offset := 0
-	if limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 && l <= 100 {
-			limit = l
-		}
-	}
-	if offsetStr != "" {
-		if o, err := strconv.Atoi(offsetStr); err == nil && o >= 0 {
-			offset = o
-		}
+	if l, err := strconv.Atoi(r.URL.Query().Get("limit")); err == nil && l > 0 && l <= 100 {
+		limit = l
 	}
 
-	// Build variables
+	// Build variables; one extra row tells us whether another page exists
 	variables := map[string]interface{}{
-		"limit":  limit,
-		"offset": offset,
+		"first": limit + 1,
+	}
+	if cursor := r.URL.Query().Get("cursor"); cursor != "" {
+		after, err := decodeJobCursor(cursor)
+		if err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid pagination cursor", err)
+			return
+		}
+		variables["after"] = after
 	}
 	if len(filters) > 0 {
 		variables["filters"] = filters
 	}
 
 	// Execute query
-	resp, err := h.client.Query(ctx, gateway.GetJobsQuery, variables)
+	resp, err := h.client.Query(ctx, gateway.GetJobsPageQuery, variables)
 	if err != nil {
 		respondError(w, http.StatusInternalServerError, "Failed to fetch jobs", err)
 		return
 	}
 
-	// Add total count header if available
-	w.Header().Set("X-Total-Count", strconv.Itoa(len(resp.Data.(map[string]interface{})["jobs"].([]interface{}))))
+	items, _ := lookup(resp.Data, "jobs").([]interface{})
+	if items == nil {
+		items = []interface{}{}
+	}
 
-	respondJSON(w, http.StatusOK, resp.Data)
+	var nextCursor string
+	if len(items) > limit {
+		items = items[:limit]
+		last := items[len(items)-1]
+		nextCursor = util.EncodeCursor(lookupString(last, "createdAt") + ":" + lookupString(last, "id"))
+	}
+
+	page := PaginatedResponse{
+		Items:      items,
+		Limit:      limit,
+		HasMore:    nextCursor != "",
+		NextCursor: nextCursor,
+	}
+
+	// The total needs a separate count query, so it is only run on request
+	if r.URL.Query().Get("includeTotal") == "true" {
+		countResp, err := h.client.Query(ctx, gateway.CountJobsQuery, map[string]interface{}{"filters": filters})
+		if err != nil {
+			respondError(w, http.StatusInternalServerError, "Failed to count jobs", err)
+			return
+		}
+		page.Total = extractCount(countResp.Data, "jobsCount")
+		w.Header().Set("X-Total-Count", strconv.Itoa(page.Total))
+	}
+
+	respondJSON(w, http.StatusOK, page)
+}
+
+// decodeJobCursor verifies a job cursor and returns the "{createdAt}:{id}"
+// keyset position it wraps
+func decodeJobCursor(cursor string) (string, error) {
+	value, err := util.DecodeCursor(cursor)
+	if err != nil {
+		return "", err
+	}
+
+	// createdAt is RFC3339 and contains colons itself, so split on the last one
+	sep := strings.LastIndex(value, ":")
+	if sep < 0 || sep == len(value)-1 {
+		return "", util.ErrInvalidCursor
+	}
+	createdAt := value[:sep]
+	if _, err := time.Parse(time.RFC3339, createdAt); err != nil {
+		return "", util.ErrInvalidCursor
+	}
+	return value, nil
 }
 
 // GetJob returns a single job by ID
@@ -119,15 +231,308 @@ func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if resp.Data == nil {
+	job := lookup(resp.Data, "job")
+	if job == nil {
 		respondError(w, http.StatusNotFound, "Job not found", nil)
 		return
 	}
 
+	applyVariant(job, r.URL.Query().Get("variant"))
+	h.addCanonicalURL(job)
 	respondJSON(w, http.StatusOK, resp.Data)
 }
 
-// CreateJob creates a new job posting
+// GetQualityScore rates how complete and specific a job posting is
+func (h *JobHandler) GetQualityScore(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID := chi.URLParam(r, "id")
+
+	resp, err := h.client.Query(ctx, gateway.GetJobQuery, map[string]interface{}{
+		"id": jobID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch job", err)
+		return
+	}
+
+	job := lookup(resp.Data, "job")
+	if job == nil {
+		respondError(w, http.StatusNotFound, "Job not found", nil)
+		return
+	}
+
+	report, err := h.quality.Score(job)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to score job", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, report)
+}
+
+// GetReadability scores how easy a job's description and requirements are to
+// read and suggests where to simplify them
+func (h *JobHandler) GetReadability(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID := chi.URLParam(r, "id")
+
+	resp, err := h.client.Query(ctx, gateway.GetJobQuery, map[string]interface{}{
+		"id": jobID,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch job", err)
+		return
+	}
+
+	job := lookup(resp.Data, "job")
+	if job == nil {
+		respondError(w, http.StatusNotFound, "Job not found", nil)
+		return
+	}
+
+	sections := []string{lookupString(job, "description")}
+	requirements, _ := lookup(job, "requirements").([]interface{})
+	for _, requirement := range requirements {
+		if text, ok := requirement.(string); ok {
+			sections = append(sections, text)
+		}
+	}
+
+	respondJSON(w, http.StatusOK, services.AnalyzeReadability(strings.Join(sections, "\n")))
+}
+
+// GetRejectionRules returns the rules that reject a job's applications on
+// submission
+func (h *JobHandler) GetRejectionRules(w http.ResponseWriter, r *http.Request) {
+	rules, err := h.rejections.Rules(r.Context(), chi.URLParam(r, "id"))
+	if errors.Is(err, services.ErrJobNotFound) {
+		respondError(w, http.StatusNotFound, "Job not found", nil)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch rejection rules", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"rules": rules})
+}
+
+// UpdateRejectionRules replaces a job's auto-rejection rules. Applications
+// already submitted are not re-evaluated.
+func (h *JobHandler) UpdateRejectionRules(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Rules []services.AutoRejectionRule `json:"rules"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if input.Rules == nil {
+		input.Rules = []services.AutoRejectionRule{}
+	}
+	rules, err := h.rejections.UpdateRules(r.Context(), chi.URLParam(r, "id"), input.Rules)
+	if errors.Is(err, services.ErrInvalidRejectionRule) {
+		respondError(w, http.StatusBadRequest, "Invalid rejection rules", err)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update rejection rules", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{"rules": rules})
+}
+
+// GetJobBySlug returns a single job by its URL slug
+func (h *JobHandler) GetJobBySlug(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	slug := chi.URLParam(r, "slug")
+
+	if slug == "" {
+		respondError(w, http.StatusBadRequest, "Job slug is required", nil)
+		return
+	}
+
+	resp, err := h.client.Query(ctx, gateway.GetJobBySlugQuery, map[string]interface{}{
+		"slug": slug,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch job", err)
+		return
+	}
+
+	job := lookup(resp.Data, "jobBySlug")
+	if job == nil {
+		respondError(w, http.StatusNotFound, "Job not found", nil)
+		return
+	}
+
+	applyVariant(job, r.URL.Query().Get("variant"))
+	h.addCanonicalURL(job)
+	respondJSON(w, http.StatusOK, map[string]interface{}{"job": job})
+}
+
+// applyVariant serves variant B's title and description in place of the
+// job's own when variant is "b" and the job is under an A/B test, and records
+// which variant was served. Visitors never see both variants.
+func applyVariant(job interface{}, variant string) {
+	record, ok := job.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	variantB, _ := record["variantB"].(map[string]interface{})
+	delete(record, "variantB")
+	if variantB == nil {
+		return
+	}
+
+	if variant != "b" {
+		record["variant"] = "a"
+		return
+	}
+	for _, field := range []string{"title", "description"} {
+		if value := lookupString(variantB, field); value != "" {
+			record[field] = value
+		}
+	}
+	record["variant"] = "b"
+}
+
+// CreateABTest starts an A/B test of a job's title and description. The
+// job's current copy is variant A.
+func (h *JobHandler) CreateABTest(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID := chi.URLParam(r, "id")
+
+	var input struct {
+		VariantBTitle       string `json:"variantBTitle"`
+		VariantBDescription string `json:"variantBDescription"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if strings.TrimSpace(input.VariantBTitle) == "" || strings.TrimSpace(input.VariantBDescription) == "" {
+		respondError(w, http.StatusBadRequest, "variantBTitle and variantBDescription are required", nil)
+		return
+	}
+
+	resp, err := h.client.Mutate(ctx, gateway.CreateJobVariantMutation, map[string]interface{}{
+		"jobId": jobID,
+		"input": map[string]interface{}{
+			"title":       input.VariantBTitle,
+			"description": input.VariantBDescription,
+		},
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create job variant", err)
+		return
+	}
+
+	// Flag biased language; warnings are advisory and never block the response
+	warnings := h.biasDetector.Check(input.VariantBTitle + "\n" + input.VariantBDescription)
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"createJobVariant": lookup(resp.Data, "createJobVariant"),
+		"biasWarnings":     warnings,
+	})
+}
+
+// UpdateJobSlug replaces the slug generated when the job was created
+func (h *JobHandler) UpdateJobSlug(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID := chi.URLParam(r, "id")
+
+	var input struct {
+		Slug string `json:"slug"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if strings.TrimSpace(input.Slug) == "" {
+		respondError(w, http.StatusBadRequest, "Slug is required", nil)
+		return
+	}
+	slug := services.GenerateSlug(input.Slug)
+
+	resp, err := h.client.Mutate(ctx, gateway.UpdateJobSlugMutation, map[string]interface{}{
+		"id":   jobID,
+		"slug": slug,
+	})
+	var gwErr *gateway.GatewayError
+	if errors.As(err, &gwErr) && gwErr.Class == gateway.ErrorValidation {
+		// Hub-HRMS rejects a slug another job has as invalid input
+		respondError(w, http.StatusConflict, "Slug could not be set: "+gwErr.Message, nil)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update job slug", err)
+		return
+	}
+	if len(resp.Errors) > 0 {
+		respondError(w, http.StatusConflict, "Slug could not be set: "+resp.Errors[0].Message, nil)
+		return
+	}
+
+	job := lookup(resp.Data, "updateJobSlug")
+	if job == nil {
+		respondError(w, http.StatusNotFound, "Job not found", nil)
+		return
+	}
+
+	h.addCanonicalURL(job)
+	respondJSON(w, http.StatusOK, resp.Data)
+}
+
+// assignSlug gives a newly created job a slug from its title, falling back to
+// one suffixed with the job ID when the title's slug is taken
+func (h *JobHandler) assignSlug(ctx context.Context, job map[string]interface{}) {
+	jobID := lookupString(job, "id")
+	slug := services.GenerateSlug(lookupString(job, "title"))
+
+	for _, candidate := range []string{slug, slug + "-" + strings.ToLower(jobID)} {
+		resp, err := h.client.Mutate(ctx, gateway.UpdateJobSlugMutation, map[string]interface{}{
+			"id":   jobID,
+			"slug": candidate,
+		})
+		if err == nil && len(resp.Errors) == 0 {
+			job["slug"] = candidate
+			return
+		}
+		if err == nil {
+			err = errors.New(resp.Errors[0].Message)
+		}
+		h.logger.Warn("failed to set job slug", "slug", candidate, "job_id", jobID, "error", err)
+	}
+}
+
+// addCanonicalURL adds the job's public URL on the careers site, preferring
+// its slug over its ID
+func (h *JobHandler) addCanonicalURL(job interface{}) {
+	record, ok := job.(map[string]interface{})
+	if !ok {
+		return
+	}
+
+	path := lookupString(record, "slug")
+	if path == "" {
+		path = lookupString(record, "id")
+	}
+	if path != "" {
+		record["canonicalUrl"] = h.site.BaseURL + "/jobs/" + url.PathEscape(path)
+	}
+}
+
+// CreateJob creates a new job posting. The response holds the job and the
+// existing postings that may be the same role, and is 201 either way.
 func (h *JobHandler) CreateJob(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
 
@@ -147,6 +552,9 @@ func (h *JobHandler) CreateJob(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	// Check before creating, so the new job is not its own duplicate
+	duplicates := h.duplicateJobs(ctx, input)
+
 	variables := map[string]interface{}{
 		"input": input,
 	}
@@ -157,7 +565,145 @@ func (h *JobHandler) CreateJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, resp.Data)
+	job, ok := lookup(resp.Data, "createJob").(map[string]interface{})
+	if ok && lookupString(job, "slug") == "" {
+		h.assignSlug(ctx, job)
+		h.addCanonicalURL(job)
+	}
+
+	// Possible duplicates do not block the posting; the recruiter is told
+	// about them and decides whether to close one
+	if duplicates == nil {
+		duplicates = []services.DuplicateCandidate{}
+	}
+	body := map[string]interface{}{
+		"job":               job,
+		"duplicateWarnings": duplicates,
+	}
+	addSalaryWarnings(body, h.salaryWarnings(ctx, "", input))
+	respondJSON(w, http.StatusCreated, body)
+}
+
+// duplicateJobStatuses are the statuses of jobs a new posting may duplicate
+var duplicateJobStatuses = []string{"PUBLISHED", "DRAFT"}
+
+// duplicateJobs returns the published and draft jobs in the new job's
+// department that look like the same role. A failed lookup is logged and
+// treated as no duplicates.
+func (h *JobHandler) duplicateJobs(ctx context.Context, input map[string]interface{}) []services.DuplicateCandidate {
+	newJob := services.JobInput{
+		Title:      lookupString(input, "title"),
+		Department: lookupString(input, "department"),
+	}
+	if newJob.Title == "" || newJob.Department == "" {
+		return nil
+	}
+
+	var existing []services.Job
+	for _, status := range duplicateJobStatuses {
+		resp, err := h.client.Query(ctx, gateway.GetJobsQuery, map[string]interface{}{
+			"filters": map[string]interface{}{
+				"departments": []string{newJob.Department},
+				"status":      status,
+			},
+			"limit":  100,
+			"offset": 0,
+		})
+		if err != nil {
+			h.logger.Warn("failed to fetch jobs for duplicate detection", "status", status, "error", err)
+			return nil
+		}
+
+		jobs, _ := lookup(resp.Data, "jobs").([]interface{})
+		for _, job := range jobs {
+			existing = append(existing, services.Job{
+				ID:         lookupString(job, "id"),
+				Title:      lookupString(job, "title"),
+				Department: lookupString(job, "department"),
+			})
+		}
+	}
+
+	return services.DetectDuplicates(newJob, existing)
+}
+
+// salaryWarnings checks the salary range in a create or update body against
+// market benchmarks. It returns nil when the body has no salary range. Title
+// and experience level missing from an update are read from the job.
+func (h *JobHandler) salaryWarnings(ctx context.Context, jobID string, input map[string]interface{}) []string {
+	salary, ok := input["salaryRange"].(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	minSalary, _ := salary["min"].(float64)
+	maxSalary, _ := salary["max"].(float64)
+	salaryRange := services.SalaryRange{Min: minSalary, Max: maxSalary, Currency: lookupString(salary, "currency")}
+
+	title := lookupString(input, "title")
+	experienceLevel := lookupString(input, "experienceLevel")
+	if jobID != "" && (title == "" || experienceLevel == "") {
+		resp, err := h.client.Query(ctx, gateway.GetJobQuery, map[string]interface{}{"id": jobID})
+		if err != nil {
+			h.logger.Warn("failed to fetch job for salary validation", "job_id", jobID, "error", err)
+			return []string{}
+		}
+		title = firstNonEmpty(title, lookupString(resp.Data, "job", "title"))
+		experienceLevel = firstNonEmpty(experienceLevel, lookupString(resp.Data, "job", "experienceLevel"))
+	}
+
+	return h.salaries.Validate(title, experienceLevel, salaryRange)
+}
+
+// addSalaryWarnings adds salary warnings to a mutation response. The
+// warnings are advisory and never block the change.
+func addSalaryWarnings(data interface{}, warnings []string) {
+	if fields, ok := data.(map[string]interface{}); ok && warnings != nil {
+		fields["warnings"] = warnings
+	}
+}
+
+// cloneExcludedFields are job fields owned by Hub-HRMS or specific to the
+// original posting, so they are not copied to a clone
+var cloneExcludedFields = []string{
+	"id", "slug", "status", "postedDate", "closingDate", "applicationCount", "viewCount",
+	"createdBy", "createdAt", "updatedAt", "variantB",
+}
+
+// CloneJob creates a new draft job from an existing one
+func (h *JobHandler) CloneJob(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID := chi.URLParam(r, "id")
+
+	resp, err := h.client.Query(ctx, gateway.GetJobQuery, map[string]interface{}{"id": jobID})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch job", err)
+		return
+	}
+
+	source, ok := lookup(resp.Data, "job").(map[string]interface{})
+	if !ok {
+		respondError(w, http.StatusNotFound, "Job not found", nil)
+		return
+	}
+
+	input := make(map[string]interface{}, len(source))
+	for field, value := range source {
+		input[field] = value
+	}
+	for _, field := range cloneExcludedFields {
+		delete(input, field)
+	}
+	input["title"] = lookupString(source, "title") + " (Copy)"
+
+	created, err := h.client.Mutate(ctx, gateway.CreateJobMutation, map[string]interface{}{
+		"input": input,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to clone job", err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, created.Data)
 }
 
 // UpdateJob updates an existing job
@@ -187,6 +733,7 @@ func (h *JobHandler) UpdateJob(w http.ResponseWriter, r *http.Request) {
 		respondError(w, http.StatusInternalServerError, "Failed to update job", err)
 		return
 	}
+	addSalaryWarnings(resp.Data, h.salaryWarnings(ctx, jobID, input))
 
 	respondJSON(w, http.StatusOK, resp.Data)
 }
@@ -211,6 +758,11 @@ func (h *JobHandler) PublishJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.webhooks.Publish(services.EventJobPublished, services.JobEventData{
+		JobID:  jobID,
+		Status: lookupString(resp.Data, "publishJob", "status"),
+	})
+
 	respondJSON(w, http.StatusOK, resp.Data)
 }
 
@@ -234,6 +786,11 @@ func (h *JobHandler) CloseJob(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	h.webhooks.Publish(services.EventJobClosed, services.JobEventData{
+		JobID:  jobID,
+		Status: lookupString(resp.Data, "closeJob", "status"),
+	})
+
 	respondJSON(w, http.StatusOK, resp.Data)
 }
 
@@ -277,6 +834,10 @@ func (h *JobHandler) IncrementView(w http.ResponseWriter, r *http.Request) {
 	variables := map[string]interface{}{
 		"id": jobID,
 	}
+	// Views of jobs under an A/B test are counted per variant
+	if variant := r.URL.Query().Get("variant"); variant == "a" || variant == "b" {
+		variables["variant"] = variant
+	}
 
 	resp, err := h.client.Mutate(ctx, gateway.IncrementJobViewMutation, variables)
 	if err != nil {
@@ -321,5 +882,377 @@ func (h *JobHandler) GenerateDescription(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	respondJSON(w, http.StatusOK, resp.Data)
-}
\ No newline at end of file
+	// Flag biased language; warnings are advisory and never block the response
+	warnings := h.biasDetector.Check(lookupString(resp.Data, "generateJobDescription", "description"))
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"generateJobDescription": lookup(resp.Data, "generateJobDescription"),
+		"biasWarnings":           warnings,
+	})
+}
+
+// SuggestJobs returns autocomplete suggestions for the careers search box
+func (h *JobHandler) SuggestJobs(w http.ResponseWriter, r *http.Request) {
+	prefix := strings.ToLower(strings.TrimSpace(r.URL.Query().Get("q")))
+	if prefix == "" {
+		respondJSON(w, http.StatusOK, []JobSuggestion{})
+		return
+	}
+
+	if suggestions, ok := h.cachedSuggestions(prefix); ok {
+		respondJSON(w, http.StatusOK, suggestions)
+		return
+	}
+
+	// Never hold up typing; an empty list is better than a slow one
+	ctx, cancel := context.WithTimeout(r.Context(), suggestTimeout)
+	defer cancel()
+
+	variables := map[string]interface{}{
+		"filters": map[string]interface{}{
+			"query":  prefix,
+			"status": "PUBLISHED",
+		},
+		"limit":  100,
+		"offset": 0,
+	}
+
+	resp, err := h.client.Query(ctx, gateway.GetJobsQuery, variables)
+	if err != nil {
+		respondJSON(w, http.StatusOK, []JobSuggestion{})
+		return
+	}
+
+	jobs, _ := lookup(resp.Data, "jobs").([]interface{})
+	suggestions := buildSuggestions(jobs, prefix)
+
+	h.suggestMu.Lock()
+	h.suggestCache[prefix] = cachedSuggestions{
+		suggestions: suggestions,
+		expiresAt:   time.Now().Add(suggestionCacheTTL),
+	}
+	h.suggestMu.Unlock()
+
+	respondJSON(w, http.StatusOK, suggestions)
+}
+
+// cachedSuggestions returns unexpired suggestions for prefix
+func (h *JobHandler) cachedSuggestions(prefix string) ([]JobSuggestion, bool) {
+	h.suggestMu.Lock()
+	defer h.suggestMu.Unlock()
+
+	entry, ok := h.suggestCache[prefix]
+	if !ok {
+		return nil, false
+	}
+	if time.Now().After(entry.expiresAt) {
+		delete(h.suggestCache, prefix)
+		return nil, false
+	}
+	return entry.suggestions, true
+}
+
+// buildSuggestions counts job titles and departments starting with prefix and
+// returns the most common ones
+func buildSuggestions(jobs []interface{}, prefix string) []JobSuggestion {
+	counts := make(map[JobSuggestion]int)
+	for _, job := range jobs {
+		for _, field := range []string{"title", "department"} {
+			value := lookupString(job, field)
+			if value != "" && strings.HasPrefix(strings.ToLower(value), prefix) {
+				counts[JobSuggestion{Type: field, Value: value}]++
+			}
+		}
+	}
+
+	suggestions := make([]JobSuggestion, 0, len(counts))
+	for suggestion, count := range counts {
+		suggestion.Count = count
+		suggestions = append(suggestions, suggestion)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if suggestions[i].Count != suggestions[j].Count {
+			return suggestions[i].Count > suggestions[j].Count
+		}
+		return suggestions[i].Value < suggestions[j].Value
+	})
+
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions
+}
+
+const (
+	feedMaxEntries   = 500
+	feedSummaryRunes = 300
+)
+
+// atomFeed is an Atom 1.0 feed document
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Links   []atomLink  `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title    string        `xml:"title"`
+	ID       string        `xml:"id"`
+	Link     atomLink      `xml:"link"`
+	Updated  string        `xml:"updated"`
+	Summary  string        `xml:"summary"`
+	Category *atomCategory `xml:"category,omitempty"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+// JobFeed serves published jobs as an Atom feed for job aggregators
+func (h *JobHandler) JobFeed(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	variables := map[string]interface{}{
+		"filters": map[string]interface{}{"status": "PUBLISHED"},
+		"limit":   feedMaxEntries,
+		"offset":  0,
+	}
+
+	resp, err := h.client.Query(ctx, gateway.GetJobsQuery, variables)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch jobs", err)
+		return
+	}
+
+	jobs, _ := lookup(resp.Data, "jobs").([]interface{})
+
+	feed := atomFeed{
+		Title: "Open Positions",
+		ID:    h.site.BaseURL + "/jobs",
+		Links: []atomLink{
+			{Href: h.site.BaseURL + "/api/v1/jobs/feed.xml", Rel: "self"},
+			{Href: h.site.BaseURL + "/jobs", Rel: "alternate"},
+		},
+		Entries: make([]atomEntry, 0, len(jobs)),
+	}
+
+	var latest time.Time
+	for _, job := range jobs {
+		link := h.site.BaseURL + "/jobs/" + lookupString(job, "id")
+		updated := parseTimestamp(lookupString(job, "updatedAt"))
+		if updated.After(latest) {
+			latest = updated
+		}
+
+		entry := atomEntry{
+			Title:   lookupString(job, "title"),
+			ID:      link,
+			Link:    atomLink{Href: link},
+			Updated: updated.Format(time.RFC3339),
+			Summary: truncateRunes(lookupString(job, "description"), feedSummaryRunes),
+		}
+		if department := lookupString(job, "department"); department != "" {
+			entry.Category = &atomCategory{Term: department}
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	if latest.IsZero() {
+		latest = time.Now().UTC()
+	}
+	feed.Updated = latest.Format(time.RFC3339)
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(feed); err != nil {
+		h.logger.Error("failed to encode job feed", "error", err)
+	}
+}
+
+// parseTimestamp parses an RFC3339 timestamp, falling back to the current
+// time when it is missing or malformed
+func parseTimestamp(value string) time.Time {
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Now().UTC()
+	}
+	return t
+}
+
+// truncateRunes shortens text to at most n runes, marking the cut with an
+// ellipsis
+func truncateRunes(text string, n int) string {
+	runes := []rune(strings.TrimSpace(text))
+	if len(runes) <= n {
+		return string(runes)
+	}
+	return strings.TrimSpace(string(runes[:n-1])) + "…"
+}
+
+// jobPosting is a schema.org JobPosting in JSON-LD form
+type jobPosting struct {
+	Context                       string          `json:"@context"`
+	Type                          string          `json:"@type"`
+	Title                         string          `json:"title"`
+	Description                   string          `json:"description"`
+	Identifier                    *propertyValue  `json:"identifier,omitempty"`
+	URL                           string          `json:"url"`
+	DatePosted                    string          `json:"datePosted,omitempty"`
+	ValidThrough                  string          `json:"validThrough,omitempty"`
+	EmploymentType                string          `json:"employmentType,omitempty"`
+	HiringOrganization            organization    `json:"hiringOrganization"`
+	JobLocation                   *place          `json:"jobLocation,omitempty"`
+	JobLocationType               string          `json:"jobLocationType,omitempty"`
+	ApplicantLocationRequirements *administrative `json:"applicantLocationRequirements,omitempty"`
+	BaseSalary                    *monetaryAmount `json:"baseSalary,omitempty"`
+}
+
+type propertyValue struct {
+	Type  string `json:"@type"`
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type organization struct {
+	Type   string `json:"@type"`
+	Name   string `json:"name"`
+	SameAs string `json:"sameAs,omitempty"`
+	Logo   string `json:"logo,omitempty"`
+}
+
+type place struct {
+	Type    string        `json:"@type"`
+	Address postalAddress `json:"address"`
+}
+
+type postalAddress struct {
+	Type            string `json:"@type"`
+	AddressLocality string `json:"addressLocality"`
+}
+
+type administrative struct {
+	Type string `json:"@type"`
+	Name string `json:"name"`
+}
+
+type monetaryAmount struct {
+	Type     string            `json:"@type"`
+	Currency string            `json:"currency"`
+	Value    quantitativeValue `json:"value"`
+}
+
+type quantitativeValue struct {
+	Type     string  `json:"@type"`
+	MinValue float64 `json:"minValue,omitempty"`
+	MaxValue float64 `json:"maxValue,omitempty"`
+	UnitText string  `json:"unitText"`
+}
+
+// schemaEmploymentTypes maps our employment types to schema.org values
+var schemaEmploymentTypes = map[string]string{
+	"full-time":  "FULL_TIME",
+	"part-time":  "PART_TIME",
+	"contract":   "CONTRACTOR",
+	"temporary":  "TEMPORARY",
+	"internship": "INTERN",
+	"volunteer":  "VOLUNTEER",
+}
+
+// JobSchema returns a job as schema.org JobPosting structured data for
+// search engine indexing
+func (h *JobHandler) JobSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	jobID := chi.URLParam(r, "id")
+
+	resp, err := h.client.Query(ctx, gateway.GetJobQuery, map[string]interface{}{"id": jobID})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch job", err)
+		return
+	}
+
+	job := lookup(resp.Data, "job")
+	if job == nil {
+		respondError(w, http.StatusNotFound, "Job not found", nil)
+		return
+	}
+
+	posting := jobPosting{
+		Context:     "https://schema.org/",
+		Type:        "JobPosting",
+		Title:       lookupString(job, "title"),
+		Description: lookupString(job, "description"),
+		Identifier: &propertyValue{
+			Type:  "PropertyValue",
+			Name:  h.site.CompanyName,
+			Value: jobID,
+		},
+		URL:          h.site.BaseURL + "/jobs/" + jobID,
+		DatePosted:   lookupString(job, "postedDate"),
+		ValidThrough: lookupString(job, "closingDate"),
+		HiringOrganization: organization{
+			Type:   "Organization",
+			Name:   h.site.CompanyName,
+			SameAs: h.site.BaseURL,
+			Logo:   h.site.CompanyLogoURL,
+		},
+	}
+
+	employmentType := strings.ReplaceAll(strings.ToLower(lookupString(job, "employmentType")), "_", "-")
+	if schemaType, ok := schemaEmploymentTypes[employmentType]; ok {
+		posting.EmploymentType = schemaType
+	} else if employmentType != "" {
+		posting.EmploymentType = "OTHER"
+	}
+
+	location := lookupString(job, "location")
+	if location != "" {
+		posting.JobLocation = &place{
+			Type:    "Place",
+			Address: postalAddress{Type: "PostalAddress", AddressLocality: location},
+		}
+	}
+
+	if remote, _ := lookup(job, "remoteWork").(bool); remote {
+		posting.JobLocationType = "TELECOMMUTE"
+		posting.ApplicantLocationRequirements = &administrative{Type: "AdministrativeArea", Name: location}
+		if location == "" {
+			posting.ApplicantLocationRequirements.Name = "Worldwide"
+		}
+	}
+
+	if salary, ok := lookup(job, "salaryRange").(map[string]interface{}); ok {
+		min, _ := salary["min"].(float64)
+		max, _ := salary["max"].(float64)
+		currency, _ := salary["currency"].(string)
+		if min > 0 || max > 0 {
+			posting.BaseSalary = &monetaryAmount{
+				Type:     "MonetaryAmount",
+				Currency: currency,
+				Value: quantitativeValue{
+					Type:     "QuantitativeValue",
+					MinValue: min,
+					MaxValue: max,
+					UnitText: "YEAR",
+				},
+			}
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/ld+json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(posting); err != nil {
+		h.logger.Error("failed to encode job schema", "error", err)
+	}
+}
@@ -0,0 +1,1086 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/middleware"
+	"hr-recruiting/internal/services"
+	"hr-recruiting/internal/util"
+)
+
+// newTestJobHandler returns a JobHandler talking to a fake Hub-HRMS that
+// answers with respond
+func newTestJobHandler(t *testing.T, respond func(gateway.GraphQLRequest) interface{}) (*JobHandler, *fakeHubHRMS) {
+	t.Helper()
+	fake, client := newFakeHubHRMS(t, respond)
+	h := NewJobHandler(
+		client,
+		services.NewBiasDetector(),
+		services.NewJobQualityScorer(),
+		services.NewSalaryValidator(nil),
+		services.NewWebhookService(func() bool { return false }),
+		SiteInfo{BaseURL: "https://careers.example.com/"},
+		slog.Default(),
+	)
+	return h, fake
+}
+
+// createJobFake creates jobs as job-new and lists existing as the published
+// jobs in every department
+func createJobFake(existing ...map[string]interface{}) func(gateway.GraphQLRequest) interface{} {
+	return func(req gateway.GraphQLRequest) interface{} {
+		switch req.Query {
+		case gateway.GetJobsQuery:
+			filters, _ := req.Variables["filters"].(map[string]interface{})
+			jobs := []interface{}{}
+			if filters["status"] == "PUBLISHED" {
+				for _, job := range existing {
+					jobs = append(jobs, job)
+				}
+			}
+			return map[string]interface{}{"jobs": jobs}
+		case gateway.CreateJobMutation:
+			input, _ := req.Variables["input"].(map[string]interface{})
+			return map[string]interface{}{"createJob": map[string]interface{}{
+				"id": "job-new", "title": input["title"], "department": input["department"], "status": "DRAFT",
+			}}
+		case gateway.UpdateJobSlugMutation:
+			return map[string]interface{}{"updateJob": map[string]interface{}{"id": req.Variables["id"], "slug": req.Variables["slug"]}}
+		}
+		return map[string]interface{}{}
+	}
+}
+
+// testJob is a create body with every required field filled in
+func testJob(title string) string {
+	job, _ := json.Marshal(map[string]interface{}{
+		"title":           title,
+		"department":      "Engineering",
+		"location":        "London",
+		"employmentType":  "FULL_TIME",
+		"experienceLevel": "SENIOR",
+		"description":     "Build the services that match candidates to roles.",
+		"requirements":    []string{"Go"},
+		"skills":          []string{"Go"},
+	})
+	return string(job)
+}
+
+func TestJobHandler_CreateJob(t *testing.T) {
+	existing := map[string]interface{}{"id": "job-1", "title": "Senior Golang Engineer", "department": "Engineering"}
+
+	create := func(h *JobHandler, body string) (*httptest.ResponseRecorder, map[string]json.RawMessage) {
+		rec := httptest.NewRecorder()
+		h.CreateJob(rec, httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(body)))
+		var response map[string]json.RawMessage
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		return rec, response
+	}
+
+	t.Run("possible duplicate", func(t *testing.T) {
+		h, fake := newTestJobHandler(t, createJobFake(existing))
+		rec, response := create(h, testJob("Senior Go Engineer"))
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+		}
+		if fake.sent(gateway.CreateJobMutation) != 1 {
+			t.Fatal("job with a possible duplicate was not created")
+		}
+
+		var job map[string]interface{}
+		json.Unmarshal(response["job"], &job)
+		if job["id"] != "job-new" || job["slug"] != "senior-go-engineer" ||
+			job["canonicalUrl"] != "https://careers.example.com/jobs/senior-go-engineer" {
+			t.Fatalf("job = %v", job)
+		}
+		var warnings []services.DuplicateCandidate
+		json.Unmarshal(response["duplicateWarnings"], &warnings)
+		if len(warnings) != 1 || warnings[0].ID != "job-1" || warnings[0].Title != "Senior Golang Engineer" ||
+			warnings[0].Similarity <= 0.7 || warnings[0].Similarity >= 1 {
+			t.Fatalf("duplicateWarnings = %+v", warnings)
+		}
+	})
+
+	t.Run("no duplicates", func(t *testing.T) {
+		h, _ := newTestJobHandler(t, createJobFake(existing))
+		rec, response := create(h, testJob("Engineering Manager"))
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+		}
+		if string(response["duplicateWarnings"]) != "[]" || response["job"] == nil {
+			t.Fatalf("response = %s, want the job and an empty duplicateWarnings", rec.Body)
+		}
+	})
+
+	t.Run("missing field", func(t *testing.T) {
+		h, fake := newTestJobHandler(t, createJobFake(existing))
+		rec, _ := create(h, `{"title":"Senior Go Engineer","department":"Engineering"}`)
+		if rec.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+		if fake.sent(gateway.CreateJobMutation) != 0 {
+			t.Fatal("invalid job was created")
+		}
+	})
+}
+
+func TestJobHandler_GenerateDescription(t *testing.T) {
+	h, fake := newTestJobHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.GenerateJobDescriptionMutation {
+			return map[string]interface{}{}
+		}
+		return map[string]interface{}{"generateJobDescription": map[string]interface{}{
+			"description": "We want a Go ninja who thrives in a competitive team.",
+		}}
+	})
+
+	generate := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.GenerateDescription(rec, httptest.NewRequest(http.MethodPost, "/jobs/generate-description", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := generate(`{"title":"Go Engineer","department":"Engineering","experienceLevel":"SENIOR","keySkills":["Go"]}`)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	var body struct {
+		GenerateJobDescription map[string]interface{} `json:"generateJobDescription"`
+		BiasWarnings           []services.BiasWarning `json:"biasWarnings"`
+	}
+	json.NewDecoder(rec.Body).Decode(&body)
+	if body.GenerateJobDescription["description"] == nil {
+		t.Fatalf("response is missing the description: %+v", body)
+	}
+	if len(body.BiasWarnings) != 2 || body.BiasWarnings[0].Term != "ninja" || body.BiasWarnings[1].Term != "competitive" {
+		t.Fatalf("biasWarnings = %+v", body.BiasWarnings)
+	}
+
+	if rec := generate(`{"title":"Go Engineer"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("missing fields: status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if sent := fake.sent(gateway.GenerateJobDescriptionMutation); sent != 1 {
+		t.Fatalf("generated %d descriptions, want 1", sent)
+	}
+}
+
+func TestJobHandler_SuggestJobs(t *testing.T) {
+	var jobs []interface{}
+	for _, title := range []string{"Software Engineer", "Software Engineer", "Solutions Architect", "Sales Manager"} {
+		jobs = append(jobs, map[string]interface{}{"title": title, "department": "Engineering"})
+	}
+	jobs = append(jobs, map[string]interface{}{"title": "Account Executive", "department": "Sales"})
+	for i := 0; i < 12; i++ {
+		jobs = append(jobs, map[string]interface{}{"title": fmt.Sprintf("Support Agent %02d", i), "department": "Support"})
+	}
+	h, fake := newTestJobHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		return map[string]interface{}{"jobs": jobs}
+	})
+
+	suggest := func(query string) []JobSuggestion {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.SuggestJobs(rec, httptest.NewRequest(http.MethodGet, "/api/v1/jobs/suggest?q="+url.QueryEscape(query), nil))
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+		}
+		var suggestions []JobSuggestion
+		if err := json.Unmarshal(rec.Body.Bytes(), &suggestions); err != nil {
+			t.Fatalf("body %s: %v", rec.Body, err)
+		}
+		return suggestions
+	}
+
+	got := suggest(" SO ")
+	want := []JobSuggestion{
+		{Type: "title", Value: "Software Engineer", Count: 2},
+		{Type: "title", Value: "Solutions Architect", Count: 1},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("suggestions for so = %+v, want %+v", got, want)
+	}
+
+	got = suggest("s")
+	if len(got) != maxSuggestions {
+		t.Fatalf("got %d suggestions, want at most %d", len(got), maxSuggestions)
+	}
+	if got[0] != (JobSuggestion{Type: "department", Value: "Support", Count: 12}) || got[1].Value != "Software Engineer" {
+		t.Fatalf("suggestions for s = %+v, want the most common first", got)
+	}
+
+	// Repeated prefixes are served from the cache
+	sent := fake.sent(gateway.GetJobsQuery)
+	suggest("so")
+	if fake.sent(gateway.GetJobsQuery) != sent {
+		t.Fatal("cached prefix was looked up again")
+	}
+	h.suggestCache["so"] = cachedSuggestions{expiresAt: time.Now().Add(-time.Second)}
+	if got := suggest("so"); len(got) != 2 || fake.sent(gateway.GetJobsQuery) != sent+1 {
+		t.Fatalf("expired prefix got %+v from the cache", got)
+	}
+
+	if got := suggest(""); len(got) != 0 || fake.sent(gateway.GetJobsQuery) != sent+1 {
+		t.Fatalf("empty query got %+v", got)
+	}
+}
+
+func TestJobHandler_SuggestJobs_Timeout(t *testing.T) {
+	h, fake := newTestJobHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		time.Sleep(300 * time.Millisecond)
+		return map[string]interface{}{"jobs": []interface{}{map[string]interface{}{"title": "Software Engineer"}}}
+	})
+
+	for i := 0; i < 2; i++ {
+		start := time.Now()
+		rec := httptest.NewRecorder()
+		h.SuggestJobs(rec, httptest.NewRequest(http.MethodGet, "/api/v1/jobs/suggest?q=soft", nil))
+		if elapsed := time.Since(start); elapsed > 250*time.Millisecond {
+			t.Fatalf("slow Hub-HRMS held the response for %v", elapsed)
+		}
+		if rec.Code != http.StatusOK || strings.TrimSpace(rec.Body.String()) != "[]" {
+			t.Fatalf("status = %d, body = %s; want an empty list", rec.Code, rec.Body)
+		}
+	}
+	// Empty lists from timeouts are not cached
+	if sent := fake.sent(gateway.GetJobsQuery); sent != 2 {
+		t.Fatalf("Hub-HRMS was asked %d times, want 2", sent)
+	}
+}
+
+func TestJobHandler_ListJobs_Cursor(t *testing.T) {
+	// Three published jobs, newest first, paged by the after keyset position
+	all := []map[string]interface{}{
+		{"id": "job-3", "title": "Data Engineer", "createdAt": "2026-10-03T09:00:00Z"},
+		{"id": "job-2", "title": "Designer", "createdAt": "2026-10-02T09:00:00Z"},
+		{"id": "job-1", "title": "Recruiter", "createdAt": "2026-10-01T09:00:00Z"},
+	}
+	h, fake := newTestJobHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query == gateway.CountJobsQuery {
+			return map[string]interface{}{"jobsCount": len(all)}
+		}
+		start := 0
+		if after, ok := req.Variables["after"].(string); ok {
+			for i, job := range all {
+				if job["createdAt"].(string)+":"+job["id"].(string) == after {
+					start = i + 1
+				}
+			}
+		}
+		end := min(start+int(req.Variables["first"].(float64)), len(all))
+		jobs := []interface{}{}
+		for _, job := range all[start:end] {
+			jobs = append(jobs, job)
+		}
+		return map[string]interface{}{"jobs": jobs}
+	})
+
+	list := func(query string) (*httptest.ResponseRecorder, PaginatedResponse) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		h.ListJobs(rec, httptest.NewRequest(http.MethodGet, "/api/v1/jobs?"+query, nil))
+		var page PaginatedResponse
+		json.Unmarshal(rec.Body.Bytes(), &page)
+		return rec, page
+	}
+	ids := func(page PaginatedResponse) string {
+		var ids []string
+		for _, item := range page.Items.([]interface{}) {
+			ids = append(ids, lookupString(item, "id"))
+		}
+		return strings.Join(ids, ",")
+	}
+
+	rec, first := list("limit=2")
+	if rec.Code != http.StatusOK || ids(first) != "job-3,job-2" || !first.HasMore || first.NextCursor == "" {
+		t.Fatalf("first page = %d %s", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("X-Total-Count") != "" || fake.sent(gateway.CountJobsQuery) != 0 {
+		t.Fatal("total was counted without includeTotal")
+	}
+
+	rec, second := list("limit=2&includeTotal=true&cursor=" + url.QueryEscape(first.NextCursor))
+	if rec.Code != http.StatusOK || ids(second) != "job-1" || second.HasMore || second.NextCursor != "" {
+		t.Fatalf("second page = %d %s", rec.Code, rec.Body)
+	}
+	if rec.Header().Get("X-Total-Count") != "3" || second.Total != 3 {
+		t.Fatalf("X-Total-Count = %q, total = %d; want 3", rec.Header().Get("X-Total-Count"), second.Total)
+	}
+
+	for _, cursor := range []string{
+		"not-a-cursor",
+		util.EncodeCursor("job-2"),
+		util.EncodeCursor("yesterday:job-2"),
+		util.EncodeCursor("2026-10-02T09:00:00Z:"),
+		first.NextCursor + "x",
+	} {
+		sent := fake.sent(gateway.GetJobsPageQuery)
+		if rec, _ := list("cursor=" + url.QueryEscape(cursor)); rec.Code != http.StatusBadRequest {
+			t.Errorf("cursor %q: status = %d, want 400", cursor, rec.Code)
+		}
+		if fake.sent(gateway.GetJobsPageQuery) != sent {
+			t.Errorf("cursor %q was sent to Hub-HRMS", cursor)
+		}
+	}
+}
+
+func TestJobHandler_JobFeed(t *testing.T) {
+	jobs := []interface{}{
+		map[string]interface{}{
+			"id": "job-1", "title": "Go & Rust <Engineer>", "department": "Engineering",
+			"description": strings.Repeat("é", 400), "updatedAt": "2026-10-02T09:00:00Z",
+		},
+		map[string]interface{}{"id": "job-2", "title": "Recruiter", "description": "Hire people.", "updatedAt": "2026-10-05T09:00:00Z"},
+		map[string]interface{}{"id": "job-3", "title": "Designer", "department": "Design", "updatedAt": "not a time"},
+	}
+	h, fake := newTestJobHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		return map[string]interface{}{"jobs": jobs}
+	})
+
+	rec := httptest.NewRecorder()
+	h.JobFeed(rec, httptest.NewRequest(http.MethodGet, "/api/v1/jobs/feed.xml", nil))
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/atom+xml; charset=utf-8" {
+		t.Fatalf("status = %d, content type = %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	var feed atomFeed
+	if err := xml.Unmarshal(rec.Body.Bytes(), &feed); err != nil {
+		t.Fatalf("feed is not well-formed XML: %v\n%s", err, rec.Body)
+	}
+	if len(feed.Entries) != 3 {
+		t.Fatalf("feed has %d entries, want 3", len(feed.Entries))
+	}
+	// job-3's malformed updatedAt falls back to now, making it the latest
+	if feed.Updated != feed.Entries[2].Updated || feed.ID != "https://careers.example.com/jobs" {
+		t.Fatalf("feed updated = %s, id = %s; want the latest entry's time", feed.Updated, feed.ID)
+	}
+
+	entry := feed.Entries[0]
+	if entry.Title != "Go & Rust <Engineer>" || entry.Link.Href != "https://careers.example.com/jobs/job-1" || entry.ID != entry.Link.Href {
+		t.Fatalf("entry = %+v", entry)
+	}
+	if runes := []rune(entry.Summary); len(runes) != feedSummaryRunes || runes[len(runes)-1] != '…' {
+		t.Fatalf("summary has %d runes, want it truncated to %d", len(runes), feedSummaryRunes)
+	}
+	if entry.Category == nil || entry.Category.Term != "Engineering" || entry.Updated != "2026-10-02T09:00:00Z" {
+		t.Fatalf("entry = %+v", entry)
+	}
+	if feed.Entries[1].Category != nil || feed.Entries[1].Summary != "Hire people." {
+		t.Fatalf("entry without a department = %+v", feed.Entries[1])
+	}
+	if _, err := time.Parse(time.RFC3339, feed.Entries[2].Updated); err != nil {
+		t.Fatalf("malformed updatedAt gave %q", feed.Entries[2].Updated)
+	}
+
+	fake.mu.Lock()
+	filters, _ := fake.requests[0].Variables["filters"].(map[string]interface{})
+	fake.mu.Unlock()
+	if filters["status"] != "PUBLISHED" {
+		t.Fatalf("filters = %v, want published jobs only", filters)
+	}
+}
+
+func TestJobHandler_JobSchema(t *testing.T) {
+	jobs := map[string]map[string]interface{}{
+		"job-1": {
+			"id": "job-1", "title": "Backend Engineer", "description": "<p>Build APIs.</p>",
+			"postedDate": "2026-10-01", "closingDate": "2026-11-01", "employmentType": "FULL_TIME",
+			"location": "London", "remoteWork": false,
+			"salaryRange": map[string]interface{}{"min": 60000.0, "max": 80000.0, "currency": "GBP"},
+		},
+		"job-2": {
+			"id": "job-2", "title": "Support Agent", "description": "Help customers.",
+			"postedDate": "2026-10-02", "employmentType": "SEASONAL", "remoteWork": true,
+		},
+	}
+	h, _ := newTestJobHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		if job, ok := jobs[req.Variables["id"].(string)]; ok {
+			return map[string]interface{}{"job": job}
+		}
+		return map[string]interface{}{"job": nil}
+	})
+	h.site.CompanyName = "Acme"
+	h.site.CompanyLogoURL = "https://careers.example.com/logo.png"
+
+	r := chi.NewRouter()
+	r.Get("/api/v1/jobs/{id}/schema.json", h.JobSchema)
+	schema := func(id string) (*httptest.ResponseRecorder, map[string]interface{}) {
+		t.Helper()
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/jobs/"+id+"/schema.json", nil))
+		var posting map[string]interface{}
+		json.Unmarshal(rec.Body.Bytes(), &posting)
+		return rec, posting
+	}
+
+	rec, posting := schema("job-1")
+	if rec.Code != http.StatusOK || rec.Header().Get("Content-Type") != "application/ld+json" {
+		t.Fatalf("status = %d, content type = %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+	// Properties Google for Jobs requires, and the recommended ones we have
+	for path, want := range map[string]interface{}{
+		"@context":                            "https://schema.org/",
+		"@type":                               "JobPosting",
+		"title":                               "Backend Engineer",
+		"description":                         "<p>Build APIs.</p>",
+		"datePosted":                          "2026-10-01",
+		"validThrough":                        "2026-11-01",
+		"employmentType":                      "FULL_TIME",
+		"url":                                 "https://careers.example.com/jobs/job-1",
+		"identifier.@type":                    "PropertyValue",
+		"identifier.value":                    "job-1",
+		"hiringOrganization.@type":            "Organization",
+		"hiringOrganization.name":             "Acme",
+		"hiringOrganization.sameAs":           "https://careers.example.com",
+		"hiringOrganization.logo":             "https://careers.example.com/logo.png",
+		"jobLocation.@type":                   "Place",
+		"jobLocation.address.@type":           "PostalAddress",
+		"jobLocation.address.addressLocality": "London",
+		"baseSalary.@type":                    "MonetaryAmount",
+		"baseSalary.currency":                 "GBP",
+		"baseSalary.value.@type":              "QuantitativeValue",
+		"baseSalary.value.minValue":           60000.0,
+		"baseSalary.value.maxValue":           80000.0,
+		"baseSalary.value.unitText":           "YEAR",
+	} {
+		if got := lookup(posting, strings.Split(path, ".")...); got != want {
+			t.Errorf("%s = %v, want %v", path, got, want)
+		}
+	}
+	if posting["jobLocationType"] != nil || posting["applicantLocationRequirements"] != nil {
+		t.Fatalf("on-site job has remote properties: %s", rec.Body)
+	}
+
+	// Remote jobs need jobLocationType and applicantLocationRequirements in
+	// place of a jobLocation
+	_, posting = schema("job-2")
+	if posting["jobLocationType"] != "TELECOMMUTE" || posting["jobLocation"] != nil || posting["baseSalary"] != nil {
+		t.Fatalf("remote posting = %v", posting)
+	}
+	if lookup(posting, "applicantLocationRequirements", "@type") != "AdministrativeArea" ||
+		lookup(posting, "applicantLocationRequirements", "name") != "Worldwide" {
+		t.Fatalf("applicantLocationRequirements = %v", posting["applicantLocationRequirements"])
+	}
+	if posting["employmentType"] != "OTHER" || posting["validThrough"] != nil {
+		t.Fatalf("posting = %v", posting)
+	}
+
+	if rec, _ := schema("job-404"); rec.Code != http.StatusNotFound {
+		t.Fatalf("unknown job: status = %d, want 404", rec.Code)
+	}
+}
+
+func TestJobHandler_CloneJob(t *testing.T) {
+	source := map[string]interface{}{
+		"id": "job-1", "slug": "backend-engineer", "title": "Backend Engineer", "department": "Engineering",
+		"status": "PUBLISHED", "postedDate": "2026-10-01", "closingDate": "2026-11-01",
+		"applicationCount": 12.0, "viewCount": 340.0, "createdAt": "2026-09-30T10:00:00Z",
+		"requirements": []interface{}{"Go", "PostgreSQL"},
+	}
+	create := createJobFake()
+	h, fake := newTestJobHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query == gateway.GetJobQuery {
+			if req.Variables["id"] == "job-1" {
+				return map[string]interface{}{"job": source}
+			}
+			return map[string]interface{}{"job": nil}
+		}
+		return create(req)
+	})
+
+	r := chi.NewRouter()
+	r.Post("/api/v1/jobs/{id}/clone", h.CloneJob)
+	clone := func(id string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/api/v1/jobs/"+id+"/clone", nil))
+		return rec
+	}
+
+	rec := clone("job-1")
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, body = %s", rec.Code, rec.Body)
+	}
+	var body map[string]interface{}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if lookup(body, "createJob", "id") != "job-new" || lookup(body, "createJob", "status") != "DRAFT" ||
+		lookup(body, "createJob", "title") != "Backend Engineer (Copy)" {
+		t.Fatalf("body = %s, want the new draft with the copy suffix", rec.Body)
+	}
+
+	fake.mu.Lock()
+	input, _ := fake.requests[len(fake.requests)-1].Variables["input"].(map[string]interface{})
+	fake.mu.Unlock()
+	for _, field := range cloneExcludedFields {
+		if _, ok := input[field]; ok {
+			t.Errorf("clone input kept %s", field)
+		}
+	}
+	if input["department"] != "Engineering" || len(input["requirements"].([]interface{})) != 2 {
+		t.Fatalf("input = %v, want the source's other fields", input)
+	}
+	if source["title"] != "Backend Engineer" {
+		t.Fatal("cloning changed the source job")
+	}
+
+	if rec := clone("job-404"); rec.Code != http.StatusNotFound || fake.sent(gateway.CreateJobMutation) != 1 {
+		t.Fatalf("unknown job: status = %d, want 404 and nothing created", rec.Code)
+	}
+}
+
+// takenSlugFake creates jobs like createJobFake, rejecting the slugs in taken
+// the way Hub-HRMS rejects a slug another job has
+func takenSlugFake(taken ...string) func(gateway.GraphQLRequest) interface{} {
+	create := createJobFake()
+	return func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.UpdateJobSlugMutation {
+			return create(req)
+		}
+		for _, slug := range taken {
+			if req.Variables["slug"] == slug {
+				return &gateway.GraphQLResponse{
+					Data: map[string]interface{}{"updateJobSlug": nil},
+					Errors: []gateway.GraphQLError{{
+						Message:    "slug " + slug + " is already in use",
+						Extensions: map[string]interface{}{"code": "BAD_USER_INPUT"},
+					}},
+				}
+			}
+		}
+		return map[string]interface{}{"updateJobSlug": map[string]interface{}{"id": req.Variables["id"], "slug": req.Variables["slug"]}}
+	}
+}
+
+func TestJobHandler_CreateJob_SlugCollision(t *testing.T) {
+	tests := []struct {
+		name      string
+		taken     []string
+		wantSlug  interface{}
+		wantURL   string
+		wantTries int
+	}{
+		{
+			name:      "title slug free",
+			wantSlug:  "senior-go-engineer",
+			wantURL:   "https://careers.example.com/jobs/senior-go-engineer",
+			wantTries: 1,
+		},
+		{
+			name:      "title slug taken",
+			taken:     []string{"senior-go-engineer"},
+			wantSlug:  "senior-go-engineer-job-new",
+			wantURL:   "https://careers.example.com/jobs/senior-go-engineer-job-new",
+			wantTries: 2,
+		},
+		{
+			name:      "both taken",
+			taken:     []string{"senior-go-engineer", "senior-go-engineer-job-new"},
+			wantSlug:  nil,
+			wantURL:   "https://careers.example.com/jobs/job-new",
+			wantTries: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestJobHandler(t, takenSlugFake(tt.taken...))
+			rec := httptest.NewRecorder()
+			h.CreateJob(rec, httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(testJob("Senior Go Engineer"))))
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+			}
+
+			var response struct {
+				Job map[string]interface{} `json:"job"`
+			}
+			json.Unmarshal(rec.Body.Bytes(), &response)
+			if response.Job["slug"] != tt.wantSlug || response.Job["canonicalUrl"] != tt.wantURL {
+				t.Fatalf("job = %v, want slug %v at %s", response.Job, tt.wantSlug, tt.wantURL)
+			}
+			if got := fake.sent(gateway.UpdateJobSlugMutation); got != tt.wantTries {
+				t.Fatalf("tried %d slugs, want %d", got, tt.wantTries)
+			}
+		})
+	}
+}
+
+func TestJobHandler_UpdateJobSlug(t *testing.T) {
+	router := func(h *JobHandler) http.Handler {
+		r := chi.NewRouter()
+		r.Patch("/jobs/{id}/slug", h.UpdateJobSlug)
+		return r
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantSlug   string
+	}{
+		{name: "normalised", body: `{"slug":"Go Engineer (Berlin)"}`, wantStatus: http.StatusOK, wantSlug: "go-engineer-berlin"},
+		{name: "taken", body: `{"slug":"senior-go-engineer"}`, wantStatus: http.StatusConflict},
+		{name: "blank", body: `{"slug":"  "}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid body", body: `{`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestJobHandler(t, takenSlugFake("senior-go-engineer"))
+			rec := httptest.NewRecorder()
+			router(h).ServeHTTP(rec, httptest.NewRequest(http.MethodPatch, "/jobs/job-1/slug", strings.NewReader(tt.body)))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus == http.StatusBadRequest && fake.sent(gateway.UpdateJobSlugMutation) != 0 {
+				t.Fatal("invalid slug was sent to Hub-HRMS")
+			}
+			if tt.wantSlug == "" {
+				return
+			}
+
+			var response struct {
+				Job map[string]interface{} `json:"updateJobSlug"`
+			}
+			json.Unmarshal(rec.Body.Bytes(), &response)
+			if response.Job["slug"] != tt.wantSlug || response.Job["canonicalUrl"] != "https://careers.example.com/jobs/"+tt.wantSlug {
+				t.Fatalf("job = %v, want slug %s with its canonical URL", response.Job, tt.wantSlug)
+			}
+		})
+	}
+}
+
+func TestJobHandler_GetJobBySlug(t *testing.T) {
+	h, fake := newTestJobHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.GetJobBySlugQuery || req.Variables["slug"] != "senior-go-engineer" {
+			return map[string]interface{}{"jobBySlug": nil}
+		}
+		return map[string]interface{}{"jobBySlug": map[string]interface{}{
+			"id": "job-1", "slug": "senior-go-engineer", "title": "Senior Go Engineer",
+		}}
+	})
+	r := chi.NewRouter()
+	r.Get("/jobs/by-slug/{slug}", h.GetJobBySlug)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs/by-slug/senior-go-engineer", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+	var response struct {
+		Job map[string]interface{} `json:"job"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &response)
+	if response.Job["id"] != "job-1" || response.Job["canonicalUrl"] != "https://careers.example.com/jobs/senior-go-engineer" {
+		t.Fatalf("job = %v", response.Job)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs/by-slug/no-such-job", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	if fake.sent(gateway.GetJobBySlugQuery) != 2 {
+		t.Fatalf("sent %d slug lookups, want 2", fake.sent(gateway.GetJobBySlugQuery))
+	}
+}
+
+// abTestJobFake serves job-1 under an A/B test and job-2 without one
+func abTestJobFake(req gateway.GraphQLRequest) interface{} {
+	switch req.Variables["id"] {
+	case "job-1":
+		return map[string]interface{}{"job": map[string]interface{}{
+			"id": "job-1", "title": "Backend Engineer", "description": "Build APIs.",
+			"variantB": map[string]interface{}{"title": "Backend Engineer (Go)", "description": "Ship Go services."},
+		}}
+	case "job-2":
+		return map[string]interface{}{"job": map[string]interface{}{"id": "job-2", "title": "Recruiter", "description": "Hire people."}}
+	}
+	return map[string]interface{}{"job": nil}
+}
+
+func TestJobHandler_GetJob_ABVariant(t *testing.T) {
+	h, _ := newTestJobHandler(t, abTestJobFake)
+	r := chi.NewRouter()
+	r.With(middleware.ABVariant).Get("/jobs/{id}", h.GetJob)
+
+	get := func(path string, cookie *http.Cookie) map[string]interface{} {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s status = %d: %s", path, rec.Code, rec.Body)
+		}
+		var response struct {
+			Job map[string]interface{} `json:"job"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &response)
+		if _, ok := response.Job["variantB"]; ok {
+			t.Fatalf("job = %v, want variant B's copy kept from visitors", response.Job)
+		}
+		return response.Job
+	}
+
+	tests := []struct {
+		name        string
+		path        string
+		wantTitle   string
+		wantVariant interface{}
+	}{
+		{name: "variant a", path: "/jobs/job-1?variant=a", wantTitle: "Backend Engineer", wantVariant: "a"},
+		{name: "variant b", path: "/jobs/job-1?variant=b", wantTitle: "Backend Engineer (Go)", wantVariant: "b"},
+		{name: "no test", path: "/jobs/job-2?variant=b", wantTitle: "Recruiter", wantVariant: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := get(tt.path, nil)
+			if job["title"] != tt.wantTitle || job["variant"] != tt.wantVariant {
+				t.Fatalf("job = %v, want title %q as variant %v", job, tt.wantTitle, tt.wantVariant)
+			}
+		})
+	}
+
+	t.Run("visitors keep their variant", func(t *testing.T) {
+		served := map[interface{}]bool{}
+		for i := 0; i < 20; i++ {
+			cookie := &http.Cookie{Name: middleware.ABVisitorCookieName, Value: fmt.Sprintf("visitor-%d", i)}
+			first := get("/jobs/job-1", cookie)
+			again := get("/jobs/job-1", cookie)
+			if first["variant"] != again["variant"] || first["title"] != again["title"] {
+				t.Fatalf("visitor %d was served %v then %v", i, first["variant"], again["variant"])
+			}
+			served[first["variant"]] = true
+		}
+		if !served["a"] || !served["b"] {
+			t.Fatalf("served variants %v, want both", served)
+		}
+	})
+}
+
+func TestJobHandler_CreateABTest(t *testing.T) {
+	h, fake := newTestJobHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		input, _ := req.Variables["input"].(map[string]interface{})
+		return map[string]interface{}{"createJobVariant": map[string]interface{}{
+			"jobId": req.Variables["jobId"], "title": input["title"], "description": input["description"],
+		}}
+	})
+	r := chi.NewRouter()
+	r.Post("/jobs/{id}/ab-test", h.CreateABTest)
+
+	post := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jobs/job-1/ab-test", strings.NewReader(body)))
+		return rec
+	}
+
+	rec := post(`{"variantBTitle":"Backend Engineer (Go)","variantBDescription":"Ship Go services."}`)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+	}
+	fake.mu.Lock()
+	variables := fake.requests[0].Variables
+	fake.mu.Unlock()
+	input, _ := variables["input"].(map[string]interface{})
+	if variables["jobId"] != "job-1" || input["title"] != "Backend Engineer (Go)" || input["description"] != "Ship Go services." {
+		t.Fatalf("variables = %v, want the job ID and variant B's copy", variables)
+	}
+
+	if rec := post(`{"variantBTitle":"Backend Engineer (Go)"}`); rec.Code != http.StatusBadRequest {
+		t.Fatalf("status without a description = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if fake.sent(gateway.CreateJobVariantMutation) != 1 {
+		t.Fatal("invalid variant was sent to Hub-HRMS")
+	}
+}
+
+func TestJobHandler_SalaryWarnings(t *testing.T) {
+	benchmarks := []services.SalaryBenchmark{
+		{JobTitle: "Go Engineer", ExperienceLevel: "SENIOR", Currency: "USD", MinMarket: 130000, MaxMarket: 180000},
+	}
+	respond := func(req gateway.GraphQLRequest) interface{} {
+		switch req.Query {
+		case gateway.GetJobQuery:
+			return map[string]interface{}{"job": map[string]interface{}{
+				"id": "job-1", "title": "Senior Go Engineer", "experienceLevel": "SENIOR",
+			}}
+		case gateway.UpdateJobMutation:
+			return map[string]interface{}{"updateJob": map[string]interface{}{"id": req.Variables["id"]}}
+		}
+		return createJobFake()(req)
+	}
+	withSalary := func(body string, salary string) string {
+		return strings.TrimSuffix(body, "}") + `,"salaryRange":` + salary + `}`
+	}
+
+	tests := []struct {
+		name         string
+		update       bool
+		body         string
+		wantWarnings []string
+	}{
+		{
+			name:         "create below market",
+			body:         withSalary(testJob("Senior Go Engineer"), `{"min":20000,"max":150000,"currency":"USD"}`),
+			wantWarnings: []string{"Minimum salary 20000 USD is more than 50% below the market minimum of 130000 USD for senior Go Engineer"},
+		},
+		{
+			name:         "create within market",
+			body:         withSalary(testJob("Senior Go Engineer"), `{"min":120000,"max":170000,"currency":"USD"}`),
+			wantWarnings: []string{},
+		},
+		{
+			name: "create without a salary",
+			body: testJob("Senior Go Engineer"),
+		},
+		{
+			name:         "update reads the title and level from the job",
+			update:       true,
+			body:         `{"salaryRange":{"min":130000,"max":900000,"currency":"USD"}}`,
+			wantWarnings: []string{"Maximum salary 900000 USD is more than 200% above the market maximum of 180000 USD for senior Go Engineer"},
+		},
+		{
+			name:   "update without a salary",
+			update: true,
+			body:   `{"title":"Staff Go Engineer"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, fake := newTestJobHandler(t, respond)
+			h.salaries = services.NewSalaryValidator(benchmarks)
+
+			r := chi.NewRouter()
+			r.Post("/jobs", h.CreateJob)
+			r.Put("/jobs/{id}", h.UpdateJob)
+			method, path, wantStatus := http.MethodPost, "/jobs", http.StatusCreated
+			if tt.update {
+				method, path, wantStatus = http.MethodPut, "/jobs/job-1", http.StatusOK
+			}
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, httptest.NewRequest(method, path, strings.NewReader(tt.body)))
+			if rec.Code != wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, wantStatus, rec.Body)
+			}
+
+			var response struct {
+				Warnings *[]string `json:"warnings"`
+			}
+			json.Unmarshal(rec.Body.Bytes(), &response)
+			switch {
+			case tt.wantWarnings == nil && response.Warnings != nil:
+				t.Fatalf("warnings = %v, want none without a salary range", *response.Warnings)
+			case tt.wantWarnings != nil && (response.Warnings == nil || !reflect.DeepEqual(*response.Warnings, tt.wantWarnings)):
+				t.Fatalf("response = %s, want warnings %q", rec.Body, tt.wantWarnings)
+			}
+			if !tt.update && fake.sent(gateway.CreateJobMutation) != 1 {
+				t.Fatal("salary warnings blocked the job")
+			}
+		})
+	}
+}
+
+func TestJobHandler_ListJobs_UnexpectedShape(t *testing.T) {
+	tests := []struct {
+		name string
+		data map[string]interface{}
+	}{
+		{name: "null data", data: nil},
+		{name: "jobs not a list", data: map[string]interface{}{"jobs": map[string]interface{}{"edges": []interface{}{}}, "jobsCount": "3"}},
+		{name: "count not a number", data: map[string]interface{}{"jobs": []interface{}{}, "jobsCount": map[string]interface{}{"value": 3}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := newTestJobHandler(t, func(gateway.GraphQLRequest) interface{} {
+				if tt.data == nil {
+					return nil
+				}
+				return tt.data
+			})
+
+			rec := httptest.NewRecorder()
+			h.ListJobs(rec, httptest.NewRequest(http.MethodGet, "/api/v1/jobs?includeTotal=true", nil))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+			var page map[string]interface{}
+			json.Unmarshal(rec.Body.Bytes(), &page)
+			if items, ok := page["items"].([]interface{}); !ok || len(items) != 0 || page["hasMore"] != false || page["limit"] != float64(20) {
+				t.Fatalf("page = %s, want an empty envelope", rec.Body)
+			}
+			if rec.Header().Get("X-Total-Count") != "0" {
+				t.Fatalf("X-Total-Count = %q, want 0", rec.Header().Get("X-Total-Count"))
+			}
+		})
+	}
+}
+
+func TestJobHandler_RejectionRules(t *testing.T) {
+	rules := []interface{}{
+		map[string]interface{}{"field": "requiresVisa", "operator": "eq", "value": true},
+	}
+	h, fake := newTestJobHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		switch req.Query {
+		case gateway.GetJobAutoRejectionRulesQuery:
+			if req.Variables["id"] != "job-1" {
+				return map[string]interface{}{"job": nil}
+			}
+			return map[string]interface{}{"job": map[string]interface{}{"id": "job-1", "autoRejectionRules": rules}}
+		case gateway.UpdateJobAutoRejectionRulesMutation:
+			rules, _ = req.Variables["rules"].([]interface{})
+			return map[string]interface{}{"updateJobAutoRejectionRules": rules}
+		}
+		return map[string]interface{}{}
+	})
+
+	r := chi.NewRouter()
+	r.Get("/jobs/{id}/rejection-rules", h.GetRejectionRules)
+	r.Put("/jobs/{id}/rejection-rules", h.UpdateRejectionRules)
+
+	get := func(path string) (int, []services.AutoRejectionRule) {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, asUser(httptest.NewRequest(http.MethodGet, path, nil), "recruiter-1", "recruiter"))
+		var body struct {
+			Rules []services.AutoRejectionRule `json:"rules"`
+		}
+		json.Unmarshal(rec.Body.Bytes(), &body)
+		return rec.Code, body.Rules
+	}
+
+	if status, got := get("/jobs/job-1/rejection-rules"); status != http.StatusOK || len(got) != 1 || got[0].Field != "requiresVisa" {
+		t.Fatalf("GET = %d %v, want the job's rule", status, got)
+	}
+	if status, _ := get("/jobs/job-9/rejection-rules"); status != http.StatusNotFound {
+		t.Fatalf("GET missing job = %d, want 404", status)
+	}
+
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "replace", body: `{"rules":[{"field":"yearsOfExperience","operator":"lt","value":3}]}`, wantStatus: http.StatusOK},
+		{name: "unknown operator", body: `{"rules":[{"field":"yearsOfExperience","operator":"between","value":3}]}`, wantStatus: http.StatusBadRequest},
+		{name: "no field", body: `{"rules":[{"operator":"eq","value":true}]}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid body", body: `{`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, asUser(httptest.NewRequest(http.MethodPut, "/jobs/job-1/rejection-rules", strings.NewReader(tt.body)), "recruiter-1", "recruiter"))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+		})
+	}
+	if fake.sent(gateway.UpdateJobAutoRejectionRulesMutation) != 1 {
+		t.Fatal("invalid rules were sent to Hub-HRMS")
+	}
+	if status, got := get("/jobs/job-1/rejection-rules"); status != http.StatusOK || len(got) != 1 || got[0].Field != "yearsOfExperience" {
+		t.Fatalf("GET after PUT = %d %v, want the replaced rule", status, got)
+	}
+
+	// An empty body clears the rules
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, asUser(httptest.NewRequest(http.MethodPut, "/jobs/job-1/rejection-rules", strings.NewReader(`{}`)), "recruiter-1", "recruiter"))
+	if status, got := get("/jobs/job-1/rejection-rules"); rec.Code != http.StatusOK || status != http.StatusOK || len(got) != 0 {
+		t.Fatalf("GET after clearing = %d %v, want no rules", status, got)
+	}
+}
+
+func TestJobHandler_V1Deprecated(t *testing.T) {
+	h, _ := newTestJobHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		job := map[string]interface{}{"id": "job-1", "title": "Backend Engineer", "status": "PUBLISHED", "createdAt": "2026-10-01T09:00:00Z"}
+		switch req.Query {
+		case gateway.GetJobQuery:
+			return map[string]interface{}{"job": job}
+		case gateway.CountJobsQuery:
+			return map[string]interface{}{"jobsCount": 1}
+		}
+		return map[string]interface{}{"jobs": []interface{}{job}}
+	})
+	sunset := time.Date(2027, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	// The same handlers with and without the v1 deprecation headers
+	plain, deprecated := chi.NewRouter(), chi.NewRouter()
+	deprecated.Use(middleware.Deprecation(sunset, "/api/v2"))
+	for _, r := range []chi.Router{plain, deprecated} {
+		r.Get("/api/v1/jobs", h.ListJobs)
+		r.Get("/api/v1/jobs/{id}", h.GetJob)
+	}
+
+	for _, path := range []string{"/api/v1/jobs", "/api/v1/jobs/job-1", "/api/v1/jobs?cursor=!!!"} {
+		t.Run(path, func(t *testing.T) {
+			want, got := httptest.NewRecorder(), httptest.NewRecorder()
+			plain.ServeHTTP(want, httptest.NewRequest(http.MethodGet, path, nil))
+			deprecated.ServeHTTP(got, httptest.NewRequest(http.MethodGet, path, nil))
+
+			if got.Code != want.Code || got.Body.String() != want.Body.String() {
+				t.Fatalf("response = %d %s, want %d %s", got.Code, got.Body, want.Code, want.Body)
+			}
+			if got.Header().Get("Deprecation") != "true" || got.Header().Get("Sunset") != "Sun, 31 Jan 2027 00:00:00 GMT" {
+				t.Fatalf("headers = %v, want v1 marked deprecated", got.Header())
+			}
+			if got.Header().Get("Content-Type") != want.Header().Get("Content-Type") {
+				t.Fatalf("Content-Type = %q, want %q", got.Header().Get("Content-Type"), want.Header().Get("Content-Type"))
+			}
+		})
+	}
+}
+
+func TestJobHandler_GetReadability(t *testing.T) {
+	h, _ := newTestJobHandler(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Variables["id"] != "job-1" {
+			return map[string]interface{}{"job": nil}
+		}
+		return map[string]interface{}{"job": map[string]interface{}{
+			"id":          "job-1",
+			"description": "You will build our hiring tools. Code is reviewed by the team.",
+			"requirements": []interface{}{
+				"Experience with Go",
+				"Experience running services on AWS",
+			},
+		}}
+	})
+	r := chi.NewRouter()
+	r.Get("/jobs/{id}/readability", h.GetReadability)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs/job-1/readability", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var report services.ReadabilityReport
+	if err := json.Unmarshal(rec.Body.Bytes(), &report); err != nil {
+		t.Fatal(err)
+	}
+	if report.FleschScore == 0 || report.GunningFog == 0 || report.Grade == "" {
+		t.Fatalf("report = %+v, want scores", report)
+	}
+	// The description and each requirement are read
+	want := []string{
+		`Rewrite "is reviewed" in the active voice, saying who does what`,
+		"Spell out AWS the first time it is used",
+	}
+	if !reflect.DeepEqual(report.Suggestions, want) {
+		t.Fatalf("suggestions = %q, want %q", report.Suggestions, want)
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs/job-9/readability", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("missing job status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,255 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/services"
+)
+
+// SendOffer extends an offer: it generates the offer letter, stores it in S3,
+// moves the application to OFFER and emails the letter to the candidate
+func (h *ApplicationHandler) SendOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	appID := chi.URLParam(r, "id")
+
+	if appID == "" {
+		respondError(w, http.StatusBadRequest, "Application ID is required", nil)
+		return
+	}
+
+	var input struct {
+		StartDate      string   `json:"startDate"`
+		Salary         float64  `json:"salary"`
+		Currency       string   `json:"currency"`
+		Benefits       []string `json:"benefits"`
+		SignatoryName  string   `json:"signatoryName"`
+		SignatoryTitle string   `json:"signatoryTitle"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	startDate, err := time.Parse("2006-01-02", input.StartDate)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "startDate must be a YYYY-MM-DD date", err)
+		return
+	}
+
+	resp, err := h.client.Query(ctx, gateway.GetOfferApplicationQuery, map[string]interface{}{"id": appID})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch application", err)
+		return
+	}
+	application := lookup(resp.Data, "application")
+	if application == nil {
+		respondError(w, http.StatusNotFound, "Application not found", nil)
+		return
+	}
+
+	from := lookupString(application, "status")
+	if h.strictTransitions && !h.checkTransition(w, r, from, "OFFER") {
+		return
+	}
+
+	candidate := services.CandidateData{
+		FirstName: lookupString(application, "candidate", "firstName"),
+		LastName:  lookupString(application, "candidate", "lastName"),
+		Email:     lookupString(application, "candidate", "email"),
+	}
+	job := services.JobData{
+		Title:          lookupString(application, "job", "title"),
+		Department:     lookupString(application, "job", "department"),
+		Location:       lookupString(application, "job", "location"),
+		EmploymentType: lookupString(application, "job", "employmentType"),
+	}
+	letter, err := services.GenerateOfferLetterPDF(candidate, job, services.OfferTerms{
+		StartDate:      startDate,
+		Salary:         input.Salary,
+		Currency:       input.Currency,
+		Benefits:       input.Benefits,
+		SignatoryName:  input.SignatoryName,
+		SignatoryTitle: input.SignatoryTitle,
+	})
+	if errors.Is(err, services.ErrInvalidOfferTerms) {
+		respondError(w, http.StatusBadRequest, "Invalid offer terms", err)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to generate offer letter", err)
+		return
+	}
+
+	key := fmt.Sprintf("offer-letters/%s/%d.pdf", appID, time.Now().Unix())
+	letterURL, err := h.uploadService.PutFile(ctx, key, "application/pdf", letter)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to store offer letter", err)
+		return
+	}
+
+	updated, err := h.client.Mutate(ctx, gateway.UpdateApplicationStatusMutation, map[string]interface{}{
+		"id":             appID,
+		"status":         "OFFER",
+		"offerLetterUrl": letterURL,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update application status", err)
+		return
+	}
+
+	// The letter is the offer itself, so it is sent whatever the sender's
+	// notification preferences
+	if candidate.Email != "" {
+		if err := h.emailQueue.Enqueue(services.OfferLetterEmail(candidate.Email, candidate.FirstName, job.Title, letter)); err != nil {
+			h.logger.Error("failed to queue offer letter email", "application_id", appID, "error", err)
+		}
+	}
+
+	h.chat.Notify(services.ApplicationNotification{
+		Event:         services.ChatStatusChanged,
+		ApplicationID: appID,
+		CandidateName: strings.TrimSpace(candidate.FirstName + " " + candidate.LastName),
+		JobTitle:      job.Title,
+		Status:        "OFFER",
+		URL:           h.applicationURL(appID),
+	})
+
+	h.webhooks.Publish(services.EventApplicationStatusChanged, services.ApplicationEventData{
+		ApplicationID: appID,
+		Status:        "OFFER",
+	})
+
+	h.pipelineEvents.Publish(services.PipelineEvent{
+		ApplicationID: appID,
+		OldStatus:     from,
+		NewStatus:     "OFFER",
+		JobID:         lookupString(application, "job", "id"),
+	})
+
+	respondJSON(w, http.StatusCreated, map[string]interface{}{
+		"application":    lookup(updated.Data, "updateApplicationStatus"),
+		"offerLetterUrl": letterURL,
+	})
+}
+
+// RecordCounterOffer records a candidate's counter offer against an application
+func (h *ApplicationHandler) RecordCounterOffer(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	appID := chi.URLParam(r, "id")
+
+	if appID == "" {
+		respondError(w, http.StatusBadRequest, "Application ID is required", nil)
+		return
+	}
+
+	var input struct {
+		RequestedSalary   float64  `json:"requestedSalary"`
+		RequestedBenefits []string `json:"requestedBenefits,omitempty"`
+		CandidateNote     string   `json:"candidateNote,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if input.RequestedSalary <= 0 {
+		respondError(w, http.StatusBadRequest, "Requested salary is required", nil)
+		return
+	}
+
+	variables := map[string]interface{}{
+		"applicationId": appID,
+		"input": map[string]interface{}{
+			"requestedSalary":   input.RequestedSalary,
+			"requestedBenefits": input.RequestedBenefits,
+			"candidateNote":     input.CandidateNote,
+		},
+	}
+
+	resp, err := h.client.Mutate(ctx, gateway.RecordCounterOfferMutation, variables)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to record counter offer", err)
+		return
+	}
+
+	go h.notifyCounterOffer(ctx, lookup(resp.Data, "recordCounterOffer", "application"), "received")
+
+	respondJSON(w, http.StatusCreated, resp.Data)
+}
+
+// ApproveCounterOffer approves a pending counter offer
+func (h *ApplicationHandler) ApproveCounterOffer(w http.ResponseWriter, r *http.Request) {
+	h.respondToCounterOffer(w, r, gateway.ApproveCounterOfferMutation, "approveCounterOffer", "approved")
+}
+
+// RejectCounterOffer rejects a pending counter offer
+func (h *ApplicationHandler) RejectCounterOffer(w http.ResponseWriter, r *http.Request) {
+	h.respondToCounterOffer(w, r, gateway.RejectCounterOfferMutation, "rejectCounterOffer", "rejected")
+}
+
+// respondToCounterOffer runs an approve/reject mutation and notifies both parties
+func (h *ApplicationHandler) respondToCounterOffer(w http.ResponseWriter, r *http.Request, mutation, field, outcome string) {
+	ctx := r.Context()
+	counterOfferID := chi.URLParam(r, "counterId")
+
+	if counterOfferID == "" {
+		respondError(w, http.StatusBadRequest, "Counter offer ID is required", nil)
+		return
+	}
+
+	var input struct {
+		Note string `json:"note,omitempty"`
+	}
+	if r.ContentLength != 0 {
+		if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+			respondError(w, http.StatusBadRequest, "Invalid request body", err)
+			return
+		}
+	}
+	defer r.Body.Close()
+
+	variables := map[string]interface{}{
+		"counterOfferId": counterOfferID,
+	}
+	if input.Note != "" {
+		variables["note"] = input.Note
+	}
+
+	resp, err := h.client.Mutate(ctx, mutation, variables)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update counter offer", err)
+		return
+	}
+
+	h.notifyCounterOffer(ctx, lookup(resp.Data, field, "application"), outcome)
+
+	respondJSON(w, http.StatusOK, resp.Data)
+}
+
+// notifyCounterOffer emails the candidate and the job's recruiter about a
+// counter offer step
+func (h *ApplicationHandler) notifyCounterOffer(ctx context.Context, application interface{}, outcome string) {
+	firstName := lookupString(application, "candidate", "firstName")
+	candidateName := strings.TrimSpace(firstName + " " + lookupString(application, "candidate", "lastName"))
+	jobTitle := lookupString(application, "job", "title")
+
+	if email := lookupString(application, "candidate", "email"); email != "" {
+		h.notify(ctx, services.NotifyStatusChanges, services.CounterOfferUpdateEmail(email, firstName, jobTitle, outcome))
+	}
+
+	if email := lookupString(application, "job", "createdBy", "email"); email != "" {
+		recruiterName := lookupString(application, "job", "createdBy", "name")
+		h.notify(ctx, services.NotifyStatusChanges, services.CounterOfferNoticeEmail(email, recruiterName, candidateName, jobTitle, outcome))
+	}
+}
@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/middleware"
+	"hr-recruiting/internal/services"
+)
+
+// counterOfferFake answers the counter offer mutations for application app-1
+func counterOfferFake(req gateway.GraphQLRequest) interface{} {
+	application := map[string]interface{}{
+		"id":        "app-1",
+		"candidate": map[string]interface{}{"firstName": "Ada", "lastName": "Lovelace", "email": "ada@example.com"},
+		"job": map[string]interface{}{
+			"id":        "job-1",
+			"title":     "Backend Engineer",
+			"createdBy": map[string]interface{}{"name": "Grace Hopper", "email": "grace@example.com"},
+		},
+	}
+	switch req.Query {
+	case gateway.RecordCounterOfferMutation:
+		input, _ := req.Variables["input"].(map[string]interface{})
+		return map[string]interface{}{"recordCounterOffer": map[string]interface{}{
+			"id":              "co-1",
+			"status":          "PENDING",
+			"requestedSalary": input["requestedSalary"],
+			"application":     application,
+		}}
+	case gateway.ApproveCounterOfferMutation:
+		return map[string]interface{}{"approveCounterOffer": map[string]interface{}{
+			"id": req.Variables["counterOfferId"], "status": "APPROVED", "responseNote": req.Variables["note"], "application": application,
+		}}
+	case gateway.RejectCounterOfferMutation:
+		return map[string]interface{}{"rejectCounterOffer": map[string]interface{}{
+			"id": req.Variables["counterOfferId"], "status": "REJECTED", "application": application,
+		}}
+	}
+	return map[string]interface{}{}
+}
+
+// waitForEmails waits for n emails to be queued, since some are queued after
+// the response is sent
+func waitForEmails(t *testing.T, emails *recordingEmailQueue, n int) []services.EmailJob {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		jobs := emails.enqueued()
+		if len(jobs) >= n || time.Now().After(deadline) {
+			return jobs
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestApplicationHandler_CounterOfferFlow(t *testing.T) {
+	h, fake, emails := newTestApplicationHandler(t, counterOfferFake)
+	h.features.EmailNotifications.Store(true)
+
+	// The routes as the server mounts them
+	r := chi.NewRouter()
+	r.With(middleware.RequireRole("recruiter", "admin")).Post("/applications/{id}/offer/counter", h.RecordCounterOffer)
+	r.With(middleware.RequireRole("manager", "admin")).Post("/applications/{id}/offer/counter/{counterId}/approve", h.ApproveCounterOffer)
+	r.With(middleware.RequireRole("manager", "admin")).Post("/applications/{id}/offer/counter/{counterId}/reject", h.RejectCounterOffer)
+
+	do := func(path, body string, roles ...string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		if roles != nil {
+			req = asUser(req, "user-1", roles...)
+		}
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("record requires recruiter", func(t *testing.T) {
+		for _, tt := range []struct {
+			roles      []string
+			wantStatus int
+		}{
+			{roles: nil, wantStatus: http.StatusUnauthorized},
+			{roles: []string{"manager"}, wantStatus: http.StatusForbidden},
+			{roles: []string{"candidate"}, wantStatus: http.StatusForbidden},
+		} {
+			if rec := do("/applications/app-1/offer/counter", `{"requestedSalary":90000}`, tt.roles...); rec.Code != tt.wantStatus {
+				t.Fatalf("roles %v: status = %d, want %d", tt.roles, rec.Code, tt.wantStatus)
+			}
+		}
+		if sent := fake.sent(gateway.RecordCounterOfferMutation); sent != 0 {
+			t.Fatalf("counter offer recorded %d times without permission", sent)
+		}
+	})
+
+	t.Run("record rejects a missing salary", func(t *testing.T) {
+		if rec := do("/applications/app-1/offer/counter", `{"candidateNote":"more please"}`, "recruiter"); rec.Code != http.StatusBadRequest {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+		}
+	})
+
+	t.Run("record", func(t *testing.T) {
+		rec := do("/applications/app-1/offer/counter", `{"requestedSalary":90000,"requestedBenefits":["remote"],"candidateNote":"relocation"}`, "recruiter")
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+		}
+		var body map[string]map[string]interface{}
+		json.NewDecoder(rec.Body).Decode(&body)
+		if body["recordCounterOffer"]["status"] != "PENDING" || body["recordCounterOffer"]["requestedSalary"] != 90000.0 {
+			t.Fatalf("response = %v", body)
+		}
+
+		jobs := waitForEmails(t, emails, 2)
+		if len(jobs) != 2 {
+			t.Fatalf("queued %d emails, want the candidate's and the recruiter's", len(jobs))
+		}
+		for _, job := range jobs {
+			if job.Data["Status"] != "received" {
+				t.Fatalf("email to %s has status %v, want received", job.To, job.Data["Status"])
+			}
+		}
+	})
+
+	t.Run("approve requires manager", func(t *testing.T) {
+		if rec := do("/applications/app-1/offer/counter/co-1/approve", "", "recruiter"); rec.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+		}
+		if sent := fake.sent(gateway.ApproveCounterOfferMutation); sent != 0 {
+			t.Fatal("counter offer approved without permission")
+		}
+	})
+
+	t.Run("approve", func(t *testing.T) {
+		rec := do("/applications/app-1/offer/counter/co-1/approve", `{"note":"agreed with finance"}`, "manager")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		var body map[string]map[string]interface{}
+		json.NewDecoder(rec.Body).Decode(&body)
+		if body["approveCounterOffer"]["id"] != "co-1" || body["approveCounterOffer"]["responseNote"] != "agreed with finance" {
+			t.Fatalf("response = %v", body)
+		}
+
+		jobs := waitForEmails(t, emails, 4)
+		if len(jobs) != 4 {
+			t.Fatalf("queued %d emails in all, want 4", len(jobs))
+		}
+		if jobs = jobs[2:]; jobs[0].To != "ada@example.com" || jobs[1].To != "grace@example.com" ||
+			jobs[1].Subject != "Counter Offer approved - Backend Engineer" {
+			t.Fatalf("approval emails = %+v", jobs)
+		}
+	})
+
+	t.Run("reject without a note", func(t *testing.T) {
+		rec := do("/applications/app-1/offer/counter/co-2/reject", "", "admin")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+		}
+		jobs := waitForEmails(t, emails, 6)
+		if len(jobs) != 6 || jobs[4].Data["Status"] != "rejected" {
+			t.Fatalf("rejection emails = %+v", jobs)
+		}
+	})
+}
+
+// sendOfferFake answers the queries SendOffer makes for application app-1,
+// keeping the variables of the status update
+func sendOfferFake(updates chan<- map[string]interface{}) func(gateway.GraphQLRequest) interface{} {
+	return func(req gateway.GraphQLRequest) interface{} {
+		switch req.Query {
+		case gateway.GetOfferApplicationQuery:
+			return map[string]interface{}{"application": map[string]interface{}{
+				"id":        "app-1",
+				"status":    "INTERVIEW",
+				"candidate": map[string]interface{}{"firstName": "Ada", "lastName": "Lovelace", "email": "ada@example.com"},
+				"job":       map[string]interface{}{"id": "job-1", "title": "Staff Engineer", "department": "Platform", "location": "London"},
+			}}
+		case gateway.UpdateApplicationStatusMutation:
+			updates <- req.Variables
+			return map[string]interface{}{"updateApplicationStatus": map[string]interface{}{"id": "app-1", "status": req.Variables["status"]}}
+		}
+		return map[string]interface{}{}
+	}
+}
+
+// s3Object is a request made to the fake S3 for an object
+type s3Object struct {
+	method      string
+	key         string
+	contentType string
+	body        []byte
+}
+
+// newFakeS3 points the AWS SDK at a fake S3 for the rest of the test and
+// returns the object requests made to it
+func newFakeS3(t *testing.T) func() []s3Object {
+	t.Helper()
+	var mu sync.Mutex
+	var objects []s3Object
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		objects = append(objects, s3Object{method: r.Method, key: r.URL.Path, contentType: r.Header.Get("Content-Type"), body: body})
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	t.Setenv("AWS_ENDPOINT_URL_S3", server.URL)
+	t.Setenv("AWS_ACCESS_KEY_ID", "AKIDEXAMPLE")
+	t.Setenv("AWS_SECRET_ACCESS_KEY", "secret")
+	return func() []s3Object {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]s3Object(nil), objects...)
+	}
+}
+
+func TestApplicationHandler_SendOffer(t *testing.T) {
+	fakePDFConverter(t)
+	objects := newFakeS3(t)
+	updates := make(chan map[string]interface{}, 1)
+	h, fake, emails := newTestApplicationHandler(t, sendOfferFake(updates))
+
+	// The route as the server mounts it
+	r := chi.NewRouter()
+	r.With(middleware.RequireRole("admin")).Post("/applications/{id}/offer", h.SendOffer)
+
+	send := func(body string, roles ...string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := asUser(httptest.NewRequest(http.MethodPost, "/applications/app-1/offer", strings.NewReader(body)), "user-1", roles...)
+		r.ServeHTTP(rec, req)
+		return rec
+	}
+
+	t.Run("requires admin", func(t *testing.T) {
+		if rec := send(`{"startDate":"2026-11-02","salary":125000,"currency":"GBP","signatoryName":"Grace Hopper"}`, "recruiter"); rec.Code != http.StatusForbidden {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusForbidden)
+		}
+	})
+
+	t.Run("invalid terms", func(t *testing.T) {
+		for _, body := range []string{
+			`{"startDate":"2 Nov 2026","salary":125000,"currency":"GBP","signatoryName":"Grace Hopper"}`,
+			`{"startDate":"2026-11-02","currency":"GBP","signatoryName":"Grace Hopper"}`,
+			`{"startDate":"2026-11-02","salary":125000,"currency":"pounds","signatoryName":"Grace Hopper"}`,
+		} {
+			if rec := send(body, "admin"); rec.Code != http.StatusBadRequest {
+				t.Fatalf("%s: status = %d, want %d", body, rec.Code, http.StatusBadRequest)
+			}
+		}
+		if len(objects()) != 0 || fake.sent(gateway.UpdateApplicationStatusMutation) != 0 {
+			t.Fatal("offer with invalid terms was stored")
+		}
+	})
+
+	t.Run("send", func(t *testing.T) {
+		rec := send(`{"startDate":"2026-11-02","salary":125000.5,"currency":"gbp","benefits":["25 days holiday"],"signatoryName":"Grace Hopper","signatoryTitle":"VP Engineering"}`, "admin")
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+		}
+
+		stored := objects()
+		if len(stored) != 1 {
+			t.Fatalf("stored %d objects, want the offer letter", len(stored))
+		}
+		letter := stored[0]
+		key := strings.TrimPrefix(letter.key, "/resumes/")
+		if letter.method != http.MethodPut || !strings.HasPrefix(key, "offer-letters/app-1/") || !strings.HasSuffix(key, ".pdf") || letter.contentType != "application/pdf" {
+			t.Fatalf("stored %s as %s", letter.key, letter.contentType)
+		}
+		if !bytes.HasPrefix(letter.body, []byte("%PDF-")) {
+			t.Fatalf("stored letter = %.40q, want a PDF", letter.body)
+		}
+		for _, want := range []string{"Dear Ada,", "Staff Engineer", "GBP 125,000.50", "November 2, 2026", "25 days holiday", "VP Engineering"} {
+			if !bytes.Contains(letter.body, []byte(want)) {
+				t.Errorf("offer letter is missing %q", want)
+			}
+		}
+
+		wantURL := "https://resumes.s3.amazonaws.com/" + key
+		if update := <-updates; update["status"] != "OFFER" || update["offerLetterUrl"] != wantURL {
+			t.Fatalf("status update = %v, want OFFER with %s", update, wantURL)
+		}
+		var body map[string]interface{}
+		json.NewDecoder(rec.Body).Decode(&body)
+		if body["offerLetterUrl"] != wantURL {
+			t.Fatalf("response = %v", body)
+		}
+
+		jobs := emails.enqueued()
+		if len(jobs) != 1 || jobs[0].To != "ada@example.com" || len(jobs[0].Attachments) != 1 ||
+			!bytes.Equal(jobs[0].Attachments[0].Content, letter.body) {
+			t.Fatalf("emails = %+v, want the letter sent to the candidate", jobs)
+		}
+	})
+}
@@ -0,0 +1,62 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"hr-recruiting/internal/services"
+)
+
+// PipelineHandler manages the stages of the hiring pipeline
+type PipelineHandler struct {
+	stages *services.PipelineStageService
+}
+
+// NewPipelineHandler creates a new pipeline handler
+func NewPipelineHandler(stages *services.PipelineStageService) *PipelineHandler {
+	return &PipelineHandler{stages: stages}
+}
+
+// pipelineResponse is the pipeline with the moves it allows from each stage
+type pipelineResponse struct {
+	Stages      []services.PipelineStage `json:"stages"`
+	Transitions map[string][]string      `json:"transitions"`
+}
+
+// GetStages returns the pipeline stages in order
+func (h *PipelineHandler) GetStages(w http.ResponseWriter, r *http.Request) {
+	stages, err := h.stages.Stages(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch pipeline stages", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pipelineResponse{Stages: stages, Transitions: services.StageTransitions(stages)})
+}
+
+// UpdateStages replaces the pipeline stages. Applications already in a stage
+// that is removed keep their status but can no longer be moved under strict
+// transitions.
+func (h *PipelineHandler) UpdateStages(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Stages []services.PipelineStage `json:"stages"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	stages, err := h.stages.Update(r.Context(), input.Stages)
+	if errors.Is(err, services.ErrInvalidPipeline) {
+		respondError(w, http.StatusBadRequest, "Invalid pipeline stages", err)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update pipeline stages", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pipelineResponse{Stages: stages, Transitions: services.StageTransitions(stages)})
+}
@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/services"
+)
+
+// CandidatePoolHandler manages saved candidate pools and the email
+// campaigns sent to them
+type CandidatePoolHandler struct {
+	pools     *services.CandidatePoolService
+	campaigns *services.CampaignService
+}
+
+// NewCandidatePoolHandler creates a new candidate pool handler
+func NewCandidatePoolHandler(pools *services.CandidatePoolService, campaigns *services.CampaignService) *CandidatePoolHandler {
+	return &CandidatePoolHandler{pools: pools, campaigns: campaigns}
+}
+
+// candidatePoolInput is the body of create and update requests
+type candidatePoolInput struct {
+	Name  string                        `json:"name"`
+	Query services.CandidateSearchQuery `json:"query"`
+}
+
+// CreatePool saves a search and the candidates it currently matches
+func (h *CandidatePoolHandler) CreatePool(w http.ResponseWriter, r *http.Request) {
+	var input candidatePoolInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	pool, err := h.pools.Create(r.Context(), input.Name, input.Query, userID(r.Context()))
+	if err != nil {
+		respondPoolError(w, "Failed to create candidate pool", err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, pool)
+}
+
+// ListPools lists saved pools
+func (h *CandidatePoolHandler) ListPools(w http.ResponseWriter, r *http.Request) {
+	pools, err := h.pools.List(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch candidate pools", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pools)
+}
+
+// GetPool returns one pool
+func (h *CandidatePoolHandler) GetPool(w http.ResponseWriter, r *http.Request) {
+	pool, err := h.pools.Get(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		respondPoolError(w, "Failed to fetch candidate pool", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pool)
+}
+
+// UpdatePool renames a pool and replaces its query. Its candidates are not
+// changed until the pool is refreshed.
+func (h *CandidatePoolHandler) UpdatePool(w http.ResponseWriter, r *http.Request) {
+	var input candidatePoolInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	pool, err := h.pools.Update(r.Context(), chi.URLParam(r, "id"), input.Name, input.Query)
+	if err != nil {
+		respondPoolError(w, "Failed to update candidate pool", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pool)
+}
+
+// DeletePool removes a pool
+func (h *CandidatePoolHandler) DeletePool(w http.ResponseWriter, r *http.Request) {
+	if err := h.pools.Delete(r.Context(), chi.URLParam(r, "id")); err != nil {
+		respondPoolError(w, "Failed to delete candidate pool", err)
+		return
+	}
+
+	respondSuccess(w, "Candidate pool deleted successfully", nil)
+}
+
+// RefreshPool re-runs a pool's query so it holds the candidates matching now
+func (h *CandidatePoolHandler) RefreshPool(w http.ResponseWriter, r *http.Request) {
+	pool, err := h.pools.Refresh(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		respondPoolError(w, "Failed to refresh candidate pool", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, pool)
+}
+
+// StartCampaign emails a pool's candidates and answers as soon as the
+// campaign is recorded; the emails go out in the background
+func (h *CandidatePoolHandler) StartCampaign(w http.ResponseWriter, r *http.Request) {
+	var input services.CampaignInput
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	campaign, err := h.campaigns.Start(r.Context(), chi.URLParam(r, "id"), input, userID(r.Context()))
+	switch {
+	case errors.Is(err, services.ErrInvalidCampaign), errors.Is(err, services.ErrNoCampaignRecipients):
+		respondError(w, http.StatusBadRequest, "Invalid campaign", err)
+		return
+	case errors.Is(err, services.ErrCampaignTooLarge):
+		respondError(w, http.StatusUnprocessableEntity, "Campaign has too many recipients", err)
+		return
+	case errors.Is(err, services.ErrJobNotFound):
+		respondError(w, http.StatusNotFound, "Job not found", nil)
+		return
+	case err != nil:
+		respondPoolError(w, "Failed to start campaign", err)
+		return
+	}
+
+	respondJSON(w, http.StatusAccepted, map[string]interface{}{
+		"campaignId":               campaign.ID,
+		"recipientCount":           campaign.RecipientCount,
+		"estimatedDeliveryMinutes": services.EstimatedDeliveryMinutes(campaign.RecipientCount),
+	})
+}
+
+// ListCampaigns lists the campaigns sent to a pool
+func (h *CandidatePoolHandler) ListCampaigns(w http.ResponseWriter, r *http.Request) {
+	campaigns, err := h.campaigns.List(r.Context(), chi.URLParam(r, "id"))
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch campaigns", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, campaigns)
+}
+
+// respondPoolError maps candidate pool service errors to status codes
+func respondPoolError(w http.ResponseWriter, message string, err error) {
+	switch {
+	case errors.Is(err, services.ErrCandidatePoolNotFound):
+		respondError(w, http.StatusNotFound, "Candidate pool not found", nil)
+	case errors.Is(err, services.ErrInvalidCandidatePool):
+		respondError(w, http.StatusBadRequest, "Invalid candidate pool", err)
+	default:
+		respondError(w, http.StatusInternalServerError, message, err)
+	}
+}
@@ -0,0 +1,208 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/services"
+)
+
+// poolFake stores pool-1 in Hub-HRMS and matches cand-1 and cand-2 in
+// candidate searches
+func poolFake(req gateway.GraphQLRequest) interface{} {
+	pool := func(id string, input map[string]interface{}) map[string]interface{} {
+		return map[string]interface{}{
+			"id": id, "name": input["name"], "query": input["query"],
+			"candidateIds": input["candidateIds"], "createdBy": input["createdBy"],
+			"updatedAt": "2026-03-01T09:00:00Z",
+		}
+	}
+	stored := pool("pool-1", map[string]interface{}{
+		"name": "Go engineers", "query": map[string]interface{}{"skills": []string{"Go"}},
+		"candidateIds": []string{"cand-1"}, "createdBy": "user-1",
+	})
+
+	input, _ := req.Variables["input"].(map[string]interface{})
+	found := req.Variables["id"] == "pool-1"
+	switch req.Query {
+	case gateway.SearchCandidatesQuery:
+		return map[string]interface{}{"searchCandidates": []interface{}{
+			map[string]interface{}{"id": "cand-1"}, map[string]interface{}{"id": "cand-2"},
+		}}
+	case gateway.GetCandidatePoolsQuery:
+		return map[string]interface{}{"candidatePools": []interface{}{stored}}
+	case gateway.GetCandidatePoolQuery:
+		if !found {
+			return map[string]interface{}{"candidatePool": nil}
+		}
+		return map[string]interface{}{"candidatePool": stored}
+	case gateway.CreateCandidatePoolMutation:
+		return map[string]interface{}{"createCandidatePool": pool("pool-2", input)}
+	case gateway.UpdateCandidatePoolMutation:
+		return map[string]interface{}{"updateCandidatePool": pool("pool-1", input)}
+	case gateway.DeleteCandidatePoolMutation:
+		return map[string]interface{}{"deleteCandidatePool": found}
+	}
+	return map[string]interface{}{}
+}
+
+func TestCandidatePoolHandler(t *testing.T) {
+	fake, client := newFakeHubHRMS(t, poolFake)
+	h := NewCandidatePoolHandler(services.NewCandidatePoolService(client), nil)
+
+	r := chi.NewRouter()
+	r.Post("/candidate-pools", h.CreatePool)
+	r.Get("/candidate-pools", h.ListPools)
+	r.Get("/candidate-pools/{id}", h.GetPool)
+	r.Put("/candidate-pools/{id}", h.UpdatePool)
+	r.Delete("/candidate-pools/{id}", h.DeletePool)
+	r.Post("/candidate-pools/{id}/refresh", h.RefreshPool)
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		body           string
+		wantStatus     int
+		wantCandidates []string
+	}{
+		{name: "create", method: http.MethodPost, path: "/candidate-pools", body: `{"name":"Go engineers in Berlin","query":{"skills":["Go"],"location":"Berlin"}}`, wantStatus: http.StatusCreated, wantCandidates: []string{"cand-1", "cand-2"}},
+		{name: "create without criteria", method: http.MethodPost, path: "/candidate-pools", body: `{"name":"Everyone","query":{}}`, wantStatus: http.StatusBadRequest},
+		{name: "create without name", method: http.MethodPost, path: "/candidate-pools", body: `{"query":{"skills":["Go"]}}`, wantStatus: http.StatusBadRequest},
+		{name: "create with invalid body", method: http.MethodPost, path: "/candidate-pools", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "list", method: http.MethodGet, path: "/candidate-pools", wantStatus: http.StatusOK},
+		{name: "get", method: http.MethodGet, path: "/candidate-pools/pool-1", wantStatus: http.StatusOK, wantCandidates: []string{"cand-1"}},
+		{name: "get missing", method: http.MethodGet, path: "/candidate-pools/pool-9", wantStatus: http.StatusNotFound},
+		{name: "update keeps candidates", method: http.MethodPut, path: "/candidate-pools/pool-1", body: `{"name":"Gophers","query":{"keywords":"go"}}`, wantStatus: http.StatusOK, wantCandidates: []string{"cand-1"}},
+		{name: "update missing", method: http.MethodPut, path: "/candidate-pools/pool-9", body: `{"name":"Gophers","query":{"keywords":"go"}}`, wantStatus: http.StatusNotFound},
+		{name: "refresh", method: http.MethodPost, path: "/candidate-pools/pool-1/refresh", wantStatus: http.StatusOK, wantCandidates: []string{"cand-1", "cand-2"}},
+		{name: "refresh missing", method: http.MethodPost, path: "/candidate-pools/pool-9/refresh", wantStatus: http.StatusNotFound},
+		{name: "delete", method: http.MethodDelete, path: "/candidate-pools/pool-1", wantStatus: http.StatusOK},
+		{name: "delete missing", method: http.MethodDelete, path: "/candidate-pools/pool-9", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := asUser(httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body)), "user-7", "recruiter")
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantCandidates == nil {
+				return
+			}
+
+			var pool services.CandidatePool
+			json.Unmarshal(rec.Body.Bytes(), &pool)
+			if strings.Join(pool.CandidateIDs, ",") != strings.Join(tt.wantCandidates, ",") {
+				t.Fatalf("candidates = %v, want %v", pool.CandidateIDs, tt.wantCandidates)
+			}
+		})
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	for _, req := range fake.requests {
+		if req.Query == gateway.CreateCandidatePoolMutation {
+			input, _ := req.Variables["input"].(map[string]interface{})
+			if input["createdBy"] != "user-7" {
+				t.Fatalf("createdBy = %v, want the caller", input["createdBy"])
+			}
+		}
+	}
+}
+
+func TestCandidatePoolHandler_StartCampaign(t *testing.T) {
+	recipients := func(n int) []interface{} {
+		candidates := make([]interface{}, n)
+		for i := range candidates {
+			candidates[i] = map[string]interface{}{"id": fmt.Sprintf("cand-%d", i+1), "email": fmt.Sprintf("candidate-%d@example.com", i+1)}
+		}
+		return candidates
+	}
+
+	tests := []struct {
+		name           string
+		path           string
+		body           string
+		candidates     []interface{}
+		wantStatus     int
+		wantRecipients int
+		wantMinutes    int
+	}{
+		{
+			name: "duplicates and opt-outs left out", path: "/candidate-pools/pool-1/campaigns",
+			body: `{"subject":"A role you may like","templateName":"candidate_outreach"}`,
+			candidates: []interface{}{
+				map[string]interface{}{"id": "cand-1", "email": "ada@example.com"},
+				map[string]interface{}{"id": "cand-2", "email": "Ada@Example.com"},
+				map[string]interface{}{"id": "cand-3", "email": "grace@example.com", "emailOptOut": true},
+				map[string]interface{}{"id": "cand-4", "email": "alan@example.com"},
+			},
+			wantStatus: http.StatusAccepted, wantRecipients: 2, wantMinutes: 1,
+		},
+		{name: "several batches", path: "/candidate-pools/pool-1/campaigns", body: `{"subject":"Hello","templateName":"candidate_outreach"}`, candidates: recipients(250), wantStatus: http.StatusAccepted, wantRecipients: 250, wantMinutes: 3},
+		{name: "everyone opted out", path: "/candidate-pools/pool-1/campaigns", body: `{"subject":"Hello","templateName":"candidate_outreach"}`, candidates: []interface{}{map[string]interface{}{"id": "cand-1", "email": "ada@example.com", "emailOptOut": true}}, wantStatus: http.StatusBadRequest},
+		{name: "too many recipients", path: "/candidate-pools/pool-1/campaigns", body: `{"subject":"Hello","templateName":"candidate_outreach"}`, candidates: recipients(501), wantStatus: http.StatusUnprocessableEntity},
+		{name: "unknown template", path: "/candidate-pools/pool-1/campaigns", body: `{"subject":"Hello","templateName":"missing"}`, candidates: recipients(1), wantStatus: http.StatusBadRequest},
+		{name: "unknown job", path: "/candidate-pools/pool-1/campaigns", body: `{"subject":"Hello","templateName":"candidate_outreach","jobId":"job-9"}`, candidates: recipients(1), wantStatus: http.StatusNotFound},
+		{name: "missing pool", path: "/candidate-pools/pool-9/campaigns", body: `{"subject":"Hello","templateName":"candidate_outreach"}`, candidates: recipients(1), wantStatus: http.StatusNotFound},
+		{name: "invalid body", path: "/candidate-pools/pool-1/campaigns", body: `{`, wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake, client := newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+				switch req.Query {
+				case gateway.GetCampaignRecipientsQuery:
+					return map[string]interface{}{"candidatesByIds": tt.candidates}
+				case gateway.GetJobQuery:
+					return map[string]interface{}{"job": nil}
+				case gateway.CreateCampaignMutation:
+					input, _ := req.Variables["input"].(map[string]interface{})
+					return map[string]interface{}{"createCampaign": map[string]interface{}{
+						"id": "campaign-1", "poolId": input["poolId"], "recipientCount": input["recipientCount"],
+					}}
+				}
+				return poolFake(req)
+			})
+			pools := services.NewCandidatePoolService(client)
+			emails := &recordingEmailQueue{}
+			h := NewCandidatePoolHandler(pools, services.NewCampaignService(client, pools, emails, services.NewEmailService("SG.test").Templates()))
+
+			r := chi.NewRouter()
+			r.Post("/candidate-pools/{id}/campaigns", h.StartCampaign)
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, asUser(httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)), "user-7", "recruiter"))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusAccepted {
+				if fake.sent(gateway.CreateCampaignMutation) != 0 {
+					t.Fatal("a rejected campaign was recorded")
+				}
+				return
+			}
+
+			var body struct {
+				CampaignID               string `json:"campaignId"`
+				RecipientCount           int    `json:"recipientCount"`
+				EstimatedDeliveryMinutes int    `json:"estimatedDeliveryMinutes"`
+			}
+			json.Unmarshal(rec.Body.Bytes(), &body)
+			if body.CampaignID != "campaign-1" || body.RecipientCount != tt.wantRecipients || body.EstimatedDeliveryMinutes != tt.wantMinutes {
+				t.Fatalf("body = %+v, want %d recipients over %d minutes", body, tt.wantRecipients, tt.wantMinutes)
+			}
+			// The first batch is queued straight away, the rest a minute apart
+			if jobs := waitForEmails(t, emails, min(tt.wantRecipients, 100)); len(jobs) != min(tt.wantRecipients, 100) {
+				t.Fatalf("queued %d emails, want the first batch", len(jobs))
+			}
+		})
+	}
+}
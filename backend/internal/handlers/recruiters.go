@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/services"
+)
+
+// RecruiterHandler assigns applications to recruiters and reports on their
+// workload
+type RecruiterHandler struct {
+	assignments *services.AssignmentService
+	chat        *services.NotificationBroadcaster
+	baseURL     string
+}
+
+// NewRecruiterHandler creates a new recruiter handler
+func NewRecruiterHandler(assignments *services.AssignmentService, chat *services.NotificationBroadcaster, baseURL string) *RecruiterHandler {
+	return &RecruiterHandler{
+		assignments: assignments,
+		chat:        chat,
+		baseURL:     strings.TrimSuffix(baseURL, "/"),
+	}
+}
+
+// GetWorkload lists recruiters with their open application counts
+func (h *RecruiterHandler) GetWorkload(w http.ResponseWriter, r *http.Request) {
+	workload, err := h.assignments.Workload(r.Context())
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch recruiter workload", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, workload)
+}
+
+// AssignApplication hands an application to a recruiter
+func (h *RecruiterHandler) AssignApplication(w http.ResponseWriter, r *http.Request) {
+	appID := chi.URLParam(r, "id")
+	if appID == "" {
+		respondError(w, http.StatusBadRequest, "Application ID is required", nil)
+		return
+	}
+
+	var input struct {
+		RecruiterID string `json:"recruiterId"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if input.RecruiterID == "" {
+		respondError(w, http.StatusBadRequest, "recruiterId is required", nil)
+		return
+	}
+
+	application, err := h.assignments.Assign(r.Context(), appID, input.RecruiterID)
+	if errors.Is(err, services.ErrApplicationNotFound) {
+		respondError(w, http.StatusNotFound, "Application not found", nil)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to assign application", err)
+		return
+	}
+
+	h.notifyAssigned(*application)
+	respondJSON(w, http.StatusOK, application)
+}
+
+// AutoAssign spreads unassigned applications over the recruiters, lightest
+// workload first
+func (h *RecruiterHandler) AutoAssign(w http.ResponseWriter, r *http.Request) {
+	assigned, err := h.assignments.AutoAssign(r.Context())
+	if errors.Is(err, services.ErrNoRecruiters) {
+		respondError(w, http.StatusConflict, "There are no recruiters to assign applications to", nil)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to auto-assign applications", err)
+		return
+	}
+
+	for _, application := range assigned {
+		h.notifyAssigned(application)
+	}
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"assigned": assigned,
+		"total":    len(assigned),
+	})
+}
+
+// notifyAssigned tells the chat channels who an application went to
+func (h *RecruiterHandler) notifyAssigned(application services.AssignedApplication) {
+	recruiter := application.Recruiter.Name
+	if recruiter == "" {
+		recruiter = application.Recruiter.Email
+	}
+
+	h.chat.Notify(services.ApplicationNotification{
+		Event:         services.ChatAssigned,
+		ApplicationID: application.ApplicationID,
+		CandidateName: application.CandidateName,
+		JobTitle:      application.JobTitle,
+		Recruiter:     recruiter,
+		URL:           applicationLink(h.baseURL, application.ApplicationID),
+	})
+}
@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/services"
+)
+
+// recruitersFake has the given recruiters and three unassigned
+// applications. app-9 does not exist.
+func recruitersFake(recruiters ...interface{}) func(gateway.GraphQLRequest) interface{} {
+	return func(req gateway.GraphQLRequest) interface{} {
+		switch req.Query {
+		case gateway.GetRecruiterWorkloadQuery:
+			return map[string]interface{}{"recruiters": recruiters}
+		case gateway.GetUnassignedApplicationsQuery:
+			return map[string]interface{}{"applications": []interface{}{
+				map[string]interface{}{"id": "app-2", "appliedDate": "2026-10-02T09:00:00Z"},
+				map[string]interface{}{"id": "app-1", "appliedDate": "2026-10-01T09:00:00Z"},
+				map[string]interface{}{"id": "app-3", "appliedDate": "2026-10-03T09:00:00Z"},
+			}}
+		case gateway.AssignApplicationMutation:
+			if req.Variables["applicationId"] == "app-9" {
+				return map[string]interface{}{"assignApplication": nil}
+			}
+			return map[string]interface{}{"assignApplication": map[string]interface{}{
+				"id":                req.Variables["applicationId"],
+				"assignedRecruiter": map[string]interface{}{"id": req.Variables["recruiterId"], "name": req.Variables["recruiterId"]},
+				"candidate":         map[string]interface{}{"firstName": "Ada", "lastName": "Lovelace"},
+				"job":               map[string]interface{}{"title": "Backend Engineer"},
+			}}
+		}
+		return map[string]interface{}{}
+	}
+}
+
+// testRecruiters are grace, with five open applications, and alan, with four
+func testRecruiters() []interface{} {
+	return []interface{}{
+		map[string]interface{}{"id": "grace", "name": "grace", "email": "grace@example.com", "openApplications": 5},
+		map[string]interface{}{"id": "alan", "name": "alan", "email": "alan@example.com", "openApplications": 4},
+	}
+}
+
+// newTestRecruiterHandler returns a recruiter handler routed as in main and
+// the notifier it posts to
+func newTestRecruiterHandler(t *testing.T, respond func(gateway.GraphQLRequest) interface{}) (http.Handler, *fakeHubHRMS, *recordingNotifier) {
+	t.Helper()
+	fake, client := newFakeHubHRMS(t, respond)
+	chat := &recordingNotifier{}
+	h := NewRecruiterHandler(services.NewAssignmentService(client), services.NewNotificationBroadcaster(chat), "https://careers.example.com/")
+
+	r := chi.NewRouter()
+	r.Get("/recruiters/workload", h.GetWorkload)
+	r.Post("/applications/auto-assign", h.AutoAssign)
+	r.Post("/applications/{id}/assign", h.AssignApplication)
+	return r, fake, chat
+}
+
+func TestRecruiterHandler_AutoAssign(t *testing.T) {
+	r, _, chat := newTestRecruiterHandler(t, recruitersFake(testRecruiters()...))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, asUser(httptest.NewRequest(http.MethodPost, "/applications/auto-assign", nil), "admin-1", "admin"))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var body struct {
+		Assigned []services.AssignedApplication `json:"assigned"`
+		Total    int                            `json:"total"`
+	}
+	json.Unmarshal(rec.Body.Bytes(), &body)
+
+	// Oldest first, to alan until alan has as many as grace, then in turns
+	want := []string{"app-1:alan", "app-2:grace", "app-3:alan"}
+	var got []string
+	for _, application := range body.Assigned {
+		got = append(got, application.ApplicationID+":"+application.Recruiter.ID)
+	}
+	if body.Total != 3 || strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("assigned %v (total %d), want %v", got, body.Total, want)
+	}
+
+	chat.mu.Lock()
+	defer chat.mu.Unlock()
+	if len(chat.notifications) != 3 {
+		t.Fatalf("notifications = %+v, want one per assignment", chat.notifications)
+	}
+	first := chat.notifications[0]
+	if first.Event != services.ChatAssigned || first.Recruiter != "alan" || first.CandidateName != "Ada Lovelace" ||
+		first.URL != "https://careers.example.com/applications/app-1" {
+		t.Fatalf("notification = %+v, want app-1 assigned to alan", first)
+	}
+}
+
+func TestRecruiterHandler_AutoAssign_NoRecruiters(t *testing.T) {
+	r, fake, _ := newTestRecruiterHandler(t, recruitersFake())
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, asUser(httptest.NewRequest(http.MethodPost, "/applications/auto-assign", nil), "admin-1", "admin"))
+	if rec.Code != http.StatusConflict {
+		t.Fatalf("status = %d, want 409: %s", rec.Code, rec.Body)
+	}
+	if fake.sent(gateway.AssignApplicationMutation) != 0 {
+		t.Fatal("applications were assigned without recruiters")
+	}
+}
+
+func TestRecruiterHandler_AssignApplication(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+		wantNotify bool
+	}{
+		{name: "assigned", path: "/applications/app-1/assign", body: `{"recruiterId":"grace"}`, wantStatus: http.StatusOK, wantNotify: true},
+		{name: "no recruiter", path: "/applications/app-1/assign", body: `{}`, wantStatus: http.StatusBadRequest},
+		{name: "invalid body", path: "/applications/app-1/assign", body: `{`, wantStatus: http.StatusBadRequest},
+		{name: "missing application", path: "/applications/app-9/assign", body: `{"recruiterId":"grace"}`, wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, fake, chat := newTestRecruiterHandler(t, recruitersFake(testRecruiters()...))
+
+			rec := httptest.NewRecorder()
+			r.ServeHTTP(rec, asUser(httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)), "admin-1", "admin"))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus == http.StatusBadRequest && fake.sent(gateway.AssignApplicationMutation) != 0 {
+				t.Fatal("invalid assignment was sent to Hub-HRMS")
+			}
+			chat.mu.Lock()
+			notified := len(chat.notifications) == 1 && chat.notifications[0].Recruiter == "grace"
+			chat.mu.Unlock()
+			if notified != tt.wantNotify {
+				t.Fatalf("notified = %v, want %v", notified, tt.wantNotify)
+			}
+		})
+	}
+}
+
+func TestRecruiterHandler_GetWorkload(t *testing.T) {
+	recruiters := append(testRecruiters(), map[string]interface{}{"id": "edsger", "name": "edsger", "openApplications": 9})
+	r, _, _ := newTestRecruiterHandler(t, recruitersFake(recruiters...))
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, asUser(httptest.NewRequest(http.MethodGet, "/recruiters/workload", nil), "admin-1", "admin"))
+	var workload []services.RecruiterWorkload
+	json.Unmarshal(rec.Body.Bytes(), &workload)
+	if rec.Code != http.StatusOK || len(workload) != 3 {
+		t.Fatalf("workload = %d %+v, want all three recruiters", rec.Code, workload)
+	}
+	for i, id := range []string{"edsger", "grace", "alan"} {
+		if workload[i].ID != id {
+			t.Fatalf("workload = %+v, want the busiest first", workload)
+		}
+	}
+	if workload[1].Email != "grace@example.com" || workload[1].OpenApplications != 5 {
+		t.Fatalf("grace = %+v", workload[1])
+	}
+}
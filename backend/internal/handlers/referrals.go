@@ -0,0 +1,216 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/mail"
+	"sort"
+	"strings"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/middleware"
+	"hr-recruiting/internal/services"
+)
+
+// referralPageSize is how many referrals are fetched per query when every
+// referral in a range is needed
+const referralPageSize = 500
+
+// ReferralHandler records employee referrals and reports on them
+type ReferralHandler struct {
+	client *gateway.HubHRMSClient
+}
+
+// NewReferralHandler creates a new referral handler
+func NewReferralHandler(client *gateway.HubHRMSClient) *ReferralHandler {
+	return &ReferralHandler{client: client}
+}
+
+// CreateReferral records an employee referring a candidate to a job. The
+// returned ID is sent as referralId when the candidate applies.
+func (h *ReferralHandler) CreateReferral(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		JobID              string `json:"jobId"`
+		ReferrerID         string `json:"referrerId"`
+		CandidateEmail     string `json:"candidateEmail"`
+		CandidateFirstName string `json:"candidateFirstName"`
+		CandidateLastName  string `json:"candidateLastName"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	input.CandidateEmail = services.NormalizeEmail(input.CandidateEmail)
+	input.CandidateFirstName = strings.TrimSpace(input.CandidateFirstName)
+	input.CandidateLastName = strings.TrimSpace(input.CandidateLastName)
+	switch {
+	case input.JobID == "":
+		respondError(w, http.StatusBadRequest, "jobId is required", nil)
+		return
+	case input.ReferrerID == "":
+		respondError(w, http.StatusBadRequest, "referrerId is required", nil)
+		return
+	case input.CandidateFirstName == "" || input.CandidateLastName == "":
+		respondError(w, http.StatusBadRequest, "Candidate first and last name are required", nil)
+		return
+	}
+	if _, err := mail.ParseAddress(input.CandidateEmail); err != nil {
+		respondError(w, http.StatusBadRequest, "candidateEmail must be a valid email address", err)
+		return
+	}
+
+	resp, err := h.client.Mutate(r.Context(), gateway.CreateReferralMutation, map[string]interface{}{
+		"input": input,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to create referral", err)
+		return
+	}
+	if len(resp.Errors) > 0 {
+		respondError(w, http.StatusBadRequest, "Referral could not be created: "+resp.Errors[0].Message, nil)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, lookup(resp.Data, "createReferral"))
+}
+
+// GetReferrerHistory lists the referrals made by one employee and where each
+// candidate got to. Employees may only see their own; recruiters and admins
+// may see anyone's.
+func (h *ReferralHandler) GetReferrerHistory(w http.ResponseWriter, r *http.Request) {
+	referrerID := chi.URLParam(r, "referrerId")
+	if referrerID != userID(r.Context()) && !middleware.HasRole(r.Context(), "recruiter", "admin") {
+		respondError(w, http.StatusForbidden, "You can only view your own referrals", nil)
+		return
+	}
+
+	referrals, err := fetchReferrals(r.Context(), h.client, map[string]interface{}{"referrerId": referrerID})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch referrals", err)
+		return
+	}
+
+	var summary referralFunnel
+	for _, referral := range referrals {
+		summary.add(referral)
+	}
+	summary.rates()
+
+	respondJSON(w, http.StatusOK, map[string]interface{}{
+		"referrerId": referrerID,
+		"summary":    summary,
+		"referrals":  referrals,
+	})
+}
+
+// fetchReferrals pages through every referral matching filters
+func fetchReferrals(ctx context.Context, client *gateway.HubHRMSClient, filters map[string]interface{}) ([]interface{}, error) {
+	var referrals []interface{}
+	for offset := 0; ; offset += referralPageSize {
+		resp, err := client.Query(ctx, gateway.GetReferralsQuery, map[string]interface{}{
+			"filters": filters,
+			"limit":   referralPageSize,
+			"offset":  offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		page, _ := lookup(resp.Data, "referrals").([]interface{})
+		referrals = append(referrals, page...)
+		if len(page) < referralPageSize {
+			return referrals, nil
+		}
+	}
+}
+
+// referralFunnel counts referrals and how many became applications and hires
+type referralFunnel struct {
+	Referrals       int     `json:"referrals"`
+	Applied         int     `json:"applied"`
+	Hired           int     `json:"hired"`
+	ApplicationRate float64 `json:"applicationRate"`
+	HireRate        float64 `json:"hireRate"`
+}
+
+func (f *referralFunnel) add(referral interface{}) {
+	f.Referrals++
+	if lookup(referral, "application") != nil {
+		f.Applied++
+	}
+	if lookupString(referral, "application", "status") == "HIRED" {
+		f.Hired++
+	}
+}
+
+// rates fills in the share of referrals that applied and the share of
+// applicants that were hired
+func (f *referralFunnel) rates() {
+	if f.Referrals > 0 {
+		f.ApplicationRate = float64(f.Applied) / float64(f.Referrals)
+	}
+	if f.Applied > 0 {
+		f.HireRate = float64(f.Hired) / float64(f.Applied)
+	}
+}
+
+// referrerFunnel is the referral funnel for one employee
+type referrerFunnel struct {
+	ReferrerID string `json:"referrerId"`
+	Name       string `json:"name,omitempty"`
+	referralFunnel
+}
+
+// referralSummary is the overall referral funnel and the employees whose
+// referrals led to the most hires
+type referralSummary struct {
+	Totals       referralFunnel   `json:"totals"`
+	TopReferrers []referrerFunnel `json:"topReferrers"`
+}
+
+// summarizeReferrals totals the referral funnel and ranks referrers by hires,
+// then referrals, listing at most top of them
+func summarizeReferrals(referrals []interface{}, top int) referralSummary {
+	var summary referralSummary
+	byReferrer := make(map[string]*referrerFunnel)
+	var order []string
+
+	for _, referral := range referrals {
+		summary.Totals.add(referral)
+
+		id := lookupString(referral, "referrer", "id")
+		if id == "" {
+			continue
+		}
+		funnel, ok := byReferrer[id]
+		if !ok {
+			funnel = &referrerFunnel{ReferrerID: id, Name: lookupString(referral, "referrer", "name")}
+			byReferrer[id] = funnel
+			order = append(order, id)
+		}
+		funnel.add(referral)
+	}
+	summary.Totals.rates()
+
+	summary.TopReferrers = make([]referrerFunnel, 0, len(order))
+	for _, id := range order {
+		byReferrer[id].rates()
+		summary.TopReferrers = append(summary.TopReferrers, *byReferrer[id])
+	}
+	sort.SliceStable(summary.TopReferrers, func(i, j int) bool {
+		a, b := summary.TopReferrers[i], summary.TopReferrers[j]
+		if a.Hired != b.Hired {
+			return a.Hired > b.Hired
+		}
+		return a.Referrals > b.Referrals
+	})
+	if len(summary.TopReferrers) > top {
+		summary.TopReferrers = summary.TopReferrers[:top]
+	}
+	return summary
+}
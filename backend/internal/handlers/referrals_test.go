@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// testReferral is a referral by referrer that led to an application with
+// status, or to none if status is empty
+func testReferral(referrer, status string) map[string]interface{} {
+	referral := map[string]interface{}{
+		"id":       "ref-" + referrer,
+		"referrer": map[string]interface{}{"id": referrer, "name": strings.ToUpper(referrer)},
+	}
+	if status != "" {
+		referral["application"] = map[string]interface{}{"id": "app-" + referrer, "status": status}
+	}
+	return referral
+}
+
+// referralsFake answers GetReferralsQuery with referrals, a page at a time
+func referralsFake(referrals []interface{}) func(gateway.GraphQLRequest) interface{} {
+	return func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.GetReferralsQuery {
+			return map[string]interface{}{}
+		}
+		offset, _ := req.Variables["offset"].(float64)
+		limit, _ := req.Variables["limit"].(float64)
+		page := []interface{}{}
+		for i := int(offset); i < min(int(offset+limit), len(referrals)); i++ {
+			page = append(page, referrals[i])
+		}
+		return map[string]interface{}{"referrals": page}
+	}
+}
+
+func TestReferralHandler_CreateReferral(t *testing.T) {
+	fake, client := newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.CreateReferralMutation {
+			return map[string]interface{}{}
+		}
+		input, _ := req.Variables["input"].(map[string]interface{})
+		if input["jobId"] == "closed-job" {
+			return &gateway.GraphQLResponse{Errors: []gateway.GraphQLError{{Message: "jobId is invalid: job is closed"}}}
+		}
+		referral := map[string]interface{}{"id": "ref-1"}
+		for field, value := range input {
+			referral[field] = value
+		}
+		return map[string]interface{}{"createReferral": referral}
+	})
+	h := NewReferralHandler(client)
+
+	create := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		h.CreateReferral(rec, httptest.NewRequest(http.MethodPost, "/api/v1/referrals", strings.NewReader(body)))
+		return rec
+	}
+
+	t.Run("created", func(t *testing.T) {
+		rec := create(`{"jobId":"job-1","referrerId":"emp-1","candidateEmail":" Ada@Example.com ",` +
+			`"candidateFirstName":" Ada ","candidateLastName":"Lovelace"}`)
+		if rec.Code != http.StatusCreated {
+			t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+		}
+		var referral map[string]interface{}
+		if err := json.NewDecoder(rec.Body).Decode(&referral); err != nil {
+			t.Fatal(err)
+		}
+		if referral["id"] != "ref-1" {
+			t.Fatalf("referral = %v, want ref-1", referral)
+		}
+
+		fake.mu.Lock()
+		defer fake.mu.Unlock()
+		input, _ := fake.requests[len(fake.requests)-1].Variables["input"].(map[string]interface{})
+		want := map[string]interface{}{
+			"jobId": "job-1", "referrerId": "emp-1", "candidateEmail": "ada@example.com",
+			"candidateFirstName": "Ada", "candidateLastName": "Lovelace",
+		}
+		if !reflect.DeepEqual(input, want) {
+			t.Fatalf("input = %v, want %v", input, want)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		body     string
+		wantBody string
+	}{
+		{name: "malformed body", body: `{`, wantBody: "Invalid request body"},
+		{name: "no job", body: `{"referrerId":"emp-1","candidateEmail":"ada@example.com","candidateFirstName":"Ada","candidateLastName":"Lovelace"}`, wantBody: "jobId is required"},
+		{name: "no referrer", body: `{"jobId":"job-1","candidateEmail":"ada@example.com","candidateFirstName":"Ada","candidateLastName":"Lovelace"}`, wantBody: "referrerId is required"},
+		{name: "blank name", body: `{"jobId":"job-1","referrerId":"emp-1","candidateEmail":"ada@example.com","candidateFirstName":" ","candidateLastName":"Lovelace"}`, wantBody: "first and last name are required"},
+		{name: "bad email", body: `{"jobId":"job-1","referrerId":"emp-1","candidateEmail":"ada","candidateFirstName":"Ada","candidateLastName":"Lovelace"}`, wantBody: "candidateEmail must be a valid email address"},
+		{name: "rejected by Hub-HRMS", body: `{"jobId":"closed-job","referrerId":"emp-1","candidateEmail":"ada@example.com","candidateFirstName":"Ada","candidateLastName":"Lovelace"}`, wantBody: "job is closed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := create(tt.body)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("body = %s, want %q", rec.Body, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestSummarizeReferrals(t *testing.T) {
+	referrals := []interface{}{
+		// ada: 3 referrals, 2 applied, 1 hired
+		testReferral("ada", "HIRED"),
+		testReferral("ada", "INTERVIEW"),
+		testReferral("ada", ""),
+		// bob: 4 referrals, 2 applied, 2 hired
+		testReferral("bob", "HIRED"),
+		testReferral("bob", "HIRED"),
+		testReferral("bob", ""),
+		testReferral("bob", ""),
+		// cy: 1 referral that did not apply
+		testReferral("cy", ""),
+		// dee: 2 referrals, 1 applied and rejected
+		testReferral("dee", "REJECTED"),
+		testReferral("dee", ""),
+		// a referral without a referrer counts towards the totals only
+		map[string]interface{}{"id": "ref-anonymous", "application": map[string]interface{}{"status": "NEW"}},
+	}
+
+	summary := summarizeReferrals(referrals, 3)
+
+	wantTotals := referralFunnel{Referrals: 11, Applied: 6, Hired: 3, ApplicationRate: 6.0 / 11, HireRate: 0.5}
+	if summary.Totals != wantTotals {
+		t.Fatalf("totals = %+v, want %+v", summary.Totals, wantTotals)
+	}
+
+	// Ranked by hires, then referrals, and cut to the top three
+	want := []referrerFunnel{
+		{ReferrerID: "bob", Name: "BOB", referralFunnel: referralFunnel{Referrals: 4, Applied: 2, Hired: 2, ApplicationRate: 0.5, HireRate: 1}},
+		{ReferrerID: "ada", Name: "ADA", referralFunnel: referralFunnel{Referrals: 3, Applied: 2, Hired: 1, ApplicationRate: 2.0 / 3, HireRate: 0.5}},
+		{ReferrerID: "dee", Name: "DEE", referralFunnel: referralFunnel{Referrals: 2, Applied: 1, ApplicationRate: 0.5}},
+	}
+	if !reflect.DeepEqual(summary.TopReferrers, want) {
+		t.Fatalf("top referrers = %+v, want %+v", summary.TopReferrers, want)
+	}
+
+	t.Run("no referrals", func(t *testing.T) {
+		summary := summarizeReferrals(nil, 3)
+		if summary.Totals != (referralFunnel{}) || summary.TopReferrers == nil || len(summary.TopReferrers) != 0 {
+			t.Fatalf("summary = %+v, want zero rates and an empty ranking", summary)
+		}
+	})
+}
+
+func TestAnalyticsHandler_GetReferrals(t *testing.T) {
+	// More referrals than one page, so every page must be fetched
+	referrals := make([]interface{}, 0, referralPageSize+10)
+	for i := 0; i < referralPageSize+10; i++ {
+		status := ""
+		if i%2 == 0 {
+			status = "NEW"
+		}
+		referrals = append(referrals, testReferral(fmt.Sprintf("emp-%d", i%20), status))
+	}
+	h, fake := newTestAnalyticsHandler(t, referralsFake(referrals), "")
+
+	rec := httptest.NewRecorder()
+	h.GetReferrals(rec, httptest.NewRequest(http.MethodGet, "/api/v1/analytics/referrals?startDate=2024-01-01&endDate=2024-03-31&jobId=job-1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+
+	var body struct {
+		StartDate    string           `json:"startDate"`
+		EndDate      string           `json:"endDate"`
+		Totals       referralFunnel   `json:"totals"`
+		TopReferrers []referrerFunnel `json:"topReferrers"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatal(err)
+	}
+	if body.StartDate != "2024-01-01" || body.EndDate != "2024-03-31" {
+		t.Fatalf("range = %s to %s, want the requested range", body.StartDate, body.EndDate)
+	}
+	if body.Totals.Referrals != len(referrals) || body.Totals.ApplicationRate != 0.5 {
+		t.Fatalf("totals = %+v, want %d referrals with half applied", body.Totals, len(referrals))
+	}
+	if len(body.TopReferrers) != topReferrers {
+		t.Fatalf("got %d top referrers, want %d", len(body.TopReferrers), topReferrers)
+	}
+
+	if n := fake.sent(gateway.GetReferralsQuery); n != 2 {
+		t.Fatalf("GetReferralsQuery sent %d times, want 2", n)
+	}
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	filters, _ := fake.requests[0].Variables["filters"].(map[string]interface{})
+	if filters["jobId"] != "job-1" || filters["dateRange"] == nil {
+		t.Fatalf("filters = %v, want the job and date range", filters)
+	}
+}
+
+func TestReferralHandler_GetReferrerHistory(t *testing.T) {
+	fake, client := newFakeHubHRMS(t, referralsFake([]interface{}{
+		testReferral("emp-1", "HIRED"),
+		testReferral("emp-1", ""),
+	}))
+	h := NewReferralHandler(client)
+	router := chi.NewRouter()
+	router.Get("/referrals/{referrerId}", h.GetReferrerHistory)
+
+	tests := []struct {
+		name       string
+		user       string
+		roles      []string
+		wantStatus int
+	}{
+		{name: "own history", user: "emp-1", wantStatus: http.StatusOK},
+		{name: "recruiter", user: "rec-1", roles: []string{"recruiter"}, wantStatus: http.StatusOK},
+		{name: "another employee", user: "emp-2", wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, asUser(httptest.NewRequest(http.MethodGet, "/referrals/emp-1", nil), tt.user, tt.roles...))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var body struct {
+				ReferrerID string         `json:"referrerId"`
+				Summary    referralFunnel `json:"summary"`
+				Referrals  []interface{}  `json:"referrals"`
+			}
+			if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+				t.Fatal(err)
+			}
+			want := referralFunnel{Referrals: 2, Applied: 1, Hired: 1, ApplicationRate: 0.5, HireRate: 1}
+			if body.ReferrerID != "emp-1" || body.Summary != want || len(body.Referrals) != 2 {
+				t.Fatalf("body = %+v, want emp-1's two referrals with %+v", body, want)
+			}
+		})
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	for _, req := range fake.requests {
+		filters, _ := req.Variables["filters"].(map[string]interface{})
+		if filters["referrerId"] != "emp-1" {
+			t.Fatalf("filters = %v, want emp-1's referrals", filters)
+		}
+	}
+}
+
+func TestApplicationHandler_SubmitApplication_Referral(t *testing.T) {
+	h, fake, _ := newTestApplicationHandler(t, submitApplicationFake())
+
+	var input map[string]interface{}
+	json.Unmarshal([]byte(testApplication("ada@example.com")), &input)
+	input["referralId"] = "ref-1"
+	body, _ := json.Marshal(input)
+	rec := httptest.NewRecorder()
+	h.SubmitApplication(rec, httptest.NewRequest(http.MethodPost, "/applications", strings.NewReader(string(body))))
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusCreated, rec.Body)
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	for _, req := range fake.requests {
+		if req.Query != gateway.SubmitApplicationMutation {
+			continue
+		}
+		if input, _ := req.Variables["input"].(map[string]interface{}); input["referralId"] != "ref-1" {
+			t.Fatalf("input = %v, want referralId ref-1", input)
+		}
+		return
+	}
+	t.Fatal("SubmitApplicationMutation not sent")
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/services"
+)
+
+// recordScoreOverride records a status change that went against the AI
+// recommendation as disagreement with the score. It runs after the response,
+// so failures are only logged.
+func (h *ApplicationHandler) recordScoreOverride(appID, recommendation, status, submittedBy string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	err := h.feedback.Submit(ctx, services.ScoringFeedback{
+		ApplicationID:  appID,
+		OverrideReason: fmt.Sprintf("Status changed to %s against recommendation %s", status, recommendation),
+		Automatic:      true,
+		SubmittedBy:    submittedBy,
+	})
+	if err != nil {
+		h.logger.Warn("failed to record scoring feedback", "application_id", appID, "error", err)
+	}
+}
+
+// SubmitScoringFeedback records whether a recruiter agrees with an
+// application's AI score. Disagreeing needs an overrideReason.
+func (h *ApplicationHandler) SubmitScoringFeedback(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	appID := chi.URLParam(r, "id")
+
+	if appID == "" {
+		respondError(w, http.StatusBadRequest, "Application ID is required", nil)
+		return
+	}
+
+	var input struct {
+		AgreesWithScore *bool  `json:"agreesWithScore"`
+		OverrideReason  string `json:"overrideReason"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if input.AgreesWithScore == nil {
+		respondError(w, http.StatusBadRequest, "agreesWithScore is required", nil)
+		return
+	}
+
+	feedback := services.ScoringFeedback{
+		ApplicationID:   appID,
+		AgreesWithScore: *input.AgreesWithScore,
+		OverrideReason:  strings.TrimSpace(input.OverrideReason),
+		SubmittedBy:     userID(ctx),
+	}
+	if err := h.feedback.Submit(ctx, feedback); err != nil {
+		if errors.Is(err, services.ErrInvalidScoringFeedback) {
+			respondError(w, http.StatusBadRequest, err.Error(), nil)
+			return
+		}
+		respondError(w, http.StatusInternalServerError, "Failed to submit scoring feedback", err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, feedback)
+}
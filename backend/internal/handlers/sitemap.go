@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"hr-recruiting/internal/gateway"
+)
+
+const (
+	// sitemapMaxURLs is the most jobs listed in one sitemap; beyond it
+	// /sitemap.xml becomes an index of numbered pages
+	sitemapMaxURLs = 1000
+	// sitemapFetchSize is how many jobs are fetched per query
+	sitemapFetchSize = 100
+)
+
+const sitemapNamespace = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// URLSet is a sitemap listing pages on the careers site
+type URLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	URLs    []SitemapURL `xml:"url"`
+}
+
+// SitemapURL is one page in a sitemap
+type SitemapURL struct {
+	Loc        string `xml:"loc"`
+	LastMod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq"`
+	Priority   string `xml:"priority"`
+}
+
+// SitemapIndex lists the pages of a sitemap too large for one file
+type SitemapIndex struct {
+	XMLName  xml.Name       `xml:"sitemapindex"`
+	Xmlns    string         `xml:"xmlns,attr"`
+	Sitemaps []SitemapEntry `xml:"sitemap"`
+}
+
+// SitemapEntry is one page of a sitemap index
+type SitemapEntry struct {
+	Loc string `xml:"loc"`
+}
+
+// Sitemap serves published jobs as an XML sitemap for search engines. Up to
+// sitemapMaxURLs jobs are listed directly; past that the sitemap is an index
+// of pages served as /sitemap.xml?page=N.
+func (h *JobHandler) Sitemap(w http.ResponseWriter, r *http.Request) {
+	urls, err := h.sitemapURLs(r)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch jobs", err)
+		return
+	}
+
+	pages := (len(urls) + sitemapMaxURLs - 1) / sitemapMaxURLs
+	var document interface{}
+	if pageParam := r.URL.Query().Get("page"); pageParam != "" {
+		page, err := strconv.Atoi(pageParam)
+		if err != nil || page < 1 || page > pages {
+			respondError(w, http.StatusNotFound, "Sitemap page not found", nil)
+			return
+		}
+		end := min(page*sitemapMaxURLs, len(urls))
+		document = URLSet{Xmlns: sitemapNamespace, URLs: urls[(page-1)*sitemapMaxURLs : end]}
+	} else if len(urls) > sitemapMaxURLs {
+		index := SitemapIndex{Xmlns: sitemapNamespace, Sitemaps: make([]SitemapEntry, pages)}
+		for i := range index.Sitemaps {
+			index.Sitemaps[i].Loc = fmt.Sprintf("%s/sitemap.xml?page=%d", h.site.BaseURL, i+1)
+		}
+		document = index
+	} else {
+		document = URLSet{Xmlns: sitemapNamespace, URLs: urls}
+	}
+
+	w.Header().Set("Content-Type", "application/xml; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(xml.Header))
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(document); err != nil {
+		h.logger.Error("failed to encode sitemap", "error", err)
+	}
+}
+
+// sitemapURLs pages through every published job, returning the public URL
+// of each
+func (h *JobHandler) sitemapURLs(r *http.Request) ([]SitemapURL, error) {
+	var urls []SitemapURL
+	for offset := 0; ; offset += sitemapFetchSize {
+		resp, err := h.client.Query(r.Context(), gateway.GetSitemapJobsQuery, map[string]interface{}{
+			"filters": map[string]interface{}{"status": "PUBLISHED"},
+			"limit":   sitemapFetchSize,
+			"offset":  offset,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		jobs, _ := lookup(resp.Data, "jobs").([]interface{})
+		for _, job := range jobs {
+			h.addCanonicalURL(job)
+			loc := lookupString(job, "canonicalUrl")
+			if loc == "" {
+				continue
+			}
+			entry := SitemapURL{Loc: loc, ChangeFreq: "weekly", Priority: "0.8"}
+			if updated := lookupString(job, "updatedAt"); updated != "" {
+				entry.LastMod = parseTimestamp(updated).Format("2006-01-02")
+			}
+			urls = append(urls, entry)
+		}
+
+		if len(jobs) < sitemapFetchSize {
+			return urls, nil
+		}
+	}
+}
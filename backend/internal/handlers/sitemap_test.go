@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/middleware"
+)
+
+// sitemapFake serves n published jobs through GetSitemapJobsQuery, a page
+// at a time. Every third job has no slug and is linked by ID.
+func sitemapFake(n int) func(gateway.GraphQLRequest) interface{} {
+	return func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.GetSitemapJobsQuery {
+			return map[string]interface{}{}
+		}
+		offset, _ := req.Variables["offset"].(float64)
+		limit, _ := req.Variables["limit"].(float64)
+		jobs := []interface{}{}
+		for i := int(offset); i < min(int(offset+limit), n); i++ {
+			job := map[string]interface{}{
+				"id":        fmt.Sprintf("job-%d", i),
+				"updatedAt": "2024-03-05T10:00:00Z",
+			}
+			if i%3 != 0 {
+				job["slug"] = fmt.Sprintf("backend-engineer-%d", i)
+			}
+			jobs = append(jobs, job)
+		}
+		return map[string]interface{}{"jobs": jobs}
+	}
+}
+
+// getSitemap requests the sitemap at target
+func getSitemap(h http.Handler, target string) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+	return rec
+}
+
+func TestJobHandler_Sitemap(t *testing.T) {
+	h, fake := newTestJobHandler(t, sitemapFake(250))
+
+	rec := getSitemap(http.HandlerFunc(h.Sitemap), "/sitemap.xml")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/xml") {
+		t.Fatalf("Content-Type = %q, want application/xml", ct)
+	}
+	if !strings.HasPrefix(rec.Body.String(), xml.Header) {
+		t.Fatal("sitemap does not start with an XML declaration")
+	}
+
+	var urlset URLSet
+	if err := xml.Unmarshal(rec.Body.Bytes(), &urlset); err != nil {
+		t.Fatalf("sitemap is not well-formed: %v", err)
+	}
+	if urlset.XMLName.Local != "urlset" || urlset.XMLName.Space != sitemapNamespace {
+		t.Fatalf("root = %+v, want urlset in %s", urlset.XMLName, sitemapNamespace)
+	}
+
+	// 250 jobs take three fetches of 100
+	if n := fake.sent(gateway.GetSitemapJobsQuery); n != 3 {
+		t.Fatalf("GetSitemapJobsQuery sent %d times, want 3", n)
+	}
+	if len(urlset.URLs) != 250 {
+		t.Fatalf("sitemap lists %d URLs, want 250", len(urlset.URLs))
+	}
+	want := []SitemapURL{
+		{Loc: "https://careers.example.com/jobs/job-0", LastMod: "2024-03-05", ChangeFreq: "weekly", Priority: "0.8"},
+		{Loc: "https://careers.example.com/jobs/backend-engineer-1", LastMod: "2024-03-05", ChangeFreq: "weekly", Priority: "0.8"},
+	}
+	for i, entry := range want {
+		if urlset.URLs[i] != entry {
+			t.Errorf("URL %d = %+v, want %+v", i, urlset.URLs[i], entry)
+		}
+	}
+
+	fake.mu.Lock()
+	defer fake.mu.Unlock()
+	for _, req := range fake.requests {
+		filters, _ := req.Variables["filters"].(map[string]interface{})
+		if filters["status"] != "PUBLISHED" {
+			t.Fatalf("filters = %v, want only published jobs", filters)
+		}
+	}
+}
+
+func TestJobHandler_Sitemap_Index(t *testing.T) {
+	h, _ := newTestJobHandler(t, sitemapFake(2500))
+	handler := http.HandlerFunc(h.Sitemap)
+
+	rec := getSitemap(handler, "/sitemap.xml")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var index SitemapIndex
+	if err := xml.Unmarshal(rec.Body.Bytes(), &index); err != nil {
+		t.Fatalf("sitemap index is not well-formed: %v", err)
+	}
+	if index.XMLName.Local != "sitemapindex" {
+		t.Fatalf("root = %q, want sitemapindex", index.XMLName.Local)
+	}
+	if len(index.Sitemaps) != 3 {
+		t.Fatalf("index lists %d sitemaps, want 3", len(index.Sitemaps))
+	}
+	for i, entry := range index.Sitemaps {
+		if want := fmt.Sprintf("https://careers.example.com/sitemap.xml?page=%d", i+1); entry.Loc != want {
+			t.Errorf("sitemap %d = %q, want %q", i, entry.Loc, want)
+		}
+	}
+
+	tests := []struct {
+		page       string
+		wantStatus int
+		wantURLs   int
+	}{
+		{page: "1", wantStatus: http.StatusOK, wantURLs: 1000},
+		{page: "3", wantStatus: http.StatusOK, wantURLs: 500},
+		{page: "4", wantStatus: http.StatusNotFound},
+		{page: "0", wantStatus: http.StatusNotFound},
+		{page: "last", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run("page "+tt.page, func(t *testing.T) {
+			rec := getSitemap(handler, "/sitemap.xml?page="+tt.page)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+			var urlset URLSet
+			if err := xml.Unmarshal(rec.Body.Bytes(), &urlset); err != nil {
+				t.Fatalf("sitemap page is not well-formed: %v", err)
+			}
+			if len(urlset.URLs) != tt.wantURLs {
+				t.Fatalf("page lists %d URLs, want %d", len(urlset.URLs), tt.wantURLs)
+			}
+		})
+	}
+}
+
+func TestJobHandler_Sitemap_Cached(t *testing.T) {
+	h, fake := newTestJobHandler(t, sitemapFake(10))
+	handler := middleware.NewResponseCache(16, time.Hour).Middleware(http.HandlerFunc(h.Sitemap))
+
+	first := getSitemap(handler, "/sitemap.xml")
+	second := getSitemap(handler, "/sitemap.xml")
+	if first.Code != http.StatusOK || second.Code != http.StatusOK {
+		t.Fatalf("statuses = %d, %d, want 200", first.Code, second.Code)
+	}
+	if first.Body.String() != second.Body.String() {
+		t.Fatal("cached sitemap differs from the first response")
+	}
+	if n := fake.sent(gateway.GetSitemapJobsQuery); n != 1 {
+		t.Fatalf("GetSitemapJobsQuery sent %d times, want 1", n)
+	}
+}
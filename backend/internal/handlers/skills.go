@@ -0,0 +1,23 @@
+package handlers
+
+import (
+	"net/http"
+
+	"hr-recruiting/internal/services"
+)
+
+// SkillHandler serves the skills taxonomy
+type SkillHandler struct {
+	normalizer *services.SkillNormalizer
+}
+
+// NewSkillHandler creates a new skill handler
+func NewSkillHandler(normalizer *services.SkillNormalizer) *SkillHandler {
+	return &SkillHandler{normalizer: normalizer}
+}
+
+// ListSkills returns every canonical skill with its synonyms, for
+// autocomplete on the application form
+func (h *SkillHandler) ListSkills(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.normalizer.Taxonomy())
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/middleware"
+	"hr-recruiting/internal/services"
+)
+
+// SubscriptionHandler manages outbound webhook subscriptions
+type SubscriptionHandler struct {
+	webhooks *services.WebhookService
+}
+
+// NewSubscriptionHandler creates a new subscription handler
+func NewSubscriptionHandler(webhooks *services.WebhookService) *SubscriptionHandler {
+	return &SubscriptionHandler{webhooks: webhooks}
+}
+
+// RegisterWebhook subscribes a URL to one or more event types
+func (h *SubscriptionHandler) RegisterWebhook(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		URL    string   `json:"url"`
+		Events []string `json:"events"`
+		Secret string   `json:"secret,omitempty"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	var createdBy string
+	if user, ok := middleware.GetUserFromContext(r.Context()); ok {
+		createdBy, _ = user["id"].(string)
+	}
+
+	sub, err := h.webhooks.Register(input.URL, input.Events, input.Secret, createdBy)
+	if errors.Is(err, services.ErrInvalidWebhook) {
+		respondError(w, http.StatusBadRequest, "Invalid webhook", err)
+		return
+	}
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to register webhook", err)
+		return
+	}
+
+	respondJSON(w, http.StatusCreated, sub)
+}
+
+// ListWebhooks lists registered subscriptions
+func (h *SubscriptionHandler) ListWebhooks(w http.ResponseWriter, r *http.Request) {
+	respondJSON(w, http.StatusOK, h.webhooks.List())
+}
+
+// UnregisterWebhook removes a subscription
+func (h *SubscriptionHandler) UnregisterWebhook(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+
+	if err := h.webhooks.Unregister(id); err != nil {
+		respondError(w, http.StatusNotFound, "Webhook not found", err)
+		return
+	}
+
+	respondSuccess(w, "Webhook deleted successfully", nil)
+}
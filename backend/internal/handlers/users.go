@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"hr-recruiting/internal/middleware"
+	"hr-recruiting/internal/services"
+)
+
+// UserHandler handles requests about the signed-in user
+type UserHandler struct {
+	preferences *services.NotificationPreferenceStore
+}
+
+// NewUserHandler creates a new user handler
+func NewUserHandler(preferences *services.NotificationPreferenceStore) *UserHandler {
+	return &UserHandler{preferences: preferences}
+}
+
+// GetNotificationPreferences returns which emails the user's actions send
+func (h *UserHandler) GetNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	id := userID(r.Context())
+	if id == "" {
+		respondError(w, http.StatusUnauthorized, "User ID missing from credentials", nil)
+		return
+	}
+
+	preference, err := h.preferences.Get(r.Context(), id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch notification preferences", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, preference)
+}
+
+// UpdateNotificationPreferences changes the user's preferences. Fields left
+// out of the body keep their current value.
+func (h *UserHandler) UpdateNotificationPreferences(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	id := userID(ctx)
+	if id == "" {
+		respondError(w, http.StatusUnauthorized, "User ID missing from credentials", nil)
+		return
+	}
+
+	var input struct {
+		StatusChanges      *bool `json:"statusChanges"`
+		NewApplications    *bool `json:"newApplications"`
+		ScoreReady         *bool `json:"scoreReady"`
+		InterviewReminders *bool `json:"interviewReminders"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	preference, err := h.preferences.Get(ctx, id)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch notification preferences", err)
+		return
+	}
+	for field, value := range map[*bool]*bool{
+		&preference.StatusChanges:      input.StatusChanges,
+		&preference.NewApplications:    input.NewApplications,
+		&preference.ScoreReady:         input.ScoreReady,
+		&preference.InterviewReminders: input.InterviewReminders,
+	} {
+		if value != nil {
+			*field = *value
+		}
+	}
+
+	preference, err = h.preferences.Update(ctx, id, preference)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to update notification preferences", err)
+		return
+	}
+
+	respondJSON(w, http.StatusOK, preference)
+}
+
+// userID returns the authenticated user's ID, or "" if there is none
+func userID(ctx context.Context) string {
+	user, ok := middleware.GetUserFromContext(ctx)
+	if !ok {
+		return ""
+	}
+	id, _ := user["id"].(string)
+	return id
+}
@@ -0,0 +1,143 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"hr-recruiting/internal/config"
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/services"
+)
+
+// preferencesFake answers the preference query and mutation from a map of
+// user ID to saved preferences; users missing from it have none saved
+func preferencesFake(saved map[string]services.NotificationPreference) func(gateway.GraphQLRequest) interface{} {
+	return func(req gateway.GraphQLRequest) interface{} {
+		userID, _ := req.Variables["userId"].(string)
+		switch req.Query {
+		case gateway.GetNotificationPreferencesQuery:
+			preference, ok := saved[userID]
+			if !ok {
+				return map[string]interface{}{"notificationPreferences": nil}
+			}
+			return map[string]interface{}{"notificationPreferences": preference}
+		case gateway.UpdateNotificationPreferencesMutation:
+			return map[string]interface{}{"updateNotificationPreferences": req.Variables["input"]}
+		}
+		return map[string]interface{}{}
+	}
+}
+
+func TestUserHandler_NotificationPreferences(t *testing.T) {
+	saved := map[string]services.NotificationPreference{
+		"quiet": {StatusChanges: false, NewApplications: true, ScoreReady: false, InterviewReminders: true},
+	}
+	fake, client := newFakeHubHRMS(t, preferencesFake(saved))
+	h := NewUserHandler(services.NewNotificationPreferenceStore(client, time.Minute))
+
+	get := func(id string) (int, services.NotificationPreference) {
+		rec := httptest.NewRecorder()
+		h.GetNotificationPreferences(rec, asUser(httptest.NewRequest(http.MethodGet, "/users/me/notifications", nil), id))
+		var preference services.NotificationPreference
+		json.NewDecoder(rec.Body).Decode(&preference)
+		return rec.Code, preference
+	}
+
+	t.Run("saved preferences", func(t *testing.T) {
+		status, preference := get("quiet")
+		if status != http.StatusOK || preference != saved["quiet"] {
+			t.Fatalf("GetNotificationPreferences() = %d %+v, want %+v", status, preference, saved["quiet"])
+		}
+	})
+
+	t.Run("defaults without saved preferences", func(t *testing.T) {
+		status, preference := get("new")
+		if status != http.StatusOK || preference != services.DefaultNotificationPreference() {
+			t.Fatalf("GetNotificationPreferences() = %d %+v, want defaults", status, preference)
+		}
+	})
+
+	t.Run("cached", func(t *testing.T) {
+		before := fake.sent(gateway.GetNotificationPreferencesQuery)
+		get("quiet")
+		if after := fake.sent(gateway.GetNotificationPreferencesQuery); after != before {
+			t.Fatalf("preferences fetched again within the cache TTL")
+		}
+	})
+
+	t.Run("partial update keeps other fields", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPut, "/users/me/notifications", strings.NewReader(`{"scoreReady":true}`))
+		h.UpdateNotificationPreferences(rec, asUser(req, "quiet"))
+
+		want := saved["quiet"]
+		want.ScoreReady = true
+		var preference services.NotificationPreference
+		json.NewDecoder(rec.Body).Decode(&preference)
+		if rec.Code != http.StatusOK || preference != want {
+			t.Fatalf("UpdateNotificationPreferences() = %d %+v, want %+v", rec.Code, preference, want)
+		}
+	})
+
+	t.Run("anonymous", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.GetNotificationPreferences(rec, httptest.NewRequest(http.MethodGet, "/users/me/notifications", nil))
+		if rec.Code != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", rec.Code, http.StatusUnauthorized)
+		}
+	})
+}
+
+func TestApplicationHandler_NotifyRespectsPreferences(t *testing.T) {
+	saved := map[string]services.NotificationPreference{
+		"no-status-emails": {StatusChanges: false, NewApplications: true, ScoreReady: true, InterviewReminders: true},
+	}
+	_, client := newFakeHubHRMS(t, preferencesFake(saved))
+	features := &config.FeatureFlags{}
+	features.EmailNotifications.Store(true)
+
+	email := services.StatusUpdateEmail("ada@example.com", "Ada", "Engineer", "INTERVIEW")
+	tests := []struct {
+		name     string
+		userID   string
+		kind     services.NotificationType
+		disabled bool
+		wantSent bool
+	}{
+		{name: "preference disabled", userID: "no-status-emails", kind: services.NotifyStatusChanges, wantSent: false},
+		{name: "other preference enabled", userID: "no-status-emails", kind: services.NotifyInterviewReminders, wantSent: true},
+		{name: "no saved preferences", userID: "new", kind: services.NotifyStatusChanges, wantSent: true},
+		{name: "no signed-in user", kind: services.NotifyStatusChanges, wantSent: true},
+		{name: "notifications switched off", userID: "new", kind: services.NotifyStatusChanges, disabled: true, wantSent: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := &recordingEmailQueue{}
+			flags := features
+			if tt.disabled {
+				flags = &config.FeatureFlags{}
+			}
+			h := &ApplicationHandler{
+				emailQueue:  queue,
+				features:    flags,
+				preferences: services.NewNotificationPreferenceStore(client, time.Minute),
+			}
+
+			ctx := context.Background()
+			if tt.userID != "" {
+				ctx = asUser(httptest.NewRequest(http.MethodGet, "/", nil), tt.userID).Context()
+			}
+			h.notify(ctx, tt.kind, email)
+
+			if sent := len(queue.enqueued()) == 1; sent != tt.wantSent {
+				t.Fatalf("email sent = %v, want %v", sent, tt.wantSent)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package v2
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/handlers"
+	"hr-recruiting/internal/models"
+)
+
+// ApplicationHandler serves applications to recruiters
+type ApplicationHandler struct {
+	client *gateway.HubHRMSClient
+}
+
+// NewApplicationHandler creates a new v2 application handler
+func NewApplicationHandler(client *gateway.HubHRMSClient) *ApplicationHandler {
+	return &ApplicationHandler{client: client}
+}
+
+// ApplicationListParams are the filters accepted when listing applications
+type ApplicationListParams struct {
+	JobID    string
+	Status   string
+	DateFrom *time.Time
+	DateTo   *time.Time
+	MinScore *float64
+}
+
+// parseApplicationListParams reads application filters from the query
+// string. Unlike v1, malformed dates and scores are rejected rather than
+// ignored.
+func parseApplicationListParams(r *http.Request) (ApplicationListParams, error) {
+	q := r.URL.Query()
+	params := ApplicationListParams{JobID: q.Get("jobId"), Status: q.Get("status")}
+
+	for name, dst := range map[string]**time.Time{"dateFrom": &params.DateFrom, "dateTo": &params.DateTo} {
+		if value := q.Get(name); value != "" {
+			t, err := time.Parse("2006-01-02", value)
+			if err != nil {
+				return params, err
+			}
+			*dst = &t
+		}
+	}
+	if value := q.Get("minScore"); value != "" {
+		score, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return params, err
+		}
+		params.MinScore = &score
+	}
+	return params, nil
+}
+
+// filters builds the Hub-HRMS ApplicationFilters input
+func (p ApplicationListParams) filters() map[string]interface{} {
+	filters := make(map[string]interface{})
+	if p.JobID != "" {
+		filters["jobId"] = p.JobID
+	}
+	if p.Status != "" {
+		filters["status"] = p.Status
+	}
+	if p.DateFrom != nil {
+		filters["dateFrom"] = p.DateFrom.Format("2006-01-02")
+	}
+	if p.DateTo != nil {
+		filters["dateTo"] = p.DateTo.Format("2006-01-02")
+	}
+	if p.MinScore != nil {
+		filters["minScore"] = *p.MinScore
+	}
+	return filters
+}
+
+// ListApplications returns a page of applications with the total matching
+func (h *ApplicationHandler) ListApplications(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := handlers.ParsePagination(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid pagination cursor", err)
+		return
+	}
+	params, err := parseApplicationListParams(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid filter", err)
+		return
+	}
+	filters := params.filters()
+
+	results, err := h.client.Batch(r.Context(), []gateway.BatchRequest{
+		{Key: "items", Query: gateway.GetApplicationsQuery, Variables: map[string]interface{}{
+			"filters": filters,
+			"limit":   limit,
+			"offset":  offset,
+		}},
+		{Key: "count", Query: gateway.CountApplicationsQuery, Variables: map[string]interface{}{
+			"filters": filters,
+		}},
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch applications", err)
+		return
+	}
+
+	applications := []models.Application{}
+	if _, err := decodeField(results["items"], "applications", &applications); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch applications", err)
+		return
+	}
+
+	total := decodeCount(results["count"], "applicationsCount")
+	respondJSON(w, http.StatusOK, models.Page[models.Application]{
+		Items:   applications,
+		Total:   total,
+		Limit:   limit,
+		Offset:  offset,
+		HasMore: offset+len(applications) < total,
+	})
+}
+
+// GetApplication returns a single application with its notes
+func (h *ApplicationHandler) GetApplication(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.Query(r.Context(), gateway.GetApplicationQuery, map[string]interface{}{
+		"id": chi.URLParam(r, "id"),
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch application", err)
+		return
+	}
+
+	var application models.Application
+	found, err := decodeField(resp, "application", &application)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch application", err)
+		return
+	}
+	if !found {
+		notFound(w, "Application")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, application)
+}
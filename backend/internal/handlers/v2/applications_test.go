@@ -0,0 +1,150 @@
+package v2
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/models"
+)
+
+func TestParseApplicationListParams(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		wantFilters map[string]interface{}
+		wantErr     bool
+	}{
+		{name: "none", wantFilters: map[string]interface{}{}},
+		{
+			name:  "every filter",
+			query: "jobId=job-1&status=SCREENING&dateFrom=2026-10-01&dateTo=2026-10-31&minScore=7.5",
+			wantFilters: map[string]interface{}{
+				"jobId": "job-1", "status": "SCREENING", "dateFrom": "2026-10-01", "dateTo": "2026-10-31", "minScore": 7.5,
+			},
+		},
+		{name: "malformed date", query: "dateFrom=01/10/2026", wantErr: true},
+		{name: "malformed score", query: "minScore=high", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params, err := parseApplicationListParams(httptest.NewRequest(http.MethodGet, "/api/v2/applications?"+tt.query, nil))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseApplicationListParams() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if filters := params.filters(); !reflect.DeepEqual(filters, tt.wantFilters) {
+				t.Fatalf("filters() = %v, want %v", filters, tt.wantFilters)
+			}
+		})
+	}
+}
+
+func TestApplicationHandler_ListApplications(t *testing.T) {
+	fake, client := newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query == gateway.CountApplicationsQuery {
+			return map[string]interface{}{"applicationsCount": 3}
+		}
+		return map[string]interface{}{"applications": []interface{}{
+			map[string]interface{}{
+				"id": "app-1", "status": "SCREENING", "appliedDate": "2026-10-01T09:00:00Z", "lastUpdated": "2026-10-02T09:00:00Z",
+				"candidate":         map[string]interface{}{"id": "cand-1", "firstName": "Ada", "lastName": "Lovelace", "email": "ada@example.com"},
+				"job":               map[string]interface{}{"id": "job-1", "title": "Backend Engineer"},
+				"yearsOfExperience": 6,
+				"aiScore":           map[string]interface{}{"overall": 8.5, "recommendation": "INTERVIEW"},
+			},
+			map[string]interface{}{"id": "app-2", "status": "NEW", "appliedDate": "2026-10-03T09:00:00Z", "lastUpdated": "2026-10-03T09:00:00Z"},
+		}}
+	})
+	h := NewApplicationHandler(client)
+
+	rec := httptest.NewRecorder()
+	h.ListApplications(rec, httptest.NewRequest(http.MethodGet, "/api/v2/applications?limit=2&jobId=job-1&minScore=7", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+
+	var page models.Page[models.Application]
+	decodeJSON(t, rec, &page)
+	if len(page.Items) != 2 || page.Total != 3 || page.Limit != 2 || page.Offset != 0 || !page.HasMore {
+		t.Fatalf("page = %+v, want 2 of 3 with more to come", page)
+	}
+	first := page.Items[0]
+	if first.Candidate == nil || first.Candidate.Email != "ada@example.com" || first.Job == nil || first.Job.Title != "Backend Engineer" {
+		t.Fatalf("application = %+v, want its candidate and job", first)
+	}
+	if first.YearsOfExperience == nil || *first.YearsOfExperience != 6 || first.AIScore == nil || first.AIScore.Overall != 8.5 {
+		t.Fatalf("application = %+v, want its experience and score", first)
+	}
+	if !first.AppliedDate.Equal(time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)) {
+		t.Fatalf("appliedDate = %v", first.AppliedDate)
+	}
+
+	// The list and the count use the same filters
+	want := map[string]interface{}{"jobId": "job-1", "minScore": float64(7)}
+	list, count := fake.last(gateway.GetApplicationsQuery), fake.last(gateway.CountApplicationsQuery)
+	if !reflect.DeepEqual(list["filters"], want) || !reflect.DeepEqual(count["filters"], want) {
+		t.Fatalf("filters sent = %v and %v, want %v", list["filters"], count["filters"], want)
+	}
+
+	for _, query := range []string{"dateTo=yesterday", "cursor=!!!"} {
+		rec := httptest.NewRecorder()
+		h.ListApplications(rec, httptest.NewRequest(http.MethodGet, "/api/v2/applications?"+query, nil))
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("?%s status = %d, want 400", query, rec.Code)
+		}
+	}
+}
+
+func TestApplicationHandler_GetApplication(t *testing.T) {
+	_, client := newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Variables["id"] != "app-1" {
+			return map[string]interface{}{"application": nil}
+		}
+		return map[string]interface{}{"application": map[string]interface{}{
+			"id": "app-1", "status": "INTERVIEW", "appliedDate": "2026-10-01T09:00:00Z", "lastUpdated": "2026-10-02T09:00:00Z",
+			"notes": []interface{}{
+				map[string]interface{}{
+					"id": "note-1", "content": "Strong systems design", "tags": []interface{}{"technical"}, "isInternal": true,
+					"author": map[string]interface{}{"id": "user-1", "name": "Grace Hopper"}, "createdAt": "2026-10-02T09:00:00Z",
+				},
+			},
+			"timeline": []interface{}{map[string]interface{}{"type": "STATUS_CHANGED"}},
+		}}
+	})
+	r := chi.NewRouter()
+	r.Get("/api/v2/applications/{id}", NewApplicationHandler(client).GetApplication)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/applications/app-1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var application models.Application
+	decodeJSON(t, rec, &application)
+	wantNote := models.Note{
+		ID: "note-1", Author: &models.UserRef{ID: "user-1", Name: "Grace Hopper"}, Content: "Strong systems design",
+		Tags: []string{"technical"}, IsInternal: true, CreatedAt: time.Date(2026, 10, 2, 9, 0, 0, 0, time.UTC),
+	}
+	if application.ID != "app-1" || len(application.Notes) != 1 || !reflect.DeepEqual(application.Notes[0], wantNote) {
+		t.Fatalf("application = %+v, want app-1 with its note", application)
+	}
+	var fields map[string]interface{}
+	decodeJSON(t, rec, &fields)
+	if _, ok := fields["timeline"]; ok {
+		t.Fatal("response has the Hub-HRMS timeline")
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/applications/app-9", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("missing application status = %d, want 404", rec.Code)
+	}
+}
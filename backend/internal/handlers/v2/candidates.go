@@ -0,0 +1,44 @@
+package v2
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/models"
+)
+
+// CandidateHandler serves candidate profiles to recruiters
+type CandidateHandler struct {
+	client *gateway.HubHRMSClient
+}
+
+// NewCandidateHandler creates a new v2 candidate handler
+func NewCandidateHandler(client *gateway.HubHRMSClient) *CandidateHandler {
+	return &CandidateHandler{client: client}
+}
+
+// GetCandidate returns a candidate's profile and their applications
+func (h *CandidateHandler) GetCandidate(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.Query(r.Context(), gateway.GetCandidateQuery, map[string]interface{}{
+		"id": chi.URLParam(r, "id"),
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch candidate", err)
+		return
+	}
+
+	var candidate models.Candidate
+	found, err := decodeField(resp, "candidate", &candidate)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch candidate", err)
+		return
+	}
+	if !found {
+		notFound(w, "Candidate")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, candidate)
+}
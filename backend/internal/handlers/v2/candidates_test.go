@@ -0,0 +1,72 @@
+package v2
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/models"
+)
+
+func TestCandidateHandler_GetCandidate(t *testing.T) {
+	_, client := newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Variables["id"] != "cand-1" {
+			return map[string]interface{}{"candidate": nil}
+		}
+		return map[string]interface{}{"candidate": map[string]interface{}{
+			"id": "cand-1", "firstName": "Ada", "lastName": "Lovelace", "email": "ada@example.com",
+			"skills": []interface{}{"Go"},
+			"experience": []interface{}{map[string]interface{}{
+				"company": "Analytical Engines", "title": "Engineer", "startDate": "2020-01", "current": true,
+			}},
+			"education": []interface{}{map[string]interface{}{
+				"institution": "University of London", "degree": "BSc", "gpa": 3.9,
+			}},
+			"applications": []interface{}{map[string]interface{}{
+				"id": "app-1", "status": "NEW", "appliedDate": "2026-10-01T09:00:00Z", "lastUpdated": "2026-10-01T09:00:00Z",
+			}},
+			"certifications": []interface{}{map[string]interface{}{"name": "CKA"}},
+			"createdAt":      "2026-09-01T09:00:00Z",
+			"updatedAt":      "2026-10-01T09:00:00Z",
+		}}
+	})
+	r := chi.NewRouter()
+	r.Get("/api/v2/candidates/{id}", NewCandidateHandler(client).GetCandidate)
+
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/candidates/cand-1", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+
+	var candidate models.Candidate
+	decodeJSON(t, rec, &candidate)
+	if candidate.ID != "cand-1" || candidate.Email != "ada@example.com" || !reflect.DeepEqual(candidate.Skills, []string{"Go"}) {
+		t.Fatalf("candidate = %+v", candidate)
+	}
+	wantExperience := []models.Experience{{Company: "Analytical Engines", Title: "Engineer", StartDate: "2020-01", Current: true}}
+	wantEducation := []models.Education{{Institution: "University of London", Degree: "BSc"}}
+	if !reflect.DeepEqual(candidate.Experience, wantExperience) || !reflect.DeepEqual(candidate.Education, wantEducation) {
+		t.Fatalf("history = %+v %+v, want %+v %+v", candidate.Experience, candidate.Education, wantExperience, wantEducation)
+	}
+	if len(candidate.Applications) != 1 || candidate.Applications[0].ID != "app-1" {
+		t.Fatalf("applications = %+v, want app-1", candidate.Applications)
+	}
+
+	// Fields outside the v2 type are not passed through
+	var fields map[string]interface{}
+	decodeJSON(t, rec, &fields)
+	if _, ok := fields["certifications"]; ok {
+		t.Fatal("response has certifications")
+	}
+
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/candidates/cand-9", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("missing candidate status = %d, want 404", rec.Code)
+	}
+}
@@ -0,0 +1,68 @@
+package v2
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// fakeHubHRMS answers GraphQL requests with the data respond returns for
+// them, or with the whole response when respond returns a
+// *gateway.GraphQLResponse, and records the requests it was sent
+type fakeHubHRMS struct {
+	mu       sync.Mutex
+	requests []gateway.GraphQLRequest
+	respond  func(req gateway.GraphQLRequest) interface{}
+}
+
+// newFakeHubHRMS starts a fake Hub-HRMS and returns a client for it
+func newFakeHubHRMS(t *testing.T, respond func(req gateway.GraphQLRequest) interface{}) (*fakeHubHRMS, *gateway.HubHRMSClient) {
+	t.Helper()
+	fake := &fakeHubHRMS{respond: respond}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req gateway.GraphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fake.mu.Lock()
+		fake.requests = append(fake.requests, req)
+		fake.mu.Unlock()
+
+		data := fake.respond(req)
+		if resp, ok := data.(*gateway.GraphQLResponse); ok {
+			json.NewEncoder(w).Encode(resp)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	t.Cleanup(server.Close)
+
+	client := gateway.NewHubHRMSClient(server.URL, "")
+	t.Cleanup(client.Close)
+	return fake, client
+}
+
+// last returns the variables of the last request made with query
+func (f *fakeHubHRMS) last(query string) map[string]interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i := len(f.requests) - 1; i >= 0; i-- {
+		if f.requests[i].Query == query {
+			return f.requests[i].Variables
+		}
+	}
+	return nil
+}
+
+// decodeJSON decodes a response body into v
+func decodeJSON(t *testing.T, rec *httptest.ResponseRecorder, v interface{}) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("response is not JSON: %v: %s", err, rec.Body)
+	}
+}
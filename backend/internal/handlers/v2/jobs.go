@@ -0,0 +1,134 @@
+package v2
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/handlers"
+	"hr-recruiting/internal/models"
+)
+
+// JobHandler serves job postings
+type JobHandler struct {
+	client *gateway.HubHRMSClient
+}
+
+// NewJobHandler creates a new v2 job handler
+func NewJobHandler(client *gateway.HubHRMSClient) *JobHandler {
+	return &JobHandler{client: client}
+}
+
+// JobListParams are the filters accepted when listing jobs
+type JobListParams struct {
+	Query           string
+	Department      string
+	Location        string
+	EmploymentType  string
+	ExperienceLevel string
+	Remote          *bool
+	// Status defaults to PUBLISHED
+	Status string
+}
+
+// parseJobListParams reads job filters from the query string
+func parseJobListParams(r *http.Request) JobListParams {
+	q := r.URL.Query()
+	params := JobListParams{
+		Query:           q.Get("query"),
+		Department:      q.Get("department"),
+		Location:        q.Get("location"),
+		EmploymentType:  q.Get("employmentType"),
+		ExperienceLevel: q.Get("experienceLevel"),
+		Status:          q.Get("status"),
+	}
+	if remote, err := strconv.ParseBool(q.Get("remote")); err == nil {
+		params.Remote = &remote
+	}
+	if params.Status == "" {
+		params.Status = "PUBLISHED"
+	}
+	return params
+}
+
+// filters builds the Hub-HRMS JobFilters input
+func (p JobListParams) filters() map[string]interface{} {
+	filters := map[string]interface{}{"status": p.Status}
+	if p.Query != "" {
+		filters["query"] = p.Query
+	}
+	if p.Department != "" {
+		filters["departments"] = []string{p.Department}
+	}
+	if p.Location != "" {
+		filters["locations"] = []string{p.Location}
+	}
+	if p.EmploymentType != "" {
+		filters["employmentTypes"] = []string{p.EmploymentType}
+	}
+	if p.ExperienceLevel != "" {
+		filters["experienceLevels"] = []string{p.ExperienceLevel}
+	}
+	if p.Remote != nil {
+		filters["remoteWork"] = *p.Remote
+	}
+	return filters
+}
+
+// ListJobs returns a page of jobs
+func (h *JobHandler) ListJobs(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := handlers.ParsePagination(r)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid pagination cursor", err)
+		return
+	}
+
+	// One extra row tells us whether another page exists
+	resp, err := h.client.Query(r.Context(), gateway.GetJobsQuery, map[string]interface{}{
+		"filters": parseJobListParams(r).filters(),
+		"limit":   limit + 1,
+		"offset":  offset,
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch jobs", err)
+		return
+	}
+
+	jobs := []models.Job{}
+	if _, err := decodeField(resp, "jobs", &jobs); err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch jobs", err)
+		return
+	}
+
+	page := models.Page[models.Job]{Items: jobs, Limit: limit, Offset: offset}
+	if len(jobs) > limit {
+		page.Items, page.HasMore = jobs[:limit], true
+	}
+	respondJSON(w, http.StatusOK, page)
+}
+
+// GetJob returns a single job
+func (h *JobHandler) GetJob(w http.ResponseWriter, r *http.Request) {
+	resp, err := h.client.Query(r.Context(), gateway.GetJobQuery, map[string]interface{}{
+		"id": chi.URLParam(r, "id"),
+	})
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch job", err)
+		return
+	}
+
+	var job models.Job
+	found, err := decodeField(resp, "job", &job)
+	if err != nil {
+		respondError(w, http.StatusInternalServerError, "Failed to fetch job", err)
+		return
+	}
+	if !found {
+		notFound(w, "Job")
+		return
+	}
+
+	respondJSON(w, http.StatusOK, job)
+}
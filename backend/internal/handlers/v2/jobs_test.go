@@ -0,0 +1,208 @@
+package v2
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/models"
+)
+
+// testJob is a job as Hub-HRMS returns it, including fields v2 leaves out
+func testJob(id string) map[string]interface{} {
+	return map[string]interface{}{
+		"id":               id,
+		"slug":             "backend-engineer",
+		"title":            "Backend Engineer",
+		"department":       "Engineering",
+		"location":         "London",
+		"employmentType":   "FULL_TIME",
+		"experienceLevel":  "SENIOR",
+		"salaryRange":      map[string]interface{}{"min": 70000, "max": 90000, "currency": "GBP"},
+		"description":      "Build the recruiting platform",
+		"requirements":     []interface{}{"Go"},
+		"responsibilities": []interface{}{"Own the API"},
+		"benefits":         []interface{}{"Pension"},
+		"skills":           []interface{}{"Go", "PostgreSQL"},
+		"status":           "PUBLISHED",
+		"postedDate":       "2026-10-01T09:00:00Z",
+		"closingDate":      nil,
+		"applicationCount": 12,
+		"viewCount":        340,
+		"remoteWork":       true,
+		"urgentHiring":     false,
+		"createdBy":        map[string]interface{}{"id": "user-1", "name": "Grace Hopper", "email": "grace@example.com"},
+		"createdAt":        "2026-09-30T09:00:00Z",
+		"updatedAt":        "2026-10-01T09:00:00Z",
+		"variantB":         map[string]interface{}{"title": "Go Engineer"},
+	}
+}
+
+func TestParseJobListParams(t *testing.T) {
+	remote := true
+
+	tests := []struct {
+		name        string
+		query       string
+		want        JobListParams
+		wantFilters map[string]interface{}
+	}{
+		{
+			name:        "defaults to published",
+			want:        JobListParams{Status: "PUBLISHED"},
+			wantFilters: map[string]interface{}{"status": "PUBLISHED"},
+		},
+		{
+			name:  "every filter",
+			query: "query=go&department=Engineering&location=London&employmentType=FULL_TIME&experienceLevel=SENIOR&remote=true&status=CLOSED",
+			want: JobListParams{
+				Query: "go", Department: "Engineering", Location: "London", EmploymentType: "FULL_TIME",
+				ExperienceLevel: "SENIOR", Remote: &remote, Status: "CLOSED",
+			},
+			wantFilters: map[string]interface{}{
+				"status": "CLOSED", "query": "go", "departments": []string{"Engineering"}, "locations": []string{"London"},
+				"employmentTypes": []string{"FULL_TIME"}, "experienceLevels": []string{"SENIOR"}, "remoteWork": true,
+			},
+		},
+		{
+			name:        "unparseable remote ignored",
+			query:       "remote=maybe",
+			want:        JobListParams{Status: "PUBLISHED"},
+			wantFilters: map[string]interface{}{"status": "PUBLISHED"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			params := parseJobListParams(httptest.NewRequest(http.MethodGet, "/api/v2/jobs?"+tt.query, nil))
+			if !reflect.DeepEqual(params, tt.want) {
+				t.Fatalf("parseJobListParams() = %+v, want %+v", params, tt.want)
+			}
+			if filters := params.filters(); !reflect.DeepEqual(filters, tt.wantFilters) {
+				t.Fatalf("filters() = %v, want %v", filters, tt.wantFilters)
+			}
+		})
+	}
+}
+
+func TestJobHandler_ListJobs(t *testing.T) {
+	fake, client := newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+		// Three jobs exist, paged by offset
+		all := []interface{}{testJob("job-1"), testJob("job-2"), testJob("job-3")}
+		offset := int(req.Variables["offset"].(float64))
+		end := min(offset+int(req.Variables["limit"].(float64)), len(all))
+		return map[string]interface{}{"jobs": all[min(offset, end):end]}
+	})
+	h := NewJobHandler(client)
+
+	tests := []struct {
+		name        string
+		query       string
+		wantIDs     []string
+		wantHasMore bool
+		wantStatus  int
+	}{
+		{name: "first page", query: "limit=2", wantIDs: []string{"job-1", "job-2"}, wantHasMore: true, wantStatus: http.StatusOK},
+		{name: "last page", query: "limit=2&offset=2", wantIDs: []string{"job-3"}, wantStatus: http.StatusOK},
+		{name: "past the end", query: "offset=10", wantIDs: []string{}, wantStatus: http.StatusOK},
+		{name: "bad cursor", query: "cursor=!!!", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			h.ListJobs(rec, httptest.NewRequest(http.MethodGet, "/api/v2/jobs?"+tt.query, nil))
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var page models.Page[models.Job]
+			decodeJSON(t, rec, &page)
+			ids := []string{}
+			for _, job := range page.Items {
+				ids = append(ids, job.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) || page.HasMore != tt.wantHasMore {
+				t.Fatalf("page = %v hasMore %v, want %v hasMore %v", ids, page.HasMore, tt.wantIDs, tt.wantHasMore)
+			}
+		})
+	}
+
+	// One more row than the page is asked for, to tell whether there is
+	// another page
+	if variables := fake.last(gateway.GetJobsQuery); variables["limit"] != float64(21) {
+		t.Fatalf("limit sent = %v, want the default page size plus one", variables["limit"])
+	}
+}
+
+func TestJobHandler_GetJob(t *testing.T) {
+	_, client := newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+		switch req.Variables["id"] {
+		case "job-1":
+			return map[string]interface{}{"job": testJob("job-1")}
+		case "job-bad":
+			return map[string]interface{}{"job": map[string]interface{}{"id": "job-bad", "applicationCount": "many"}}
+		case "job-err":
+			return &gateway.GraphQLResponse{
+				Data:   map[string]interface{}{"job": nil},
+				Errors: []gateway.GraphQLError{{Message: "resolver failed"}},
+			}
+		}
+		return map[string]interface{}{"job": nil}
+	})
+	r := chi.NewRouter()
+	r.Get("/api/v2/jobs/{id}", NewJobHandler(client).GetJob)
+
+	get := func(id string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v2/jobs/"+id, nil))
+		return rec
+	}
+
+	rec := get("job-1")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+	var job models.Job
+	decodeJSON(t, rec, &job)
+	posted := time.Date(2026, 10, 1, 9, 0, 0, 0, time.UTC)
+	want := models.Job{
+		ID: "job-1", Slug: "backend-engineer", Title: "Backend Engineer", Department: "Engineering", Location: "London",
+		EmploymentType: "FULL_TIME", ExperienceLevel: "SENIOR",
+		SalaryRange:  &models.SalaryRange{Min: 70000, Max: 90000, Currency: "GBP"},
+		Description:  "Build the recruiting platform",
+		Requirements: []string{"Go"}, Responsibilities: []string{"Own the API"}, Benefits: []string{"Pension"},
+		Skills: []string{"Go", "PostgreSQL"}, Status: "PUBLISHED", PostedDate: &posted,
+		ApplicationCount: 12, ViewCount: 340, RemoteWork: true,
+		CreatedBy: &models.UserRef{ID: "user-1", Name: "Grace Hopper", Email: "grace@example.com"},
+		CreatedAt: time.Date(2026, 9, 30, 9, 0, 0, 0, time.UTC), UpdatedAt: posted,
+	}
+	if !reflect.DeepEqual(job, want) {
+		t.Fatalf("job = %+v, want %+v", job, want)
+	}
+
+	// Fields outside the v2 type are not passed through
+	var fields map[string]interface{}
+	decodeJSON(t, rec, &fields)
+	for _, field := range []string{"variantB", "closingDate"} {
+		if _, ok := fields[field]; ok {
+			t.Errorf("response has %s", field)
+		}
+	}
+
+	for id, wantStatus := range map[string]int{
+		"job-9":   http.StatusNotFound,
+		"job-bad": http.StatusInternalServerError,
+		"job-err": http.StatusBadGateway,
+	} {
+		if rec := get(id); rec.Code != wantStatus {
+			t.Errorf("GET %s = %d, want %d", id, rec.Code, wantStatus)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+// Package v2 serves the /api/v2 routes. They return the typed resources in
+// the models package rather than Hub-HRMS responses passed through as is, so
+// renaming a Hub-HRMS field no longer changes the API.
+package v2
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/handlers"
+)
+
+// decodeField decodes the named top-level field of a Hub-HRMS response into
+// v, reporting false when the field is null or missing
+func decodeField(resp *gateway.GraphQLResponse, field string, v interface{}) (bool, error) {
+	if len(resp.Errors) > 0 {
+		return false, fmt.Errorf("hub-hrms error: %s", resp.Errors[0].Message)
+	}
+
+	fields, _ := resp.Data.(map[string]interface{})
+	if fields[field] == nil {
+		return false, nil
+	}
+
+	raw, err := json.Marshal(fields[field])
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return false, fmt.Errorf("invalid %s in Hub-HRMS response: %w", field, err)
+	}
+	return true, nil
+}
+
+// decodeCount reads a count field of a Hub-HRMS response
+func decodeCount(resp *gateway.GraphQLResponse, field string) int {
+	var count int
+	if _, err := decodeField(resp, field, &count); err != nil {
+		return 0
+	}
+	return count
+}
+
+var (
+	respondJSON  = handlers.RespondJSON
+	respondError = handlers.RespondError
+)
+
+// notFound responds 404 for a missing resource
+func notFound(w http.ResponseWriter, resource string) {
+	respondError(w, http.StatusNotFound, resource+" not found", nil)
+}
@@ -0,0 +1,225 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	"hr-recruiting/internal/config"
+	"hr-recruiting/internal/gateway"
+	"hr-recruiting/internal/services"
+)
+
+// WebhookHandler handles callbacks from external services
+type WebhookHandler struct {
+	client        *gateway.HubHRMSClient
+	uploadService *services.UploadService
+	snsVerifier   *services.SNSVerifier
+
+	emailQueue     services.EmailEnqueuer
+	pipelineEvents *services.PipelineEventBus
+	webhooks       *services.WebhookService
+	features       *config.FeatureFlags
+	logger         *slog.Logger
+}
+
+// NewWebhookHandler creates a new webhook handler
+func NewWebhookHandler(
+	client *gateway.HubHRMSClient,
+	uploadService *services.UploadService,
+	snsVerifier *services.SNSVerifier,
+	emailQueue *services.EmailQueue,
+	pipelineEvents *services.PipelineEventBus,
+	webhooks *services.WebhookService,
+	features *config.FeatureFlags,
+	logger *slog.Logger,
+) *WebhookHandler {
+	return &WebhookHandler{
+		client:        client,
+		uploadService: uploadService,
+		snsVerifier:   snsVerifier,
+
+		emailQueue:     emailQueue,
+		pipelineEvents: pipelineEvents,
+		webhooks:       webhooks,
+		features:       features,
+		logger:         logger,
+	}
+}
+
+// hubHRMSEvent is a callback from Hub-HRMS about a change made there
+type hubHRMSEvent struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	OccurredAt time.Time              `json:"occurredAt"`
+	Data       map[string]interface{} `json:"data"`
+}
+
+// HubHRMSEvent handles callbacks from Hub-HRMS, which are signed and checked
+// by middleware.HubHRMSWebhookVerifier. Changes made through this API are
+// already announced, so Hub-HRMS only calls back for changes made elsewhere,
+// such as AI scoring finishing or a status set in the Hub-HRMS console.
+func (h *WebhookHandler) HubHRMSEvent(w http.ResponseWriter, r *http.Request) {
+	var event hubHRMSEvent
+	if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	applicationID := lookupString(event.Data, "applicationId")
+	jobID := lookupString(event.Data, "jobId")
+
+	switch event.Type {
+	case services.EventApplicationScored:
+		h.webhooks.Publish(services.EventApplicationScored, services.ApplicationEventData{
+			ApplicationID: applicationID,
+			JobID:         jobID,
+		})
+
+	case services.EventApplicationStatusChanged:
+		status := lookupString(event.Data, "newStatus")
+		h.pipelineEvents.Publish(services.PipelineEvent{
+			ApplicationID: applicationID,
+			OldStatus:     lookupString(event.Data, "oldStatus"),
+			NewStatus:     status,
+			JobID:         jobID,
+		})
+		h.webhooks.Publish(services.EventApplicationStatusChanged, services.ApplicationEventData{
+			ApplicationID: applicationID,
+			JobID:         jobID,
+			Status:        status,
+		})
+		if email := lookupString(event.Data, "candidate", "email"); email != "" && h.features.EmailNotifications.Load() {
+			h.emailQueue.Enqueue(services.StatusUpdateEmail(
+				email,
+				lookupString(event.Data, "candidate", "firstName"),
+				lookupString(event.Data, "job", "title"),
+				status,
+			))
+		}
+
+	default:
+		respondSuccess(w, "Ignored event type "+event.Type, nil)
+		return
+	}
+
+	h.logger.Info("processed hub-hrms event", "event_id", event.ID, "event_type", event.Type, "application_id", applicationID)
+	respondSuccess(w, "Event processed", nil)
+}
+
+// s3Event is the S3 event notification carried in an SNS message
+type s3Event struct {
+	Records []struct {
+		EventName string `json:"eventName"`
+		S3        struct {
+			Object struct {
+				Key string `json:"key"`
+			} `json:"object"`
+		} `json:"s3"`
+	} `json:"Records"`
+}
+
+// UploadComplete links resumes uploaded via presigned URL to their candidate
+func (h *WebhookHandler) UploadComplete(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var msg services.SNSMessage
+	if err := json.NewDecoder(r.Body).Decode(&msg); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid request body", err)
+		return
+	}
+	defer r.Body.Close()
+
+	if err := h.snsVerifier.Verify(&msg); err != nil {
+		respondError(w, http.StatusUnauthorized, "Invalid notification signature", err)
+		return
+	}
+
+	switch msg.Type {
+	case "SubscriptionConfirmation":
+		if err := h.snsVerifier.ConfirmSubscription(&msg); err != nil {
+			respondError(w, http.StatusBadGateway, "Failed to confirm subscription", err)
+			return
+		}
+		respondSuccess(w, "Subscription confirmed", nil)
+		return
+	case "Notification":
+	default:
+		respondSuccess(w, "Ignored message type "+msg.Type, nil)
+		return
+	}
+
+	var event s3Event
+	if err := json.Unmarshal([]byte(msg.Message), &event); err != nil {
+		respondError(w, http.StatusBadRequest, "Invalid S3 event", err)
+		return
+	}
+
+	linked := 0
+	for _, record := range event.Records {
+		// S3 form-encodes object keys in event notifications
+		key, err := url.QueryUnescape(record.S3.Object.Key)
+		if err != nil {
+			h.logger.Warn("skipping upload with malformed key", "key", record.S3.Object.Key, "error", err)
+			continue
+		}
+
+		if err := h.linkResume(ctx, key); err != nil {
+			h.logger.Error("failed to link uploaded resume", "key", key, "error", err)
+			continue
+		}
+		linked++
+	}
+
+	respondSuccess(w, "Upload processed", map[string]interface{}{
+		"linked": linked,
+	})
+}
+
+// linkResume points the uploading candidate's profile at the new resume and
+// kicks off text extraction
+func (h *WebhookHandler) linkResume(ctx context.Context, key string) error {
+	metadata, err := h.uploadService.GetFileMetadata(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	candidateID := metadata["candidate-id"]
+	if candidateID == "" {
+		// Uploaded before a candidate existed; SubmitApplication carries the URL
+		return nil
+	}
+
+	resumeURL := h.uploadService.GetFileURL(key)
+	variables := map[string]interface{}{
+		"id": candidateID,
+		"input": map[string]interface{}{
+			"resumeUrl": resumeURL,
+		},
+	}
+	if _, err := h.client.Mutate(ctx, gateway.UpdateCandidateProfileMutation, variables); err != nil {
+		return err
+	}
+
+	go h.extractResumeText(candidateID, resumeURL)
+
+	return nil
+}
+
+// extractResumeText asks Hub-HRMS to extract and index the resume text
+func (h *WebhookHandler) extractResumeText(candidateID, resumeURL string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	variables := map[string]interface{}{
+		"candidateId": candidateID,
+		"resumeUrl":   resumeURL,
+	}
+	if _, err := h.client.Mutate(ctx, gateway.ExtractResumeTextMutation, variables); err != nil {
+		h.logger.Warn("failed to start resume text extraction", "candidate_id", candidateID, "error", err)
+	}
+}
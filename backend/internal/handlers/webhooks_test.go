@@ -0,0 +1,206 @@
+package handlers
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+
+	"hr-recruiting/internal/config"
+	"hr-recruiting/internal/middleware"
+	"hr-recruiting/internal/services"
+)
+
+const testWebhookSecret = "hubhrms-webhook-secret"
+
+// newTestWebhookHandler returns a router serving the Hub-HRMS callback behind
+// its signature check, with the event bus, webhook service and email queue
+// the handler dispatches to
+func newTestWebhookHandler(t *testing.T) (http.Handler, *services.PipelineEventBus, *services.WebhookService, *recordingEmailQueue) {
+	t.Helper()
+	events := services.NewPipelineEventBus()
+	webhooks := services.NewWebhookService(func() bool { return true })
+	features := &config.FeatureFlags{}
+	features.EmailNotifications.Store(true)
+
+	h := NewWebhookHandler(nil, nil, nil, nil, events, webhooks, features, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	emails := &recordingEmailQueue{}
+	h.emailQueue = emails
+
+	r := chi.NewRouter()
+	r.With(middleware.HubHRMSWebhookVerifier(testWebhookSecret)).Post("/webhooks/hubhrms", h.HubHRMSEvent)
+	return r, events, webhooks, emails
+}
+
+// signedCallback is a Hub-HRMS callback carrying body, signed with secret
+func signedCallback(secret, body string) *http.Request {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/hubhrms", strings.NewReader(body))
+	req.Header.Set(middleware.HubHRMSSignatureHeader, "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return req
+}
+
+// subscribe registers a webhook subscriber for eventType and returns the
+// events it receives
+func subscribe(t *testing.T, webhooks *services.WebhookService, eventType string) <-chan services.WebhookEvent {
+	t.Helper()
+	received := make(chan services.WebhookEvent, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var event services.WebhookEvent
+		json.NewDecoder(r.Body).Decode(&event)
+		received <- event
+	}))
+	t.Cleanup(server.Close)
+	if _, err := webhooks.Register(server.URL, []string{eventType}, "", "test"); err != nil {
+		t.Fatal(err)
+	}
+	return received
+}
+
+const statusChangedCallback = `{
+	"id": "evt-1",
+	"type": "application.status_changed",
+	"occurredAt": "2026-03-01T09:00:00Z",
+	"data": {
+		"applicationId": "app-1",
+		"jobId": "job-1",
+		"oldStatus": "SCREENING",
+		"newStatus": "INTERVIEW",
+		"candidate": {"email": "ada@example.com", "firstName": "Ada"},
+		"job": {"title": "Backend Engineer"}
+	}
+}`
+
+func TestWebhookHandler_HubHRMSEvent_StatusChanged(t *testing.T) {
+	router, events, webhooks, emails := newTestWebhookHandler(t)
+	pipeline, unsubscribe := events.Subscribe()
+	defer unsubscribe()
+	delivered := subscribe(t, webhooks, services.EventApplicationStatusChanged)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, signedCallback(testWebhookSecret, statusChangedCallback))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	select {
+	case event := <-pipeline:
+		want := services.PipelineEvent{ApplicationID: "app-1", OldStatus: "SCREENING", NewStatus: "INTERVIEW", JobID: "job-1"}
+		if event != want {
+			t.Fatalf("pipeline event = %+v, want %+v", event, want)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no pipeline event published")
+	}
+
+	select {
+	case event := <-delivered:
+		data, _ := event.Data.(map[string]interface{})
+		if event.Type != services.EventApplicationStatusChanged || data["applicationId"] != "app-1" || data["status"] != "INTERVIEW" {
+			t.Fatalf("webhook event = %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no webhook delivered to subscribers")
+	}
+
+	sent := emails.enqueued()
+	if len(sent) != 1 || sent[0].To != "ada@example.com" || sent[0].Template != "status_update" ||
+		sent[0].Data["Status"] != "INTERVIEW" || sent[0].Data["JobTitle"] != "Backend Engineer" {
+		t.Fatalf("emails = %+v, want a status update to the candidate", sent)
+	}
+}
+
+func TestWebhookHandler_HubHRMSEvent_Scored(t *testing.T) {
+	router, events, webhooks, emails := newTestWebhookHandler(t)
+	pipeline, unsubscribe := events.Subscribe()
+	defer unsubscribe()
+	delivered := subscribe(t, webhooks, services.EventApplicationScored)
+
+	body := `{"id":"evt-2","type":"application.scored","data":{"applicationId":"app-2","jobId":"job-1"}}`
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, signedCallback(testWebhookSecret, body))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+	}
+
+	select {
+	case event := <-delivered:
+		data, _ := event.Data.(map[string]interface{})
+		if event.Type != services.EventApplicationScored || data["applicationId"] != "app-2" {
+			t.Fatalf("webhook event = %+v", event)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no webhook delivered to subscribers")
+	}
+	select {
+	case event := <-pipeline:
+		t.Fatalf("scoring published pipeline event %+v", event)
+	default:
+	}
+	if len(emails.enqueued()) != 0 {
+		t.Fatal("scoring emailed the candidate")
+	}
+}
+
+func TestWebhookHandler_HubHRMSEvent_Rejected(t *testing.T) {
+	tampered := strings.Replace(statusChangedCallback, "INTERVIEW", "OFFER", 1)
+
+	tests := []struct {
+		name       string
+		req        *http.Request
+		wantStatus int
+	}{
+		{name: "tampered payload", req: func() *http.Request {
+			req := signedCallback(testWebhookSecret, statusChangedCallback)
+			req.Body = io.NopCloser(strings.NewReader(tampered))
+			return req
+		}(), wantStatus: http.StatusUnauthorized},
+		{name: "wrong secret", req: signedCallback("another-secret", statusChangedCallback), wantStatus: http.StatusUnauthorized},
+		{name: "unsigned", req: httptest.NewRequest(http.MethodPost, "/webhooks/hubhrms", strings.NewReader(statusChangedCallback)), wantStatus: http.StatusUnauthorized},
+		{name: "signed but malformed", req: signedCallback(testWebhookSecret, `{"type":`), wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router, events, _, emails := newTestWebhookHandler(t)
+			pipeline, unsubscribe := events.Subscribe()
+			defer unsubscribe()
+
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, tt.req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			select {
+			case event := <-pipeline:
+				t.Fatalf("rejected callback published %+v", event)
+			default:
+			}
+			if len(emails.enqueued()) != 0 {
+				t.Fatal("rejected callback emailed the candidate")
+			}
+		})
+	}
+}
+
+func TestWebhookHandler_HubHRMSEvent_UnknownType(t *testing.T) {
+	router, _, _, emails := newTestWebhookHandler(t)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, signedCallback(testWebhookSecret, `{"id":"evt-3","type":"job.archived","data":{"jobId":"job-1"}}`))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Ignored event type job.archived") {
+		t.Fatalf("response = %d %s, want the event acknowledged and ignored", rec.Code, rec.Body)
+	}
+	if len(emails.enqueued()) != 0 {
+		t.Fatal("ignored event emailed a candidate")
+	}
+}
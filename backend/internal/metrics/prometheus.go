@@ -0,0 +1,99 @@
+// Package metrics collects operational metrics with the Prometheus client
+// library and serves them with promhttp.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// DefaultBuckets are latency histogram bounds in seconds
+var DefaultBuckets = prometheus.DefBuckets
+
+// Circuit breaker states reported by hubhrms_circuit_state
+const (
+	CircuitClosed   = 0
+	CircuitHalfOpen = 1
+	CircuitOpen     = 2
+)
+
+// Metrics holds the application's collectors. A nil *Metrics records nothing.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	HTTPRequests        *prometheus.CounterVec
+	HTTPRequestDuration *prometheus.HistogramVec
+	HubHRMSCallDuration *prometheus.HistogramVec
+	HubHRMSCircuitState prometheus.Gauge
+}
+
+// New creates the application's collectors on a fresh registry, alongside
+// the Go runtime and process collectors
+func New() *Metrics {
+	r := prometheus.NewRegistry()
+	r.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	factory := promauto.With(r)
+	m := &Metrics{
+		registry: r,
+		HTTPRequests: factory.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total HTTP requests by method, route and status.",
+		}, []string{"method", "path", "status"}),
+		HTTPRequestDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by route.",
+			Buckets: DefaultBuckets,
+		}, []string{"path"}),
+		HubHRMSCallDuration: factory.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "hubhrms_call_duration_seconds",
+			Help:    "Hub-HRMS GraphQL call latency by operation.",
+			Buckets: DefaultBuckets,
+		}, []string{"operation"}),
+		HubHRMSCircuitState: factory.NewGauge(prometheus.GaugeOpts{
+			Name: "hubhrms_circuit_state",
+			Help: "Hub-HRMS circuit breaker state (0 closed, 1 half-open, 2 open).",
+		}),
+	}
+
+	// The client has no breaker yet, so calls are always let through
+	m.HubHRMSCircuitState.Set(CircuitClosed)
+
+	return m
+}
+
+// Registry returns the registry backing m, for registering extra collectors
+func (m *Metrics) Registry() *prometheus.Registry {
+	return m.registry
+}
+
+// ObserveRequest records one served HTTP request
+func (m *Metrics) ObserveRequest(method, path string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.HTTPRequests.WithLabelValues(method, path, strconv.Itoa(status)).Inc()
+	m.HTTPRequestDuration.WithLabelValues(path).Observe(duration.Seconds())
+}
+
+// ObserveHubHRMSCall records one outbound GraphQL call
+func (m *Metrics) ObserveHubHRMSCall(operation string, duration time.Duration) {
+	if m == nil {
+		return
+	}
+	m.HubHRMSCallDuration.WithLabelValues(operation).Observe(duration.Seconds())
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
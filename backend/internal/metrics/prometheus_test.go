@@ -0,0 +1,87 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/prometheus/common/expfmt"
+	"github.com/prometheus/common/model"
+)
+
+func TestMetrics_ObserveRequest(t *testing.T) {
+	m := New()
+	// Bounds are inclusive, and values past the last bound only count to +Inf
+	m.ObserveRequest(http.MethodGet, "/api/v1/jobs/{id}", http.StatusOK, 5*time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "/api/v1/jobs/{id}", http.StatusOK, 300*time.Millisecond)
+	m.ObserveRequest(http.MethodGet, "/api/v1/jobs/{id}", http.StatusNotFound, 12*time.Second)
+
+	want := `
+# HELP http_request_duration_seconds HTTP request latency by route.
+# TYPE http_request_duration_seconds histogram
+http_request_duration_seconds_bucket{path="/api/v1/jobs/{id}",le="0.005"} 1
+http_request_duration_seconds_bucket{path="/api/v1/jobs/{id}",le="0.01"} 1
+http_request_duration_seconds_bucket{path="/api/v1/jobs/{id}",le="0.025"} 1
+http_request_duration_seconds_bucket{path="/api/v1/jobs/{id}",le="0.05"} 1
+http_request_duration_seconds_bucket{path="/api/v1/jobs/{id}",le="0.1"} 1
+http_request_duration_seconds_bucket{path="/api/v1/jobs/{id}",le="0.25"} 1
+http_request_duration_seconds_bucket{path="/api/v1/jobs/{id}",le="0.5"} 2
+http_request_duration_seconds_bucket{path="/api/v1/jobs/{id}",le="1"} 2
+http_request_duration_seconds_bucket{path="/api/v1/jobs/{id}",le="2.5"} 2
+http_request_duration_seconds_bucket{path="/api/v1/jobs/{id}",le="5"} 2
+http_request_duration_seconds_bucket{path="/api/v1/jobs/{id}",le="10"} 2
+http_request_duration_seconds_bucket{path="/api/v1/jobs/{id}",le="+Inf"} 3
+http_request_duration_seconds_sum{path="/api/v1/jobs/{id}"} 12.305
+http_request_duration_seconds_count{path="/api/v1/jobs/{id}"} 3
+# HELP http_requests_total Total HTTP requests by method, route and status.
+# TYPE http_requests_total counter
+http_requests_total{method="GET",path="/api/v1/jobs/{id}",status="200"} 2
+http_requests_total{method="GET",path="/api/v1/jobs/{id}",status="404"} 1
+`
+	if err := testutil.GatherAndCompare(m.Registry(), strings.NewReader(want), "http_requests_total", "http_request_duration_seconds"); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestMetrics_ObserveHubHRMSCall(t *testing.T) {
+	m := New()
+	m.ObserveHubHRMSCall("GetJobs", 80*time.Millisecond)
+	m.ObserveHubHRMSCall("GetJobs", 120*time.Millisecond)
+	m.ObserveHubHRMSCall("batch", time.Second)
+
+	if got := testutil.CollectAndCount(m.HubHRMSCallDuration); got != 2 {
+		t.Fatalf("hubhrms_call_duration_seconds has %d series, want 2", got)
+	}
+	if got := testutil.ToFloat64(m.HubHRMSCircuitState); got != CircuitClosed {
+		t.Fatalf("hubhrms_circuit_state = %v, want closed", got)
+	}
+
+	var nilMetrics *Metrics
+	nilMetrics.ObserveRequest(http.MethodGet, "/", http.StatusOK, time.Second)
+	nilMetrics.ObserveHubHRMSCall("GetJobs", time.Second)
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	m := New()
+	m.ObserveRequest(http.MethodPost, "/api/v1/applications", http.StatusCreated, 40*time.Millisecond)
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if rec.Code != http.StatusOK || !strings.HasPrefix(rec.Header().Get("Content-Type"), "text/plain; version=0.0.4") {
+		t.Fatalf("scrape = %d %q", rec.Code, rec.Header().Get("Content-Type"))
+	}
+
+	parser := expfmt.NewTextParser(model.LegacyValidation)
+	families, err := parser.TextToMetricFamilies(rec.Body)
+	if err != nil {
+		t.Fatalf("scrape is not valid exposition format: %v", err)
+	}
+	for _, name := range []string{"http_requests_total", "http_request_duration_seconds", "hubhrms_circuit_state", "go_goroutines", "promhttp_metric_handler_errors_total"} {
+		if families[name] == nil {
+			t.Errorf("%s was not exposed", name)
+		}
+	}
+}
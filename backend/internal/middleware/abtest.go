@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"hash/fnv"
+	"net/http"
+	"time"
+)
+
+// ABVisitorCookieName is the cookie that keeps an anonymous visitor in one
+// A/B test variant
+const ABVisitorCookieName = "ab_visitor"
+
+// abVisitorCookieMaxAge keeps a visitor's variant for the length of a test
+const abVisitorCookieMaxAge = 90 * 24 * time.Hour
+
+// ABVariant assigns requests without a valid variant query parameter to
+// variant "a" or "b", so a visitor keeps seeing the same variant. Signed-in
+// callers are bucketed by user ID and anonymous visitors by a random ID kept
+// in a cookie, which is set on their first visit; unlike the client IP, these
+// are not shared by everyone behind one NAT and do not change when a visitor
+// switches networks. The parameter is added to the URL, so register this
+// before ResponseCache to cache each variant separately.
+func ABVariant(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if variant := query.Get("variant"); variant != "a" && variant != "b" {
+			query.Set("variant", VariantFor(abVisitorKey(w, r)))
+			r.URL.RawQuery = query.Encode()
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// VariantFor deterministically maps key to variant "a" or "b"
+func VariantFor(key string) string {
+	h := fnv.New32a()
+	h.Write([]byte(key))
+	if h.Sum32()%2 == 0 {
+		return "a"
+	}
+	return "b"
+}
+
+// abVisitorKey identifies the visitor to bucket: the user ID when
+// authenticated, else the visitor cookie, which is issued when missing. The
+// client IP is only a fallback for when no visitor ID can be generated.
+func abVisitorKey(w http.ResponseWriter, r *http.Request) string {
+	if user, ok := GetUserFromContext(r.Context()); ok {
+		if id, _ := user["id"].(string); id != "" {
+			return "user:" + id
+		}
+	}
+
+	if cookie, err := r.Cookie(ABVisitorCookieName); err == nil && cookie.Value != "" {
+		return "visitor:" + cookie.Value
+	}
+
+	raw := make([]byte, 16)
+	if _, err := rand.Read(raw); err != nil {
+		return "ip:" + clientIP(r)
+	}
+	visitor := base64.RawURLEncoding.EncodeToString(raw)
+	http.SetCookie(w, &http.Cookie{
+		Name:     ABVisitorCookieName,
+		Value:    visitor,
+		Path:     "/",
+		MaxAge:   int(abVisitorCookieMaxAge.Seconds()),
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+		Secure:   r.TLS != nil || r.Header.Get("X-Forwarded-Proto") == "https",
+	})
+	return "visitor:" + visitor
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// servedVariant runs req through ABVariant, returning the variant the handler
+// saw and the response
+func servedVariant(req *http.Request) (string, *httptest.ResponseRecorder) {
+	var variant string
+	handler := ABVariant(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		variant = r.URL.Query().Get("variant")
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return variant, rec
+}
+
+// visitorCookie returns the A/B visitor cookie rec sets, if any
+func visitorCookie(rec *httptest.ResponseRecorder) *http.Cookie {
+	for _, cookie := range rec.Result().Cookies() {
+		if cookie.Name == ABVisitorCookieName {
+			return cookie
+		}
+	}
+	return nil
+}
+
+func TestVariantFor(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		key := fmt.Sprintf("visitor:%d", i)
+		variant := VariantFor(key)
+		if variant != "a" && variant != "b" {
+			t.Fatalf("VariantFor(%q) = %q, want a or b", key, variant)
+		}
+		if again := VariantFor(key); again != variant {
+			t.Fatalf("VariantFor(%q) = %q then %q, want the same variant", key, variant, again)
+		}
+		seen[variant] = true
+	}
+	if !seen["a"] || !seen["b"] {
+		t.Fatalf("100 keys got variants %v, want both", seen)
+	}
+}
+
+func TestABVariant_ExplicitVariant(t *testing.T) {
+	for _, variant := range []string{"a", "b"} {
+		got, rec := servedVariant(httptest.NewRequest(http.MethodGet, "/jobs/job-1?variant="+variant, nil))
+		if got != variant {
+			t.Fatalf("variant = %q, want the requested %q", got, variant)
+		}
+		if visitorCookie(rec) != nil {
+			t.Fatal("visitor cookie set for a request that chose its variant")
+		}
+	}
+}
+
+func TestABVariant_StickyCookie(t *testing.T) {
+	first := httptest.NewRequest(http.MethodGet, "/jobs/job-1?variant=c", nil)
+	variant, rec := servedVariant(first)
+	cookie := visitorCookie(rec)
+	if cookie == nil || cookie.Value == "" || !cookie.HttpOnly || cookie.MaxAge <= 0 {
+		t.Fatalf("cookie = %+v, want a persistent HttpOnly visitor cookie", cookie)
+	}
+	if variant != VariantFor("visitor:"+cookie.Value) {
+		t.Fatalf("variant = %q, want the one for the issued visitor ID", variant)
+	}
+
+	// The same visitor from another network keeps their variant
+	for _, remoteAddr := range []string{"203.0.113.7:4000", "198.51.100.20:5000"} {
+		req := httptest.NewRequest(http.MethodGet, "/jobs/job-1", nil)
+		req.RemoteAddr = remoteAddr
+		req.AddCookie(cookie)
+		again, rec := servedVariant(req)
+		if again != variant {
+			t.Fatalf("variant from %s = %q, want %q", remoteAddr, again, variant)
+		}
+		if visitorCookie(rec) != nil {
+			t.Fatal("visitor cookie reissued to a returning visitor")
+		}
+	}
+}
+
+func TestABVariant_VisitorsBehindOneIP(t *testing.T) {
+	seen := map[string]bool{}
+	for i := 0; i < 50; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/jobs/job-1", nil)
+		req.RemoteAddr = "192.0.2.1:1234"
+		req.AddCookie(&http.Cookie{Name: ABVisitorCookieName, Value: fmt.Sprintf("visitor-%d", i)})
+		variant, _ := servedVariant(req)
+		seen[variant] = true
+	}
+	if !seen["a"] || !seen["b"] {
+		t.Fatalf("visitors sharing an IP got variants %v, want both", seen)
+	}
+}
+
+func TestABVariant_UserID(t *testing.T) {
+	want := VariantFor("user:user-42")
+	for _, cookie := range []string{"", "visitor-1", "visitor-2"} {
+		req := httptest.NewRequest(http.MethodGet, "/jobs/job-1", nil)
+		if cookie != "" {
+			req.AddCookie(&http.Cookie{Name: ABVisitorCookieName, Value: cookie})
+		}
+		req = req.WithContext(WithUser(req.Context(), map[string]interface{}{"id": "user-42"}))
+		variant, rec := servedVariant(req)
+		if variant != want {
+			t.Fatalf("variant with cookie %q = %q, want the user's %q", cookie, variant, want)
+		}
+		if visitorCookie(rec) != nil {
+			t.Fatal("visitor cookie set for a signed-in user")
+		}
+	}
+}
@@ -0,0 +1,213 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// apiKeyPrefix marks keys issued by this service so they are recognisable in
+// logs and secret scanners
+const apiKeyPrefix = "hrk_"
+
+var (
+	// ErrUnknownAPIKey is returned for keys that were never issued or have
+	// been revoked
+	ErrUnknownAPIKey = errors.New("unknown API key")
+	// ErrAPIKeyNotFound is returned when revoking a key ID that does not exist
+	ErrAPIKeyNotFound = errors.New("API key not found")
+)
+
+// APIKey is a static credential for an integration. Only a SHA-256 hash of
+// the key itself is kept.
+type APIKey struct {
+	ID        string    `json:"id" yaml:"id"`
+	Name      string    `json:"name" yaml:"name"`
+	Roles     []string  `json:"roles" yaml:"roles,flow"`
+	RateLimit float64   `json:"rateLimit,omitempty" yaml:"rateLimit,omitempty"` // requests per second, 0 for no per-key limit
+	KeyHash   string    `json:"keyHash" yaml:"keyHash"`
+	CreatedAt time.Time `json:"createdAt" yaml:"createdAt"`
+}
+
+// APIKeyStore holds API keys in memory, indexed by key hash, and writes every
+// change back to its YAML file
+type APIKeyStore struct {
+	mu     sync.RWMutex
+	path   string
+	byHash map[string]*APIKey
+	limits map[string]*RateLimiter // key ID -> per-key limiter
+}
+
+// NewAPIKeyStore loads the keys in the YAML file at path. An empty path gives
+// a store that only lives in memory; a missing file is created on the first
+// change.
+func NewAPIKeyStore(path string) (*APIKeyStore, error) {
+	s := &APIKeyStore{
+		path:   path,
+		byHash: make(map[string]*APIKey),
+		limits: make(map[string]*RateLimiter),
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API keys: %w", err)
+	}
+
+	keys, err := unmarshalAPIKeys(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid API keys file %s: %w", path, err)
+	}
+	for _, key := range keys {
+		if key.ID == "" || key.KeyHash == "" {
+			return nil, fmt.Errorf("invalid API keys file %s: every key needs an id and keyHash", path)
+		}
+		s.add(key)
+	}
+	return s, nil
+}
+
+// Lookup returns the entry for a presented key
+func (s *APIKeyStore) Lookup(key string) (*APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.byHash[hashAPIKey(key)]
+	if !ok {
+		return nil, ErrUnknownAPIKey
+	}
+	return entry, nil
+}
+
+// Create issues a new key and persists it. The plaintext key is returned
+// once and cannot be recovered afterwards.
+func (s *APIKeyStore) Create(name string, roles []string, rateLimit float64) (*APIKey, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("failed to generate API key: %w", err)
+	}
+	plaintext := apiKeyPrefix + base64.RawURLEncoding.EncodeToString(secret)
+
+	if roles == nil {
+		roles = []string{}
+	}
+	key := &APIKey{
+		ID:        uuid.New().String(),
+		Name:      name,
+		Roles:     roles,
+		RateLimit: rateLimit,
+		KeyHash:   hashAPIKey(plaintext),
+		CreatedAt: time.Now().UTC(),
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.add(key)
+	if err := s.save(); err != nil {
+		s.remove(key)
+		return nil, "", err
+	}
+	return key, plaintext, nil
+}
+
+// Revoke deletes the key with id and persists the change
+func (s *APIKeyStore) Revoke(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range s.byHash {
+		if key.ID != id {
+			continue
+		}
+		s.remove(key)
+		if err := s.save(); err != nil {
+			s.add(key)
+			return err
+		}
+		return nil
+	}
+	return ErrAPIKeyNotFound
+}
+
+// allow applies the key's own rate limit, if it has one
+func (s *APIKeyStore) allow(key *APIKey) (bool, time.Duration) {
+	s.mu.RLock()
+	limiter, ok := s.limits[key.ID]
+	s.mu.RUnlock()
+	if !ok {
+		return true, 0
+	}
+	return limiter.allow(key.ID, time.Now())
+}
+
+func (s *APIKeyStore) add(key *APIKey) {
+	s.byHash[key.KeyHash] = key
+	if key.RateLimit > 0 {
+		burst := int(math.Max(1, math.Ceil(key.RateLimit)))
+		s.limits[key.ID] = &RateLimiter{rate: key.RateLimit, burst: burst}
+	}
+}
+
+func (s *APIKeyStore) remove(key *APIKey) {
+	delete(s.byHash, key.KeyHash)
+	delete(s.limits, key.ID)
+}
+
+// save writes the keys to a temporary file and renames it over the original,
+// so a crash never leaves a truncated file behind. Callers hold s.mu.
+func (s *APIKeyStore) save() error {
+	if s.path == "" {
+		return nil
+	}
+
+	keys := make([]*APIKey, 0, len(s.byHash))
+	for _, key := range s.byHash {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].CreatedAt.Before(keys[j].CreatedAt) })
+
+	data, err := marshalAPIKeys(keys)
+	if err != nil {
+		return fmt.Errorf("failed to save API keys: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".api-keys-*")
+	if err != nil {
+		return fmt.Errorf("failed to save API keys: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to save API keys: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to save API keys: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("failed to save API keys: %w", err)
+	}
+	return nil
+}
+
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
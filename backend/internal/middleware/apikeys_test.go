@@ -0,0 +1,193 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// authenticatedAs sends apiKey through AuthMiddleware and returns the status
+// and the user the handler saw
+func authenticatedAs(t *testing.T, store *APIKeyStore, apiKey string) (int, map[string]interface{}) {
+	t.Helper()
+	var user map[string]interface{}
+	handler := AuthMiddleware(nil, store)(RequireAuth(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, _ = GetUserFromContext(r.Context())
+		w.WriteHeader(http.StatusNoContent)
+	})))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("X-API-Key", apiKey)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code, user
+}
+
+func TestAuthMiddleware_APIKeys(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-keys.yaml")
+	store, err := NewAPIKeyStore(path)
+	if err != nil {
+		t.Fatalf("NewAPIKeyStore() error = %v", err)
+	}
+	key, plaintext, err := store.Create("ATS sync", []string{"recruiter"}, 0)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	revoked, revokedPlaintext, err := store.Create("Old export", []string{"admin"}, 0)
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := store.Revoke(revoked.ID); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+
+	t.Run("valid key", func(t *testing.T) {
+		status, user := authenticatedAs(t, store, plaintext)
+		if status != http.StatusNoContent {
+			t.Fatalf("status = %d, want %d", status, http.StatusNoContent)
+		}
+		want := map[string]interface{}{"id": key.ID, "name": "ATS sync", "roles": []string{"recruiter"}}
+		if !reflect.DeepEqual(user, want) {
+			t.Fatalf("user = %v, want %v", user, want)
+		}
+	})
+
+	t.Run("unknown key", func(t *testing.T) {
+		if status, _ := authenticatedAs(t, store, "hrk_never-issued"); status != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", status, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("revoked key", func(t *testing.T) {
+		if status, _ := authenticatedAs(t, store, revokedPlaintext); status != http.StatusUnauthorized {
+			t.Fatalf("status = %d, want %d", status, http.StatusUnauthorized)
+		}
+	})
+
+	t.Run("revocation persists", func(t *testing.T) {
+		reloaded, err := NewAPIKeyStore(path)
+		if err != nil {
+			t.Fatalf("NewAPIKeyStore() error = %v", err)
+		}
+		if _, err := reloaded.Lookup(plaintext); err != nil {
+			t.Fatalf("Lookup(valid key) error = %v", err)
+		}
+		if _, err := reloaded.Lookup(revokedPlaintext); err != ErrUnknownAPIKey {
+			t.Fatalf("Lookup(revoked key) error = %v, want %v", err, ErrUnknownAPIKey)
+		}
+	})
+}
+
+func TestAPIKeyStore_YAMLRoundTrip(t *testing.T) {
+	keys := []*APIKey{
+		{
+			ID:        "6f1c2a9e-3b7d-4c1e-9a51-0d2f7e8b4c10",
+			Name:      `Payroll "sync": O'Brien # 2`,
+			Roles:     []string{"recruiter", "hiring-manager"},
+			RateLimit: 2.5,
+			KeyHash:   hashAPIKey("hrk_one"),
+			CreatedAt: time.Date(2026, 3, 1, 9, 30, 0, 123, time.UTC),
+		},
+		{
+			ID:        "a3e8d1c4-5f6b-4a7c-8d9e-0f1a2b3c4d5e",
+			Name:      "Reporting",
+			Roles:     []string{},
+			KeyHash:   hashAPIKey("hrk_two"),
+			CreatedAt: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	data, err := marshalAPIKeys(keys)
+	if err != nil {
+		t.Fatalf("marshalAPIKeys() error = %v", err)
+	}
+	got, err := unmarshalAPIKeys(data)
+	if err != nil {
+		t.Fatalf("unmarshalAPIKeys() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, keys) {
+		t.Fatalf("round trip = %+v, want %+v", got, keys)
+	}
+
+	data, err = marshalAPIKeys(nil)
+	if err != nil {
+		t.Fatalf("marshalAPIKeys(nil) error = %v", err)
+	}
+	empty, err := unmarshalAPIKeys(data)
+	if err != nil || len(empty) != 0 {
+		t.Fatalf("empty round trip = %v, %v", empty, err)
+	}
+}
+
+func TestNewAPIKeyStore_HandWrittenYAML(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api-keys.yaml")
+	yaml := strings.Join([]string{
+		"# Integrations",
+		"---",
+		"- id: ats",
+		"  name: ATS sync # nightly",
+		"  roles:",
+		"    - recruiter",
+		"    - 'hiring-manager'",
+		"  rateLimit: 5",
+		"  keyHash: " + hashAPIKey("hrk_ats"),
+		"  createdAt: 2026-03-01T09:30:00Z",
+		"-",
+		"  id: \"reports\"",
+		"  name: Reports",
+		"  roles: [admin]",
+		"  keyHash: '" + hashAPIKey("hrk_reports") + "'",
+		"",
+	}, "\n")
+	if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := NewAPIKeyStore(path)
+	if err != nil {
+		t.Fatalf("NewAPIKeyStore() error = %v", err)
+	}
+
+	ats, err := store.Lookup("hrk_ats")
+	if err != nil {
+		t.Fatalf("Lookup(hrk_ats) error = %v", err)
+	}
+	if ats.Name != "ATS sync" || !reflect.DeepEqual(ats.Roles, []string{"recruiter", "hiring-manager"}) || ats.RateLimit != 5 {
+		t.Fatalf("ats = %+v", ats)
+	}
+
+	reports, err := store.Lookup("hrk_reports")
+	if err != nil {
+		t.Fatalf("Lookup(hrk_reports) error = %v", err)
+	}
+	if reports.ID != "reports" || !reflect.DeepEqual(reports.Roles, []string{"admin"}) {
+		t.Fatalf("reports = %+v", reports)
+	}
+}
+
+func TestNewAPIKeyStore_InvalidYAML(t *testing.T) {
+	tests := map[string]string{
+		"unknown field":    "- id: a\n  keyHash: b\n  owner: c\n",
+		"missing key hash": "- id: a\n  name: b\n",
+		"not a sequence":   "id: a\nkeyHash: b\n",
+		"bad rate limit":   "- id: a\n  keyHash: b\n  rateLimit: fast\n",
+		"unterminated":     "- id: \"a\n  keyHash: b\n",
+	}
+
+	for name, yaml := range tests {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "api-keys.yaml")
+			if err := os.WriteFile(path, []byte(yaml), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := NewAPIKeyStore(path); err == nil {
+				t.Fatal("NewAPIKeyStore() error = nil, want an error")
+			}
+		})
+	}
+}
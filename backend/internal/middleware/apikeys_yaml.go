@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// The API keys file is YAML: a sequence with one mapping per key.
+//
+//	# Issued through POST /api/v1/admin/api-keys
+//	- id: 6f1c2a9e-3b7d-4c1e-9a51-0d2f7e8b4c10
+//	  name: ATS sync
+//	  roles: [recruiter]
+//	  rateLimit: 5
+//	  keyHash: 9f86d081884c7d659a2feaa0c55ad015a3bf4f1b2b0b822cd15d6c15b0f00a08
+//	  createdAt: 2026-03-01T09:30:00Z
+
+// apiKeysFileHeader starts every file the store writes
+const apiKeysFileHeader = "# API keys for integrations, managed through /api/v1/admin/api-keys.\n" +
+	"# Only a SHA-256 hash of each key is stored.\n"
+
+// marshalAPIKeys writes keys as the YAML API keys file
+func marshalAPIKeys(keys []*APIKey) ([]byte, error) {
+	if keys == nil {
+		keys = []*APIKey{}
+	}
+
+	var b bytes.Buffer
+	b.WriteString(apiKeysFileHeader)
+	encoder := yaml.NewEncoder(&b)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(keys); err != nil {
+		return nil, err
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// unmarshalAPIKeys reads the YAML API keys file, rejecting unknown fields
+func unmarshalAPIKeys(data []byte) ([]*APIKey, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+
+	var keys []*APIKey
+	if err := decoder.Decode(&keys); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	for i, key := range keys {
+		if key == nil {
+			return nil, fmt.Errorf("key %d is empty", i+1)
+		}
+	}
+	return keys, nil
+}
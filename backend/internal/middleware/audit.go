@@ -0,0 +1,326 @@
+package middleware
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"hr-recruiting/internal/requestid"
+)
+
+// Audit log limits
+const (
+	auditQueueSize   = 1024
+	maxAuditBodySize = 64 << 10
+)
+
+// ErrAuditLogNotFile is returned when tailing an audit log written to stdout
+var ErrAuditLogNotFile = errors.New("audit log is not written to a file")
+
+// auditRedactedSuffixes mark request body fields holding personal data or
+// credentials. A field is redacted at any depth when its name, lowercased and
+// without '_' or '-', ends in one of them, so candidateEmail, client_secret
+// and accessToken are caught as well as email, secret and token.
+var auditRedactedSuffixes = []string{
+	"email",
+	"phone",
+	"firstname",
+	"lastname",
+	"address",
+	"password",
+	"secret",
+	"token",
+	"apikey",
+}
+
+// AuditEntry records one authenticated change
+type AuditEntry struct {
+	Time      time.Time       `json:"time"`
+	RequestID string          `json:"request_id,omitempty"`
+	UserID    string          `json:"user_id"`
+	UserEmail string          `json:"user_email,omitempty"`
+	Method    string          `json:"method"`
+	Path      string          `json:"path"`
+	Status    int             `json:"status"`
+	Body      json.RawMessage `json:"body,omitempty"`
+}
+
+// AuditLog writes audit entries as JSON lines from a background goroutine,
+// so requests never wait on disk. File logs start a new file at midnight,
+// moving the previous day's entries to path.YYYY-MM-DD.
+type AuditLog struct {
+	path    string
+	entries chan AuditEntry
+	done    chan struct{}
+
+	mu     sync.Mutex
+	closed bool
+
+	// Owned by the writer goroutine
+	out    io.Writer
+	file   *os.File
+	opened time.Time
+}
+
+// NewAuditLog opens the audit log at path, or writes to stdout when path is
+// empty
+func NewAuditLog(path string) (*AuditLog, error) {
+	l := &AuditLog{
+		path:    path,
+		entries: make(chan AuditEntry, auditQueueSize),
+		done:    make(chan struct{}),
+		out:     os.Stdout,
+	}
+	if path != "" {
+		if err := l.open(); err != nil {
+			return nil, err
+		}
+	}
+
+	go l.run()
+	return l, nil
+}
+
+// Record queues an entry. Entries are dropped, and the drop logged, when the
+// writer has fallen too far behind.
+func (l *AuditLog) Record(entry AuditEntry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.closed {
+		return
+	}
+
+	select {
+	case l.entries <- entry:
+	default:
+		slog.Error("audit log queue full, entry dropped", "method", entry.Method, "path", entry.Path, "user_id", entry.UserID)
+	}
+}
+
+// Shutdown writes queued entries and closes the file
+func (l *AuditLog) Shutdown(ctx context.Context) error {
+	l.mu.Lock()
+	if !l.closed {
+		l.closed = true
+		close(l.entries)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-l.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Tail returns up to n of the most recent entries in today's file, oldest
+// first
+func (l *AuditLog) Tail(n int) ([]json.RawMessage, error) {
+	if l.path == "" {
+		return nil, ErrAuditLogNotFile
+	}
+
+	file, err := os.Open(l.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	lines := make([]json.RawMessage, 0, n)
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64<<10), 2*maxAuditBodySize)
+	for scanner.Scan() {
+		// A line still being written is not valid JSON yet
+		if !json.Valid(scanner.Bytes()) {
+			continue
+		}
+		if len(lines) == n {
+			lines = lines[1:]
+		}
+		lines = append(lines, json.RawMessage(bytes.Clone(scanner.Bytes())))
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return lines, nil
+}
+
+func (l *AuditLog) run() {
+	defer close(l.done)
+	defer func() {
+		if l.file != nil {
+			l.file.Close()
+		}
+	}()
+
+	for entry := range l.entries {
+		if l.file != nil && !sameDay(l.opened, entry.Time) {
+			if err := l.rotate(entry.Time); err != nil {
+				slog.Error("audit log rotation failed", "error", err)
+			}
+		}
+
+		line, err := json.Marshal(entry)
+		if err != nil {
+			slog.Error("failed to encode audit entry", "error", err)
+			continue
+		}
+		if _, err := l.out.Write(append(line, '\n')); err != nil {
+			slog.Error("failed to write audit entry", "error", err)
+		}
+	}
+}
+
+func (l *AuditLog) open() error {
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit log: %w", err)
+	}
+
+	opened := time.Now()
+	if info, err := file.Stat(); err == nil && info.Size() > 0 {
+		// Entries already in the file date from its last write
+		opened = info.ModTime()
+	}
+	l.file, l.out, l.opened = file, file, opened
+	return nil
+}
+
+// rotate moves the current file aside under its date and starts a new one
+// for the day of now. If the move fails, writing continues in the current
+// file.
+func (l *AuditLog) rotate(now time.Time) error {
+	l.file.Close()
+	renameErr := os.Rename(l.path, l.path+"."+l.opened.Format("2006-01-02"))
+	if err := l.open(); err != nil {
+		l.file, l.out = nil, io.Discard
+		return err
+	}
+	l.opened = now
+	return renameErr
+}
+
+func sameDay(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
+
+// AuditLogger records every POST, PUT, PATCH and DELETE made by an
+// authenticated caller: who made it, the request body with personal data
+// redacted, and the response status. It must be registered after
+// AuthMiddleware.
+func AuditLogger(auditLog *AuditLog) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			user, ok := GetUserFromContext(r.Context())
+			if !ok || !isMutation(r.Method) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			var body json.RawMessage
+			if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") || r.Header.Get("Content-Type") == "" {
+				raw, err := io.ReadAll(io.LimitReader(r.Body, maxAuditBodySize+1))
+				r.Body = readCloser{io.MultiReader(bytes.NewReader(raw), r.Body), r.Body}
+				if err == nil && len(raw) <= maxAuditBodySize {
+					body = redactBody(raw)
+				}
+			}
+
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+			next.ServeHTTP(ww, r)
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+			userID, _ := user["id"].(string)
+			userEmail, _ := user["email"].(string)
+			auditLog.Record(AuditEntry{
+				Time:      time.Now(),
+				RequestID: requestid.FromContext(r.Context()),
+				UserID:    userID,
+				UserEmail: userEmail,
+				Method:    r.Method,
+				Path:      r.URL.Path,
+				Status:    status,
+				Body:      body,
+			})
+		})
+	}
+}
+
+func isMutation(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// readCloser reads the buffered body prefix followed by the rest of the
+// original body, closing the original
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// redactBody returns a JSON body with personal fields replaced by
+// "[REDACTED]", or nil if the body is empty or not JSON
+func redactBody(raw []byte) json.RawMessage {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return nil
+	}
+
+	redacted, err := json.Marshal(redactValue(value))
+	if err != nil {
+		return nil
+	}
+	return redacted
+}
+
+// isRedactedField reports whether a body field named key is redacted
+func isRedactedField(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	for _, suffix := range auditRedactedSuffixes {
+		if strings.HasSuffix(normalized, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func redactValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			if isRedactedField(key) {
+				v[key] = "[REDACTED]"
+			} else {
+				v[key] = redactValue(field)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactValue(item)
+		}
+	}
+	return value
+}
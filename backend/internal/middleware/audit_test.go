@@ -0,0 +1,227 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRedactBody(t *testing.T) {
+	tests := []struct {
+		name string
+		body string
+		want string
+	}{
+		{
+			name: "personal fields",
+			body: `{"jobId":"j1","email":"ada@example.com","Phone":"123","firstName":"Ada","last_name":"Lovelace","status":"NEW"}`,
+			want: `{"Phone":"[REDACTED]","email":"[REDACTED]","firstName":"[REDACTED]","jobId":"j1","last_name":"[REDACTED]","status":"NEW"}`,
+		},
+		{
+			name: "suffixed fields",
+			body: `{"candidateEmail":"ada@example.com","client_secret":"s","accessToken":"t","refresh-token":"r","newPassword":"p","homeAddress":"x","X-API-Key":"k"}`,
+			want: `{"X-API-Key":"[REDACTED]","accessToken":"[REDACTED]","candidateEmail":"[REDACTED]","client_secret":"[REDACTED]","homeAddress":"[REDACTED]","newPassword":"[REDACTED]","refresh-token":"[REDACTED]"}`,
+		},
+		{
+			name: "nested objects and arrays",
+			body: `{"candidate":{"contact":{"EMAIL":"ada@example.com"}},"references":[{"name":"Grace","phone":"456"}],"salary":95000.50}`,
+			want: `{"candidate":{"contact":{"EMAIL":"[REDACTED]"}},"references":[{"name":"Grace","phone":"[REDACTED]"}],"salary":95000.50}`,
+		},
+		{
+			name: "objects under redacted fields are replaced whole",
+			body: `{"token":{"value":"t","expiresIn":3600}}`,
+			want: `{"token":"[REDACTED]"}`,
+		},
+		{
+			name: "fields only starting with a marker are kept",
+			body: `{"emailTemplate":"rejection","tokenCount":3,"secretary":"no"}`,
+			want: `{"emailTemplate":"rejection","secretary":"no","tokenCount":3}`,
+		},
+		{name: "not JSON", body: `email=ada@example.com`, want: ``},
+		{name: "empty", body: ``, want: ``},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(redactBody([]byte(tt.body))); got != tt.want {
+				t.Fatalf("redactBody() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+// readAuditFile returns the entries written to the audit log file at path
+func readAuditFile(t *testing.T, path string) []AuditEntry {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var entries []AuditEntry
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry AuditEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("audit line %q is not JSON: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestAuditLogger(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	auditLog, err := NewAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var received string
+	handler := AuditLogger(auditLog)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = string(body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	send := func(method string, user map[string]interface{}, body string) {
+		req := httptest.NewRequest(method, "/api/v1/applications", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if user != nil {
+			req = req.WithContext(WithUser(req.Context(), user))
+		}
+		handler.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	recruiter := map[string]interface{}{"id": "u1", "email": "grace@example.com", "roles": []string{"recruiter"}}
+	body := `{"jobId":"j1","candidateEmail":"ada@example.com"}`
+	send(http.MethodPost, recruiter, body)
+	if received != body {
+		t.Fatalf("handler read body %q, want it unchanged by auditing", received)
+	}
+	send(http.MethodGet, recruiter, "")
+	send(http.MethodPost, nil, body)
+	send(http.MethodDelete, recruiter, "")
+
+	if err := auditLog.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	entries := readAuditFile(t, path)
+	if len(entries) != 2 {
+		t.Fatalf("recorded %d entries, want the two authenticated mutations", len(entries))
+	}
+	entry := entries[0]
+	if entry.UserID != "u1" || entry.UserEmail != "grace@example.com" || entry.Method != http.MethodPost ||
+		entry.Path != "/api/v1/applications" || entry.Status != http.StatusCreated {
+		t.Fatalf("entry = %+v", entry)
+	}
+	if string(entry.Body) != `{"candidateEmail":"[REDACTED]","jobId":"j1"}` {
+		t.Fatalf("body = %s, want the email redacted", entry.Body)
+	}
+	if entries[1].Method != http.MethodDelete || entries[1].Body != nil {
+		t.Fatalf("second entry = %+v", entries[1])
+	}
+}
+
+// blockingWriter holds every write until release is closed
+type blockingWriter struct {
+	release chan struct{}
+	lines   chan string
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.lines <- string(p)
+	return len(p), nil
+}
+
+func TestAuditLog_RecordDoesNotBlock(t *testing.T) {
+	out := &blockingWriter{release: make(chan struct{}), lines: make(chan string, 10)}
+	auditLog := &AuditLog{entries: make(chan AuditEntry, 2), done: make(chan struct{}), out: out}
+	go auditLog.run()
+
+	// The writer takes one entry and blocks on it; two more fill the queue
+	// and the rest are dropped
+	recorded := make(chan struct{})
+	go func() {
+		for i := 0; i < 10; i++ {
+			auditLog.Record(AuditEntry{Time: time.Now(), Method: http.MethodPost, Path: "/jobs", UserID: "u1"})
+		}
+		close(recorded)
+	}()
+	select {
+	case <-recorded:
+	case <-time.After(time.Second):
+		t.Fatal("Record blocked on a stalled writer")
+	}
+
+	close(out.release)
+	if err := auditLog.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if written := len(out.lines); written < 1 || written > 3 {
+		t.Fatalf("wrote %d entries, want the queued ones only", written)
+	}
+
+	// Entries recorded after shutdown are ignored
+	auditLog.Record(AuditEntry{Time: time.Now()})
+}
+
+func TestAuditLog_Shutdown(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	auditLog, err := NewAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 100; i++ {
+		auditLog.Record(AuditEntry{Time: time.Now(), Method: http.MethodPut, Path: "/jobs/j1", UserID: "u1"})
+	}
+	if err := auditLog.Shutdown(context.Background()); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if entries := readAuditFile(t, path); len(entries) != 100 {
+		t.Fatalf("flushed %d entries, want all 100 queued before shutdown", len(entries))
+	}
+
+	tail, err := auditLog.Tail(3)
+	if err != nil || len(tail) != 3 {
+		t.Fatalf("Tail(3) = %d entries, %v", len(tail), err)
+	}
+	if _, err := (&AuditLog{}).Tail(3); err != ErrAuditLogNotFile {
+		t.Fatalf("Tail() on stdout error = %v, want ErrAuditLogNotFile", err)
+	}
+}
+
+func TestAuditLog_RotatesAtMidnight(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	auditLog, err := NewAuditLog(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	today := time.Now()
+	tomorrow := today.AddDate(0, 0, 1)
+	auditLog.Record(AuditEntry{Time: today, Path: "/today"})
+	auditLog.Record(AuditEntry{Time: tomorrow, Path: "/tomorrow-1"})
+	auditLog.Record(AuditEntry{Time: tomorrow, Path: "/tomorrow-2"})
+	if err := auditLog.Shutdown(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	rotated := readAuditFile(t, path+"."+today.Format("2006-01-02"))
+	if len(rotated) != 1 || rotated[0].Path != "/today" {
+		t.Fatalf("rotated file holds %+v, want only today's entry", rotated)
+	}
+	current := readAuditFile(t, path)
+	if len(current) != 2 || current[0].Path != "/tomorrow-1" || current[1].Path != "/tomorrow-2" {
+		t.Fatalf("current file holds %+v, want both of tomorrow's entries", current)
+	}
+}
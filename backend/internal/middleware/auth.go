@@ -2,7 +2,9 @@ package middleware
 
 import (
 	"context"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 )
 
@@ -10,36 +12,87 @@ type contextKey string
 
 const userContextKey contextKey = "user"
 
-// AuthMiddleware handles authentication
-func AuthMiddleware(next http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Get token from Authorization header
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			// No auth required for public endpoints
-			next.ServeHTTP(w, r)
-			return
-		}
+// AuthMiddleware validates bearer tokens, or API keys sent as X-API-Key, and
+// stores the caller in the context. A bearer token wins if both are sent.
+func AuthMiddleware(validator *JWTValidator, apiKeys *APIKeyStore) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Get token from Authorization header
+			authHeader := r.Header.Get("Authorization")
+			if authHeader == "" && apiKeys != nil && r.Header.Get("X-API-Key") != "" {
+				authenticateAPIKey(apiKeys, next, w, r)
+				return
+			}
+			if authHeader == "" {
+				// No auth required for public endpoints
+				next.ServeHTTP(w, r)
+				return
+			}
 
-		// Extract token
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || parts[0] != "Bearer" {
-			http.Error(w, "Invalid authorization header", http.StatusUnauthorized)
-			return
-		}
+			// Extract token
+			parts := strings.Split(authHeader, " ")
+			if len(parts) != 2 || parts[0] != "Bearer" {
+				respondError(w, http.StatusUnauthorized, "Invalid authorization header", nil)
+				return
+			}
 
-		token := parts[1]
+			claims, err := validator.Validate(parts[1])
+			if err != nil {
+				respondError(w, http.StatusUnauthorized, "Invalid token", err)
+				return
+			}
 
-		// TODO: Validate token with Hub-HRMS or JWT library
-		// For now, just pass it through
-		user := map[string]interface{}{
-			"token": token,
-		}
+			roles := []string(claims.Roles)
+			if roles == nil {
+				roles = []string{}
+			}
+			user := map[string]interface{}{
+				"id":    claims.Subject,
+				"email": claims.Email,
+				"roles": roles,
+			}
 
-		// Add user to context
-		ctx := context.WithValue(r.Context(), userContextKey, user)
-		next.ServeHTTP(w, r.WithContext(ctx))
-	})
+			setLogUserID(r.Context(), claims.Subject)
+
+			// Add user to context
+			next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), user)))
+		})
+	}
+}
+
+// authenticateAPIKey resolves the caller from the X-API-Key header
+func authenticateAPIKey(apiKeys *APIKeyStore, next http.Handler, w http.ResponseWriter, r *http.Request) {
+	key, err := apiKeys.Lookup(r.Header.Get("X-API-Key"))
+	if err != nil {
+		respondError(w, http.StatusUnauthorized, "Invalid API key", err)
+		return
+	}
+
+	if allowed, retryAfter := apiKeys.allow(key); !allowed {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+		respondError(w, http.StatusTooManyRequests, "API key rate limit exceeded", nil)
+		return
+	}
+
+	roles := key.Roles
+	if roles == nil {
+		roles = []string{}
+	}
+	user := map[string]interface{}{
+		"id":    key.ID,
+		"name":  key.Name,
+		"roles": roles,
+	}
+
+	setLogUserID(r.Context(), key.ID)
+
+	next.ServeHTTP(w, r.WithContext(WithUser(r.Context(), user)))
+}
+
+// WithUser returns a copy of ctx with user as the authenticated caller, as
+// AuthMiddleware stores it: a map with id, roles and email or name
+func WithUser(ctx context.Context, user map[string]interface{}) context.Context {
+	return context.WithValue(ctx, userContextKey, user)
 }
 
 // GetUserFromContext retrieves user from context
@@ -53,9 +106,48 @@ func RequireAuth(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		_, ok := GetUserFromContext(r.Context())
 		if !ok {
-			http.Error(w, "Unauthorized", http.StatusUnauthorized)
+			respondError(w, http.StatusUnauthorized, "Unauthorized", nil)
 			return
 		}
 		next.ServeHTTP(w, r)
 	})
-}
\ No newline at end of file
+}
+
+// GetUserRoles returns the roles of the authenticated caller, if any
+func GetUserRoles(ctx context.Context) []string {
+	user, ok := GetUserFromContext(ctx)
+	if !ok {
+		return nil
+	}
+	roles, _ := user["roles"].([]string)
+	return roles
+}
+
+// HasRole reports whether the authenticated caller holds any of roles
+func HasRole(ctx context.Context, roles ...string) bool {
+	for _, held := range GetUserRoles(ctx) {
+		for _, role := range roles {
+			if held == role {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// RequireRole middleware requires the caller to hold at least one of roles
+func RequireRole(roles ...string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := GetUserFromContext(r.Context()); !ok {
+				respondError(w, http.StatusUnauthorized, "Unauthorized", nil)
+				return
+			}
+			if !HasRole(r.Context(), roles...) {
+				respondError(w, http.StatusForbidden, "Insufficient permissions", nil)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
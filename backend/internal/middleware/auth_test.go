@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestRequireRole(t *testing.T) {
+	handler := RequireRole("recruiter", "admin")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name       string
+		user       map[string]interface{}
+		wantStatus int
+	}{
+		{name: "recruiter", user: map[string]interface{}{"id": "u1", "roles": []string{"recruiter"}}, wantStatus: http.StatusOK},
+		{name: "admin among other roles", user: map[string]interface{}{"id": "u2", "roles": []string{"manager", "admin"}}, wantStatus: http.StatusOK},
+		{name: "wrong role", user: map[string]interface{}{"id": "u3", "roles": []string{"manager"}}, wantStatus: http.StatusForbidden},
+		{name: "no roles", user: map[string]interface{}{"id": "u4", "roles": []string{}}, wantStatus: http.StatusForbidden},
+		{name: "role names are exact", user: map[string]interface{}{"id": "u5", "roles": []string{"Recruiter"}}, wantStatus: http.StatusForbidden},
+		{name: "anonymous", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs/j1/publish", nil)
+			if tt.user != nil {
+				req = req.WithContext(WithUser(req.Context(), tt.user))
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"bytes"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/golang-lru/v2/expirable"
+)
+
+// cachedHeaders are the response headers replayed on a cache hit
+var cachedHeaders = []string{"Content-Type", "X-Total-Count", "X-Next-Cursor"}
+
+// cacheEntry is a stored response
+type cacheEntry struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// ResponseCache is an in-memory LRU cache of GET responses keyed on URL
+type ResponseCache struct {
+	entries *expirable.LRU[string, *cacheEntry]
+}
+
+// NewResponseCache creates a cache holding up to size responses for ttl
+func NewResponseCache(size int, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{entries: expirable.NewLRU[string, *cacheEntry](size, nil, ttl)}
+}
+
+// Middleware serves GET requests from the cache and stores 200 responses
+func (c *ResponseCache) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		key := r.URL.RequestURI()
+		if entry, ok := c.entries.Get(key); ok {
+			for name, values := range entry.header {
+				w.Header()[name] = values
+			}
+			w.Header().Set("X-Cache", "HIT")
+			w.WriteHeader(entry.status)
+			w.Write(entry.body)
+			return
+		}
+
+		w.Header().Set("X-Cache", "MISS")
+		recorder := &responseRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		if recorder.status == http.StatusOK {
+			header := make(http.Header)
+			for _, name := range cachedHeaders {
+				if value := w.Header().Get(name); value != "" {
+					header.Set(name, value)
+				}
+			}
+			c.entries.Add(key, &cacheEntry{status: recorder.status, header: header, body: recorder.body.Bytes()})
+		}
+	})
+}
+
+// InvalidateOnSuccess evicts cached responses for the paths returned by
+// paths (including any query string variants) after a successful request
+func (c *ResponseCache) InvalidateOnSuccess(paths func(r *http.Request) []string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(recorder, r)
+
+			if recorder.status < 300 {
+				c.Invalidate(paths(r)...)
+			}
+		})
+	}
+}
+
+// Invalidate evicts every cached response whose path matches one of paths
+func (c *ResponseCache) Invalidate(paths ...string) {
+	for _, key := range c.entries.Keys() {
+		path, _, _ := strings.Cut(key, "?")
+		if slices.Contains(paths, path) {
+			c.entries.Remove(key)
+		}
+	}
+}
+
+// statusRecorder remembers the status code written through it
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// responseRecorder remembers the status code and a copy of the body
+type responseRecorder struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+func (r *responseRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+	return r.ResponseWriter.Write(b)
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// countingHandler answers with the number of times it has been called, so a
+// repeated body shows the response came from the cache
+type countingHandler struct {
+	calls  int
+	status int
+}
+
+func (h *countingHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.calls++
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Total-Count", "12")
+	w.Header().Set("X-Request-Only", "not replayed")
+	if h.status != 0 {
+		w.WriteHeader(h.status)
+	}
+	fmt.Fprintf(w, `{"call":%d}`, h.calls)
+}
+
+func get(t *testing.T, handler http.Handler, method, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(method, target, nil))
+	return rec
+}
+
+func TestResponseCache_HitAndMiss(t *testing.T) {
+	cache := NewResponseCache(10, time.Minute)
+	next := &countingHandler{}
+	handler := cache.Middleware(next)
+
+	miss := get(t, handler, http.MethodGet, "/api/v1/jobs?page=1")
+	if miss.Header().Get("X-Cache") != "MISS" || miss.Body.String() != `{"call":1}` {
+		t.Fatalf("first request = %s %q, want a miss", miss.Header().Get("X-Cache"), miss.Body.String())
+	}
+
+	hit := get(t, handler, http.MethodGet, "/api/v1/jobs?page=1")
+	if hit.Header().Get("X-Cache") != "HIT" || hit.Body.String() != `{"call":1}` || hit.Code != http.StatusOK {
+		t.Fatalf("second request = %d %s %q, want the cached response", hit.Code, hit.Header().Get("X-Cache"), hit.Body.String())
+	}
+	if hit.Header().Get("Content-Type") != "application/json" || hit.Header().Get("X-Total-Count") != "12" {
+		t.Fatalf("cached headers = %v", hit.Header())
+	}
+	if hit.Header().Get("X-Request-Only") != "" {
+		t.Fatal("a header outside the cached set was replayed")
+	}
+
+	// The query string is part of the key
+	if rec := get(t, handler, http.MethodGet, "/api/v1/jobs?page=2"); rec.Header().Get("X-Cache") != "MISS" {
+		t.Fatal("a different query string was served from the cache")
+	}
+	if next.calls != 2 {
+		t.Fatalf("handler called %d times, want 2", next.calls)
+	}
+}
+
+func TestResponseCache_NotCached(t *testing.T) {
+	cache := NewResponseCache(10, time.Minute)
+
+	failing := &countingHandler{status: http.StatusBadGateway}
+	handler := cache.Middleware(failing)
+	get(t, handler, http.MethodGet, "/api/v1/jobs/1")
+	if rec := get(t, handler, http.MethodGet, "/api/v1/jobs/1"); rec.Header().Get("X-Cache") != "MISS" || failing.calls != 2 {
+		t.Fatal("an error response was cached")
+	}
+
+	next := &countingHandler{}
+	handler = cache.Middleware(next)
+	get(t, handler, http.MethodPost, "/api/v1/jobs/2")
+	rec := get(t, handler, http.MethodPost, "/api/v1/jobs/2")
+	if rec.Header().Get("X-Cache") != "" || next.calls != 2 {
+		t.Fatal("a POST was cached")
+	}
+}
+
+func TestResponseCache_Expiry(t *testing.T) {
+	cache := NewResponseCache(10, 10*time.Millisecond)
+	next := &countingHandler{}
+	handler := cache.Middleware(next)
+
+	get(t, handler, http.MethodGet, "/api/v1/jobs")
+	time.Sleep(20 * time.Millisecond)
+	if rec := get(t, handler, http.MethodGet, "/api/v1/jobs"); rec.Header().Get("X-Cache") != "MISS" || next.calls != 2 {
+		t.Fatal("an expired response was served")
+	}
+}
+
+func TestResponseCache_LeastRecentlyUsedEviction(t *testing.T) {
+	cache := NewResponseCache(2, time.Minute)
+	next := &countingHandler{}
+	handler := cache.Middleware(next)
+
+	get(t, handler, http.MethodGet, "/api/v1/jobs/1")
+	get(t, handler, http.MethodGet, "/api/v1/jobs/2")
+	get(t, handler, http.MethodGet, "/api/v1/jobs/1") // 1 is now the most recently used
+	get(t, handler, http.MethodGet, "/api/v1/jobs/3") // evicts 2
+
+	// Checked in order, since the miss caches 2 again and evicts another
+	for _, check := range []struct{ target, want string }{
+		{"/api/v1/jobs/1", "HIT"},
+		{"/api/v1/jobs/3", "HIT"},
+		{"/api/v1/jobs/2", "MISS"},
+	} {
+		if got := get(t, handler, http.MethodGet, check.target).Header().Get("X-Cache"); got != check.want {
+			t.Errorf("%s X-Cache = %s, want %s", check.target, got, check.want)
+		}
+	}
+}
+
+func TestResponseCache_InvalidateOnSuccess(t *testing.T) {
+	cache := NewResponseCache(10, time.Minute)
+	read := cache.Middleware(&countingHandler{})
+	for _, target := range []string{"/api/v1/jobs", "/api/v1/jobs?page=2", "/api/v1/jobs/1", "/api/v1/jobs/2"} {
+		get(t, read, http.MethodGet, target)
+	}
+
+	paths := func(r *http.Request) []string { return []string{"/api/v1/jobs", "/api/v1/jobs/1"} }
+	mutation := &countingHandler{status: http.StatusConflict}
+	update := cache.InvalidateOnSuccess(paths)(mutation)
+	get(t, update, http.MethodPut, "/api/v1/jobs/1")
+	if got := get(t, read, http.MethodGet, "/api/v1/jobs/1").Header().Get("X-Cache"); got != "HIT" {
+		t.Fatal("a failed mutation evicted the cache")
+	}
+
+	mutation.status = http.StatusOK
+	get(t, update, http.MethodPut, "/api/v1/jobs/1")
+	for target, want := range map[string]string{
+		"/api/v1/jobs":        "MISS",
+		"/api/v1/jobs?page=2": "MISS",
+		"/api/v1/jobs/1":      "MISS",
+		"/api/v1/jobs/2":      "HIT",
+	} {
+		if got := get(t, read, http.MethodGet, target).Header().Get("X-Cache"); got != want {
+			t.Errorf("%s X-Cache = %s, want %s after the update", target, got, want)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+)
+
+// ConditionalGet sets an ETag derived from the response body on successful
+// GET responses and answers 304 Not Modified when the client's If-None-Match
+// already names it. The tag is a hash of the body, so nothing is stored
+// server-side; the handler still runs, but the body is not resent.
+func ConditionalGet(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		buffered := &bufferedResponse{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(buffered, r)
+
+		if buffered.status != http.StatusOK {
+			w.WriteHeader(buffered.status)
+			w.Write(buffered.body.Bytes())
+			return
+		}
+
+		etag := bodyETag(buffered.body.Bytes())
+		w.Header().Set("ETag", etag)
+
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			// A 304 carries no body, so drop the headers that describe one
+			w.Header().Del("Content-Type")
+			w.Header().Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write(buffered.body.Bytes())
+	})
+}
+
+// bodyETag returns a strong ETag of the first 16 hex characters of the
+// body's SHA-256
+func bodyETag(body []byte) string {
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:])[:16] + `"`
+}
+
+// etagMatches applies the weak comparison If-None-Match calls for: "*"
+// matches any current representation, and W/ prefixes are ignored
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// bufferedResponse holds back the status and body until the handler returns
+type bufferedResponse struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+	body        bytes.Buffer
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if !b.wroteHeader {
+		b.status = status
+		b.wroteHeader = true
+	}
+}
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	b.wroteHeader = true
+	return b.body.Write(p)
+}
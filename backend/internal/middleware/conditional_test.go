@@ -0,0 +1,113 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// detailHandler answers with body and status, counting its calls
+type detailHandler struct {
+	body   string
+	status int
+	calls  int
+}
+
+func (h *detailHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.calls++
+	w.Header().Set("Content-Type", "application/json")
+	if h.status != 0 {
+		w.WriteHeader(h.status)
+	}
+	fmt.Fprint(w, h.body)
+}
+
+// conditionalGet sends method to handler with ifNoneMatch, if set
+func conditionalGet(handler http.Handler, method, ifNoneMatch string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/v1/jobs/job-1", nil)
+	if ifNoneMatch != "" {
+		req.Header.Set("If-None-Match", ifNoneMatch)
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestConditionalGet(t *testing.T) {
+	next := &detailHandler{body: `{"id":"job-1","title":"Backend Engineer"}`}
+	handler := ConditionalGet(next)
+
+	first := conditionalGet(handler, http.MethodGet, "")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || first.Body.String() != next.body {
+		t.Fatalf("response = %d %s, want 200 with the job", first.Code, first.Body)
+	}
+	// "..." around the first 16 hex characters of the body's SHA-256
+	if len(etag) != 18 || etag[0] != '"' || etag[17] != '"' || strings.Trim(etag[1:17], "0123456789abcdef") != "" {
+		t.Fatalf("ETag = %q, want 16 quoted hex characters", etag)
+	}
+	if etag != bodyETag([]byte(next.body)) {
+		t.Fatalf("ETag = %q, want the body hash %q", etag, bodyETag([]byte(next.body)))
+	}
+
+	tests := []struct {
+		name        string
+		ifNoneMatch string
+		wantStatus  int
+	}{
+		{name: "matching", ifNoneMatch: etag, wantStatus: http.StatusNotModified},
+		{name: "weak match", ifNoneMatch: "W/" + etag, wantStatus: http.StatusNotModified},
+		{name: "one of several", ifNoneMatch: `"0000000000000000", ` + etag, wantStatus: http.StatusNotModified},
+		{name: "wildcard", ifNoneMatch: "*", wantStatus: http.StatusNotModified},
+		{name: "stale", ifNoneMatch: `"0000000000000000"`, wantStatus: http.StatusOK},
+		{name: "unquoted", ifNoneMatch: etag[1:17], wantStatus: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := conditionalGet(handler, http.MethodGet, tt.ifNoneMatch)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if rec.Header().Get("ETag") != etag {
+				t.Fatalf("ETag = %q, want %q", rec.Header().Get("ETag"), etag)
+			}
+			if tt.wantStatus == http.StatusNotModified && (rec.Body.Len() != 0 || rec.Header().Get("Content-Type") != "") {
+				t.Fatalf("304 sent body %q and Content-Type %q", rec.Body, rec.Header().Get("Content-Type"))
+			}
+			if tt.wantStatus == http.StatusOK && rec.Body.String() != next.body {
+				t.Fatalf("body = %s, want the job", rec.Body)
+			}
+		})
+	}
+
+	t.Run("modified data", func(t *testing.T) {
+		next.body = `{"id":"job-1","title":"Staff Backend Engineer"}`
+		rec := conditionalGet(handler, http.MethodGet, etag)
+		if rec.Code != http.StatusOK || rec.Body.String() != next.body {
+			t.Fatalf("response = %d %s, want 200 with the updated job", rec.Code, rec.Body)
+		}
+		if got := rec.Header().Get("ETag"); got == etag || got != bodyETag([]byte(next.body)) {
+			t.Fatalf("ETag = %q, want a new tag for the updated job", got)
+		}
+	})
+}
+
+func TestConditionalGet_Passthrough(t *testing.T) {
+	t.Run("errors are not tagged", func(t *testing.T) {
+		next := &detailHandler{body: `{"error":"Not Found"}`, status: http.StatusNotFound}
+		rec := conditionalGet(ConditionalGet(next), http.MethodGet, "*")
+		if rec.Code != http.StatusNotFound || rec.Body.String() != next.body || rec.Header().Get("ETag") != "" {
+			t.Fatalf("response = %d %s, ETag %q; want the 404 untouched", rec.Code, rec.Body, rec.Header().Get("ETag"))
+		}
+	})
+
+	t.Run("other methods are not buffered", func(t *testing.T) {
+		next := &detailHandler{body: `{"id":"job-1"}`, status: http.StatusCreated}
+		rec := conditionalGet(ConditionalGet(next), http.MethodPost, "*")
+		if rec.Code != http.StatusCreated || rec.Header().Get("ETag") != "" {
+			t.Fatalf("response = %d, ETag %q; want the POST untouched", rec.Code, rec.Header().Get("ETag"))
+		}
+	})
+}
@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+)
+
+// Deprecation marks every response as coming from a deprecated API version
+// (RFC 9745), naming successor as its replacement. A non-zero sunset is
+// sent as the Sunset header (RFC 8594), the date the version stops working.
+func Deprecation(sunset time.Time, successor string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Deprecation", "true")
+			if !sunset.IsZero() {
+				w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+			}
+			if successor != "" {
+				w.Header().Add("Link", "<"+successor+`>; rel="successor-version"`)
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
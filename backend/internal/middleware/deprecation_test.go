@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDeprecation(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Add("Link", `</api/v1/jobs?page=2>; rel="next"`)
+		w.WriteHeader(http.StatusTeapot)
+		w.Write([]byte(`{"ok":true}`))
+	})
+
+	t.Run("with sunset", func(t *testing.T) {
+		sunset := time.Date(2027, 1, 31, 0, 0, 0, 0, time.FixedZone("CET", 3600))
+		rec := httptest.NewRecorder()
+		Deprecation(sunset, "/api/v2")(next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/jobs", nil))
+
+		if got := rec.Header().Get("Deprecation"); got != "true" {
+			t.Fatalf("Deprecation = %q, want true", got)
+		}
+		if got := rec.Header().Get("Sunset"); got != "Sat, 30 Jan 2027 23:00:00 GMT" {
+			t.Fatalf("Sunset = %q, want the date in GMT", got)
+		}
+		links := rec.Header().Values("Link")
+		if len(links) != 2 || links[0] != `</api/v2>; rel="successor-version"` {
+			t.Fatalf("Link = %q, want the successor alongside the handler's own link", links)
+		}
+		// The response itself is untouched
+		if rec.Code != http.StatusTeapot || rec.Body.String() != `{"ok":true}` {
+			t.Fatalf("response = %d %s, want the handler's", rec.Code, rec.Body)
+		}
+	})
+
+	t.Run("without sunset or successor", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		Deprecation(time.Time{}, "")(next).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/v1/jobs", nil))
+
+		if rec.Header().Get("Deprecation") != "true" {
+			t.Fatal("response is not marked deprecated")
+		}
+		if _, ok := rec.Header()["Sunset"]; ok {
+			t.Fatal("Sunset sent without a date")
+		}
+		if links := rec.Header().Values("Link"); len(links) != 1 {
+			t.Fatalf("Link = %q, want only the handler's", links)
+		}
+	})
+}
@@ -0,0 +1,63 @@
+package middleware
+
+import (
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// geoRecord is the part of a GeoIP2/GeoLite2 Country record GeoRestrict reads
+type geoRecord struct {
+	Country struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"country"`
+	RegisteredCountry struct {
+		ISOCode string `maxminddb:"iso_code"`
+	} `maxminddb:"registered_country"`
+}
+
+// lookupCountry returns the ISO code of the country ip is in, falling back to
+// the country its network is registered to, or "" if the DB has no record
+func lookupCountry(ipDB *maxminddb.Reader, ip net.IP) (string, error) {
+	var record geoRecord
+	if err := ipDB.Lookup(ip, &record); err != nil {
+		return "", err
+	}
+	if record.Country.ISOCode != "" {
+		return record.Country.ISOCode, nil
+	}
+	return record.RegisteredCountry.ISOCode, nil
+}
+
+// GeoRestrict only lets through requests whose client IP, as set by
+// middleware.RealIP, is in one of allowedCountryCodes. Requests that cannot
+// be placed in a country are refused too, since regional data rules cannot
+// be shown to hold for them. A nil ipDB disables the check.
+func GeoRestrict(allowedCountryCodes []string, ipDB *maxminddb.Reader) func(http.Handler) http.Handler {
+	allowed := make(map[string]bool, len(allowedCountryCodes))
+	for _, code := range allowedCountryCodes {
+		allowed[strings.ToUpper(strings.TrimSpace(code))] = true
+	}
+
+	return func(next http.Handler) http.Handler {
+		if ipDB == nil {
+			return next
+		}
+
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ip := clientIP(r)
+			country, err := lookupCountry(ipDB, net.ParseIP(ip))
+			if err != nil {
+				slog.Warn("geo lookup failed", "ip", ip, "error", err)
+			}
+			if !allowed[country] {
+				respondError(w, http.StatusForbidden, "This resource is not available in your region", nil)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+	"github.com/oschwald/maxminddb-golang"
+)
+
+// openTestGeoDB opens testdata/GeoIP2-Country-Test.mmdb, an IPv4 database of:
+//
+//	89.160.20.0/24   country SE
+//	2.125.160.0/24   country GB
+//	216.160.83.0/24  country US
+//	81.2.69.0/24     registered_country DE only
+func openTestGeoDB(t *testing.T) *maxminddb.Reader {
+	t.Helper()
+	ipDB, err := maxminddb.Open("testdata/GeoIP2-Country-Test.mmdb")
+	if err != nil {
+		t.Fatalf("open test MaxMind DB: %v", err)
+	}
+	t.Cleanup(func() { ipDB.Close() })
+	return ipDB
+}
+
+func TestGeoRestrict(t *testing.T) {
+	ipDB := openTestGeoDB(t)
+	handler := chimiddleware.RealIP(GeoRestrict([]string{"se", " DE "}, ipDB)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})))
+
+	tests := []struct {
+		name       string
+		realIP     string
+		wantStatus int
+	}{
+		{name: "allowed country", realIP: "89.160.20.112", wantStatus: http.StatusOK},
+		{name: "allowed registered country", realIP: "81.2.69.142", wantStatus: http.StatusOK},
+		{name: "blocked country", realIP: "216.160.83.56", wantStatus: http.StatusForbidden},
+		{name: "another blocked country", realIP: "2.125.160.216", wantStatus: http.StatusForbidden},
+		{name: "unknown IP", realIP: "203.0.113.7", wantStatus: http.StatusForbidden},
+		{name: "private IP", realIP: "10.1.2.3", wantStatus: http.StatusForbidden},
+		{name: "unparseable IP", realIP: "not-an-ip", wantStatus: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/candidates/c1", nil)
+			req.Header.Set("X-Real-IP", tt.realIP)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestGeoRestrict_Disabled(t *testing.T) {
+	handler := GeoRestrict([]string{"SE"}, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/candidates/c1", nil)
+	req.RemoteAddr = "216.160.83.56:4321"
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d with no MaxMind DB", rec.Code, http.StatusOK)
+	}
+}
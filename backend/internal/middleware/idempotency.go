@@ -0,0 +1,165 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyTTL is how long a response is replayed for its key
+const IdempotencyTTL = 24 * time.Hour
+
+// IdempotencyStore persists captured responses. services.DeduplicationStore
+// satisfies it, so the in-memory and Redis stores can both back replays.
+type IdempotencyStore interface {
+	Get(ctx context.Context, key string) (string, bool, error)
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+}
+
+// storedResponse is a captured response as kept in the store
+type storedResponse struct {
+	Status int         `json:"status"`
+	Header http.Header `json:"header"`
+	Body   []byte      `json:"body"`
+}
+
+// replayedHeaders are the response headers restored on a replay
+var replayedHeaders = []string{"Content-Type", "Location"}
+
+// IdempotencyMiddleware replays the stored response for a repeated
+// Idempotency-Key instead of running the handler again, so clients can safely
+// retry a mutation after a timeout. Requests without the header run as usual.
+// Keys are scoped to the caller and route, and 5xx responses are not stored
+// so a failed attempt can be retried. Store failures are logged to logger.
+func IdempotencyMiddleware(store IdempotencyStore, ttl time.Duration, logger *slog.Logger) func(http.Handler) http.Handler {
+	var inFlight sync.Map
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			idempotencyKey := r.Header.Get("Idempotency-Key")
+			if idempotencyKey == "" {
+				next.ServeHTTP(w, r)
+				return
+			}
+			if _, err := uuid.Parse(idempotencyKey); err != nil {
+				respondError(w, http.StatusBadRequest, "Idempotency-Key must be a UUID", err)
+				return
+			}
+
+			ctx := r.Context()
+			key := idempotencyStoreKey(r, idempotencyKey)
+
+			if value, found, err := store.Get(ctx, key); err != nil {
+				// Fail open: run the request rather than reject it
+				logger.Warn("idempotency lookup failed", "error", err)
+			} else if found {
+				replay(w, value)
+				return
+			}
+
+			if _, busy := inFlight.LoadOrStore(key, struct{}{}); busy {
+				respondError(w, http.StatusConflict, "A request with this Idempotency-Key is already in progress", nil)
+				return
+			}
+			defer inFlight.Delete(key)
+
+			capture := NewResponseCapture(w)
+			next.ServeHTTP(capture, r)
+
+			if capture.Status() >= 500 {
+				return
+			}
+			stored, err := json.Marshal(capture.stored())
+			if err == nil {
+				err = store.Set(ctx, key, string(stored), ttl)
+			}
+			if err != nil {
+				logger.Warn("failed to store idempotent response", "error", err)
+			}
+		})
+	}
+}
+
+// idempotencyStoreKey scopes a client's key to the caller and the route, so
+// one caller cannot replay another's response
+func idempotencyStoreKey(r *http.Request, idempotencyKey string) string {
+	sum := sha256.Sum256([]byte(rateLimitKey(r) + "\x00" + r.Method + " " + r.URL.Path + "\x00" + idempotencyKey))
+	return "idempotency:" + hex.EncodeToString(sum[:])
+}
+
+// replay writes a stored response, marking it as a replay
+func replay(w http.ResponseWriter, value string) {
+	var stored storedResponse
+	if err := json.Unmarshal([]byte(value), &stored); err != nil {
+		respondError(w, http.StatusInternalServerError, "Stored idempotent response is corrupt", err)
+		return
+	}
+
+	for name, values := range stored.Header {
+		w.Header()[name] = values
+	}
+	w.Header().Set("Idempotency-Replayed", "true")
+	w.WriteHeader(stored.Status)
+	w.Write(stored.Body)
+}
+
+// ResponseCapture passes a response through to the client while keeping a
+// copy of its status and body
+type ResponseCapture struct {
+	http.ResponseWriter
+	status int
+	body   bytes.Buffer
+}
+
+// NewResponseCapture wraps w
+func NewResponseCapture(w http.ResponseWriter) *ResponseCapture {
+	return &ResponseCapture{ResponseWriter: w}
+}
+
+// WriteHeader implements http.ResponseWriter
+func (c *ResponseCapture) WriteHeader(status int) {
+	if c.status == 0 {
+		c.status = status
+	}
+	c.ResponseWriter.WriteHeader(status)
+}
+
+// Write implements http.ResponseWriter
+func (c *ResponseCapture) Write(b []byte) (int, error) {
+	if c.status == 0 {
+		c.status = http.StatusOK
+	}
+	c.body.Write(b)
+	return c.ResponseWriter.Write(b)
+}
+
+// Status returns the status code written, or 200 if none was
+func (c *ResponseCapture) Status() int {
+	if c.status == 0 {
+		return http.StatusOK
+	}
+	return c.status
+}
+
+// Body returns the bytes written so far
+func (c *ResponseCapture) Body() []byte {
+	return c.body.Bytes()
+}
+
+func (c *ResponseCapture) stored() storedResponse {
+	header := make(http.Header)
+	for _, name := range replayedHeaders {
+		if values := c.Header().Values(name); len(values) > 0 {
+			header[name] = values
+		}
+	}
+	return storedResponse{Status: c.Status(), Header: header, Body: c.Body()}
+}
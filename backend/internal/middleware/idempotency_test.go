@@ -0,0 +1,238 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// failingStore is an IdempotencyStore that is always unavailable
+type failingStore struct{}
+
+func (failingStore) Get(context.Context, string) (string, bool, error) {
+	return "", false, errors.New("store unavailable")
+}
+
+func (failingStore) Set(context.Context, string, string, time.Duration) error {
+	return errors.New("store unavailable")
+}
+
+func TestIdempotencyMiddleware_LogsStoreFailures(t *testing.T) {
+	var logs bytes.Buffer
+	handler := IdempotencyMiddleware(failingStore{}, time.Hour, NewLogger(&logs, "info", "json"))(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/applications", nil)
+	req.Header.Set("Idempotency-Key", "5f0c6a2e-8d1b-4c8e-9a57-1f4b2f6d3e10")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if rec.Code != http.StatusCreated {
+		t.Fatalf("status = %d, want the request to run despite the store failing", rec.Code)
+	}
+
+	records := decodeLogRecords(t, &logs)
+	if len(records) != 2 {
+		t.Fatalf("logged %d records, want the failed lookup and the failed store", len(records))
+	}
+	for _, record := range records {
+		if record["level"] != "WARN" || record["error"] != "store unavailable" {
+			t.Fatalf("record = %v, want a warning with the store error", record)
+		}
+	}
+}
+
+// memoryStore is an IdempotencyStore whose entries expire against now
+type memoryStore struct {
+	mu      sync.Mutex
+	now     time.Time
+	entries map[string]memoryStoreEntry
+}
+
+type memoryStoreEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+func newMemoryStore() *memoryStore {
+	return &memoryStore{now: time.Now(), entries: make(map[string]memoryStoreEntry)}
+}
+
+func (s *memoryStore) Get(_ context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.entries[key]
+	if !ok || !s.now.Before(entry.expiresAt) {
+		return "", false, nil
+	}
+	return entry.value, true, nil
+}
+
+func (s *memoryStore) Set(_ context.Context, key, value string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key] = memoryStoreEntry{value: value, expiresAt: s.now.Add(ttl)}
+	return nil
+}
+
+func (s *memoryStore) advance(d time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = s.now.Add(d)
+}
+
+// withIdempotency wraps next in the middleware backed by store
+func withIdempotency(store IdempotencyStore, next http.Handler) http.Handler {
+	return IdempotencyMiddleware(store, IdempotencyTTL, slog.New(slog.NewTextHandler(io.Discard, nil)))(next)
+}
+
+const testIdempotencyKey = "5f0c6a2e-8d1b-4c8e-9a57-1f4b2f6d3e10"
+
+// creatingHandler creates application app-N on its Nth call, answering
+// with status
+func creatingHandler(calls *atomic.Int32, status int) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Location", fmt.Sprintf("/api/v1/applications/app-%d", n))
+		w.Header().Set("X-Request-Count", fmt.Sprint(n))
+		w.WriteHeader(status)
+		fmt.Fprintf(w, `{"id":"app-%d"}`, n)
+	})
+}
+
+// idempotentRequest sends a POST to path with key as its Idempotency-Key,
+// from userID when set
+func idempotentRequest(handler http.Handler, path, key, userID string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, nil)
+	if key != "" {
+		req.Header.Set("Idempotency-Key", key)
+	}
+	if userID != "" {
+		req = req.WithContext(WithUser(req.Context(), map[string]interface{}{"id": userID}))
+	}
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestIdempotencyMiddleware_Replay(t *testing.T) {
+	var calls atomic.Int32
+	handler := withIdempotency(newMemoryStore(), creatingHandler(&calls, http.StatusCreated))
+
+	first := idempotentRequest(handler, "/api/v1/applications", testIdempotencyKey, "user-1")
+	if first.Code != http.StatusCreated || first.Header().Get("Idempotency-Replayed") != "" {
+		t.Fatalf("first response = %d, replayed %q", first.Code, first.Header().Get("Idempotency-Replayed"))
+	}
+
+	second := idempotentRequest(handler, "/api/v1/applications", testIdempotencyKey, "user-1")
+	if calls.Load() != 1 {
+		t.Fatalf("handler ran %d times, want once", calls.Load())
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != `{"id":"app-1"}` {
+		t.Fatalf("replay = %d %s, want 201 {\"id\":\"app-1\"}", second.Code, second.Body)
+	}
+	if got := second.Header().Get("Idempotency-Replayed"); got != "true" {
+		t.Fatalf("Idempotency-Replayed = %q, want true", got)
+	}
+	if second.Header().Get("Content-Type") != "application/json" || second.Header().Get("Location") != "/api/v1/applications/app-1" {
+		t.Fatalf("replayed headers = %v, want Content-Type and Location restored", second.Header())
+	}
+	if got := second.Header().Get("X-Request-Count"); got != "" {
+		t.Fatalf("X-Request-Count = %q, want only the listed headers replayed", got)
+	}
+
+	tests := []struct {
+		name   string
+		path   string
+		key    string
+		userID string
+	}{
+		{name: "another key", path: "/api/v1/applications", key: "0b7e6a3c-2f44-4d0e-8f3a-6c1d9e2b7a55", userID: "user-1"},
+		{name: "another caller", path: "/api/v1/applications", key: testIdempotencyKey, userID: "user-2"},
+		{name: "another route", path: "/api/v1/applications/bulk-status", key: testIdempotencyKey, userID: "user-1"},
+		{name: "no key", path: "/api/v1/applications", userID: "user-1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := calls.Load()
+			rec := idempotentRequest(handler, tt.path, tt.key, tt.userID)
+			if calls.Load() != before+1 || rec.Header().Get("Idempotency-Replayed") != "" {
+				t.Fatalf("response = %s, replayed %q; want the handler to run", rec.Body, rec.Header().Get("Idempotency-Replayed"))
+			}
+		})
+	}
+}
+
+func TestIdempotencyMiddleware_TTLExpiry(t *testing.T) {
+	store := newMemoryStore()
+	var calls atomic.Int32
+	handler := withIdempotency(store, creatingHandler(&calls, http.StatusOK))
+
+	idempotentRequest(handler, "/api/v1/applications/app-1/status", testIdempotencyKey, "user-1")
+
+	store.advance(IdempotencyTTL - time.Minute)
+	if rec := idempotentRequest(handler, "/api/v1/applications/app-1/status", testIdempotencyKey, "user-1"); rec.Header().Get("Idempotency-Replayed") != "true" {
+		t.Fatal("response was not replayed within the TTL")
+	}
+
+	store.advance(2 * time.Minute)
+	rec := idempotentRequest(handler, "/api/v1/applications/app-1/status", testIdempotencyKey, "user-1")
+	if rec.Header().Get("Idempotency-Replayed") != "" || calls.Load() != 2 {
+		t.Fatalf("handler ran %d times, want the request re-executed after the TTL", calls.Load())
+	}
+}
+
+func TestIdempotencyMiddleware_ServerErrorsNotStored(t *testing.T) {
+	var calls atomic.Int32
+	handler := withIdempotency(newMemoryStore(), creatingHandler(&calls, http.StatusBadGateway))
+
+	idempotentRequest(handler, "/api/v1/applications", testIdempotencyKey, "user-1")
+	idempotentRequest(handler, "/api/v1/applications", testIdempotencyKey, "user-1")
+	if calls.Load() != 2 {
+		t.Fatalf("handler ran %d times, want a failed attempt to be retried", calls.Load())
+	}
+}
+
+func TestIdempotencyMiddleware_InvalidKey(t *testing.T) {
+	var calls atomic.Int32
+	handler := withIdempotency(newMemoryStore(), creatingHandler(&calls, http.StatusCreated))
+
+	if rec := idempotentRequest(handler, "/api/v1/applications", "not-a-uuid", "user-1"); rec.Code != http.StatusBadRequest {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusBadRequest)
+	}
+	if calls.Load() != 0 {
+		t.Fatal("handler ran for an invalid key")
+	}
+}
+
+func TestIdempotencyMiddleware_InFlight(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := withIdempotency(newMemoryStore(), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- idempotentRequest(handler, "/api/v1/applications", testIdempotencyKey, "user-1") }()
+	<-started
+
+	if rec := idempotentRequest(handler, "/api/v1/applications", testIdempotencyKey, "user-1"); rec.Code != http.StatusConflict {
+		t.Fatalf("concurrent request status = %d, want %d", rec.Code, http.StatusConflict)
+	}
+	close(release)
+	if rec := <-done; rec.Code != http.StatusCreated {
+		t.Fatalf("first request status = %d, want %d", rec.Code, http.StatusCreated)
+	}
+}
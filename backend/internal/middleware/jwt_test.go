@@ -0,0 +1,237 @@
+package middleware
+
+import (
+	"crypto"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const (
+	testIssuer    = "https://auth.example.com"
+	testJWTSecret = "0123456789abcdef0123456789abcdef"
+)
+
+// testKeys are the keys the validator trusts and others it must not
+type testKeys struct {
+	rsaKey      *rsa.PrivateKey
+	otherRSAKey *rsa.PrivateKey
+	publicPEM   []byte
+}
+
+func newTestKeys(t *testing.T) testKeys {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherRSAKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return testKeys{
+		rsaKey:      rsaKey,
+		otherRSAKey: otherRSAKey,
+		publicPEM:   pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}),
+	}
+}
+
+// signJWT builds a token with the given alg header, signing it with key: a
+// []byte HMAC secret, an *rsa.PrivateKey, or nil for no signature
+func signJWT(t *testing.T, alg string, claims interface{}, key interface{}) string {
+	t.Helper()
+	header, _ := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+
+	var signature []byte
+	switch key := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(signed))
+		signature = mac.Sum(nil)
+	case *rsa.PrivateKey:
+		digest := sha256.Sum256([]byte(signed))
+		if signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return signed + "." + base64.RawURLEncoding.EncodeToString(signature)
+}
+
+func validClaims() Claims {
+	now := time.Now()
+	return Claims{
+		Subject:   "user-1",
+		Email:     "ada@example.com",
+		Issuer:    testIssuer,
+		Roles:     StringList{"recruiter"},
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(time.Hour).Unix(),
+	}
+}
+
+func TestJWTValidator_Validate(t *testing.T) {
+	keys := newTestKeys(t)
+	keyPath := filepath.Join(t.TempDir(), "jwt.pub")
+	if err := os.WriteFile(keyPath, keys.publicPEM, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	validator, err := NewJWTValidator(testJWTSecret, keyPath, testIssuer)
+	if err != nil {
+		t.Fatalf("NewJWTValidator() error = %v", err)
+	}
+	hsOnly, err := NewJWTValidator(testJWTSecret, "", testIssuer)
+	if err != nil {
+		t.Fatalf("NewJWTValidator() error = %v", err)
+	}
+
+	with := func(modify func(c *Claims)) Claims {
+		claims := validClaims()
+		modify(&claims)
+		return claims
+	}
+	expired := with(func(c *Claims) { c.ExpiresAt = time.Now().Add(-time.Hour).Unix() })
+	notYetValid := with(func(c *Claims) { c.NotBefore = time.Now().Add(time.Hour).Unix() })
+	wrongIssuer := with(func(c *Claims) { c.Issuer = "https://evil.example.com" })
+	noSubject := with(func(c *Claims) { c.Subject = "" })
+	noExpiry := with(func(c *Claims) { c.ExpiresAt = 0 })
+	noIssuer := with(func(c *Claims) { c.Issuer = "" })
+
+	type algorithm struct {
+		name      string
+		key       interface{}
+		wrongKey  interface{}
+		validator *JWTValidator
+	}
+	algorithms := []algorithm{
+		{name: "HS256", key: []byte(testJWTSecret), wrongKey: []byte("another-secret-another-secret-xx"), validator: validator},
+		{name: "RS256", key: keys.rsaKey, wrongKey: keys.otherRSAKey, validator: validator},
+	}
+
+	type jwtCase struct {
+		name      string
+		token     string
+		validator *JWTValidator
+		wantErr   error
+	}
+	for _, alg := range algorithms {
+		tests := []jwtCase{
+			{name: "valid", token: signJWT(t, alg.name, validClaims(), alg.key)},
+			{name: "expired", token: signJWT(t, alg.name, expired, alg.key), wantErr: ErrTokenExpired},
+			{name: "not yet valid", token: signJWT(t, alg.name, notYetValid, alg.key), wantErr: ErrTokenNotYetValid},
+			{name: "wrong issuer", token: signJWT(t, alg.name, wrongIssuer, alg.key), wantErr: ErrInvalidIssuer},
+			{name: "missing issuer", token: signJWT(t, alg.name, noIssuer, alg.key), wantErr: ErrInvalidIssuer},
+			{name: "missing subject", token: signJWT(t, alg.name, noSubject, alg.key), wantErr: ErrMissingClaim},
+			{name: "missing expiry", token: signJWT(t, alg.name, noExpiry, alg.key), wantErr: ErrMissingClaim},
+			{name: "signed with another key", token: signJWT(t, alg.name, validClaims(), alg.wrongKey), wantErr: ErrInvalidSignature},
+			{name: "unsigned", token: signJWT(t, alg.name, validClaims(), nil), wantErr: ErrInvalidSignature},
+		}
+		if alg.name == "RS256" {
+			tests = append(tests, jwtCase{name: "no public key configured", token: signJWT(t, "RS256", validClaims(), keys.rsaKey), validator: hsOnly, wantErr: ErrInvalidSignature})
+		}
+
+		for _, tt := range tests {
+			t.Run(alg.name+"/"+tt.name, func(t *testing.T) {
+				v := alg.validator
+				if tt.validator != nil {
+					v = tt.validator
+				}
+				claims, err := v.Validate(tt.token)
+				if tt.wantErr == nil {
+					if err != nil {
+						t.Fatalf("Validate() error = %v, want nil", err)
+					}
+					if claims.Subject != "user-1" || claims.Email != "ada@example.com" || len(claims.Roles) != 1 {
+						t.Fatalf("Validate() claims = %+v", claims)
+					}
+					return
+				}
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Validate() error = %v, want %v", err, tt.wantErr)
+				}
+			})
+		}
+	}
+
+	wrongAlgorithm := []struct {
+		name  string
+		token string
+	}{
+		{name: "none", token: signJWT(t, "none", validClaims(), nil)},
+		{name: "HS512", token: signJWT(t, "HS512", validClaims(), []byte(testJWTSecret))},
+		// The RS256 public key used as an HMAC secret, the classic algorithm
+		// confusion attack
+		{name: "HS256 keyed with the RSA public key", token: signJWT(t, "HS256", validClaims(), keys.publicPEM)},
+		{name: "RS256 header on an HMAC signature", token: signJWT(t, "RS256", validClaims(), []byte(testJWTSecret))},
+		{name: "HS256 header on an RSA signature", token: signJWT(t, "HS256", validClaims(), keys.rsaKey)},
+	}
+	for _, tt := range wrongAlgorithm {
+		t.Run("wrong algorithm/"+tt.name, func(t *testing.T) {
+			if _, err := validator.Validate(tt.token); !errors.Is(err, ErrInvalidSignature) {
+				t.Fatalf("Validate() error = %v, want %v", err, ErrInvalidSignature)
+			}
+		})
+	}
+
+	malformed := map[string]string{
+		"two parts":          "aGVhZGVy.cGF5bG9hZA",
+		"header not base64":  "!!!.cGF5bG9hZA.c2ln",
+		"header not JSON":    base64.RawURLEncoding.EncodeToString([]byte("HS256")) + ".cGF5bG9hZA.c2ln",
+		"empty":              "",
+		"signature not b64":  signJWT(t, "HS256", validClaims(), []byte(testJWTSecret)) + "!",
+		"payload not claims": signJWT(t, "HS256", []string{"user-1"}, []byte(testJWTSecret)),
+	}
+	for name, token := range malformed {
+		t.Run("malformed/"+name, func(t *testing.T) {
+			if _, err := validator.Validate(token); !errors.Is(err, ErrMalformedToken) {
+				t.Fatalf("Validate() error = %v, want %v", err, ErrMalformedToken)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_RejectsInvalidToken(t *testing.T) {
+	validator, err := NewJWTValidator(testJWTSecret, "", testIssuer)
+	if err != nil {
+		t.Fatalf("NewJWTValidator() error = %v", err)
+	}
+	claims := validClaims()
+	claims.ExpiresAt = time.Now().Add(-time.Hour).Unix()
+	token := signJWT(t, "HS256", claims, []byte(testJWTSecret))
+
+	handler := AuthMiddleware(validator, nil)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Fatal("handler ran for an expired token")
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var body errorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("response is not an error response: %v", err)
+	}
+	if rec.Code != http.StatusUnauthorized || body.Status != http.StatusUnauthorized || body.Message == "" {
+		t.Fatalf("response = %d %+v, want a 401 error response", rec.Code, body)
+	}
+}
@@ -0,0 +1,85 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"hr-recruiting/internal/requestid"
+)
+
+const logFieldsContextKey contextKey = "logFields"
+
+// logFields collects values that are only known further down the chain
+type logFields struct {
+	userID string
+}
+
+// NewLogger creates a logger writing to w. format is "json" or "text" and
+// level one of "debug", "info", "warn" or "error".
+func NewLogger(w io.Writer, level, format string) *slog.Logger {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		lvl = slog.LevelInfo
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	if strings.EqualFold(format, "text") {
+		return slog.New(slog.NewTextHandler(w, opts))
+	}
+	return slog.New(slog.NewJSONHandler(w, opts))
+}
+
+// StructuredLogger logs one structured record per request. It should be
+// registered after middleware.RequestID and middleware.RealIP.
+func StructuredLogger(logger *slog.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			fields := &logFields{}
+			ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+
+			ctx := context.WithValue(r.Context(), logFieldsContextKey, fields)
+			next.ServeHTTP(ww, r.WithContext(ctx))
+
+			status := ww.Status()
+			if status == 0 {
+				status = http.StatusOK
+			}
+
+			level := slog.LevelInfo
+			if status >= 500 {
+				level = slog.LevelError
+			}
+
+			remoteIP, _, err := net.SplitHostPort(r.RemoteAddr)
+			if err != nil {
+				remoteIP = r.RemoteAddr
+			}
+
+			logger.LogAttrs(ctx, level, "request",
+				slog.String("request_id", requestid.FromContext(r.Context())),
+				slog.String("method", r.Method),
+				slog.String("path", r.URL.Path),
+				slog.Int("status", status),
+				slog.Float64("latency_ms", float64(time.Since(start).Microseconds())/1000),
+				slog.String("remote_ip", remoteIP),
+				slog.String("user_id", fields.userID),
+				slog.Int("bytes_written", ww.BytesWritten()),
+			)
+		})
+	}
+}
+
+// setLogUserID records the authenticated caller for the request log line
+func setLogUserID(ctx context.Context, userID string) {
+	if fields, ok := ctx.Value(logFieldsContextKey).(*logFields); ok {
+		fields.userID = userID
+	}
+}
@@ -0,0 +1,126 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	chimiddleware "github.com/go-chi/chi/v5/middleware"
+)
+
+// decodeLogRecords parses the JSON records written to logs, one per line
+func decodeLogRecords(t *testing.T, logs *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestStructuredLogger(t *testing.T) {
+	var logs bytes.Buffer
+	logger := NewLogger(&logs, "info", "json")
+
+	handler := chimiddleware.RequestID(chimiddleware.RealIP(StructuredLogger(logger)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		setLogUserID(r.Context(), "user-42")
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte("hello"))
+	}))))
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/jobs?draft=true", nil)
+	req.Header.Set("X-Request-Id", "req-123")
+	req.Header.Set("X-Real-IP", "203.0.113.9")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/fail", nil))
+
+	records := decodeLogRecords(t, &logs)
+	if len(records) != 2 {
+		t.Fatalf("logged %d records, want one per request", len(records))
+	}
+
+	record := records[0]
+	keys := make([]string, 0, len(record))
+	for key := range record {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	want := []string{"bytes_written", "latency_ms", "level", "method", "msg", "path", "remote_ip", "request_id", "status", "time", "user_id"}
+	if !slices.Equal(keys, want) {
+		t.Fatalf("record keys = %v, want %v", keys, want)
+	}
+
+	for key, value := range map[string]interface{}{
+		"level":         "INFO",
+		"msg":           "request",
+		"request_id":    "req-123",
+		"method":        http.MethodPost,
+		"path":          "/api/v1/jobs",
+		"status":        float64(http.StatusCreated),
+		"remote_ip":     "203.0.113.9",
+		"user_id":       "user-42",
+		"bytes_written": float64(len("hello")),
+	} {
+		if record[key] != value {
+			t.Errorf("%s = %#v, want %#v", key, record[key], value)
+		}
+	}
+	if latency, ok := record["latency_ms"].(float64); !ok || latency < 0 {
+		t.Errorf("latency_ms = %#v, want a non-negative number", record["latency_ms"])
+	}
+
+	if failed := records[1]; failed["level"] != "ERROR" || failed["status"] != float64(http.StatusInternalServerError) {
+		t.Errorf("server error logged as %v %v, want ERROR 500", failed["level"], failed["status"])
+	}
+	if records[1]["request_id"] == "" {
+		t.Error("request without an X-Request-Id was logged without a generated one")
+	}
+}
+
+func TestNewLogger(t *testing.T) {
+	t.Run("level filters records", func(t *testing.T) {
+		var logs bytes.Buffer
+		logger := NewLogger(&logs, "warn", "json")
+		logger.Info("dropped")
+		logger.Warn("kept", "error", "boom")
+
+		records := decodeLogRecords(t, &logs)
+		if len(records) != 1 || records[0]["msg"] != "kept" || records[0]["error"] != "boom" {
+			t.Fatalf("records = %v, want only the warning", records)
+		}
+	})
+
+	t.Run("unknown level and format fall back to info JSON", func(t *testing.T) {
+		var logs bytes.Buffer
+		logger := NewLogger(&logs, "verbose", "")
+		logger.Debug("dropped")
+		logger.Info("kept")
+
+		if records := decodeLogRecords(t, &logs); len(records) != 1 || records[0]["level"] != "INFO" {
+			t.Fatalf("records = %v, want one INFO record", records)
+		}
+	})
+
+	t.Run("text format", func(t *testing.T) {
+		var logs bytes.Buffer
+		NewLogger(&logs, "info", "TEXT").Info("started", "port", 8080)
+		if line := logs.String(); !strings.Contains(line, "level=INFO") || !strings.Contains(line, "msg=started port=8080") {
+			t.Fatalf("text record = %q", line)
+		}
+	})
+}
@@ -0,0 +1,124 @@
+package middleware
+
+import (
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// limiterTTL is how long an idle key keeps its bucket before eviction
+const limiterTTL = 10 * time.Minute
+
+// limiterEntry is one caller's limiter and when it was last used
+type limiterEntry struct {
+	limiter  *rate.Limiter
+	lastSeen atomic.Int64 // unix nanoseconds
+}
+
+// RateLimiter limits requests per caller with one rate.Limiter per key
+type RateLimiter struct {
+	rate    float64
+	burst   int
+	buckets sync.Map // key -> *limiterEntry
+}
+
+// NewRateLimiter returns middleware allowing rps requests per second with
+// bursts of up to burst. Authenticated callers are limited by user ID, anyone
+// else by remote IP.
+func NewRateLimiter(rps float64, burst int) func(http.Handler) http.Handler {
+	limiter := &RateLimiter{rate: rps, burst: burst}
+	go limiter.evictIdle(limiterTTL)
+	return limiter.Handler
+}
+
+// Handler enforces the limit for each request
+func (l *RateLimiter) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		allowed, retryAfter := l.allow(rateLimitKey(r), time.Now())
+		if !allowed {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retryAfter.Seconds()))))
+			respondError(w, http.StatusTooManyRequests, "Rate limit exceeded", nil)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allow takes a token for key, or reports how long until one is available
+func (l *RateLimiter) allow(key string, now time.Time) (bool, time.Duration) {
+	value, ok := l.buckets.Load(key)
+	if !ok {
+		value, _ = l.buckets.LoadOrStore(key, &limiterEntry{
+			limiter: rate.NewLimiter(rate.Limit(l.rate), l.burst),
+		})
+	}
+	entry := value.(*limiterEntry)
+	entry.lastSeen.Store(now.UnixNano())
+
+	// A reservation that would have to wait is cancelled, so a refused
+	// request doesn't consume the next token
+	reservation := entry.limiter.ReserveN(now, 1)
+	if !reservation.OK() {
+		return false, time.Minute
+	}
+	delay := reservation.DelayFrom(now)
+	if delay == 0 {
+		return true, 0
+	}
+	reservation.CancelAt(now)
+	if delay == rate.InfDuration {
+		// With no refill the burst, once spent, never comes back
+		return false, time.Minute
+	}
+	return false, delay
+}
+
+// evictIdle drops, every ttl/2, the buckets of keys idle for longer than ttl
+func (l *RateLimiter) evictIdle(ttl time.Duration) {
+	ticker := time.NewTicker(ttl / 2)
+	defer ticker.Stop()
+
+	for now := range ticker.C {
+		l.evict(now, ttl)
+	}
+}
+
+// evict drops the buckets of keys that have not been seen within ttl of now.
+// An evicted key starts again with a full bucket.
+func (l *RateLimiter) evict(now time.Time, ttl time.Duration) {
+	l.buckets.Range(func(key, value interface{}) bool {
+		lastSeen := time.Unix(0, value.(*limiterEntry).lastSeen.Load())
+		if now.Sub(lastSeen) > ttl {
+			l.buckets.Delete(key)
+		}
+		return true
+	})
+}
+
+// rateLimitKey identifies the caller by user ID when authenticated, falling
+// back to the client IP set by middleware.RealIP
+func rateLimitKey(r *http.Request) string {
+	if user, ok := GetUserFromContext(r.Context()); ok {
+		if id, _ := user["id"].(string); id != "" {
+			return "user:" + id
+		}
+	}
+
+	return "ip:" + clientIP(r)
+}
+
+// clientIP returns the host part of the remote address set by
+// middleware.RealIP
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
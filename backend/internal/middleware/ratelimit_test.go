@@ -0,0 +1,151 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestRateLimiter_BurstExhaustion(t *testing.T) {
+	l := &RateLimiter{rate: 2, burst: 3}
+	start := time.Now()
+
+	for i := 0; i < 3; i++ {
+		if allowed, _ := l.allow("ip:1", start); !allowed {
+			t.Fatalf("request %d within the burst was refused", i+1)
+		}
+	}
+
+	allowed, retryAfter := l.allow("ip:1", start)
+	if allowed {
+		t.Fatal("request past the burst was allowed")
+	}
+	if retryAfter != 500*time.Millisecond {
+		t.Fatalf("retryAfter = %v, want 500ms for one token at 2 rps", retryAfter)
+	}
+
+	if allowed, retryAfter := l.allow("ip:1", start.Add(250*time.Millisecond)); allowed || retryAfter != 250*time.Millisecond {
+		t.Fatalf("after 250ms allow() = %v, %v, want refused with 250ms to wait", allowed, retryAfter)
+	}
+	if allowed, _ := l.allow("ip:1", start.Add(500*time.Millisecond)); !allowed {
+		t.Fatal("request after the refill was refused")
+	}
+
+	// Other callers have buckets of their own
+	if allowed, _ := l.allow("ip:2", start); !allowed {
+		t.Fatal("another key was refused")
+	}
+
+	// Refilling stops at the burst size
+	later := start.Add(time.Hour)
+	for i := 0; i < 3; i++ {
+		if allowed, _ := l.allow("ip:1", later); !allowed {
+			t.Fatalf("request %d after a long idle was refused", i+1)
+		}
+	}
+	if allowed, _ := l.allow("ip:1", later); allowed {
+		t.Fatal("bucket refilled past its burst")
+	}
+}
+
+func TestRateLimiter_ZeroRate(t *testing.T) {
+	l := &RateLimiter{rate: 0, burst: 1}
+	now := time.Now()
+	l.allow("ip:1", now)
+
+	allowed, retryAfter := l.allow("ip:1", now.Add(time.Hour))
+	if allowed || retryAfter != time.Minute {
+		t.Fatalf("allow() = %v, %v, want refused with a minute to wait", allowed, retryAfter)
+	}
+}
+
+func TestRateLimiter_IdleEviction(t *testing.T) {
+	l := &RateLimiter{rate: 1, burst: 1}
+	start := time.Now()
+	const ttl = 10 * time.Minute
+
+	l.allow("ip:idle", start)
+	l.allow("ip:active", start)
+	l.allow("ip:active", start.Add(ttl))
+
+	l.evict(start.Add(ttl+time.Second), ttl)
+
+	if _, ok := l.buckets.Load("ip:idle"); ok {
+		t.Fatal("idle key was not evicted")
+	}
+	if _, ok := l.buckets.Load("ip:active"); !ok {
+		t.Fatal("recently seen key was evicted")
+	}
+
+	// An evicted key starts again with a full bucket
+	if allowed, _ := l.allow("ip:idle", start.Add(ttl+time.Second)); !allowed {
+		t.Fatal("evicted key was refused")
+	}
+}
+
+func TestRateLimiter_Handler(t *testing.T) {
+	l := &RateLimiter{rate: 1, burst: 1}
+	var served atomic.Int32
+	handler := l.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		served.Add(1)
+	}))
+
+	send := func(remoteAddr, userID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/applications", nil)
+		req.RemoteAddr = remoteAddr
+		if userID != "" {
+			req = req.WithContext(WithUser(req.Context(), map[string]interface{}{"id": userID}))
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	if rec := send("203.0.113.7:1111", ""); rec.Code != http.StatusOK {
+		t.Fatalf("first request status = %d", rec.Code)
+	}
+	rec := send("203.0.113.7:2222", "")
+	if rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request from the same IP status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if retryAfter := rec.Header().Get("Retry-After"); retryAfter != "1" {
+		t.Fatalf("Retry-After = %q, want %q", retryAfter, "1")
+	}
+
+	// Authenticated callers are limited by user, wherever they connect from
+	if rec := send("203.0.113.7:3333", "user-1"); rec.Code != http.StatusOK {
+		t.Fatalf("first request from user-1 status = %d", rec.Code)
+	}
+	if rec := send("198.51.100.2:4444", "user-1"); rec.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request from user-1 status = %d, want %d", rec.Code, http.StatusTooManyRequests)
+	}
+	if served.Load() != 2 {
+		t.Fatalf("served = %d, want 2", served.Load())
+	}
+}
+
+// BenchmarkRateLimiter_Allow measures allow() when every request shares a key
+// and when requests are spread over many keys
+func BenchmarkRateLimiter_Allow(b *testing.B) {
+	for _, keys := range []int{1, 1000, 100000} {
+		b.Run(fmt.Sprintf("keys=%d", keys), func(b *testing.B) {
+			l := &RateLimiter{rate: 1e9, burst: 1e9}
+			names := make([]string, keys)
+			for i := range names {
+				names[i] = fmt.Sprintf("ip:%d", i)
+			}
+			var n atomic.Int64
+
+			b.ReportAllocs()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					l.allow(names[int(n.Add(1))%keys], time.Now())
+				}
+			})
+		})
+	}
+}
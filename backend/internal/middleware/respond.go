@@ -0,0 +1,31 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// errorResponse mirrors handlers.ErrorResponse so middleware rejections look
+// the same as handler errors
+type errorResponse struct {
+	Error   string `json:"error"`
+	Message string `json:"message,omitempty"`
+	Details string `json:"details,omitempty"`
+	Status  int    `json:"status"`
+}
+
+// respondError writes a JSON error response
+func respondError(w http.ResponseWriter, status int, message string, err error) {
+	response := errorResponse{
+		Error:   http.StatusText(status),
+		Message: message,
+		Status:  status,
+	}
+	if err != nil {
+		response.Details = err.Error()
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
@@ -0,0 +1,180 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ExtendDeadline gives slow endpoints such as exports more time than the
+// server's write timeout allows, ignoring any deadline already on the
+// request. The request is still abandoned if the client disconnects.
+func ExtendDeadline(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(context.WithoutCancel(r.Context()), d)
+			defer cancel()
+
+			stop := context.AfterFunc(r.Context(), func() {
+				if errors.Is(r.Context().Err(), context.Canceled) {
+					cancel()
+				}
+			})
+			defer stop()
+
+			http.NewResponseController(w).SetWriteDeadline(time.Now().Add(d))
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// WithTimeout bounds a route's handler to d. If the handler has not started
+// its response when d elapses, the client gets a 503 and anything the handler
+// writes afterwards is discarded; a response already being streamed is left
+// to finish as the handler sees its context cancelled. The server's write
+// timeout is moved out to match, so routes may run longer than it.
+func WithTimeout(d time.Duration) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx, cancel := context.WithTimeout(r.Context(), d)
+			defer cancel()
+
+			// Leave time to send the 503 after the deadline
+			http.NewResponseController(w).SetWriteDeadline(time.Now().Add(d + timeoutGrace))
+
+			tw := &timeoutWriter{ResponseWriter: w, ctx: ctx, header: w.Header().Clone()}
+			done := make(chan struct{})
+			panicked := make(chan interface{}, 1)
+			go func() {
+				defer func() {
+					if p := recover(); p != nil {
+						panicked <- p
+					}
+				}()
+				next.ServeHTTP(tw, r.WithContext(ctx))
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case p := <-panicked:
+				// Re-raise on the request goroutine so Recoverer sees it
+				panic(p)
+			case <-ctx.Done():
+				tw.mu.Lock()
+				if !tw.wroteHeader && tw.expired() {
+					tw.timeOutLocked()
+				}
+				timedOut := tw.timedOut
+				tw.mu.Unlock()
+				if timedOut {
+					return
+				}
+
+				// The response has started, so the handler still owns the writer
+				select {
+				case <-done:
+				case p := <-panicked:
+					panic(p)
+				}
+			}
+		})
+	}
+}
+
+// timeoutGrace is how long past a route's timeout the 503 may take to send
+const timeoutGrace = 5 * time.Second
+
+// timeoutWriter passes writes through until WithTimeout answers for the
+// handler, after which they fail with http.ErrHandlerTimeout. A handler that
+// only starts its response after the deadline gets the 503 in its place. The
+// handler has its own header map so the 503's headers never race it.
+type timeoutWriter struct {
+	http.ResponseWriter
+	ctx context.Context
+
+	mu          sync.Mutex
+	header      http.Header
+	wroteHeader bool
+	timedOut    bool
+}
+
+func (tw *timeoutWriter) Header() http.Header {
+	return tw.header
+}
+
+func (tw *timeoutWriter) WriteHeader(status int) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if tw.timedOut || tw.wroteHeader {
+		return
+	}
+	if tw.expired() {
+		tw.timeOutLocked()
+		return
+	}
+	tw.writeHeaderLocked(status)
+}
+
+func (tw *timeoutWriter) Write(b []byte) (int, error) {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if !tw.wroteHeader && tw.expired() {
+		tw.timeOutLocked()
+	}
+	if tw.timedOut {
+		return 0, http.ErrHandlerTimeout
+	}
+	if !tw.wroteHeader {
+		tw.writeHeaderLocked(http.StatusOK)
+	}
+	return tw.ResponseWriter.Write(b)
+}
+
+// Flush supports streaming handlers such as server-sent events
+func (tw *timeoutWriter) Flush() {
+	tw.mu.Lock()
+	defer tw.mu.Unlock()
+	if !tw.wroteHeader && tw.expired() {
+		tw.timeOutLocked()
+	}
+	if tw.timedOut {
+		return
+	}
+	if !tw.wroteHeader {
+		tw.writeHeaderLocked(http.StatusOK)
+	}
+	http.NewResponseController(tw.ResponseWriter).Flush()
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (tw *timeoutWriter) Unwrap() http.ResponseWriter {
+	return tw.ResponseWriter
+}
+
+func (tw *timeoutWriter) writeHeaderLocked(status int) {
+	dst := tw.ResponseWriter.Header()
+	for key, values := range tw.header {
+		dst[key] = values
+	}
+	tw.wroteHeader = true
+	tw.ResponseWriter.WriteHeader(status)
+}
+
+func (tw *timeoutWriter) expired() bool {
+	return errors.Is(tw.ctx.Err(), context.DeadlineExceeded)
+}
+
+// timeOutLocked answers the request with a 503 in place of the handler
+func (tw *timeoutWriter) timeOutLocked() {
+	tw.timedOut = true
+	tw.wroteHeader = true
+	w := tw.ResponseWriter
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusServiceUnavailable)
+	json.NewEncoder(w).Encode(errorResponse{Error: "request timeout", Status: http.StatusServiceUnavailable})
+}
@@ -0,0 +1,174 @@
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTimeoutServer serves handler on a real server whose write
+// timeout is writeTimeout, so response deadlines are enforced
+func newTimeoutServer(t *testing.T, writeTimeout time.Duration, handler http.Handler) *httptest.Server {
+	t.Helper()
+	server := httptest.NewUnstartedServer(handler)
+	server.Config.WriteTimeout = writeTimeout
+	server.Start()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestWithTimeout_SlowHandler(t *testing.T) {
+	writeErr := make(chan error, 1)
+	handler := WithTimeout(50 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		time.Sleep(20 * time.Millisecond)
+		w.Header().Set("X-Late", "true")
+		_, err := w.Write([]byte("too late"))
+		writeErr <- err
+	}))
+	server := newTimeoutServer(t, 15*time.Second, handler)
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	var body errorResponse
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		t.Fatalf("decode 503 body: %v", err)
+	}
+	if resp.StatusCode != http.StatusServiceUnavailable || body.Error != "request timeout" {
+		t.Fatalf("response = %d %+v, want a 503 request timeout", resp.StatusCode, body)
+	}
+	if resp.Header.Get("X-Late") != "" {
+		t.Fatal("headers set after the timeout reached the client")
+	}
+	if err := <-writeErr; !errors.Is(err, http.ErrHandlerTimeout) {
+		t.Fatalf("late Write() error = %v, want %v", err, http.ErrHandlerTimeout)
+	}
+}
+
+func TestWithTimeout_FastHandler(t *testing.T) {
+	handler := WithTimeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Deadline(); !ok {
+			t.Error("handler context has no deadline")
+		}
+		w.Header().Set("Location", "/jobs/42")
+		w.WriteHeader(http.StatusCreated)
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jobs", nil))
+	if rec.Code != http.StatusCreated || rec.Header().Get("Location") != "/jobs/42" {
+		t.Fatalf("response = %d %v", rec.Code, rec.Header())
+	}
+}
+
+func TestWithTimeout_StartedResponseFinishes(t *testing.T) {
+	handler := WithTimeout(30 * time.Millisecond)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("started "))
+		<-r.Context().Done()
+		w.Write([]byte("finished"))
+	}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || rec.Body.String() != "started finished" {
+		t.Fatalf("response = %d %q, want the streamed response left to finish", rec.Code, rec.Body)
+	}
+}
+
+func TestWithTimeout_Panic(t *testing.T) {
+	handler := WithTimeout(time.Second)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+
+	defer func() {
+		if p := recover(); p != "boom" {
+			t.Fatalf("recovered %v, want the handler's panic", p)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
+
+// slowResponse answers after delay, past the test server's write timeout
+func slowResponse(delay time.Duration) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(delay)
+		w.Write([]byte("ok"))
+	})
+}
+
+func TestDeadlines_ExtendWriteTimeout(t *testing.T) {
+	const writeTimeout = 50 * time.Millisecond
+
+	tests := []struct {
+		name    string
+		handler http.Handler
+		wantOK  bool
+	}{
+		{name: "server write timeout", handler: slowResponse(4 * writeTimeout), wantOK: false},
+		{name: "WithTimeout", handler: WithTimeout(time.Second)(slowResponse(4 * writeTimeout)), wantOK: true},
+		{name: "ExtendDeadline", handler: ExtendDeadline(time.Second)(slowResponse(4 * writeTimeout)), wantOK: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newTimeoutServer(t, writeTimeout, tt.handler)
+
+			resp, err := http.Get(server.URL)
+			if !tt.wantOK {
+				if err == nil {
+					resp.Body.Close()
+					t.Fatal("response arrived after the write timeout, so the test cannot tell extension apart")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GET error = %v, want the write deadline extended", err)
+			}
+			defer resp.Body.Close()
+			body, _ := io.ReadAll(resp.Body)
+			if resp.StatusCode != http.StatusOK || string(body) != "ok" {
+				t.Fatalf("response = %d %q", resp.StatusCode, body)
+			}
+		})
+	}
+}
+
+func TestExtendDeadline_Context(t *testing.T) {
+	handler := ExtendDeadline(time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deadline, ok := r.Context().Deadline()
+		if !ok || time.Until(deadline) < 50*time.Second {
+			t.Errorf("deadline = %v, want about a minute away", deadline)
+		}
+		if r.Context().Err() != nil {
+			t.Errorf("context error = %v, want the original deadline ignored", r.Context().Err())
+		}
+	}))
+
+	// The request already carries a deadline that has passed
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+
+	// A client that goes away still cancels the request
+	ctx, cancel = context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	handler = ExtendDeadline(time.Minute)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cancel()
+		<-r.Context().Done()
+		done <- r.Context().Err()
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil).WithContext(ctx))
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("context error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracing_NamesSpanByRoute(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	recorder := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+
+	r := chi.NewRouter()
+	r.Use(Tracing(tp))
+	r.Get("/jobs/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/jobs/42", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("recorded %d spans, want 1", len(spans))
+	}
+	span := spans[0]
+	if span.Name() != "GET /jobs/{id}" || span.SpanKind() != trace.SpanKindServer {
+		t.Fatalf("span = %q (%v), want a server span named after the route", span.Name(), span.SpanKind())
+	}
+	if span.SpanContext().TraceID().String() != "4bf92f3577b34da6a3ce929d0e0e4736" || span.Parent().SpanID().String() != "00f067aa0ba902b7" {
+		t.Fatal("span does not continue the remote trace")
+	}
+
+	attributes := make(map[string]string)
+	for _, attr := range span.Attributes() {
+		attributes[string(attr.Key)] = attr.Value.Emit()
+	}
+	if attributes["http.route"] != "/jobs/{id}" || attributes["http.response.status_code"] != "502" {
+		t.Fatalf("span attributes = %v", attributes)
+	}
+	if span.Status().Code != codes.Error {
+		t.Fatalf("span status = %v, want an error for a 5xx", span.Status())
+	}
+}
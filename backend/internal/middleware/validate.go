@@ -0,0 +1,162 @@
+package middleware
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v5"
+)
+
+//go:embed schemas/*.json
+var schemaFS embed.FS
+
+// maxValidatedBodySize caps the request bodies ValidateBody buffers
+const maxValidatedBodySize = 1 << 20
+
+// fieldError describes why one field failed validation
+type fieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// validationErrorResponse is errorResponse with structured details
+type validationErrorResponse struct {
+	Error   string       `json:"error"`
+	Message string       `json:"message"`
+	Details []fieldError `json:"details"`
+	Status  int          `json:"status"`
+}
+
+// ValidateBody rejects requests whose JSON body does not match the named
+// schema from the embedded schemas directory, answering 422 with one error
+// per failing field. The body is restored before next runs, so handlers
+// decode it as usual. An unknown schema name panics at startup.
+func ValidateBody(schemaName string) func(http.Handler) http.Handler {
+	schema, err := loadSchema(schemaName)
+	if err != nil {
+		panic(err)
+	}
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxValidatedBodySize))
+			r.Body.Close()
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				respondError(w, http.StatusRequestEntityTooLarge, "Request body is too large", nil)
+				return
+			}
+			if err != nil {
+				respondError(w, http.StatusBadRequest, "Failed to read request body", err)
+				return
+			}
+
+			// Numbers are decoded exactly, so integer bounds hold for
+			// values past float64 precision
+			decoder := json.NewDecoder(bytes.NewReader(body))
+			decoder.UseNumber()
+			var document interface{}
+			if err := decoder.Decode(&document); err != nil || decoder.More() {
+				respondError(w, http.StatusBadRequest, "Invalid request body", err)
+				return
+			}
+
+			if err := schema.Validate(document); err != nil {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusUnprocessableEntity)
+				json.NewEncoder(w).Encode(validationErrorResponse{
+					Error:   http.StatusText(http.StatusUnprocessableEntity),
+					Message: "Request body failed validation",
+					Details: fieldErrors(err),
+					Status:  http.StatusUnprocessableEntity,
+				})
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// schemaURL is where loadSchema finds a named schema. Schemas may $ref each
+// other relative to it, but nothing outside the embedded directory is loaded.
+func schemaURL(name string) string {
+	return "embed:///schemas/" + name + ".json"
+}
+
+func loadSchema(name string) (*jsonschema.Schema, error) {
+	compiler := jsonschema.NewCompiler()
+	compiler.Draft = jsonschema.Draft2020
+	compiler.AssertFormat = true
+	compiler.LoadURL = func(s string) (io.ReadCloser, error) {
+		parsed, err := url.Parse(s)
+		if err != nil || parsed.Scheme != "embed" {
+			return nil, fmt.Errorf("schema %s is not embedded", s)
+		}
+		return schemaFS.Open(strings.TrimPrefix(parsed.Path, "/"))
+	}
+
+	schema, err := compiler.Compile(schemaURL(name))
+	if err != nil {
+		return nil, fmt.Errorf("invalid request schema %q: %w", name, err)
+	}
+	return schema, nil
+}
+
+// fieldErrors flattens a validation error into one error per failing
+// keyword, naming fields by their path from the document root
+func fieldErrors(err error) []fieldError {
+	var validationErr *jsonschema.ValidationError
+	if !errors.As(err, &validationErr) {
+		return []fieldError{{Field: "(body)", Message: err.Error()}}
+	}
+
+	var errs []fieldError
+	var walk func(e *jsonschema.ValidationError)
+	walk = func(e *jsonschema.ValidationError) {
+		if len(e.Causes) == 0 {
+			errs = append(errs, fieldError{Field: fieldName(e.InstanceLocation), Message: e.Message})
+			return
+		}
+		for _, cause := range e.Causes {
+			walk(cause)
+		}
+	}
+	walk(validationErr)
+
+	// Sorted so the same body always produces errors in the same order
+	sort.SliceStable(errs, func(i, j int) bool { return errs[i].Field < errs[j].Field })
+	return errs
+}
+
+// fieldName turns a JSON pointer such as /salaryRange/min or /skills/1 into
+// salaryRange.min or skills[1]
+func fieldName(pointer string) string {
+	if pointer == "" {
+		return "(body)"
+	}
+
+	var name strings.Builder
+	for _, token := range strings.Split(strings.TrimPrefix(pointer, "/"), "/") {
+		token = strings.NewReplacer("~1", "/", "~0", "~").Replace(token)
+		if _, err := strconv.Atoi(token); err == nil {
+			name.WriteString("[" + token + "]")
+			continue
+		}
+		if name.Len() > 0 {
+			name.WriteByte('.')
+		}
+		name.WriteString(token)
+	}
+	return name.String()
+}
@@ -0,0 +1,195 @@
+package middleware
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// validated runs body through ValidateBody(schemaName) and returns the status
+func validated(t *testing.T, schemaName, body string) int {
+	t.Helper()
+	handler := ValidateBody(schemaName)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	return rec.Code
+}
+
+func TestValidateBody_UpdateStatus(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "default stage", body: `{"status":"INTERVIEW"}`, wantStatus: http.StatusNoContent},
+		// Stages are checked against the configured pipeline by the handler
+		{name: "custom stage", body: `{"status":"PHONE_SCREEN","note":"Booked"}`, wantStatus: http.StatusNoContent},
+		{name: "missing status", body: `{"note":"Booked"}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "empty status", body: `{"status":""}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "status of wrong type", body: `{"status":3}`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "extra field", body: `{"status":"HIRED","salary":1}`, wantStatus: http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := validated(t, "update_status", tt.body); status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+const validApplication = `{
+	"jobId": "job-1",
+	"firstName": "Ada",
+	"lastName": "Lovelace",
+	"email": "ada@example.com",
+	"phone": "+44 20 7946 0000",
+	"resumeUrl": "https://files.example.com/ada.pdf",
+	"currentLocation": "London",
+	"availability": "Immediately",
+	"yearsOfExperience": 12,
+	"skills": ["Go", "SQL"],
+	"customAnswer": "allowed by additionalProperties"
+}`
+
+// withField returns validApplication with field set to value, or removed when
+// value is empty
+func withField(t *testing.T, field, value string) string {
+	t.Helper()
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(validApplication), &body); err != nil {
+		t.Fatal(err)
+	}
+	if value == "" {
+		delete(body, field)
+	} else {
+		body[field] = json.RawMessage(value)
+	}
+	data, _ := json.Marshal(body)
+	return string(data)
+}
+
+func TestValidateBody_SubmitApplication(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+	}{
+		{name: "valid", body: validApplication, wantStatus: http.StatusNoContent},
+		{name: "required field missing", body: withField(t, "email", ""), wantStatus: http.StatusUnprocessableEntity},
+		{name: "wrong type", body: withField(t, "firstName", `42`), wantStatus: http.StatusUnprocessableEntity},
+		{name: "fractional integer", body: withField(t, "yearsOfExperience", `2.5`), wantStatus: http.StatusUnprocessableEntity},
+		{name: "below minimum", body: withField(t, "yearsOfExperience", `-1`), wantStatus: http.StatusUnprocessableEntity},
+		{name: "invalid email", body: withField(t, "email", `"Ada <ada@example.com>"`), wantStatus: http.StatusUnprocessableEntity},
+		{name: "relative URL", body: withField(t, "resumeUrl", `"/files/ada.pdf"`), wantStatus: http.StatusUnprocessableEntity},
+		{name: "invalid array item", body: withField(t, "skills", `["Go", ""]`), wantStatus: http.StatusUnprocessableEntity},
+		{name: "too long", body: withField(t, "firstName", `"`+strings.Repeat("a", 101)+`"`), wantStatus: http.StatusUnprocessableEntity},
+		{name: "not an object", body: `["job-1"]`, wantStatus: http.StatusUnprocessableEntity},
+		{name: "not JSON", body: `{"jobId":`, wantStatus: http.StatusBadRequest},
+		{name: "too large", body: withField(t, "coverLetter", `"`+strings.Repeat("a", maxValidatedBodySize)+`"`), wantStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if status := validated(t, "submit_application", tt.body); status != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", status, tt.wantStatus)
+			}
+		})
+	}
+}
+
+func TestValidateBody_CreateJobFieldErrors(t *testing.T) {
+	body := `{
+		"title": "Engineer",
+		"department": "",
+		"location": "Remote",
+		"employmentType": "FULL_TIME",
+		"experienceLevel": "SENIOR",
+		"requirements": ["Go"],
+		"skills": [],
+		"salaryRange": {"min": "lots", "max": 90000, "currency": "GBP", "bonus": true},
+		"closingDate": "next week",
+		"headcount": 2
+	}`
+
+	var passedBody []byte
+	handler := ValidateBody("create_job")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		passedBody, _ = io.ReadAll(r.Body)
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/jobs", strings.NewReader(body)))
+
+	if rec.Code != http.StatusUnprocessableEntity || passedBody != nil {
+		t.Fatalf("status = %d, want %d without calling the handler", rec.Code, http.StatusUnprocessableEntity)
+	}
+	var response validationErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("response is not a validation error: %v", err)
+	}
+	want := []fieldError{
+		{Field: "(body)", Message: "missing properties: 'description'"},
+		{Field: "(body)", Message: "additionalProperties 'headcount' not allowed"},
+		{Field: "closingDate", Message: "'next week' is not valid 'date-time'"},
+		{Field: "department", Message: "length must be >= 1, but got 0"},
+		{Field: "salaryRange", Message: "additionalProperties 'bonus' not allowed"},
+		{Field: "salaryRange.min", Message: "expected number, but got string"},
+		{Field: "skills", Message: "minimum 1 items required, but found 0 items"},
+	}
+	if !reflect.DeepEqual(response.Details, want) {
+		t.Fatalf("details = %+v, want %+v", response.Details, want)
+	}
+}
+
+func TestValidateBody_RestoresBody(t *testing.T) {
+	var passedBody []byte
+	handler := ValidateBody("update_status")(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		passedBody, _ = io.ReadAll(r.Body)
+	}))
+	body := `{"status":"OFFER","note":"Verbal offer accepted"}`
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/", strings.NewReader(body)))
+
+	if string(passedBody) != body {
+		t.Fatalf("handler read %q, want %q", passedBody, body)
+	}
+}
+
+func TestFieldName(t *testing.T) {
+	tests := map[string]string{
+		"":                 "(body)",
+		"/email":           "email",
+		"/salaryRange/min": "salaryRange.min",
+		"/skills/1":        "skills[1]",
+		"/answers/0/text":  "answers[0].text",
+		"/a~1b/c~0d":       "a/b.c~d",
+	}
+	for pointer, want := range tests {
+		if got := fieldName(pointer); got != want {
+			t.Errorf("fieldName(%q) = %q, want %q", pointer, got, want)
+		}
+	}
+}
+
+func TestLoadSchema_EmbeddedSchemas(t *testing.T) {
+	entries, err := schemaFS.ReadDir("schemas")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		name := strings.TrimSuffix(entry.Name(), ".json")
+		if _, err := loadSchema(name); err != nil {
+			t.Errorf("loadSchema(%q) error = %v", name, err)
+		}
+	}
+	if _, err := loadSchema("missing"); err == nil {
+		t.Fatal("loadSchema(missing) error = nil, want an error")
+	}
+}
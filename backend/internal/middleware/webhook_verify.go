@@ -0,0 +1,62 @@
+package middleware
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// HubHRMSSignatureHeader carries the hex HMAC-SHA256 of a Hub-HRMS callback
+// body, optionally prefixed with "sha256="
+const HubHRMSSignatureHeader = "X-HubHRMS-Signature"
+
+// maxWebhookBodySize caps the callback bodies HubHRMSWebhookVerifier buffers
+const maxWebhookBodySize = 1 << 20
+
+// HubHRMSWebhookVerifier rejects callbacks whose signature header does not
+// match an HMAC-SHA256 of the raw body under secret. The body is restored
+// before next runs. Without a secret every callback is refused, since an
+// empty key would let anyone sign.
+func HubHRMSWebhookVerifier(secret string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if secret == "" {
+				respondError(w, http.StatusServiceUnavailable, "Hub-HRMS webhooks are not configured", nil)
+				return
+			}
+
+			body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBodySize))
+			r.Body.Close()
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				respondError(w, http.StatusRequestEntityTooLarge, "Request body is too large", nil)
+				return
+			}
+			if err != nil {
+				respondError(w, http.StatusBadRequest, "Failed to read request body", err)
+				return
+			}
+
+			signature, err := hex.DecodeString(strings.TrimPrefix(r.Header.Get(HubHRMSSignatureHeader), "sha256="))
+			if err != nil || len(signature) == 0 {
+				respondError(w, http.StatusUnauthorized, "Missing or malformed webhook signature", nil)
+				return
+			}
+
+			mac := hmac.New(sha256.New, []byte(secret))
+			mac.Write(body)
+			if !hmac.Equal(signature, mac.Sum(nil)) {
+				respondError(w, http.StatusUnauthorized, "Invalid webhook signature", nil)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			next.ServeHTTP(w, r)
+		})
+	}
+}
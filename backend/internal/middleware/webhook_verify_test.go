@@ -0,0 +1,77 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// hubHRMSSignature signs body as Hub-HRMS does
+func hubHRMSSignature(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestHubHRMSWebhookVerifier(t *testing.T) {
+	const secret = "webhook-secret"
+	body := `{"event":"application.status_changed","applicationId":"app-1"}`
+
+	var received string
+	verified := func(secret string) http.Handler {
+		return HubHRMSWebhookVerifier(secret)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			received = string(data)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+	}
+
+	tests := []struct {
+		name       string
+		secret     string
+		body       string
+		signature  string
+		wantStatus int
+	}{
+		{name: "valid signature", secret: secret, body: body, signature: hubHRMSSignature(secret, body), wantStatus: http.StatusNoContent},
+		{name: "valid signature with prefix", secret: secret, body: body, signature: "sha256=" + hubHRMSSignature(secret, body), wantStatus: http.StatusNoContent},
+		{name: "bad signature", secret: secret, body: body, signature: hubHRMSSignature("other-secret", body), wantStatus: http.StatusUnauthorized},
+		{name: "signature of another body", secret: secret, body: body, signature: hubHRMSSignature(secret, body+" "), wantStatus: http.StatusUnauthorized},
+		{name: "malformed signature", secret: secret, body: body, signature: "not-hex", wantStatus: http.StatusUnauthorized},
+		{name: "missing header", secret: secret, body: body, wantStatus: http.StatusUnauthorized},
+		{
+			name:       "oversized body",
+			secret:     secret,
+			body:       strings.Repeat("x", maxWebhookBodySize+1),
+			signature:  hubHRMSSignature(secret, strings.Repeat("x", maxWebhookBodySize+1)),
+			wantStatus: http.StatusRequestEntityTooLarge,
+		},
+		{name: "no secret configured", body: body, signature: hubHRMSSignature("", body), wantStatus: http.StatusServiceUnavailable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received = ""
+			req := httptest.NewRequest(http.MethodPost, "/webhooks/hubhrms", strings.NewReader(tt.body))
+			if tt.signature != "" {
+				req.Header.Set(HubHRMSSignatureHeader, tt.signature)
+			}
+			rec := httptest.NewRecorder()
+			verified(tt.secret).ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if tt.wantStatus == http.StatusNoContent && received != tt.body {
+				t.Fatalf("handler read %q, want the verified body", received)
+			}
+			if tt.wantStatus != http.StatusNoContent && received != "" {
+				t.Fatal("handler ran for a rejected callback")
+			}
+		})
+	}
+}
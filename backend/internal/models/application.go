@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// AIScore is the automated assessment of an application
+type AIScore struct {
+	Overall        float64    `json:"overall"`
+	Insights       []string   `json:"insights,omitempty"`
+	Strengths      []string   `json:"strengths,omitempty"`
+	Concerns       []string   `json:"concerns,omitempty"`
+	Recommendation string     `json:"recommendation"`
+	GeneratedAt    *time.Time `json:"generatedAt,omitempty"`
+}
+
+// Note is a recruiter's note on an application
+type Note struct {
+	ID         string    `json:"id"`
+	Author     *UserRef  `json:"author,omitempty"`
+	Content    string    `json:"content"`
+	Tags       []string  `json:"tags"`
+	IsInternal bool      `json:"isInternal"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// Application is a candidate's application to a job
+type Application struct {
+	ID                string        `json:"id"`
+	Job               *JobRef       `json:"job,omitempty"`
+	Candidate         *CandidateRef `json:"candidate,omitempty"`
+	Status            string        `json:"status"`
+	AppliedDate       time.Time     `json:"appliedDate"`
+	LastUpdated       time.Time     `json:"lastUpdated"`
+	ResumeURL         string        `json:"resumeUrl,omitempty"`
+	CoverLetter       string        `json:"coverLetter,omitempty"`
+	LinkedinURL       string        `json:"linkedinUrl,omitempty"`
+	PortfolioURL      string        `json:"portfolioUrl,omitempty"`
+	YearsOfExperience *float64      `json:"yearsOfExperience,omitempty"`
+	CurrentLocation   string        `json:"currentLocation,omitempty"`
+	WillingToRelocate *bool         `json:"willingToRelocate,omitempty"`
+	ExpectedSalary    *float64      `json:"expectedSalary,omitempty"`
+	Availability      string        `json:"availability,omitempty"`
+	AIScore           *AIScore      `json:"aiScore,omitempty"`
+	Notes             []Note        `json:"notes,omitempty"`
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+// CandidateRef is the summary of a candidate embedded in other resources
+type CandidateRef struct {
+	ID        string `json:"id"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Email     string `json:"email"`
+	Phone     string `json:"phone,omitempty"`
+	Location  string `json:"location,omitempty"`
+}
+
+// Experience is a position on a candidate's work history
+type Experience struct {
+	Company      string   `json:"company"`
+	Title        string   `json:"title"`
+	StartDate    string   `json:"startDate"`
+	EndDate      string   `json:"endDate,omitempty"`
+	Current      bool     `json:"current"`
+	Description  string   `json:"description,omitempty"`
+	Achievements []string `json:"achievements,omitempty"`
+}
+
+// Education is a qualification on a candidate's profile
+type Education struct {
+	Institution string `json:"institution"`
+	Degree      string `json:"degree"`
+	Field       string `json:"field,omitempty"`
+	StartDate   string `json:"startDate,omitempty"`
+	EndDate     string `json:"endDate,omitempty"`
+}
+
+// Candidate is a candidate's profile
+type Candidate struct {
+	ID                 string        `json:"id"`
+	FirstName          string        `json:"firstName"`
+	LastName           string        `json:"lastName"`
+	Email              string        `json:"email"`
+	Phone              string        `json:"phone,omitempty"`
+	Location           string        `json:"location,omitempty"`
+	Headline           string        `json:"headline,omitempty"`
+	Summary            string        `json:"summary,omitempty"`
+	ResumeURL          string        `json:"resumeUrl,omitempty"`
+	LinkedinURL        string        `json:"linkedinUrl,omitempty"`
+	PortfolioURL       string        `json:"portfolioUrl,omitempty"`
+	GithubURL          string        `json:"githubUrl,omitempty"`
+	Skills             []string      `json:"skills"`
+	Experience         []Experience  `json:"experience"`
+	Education          []Education   `json:"education"`
+	Applications       []Application `json:"applications,omitempty"`
+	Availability       string        `json:"availability,omitempty"`
+	ExpectedSalary     *float64      `json:"expectedSalary,omitempty"`
+	PreferredLocations []string      `json:"preferredLocations,omitempty"`
+	RemotePreference   string        `json:"remotePreference,omitempty"`
+	CreatedAt          time.Time     `json:"createdAt"`
+	UpdatedAt          time.Time     `json:"updatedAt"`
+}
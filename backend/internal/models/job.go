@@ -0,0 +1,65 @@
+// Package models holds the typed API resources served by the v2 routes. Field
+// names and JSON tags follow the Hub-HRMS schema, so Hub-HRMS responses
+// decode into them directly.
+package models
+
+import "time"
+
+// SalaryRange is the pay offered for a job
+type SalaryRange struct {
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	Currency string  `json:"currency"`
+}
+
+// UserRef identifies a recruiter or other user
+type UserRef struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email,omitempty"`
+}
+
+// Job is a job posting
+type Job struct {
+	ID               string       `json:"id"`
+	Slug             string       `json:"slug,omitempty"`
+	Title            string       `json:"title"`
+	Department       string       `json:"department"`
+	Location         string       `json:"location"`
+	EmploymentType   string       `json:"employmentType"`
+	ExperienceLevel  string       `json:"experienceLevel"`
+	SalaryRange      *SalaryRange `json:"salaryRange,omitempty"`
+	Description      string       `json:"description"`
+	Requirements     []string     `json:"requirements"`
+	Responsibilities []string     `json:"responsibilities"`
+	Benefits         []string     `json:"benefits"`
+	Skills           []string     `json:"skills"`
+	Status           string       `json:"status"`
+	PostedDate       *time.Time   `json:"postedDate,omitempty"`
+	ClosingDate      *time.Time   `json:"closingDate,omitempty"`
+	ApplicationCount int          `json:"applicationCount"`
+	ViewCount        int          `json:"viewCount"`
+	RemoteWork       bool         `json:"remoteWork"`
+	UrgentHiring     bool         `json:"urgentHiring"`
+	CreatedBy        *UserRef     `json:"createdBy,omitempty"`
+	CreatedAt        time.Time    `json:"createdAt"`
+	UpdatedAt        time.Time    `json:"updatedAt"`
+}
+
+// JobRef is the summary of a job embedded in other resources
+type JobRef struct {
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Department string `json:"department,omitempty"`
+	Location   string `json:"location,omitempty"`
+}
+
+// Page is one page of a list. Total counts the matching items across all
+// pages, when the endpoint counts them.
+type Page[T any] struct {
+	Items   []T  `json:"items"`
+	Total   int  `json:"total,omitempty"`
+	Limit   int  `json:"limit"`
+	Offset  int  `json:"offset"`
+	HasMore bool `json:"hasMore"`
+}
@@ -0,0 +1,26 @@
+// Package requestid carries the ID of the request that caused some work
+// through its context, so that clients and services can log and forward it
+// without depending on the HTTP middleware.
+package requestid
+
+import (
+	"context"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+// Header carries the request ID between services, so their logs can be
+// matched up
+const Header = "X-Request-ID"
+
+// NewContext returns a copy of ctx carrying id as its request ID, where chi's
+// RequestID middleware would have put it. Work started outside a request can
+// use it to keep the ID of the request that caused it.
+func NewContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, middleware.RequestIDKey, id)
+}
+
+// FromContext returns the request ID in ctx, or "" when there is none
+func FromContext(ctx context.Context) string {
+	return middleware.GetReqID(ctx)
+}
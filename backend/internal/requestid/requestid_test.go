@@ -0,0 +1,31 @@
+package requestid
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+)
+
+func TestFromContext(t *testing.T) {
+	if got := FromContext(context.Background()); got != "" {
+		t.Fatalf("FromContext(empty) = %q, want \"\"", got)
+	}
+	if got := FromContext(NewContext(context.Background(), "job-sweep-1")); got != "job-sweep-1" {
+		t.Fatalf("FromContext() = %q, want %q", got, "job-sweep-1")
+	}
+
+	// IDs assigned by chi's RequestID middleware are found too
+	var fromRequest string
+	handler := middleware.RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fromRequest = FromContext(r.Context())
+	}))
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(Header, "edge-42")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+	if fromRequest != "edge-42" {
+		t.Fatalf("FromContext(request) = %q, want %q", fromRequest, "edge-42")
+	}
+}
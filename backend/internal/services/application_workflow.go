@@ -0,0 +1,144 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrUnknownStatus is returned for a status outside the workflow
+	ErrUnknownStatus = errors.New("unknown application status")
+	// ErrInvalidTransition is returned when the workflow forbids a move
+	ErrInvalidTransition = errors.New("invalid status transition")
+	// ErrInvalidPipeline is returned for stage configurations that do not
+	// form a usable pipeline
+	ErrInvalidPipeline = errors.New("invalid pipeline")
+)
+
+// PipelineStage is one stage of the hiring pipeline. ID is the application
+// status stored in Hub-HRMS; Name and Color are for display.
+type PipelineStage struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Color      string `json:"color,omitempty"`
+	Order      int    `json:"order"`
+	FinalStage bool   `json:"finalStage"`
+}
+
+// DefaultPipelineStages is the pipeline used until an admin configures one
+var DefaultPipelineStages = []PipelineStage{
+	{ID: "APPLIED", Name: "Applied", Color: "#6b7280", Order: 1},
+	{ID: "SCREENING", Name: "Screening", Color: "#3b82f6", Order: 2},
+	{ID: "INTERVIEW", Name: "Interview", Color: "#8b5cf6", Order: 3},
+	{ID: "OFFER", Name: "Offer", Color: "#f59e0b", Order: 4},
+	{ID: "HIRED", Name: "Hired", Color: "#10b981", Order: 5, FinalStage: true},
+	{ID: "REJECTED", Name: "Rejected", Color: "#ef4444", Order: 6, FinalStage: true},
+	{ID: "WITHDRAWN", Name: "Withdrawn", Color: "#9ca3af", Order: 7, FinalStage: true},
+}
+
+// StageTransitions lists, for each stage, the stages it may move to next.
+// An open stage advances to the stage after it in order. A final stage that
+// directly follows an open stage is that stage's outcome, as HIRED follows
+// OFFER, and is reached only from it; any other final stage, such as
+// REJECTED, closes an application from any open stage. Final stages have no
+// transitions.
+func StageTransitions(stages []PipelineStage) map[string][]string {
+	ordered := append([]PipelineStage(nil), stages...)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].Order < ordered[j].Order })
+
+	outcome := make(map[string]bool)
+	for i := 1; i < len(ordered); i++ {
+		if ordered[i].FinalStage && !ordered[i-1].FinalStage {
+			outcome[ordered[i].ID] = true
+		}
+	}
+
+	transitions := make(map[string][]string, len(ordered))
+	for i, stage := range ordered {
+		next := []string{}
+		if !stage.FinalStage {
+			for j, other := range ordered {
+				if j == i+1 || (j != i && other.FinalStage && !outcome[other.ID]) {
+					next = append(next, other.ID)
+				}
+			}
+		}
+		transitions[stage.ID] = next
+	}
+	return transitions
+}
+
+// ValidateStages checks that stages form a usable pipeline: every stage has
+// a unique ID and order, and there is at least one open and one final stage
+func ValidateStages(stages []PipelineStage) error {
+	ids := make(map[string]bool, len(stages))
+	orders := make(map[int]bool, len(stages))
+	open, final := false, false
+	for _, stage := range stages {
+		switch {
+		case stage.ID == "":
+			return fmt.Errorf("%w: every stage needs an id", ErrInvalidPipeline)
+		case stage.ID != strings.ToUpper(stage.ID) || strings.ContainsAny(stage.ID, " \t"):
+			return fmt.Errorf("%w: stage id %q must be upper case without spaces", ErrInvalidPipeline, stage.ID)
+		case strings.TrimSpace(stage.Name) == "":
+			return fmt.Errorf("%w: stage %s needs a name", ErrInvalidPipeline, stage.ID)
+		case ids[stage.ID]:
+			return fmt.Errorf("%w: stage id %s is used twice", ErrInvalidPipeline, stage.ID)
+		case orders[stage.Order]:
+			return fmt.Errorf("%w: more than one stage has order %d", ErrInvalidPipeline, stage.Order)
+		}
+		ids[stage.ID], orders[stage.Order] = true, true
+		if stage.FinalStage {
+			final = true
+		} else {
+			open = true
+		}
+	}
+	if !open || !final {
+		return fmt.Errorf("%w: at least one open and one final stage are required", ErrInvalidPipeline)
+	}
+	return nil
+}
+
+// ValidateStage reports whether status is one of the stages of the pipeline
+func ValidateStage(stages []PipelineStage, status string) error {
+	status = strings.ToUpper(status)
+	for _, stage := range stages {
+		if stage.ID == status {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %q", ErrUnknownStatus, status)
+}
+
+// ValidateTransition reports whether an application may move from one status
+// to another in the pipeline made of stages. Staying in the same status is
+// always allowed.
+func ValidateTransition(stages []PipelineStage, from, to string) error {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	transitions := StageTransitions(stages)
+
+	allowed, ok := transitions[from]
+	if !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownStatus, from)
+	}
+	if _, ok := transitions[to]; !ok {
+		return fmt.Errorf("%w: %q", ErrUnknownStatus, to)
+	}
+	if from == to {
+		return nil
+	}
+
+	for _, next := range allowed {
+		if next == to {
+			return nil
+		}
+	}
+
+	if len(allowed) == 0 {
+		return fmt.Errorf("%w: %s is a final status", ErrInvalidTransition, from)
+	}
+	return fmt.Errorf("%w: %s can only move to %s", ErrInvalidTransition, from, strings.Join(allowed, ", "))
+}
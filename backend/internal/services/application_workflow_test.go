@@ -0,0 +1,80 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateTransition_DefaultPipeline(t *testing.T) {
+	closing := []string{"REJECTED", "WITHDRAWN"}
+	allowed := map[string][]string{
+		"APPLIED":   append([]string{"SCREENING"}, closing...),
+		"SCREENING": append([]string{"INTERVIEW"}, closing...),
+		"INTERVIEW": append([]string{"OFFER"}, closing...),
+		"OFFER":     append([]string{"HIRED"}, closing...),
+		"HIRED":     {},
+		"REJECTED":  {},
+		"WITHDRAWN": {},
+	}
+
+	// Every pair of default stages, so no transition is left untested
+	for _, from := range DefaultPipelineStages {
+		for _, to := range DefaultPipelineStages {
+			valid := from.ID == to.ID || slices.Contains(allowed[from.ID], to.ID)
+			t.Run(from.ID+"->"+to.ID, func(t *testing.T) {
+				err := ValidateTransition(DefaultPipelineStages, from.ID, to.ID)
+				if valid && err != nil {
+					t.Fatalf("ValidateTransition() error = %v, want nil", err)
+				}
+				if !valid && !errors.Is(err, ErrInvalidTransition) {
+					t.Fatalf("ValidateTransition() error = %v, want %v", err, ErrInvalidTransition)
+				}
+			})
+		}
+	}
+
+	tests := []struct {
+		from, to string
+		wantErr  error
+		wantMsg  string
+	}{
+		{from: "applied", to: "Screening"},
+		{from: "APPLIED", to: "OFFER", wantErr: ErrInvalidTransition, wantMsg: "APPLIED can only move to SCREENING, REJECTED, WITHDRAWN"},
+		{from: "HIRED", to: "REJECTED", wantErr: ErrInvalidTransition, wantMsg: "HIRED is a final status"},
+		{from: "NEW", to: "SCREENING", wantErr: ErrUnknownStatus},
+		{from: "APPLIED", to: "ARCHIVED", wantErr: ErrUnknownStatus},
+		{from: "", to: "SCREENING", wantErr: ErrUnknownStatus},
+	}
+	for _, tt := range tests {
+		err := ValidateTransition(DefaultPipelineStages, tt.from, tt.to)
+		if tt.wantErr == nil && err != nil {
+			t.Errorf("ValidateTransition(%q, %q) error = %v, want nil", tt.from, tt.to, err)
+		}
+		if tt.wantErr != nil && (!errors.Is(err, tt.wantErr) || !strings.Contains(err.Error(), tt.wantMsg)) {
+			t.Errorf("ValidateTransition(%q, %q) error = %v, want %v %q", tt.from, tt.to, err, tt.wantErr, tt.wantMsg)
+		}
+	}
+}
+
+func TestStageTransitions_OrdersByStage(t *testing.T) {
+	shuffled := []PipelineStage{
+		{ID: "REJECTED", Name: "Rejected", Order: 4, FinalStage: true},
+		{ID: "OFFER", Name: "Offer", Order: 2},
+		{ID: "APPLIED", Name: "Applied", Order: 1},
+		{ID: "HIRED", Name: "Hired", Order: 3, FinalStage: true},
+	}
+	got := StageTransitions(shuffled)
+	want := map[string][]string{
+		"APPLIED":  {"OFFER", "REJECTED"},
+		"OFFER":    {"HIRED", "REJECTED"},
+		"HIRED":    {},
+		"REJECTED": {},
+	}
+	for id, next := range want {
+		if !slices.Equal(got[id], next) {
+			t.Errorf("transitions from %s = %v, want %v", id, got[id], next)
+		}
+	}
+}
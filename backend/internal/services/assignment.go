@@ -0,0 +1,223 @@
+package services
+
+import (
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// maxAutoAssign caps the unassigned applications one auto-assign run takes
+const maxAutoAssign = 200
+
+var (
+	// ErrApplicationNotFound is returned when assigning an application that
+	// does not exist
+	ErrApplicationNotFound = errors.New("application not found")
+	// ErrNoRecruiters is returned by AutoAssign when there is no recruiter to
+	// hand applications to
+	ErrNoRecruiters = errors.New("no recruiters available")
+)
+
+// Recruiter is a user applications can be assigned to
+type Recruiter struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+// RecruiterWorkload is a recruiter and the open applications assigned to them
+type RecruiterWorkload struct {
+	Recruiter
+	OpenApplications int `json:"openApplications"`
+}
+
+// AssignedApplication is an application and the recruiter now handling it
+type AssignedApplication struct {
+	ApplicationID string    `json:"applicationId"`
+	Recruiter     Recruiter `json:"recruiter"`
+	CandidateName string    `json:"candidateName,omitempty"`
+	JobTitle      string    `json:"jobTitle,omitempty"`
+}
+
+// Assignment pairs an application with the recruiter it should go to
+type Assignment struct {
+	ApplicationID string
+	RecruiterID   string
+}
+
+// AssignmentService assigns applications to recruiters in Hub-HRMS
+type AssignmentService struct {
+	client *gateway.HubHRMSClient
+}
+
+// NewAssignmentService creates a new assignment service
+func NewAssignmentService(client *gateway.HubHRMSClient) *AssignmentService {
+	return &AssignmentService{client: client}
+}
+
+// Workload returns every recruiter with their open application count,
+// busiest first
+func (s *AssignmentService) Workload(ctx context.Context) ([]RecruiterWorkload, error) {
+	resp, err := s.client.Query(ctx, gateway.GetRecruiterWorkloadQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch recruiter workload: %w", err)
+	}
+
+	workload := []RecruiterWorkload{}
+	if _, err := decodeField(resp.Data, "recruiters", &workload); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(workload, func(i, j int) bool {
+		return workload[i].OpenApplications > workload[j].OpenApplications
+	})
+	return workload, nil
+}
+
+// Assign hands an application to a recruiter
+func (s *AssignmentService) Assign(ctx context.Context, applicationID, recruiterID string) (*AssignedApplication, error) {
+	resp, err := s.client.Mutate(ctx, gateway.AssignApplicationMutation, map[string]interface{}{
+		"applicationId": applicationID,
+		"recruiterId":   recruiterID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign application: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("failed to assign application: %s", resp.Errors[0].Message)
+	}
+
+	var application struct {
+		ID        string    `json:"id"`
+		Recruiter Recruiter `json:"assignedRecruiter"`
+		Candidate struct {
+			FirstName string `json:"firstName"`
+			LastName  string `json:"lastName"`
+		} `json:"candidate"`
+		Job struct {
+			Title string `json:"title"`
+		} `json:"job"`
+	}
+	found, err := decodeField(resp.Data, "assignApplication", &application)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrApplicationNotFound
+	}
+
+	return &AssignedApplication{
+		ApplicationID: application.ID,
+		Recruiter:     application.Recruiter,
+		CandidateName: strings.TrimSpace(application.Candidate.FirstName + " " + application.Candidate.LastName),
+		JobTitle:      application.Job.Title,
+	}, nil
+}
+
+// AutoAssign spreads the oldest unassigned applications over the recruiters
+// with BalanceAssignments. An application that fails to assign is logged and
+// left for the next run.
+func (s *AssignmentService) AutoAssign(ctx context.Context) ([]AssignedApplication, error) {
+	workload, err := s.Workload(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(workload) == 0 {
+		return nil, ErrNoRecruiters
+	}
+
+	resp, err := s.client.Query(ctx, gateway.GetUnassignedApplicationsQuery, map[string]interface{}{
+		"limit": maxAutoAssign,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch unassigned applications: %w", err)
+	}
+	var unassigned []struct {
+		ID          string `json:"id"`
+		AppliedDate string `json:"appliedDate"`
+	}
+	if _, err := decodeField(resp.Data, "applications", &unassigned); err != nil {
+		return nil, err
+	}
+	sort.SliceStable(unassigned, func(i, j int) bool {
+		return unassigned[i].AppliedDate < unassigned[j].AppliedDate
+	})
+
+	applicationIDs := make([]string, len(unassigned))
+	for i, application := range unassigned {
+		applicationIDs[i] = application.ID
+	}
+
+	assigned := []AssignedApplication{}
+	for _, assignment := range BalanceAssignments(workload, applicationIDs) {
+		application, err := s.Assign(ctx, assignment.ApplicationID, assignment.RecruiterID)
+		if err != nil {
+			slog.Warn("failed to auto-assign application",
+				"application_id", assignment.ApplicationID, "recruiter_id", assignment.RecruiterID, "error", err)
+			continue
+		}
+		assigned = append(assigned, *application)
+	}
+	return assigned, nil
+}
+
+// BalanceAssignments gives each application, in order, to the recruiter with
+// the fewest open applications. Recruiters on equal workloads take turns, the
+// one assigned to least recently going first.
+func BalanceAssignments(recruiters []RecruiterWorkload, applicationIDs []string) []Assignment {
+	if len(recruiters) == 0 {
+		return nil
+	}
+
+	loads := make(workloadHeap, len(recruiters))
+	for i, recruiter := range recruiters {
+		loads[i] = &recruiterLoad{id: recruiter.ID, open: recruiter.OpenApplications, turn: i}
+	}
+	heap.Init(&loads)
+
+	assignments := make([]Assignment, 0, len(applicationIDs))
+	for i, applicationID := range applicationIDs {
+		next := loads[0]
+		assignments = append(assignments, Assignment{ApplicationID: applicationID, RecruiterID: next.id})
+		next.open++
+		next.turn = len(recruiters) + i
+		heap.Fix(&loads, 0)
+	}
+	return assignments
+}
+
+// recruiterLoad is a recruiter's place in a workloadHeap. turn orders
+// recruiters with equal workloads.
+type recruiterLoad struct {
+	id   string
+	open int
+	turn int
+}
+
+// workloadHeap is a min-heap of recruiters by open applications
+type workloadHeap []*recruiterLoad
+
+func (h workloadHeap) Len() int { return len(h) }
+
+func (h workloadHeap) Less(i, j int) bool {
+	if h[i].open != h[j].open {
+		return h[i].open < h[j].open
+	}
+	return h[i].turn < h[j].turn
+}
+
+func (h workloadHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *workloadHeap) Push(x interface{}) { *h = append(*h, x.(*recruiterLoad)) }
+
+func (h *workloadHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
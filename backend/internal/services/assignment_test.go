@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+
+	"hr-recruiting/internal/gateway"
+)
+
+func TestBalanceAssignments(t *testing.T) {
+	workload := func(open ...int) []RecruiterWorkload {
+		recruiters := make([]RecruiterWorkload, len(open))
+		for i, n := range open {
+			recruiters[i] = RecruiterWorkload{Recruiter: Recruiter{ID: string(rune('a' + i))}, OpenApplications: n}
+		}
+		return recruiters
+	}
+	applications := func(n int) []string {
+		ids := make([]string, n)
+		for i := range ids {
+			ids[i] = "app-" + string(rune('1'+i))
+		}
+		return ids
+	}
+
+	tests := []struct {
+		name       string
+		recruiters []RecruiterWorkload
+		apps       int
+		want       string
+	}{
+		{name: "equal workloads take turns", recruiters: workload(0, 0, 0), apps: 6, want: "abcabc"},
+		{name: "lightest first", recruiters: workload(5, 2, 0), apps: 3, want: "ccb"},
+		{name: "catches up before sharing", recruiters: workload(3, 0), apps: 5, want: "bbbab"},
+		{name: "ties broken by least recently assigned", recruiters: workload(1, 0, 1), apps: 4, want: "bacb"},
+		{name: "one recruiter", recruiters: workload(9), apps: 3, want: "aaa"},
+		{name: "no applications", recruiters: workload(0, 0), apps: 0, want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ids := applications(tt.apps)
+			assignments := BalanceAssignments(tt.recruiters, ids)
+			got := ""
+			for i, assignment := range assignments {
+				if assignment.ApplicationID != ids[i] {
+					t.Fatalf("assignment %d is for %s, want %s: applications keep their order", i, assignment.ApplicationID, ids[i])
+				}
+				got += assignment.RecruiterID
+			}
+			if got != tt.want {
+				t.Fatalf("recruiters in order = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if assignments := BalanceAssignments(nil, applications(2)); assignments != nil {
+		t.Fatalf("BalanceAssignments(no recruiters) = %v, want none", assignments)
+	}
+}
+
+// assignmentFake is a Hub-HRMS with recruiters and unassigned applications.
+// app-missing does not exist.
+type assignmentFake struct {
+	mu         sync.Mutex
+	recruiters []interface{}
+	unassigned []interface{}
+	assigned   []Assignment
+}
+
+func (f *assignmentFake) respond(req gateway.GraphQLRequest) interface{} {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	switch req.Query {
+	case gateway.GetRecruiterWorkloadQuery:
+		return map[string]interface{}{"recruiters": f.recruiters}
+	case gateway.GetUnassignedApplicationsQuery:
+		return map[string]interface{}{"applications": f.unassigned}
+	case gateway.AssignApplicationMutation:
+		applicationID, _ := req.Variables["applicationId"].(string)
+		recruiterID, _ := req.Variables["recruiterId"].(string)
+		if applicationID == "app-missing" {
+			return map[string]interface{}{"assignApplication": nil}
+		}
+		f.assigned = append(f.assigned, Assignment{ApplicationID: applicationID, RecruiterID: recruiterID})
+		return map[string]interface{}{"assignApplication": map[string]interface{}{
+			"id":                applicationID,
+			"assignedRecruiter": map[string]interface{}{"id": recruiterID, "name": "Recruiter " + recruiterID},
+			"candidate":         map[string]interface{}{"firstName": "Ada", "lastName": "Lovelace"},
+			"job":               map[string]interface{}{"title": "Backend Engineer"},
+		}}
+	}
+	return map[string]interface{}{}
+}
+
+func TestAssignmentService_AutoAssign(t *testing.T) {
+	fake := &assignmentFake{
+		recruiters: []interface{}{
+			map[string]interface{}{"id": "grace", "name": "Grace", "openApplications": 4},
+			map[string]interface{}{"id": "alan", "name": "Alan", "openApplications": 1},
+			map[string]interface{}{"id": "edsger", "name": "Edsger", "openApplications": 1},
+		},
+		// Out of order; the oldest are assigned first
+		unassigned: []interface{}{
+			map[string]interface{}{"id": "app-3", "appliedDate": "2026-10-03T09:00:00Z"},
+			map[string]interface{}{"id": "app-1", "appliedDate": "2026-10-01T09:00:00Z"},
+			map[string]interface{}{"id": "app-missing", "appliedDate": "2026-10-01T12:00:00Z"},
+			map[string]interface{}{"id": "app-2", "appliedDate": "2026-10-02T09:00:00Z"},
+		},
+	}
+	service := NewAssignmentService(newFakeHubHRMS(t, fake.respond))
+
+	workload, err := service.Workload(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if workload[0].ID != "grace" || workload[0].OpenApplications != 4 || workload[0].Name != "Grace" {
+		t.Fatalf("workload = %+v, want the busiest recruiter first", workload)
+	}
+
+	assigned, err := service.AutoAssign(context.Background())
+	if err != nil {
+		t.Fatalf("AutoAssign() error = %v", err)
+	}
+	// alan and edsger share the lightest load and take turns; app-missing
+	// used edsger's turn but is skipped
+	want := []Assignment{
+		{ApplicationID: "app-1", RecruiterID: "alan"},
+		{ApplicationID: "app-2", RecruiterID: "alan"},
+		{ApplicationID: "app-3", RecruiterID: "edsger"},
+	}
+	if !reflect.DeepEqual(fake.assigned, want) {
+		t.Fatalf("assigned %v, want %v", fake.assigned, want)
+	}
+	if len(assigned) != 3 || assigned[0].Recruiter.Name != "Recruiter alan" || assigned[0].CandidateName != "Ada Lovelace" || assigned[0].JobTitle != "Backend Engineer" {
+		t.Fatalf("AutoAssign() = %+v, want the three assigned applications", assigned)
+	}
+
+	t.Run("no recruiters", func(t *testing.T) {
+		service := NewAssignmentService(newFakeHubHRMS(t, (&assignmentFake{unassigned: fake.unassigned}).respond))
+		if _, err := service.AutoAssign(context.Background()); !errors.Is(err, ErrNoRecruiters) {
+			t.Fatalf("AutoAssign() error = %v, want ErrNoRecruiters", err)
+		}
+	})
+}
+
+func TestAssignmentService_Assign(t *testing.T) {
+	service := NewAssignmentService(newFakeHubHRMS(t, (&assignmentFake{}).respond))
+
+	application, err := service.Assign(context.Background(), "app-1", "grace")
+	if err != nil || application.ApplicationID != "app-1" || application.Recruiter.ID != "grace" {
+		t.Fatalf("Assign() = %+v, %v, want app-1 with grace", application, err)
+	}
+	if _, err := service.Assign(context.Background(), "app-missing", "grace"); !errors.Is(err, ErrApplicationNotFound) {
+		t.Fatalf("Assign(missing) error = %v, want ErrApplicationNotFound", err)
+	}
+}
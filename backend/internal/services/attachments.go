@@ -0,0 +1,174 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/google/uuid"
+)
+
+// Attachment limits. Each file may be as large as a resume, but a submission
+// as a whole is capped lower than five of them.
+const (
+	MaxApplicationAttachments = 5
+	maxAttachmentSize         = maxResumeSize
+	maxAttachmentsTotalSize   = 25 << 20
+	// attachmentMemory is how much of a submission is held in memory; the
+	// rest is spooled to temporary files while parsing
+	attachmentMemory = 8 << 20
+)
+
+// attachmentTypes are the supporting documents accepted with an application:
+// the resume formats plus images for portfolios
+var attachmentTypes = map[string]string{
+	".pdf":  pdfContentType,
+	".doc":  docContentType,
+	".docx": docxContentType,
+	".png":  "image/png",
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+}
+
+// Attachment describes one uploaded supporting document
+type Attachment struct {
+	URL         string `json:"url"`
+	Filename    string `json:"filename"`
+	ContentType string `json:"contentType"`
+	Size        int64  `json:"size"`
+}
+
+// UploadApplicationAttachment handles uploads of supporting documents, such
+// as portfolios and writing samples, sent as the "files" fields of a
+// multipart form. Every file is checked before any is stored, so a rejected
+// submission leaves nothing behind in S3.
+func (s *UploadService) UploadApplicationAttachment(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	// Leave room for the multipart framing around the files
+	r.Body = http.MaxBytesReader(w, r.Body, maxAttachmentsTotalSize+(1<<20))
+	if err := r.ParseMultipartForm(attachmentMemory); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			http.Error(w, "Attachments too large. Maximum total size is 25MB", http.StatusBadRequest)
+			return
+		}
+		http.Error(w, "Failed to parse form", http.StatusBadRequest)
+		return
+	}
+	defer r.MultipartForm.RemoveAll()
+
+	files := r.MultipartForm.File["files"]
+	if len(files) == 0 {
+		http.Error(w, "No files in form", http.StatusBadRequest)
+		return
+	}
+	if len(files) > MaxApplicationAttachments {
+		http.Error(w, fmt.Sprintf("Too many files. At most %d attachments are allowed", MaxApplicationAttachments), http.StatusBadRequest)
+		return
+	}
+
+	var total int64
+	contentTypes := make([]string, len(files))
+	for i, header := range files {
+		if header.Size > maxAttachmentSize {
+			http.Error(w, fmt.Sprintf("File %s too large. Maximum size is 10MB", header.Filename), http.StatusBadRequest)
+			return
+		}
+		total += header.Size
+		if total > maxAttachmentsTotalSize {
+			http.Error(w, "Attachments too large. Maximum total size is 25MB", http.StatusBadRequest)
+			return
+		}
+
+		contentType, err := checkAttachment(header)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("File %s is not an allowed type. Only PDF, DOC, DOCX, PNG, and JPEG are allowed", header.Filename), http.StatusUnsupportedMediaType)
+			return
+		}
+		contentTypes[i] = contentType
+	}
+
+	attachments := make([]Attachment, 0, len(files))
+	for i, header := range files {
+		file, err := header.Open()
+		if err != nil {
+			http.Error(w, "Failed to read file from form", http.StatusBadRequest)
+			return
+		}
+
+		key := fmt.Sprintf("attachments/%s/%s%s",
+			time.Now().Format("2006/01"),
+			uuid.New().String(),
+			strings.ToLower(filepath.Ext(header.Filename)),
+		)
+		_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+			Bucket:      aws.String(s.bucket),
+			Key:         aws.String(key),
+			Body:        file,
+			ContentType: aws.String(contentTypes[i]),
+			Metadata: map[string]string{
+				"original-filename": header.Filename,
+				"uploaded-at":       time.Now().Format(time.RFC3339),
+			},
+		})
+		file.Close()
+		if err != nil {
+			http.Error(w, fmt.Sprintf("Failed to upload file: %v", err), http.StatusInternalServerError)
+			return
+		}
+
+		attachments = append(attachments, Attachment{
+			URL:         s.GetFileURL(key),
+			Filename:    header.Filename,
+			ContentType: contentTypes[i],
+			Size:        header.Size,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(attachments)
+}
+
+// checkAttachment returns the content type of an uploaded file, provided its
+// extension is allowed and its content matches the extension
+func checkAttachment(header *multipart.FileHeader) (string, error) {
+	contentType, allowed := attachmentTypes[strings.ToLower(filepath.Ext(header.Filename))]
+	if !allowed {
+		return "", errUnsupportedContentType
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(file, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	head = head[:n]
+
+	detected := http.DetectContentType(head)
+	if !strings.HasPrefix(detected, "image/") {
+		if detected, err = detectContentType(bytes.NewReader(head)); err != nil {
+			return "", err
+		}
+	}
+	if detected != contentType {
+		return "", errUnsupportedContentType
+	}
+	return contentType, nil
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// attachmentFile is one part of an attachments form
+type attachmentFile struct {
+	name    string
+	content []byte
+}
+
+// uploadAttachments posts files as the "files" fields of a multipart form
+func uploadAttachments(t *testing.T, s *UploadService, files ...attachmentFile) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	for _, file := range files {
+		part, err := form.CreateFormFile("files", file.name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		part.Write(file.content)
+	}
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/upload/attachments", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := httptest.NewRecorder()
+	s.UploadApplicationAttachment(rec, req)
+	return rec
+}
+
+func TestUploadApplicationAttachment(t *testing.T) {
+	s, puts := newFakeS3Service(t)
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 24)...)
+	pdf := paddedPDF(1024)
+
+	rec := uploadAttachments(t, s,
+		attachmentFile{name: "portfolio.PNG", content: png},
+		attachmentFile{name: "writing-sample.pdf", content: pdf},
+	)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200: %s", rec.Code, rec.Body)
+	}
+
+	var attachments []Attachment
+	if err := json.NewDecoder(rec.Body).Decode(&attachments); err != nil {
+		t.Fatal(err)
+	}
+	want := []Attachment{
+		{Filename: "portfolio.PNG", ContentType: "image/png", Size: int64(len(png))},
+		{Filename: "writing-sample.pdf", ContentType: pdfContentType, Size: int64(len(pdf))},
+	}
+	if len(attachments) != len(want) {
+		t.Fatalf("got %d attachments, want %d: %+v", len(attachments), len(want), attachments)
+	}
+
+	keys := puts()
+	if len(keys) != len(want) {
+		t.Fatalf("stored %d objects, want %d: %v", len(keys), len(want), keys)
+	}
+	for i, attachment := range attachments {
+		if attachment.Filename != want[i].Filename || attachment.ContentType != want[i].ContentType || attachment.Size != want[i].Size {
+			t.Errorf("attachment %d = %+v, want %+v", i, attachment, want[i])
+		}
+		if !strings.HasPrefix(keys[i], "attachments/") {
+			t.Errorf("key %q is not under attachments/", keys[i])
+		}
+		if attachment.URL != s.GetFileURL(keys[i]) {
+			t.Errorf("attachment %d URL = %q, want the URL of %q", i, attachment.URL, keys[i])
+		}
+	}
+	if !strings.HasSuffix(keys[0], ".png") || !strings.HasSuffix(keys[1], ".pdf") {
+		t.Errorf("keys = %v, want lower-case extensions", keys)
+	}
+}
+
+func TestUploadApplicationAttachment_Rejected(t *testing.T) {
+	pdf := attachmentFile{name: "sample.pdf", content: paddedPDF(1024)}
+	nineMB := paddedPDF(9 << 20)
+
+	tests := []struct {
+		name       string
+		files      []attachmentFile
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "no files",
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "No files in form",
+		},
+		{
+			name:       "too many files",
+			files:      []attachmentFile{pdf, pdf, pdf, pdf, pdf, pdf},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Too many files",
+		},
+		{
+			name:       "file over 10MB",
+			files:      []attachmentFile{pdf, {name: "large.pdf", content: paddedPDF(maxAttachmentSize + 1)}},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "File large.pdf too large",
+		},
+		{
+			name: "total over 25MB",
+			files: []attachmentFile{
+				{name: "a.pdf", content: nineMB},
+				{name: "b.pdf", content: nineMB},
+				{name: "c.pdf", content: nineMB},
+			},
+			wantStatus: http.StatusBadRequest,
+			wantBody:   "Attachments too large",
+		},
+		{
+			name:       "disallowed extension",
+			files:      []attachmentFile{pdf, {name: "notes.txt", content: []byte("plain text")}},
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantBody:   "notes.txt is not an allowed type",
+		},
+		{
+			name:       "content not matching the extension",
+			files:      []attachmentFile{pdf, {name: "photo.png", content: paddedPDF(1024)}},
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantBody:   "photo.png is not an allowed type",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, puts := newFakeS3Service(t)
+			rec := uploadAttachments(t, s, tt.files...)
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, tt.wantStatus, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), tt.wantBody) {
+				t.Fatalf("body = %q, want %q", rec.Body, tt.wantBody)
+			}
+			if keys := puts(); len(keys) != 0 {
+				t.Fatalf("rejected submission stored %v", keys)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"hr-recruiting/internal/gateway"
+)
+
+var (
+	// ErrInvalidRejectionRule is returned for rules with an unknown operator
+	// or a value the operator cannot compare against
+	ErrInvalidRejectionRule = errors.New("invalid auto-rejection rule")
+	// ErrJobNotFound is returned for rules of a job that does not exist
+	ErrJobNotFound = errors.New("job not found")
+)
+
+// maxRejectionRules caps the rules one job may have
+const maxRejectionRules = 20
+
+// Operators an AutoRejectionRule may use
+const (
+	RuleEquals      = "eq"
+	RuleNotEquals   = "neq"
+	RuleLessThan    = "lt"
+	RuleLessOrEqual = "lte"
+	RuleGreaterThan = "gt"
+	RuleGreaterOrEq = "gte"
+	RuleIn          = "in"
+	RuleNotIn       = "not_in"
+	RuleContains    = "contains"
+	RuleNotContains = "not_contains"
+)
+
+// AutoRejectionRule rejects an application whose Field compares to Value by
+// Operator, for example {"yearsOfExperience", "lt", 3}. Field is a key of
+// the submitted application and may be a dotted path into nested objects.
+type AutoRejectionRule struct {
+	Field    string      `json:"field"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value"`
+}
+
+// String describes the rule for logs and notes
+func (r AutoRejectionRule) String() string {
+	return fmt.Sprintf("%s %s %v", r.Field, r.Operator, r.Value)
+}
+
+// RuleEngine reads and writes the auto-rejection rules kept on each job in
+// Hub-HRMS and evaluates applications against them
+type RuleEngine struct {
+	client *gateway.HubHRMSClient
+}
+
+// NewRuleEngine creates a rule engine backed by Hub-HRMS
+func NewRuleEngine(client *gateway.HubHRMSClient) *RuleEngine {
+	return &RuleEngine{client: client}
+}
+
+// Rules returns the auto-rejection rules of a job
+func (e *RuleEngine) Rules(ctx context.Context, jobID string) ([]AutoRejectionRule, error) {
+	resp, err := e.client.Query(ctx, gateway.GetJobAutoRejectionRulesQuery, map[string]interface{}{
+		"id": jobID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch auto-rejection rules: %w", err)
+	}
+
+	var job struct {
+		Rules []AutoRejectionRule `json:"autoRejectionRules"`
+	}
+	found, err := decodeField(resp.Data, "job", &job)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrJobNotFound
+	}
+	if job.Rules == nil {
+		job.Rules = []AutoRejectionRule{}
+	}
+	return job.Rules, nil
+}
+
+// UpdateRules replaces a job's auto-rejection rules
+func (e *RuleEngine) UpdateRules(ctx context.Context, jobID string, rules []AutoRejectionRule) ([]AutoRejectionRule, error) {
+	if err := ValidateRejectionRules(rules); err != nil {
+		return nil, err
+	}
+
+	resp, err := e.client.Mutate(ctx, gateway.UpdateJobAutoRejectionRulesMutation, map[string]interface{}{
+		"jobId": jobID,
+		"rules": rules,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update auto-rejection rules: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("failed to update auto-rejection rules: %s", resp.Errors[0].Message)
+	}
+
+	saved := []AutoRejectionRule{}
+	if _, err := decodeField(resp.Data, "updateJobAutoRejectionRules", &saved); err != nil {
+		return nil, err
+	}
+	return saved, nil
+}
+
+// ValidateRejectionRules checks every rule names a field, uses a known
+// operator and has a value that operator can compare
+func ValidateRejectionRules(rules []AutoRejectionRule) error {
+	if len(rules) > maxRejectionRules {
+		return fmt.Errorf("%w: at most %d rules are allowed", ErrInvalidRejectionRule, maxRejectionRules)
+	}
+	for i, rule := range rules {
+		if strings.TrimSpace(rule.Field) == "" {
+			return fmt.Errorf("%w: rule %d has no field", ErrInvalidRejectionRule, i+1)
+		}
+		switch rule.Operator {
+		case RuleEquals, RuleNotEquals, RuleContains, RuleNotContains:
+			if rule.Value == nil {
+				return fmt.Errorf("%w: rule %d has no value", ErrInvalidRejectionRule, i+1)
+			}
+		case RuleLessThan, RuleLessOrEqual, RuleGreaterThan, RuleGreaterOrEq:
+			if _, ok := ruleNumber(rule.Value); !ok {
+				return fmt.Errorf("%w: rule %d compares against a non-number", ErrInvalidRejectionRule, i+1)
+			}
+		case RuleIn, RuleNotIn:
+			if _, ok := rule.Value.([]interface{}); !ok {
+				return fmt.Errorf("%w: rule %d needs a list of values", ErrInvalidRejectionRule, i+1)
+			}
+		default:
+			return fmt.Errorf("%w: rule %d has unknown operator %q", ErrInvalidRejectionRule, i+1, rule.Operator)
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the first rule application matches. A rule on a field the
+// application leaves out never matches, so missing data does not reject.
+func (e *RuleEngine) Evaluate(rules []AutoRejectionRule, application map[string]interface{}) (AutoRejectionRule, bool) {
+	for _, rule := range rules {
+		value, ok := ruleField(application, rule.Field)
+		if ok && rule.matches(value) {
+			return rule, true
+		}
+	}
+	return AutoRejectionRule{}, false
+}
+
+// matches compares an application's value for the rule's field
+func (r AutoRejectionRule) matches(value interface{}) bool {
+	switch r.Operator {
+	case RuleEquals:
+		return ruleEqual(value, r.Value)
+	case RuleNotEquals:
+		return !ruleEqual(value, r.Value)
+	case RuleLessThan, RuleLessOrEqual, RuleGreaterThan, RuleGreaterOrEq:
+		got, ok := ruleNumber(value)
+		want, wantOK := ruleNumber(r.Value)
+		if !ok || !wantOK {
+			return false
+		}
+		switch r.Operator {
+		case RuleLessThan:
+			return got < want
+		case RuleLessOrEqual:
+			return got <= want
+		case RuleGreaterThan:
+			return got > want
+		default:
+			return got >= want
+		}
+	case RuleIn, RuleNotIn:
+		options, _ := r.Value.([]interface{})
+		in := false
+		for _, option := range options {
+			if ruleEqual(value, option) {
+				in = true
+				break
+			}
+		}
+		return in == (r.Operator == RuleIn)
+	case RuleContains, RuleNotContains:
+		return ruleContains(value, r.Value) == (r.Operator == RuleContains)
+	}
+	return false
+}
+
+// ruleField looks up a dotted path in an application
+func ruleField(application map[string]interface{}, path string) (interface{}, bool) {
+	var current interface{} = application
+	for _, key := range strings.Split(path, ".") {
+		fields, ok := current.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+		if current, ok = fields[key]; !ok || current == nil {
+			return nil, false
+		}
+	}
+	return current, true
+}
+
+// ruleEqual compares numbers by value, strings ignoring case, and booleans.
+// Lists and objects are never equal.
+func ruleEqual(a, b interface{}) bool {
+	if x, ok := ruleNumber(a); ok {
+		y, ok := ruleNumber(b)
+		return ok && x == y
+	}
+	if x, ok := a.(string); ok {
+		y, ok := b.(string)
+		return ok && strings.EqualFold(strings.TrimSpace(x), strings.TrimSpace(y))
+	}
+	if x, ok := a.(bool); ok {
+		y, ok := b.(bool)
+		return ok && x == y
+	}
+	return false
+}
+
+// ruleContains reports whether a list holds want, or a string has want as a
+// substring ignoring case
+func ruleContains(value, want interface{}) bool {
+	switch v := value.(type) {
+	case []interface{}:
+		for _, item := range v {
+			if ruleEqual(item, want) {
+				return true
+			}
+		}
+	case string:
+		if s, ok := want.(string); ok {
+			return strings.Contains(strings.ToLower(v), strings.ToLower(s))
+		}
+	}
+	return false
+}
+
+// ruleNumber reads a JSON number, or a string holding one, as candidates
+// often submit numeric form fields as text
+func ruleNumber(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case string:
+		if n, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,174 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// decodeApplication decodes a submission the way the handler receives it, so
+// numbers are float64 and lists are []interface{}
+func decodeApplication(t *testing.T, data string) map[string]interface{} {
+	t.Helper()
+	var application map[string]interface{}
+	if err := json.Unmarshal([]byte(data), &application); err != nil {
+		t.Fatal(err)
+	}
+	return application
+}
+
+func TestRuleEngine_Evaluate(t *testing.T) {
+	application := decodeApplication(t, `{
+		"yearsOfExperience": 4,
+		"expectedSalary": "85000",
+		"requiresVisa": true,
+		"currentLocation": "London",
+		"skills": ["Go", "PostgreSQL"],
+		"coverLetter": "I have shipped Kubernetes operators in Go",
+		"customFields": {"rightToWork": "No"}
+	}`)
+
+	tests := []struct {
+		name  string
+		rule  AutoRejectionRule
+		match bool
+	}{
+		{name: "lt matches", rule: AutoRejectionRule{"yearsOfExperience", RuleLessThan, 5.0}, match: true},
+		{name: "lt passes", rule: AutoRejectionRule{"yearsOfExperience", RuleLessThan, 3.0}},
+		{name: "lte at the boundary", rule: AutoRejectionRule{"yearsOfExperience", RuleLessOrEqual, 4.0}, match: true},
+		{name: "gt on a numeric string", rule: AutoRejectionRule{"expectedSalary", RuleGreaterThan, 80000.0}, match: true},
+		{name: "gte passes", rule: AutoRejectionRule{"expectedSalary", RuleGreaterOrEq, 90000.0}},
+		{name: "eq boolean", rule: AutoRejectionRule{"requiresVisa", RuleEquals, true}, match: true},
+		{name: "eq ignores case", rule: AutoRejectionRule{"currentLocation", RuleEquals, " london "}, match: true},
+		{name: "neq", rule: AutoRejectionRule{"currentLocation", RuleNotEquals, "London"}},
+		{name: "in", rule: AutoRejectionRule{"currentLocation", RuleIn, []interface{}{"Paris", "LONDON"}}, match: true},
+		{name: "not_in", rule: AutoRejectionRule{"currentLocation", RuleNotIn, []interface{}{"Berlin", "Paris"}}, match: true},
+		{name: "not_in passes", rule: AutoRejectionRule{"currentLocation", RuleNotIn, []interface{}{"London"}}},
+		{name: "contains list item", rule: AutoRejectionRule{"skills", RuleContains, "go"}, match: true},
+		{name: "not_contains list item", rule: AutoRejectionRule{"skills", RuleNotContains, "Go"}},
+		{name: "contains substring", rule: AutoRejectionRule{"coverLetter", RuleContains, "kubernetes"}, match: true},
+		{name: "dotted path", rule: AutoRejectionRule{"customFields.rightToWork", RuleEquals, "no"}, match: true},
+		{name: "missing field never matches", rule: AutoRejectionRule{"degree", RuleNotEquals, "PhD"}},
+		{name: "missing nested field", rule: AutoRejectionRule{"customFields.visa.type", RuleEquals, "H1B"}},
+		{name: "number against a word", rule: AutoRejectionRule{"currentLocation", RuleLessThan, 3.0}},
+	}
+
+	engine := NewRuleEngine(nil)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, matched := engine.Evaluate([]AutoRejectionRule{tt.rule}, application)
+			if matched != tt.match {
+				t.Fatalf("Evaluate(%s) matched = %v, want %v", tt.rule, matched, tt.match)
+			}
+			if matched && !reflect.DeepEqual(rule, tt.rule) {
+				t.Fatalf("Evaluate() = %s, want %s", rule, tt.rule)
+			}
+		})
+	}
+
+	t.Run("first match wins", func(t *testing.T) {
+		rules := []AutoRejectionRule{
+			{"yearsOfExperience", RuleGreaterThan, 10.0},
+			{"requiresVisa", RuleEquals, true},
+			{"currentLocation", RuleEquals, "London"},
+		}
+		rule, matched := engine.Evaluate(rules, application)
+		if !matched || rule.Field != "requiresVisa" {
+			t.Fatalf("Evaluate() = %s, %v, want the visa rule", rule, matched)
+		}
+	})
+
+	t.Run("no rules", func(t *testing.T) {
+		if _, matched := engine.Evaluate(nil, application); matched {
+			t.Fatal("Evaluate() matched without rules")
+		}
+	})
+}
+
+func TestValidateRejectionRules(t *testing.T) {
+	tooMany := make([]AutoRejectionRule, maxRejectionRules+1)
+	for i := range tooMany {
+		tooMany[i] = AutoRejectionRule{"requiresVisa", RuleEquals, true}
+	}
+
+	tests := []struct {
+		name    string
+		rules   []AutoRejectionRule
+		wantErr bool
+	}{
+		{name: "none"},
+		{name: "valid", rules: []AutoRejectionRule{
+			{"yearsOfExperience", RuleLessThan, 3.0},
+			{"expectedSalary", RuleGreaterThan, "120000"},
+			{"currentLocation", RuleNotIn, []interface{}{"London"}},
+			{"skills", RuleNotContains, "Go"},
+		}},
+		{name: "no field", rules: []AutoRejectionRule{{" ", RuleEquals, true}}, wantErr: true},
+		{name: "unknown operator", rules: []AutoRejectionRule{{"yearsOfExperience", "between", 3.0}}, wantErr: true},
+		{name: "no value", rules: []AutoRejectionRule{{"requiresVisa", RuleEquals, nil}}, wantErr: true},
+		{name: "non-number comparison", rules: []AutoRejectionRule{{"yearsOfExperience", RuleLessThan, "three"}}, wantErr: true},
+		{name: "in without a list", rules: []AutoRejectionRule{{"currentLocation", RuleIn, "London"}}, wantErr: true},
+		{name: "too many", rules: tooMany, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRejectionRules(tt.rules)
+			if (err != nil) != tt.wantErr || (err != nil && !errors.Is(err, ErrInvalidRejectionRule)) {
+				t.Fatalf("ValidateRejectionRules() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRuleEngine_Rules(t *testing.T) {
+	var saved []interface{}
+	client := newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+		switch req.Query {
+		case gateway.GetJobAutoRejectionRulesQuery:
+			switch req.Variables["id"] {
+			case "job-1":
+				return map[string]interface{}{"job": map[string]interface{}{"id": "job-1", "autoRejectionRules": []interface{}{
+					map[string]interface{}{"field": "requiresVisa", "operator": "eq", "value": true},
+				}}}
+			case "job-2":
+				return map[string]interface{}{"job": map[string]interface{}{"id": "job-2", "autoRejectionRules": nil}}
+			}
+			return map[string]interface{}{"job": nil}
+		case gateway.UpdateJobAutoRejectionRulesMutation:
+			saved, _ = req.Variables["rules"].([]interface{})
+			return map[string]interface{}{"updateJobAutoRejectionRules": saved}
+		}
+		return map[string]interface{}{}
+	})
+	engine := NewRuleEngine(client)
+	ctx := context.Background()
+
+	rules, err := engine.Rules(ctx, "job-1")
+	if err != nil || !reflect.DeepEqual(rules, []AutoRejectionRule{{"requiresVisa", RuleEquals, true}}) {
+		t.Fatalf("Rules(job-1) = %v, %v", rules, err)
+	}
+	if rules, err := engine.Rules(ctx, "job-2"); err != nil || rules == nil || len(rules) != 0 {
+		t.Fatalf("Rules(job-2) = %#v, %v, want an empty list", rules, err)
+	}
+	if _, err := engine.Rules(ctx, "job-9"); !errors.Is(err, ErrJobNotFound) {
+		t.Fatalf("Rules(job-9) error = %v, want ErrJobNotFound", err)
+	}
+
+	update := []AutoRejectionRule{{"yearsOfExperience", RuleLessThan, 3.0}}
+	got, err := engine.UpdateRules(ctx, "job-1", update)
+	if err != nil || !reflect.DeepEqual(got, update) || len(saved) != 1 {
+		t.Fatalf("UpdateRules() = %v, %v, want the rules saved", got, err)
+	}
+
+	saved = nil
+	if _, err := engine.UpdateRules(ctx, "job-1", []AutoRejectionRule{{"yearsOfExperience", "between", 3.0}}); !errors.Is(err, ErrInvalidRejectionRule) {
+		t.Fatalf("UpdateRules() error = %v, want ErrInvalidRejectionRule", err)
+	}
+	if saved != nil {
+		t.Fatal("invalid rules were sent to Hub-HRMS")
+	}
+}
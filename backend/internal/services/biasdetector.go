@@ -0,0 +1,107 @@
+package services
+
+import (
+	"log/slog"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// BiasWarning describes a potentially biased phrase found in job copy
+type BiasWarning struct {
+	Term       string `json:"term"`
+	Context    string `json:"context"`
+	Suggestion string `json:"suggestion"`
+}
+
+// biasedTerm is an entry in the built-in list of terms to flag
+type biasedTerm struct {
+	pattern    string
+	suggestion string
+}
+
+// biasedTerms covers jargon that discourages applicants and gender-coded
+// adjectives commonly found in job postings
+var biasedTerms = []biasedTerm{
+	{`ninjas?`, "Use a concrete title such as \"expert\" or \"specialist\""},
+	{`rock ?stars?`, "Use a concrete title such as \"expert\" or \"specialist\""},
+	{`gurus?`, "Use \"expert\" or describe the actual expertise needed"},
+	{`wizards?`, "Use \"expert\" or describe the actual expertise needed"},
+	{`superstars?`, "Describe the skills and outcomes you are looking for"},
+	{`hackers?`, "Use \"engineer\" or \"developer\""},
+	{`aggressive`, "Use \"ambitious\" or \"proactive\""},
+	{`dominant`, "Use \"leading\" or \"influential\""},
+	{`competitive`, "Use \"motivated\" or \"driven\""},
+	{`fearless`, "Use \"confident\" or \"willing to take initiative\""},
+	{`assertive`, "Use \"confident\" or \"clear communicator\""},
+	{`headstrong`, "Use \"determined\""},
+	{`manpower`, "Use \"workforce\" or \"staff\""},
+	{`chairman`, "Use \"chair\" or \"chairperson\""},
+	{`salesman`, "Use \"salesperson\" or \"sales representative\""},
+	{`he or she`, "Use \"they\""},
+	{`he/she`, "Use \"they\""},
+	{`young`, "Avoid age-related language; describe the experience level instead"},
+	{`digital natives?`, "Avoid age-related language; describe the skills instead"},
+	{`recent grad(uate)?s?`, "Describe the experience level rather than graduation date"},
+	{`native (english )?speakers?`, "Use \"fluent in English\""},
+	{`culture fit`, "Use \"culture add\" or describe the values you hold"},
+	{`work hard,? play hard`, "Describe the working hours and team culture concretely"},
+}
+
+// BiasDetector flags biased language in generated job descriptions
+type BiasDetector struct {
+	patterns []*regexp.Regexp
+	terms    []biasedTerm
+}
+
+// NewBiasDetector creates a new bias detector using the built-in term list
+func NewBiasDetector() *BiasDetector {
+	d := &BiasDetector{terms: biasedTerms}
+	for _, term := range biasedTerms {
+		d.patterns = append(d.patterns, regexp.MustCompile(`(?i)\b`+term.pattern+`\b`))
+	}
+	return d
+}
+
+// Check returns a warning for every biased term found in text
+func (d *BiasDetector) Check(text string) []BiasWarning {
+	warnings := []BiasWarning{}
+	for i, pattern := range d.patterns {
+		for _, loc := range pattern.FindAllStringIndex(text, -1) {
+			warnings = append(warnings, BiasWarning{
+				Term:       text[loc[0]:loc[1]],
+				Context:    surroundingText(text, loc[0], loc[1], 40),
+				Suggestion: d.terms[i].suggestion,
+			})
+		}
+	}
+
+	// Advisory only, but logged so DEI reporting can aggregate them
+	for _, warning := range warnings {
+		slog.Info("bias_warning", "term", strings.ToLower(warning.Term))
+	}
+
+	return warnings
+}
+
+// surroundingText returns the match with up to radius bytes on either side
+func surroundingText(text string, start, end, radius int) string {
+	from := start - radius
+	if from < 0 {
+		from = 0
+	}
+	to := end + radius
+	if to > len(text) {
+		to = len(text)
+	}
+
+	// Don't cut a multi-byte character in half
+	for from > 0 && !utf8.RuneStart(text[from]) {
+		from--
+	}
+	for to < len(text) && !utf8.RuneStart(text[to]) {
+		to++
+	}
+
+	return strings.TrimSpace(text[from:to])
+}
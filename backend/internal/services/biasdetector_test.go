@@ -0,0 +1,62 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBiasDetector_Check(t *testing.T) {
+	detector := NewBiasDetector()
+
+	tests := []struct {
+		name      string
+		text      string
+		wantTerms []string
+	}{
+		{name: "neutral", text: "We are hiring an experienced backend engineer to own our billing platform."},
+		{name: "jargon", text: "Join us as a Go Ninja.", wantTerms: []string{"Ninja"}},
+		{name: "plural and spacing", text: "We need rock stars and rockstars.", wantTerms: []string{"rock stars", "rockstars"}},
+		{name: "gender-coded", text: "An aggressive, dominant closer. He or she will report to the chairman.", wantTerms: []string{"aggressive", "dominant", "chairman", "He or she"}},
+		{name: "age-coded", text: "Ideal for recent graduates and digital natives.", wantTerms: []string{"digital natives", "recent graduates"}},
+		{name: "phrases", text: "Culture fit matters here: we work hard, play hard.", wantTerms: []string{"Culture fit", "work hard, play hard"}},
+		// Whole words only
+		{name: "inside other words", text: "A youngster-friendly, noncompetitive mentorship for uncompetitiveness.", wantTerms: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			warnings := detector.Check(tt.text)
+			if warnings == nil {
+				t.Fatal("Check() = nil, want an empty list so the response has []")
+			}
+			var terms []string
+			for _, warning := range warnings {
+				terms = append(terms, warning.Term)
+				if warning.Suggestion == "" || !strings.Contains(warning.Context, warning.Term) {
+					t.Errorf("warning = %+v, want a suggestion and the term in context", warning)
+				}
+			}
+			if strings.Join(terms, ",") != strings.Join(tt.wantTerms, ",") {
+				t.Fatalf("terms = %v, want %v", terms, tt.wantTerms)
+			}
+		})
+	}
+}
+
+func TestSurroundingText(t *testing.T) {
+	text := "We are looking for a ninja who loves distributed systems"
+	start := strings.Index(text, "ninja")
+
+	if got := surroundingText(text, start, start+len("ninja"), 10); got != "ing for a ninja who loves" {
+		t.Fatalf("surroundingText() = %q", got)
+	}
+	if got := surroundingText(text, start, start+len("ninja"), 1000); got != text {
+		t.Fatalf("surroundingText() with a large radius = %q, want the whole text", got)
+	}
+
+	// The radius ends inside "é" and "ü", which are kept whole
+	text = "Café ninja über"
+	start = strings.Index(text, "ninja")
+	if got := surroundingText(text, start, start+len("ninja"), 2); got != "é ninja ü" {
+		t.Fatalf("surroundingText() = %q, want whole characters", got)
+	}
+}
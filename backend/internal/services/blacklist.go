@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+var (
+	// ErrBlacklistEntryNotFound is returned when removing an unknown entry
+	ErrBlacklistEntryNotFound = errors.New("blacklist entry not found")
+	// ErrInvalidBlacklistEntry is returned for entries without an email or reason
+	ErrInvalidBlacklistEntry = errors.New("invalid blacklist entry")
+)
+
+// BlacklistEntry blocks applications from an email address, permanently or
+// until ExpiresAt
+type BlacklistEntry struct {
+	ID        string     `json:"id"`
+	Email     string     `json:"email"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	CreatedBy string     `json:"createdBy,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// BlacklistChecker screens applicants against the candidate blacklist kept
+// in Hub-HRMS, and manages its entries
+type BlacklistChecker struct {
+	client *gateway.HubHRMSClient
+}
+
+// NewBlacklistChecker creates a checker backed by Hub-HRMS
+func NewBlacklistChecker(client *gateway.HubHRMSClient) *BlacklistChecker {
+	return &BlacklistChecker{client: client}
+}
+
+// IsBlocked reports whether applications from email are refused. Expired
+// entries no longer block.
+func (c *BlacklistChecker) IsBlocked(ctx context.Context, email string) (bool, error) {
+	normalized := NormalizeEmail(email)
+	if normalized == "" {
+		return false, nil
+	}
+
+	resp, err := c.client.Query(ctx, gateway.CheckBlacklistQuery, map[string]interface{}{
+		"email": normalized,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to check blacklist: %w", err)
+	}
+
+	var entry struct {
+		ExpiresAt *time.Time `json:"expiresAt"`
+	}
+	found, err := decodeField(resp.Data, "blacklistEntry", &entry)
+	if err != nil || !found {
+		return false, err
+	}
+	return entry.ExpiresAt == nil || time.Now().Before(*entry.ExpiresAt), nil
+}
+
+// Add blacklists email. A nil expiresAt blocks it indefinitely.
+func (c *BlacklistChecker) Add(ctx context.Context, email, reason string, expiresAt *time.Time, createdBy string) (*BlacklistEntry, error) {
+	normalized := NormalizeEmail(email)
+	if normalized == "" {
+		return nil, fmt.Errorf("%w: email is required", ErrInvalidBlacklistEntry)
+	}
+	if strings.TrimSpace(reason) == "" {
+		return nil, fmt.Errorf("%w: reason is required", ErrInvalidBlacklistEntry)
+	}
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return nil, fmt.Errorf("%w: expiresAt must be in the future", ErrInvalidBlacklistEntry)
+	}
+
+	input := map[string]interface{}{
+		"email":     normalized,
+		"reason":    reason,
+		"createdBy": createdBy,
+	}
+	if expiresAt != nil {
+		input["expiresAt"] = expiresAt.UTC().Format(time.RFC3339)
+	}
+
+	resp, err := c.client.Mutate(ctx, gateway.BlacklistCandidateMutation, map[string]interface{}{"input": input})
+	if err != nil {
+		return nil, fmt.Errorf("failed to blacklist candidate: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("failed to blacklist candidate: %s", resp.Errors[0].Message)
+	}
+
+	var entry BlacklistEntry
+	if _, err := decodeField(resp.Data, "blacklistCandidate", &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// List returns every blacklist entry, including expired ones
+func (c *BlacklistChecker) List(ctx context.Context) ([]BlacklistEntry, error) {
+	resp, err := c.client.Query(ctx, gateway.GetBlacklistQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch blacklist: %w", err)
+	}
+
+	entries := []BlacklistEntry{}
+	if _, err := decodeField(resp.Data, "blacklist", &entries); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Remove deletes a blacklist entry
+func (c *BlacklistChecker) Remove(ctx context.Context, id string) error {
+	resp, err := c.client.Mutate(ctx, gateway.RemoveBlacklistEntryMutation, map[string]interface{}{"id": id})
+	if err != nil {
+		return fmt.Errorf("failed to remove blacklist entry: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("failed to remove blacklist entry: %s", resp.Errors[0].Message)
+	}
+
+	data, _ := resp.Data.(map[string]interface{})
+	if removed, _ := data["removeBlacklistEntry"].(bool); !removed {
+		return ErrBlacklistEntryNotFound
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// blacklistServer fakes Hub-HRMS holding entries by email, recording the
+// addresses it was asked to check
+type blacklistServer struct {
+	mu      sync.Mutex
+	entries map[string]map[string]interface{}
+	checked []string
+}
+
+func newBlacklistChecker(t *testing.T, entries map[string]map[string]interface{}) (*BlacklistChecker, *blacklistServer) {
+	server := &blacklistServer{entries: entries}
+	return NewBlacklistChecker(newFakeHubHRMS(t, server.respond)), server
+}
+
+func (s *blacklistServer) respond(req gateway.GraphQLRequest) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch req.Query {
+	case gateway.CheckBlacklistQuery:
+		email, _ := req.Variables["email"].(string)
+		s.checked = append(s.checked, email)
+		if entry, ok := s.entries[email]; ok {
+			return map[string]interface{}{"blacklistEntry": entry}
+		}
+		return map[string]interface{}{"blacklistEntry": nil}
+	case gateway.BlacklistCandidateMutation:
+		input, _ := req.Variables["input"].(map[string]interface{})
+		entry := map[string]interface{}{"id": "bl-1", "createdAt": "2026-03-01T09:00:00Z"}
+		for key, value := range input {
+			entry[key] = value
+		}
+		return map[string]interface{}{"blacklistCandidate": entry}
+	case gateway.RemoveBlacklistEntryMutation:
+		return map[string]interface{}{"removeBlacklistEntry": req.Variables["id"] == "bl-1"}
+	}
+	return map[string]interface{}{}
+}
+
+func TestBlacklistChecker_IsBlocked(t *testing.T) {
+	checker, server := newBlacklistChecker(t, map[string]map[string]interface{}{
+		"fraud@example.com":     {"id": "bl-1"},
+		"cooling@example.com":   {"id": "bl-2", "expiresAt": time.Now().Add(24 * time.Hour).Format(time.RFC3339)},
+		"reformed@example.com":  {"id": "bl-3", "expiresAt": time.Now().Add(-time.Hour).Format(time.RFC3339)},
+		"forgotten@example.com": {"id": "bl-4", "expiresAt": nil},
+	})
+
+	tests := []struct {
+		name  string
+		email string
+		want  bool
+	}{
+		{name: "permanent entry", email: "fraud@example.com", want: true},
+		{name: "address normalised", email: "  Fraud@Example.COM ", want: true},
+		{name: "unexpired entry", email: "cooling@example.com", want: true},
+		{name: "expired entry", email: "reformed@example.com", want: false},
+		{name: "null expiry", email: "forgotten@example.com", want: true},
+		{name: "not listed", email: "ada@example.com", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			blocked, err := checker.IsBlocked(context.Background(), tt.email)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if blocked != tt.want {
+				t.Fatalf("IsBlocked(%q) = %v, want %v", tt.email, blocked, tt.want)
+			}
+		})
+	}
+
+	if blocked, err := checker.IsBlocked(context.Background(), "  "); blocked || err != nil {
+		t.Fatalf("IsBlocked of a blank address = %v, %v; want false", blocked, err)
+	}
+	if len(server.checked) != len(tests) || server.checked[1] != "fraud@example.com" {
+		t.Fatalf("checked %v, want each normalised address and no blank one", server.checked)
+	}
+}
+
+func TestBlacklistChecker_Add(t *testing.T) {
+	checker, _ := newBlacklistChecker(t, nil)
+	ctx := context.Background()
+	expiresAt := time.Now().Add(30 * 24 * time.Hour)
+	past := time.Now().Add(-time.Minute)
+
+	entry, err := checker.Add(ctx, " Spammer@Example.com", "Abusive cover letter", &expiresAt, "admin-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.ID != "bl-1" || entry.Email != "spammer@example.com" || entry.CreatedBy != "admin-1" ||
+		entry.ExpiresAt == nil || !entry.ExpiresAt.Equal(expiresAt.Truncate(time.Second)) {
+		t.Fatalf("entry = %+v, want the normalised address with its expiry", entry)
+	}
+
+	tests := []struct {
+		name      string
+		email     string
+		reason    string
+		expiresAt *time.Time
+	}{
+		{name: "no email", reason: "Fraud"},
+		{name: "no reason", email: "fraud@example.com", reason: " "},
+		{name: "expiry in the past", email: "fraud@example.com", reason: "Fraud", expiresAt: &past},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := checker.Add(ctx, tt.email, tt.reason, tt.expiresAt, "admin-1"); !errors.Is(err, ErrInvalidBlacklistEntry) {
+				t.Fatalf("Add error = %v, want ErrInvalidBlacklistEntry", err)
+			}
+		})
+	}
+}
+
+func TestBlacklistChecker_Remove(t *testing.T) {
+	checker, _ := newBlacklistChecker(t, nil)
+
+	if err := checker.Remove(context.Background(), "bl-1"); err != nil {
+		t.Fatal(err)
+	}
+	if err := checker.Remove(context.Background(), "bl-9"); !errors.Is(err, ErrBlacklistEntryNotFound) {
+		t.Fatalf("Remove error = %v, want ErrBlacklistEntryNotFound", err)
+	}
+}
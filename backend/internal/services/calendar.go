@@ -0,0 +1,138 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// icsTimeFormat is the RFC 5545 UTC date-time form
+const icsTimeFormat = "20060102T150405Z"
+
+// InterviewDateFormat is how interview times are written in emails
+const InterviewDateFormat = "Monday, January 2, 2006 at 3:04 PM MST"
+
+// Attendee is a person invited to a calendar event
+type Attendee struct {
+	Name  string
+	Email string
+}
+
+// InterviewEvent describes an interview to put on attendees' calendars
+type InterviewEvent struct {
+	UID         string
+	Summary     string
+	Description string
+	Location    string
+	Start       time.Time
+	Duration    time.Duration
+	Attendees   []Attendee
+}
+
+// CalendarService generates calendar invitations sent on behalf of the
+// recruiting team
+type CalendarService struct {
+	organizerName  string
+	organizerEmail string
+}
+
+// NewCalendarService creates a calendar service whose invitations are
+// organized by the given mailbox
+func NewCalendarService(organizerName, organizerEmail string) *CalendarService {
+	return &CalendarService{
+		organizerName:  organizerName,
+		organizerEmail: organizerEmail,
+	}
+}
+
+// GenerateICS renders event as an RFC 5545 iCalendar REQUEST, which mail
+// clients present as an invitation the attendee can accept
+func (s *CalendarService) GenerateICS(event InterviewEvent) []byte {
+	var buf bytes.Buffer
+	line := func(content string) {
+		writeFolded(&buf, content)
+	}
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//HR Recruiting//Interview Scheduler//EN")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:REQUEST")
+	line("BEGIN:VEVENT")
+	line("UID:" + escapeICSText(event.UID))
+	line("DTSTAMP:" + time.Now().UTC().Format(icsTimeFormat))
+	line("DTSTART:" + event.Start.UTC().Format(icsTimeFormat))
+	line("DURATION:" + formatICSDuration(event.Duration))
+	line("SUMMARY:" + escapeICSText(event.Summary))
+	if event.Description != "" {
+		line("DESCRIPTION:" + escapeICSText(event.Description))
+	}
+	if event.Location != "" {
+		line("LOCATION:" + escapeICSText(event.Location))
+	}
+	line(fmt.Sprintf("ORGANIZER;CN=%s:mailto:%s", quoteICSParam(s.organizerName), s.organizerEmail))
+	for _, attendee := range event.Attendees {
+		line(fmt.Sprintf("ATTENDEE;CN=%s;ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:%s",
+			quoteICSParam(attendee.Name), attendee.Email))
+	}
+	line("STATUS:CONFIRMED")
+	line("SEQUENCE:0")
+	line("END:VEVENT")
+	line("END:VCALENDAR")
+
+	return buf.Bytes()
+}
+
+// formatICSDuration renders d as an RFC 5545 duration such as PT1H30M
+func formatICSDuration(d time.Duration) string {
+	if d <= 0 {
+		return "PT0S"
+	}
+
+	var b strings.Builder
+	b.WriteString("PT")
+	if h := d / time.Hour; h > 0 {
+		fmt.Fprintf(&b, "%dH", h)
+		d -= h * time.Hour
+	}
+	if m := d / time.Minute; m > 0 {
+		fmt.Fprintf(&b, "%dM", m)
+		d -= m * time.Minute
+	}
+	if s := d / time.Second; s > 0 {
+		fmt.Fprintf(&b, "%dS", s)
+	}
+	return b.String()
+}
+
+// icsTextEscaper escapes TEXT values per RFC 5545 section 3.3.11
+var icsTextEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+func escapeICSText(s string) string {
+	return icsTextEscaper.Replace(s)
+}
+
+// quoteICSParam quotes a parameter value, which may not contain DQUOTE
+func quoteICSParam(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "'") + `"`
+}
+
+// writeFolded writes a content line, folding it at 75 octets without
+// splitting a UTF-8 sequence
+func writeFolded(buf *bytes.Buffer, content string) {
+	const limit = 75
+
+	width := 0
+	for _, r := range content {
+		size := utf8.RuneLen(r)
+		if width+size > limit {
+			buf.WriteString("\r\n ")
+			width = 1
+		}
+		buf.WriteRune(r)
+		width += size
+	}
+	buf.WriteString("\r\n")
+}
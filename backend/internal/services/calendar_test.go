@@ -0,0 +1,130 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+// icsLines unfolds an iCalendar file into its content lines
+func icsLines(t *testing.T, ics []byte) []string {
+	t.Helper()
+	text := string(ics)
+	if !strings.HasSuffix(text, "\r\n") || strings.Contains(strings.ReplaceAll(text, "\r\n", ""), "\n") {
+		t.Fatalf("ICS lines must end in CRLF:\n%q", text)
+	}
+	return strings.Split(strings.TrimSuffix(strings.ReplaceAll(text, "\r\n ", ""), "\r\n"), "\r\n")
+}
+
+// icsProperty returns the values of every line of the named property
+func icsProperty(lines []string, name string) []string {
+	var values []string
+	for _, line := range lines {
+		if strings.HasPrefix(line, name+":") || strings.HasPrefix(line, name+";") {
+			values = append(values, line)
+		}
+	}
+	return values
+}
+
+func TestCalendarService_GenerateICS(t *testing.T) {
+	calendar := NewCalendarService("Recruiting Team", "recruiting@example.com")
+	start := time.Date(2026, 11, 3, 14, 30, 0, 0, time.FixedZone("CET", 3600))
+	ics := calendar.GenerateICS(InterviewEvent{
+		UID:         "int-1@hr-recruiting",
+		Summary:     "Interview: Ada Lovelace - Analyst, Engines",
+		Description: "Bring your notes; we'll discuss\nthe design",
+		Location:    "Room 4",
+		Start:       start,
+		Duration:    90 * time.Minute,
+		Attendees: []Attendee{
+			{Name: "Ada Lovelace", Email: "ada@example.com"},
+			{Name: `Grace "Amazing" Hopper`, Email: "grace@example.com"},
+		},
+	})
+	lines := icsLines(t, ics)
+
+	if lines[0] != "BEGIN:VCALENDAR" || lines[len(lines)-1] != "END:VCALENDAR" || !strings.Contains(string(ics), "METHOD:REQUEST\r\n") {
+		t.Fatalf("ICS is not a calendar REQUEST:\n%s", ics)
+	}
+	for name, want := range map[string]string{
+		"DTSTART":     "DTSTART:20261103T133000Z",
+		"DURATION":    "DURATION:PT1H30M",
+		"UID":         "UID:int-1@hr-recruiting",
+		"SUMMARY":     `SUMMARY:Interview: Ada Lovelace - Analyst\, Engines`,
+		"DESCRIPTION": `DESCRIPTION:Bring your notes\; we'll discuss\nthe design`,
+		"LOCATION":    "LOCATION:Room 4",
+		"ORGANIZER":   `ORGANIZER;CN="Recruiting Team":mailto:recruiting@example.com`,
+	} {
+		if got := icsProperty(lines, name); len(got) != 1 || got[0] != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+
+	attendees := icsProperty(lines, "ATTENDEE")
+	want := []string{
+		`ATTENDEE;CN="Ada Lovelace";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:ada@example.com`,
+		`ATTENDEE;CN="Grace 'Amazing' Hopper";ROLE=REQ-PARTICIPANT;PARTSTAT=NEEDS-ACTION;RSVP=TRUE:mailto:grace@example.com`,
+	}
+	if strings.Join(attendees, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("ATTENDEE lines = %q, want %q", attendees, want)
+	}
+
+	// Folded lines stay within 75 octets
+	for _, line := range strings.Split(string(ics), "\r\n") {
+		if len(line) > 75 {
+			t.Fatalf("line of %d octets: %q", len(line), line)
+		}
+	}
+}
+
+func TestCalendarService_GenerateICS_OptionalFields(t *testing.T) {
+	ics := NewCalendarService("Recruiting", "recruiting@example.com").GenerateICS(InterviewEvent{
+		UID:     "int-2@hr-recruiting",
+		Summary: "Interview",
+		Start:   time.Date(2026, 11, 3, 9, 0, 0, 0, time.UTC),
+	})
+	lines := icsLines(t, ics)
+	if len(icsProperty(lines, "DESCRIPTION")) != 0 || len(icsProperty(lines, "LOCATION")) != 0 || len(icsProperty(lines, "ATTENDEE")) != 0 {
+		t.Fatalf("empty fields were written:\n%s", ics)
+	}
+	if got := icsProperty(lines, "DURATION"); got[0] != "DURATION:PT0S" {
+		t.Fatalf("DURATION = %q, want PT0S", got)
+	}
+}
+
+func TestFormatICSDuration(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 30 * time.Minute, want: "PT30M"},
+		{d: time.Hour, want: "PT1H"},
+		{d: 2*time.Hour + 15*time.Minute, want: "PT2H15M"},
+		{d: 45 * time.Second, want: "PT45S"},
+		{d: 0, want: "PT0S"},
+		{d: -time.Minute, want: "PT0S"},
+	}
+	for _, tt := range tests {
+		if got := formatICSDuration(tt.d); got != tt.want {
+			t.Errorf("formatICSDuration(%v) = %s, want %s", tt.d, got, tt.want)
+		}
+	}
+}
+
+func TestWriteFolded_KeepsRunesWhole(t *testing.T) {
+	ics := NewCalendarService("Recruiting", "recruiting@example.com").GenerateICS(InterviewEvent{
+		UID:     "int-3",
+		Summary: strings.Repeat("é", 100),
+		Start:   time.Date(2026, 11, 3, 9, 0, 0, 0, time.UTC),
+	})
+	for _, line := range strings.Split(string(ics), "\r\n") {
+		if !utf8.ValidString(line) {
+			t.Fatalf("folding split a UTF-8 sequence: %q", line)
+		}
+	}
+	if got := icsProperty(icsLines(t, ics), "SUMMARY"); got[0] != "SUMMARY:"+strings.Repeat("é", 100) {
+		t.Fatalf("unfolded SUMMARY = %q", got)
+	}
+}
@@ -0,0 +1,308 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// Campaign sending limits
+const (
+	// MaxCampaignRecipients caps the emails one campaign may send
+	MaxCampaignRecipients = 500
+	// campaignBatchSize is how many emails are queued at once
+	campaignBatchSize = 100
+	// campaignBatchInterval spaces batches out, so a campaign does not
+	// crowd transactional email out of the queue
+	campaignBatchInterval = time.Minute
+)
+
+var (
+	// ErrInvalidCampaign is returned for campaigns without a subject or
+	// with an unknown template
+	ErrInvalidCampaign = errors.New("invalid campaign")
+	// ErrCampaignTooLarge is returned for campaigns over
+	// MaxCampaignRecipients recipients
+	ErrCampaignTooLarge = errors.New("campaign has too many recipients")
+	// ErrNoCampaignRecipients is returned when no one in the pool can be
+	// emailed
+	ErrNoCampaignRecipients = errors.New("campaign has no recipients")
+)
+
+// CampaignInput is the outreach a recruiter sends to a pool. JobID, when
+// set, is the role the email is about.
+type CampaignInput struct {
+	Subject      string `json:"subject"`
+	TemplateName string `json:"templateName"`
+	JobID        string `json:"jobId,omitempty"`
+}
+
+// Campaign is outreach sent to a candidate pool
+type Campaign struct {
+	ID             string    `json:"id"`
+	PoolID         string    `json:"poolId"`
+	Subject        string    `json:"subject"`
+	TemplateName   string    `json:"templateName"`
+	JobID          string    `json:"jobId,omitempty"`
+	RecipientCount int       `json:"recipientCount"`
+	SentCount      int       `json:"sentCount"`
+	OpenedCount    int       `json:"openedCount"`
+	CreatedBy      string    `json:"createdBy,omitempty"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// CampaignRecipient is a pool candidate a campaign may email
+type CampaignRecipient struct {
+	ID        string `json:"id"`
+	FirstName string `json:"firstName"`
+	LastName  string `json:"lastName"`
+	Email     string `json:"email"`
+	// EmailOptOut is set for candidates who unsubscribed from outreach
+	EmailOptOut bool `json:"emailOptOut"`
+}
+
+// CampaignService sends email campaigns to candidate pools through the
+// email queue and records them in Hub-HRMS
+type CampaignService struct {
+	client    *gateway.HubHRMSClient
+	pools     *CandidatePoolService
+	queue     EmailEnqueuer
+	templates *TemplateEngine
+
+	batchInterval time.Duration
+}
+
+// NewCampaignService creates a new campaign service
+func NewCampaignService(client *gateway.HubHRMSClient, pools *CandidatePoolService, queue EmailEnqueuer, templates *TemplateEngine) *CampaignService {
+	return &CampaignService{
+		client:        client,
+		pools:         pools,
+		queue:         queue,
+		templates:     templates,
+		batchInterval: campaignBatchInterval,
+	}
+}
+
+// Start records a campaign to a pool's candidates and begins sending it in
+// the background, one batch a minute. Candidates who opted out and
+// repeated addresses are left out.
+func (s *CampaignService) Start(ctx context.Context, poolID string, input CampaignInput, createdBy string) (*Campaign, error) {
+	if err := s.validate(input); err != nil {
+		return nil, err
+	}
+	pool, err := s.pools.Get(ctx, poolID)
+	if err != nil {
+		return nil, err
+	}
+
+	jobTitle, err := s.jobTitle(ctx, input.JobID)
+	if err != nil {
+		return nil, err
+	}
+
+	candidates, err := s.candidates(ctx, pool.CandidateIDs)
+	if err != nil {
+		return nil, err
+	}
+	recipients := CampaignRecipients(candidates)
+	if len(recipients) == 0 {
+		return nil, ErrNoCampaignRecipients
+	}
+	if len(recipients) > MaxCampaignRecipients {
+		return nil, fmt.Errorf("%w: %d recipients, at most %d allowed", ErrCampaignTooLarge, len(recipients), MaxCampaignRecipients)
+	}
+
+	resp, err := s.client.Mutate(ctx, gateway.CreateCampaignMutation, map[string]interface{}{
+		"input": map[string]interface{}{
+			"poolId":         poolID,
+			"subject":        input.Subject,
+			"templateName":   input.TemplateName,
+			"jobId":          input.JobID,
+			"recipientCount": len(recipients),
+			"createdBy":      createdBy,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create campaign: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("failed to create campaign: %s", resp.Errors[0].Message)
+	}
+	var campaign Campaign
+	if _, err := decodeField(resp.Data, "createCampaign", &campaign); err != nil {
+		return nil, err
+	}
+
+	go s.send(campaign, recipients, jobTitle)
+	return &campaign, nil
+}
+
+// List returns the campaigns sent to a pool
+func (s *CampaignService) List(ctx context.Context, poolID string) ([]Campaign, error) {
+	resp, err := s.client.Query(ctx, gateway.GetPoolCampaignsQuery, map[string]interface{}{
+		"poolId": poolID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch campaigns: %w", err)
+	}
+
+	campaigns := []Campaign{}
+	if _, err := decodeField(resp.Data, "campaigns", &campaigns); err != nil {
+		return nil, err
+	}
+	return campaigns, nil
+}
+
+// EstimatedDeliveryMinutes is how long a campaign to recipients takes to
+// queue, one batch a minute
+func EstimatedDeliveryMinutes(recipients int) int {
+	batches := (recipients + campaignBatchSize - 1) / campaignBatchSize
+	return int(time.Duration(batches) * campaignBatchInterval / time.Minute)
+}
+
+// CampaignRecipients drops candidates without an email address, those who
+// opted out of outreach and repeats of an address, keeping the first
+func CampaignRecipients(candidates []CampaignRecipient) []CampaignRecipient {
+	seen := make(map[string]bool, len(candidates))
+	recipients := make([]CampaignRecipient, 0, len(candidates))
+	for _, candidate := range candidates {
+		email := NormalizeEmail(candidate.Email)
+		if email == "" || candidate.EmailOptOut || seen[email] {
+			continue
+		}
+		seen[email] = true
+		candidate.Email = email
+		recipients = append(recipients, candidate)
+	}
+	return recipients
+}
+
+// BatchRecipients splits recipients into batches of at most size
+func BatchRecipients(recipients []CampaignRecipient, size int) [][]CampaignRecipient {
+	var batches [][]CampaignRecipient
+	for len(recipients) > 0 {
+		n := min(size, len(recipients))
+		batches = append(batches, recipients[:n])
+		recipients = recipients[n:]
+	}
+	return batches
+}
+
+// validate checks a campaign has a subject and a template that exists
+func (s *CampaignService) validate(input CampaignInput) error {
+	if strings.TrimSpace(input.Subject) == "" {
+		return fmt.Errorf("%w: subject is required", ErrInvalidCampaign)
+	}
+	for _, name := range s.templates.Names() {
+		if name == input.TemplateName {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: unknown template %q", ErrInvalidCampaign, input.TemplateName)
+}
+
+// jobTitle returns the title of the job a campaign is about, if any
+func (s *CampaignService) jobTitle(ctx context.Context, jobID string) (string, error) {
+	if jobID == "" {
+		return "", nil
+	}
+
+	resp, err := s.client.Query(ctx, gateway.GetJobQuery, map[string]interface{}{"id": jobID})
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch job: %w", err)
+	}
+	var job struct {
+		Title string `json:"title"`
+	}
+	found, err := decodeField(resp.Data, "job", &job)
+	if err != nil {
+		return "", err
+	}
+	if !found {
+		return "", ErrJobNotFound
+	}
+	return job.Title, nil
+}
+
+// candidates fetches the contact details of a pool's candidates
+func (s *CampaignService) candidates(ctx context.Context, ids []string) ([]CampaignRecipient, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	resp, err := s.client.Query(ctx, gateway.GetCampaignRecipientsQuery, map[string]interface{}{
+		"ids": ids,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pool candidates: %w", err)
+	}
+
+	var candidates []CampaignRecipient
+	if _, err := decodeField(resp.Data, "candidatesByIds", &candidates); err != nil {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// send queues a campaign's emails batch by batch and records how many were
+// queued. Emails the queue refuses are logged and not retried.
+func (s *CampaignService) send(campaign Campaign, recipients []CampaignRecipient, jobTitle string) {
+	sent := 0
+	for i, batch := range BatchRecipients(recipients, campaignBatchSize) {
+		if i > 0 {
+			time.Sleep(s.batchInterval)
+		}
+		for _, recipient := range batch {
+			err := s.queue.Enqueue(CampaignEmail(recipient, campaign, jobTitle))
+			if errors.Is(err, ErrEmailQueueClosed) {
+				slog.Warn("campaign stopped by shutdown", "campaign_id", campaign.ID, "sent", sent)
+				s.recordSent(campaign.ID, sent)
+				return
+			}
+			if err != nil {
+				slog.Warn("failed to queue campaign email",
+					"campaign_id", campaign.ID, "candidate_id", recipient.ID, "error", err)
+				continue
+			}
+			sent++
+		}
+	}
+	s.recordSent(campaign.ID, sent)
+}
+
+// recordSent stores how many of a campaign's emails were queued
+func (s *CampaignService) recordSent(campaignID string, sent int) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	resp, err := s.client.Mutate(ctx, gateway.RecordCampaignSentMutation, map[string]interface{}{
+		"campaignId": campaignID,
+		"sentCount":  sent,
+	})
+	if err == nil && len(resp.Errors) > 0 {
+		err = errors.New(resp.Errors[0].Message)
+	}
+	if err != nil {
+		slog.Warn("failed to record campaign sent count", "campaign_id", campaignID, "sent", sent, "error", err)
+	}
+}
+
+// CampaignEmail is one recipient's copy of a campaign
+func CampaignEmail(recipient CampaignRecipient, campaign Campaign, jobTitle string) EmailJob {
+	return EmailJob{
+		To:       recipient.Email,
+		Subject:  campaign.Subject,
+		Template: campaign.TemplateName,
+		Data: map[string]interface{}{
+			"FirstName":     recipient.FirstName,
+			"CandidateName": strings.TrimSpace(recipient.FirstName + " " + recipient.LastName),
+			"JobTitle":      jobTitle,
+			"JobID":         campaign.JobID,
+		},
+	}
+}
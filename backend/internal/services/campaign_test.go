@@ -0,0 +1,328 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+func TestCampaignRecipients(t *testing.T) {
+	candidates := []CampaignRecipient{
+		{ID: "cand-1", FirstName: "Ada", Email: "Ada@Example.com "},
+		{ID: "cand-2", FirstName: "Grace", Email: "grace@example.com", EmailOptOut: true},
+		{ID: "cand-3", FirstName: "Augusta", Email: "ada@example.com"},
+		{ID: "cand-4", FirstName: "Alan", Email: " "},
+		{ID: "cand-5", FirstName: "Edsger", Email: "edsger@example.com"},
+		{ID: "cand-6", FirstName: "Grace", Email: "GRACE@example.com"},
+	}
+
+	recipients := CampaignRecipients(candidates)
+	var got []string
+	for _, recipient := range recipients {
+		got = append(got, recipient.ID+":"+recipient.Email)
+	}
+	// The first of a repeated address is kept, and an opt-out does not
+	// count as the address being seen
+	want := []string{"cand-1:ada@example.com", "cand-5:edsger@example.com", "cand-6:grace@example.com"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("CampaignRecipients() = %v, want %v", got, want)
+	}
+	if candidates[0].Email != "Ada@Example.com " {
+		t.Fatal("CampaignRecipients() modified the candidates")
+	}
+	if recipients := CampaignRecipients(nil); len(recipients) != 0 {
+		t.Fatalf("CampaignRecipients(nil) = %v, want none", recipients)
+	}
+}
+
+func TestBatchRecipients(t *testing.T) {
+	tests := []struct {
+		recipients int
+		want       []int
+	}{
+		{recipients: 0},
+		{recipients: 1, want: []int{1}},
+		{recipients: 100, want: []int{100}},
+		{recipients: 101, want: []int{100, 1}},
+		{recipients: 250, want: []int{100, 100, 50}},
+		{recipients: 500, want: []int{100, 100, 100, 100, 100}},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.recipients), func(t *testing.T) {
+			recipients := testRecipients(tt.recipients)
+			var sizes []int
+			var batched []CampaignRecipient
+			for _, batch := range BatchRecipients(recipients, campaignBatchSize) {
+				sizes = append(sizes, len(batch))
+				batched = append(batched, batch...)
+			}
+			if !slices.Equal(sizes, tt.want) {
+				t.Fatalf("batch sizes = %v, want %v", sizes, tt.want)
+			}
+			if !slices.Equal(batched, recipients) {
+				t.Fatal("batches do not hold every recipient in order")
+			}
+		})
+	}
+}
+
+func TestEstimatedDeliveryMinutes(t *testing.T) {
+	for recipients, want := range map[int]int{0: 0, 1: 1, 100: 1, 101: 2, 500: 5} {
+		if got := EstimatedDeliveryMinutes(recipients); got != want {
+			t.Errorf("EstimatedDeliveryMinutes(%d) = %d, want %d", recipients, got, want)
+		}
+	}
+}
+
+// testRecipients returns n recipients with distinct addresses
+func testRecipients(n int) []CampaignRecipient {
+	recipients := make([]CampaignRecipient, n)
+	for i := range recipients {
+		recipients[i] = CampaignRecipient{
+			ID:        fmt.Sprintf("cand-%d", i+1),
+			FirstName: "Candidate",
+			Email:     fmt.Sprintf("candidate-%d@example.com", i+1),
+		}
+	}
+	return recipients
+}
+
+// campaignServer fakes Hub-HRMS with pool-1 holding candidates, and job-1
+type campaignServer struct {
+	*poolServer
+	candidates []CampaignRecipient
+
+	mu        sync.Mutex
+	created   []map[string]interface{}
+	sentCount chan int
+}
+
+func newCampaignService(t *testing.T, queue EmailEnqueuer, candidates []CampaignRecipient) (*CampaignService, *campaignServer) {
+	t.Helper()
+	pools := &poolServer{pools: make(map[string]map[string]interface{})}
+	ids := make([]string, len(candidates))
+	for i, candidate := range candidates {
+		ids[i] = candidate.ID
+	}
+	pools.pools["pool-1"] = poolRecord("pool-1", map[string]interface{}{"name": "Go engineers", "candidateIds": ids})
+
+	server := &campaignServer{poolServer: pools, candidates: candidates, sentCount: make(chan int, 1)}
+	client := newFakeHubHRMS(t, server.respond)
+	templates, err := NewTemplateEngine(emailTemplateFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service := NewCampaignService(client, NewCandidatePoolService(client), queue, templates)
+	service.batchInterval = 20 * time.Millisecond
+	return service, server
+}
+
+func (s *campaignServer) respond(req gateway.GraphQLRequest) interface{} {
+	switch req.Query {
+	case gateway.GetCampaignRecipientsQuery:
+		return map[string]interface{}{"candidatesByIds": s.candidates}
+	case gateway.GetJobQuery:
+		if req.Variables["id"] != "job-1" {
+			return map[string]interface{}{"job": nil}
+		}
+		return map[string]interface{}{"job": map[string]interface{}{"id": "job-1", "title": "Backend Engineer"}}
+	case gateway.CreateCampaignMutation:
+		input, _ := req.Variables["input"].(map[string]interface{})
+		s.mu.Lock()
+		s.created = append(s.created, input)
+		s.mu.Unlock()
+		return map[string]interface{}{"createCampaign": map[string]interface{}{
+			"id": "campaign-1", "poolId": input["poolId"], "subject": input["subject"],
+			"templateName": input["templateName"], "jobId": input["jobId"],
+			"recipientCount": input["recipientCount"], "createdAt": "2026-10-16T09:00:00Z",
+		}}
+	case gateway.RecordCampaignSentMutation:
+		sent, _ := req.Variables["sentCount"].(float64)
+		s.sentCount <- int(sent)
+		return map[string]interface{}{"recordCampaignSent": map[string]interface{}{"id": req.Variables["campaignId"]}}
+	}
+	return s.poolServer.respond(req)
+}
+
+func (s *campaignServer) createdCount() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.created)
+}
+
+// waitForSent waits for the campaign to record how many emails it queued
+func (s *campaignServer) waitForSent(t *testing.T) int {
+	t.Helper()
+	select {
+	case sent := <-s.sentCount:
+		return sent
+	case <-time.After(5 * time.Second):
+		t.Fatal("campaign never recorded its sent count")
+		return 0
+	}
+}
+
+// campaignQueue records the emails enqueued and when. With closeAfter set,
+// it refuses emails past that many as a queue shutting down does.
+type campaignQueue struct {
+	mu         sync.Mutex
+	jobs       []EmailJob
+	times      []time.Time
+	closeAfter int
+}
+
+func (q *campaignQueue) Enqueue(job EmailJob) error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closeAfter > 0 && len(q.jobs) >= q.closeAfter {
+		return ErrEmailQueueClosed
+	}
+	q.jobs = append(q.jobs, job)
+	q.times = append(q.times, time.Now())
+	return nil
+}
+
+func TestCampaignService_Start(t *testing.T) {
+	ctx := context.Background()
+	candidates := []CampaignRecipient{
+		{ID: "cand-1", FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com"},
+		{ID: "cand-2", FirstName: "Augusta", Email: "ADA@example.com"},
+		{ID: "cand-3", FirstName: "Grace", Email: "grace@example.com", EmailOptOut: true},
+		{ID: "cand-4", FirstName: "Alan", Email: "alan@example.com"},
+	}
+	queue := &campaignQueue{}
+	service, server := newCampaignService(t, queue, candidates)
+
+	input := CampaignInput{Subject: "A role you may like", TemplateName: "candidate_outreach", JobID: "job-1"}
+	campaign, err := service.Start(ctx, "pool-1", input, "user-1")
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if campaign.ID != "campaign-1" || campaign.RecipientCount != 2 {
+		t.Fatalf("Start() = %+v, want campaign-1 to two recipients", campaign)
+	}
+	if created := server.created[0]; created["createdBy"] != "user-1" || created["recipientCount"] != float64(2) {
+		t.Fatalf("created campaign = %v, want it recorded by user-1 for two recipients", created)
+	}
+
+	if sent := server.waitForSent(t); sent != 2 {
+		t.Fatalf("sent count = %d, want 2", sent)
+	}
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	var to []string
+	for _, job := range queue.jobs {
+		to = append(to, job.To)
+	}
+	if !slices.Equal(to, []string{"ada@example.com", "alan@example.com"}) {
+		t.Fatalf("emailed %v, want the duplicate and the opted-out candidate left out", to)
+	}
+	first := queue.jobs[0]
+	if first.Subject != input.Subject || first.Template != "candidate_outreach" ||
+		first.Data["CandidateName"] != "Ada Lovelace" || first.Data["JobTitle"] != "Backend Engineer" {
+		t.Fatalf("email = %+v, want the campaign for Ada about the job", first)
+	}
+}
+
+func TestCampaignService_Start_Batches(t *testing.T) {
+	queue := &campaignQueue{}
+	service, server := newCampaignService(t, queue, testRecipients(250))
+
+	campaign, err := service.Start(context.Background(), "pool-1", CampaignInput{Subject: "Hello", TemplateName: "candidate_outreach"}, "user-1")
+	if err != nil || campaign.RecipientCount != 250 {
+		t.Fatalf("Start() = %+v, %v, want 250 recipients", campaign, err)
+	}
+	if sent := server.waitForSent(t); sent != 250 {
+		t.Fatalf("sent count = %d, want 250", sent)
+	}
+
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	if len(queue.jobs) != 250 {
+		t.Fatalf("queued %d emails, want 250", len(queue.jobs))
+	}
+	// Batches of 100 are queued one interval apart
+	for _, i := range []int{100, 200} {
+		if gap := queue.times[i].Sub(queue.times[i-1]); gap < service.batchInterval {
+			t.Fatalf("email %d queued %v after the previous batch, want at least %v", i, gap, service.batchInterval)
+		}
+	}
+}
+
+func TestCampaignService_Start_Shutdown(t *testing.T) {
+	// The queue shuts down after the first batch
+	queue := &campaignQueue{closeAfter: campaignBatchSize}
+	service, server := newCampaignService(t, queue, testRecipients(150))
+
+	if _, err := service.Start(context.Background(), "pool-1", CampaignInput{Subject: "Hello", TemplateName: "candidate_outreach"}, "user-1"); err != nil {
+		t.Fatal(err)
+	}
+	if sent := server.waitForSent(t); sent != campaignBatchSize {
+		t.Fatalf("sent count = %d, want the first batch", sent)
+	}
+	queue.mu.Lock()
+	defer queue.mu.Unlock()
+	if len(queue.jobs) != campaignBatchSize {
+		t.Fatalf("queued %d emails, want the first batch", len(queue.jobs))
+	}
+}
+
+func TestCampaignService_Start_Rejected(t *testing.T) {
+	outreach := CampaignInput{Subject: "Hello", TemplateName: "candidate_outreach"}
+	optedOut := testRecipients(3)
+	for i := range optedOut {
+		optedOut[i].EmailOptOut = true
+	}
+	// 501 candidates, but one repeats an address, so 500 can be emailed
+	atLimit := append(testRecipients(MaxCampaignRecipients), CampaignRecipient{ID: "cand-501", Email: "candidate-1@example.com"})
+
+	tests := []struct {
+		name       string
+		pool       string
+		input      CampaignInput
+		candidates []CampaignRecipient
+		wantErr    error
+	}{
+		{name: "no subject", pool: "pool-1", input: CampaignInput{Subject: " ", TemplateName: "candidate_outreach"}, candidates: testRecipients(1), wantErr: ErrInvalidCampaign},
+		{name: "unknown template", pool: "pool-1", input: CampaignInput{Subject: "Hello", TemplateName: "missing"}, candidates: testRecipients(1), wantErr: ErrInvalidCampaign},
+		{name: "unknown pool", pool: "pool-9", input: outreach, candidates: testRecipients(1), wantErr: ErrCandidatePoolNotFound},
+		{name: "unknown job", pool: "pool-1", input: CampaignInput{Subject: "Hello", TemplateName: "candidate_outreach", JobID: "job-9"}, candidates: testRecipients(1), wantErr: ErrJobNotFound},
+		{name: "everyone opted out", pool: "pool-1", input: outreach, candidates: optedOut, wantErr: ErrNoCampaignRecipients},
+		{name: "empty pool", pool: "pool-1", input: outreach, wantErr: ErrNoCampaignRecipients},
+		{name: "too many recipients", pool: "pool-1", input: outreach, candidates: testRecipients(MaxCampaignRecipients + 1), wantErr: ErrCampaignTooLarge},
+		{name: "at the limit after deduplication", pool: "pool-1", input: outreach, candidates: atLimit},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			queue := &campaignQueue{}
+			service, server := newCampaignService(t, queue, tt.candidates)
+
+			campaign, err := service.Start(context.Background(), tt.pool, tt.input, "user-1")
+			if tt.wantErr == nil {
+				if err != nil || campaign.RecipientCount != MaxCampaignRecipients {
+					t.Fatalf("Start() = %+v, %v, want %d recipients", campaign, err, MaxCampaignRecipients)
+				}
+				server.waitForSent(t)
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Start() error = %v, want %v", err, tt.wantErr)
+			}
+			if server.createdCount() != 0 {
+				t.Fatal("a rejected campaign was recorded")
+			}
+			queue.mu.Lock()
+			defer queue.mu.Unlock()
+			if len(queue.jobs) != 0 {
+				t.Fatalf("a rejected campaign queued %d emails", len(queue.jobs))
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// Timeline event types that describe a candidate's journey
+const (
+	TimelineApplied            = "APPLIED"
+	TimelineStatusChange       = "STATUS_CHANGE"
+	TimelineNoteAdded          = "NOTE_ADDED"
+	TimelineInterviewScheduled = "INTERVIEW_SCHEDULED"
+)
+
+// Candidate experience targets. Each score component is full at its target
+// and falls linearly to nothing at its limit.
+const (
+	responseTarget = 3 * 24 * time.Hour
+	responseLimit  = 14 * 24 * time.Hour
+	pipelineTarget = 30 * 24 * time.Hour
+	pipelineLimit  = 90 * 24 * time.Hour
+
+	responsePoints = 50
+	notesPoints    = 25
+	pipelinePoints = 25
+)
+
+// Candidate experience fetch limits
+const (
+	// maxJourneyEvents caps the timeline events fetched per application
+	maxJourneyEvents = 500
+	// journeyPageSize is how many applications Summary fetches per request
+	journeyPageSize = 100
+	// maxJourneys caps the applications one Summary covers
+	maxJourneys = 2000
+)
+
+// finalStatuses end an application's time in the pipeline
+var finalStatuses = map[string]bool{"HIRED": true, "REJECTED": true, "WITHDRAWN": true}
+
+// TimelineEvent is one step of an application's journey. Status and Note
+// are set on status changes.
+type TimelineEvent struct {
+	Type      string    `json:"type"`
+	Status    string    `json:"status,omitempty"`
+	Note      string    `json:"note,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// CandidateExperienceScore rates from 0 to 100 how well an application's
+// candidate was treated, with the measurements behind the score
+type CandidateExperienceScore struct {
+	Score   int                    `json:"score"`
+	Details map[string]interface{} `json:"details"`
+}
+
+// CandidateExperienceSummary aggregates candidate experience over many
+// applications
+type CandidateExperienceSummary struct {
+	Applications int `json:"applications"`
+	// Responded counts the applications that have had a first action
+	Responded                     int     `json:"responded"`
+	TimeToFirstActionP50Hours     float64 `json:"timeToFirstActionP50Hours"`
+	TimeToFirstActionP95Hours     float64 `json:"timeToFirstActionP95Hours"`
+	StatusChangesWithNotesPercent float64 `json:"statusChangesWithNotesPercent"`
+	AveragePipelineDays           float64 `json:"averagePipelineDays"`
+	AverageScore                  float64 `json:"averageScore"`
+}
+
+// journey is what a timeline says about how a candidate was treated
+type journey struct {
+	// started is false for an empty timeline, which measures nothing
+	started bool
+	applied time.Time
+	// firstAction is how long the first recruiter action took; responded
+	// is false while there has been none
+	firstAction      time.Duration
+	responded        bool
+	statusChanges    int
+	changesWithNotes int
+	inPipeline       time.Duration
+	completed        bool
+}
+
+// actionEvents are the timeline events that show a recruiter acting on an
+// application
+var actionEvents = map[string]bool{
+	TimelineStatusChange:       true,
+	TimelineNoteAdded:          true,
+	TimelineInterviewScheduled: true,
+}
+
+// measureJourney reads a timeline in time order. The journey starts at the
+// APPLIED event, or the earliest event without one, and an open
+// application's time in the pipeline runs until now.
+func measureJourney(timeline []TimelineEvent, now time.Time) journey {
+	events := append([]TimelineEvent(nil), timeline...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	var j journey
+	if len(events) == 0 {
+		return j
+	}
+	j.started = true
+	j.applied = events[0].Timestamp
+	for _, event := range events {
+		if event.Type == TimelineApplied {
+			j.applied = event.Timestamp
+			break
+		}
+	}
+
+	end := now
+	for _, event := range events {
+		if event.Timestamp.Before(j.applied) {
+			continue
+		}
+		if event.Type == TimelineStatusChange {
+			j.statusChanges++
+			if event.Note != "" {
+				j.changesWithNotes++
+			}
+			if finalStatuses[event.Status] && !j.completed {
+				j.completed = true
+				end = event.Timestamp
+			}
+		}
+		if actionEvents[event.Type] && !j.responded {
+			j.responded = true
+			j.firstAction = event.Timestamp.Sub(j.applied)
+		}
+	}
+
+	j.inPipeline = max(end.Sub(j.applied), 0)
+	return j
+}
+
+// ComputeExperienceScore scores an application's timeline out of 100: half
+// for how soon a recruiter first acted on it (full within 3 days), a quarter
+// for the share of status changes explained with a note and a quarter for
+// the time spent in the pipeline (full within 30 days)
+func ComputeExperienceScore(timeline []TimelineEvent) CandidateExperienceScore {
+	return experienceScore(measureJourney(timeline, time.Now()))
+}
+
+// experienceScore scores a measured journey
+func experienceScore(j journey) CandidateExperienceScore {
+	if !j.started {
+		return CandidateExperienceScore{Details: map[string]interface{}{}}
+	}
+
+	// An application still waiting is scored on how long it has waited
+	wait := j.firstAction
+	if !j.responded {
+		wait = j.inPipeline
+	}
+	response := linearPoints(wait, responseTarget, responseLimit, responsePoints)
+
+	notes := float64(notesPoints)
+	if j.statusChanges > 0 {
+		notes = notesPoints * float64(j.changesWithNotes) / float64(j.statusChanges)
+	}
+
+	pipeline := linearPoints(j.inPipeline, pipelineTarget, pipelineLimit, pipelinePoints)
+
+	details := map[string]interface{}{
+		"appliedAt":                 j.applied,
+		"responded":                 j.responded,
+		"timeToFirstActionHours":    nil,
+		"respondedWithinTarget":     j.responded && j.firstAction <= responseTarget,
+		"statusChanges":             j.statusChanges,
+		"statusChangesWithNotes":    j.changesWithNotes,
+		"statusChangesWithoutNotes": j.statusChanges - j.changesWithNotes,
+		"pipelineDays":              roundTo(j.inPipeline.Hours()/24, 1),
+		"completed":                 j.completed,
+		"responsePoints":            roundTo(response, 1),
+		"notesPoints":               roundTo(notes, 1),
+		"pipelinePoints":            roundTo(pipeline, 1),
+	}
+	if j.responded {
+		details["timeToFirstActionHours"] = roundTo(j.firstAction.Hours(), 1)
+	}
+
+	return CandidateExperienceScore{
+		Score:   int(math.Round(response + notes + pipeline)),
+		Details: details,
+	}
+}
+
+// SummarizeCandidateExperience aggregates the journeys of many timelines
+func SummarizeCandidateExperience(timelines [][]TimelineEvent) CandidateExperienceSummary {
+	now := time.Now()
+	var summary CandidateExperienceSummary
+	var firstActions []float64
+	var statusChanges, withNotes int
+	var pipelineDays, scores float64
+
+	for _, timeline := range timelines {
+		j := measureJourney(timeline, now)
+		if !j.started {
+			continue
+		}
+		summary.Applications++
+		if j.responded {
+			summary.Responded++
+			firstActions = append(firstActions, j.firstAction.Hours())
+		}
+		statusChanges += j.statusChanges
+		withNotes += j.changesWithNotes
+		pipelineDays += j.inPipeline.Hours() / 24
+		scores += float64(experienceScore(j).Score)
+	}
+
+	if summary.Applications == 0 {
+		return summary
+	}
+	sort.Float64s(firstActions)
+	summary.TimeToFirstActionP50Hours = roundTo(nearestRank(firstActions, 50), 1)
+	summary.TimeToFirstActionP95Hours = roundTo(nearestRank(firstActions, 95), 1)
+	if statusChanges > 0 {
+		summary.StatusChangesWithNotesPercent = roundTo(100*float64(withNotes)/float64(statusChanges), 1)
+	}
+	summary.AveragePipelineDays = roundTo(pipelineDays/float64(summary.Applications), 1)
+	summary.AverageScore = roundTo(scores/float64(summary.Applications), 1)
+	return summary
+}
+
+// CandidateExperienceService measures candidate experience from application
+// timelines in Hub-HRMS
+type CandidateExperienceService struct {
+	client *gateway.HubHRMSClient
+}
+
+// NewCandidateExperienceService creates a new candidate experience service
+func NewCandidateExperienceService(client *gateway.HubHRMSClient) *CandidateExperienceService {
+	return &CandidateExperienceService{client: client}
+}
+
+// applicationJourney is an application as GetApplicationJourneyQuery and
+// GetApplicationJourneysQuery return it
+type applicationJourney struct {
+	ID          string          `json:"id"`
+	AppliedDate *time.Time      `json:"appliedDate"`
+	Timeline    []TimelineEvent `json:"timeline"`
+}
+
+// events is the application's timeline, starting with an APPLIED event at
+// its applied date when the timeline has none
+func (a applicationJourney) events() []TimelineEvent {
+	if a.AppliedDate == nil {
+		return a.Timeline
+	}
+	for _, event := range a.Timeline {
+		if event.Type == TimelineApplied {
+			return a.Timeline
+		}
+	}
+	return append([]TimelineEvent{{Type: TimelineApplied, Timestamp: *a.AppliedDate}}, a.Timeline...)
+}
+
+// Score computes the candidate experience score of one application
+func (s *CandidateExperienceService) Score(ctx context.Context, applicationID string) (*CandidateExperienceScore, error) {
+	resp, err := s.client.Query(ctx, gateway.GetApplicationJourneyQuery, map[string]interface{}{
+		"id":            applicationID,
+		"timelineLimit": maxJourneyEvents,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch application timeline: %w", err)
+	}
+
+	var application applicationJourney
+	found, err := decodeField(resp.Data, "application", &application)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrApplicationNotFound
+	}
+
+	score := ComputeExperienceScore(application.events())
+	return &score, nil
+}
+
+// Summary aggregates the candidate experience of applications made between
+// start and end, optionally for one job. At most maxJourneys applications
+// are covered.
+func (s *CandidateExperienceService) Summary(ctx context.Context, start, end time.Time, jobID string) (*CandidateExperienceSummary, error) {
+	filters := map[string]interface{}{
+		"dateFrom": start.Format("2006-01-02"),
+		"dateTo":   end.Format("2006-01-02"),
+	}
+	if jobID != "" {
+		filters["jobId"] = jobID
+	}
+
+	var timelines [][]TimelineEvent
+	for offset := 0; offset < maxJourneys; offset += journeyPageSize {
+		resp, err := s.client.Query(ctx, gateway.GetApplicationJourneysQuery, map[string]interface{}{
+			"filters":       filters,
+			"limit":         journeyPageSize,
+			"offset":        offset,
+			"timelineLimit": maxJourneyEvents,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch application timelines: %w", err)
+		}
+
+		var page []applicationJourney
+		if _, err := decodeField(resp.Data, "applications", &page); err != nil {
+			return nil, err
+		}
+		for _, application := range page {
+			timelines = append(timelines, application.events())
+		}
+		if len(page) < journeyPageSize {
+			break
+		}
+	}
+
+	summary := SummarizeCandidateExperience(timelines)
+	return &summary, nil
+}
+
+// linearPoints awards full points up to target, none from limit on and a
+// linear share in between
+func linearPoints(d, target, limit time.Duration, points float64) float64 {
+	switch {
+	case d <= target:
+		return points
+	case d >= limit:
+		return 0
+	}
+	return points * float64(limit-d) / float64(limit-target)
+}
+
+// nearestRank returns the nearest-rank percentile of sorted values, or 0
+// when there are none
+func nearestRank(sorted []float64, percentile float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(percentile / 100 * float64(len(sorted))))
+	return sorted[max(rank, 1)-1]
+}
+
+// roundTo rounds value to the given number of decimal places
+func roundTo(value float64, places int) float64 {
+	scale := math.Pow(10, float64(places))
+	return math.Round(value*scale) / scale
+}
@@ -0,0 +1,322 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// journeyStart is when the synthetic applications below were made
+var journeyStart = time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+
+// at is the time days after journeyStart
+func at(days float64) time.Time {
+	return journeyStart.Add(time.Duration(days * 24 * float64(time.Hour)))
+}
+
+// fastJourney is acted on within a day and hired in three weeks, every
+// status change explained
+func fastJourney() []TimelineEvent {
+	return []TimelineEvent{
+		{Type: TimelineApplied, Timestamp: at(0)},
+		{Type: TimelineStatusChange, Status: "SCREENING", Note: "Strong Go background", Timestamp: at(0.5)},
+		{Type: TimelineInterviewScheduled, Timestamp: at(2)},
+		{Type: TimelineStatusChange, Status: "INTERVIEW", Note: "Booked with the team", Timestamp: at(3)},
+		{Type: TimelineStatusChange, Status: "HIRED", Note: "Accepted the offer", Timestamp: at(21)},
+	}
+}
+
+// slowJourney waits eight and a half days for a first action and two months
+// to be rejected, with one of its two status changes explained
+func slowJourney() []TimelineEvent {
+	return []TimelineEvent{
+		{Type: TimelineApplied, Timestamp: at(0)},
+		{Type: TimelineStatusChange, Status: "SCREENING", Timestamp: at(8.5)},
+		{Type: TimelineStatusChange, Status: "REJECTED", Note: "Role filled", Timestamp: at(60)},
+	}
+}
+
+func TestComputeExperienceScore(t *testing.T) {
+	tests := []struct {
+		name        string
+		timeline    []TimelineEvent
+		wantScore   int
+		wantDetails map[string]interface{}
+	}{
+		{
+			name:      "fast response",
+			timeline:  fastJourney(),
+			wantScore: 100,
+			wantDetails: map[string]interface{}{
+				"responded": true, "timeToFirstActionHours": 12.0, "respondedWithinTarget": true,
+				"statusChanges": 3, "statusChangesWithNotes": 3, "statusChangesWithoutNotes": 0,
+				"pipelineDays": 21.0, "completed": true,
+				"responsePoints": 50.0, "notesPoints": 25.0, "pipelinePoints": 25.0,
+			},
+		},
+		{
+			// Half the response points at 8.5 of 3 to 14 days, half the notes
+			// and half the pipeline points at 60 of 30 to 90 days
+			name:      "slow response",
+			timeline:  slowJourney(),
+			wantScore: 50,
+			wantDetails: map[string]interface{}{
+				"responded": true, "timeToFirstActionHours": 204.0, "respondedWithinTarget": false,
+				"statusChanges": 2, "statusChangesWithNotes": 1, "statusChangesWithoutNotes": 1,
+				"pipelineDays": 60.0, "completed": true,
+				"responsePoints": 25.0, "notesPoints": 12.5, "pipelinePoints": 12.5,
+			},
+		},
+		{
+			name: "very slow response",
+			timeline: []TimelineEvent{
+				{Type: TimelineApplied, Timestamp: at(0)},
+				{Type: TimelineStatusChange, Status: "REJECTED", Timestamp: at(100)},
+			},
+			wantScore: 0,
+			wantDetails: map[string]interface{}{
+				"responded": true, "timeToFirstActionHours": 2400.0, "respondedWithinTarget": false,
+				"statusChangesWithoutNotes": 1, "pipelineDays": 100.0,
+				"responsePoints": 0.0, "notesPoints": 0.0, "pipelinePoints": 0.0,
+			},
+		},
+		{
+			// A note counts as a first action; only status changes are
+			// expected to carry notes
+			name: "first action a note",
+			timeline: []TimelineEvent{
+				{Type: TimelineApplied, Timestamp: at(0)},
+				{Type: TimelineNoteAdded, Timestamp: at(1)},
+				{Type: TimelineStatusChange, Status: "WITHDRAWN", Timestamp: at(10)},
+			},
+			wantDetails: map[string]interface{}{
+				"timeToFirstActionHours": 24.0, "respondedWithinTarget": true, "statusChanges": 1,
+				"statusChangesWithNotes": 0, "notesPoints": 0.0, "pipelineDays": 10.0,
+			},
+			wantScore: 75,
+		},
+		{
+			// Events are read in time order, whatever order they come in
+			name: "out of order",
+			timeline: []TimelineEvent{
+				{Type: TimelineStatusChange, Status: "HIRED", Note: "Accepted", Timestamp: at(20)},
+				{Type: TimelineStatusChange, Status: "SCREENING", Note: "Looks good", Timestamp: at(1)},
+				{Type: TimelineApplied, Timestamp: at(0)},
+			},
+			wantScore: 100,
+			wantDetails: map[string]interface{}{
+				"timeToFirstActionHours": 24.0, "pipelineDays": 20.0, "completed": true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := ComputeExperienceScore(tt.timeline)
+			if score.Score != tt.wantScore {
+				t.Errorf("Score = %d, want %d (details %v)", score.Score, tt.wantScore, score.Details)
+			}
+			for key, want := range tt.wantDetails {
+				if got := score.Details[key]; got != want {
+					t.Errorf("Details[%q] = %v (%T), want %v", key, got, got, want)
+				}
+			}
+			if !score.Details["appliedAt"].(time.Time).Equal(journeyStart) {
+				t.Errorf("appliedAt = %v, want %v", score.Details["appliedAt"], journeyStart)
+			}
+		})
+	}
+
+	t.Run("empty timeline", func(t *testing.T) {
+		score := ComputeExperienceScore(nil)
+		if score.Score != 0 || score.Details == nil || len(score.Details) != 0 {
+			t.Fatalf("ComputeExperienceScore(nil) = %+v, want no score", score)
+		}
+	})
+}
+
+func TestExperienceScore_Open(t *testing.T) {
+	tests := []struct {
+		name         string
+		timeline     []TimelineEvent
+		now          time.Time
+		wantScore    int
+		wantResponse float64
+	}{
+		// Waiting two days is within target, and nothing has needed a note
+		{name: "waiting briefly", timeline: []TimelineEvent{{Type: TimelineApplied, Timestamp: at(0)}}, now: at(2), wantScore: 100, wantResponse: 50},
+		// Still waiting after 20 days loses every response point
+		{name: "waiting long", timeline: []TimelineEvent{{Type: TimelineApplied, Timestamp: at(0)}}, now: at(20), wantScore: 50, wantResponse: 0},
+		// Answered fast but still open after 60 days
+		{
+			name: "open in the pipeline",
+			timeline: []TimelineEvent{
+				{Type: TimelineApplied, Timestamp: at(0)},
+				{Type: TimelineStatusChange, Status: "INTERVIEW", Note: "Booked", Timestamp: at(1)},
+			},
+			now: at(60), wantScore: 88, wantResponse: 50,
+		},
+		// Events before the application are ignored
+		{
+			name: "events before applying",
+			timeline: []TimelineEvent{
+				{Type: TimelineNoteAdded, Timestamp: at(-3)},
+				{Type: TimelineApplied, Timestamp: at(0)},
+			},
+			now: at(20), wantScore: 50, wantResponse: 0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score := experienceScore(measureJourney(tt.timeline, tt.now))
+			if score.Score != tt.wantScore {
+				t.Errorf("Score = %d, want %d (details %v)", score.Score, tt.wantScore, score.Details)
+			}
+			if score.Details["responsePoints"] != tt.wantResponse {
+				t.Errorf("responsePoints = %v, want %v", score.Details["responsePoints"], tt.wantResponse)
+			}
+			if score.Details["completed"] != false {
+				t.Error("an open application was counted as completed")
+			}
+		})
+	}
+
+	t.Run("no APPLIED event", func(t *testing.T) {
+		j := measureJourney([]TimelineEvent{
+			{Type: TimelineStatusChange, Status: "SCREENING", Timestamp: at(4)},
+			{Type: TimelineNoteAdded, Timestamp: at(1)},
+		}, at(10))
+		if !j.applied.Equal(at(1)) || j.firstAction != 0 || j.inPipeline != 9*24*time.Hour {
+			t.Fatalf("journey = %+v, want it to start at the earliest event", j)
+		}
+	})
+}
+
+func TestSummarizeCandidateExperience(t *testing.T) {
+	quick := func(hours float64) []TimelineEvent {
+		return []TimelineEvent{
+			{Type: TimelineApplied, Timestamp: at(0)},
+			{Type: TimelineStatusChange, Status: "REJECTED", Note: "Not a fit", Timestamp: at(hours / 24)},
+		}
+	}
+	// First actions after 12, 204, 2 and 48 hours; the empty timeline has
+	// nothing to measure
+	timelines := [][]TimelineEvent{fastJourney(), slowJourney(), quick(2), quick(48), nil}
+
+	summary := SummarizeCandidateExperience(timelines)
+	if summary.Applications != 4 || summary.Responded != 4 {
+		t.Fatalf("summary = %+v, want four measured applications, all responded to", summary)
+	}
+	// Of 2, 12, 48 and 204 hours the median by nearest rank is the second
+	// and the 95th percentile the fourth
+	if summary.TimeToFirstActionP50Hours != 12 || summary.TimeToFirstActionP95Hours != 204 {
+		t.Fatalf("time to first action p50 = %v, p95 = %v, want 12 and 204",
+			summary.TimeToFirstActionP50Hours, summary.TimeToFirstActionP95Hours)
+	}
+	// Six of seven status changes have notes
+	if summary.StatusChangesWithNotesPercent != 85.7 {
+		t.Fatalf("status changes with notes = %v%%, want 85.7%%", summary.StatusChangesWithNotesPercent)
+	}
+	wantDays := roundTo((21+60+2.0/24+2)/4, 1)
+	if summary.AveragePipelineDays != wantDays {
+		t.Fatalf("average pipeline days = %v, want %v", summary.AveragePipelineDays, wantDays)
+	}
+	if summary.AverageScore != (100+50+100+100)/4.0 {
+		t.Fatalf("average score = %v, want 87.5", summary.AverageScore)
+	}
+
+	if empty := SummarizeCandidateExperience(nil); empty != (CandidateExperienceSummary{}) {
+		t.Fatalf("SummarizeCandidateExperience(nil) = %+v, want zeroes", empty)
+	}
+}
+
+func TestNearestRank(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	for percentile, want := range map[float64]float64{0: 1, 10: 1, 50: 5, 95: 10, 100: 10} {
+		if got := nearestRank(values, percentile); got != want {
+			t.Errorf("nearestRank(%v) = %v, want %v", percentile, got, want)
+		}
+	}
+	if got := nearestRank(nil, 50); got != 0 {
+		t.Errorf("nearestRank(nil) = %v, want 0", got)
+	}
+}
+
+// journeyRecord is an application as Hub-HRMS returns its journey
+func journeyRecord(id string, appliedDate *time.Time, timeline []TimelineEvent) map[string]interface{} {
+	return map[string]interface{}{"id": id, "appliedDate": appliedDate, "timeline": timeline}
+}
+
+func TestCandidateExperienceService_Score(t *testing.T) {
+	applied := at(0)
+	service := NewCandidateExperienceService(newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Variables["timelineLimit"] != float64(maxJourneyEvents) {
+			t.Errorf("timelineLimit = %v, want %d", req.Variables["timelineLimit"], maxJourneyEvents)
+		}
+		switch req.Variables["id"] {
+		case "app-1":
+			// The timeline leaves out the APPLIED event the applied date stands for
+			return map[string]interface{}{"application": journeyRecord("app-1", &applied, slowJourney()[1:])}
+		case "app-2":
+			return map[string]interface{}{"application": journeyRecord("app-2", nil, fastJourney())}
+		}
+		return map[string]interface{}{"application": nil}
+	}))
+	ctx := context.Background()
+
+	score, err := service.Score(ctx, "app-1")
+	if err != nil {
+		t.Fatalf("Score() error = %v", err)
+	}
+	if score.Score != 50 || score.Details["timeToFirstActionHours"] != 204.0 {
+		t.Fatalf("Score() = %+v, want the slow journey measured from the applied date", score)
+	}
+	if score, err := service.Score(ctx, "app-2"); err != nil || score.Score != 100 {
+		t.Fatalf("Score(app-2) = %+v, %v, want the fast journey", score, err)
+	}
+	if _, err := service.Score(ctx, "app-9"); !errors.Is(err, ErrApplicationNotFound) {
+		t.Fatalf("Score(missing) error = %v, want ErrApplicationNotFound", err)
+	}
+}
+
+func TestCandidateExperienceService_Summary(t *testing.T) {
+	// A page and a half of slow journeys and one fast one
+	var applications []interface{}
+	for i := 0; i < journeyPageSize+49; i++ {
+		applications = append(applications, journeyRecord("app", nil, slowJourney()))
+	}
+	applications = append(applications, journeyRecord("app-fast", nil, fastJourney()))
+
+	var mu sync.Mutex
+	var filters []interface{}
+	service := NewCandidateExperienceService(newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+		mu.Lock()
+		filters = append(filters, req.Variables["filters"])
+		mu.Unlock()
+		offset := int(req.Variables["offset"].(float64))
+		limit := int(req.Variables["limit"].(float64))
+		return map[string]interface{}{"applications": applications[min(offset, len(applications)):min(offset+limit, len(applications))]}
+	}))
+
+	summary, err := service.Summary(context.Background(), at(-30), at(0), "job-1")
+	if err != nil {
+		t.Fatalf("Summary() error = %v", err)
+	}
+	if summary.Applications != len(applications) {
+		t.Fatalf("applications = %d, want every page counted", summary.Applications)
+	}
+	if math.Abs(summary.AverageScore-(50*149+100)/150.0) > 0.05 {
+		t.Fatalf("average score = %v", summary.AverageScore)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	first, _ := filters[0].(map[string]interface{})
+	if len(filters) != 2 || first["jobId"] != "job-1" || first["dateFrom"] != "2026-01-31" || first["dateTo"] != "2026-03-02" {
+		t.Fatalf("filters = %v, want two pages for job-1 over the range", filters)
+	}
+}
@@ -0,0 +1,238 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// DeduplicationStore remembers recently seen keys for a limited time
+type DeduplicationStore interface {
+	// Get returns the value stored for key, if it has not expired
+	Get(ctx context.Context, key string) (string, bool, error)
+	// Set stores value for key until ttl elapses
+	Set(ctx context.Context, key, value string, ttl time.Duration) error
+	// Delete forgets key
+	Delete(ctx context.Context, key string) error
+	// Close releases the store's resources
+	Close() error
+}
+
+// ApplicationDedupKey identifies a candidate's application to a job
+func ApplicationDedupKey(jobID, email string) string {
+	sum := sha256.Sum256([]byte(jobID + NormalizeEmail(email)))
+	return "dedup:application:" + hex.EncodeToString(sum[:])
+}
+
+// NormalizeEmail returns the canonical form of an email address used to
+// match candidates, so differences in case do not create duplicates
+func NormalizeEmail(email string) string {
+	return strings.ToLower(strings.TrimSpace(email))
+}
+
+// CandidateMatch is an existing candidate found for an applicant
+type CandidateMatch struct {
+	CandidateID string
+	// ExistingApplicationID is set when the candidate already applied to the job
+	ExistingApplicationID string
+}
+
+// CandidateDuplicateChecker finds existing candidate records for an
+// applicant so a repeat applicant is not created twice
+type CandidateDuplicateChecker struct {
+	client *gateway.HubHRMSClient
+}
+
+// NewCandidateDuplicateChecker creates a checker that searches Hub-HRMS
+func NewCandidateDuplicateChecker(client *gateway.HubHRMSClient) *CandidateDuplicateChecker {
+	return &CandidateDuplicateChecker{client: client}
+}
+
+// Check returns the candidate registered under email, and their application
+// to jobID if there is one. It returns nil when no candidate matches.
+func (c *CandidateDuplicateChecker) Check(ctx context.Context, email, jobID string) (*CandidateMatch, error) {
+	normalized := NormalizeEmail(email)
+	if normalized == "" {
+		return nil, nil
+	}
+
+	resp, err := c.client.Query(ctx, gateway.SearchCandidatesByEmailQuery, map[string]interface{}{
+		"email": normalized,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search candidates: %w", err)
+	}
+
+	data, _ := resp.Data.(map[string]interface{})
+	candidates, _ := data["searchCandidates"].([]interface{})
+	for _, item := range candidates {
+		candidate, _ := item.(map[string]interface{})
+		candidateID, _ := candidate["id"].(string)
+		candidateEmail, _ := candidate["email"].(string)
+		if candidateID == "" || NormalizeEmail(candidateEmail) != normalized {
+			continue
+		}
+
+		match := &CandidateMatch{CandidateID: candidateID}
+		applications, _ := candidate["applications"].([]interface{})
+		for _, app := range applications {
+			application, _ := app.(map[string]interface{})
+			job, _ := application["job"].(map[string]interface{})
+			if job["id"] == jobID {
+				match.ExistingApplicationID, _ = application["id"].(string)
+				break
+			}
+		}
+		return match, nil
+	}
+
+	return nil, nil
+}
+
+// NewDeduplicationStore returns a Redis-backed store when redisURL is set and
+// an in-memory store otherwise
+func NewDeduplicationStore(redisURL string) (DeduplicationStore, error) {
+	if redisURL == "" {
+		return NewMemoryDeduplicationStore(), nil
+	}
+	return NewRedisDeduplicationStore(redisURL)
+}
+
+// MemoryDeduplicationStore is a DeduplicationStore for a single instance
+type MemoryDeduplicationStore struct {
+	mu      sync.Mutex
+	entries map[string]memoryEntry
+
+	stop     chan struct{}
+	stopOnce sync.Once
+}
+
+type memoryEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+// memoryEvictInterval is how often expired entries are dropped from memory
+const memoryEvictInterval = time.Minute
+
+// NewMemoryDeduplicationStore creates a new in-memory store. Close stops its
+// background eviction.
+func NewMemoryDeduplicationStore() *MemoryDeduplicationStore {
+	return newMemoryDeduplicationStore(memoryEvictInterval)
+}
+
+func newMemoryDeduplicationStore(evictInterval time.Duration) *MemoryDeduplicationStore {
+	store := &MemoryDeduplicationStore{
+		entries: make(map[string]memoryEntry),
+		stop:    make(chan struct{}),
+	}
+	go store.evictExpired(evictInterval)
+	return store
+}
+
+// Get implements DeduplicationStore
+func (s *MemoryDeduplicationStore) Get(ctx context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, ok := s.entries[key]
+	if !ok || time.Now().After(entry.expiresAt) {
+		return "", false, nil
+	}
+	return entry.value, true, nil
+}
+
+// Set implements DeduplicationStore
+func (s *MemoryDeduplicationStore) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.entries[key] = memoryEntry{value: value, expiresAt: time.Now().Add(ttl)}
+	return nil
+}
+
+// Delete implements DeduplicationStore
+func (s *MemoryDeduplicationStore) Delete(ctx context.Context, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.entries, key)
+	return nil
+}
+
+// Close implements DeduplicationStore. It may be called more than once.
+func (s *MemoryDeduplicationStore) Close() error {
+	s.stopOnce.Do(func() { close(s.stop) })
+	return nil
+}
+
+func (s *MemoryDeduplicationStore) evictExpired(interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case now := <-ticker.C:
+			s.mu.Lock()
+			for key, entry := range s.entries {
+				if now.After(entry.expiresAt) {
+					delete(s.entries, key)
+				}
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// RedisDeduplicationStore is a DeduplicationStore shared between instances
+type RedisDeduplicationStore struct {
+	client *redis.Client
+}
+
+// NewRedisDeduplicationStore creates a store from a redis:// or rediss:// URL,
+// e.g. redis://:password@host:6379/0
+func NewRedisDeduplicationStore(redisURL string) (*RedisDeduplicationStore, error) {
+	options, err := redis.ParseURL(redisURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Redis URL: %w", err)
+	}
+	return &RedisDeduplicationStore{client: redis.NewClient(options)}, nil
+}
+
+// Get implements DeduplicationStore
+func (s *RedisDeduplicationStore) Get(ctx context.Context, key string) (string, bool, error) {
+	value, err := s.client.Get(ctx, key).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	return value, true, nil
+}
+
+// Set implements DeduplicationStore
+func (s *RedisDeduplicationStore) Set(ctx context.Context, key, value string, ttl time.Duration) error {
+	return s.client.Set(ctx, key, value, ttl).Err()
+}
+
+// Delete implements DeduplicationStore
+func (s *RedisDeduplicationStore) Delete(ctx context.Context, key string) error {
+	return s.client.Del(ctx, key).Err()
+}
+
+// Close implements DeduplicationStore, closing the connection pool
+func (s *RedisDeduplicationStore) Close() error {
+	return s.client.Close()
+}
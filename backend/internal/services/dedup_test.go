@@ -0,0 +1,237 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// testDeduplicationStore runs the behaviour every DeduplicationStore shares.
+// expire makes entries set with a TTL of ttl expire.
+func testDeduplicationStore(t *testing.T, store DeduplicationStore, ttl time.Duration, expire func()) {
+	t.Helper()
+	ctx := context.Background()
+	key := ApplicationDedupKey("job-1", "ada@example.com")
+
+	if _, found, err := store.Get(ctx, key); err != nil || found {
+		t.Fatalf("Get(unset) = found %v, error %v", found, err)
+	}
+	if err := store.Set(ctx, key, "app-1", ttl); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if value, found, err := store.Get(ctx, key); err != nil || !found || value != "app-1" {
+		t.Fatalf("Get() = %q, %v, %v, want app-1", value, found, err)
+	}
+
+	expire()
+	if _, found, err := store.Get(ctx, key); err != nil || found {
+		t.Fatalf("Get(expired) = found %v, error %v", found, err)
+	}
+
+	if err := store.Set(ctx, key, "app-2", time.Hour); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if err := store.Delete(ctx, key); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, found, _ := store.Get(ctx, key); found {
+		t.Fatal("Get(deleted) found the key")
+	}
+}
+
+func TestApplicationDedupKey(t *testing.T) {
+	key := ApplicationDedupKey("job-1", "Ada@Example.com ")
+	if key != ApplicationDedupKey("job-1", "ada@example.com") {
+		t.Fatal("keys differ by email case and whitespace")
+	}
+	if key == ApplicationDedupKey("job-2", "ada@example.com") {
+		t.Fatal("applications to different jobs share a key")
+	}
+}
+
+func TestMemoryDeduplicationStore(t *testing.T) {
+	store := NewMemoryDeduplicationStore()
+	defer store.Close()
+
+	testDeduplicationStore(t, store, 20*time.Millisecond, func() { time.Sleep(30 * time.Millisecond) })
+}
+
+func TestMemoryDeduplicationStore_EvictsExpired(t *testing.T) {
+	store := newMemoryDeduplicationStore(5 * time.Millisecond)
+	defer store.Close()
+
+	ctx := context.Background()
+	store.Set(ctx, "short", "1", time.Millisecond)
+	store.Set(ctx, "long", "2", time.Hour)
+
+	deadline := time.Now().Add(time.Second)
+	for {
+		store.mu.Lock()
+		_, short := store.entries["short"]
+		_, long := store.entries["long"]
+		store.mu.Unlock()
+		if !short && long {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("entries after eviction: short %v, long %v; want only long", short, long)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestMemoryDeduplicationStore_CloseStopsEviction(t *testing.T) {
+	before := runtime.NumGoroutine()
+	stores := make([]*MemoryDeduplicationStore, 10)
+	for i := range stores {
+		stores[i] = newMemoryDeduplicationStore(time.Millisecond)
+	}
+	for _, store := range stores {
+		if err := store.Close(); err != nil {
+			t.Fatalf("Close() error = %v", err)
+		}
+		// Closing again is harmless
+		store.Close()
+	}
+
+	deadline := time.Now().Add(time.Second)
+	for runtime.NumGoroutine() > before {
+		if time.Now().After(deadline) {
+			t.Fatalf("%d goroutines still running after Close, want %d", runtime.NumGoroutine(), before)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestRedisDeduplicationStore(t *testing.T) {
+	server := miniredis.RunT(t)
+	store, err := NewDeduplicationStore("redis://" + server.Addr() + "/0")
+	if err != nil {
+		t.Fatalf("NewDeduplicationStore() error = %v", err)
+	}
+	defer store.Close()
+	if _, ok := store.(*RedisDeduplicationStore); !ok {
+		t.Fatalf("NewDeduplicationStore() = %T, want a Redis store", store)
+	}
+
+	testDeduplicationStore(t, store, time.Hour, func() {
+		if ttl := server.TTL(ApplicationDedupKey("job-1", "ada@example.com")); ttl != time.Hour {
+			t.Fatalf("Redis TTL = %v, want 1h", ttl)
+		}
+		server.FastForward(time.Hour)
+	})
+}
+
+func TestNewDeduplicationStore(t *testing.T) {
+	store, err := NewDeduplicationStore("")
+	if err != nil {
+		t.Fatalf("NewDeduplicationStore() error = %v", err)
+	}
+	defer store.Close()
+	if _, ok := store.(*MemoryDeduplicationStore); !ok {
+		t.Fatalf("NewDeduplicationStore(\"\") = %T, want an in-memory store", store)
+	}
+
+	if _, err := NewDeduplicationStore("http://localhost:6379"); err == nil {
+		t.Fatal("NewDeduplicationStore() accepted a non-Redis URL")
+	}
+}
+
+// candidateSearchServer fakes Hub-HRMS holding candidates, answering
+// SearchCandidatesByEmailQuery case-sensitively like a plain email index.
+// searched receives every email searched for.
+func candidateSearchServer(t *testing.T, searched *[]string, candidates ...map[string]interface{}) *gateway.HubHRMSClient {
+	var mu sync.Mutex
+	return newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.SearchCandidatesByEmailQuery {
+			return map[string]interface{}{}
+		}
+		email, _ := req.Variables["email"].(string)
+		mu.Lock()
+		*searched = append(*searched, email)
+		mu.Unlock()
+
+		found := []interface{}{}
+		for _, candidate := range candidates {
+			if candidate["email"] == email {
+				found = append(found, candidate)
+			}
+		}
+		return map[string]interface{}{"searchCandidates": found}
+	})
+}
+
+func TestCandidateDuplicateChecker_Check(t *testing.T) {
+	ada := map[string]interface{}{
+		"id":    "cand-1",
+		"email": "ada@example.com",
+		"applications": []interface{}{
+			map[string]interface{}{"id": "app-7", "job": map[string]interface{}{"id": "job-1"}},
+		},
+	}
+	// A record stored before emails were normalized only matches when the
+	// search is normalized too
+	grace := map[string]interface{}{"id": "cand-2", "email": "grace@example.com", "applications": []interface{}{}}
+
+	tests := []struct {
+		name  string
+		email string
+		jobID string
+		want  *CandidateMatch
+	}{
+		{name: "new candidate", email: "alan@example.com", jobID: "job-1"},
+		{name: "existing candidate, new job", email: "ada@example.com", jobID: "job-2", want: &CandidateMatch{CandidateID: "cand-1"}},
+		{name: "case-insensitive match", email: "  Grace@Example.COM ", jobID: "job-1", want: &CandidateMatch{CandidateID: "cand-2"}},
+		{name: "already applied", email: "ADA@example.com", jobID: "job-1", want: &CandidateMatch{CandidateID: "cand-1", ExistingApplicationID: "app-7"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var searched []string
+			checker := NewCandidateDuplicateChecker(candidateSearchServer(t, &searched, ada, grace))
+
+			match, err := checker.Check(context.Background(), tt.email, tt.jobID)
+			if err != nil {
+				t.Fatalf("Check() error = %v", err)
+			}
+			if (match == nil) != (tt.want == nil) || (match != nil && *match != *tt.want) {
+				t.Fatalf("Check() = %+v, want %+v", match, tt.want)
+			}
+			if want := NormalizeEmail(tt.email); len(searched) != 1 || searched[0] != want {
+				t.Fatalf("searched for %q, want %q", searched, want)
+			}
+		})
+	}
+
+	t.Run("blank email is not searched", func(t *testing.T) {
+		var searched []string
+		checker := NewCandidateDuplicateChecker(candidateSearchServer(t, &searched, ada))
+		if match, err := checker.Check(context.Background(), "  ", "job-1"); match != nil || err != nil {
+			t.Fatalf("Check(blank) = %+v, %v, want nil", match, err)
+		}
+		if len(searched) != 0 {
+			t.Fatalf("searched for %q", searched)
+		}
+	})
+
+	t.Run("search failure", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "bad request", http.StatusBadRequest)
+		}))
+		t.Cleanup(server.Close)
+		client := gateway.NewHubHRMSClient(server.URL, "")
+		t.Cleanup(client.Close)
+
+		if _, err := NewCandidateDuplicateChecker(client).Check(context.Background(), "ada@example.com", "job-1"); err == nil {
+			t.Fatal("Check() error = nil, want the search failure")
+		}
+	})
+}
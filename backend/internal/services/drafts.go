@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ApplicationDraftTTL is how long an unfinished application is kept
+const ApplicationDraftTTL = 24 * time.Hour
+
+// ErrDraftNotFound is returned for drafts that were never saved, have been
+// discarded or have expired
+var ErrDraftNotFound = errors.New("application draft not found")
+
+// ApplicationDraft is a partially completed application saved by a candidate
+type ApplicationDraft struct {
+	ID        string                 `json:"draftId"`
+	Data      map[string]interface{} `json:"data"`
+	ExpiresAt time.Time              `json:"expiresAt"`
+}
+
+// ApplicationDraftStore keeps application drafts in a DeduplicationStore, so
+// they live in Redis when one is configured and in memory otherwise
+type ApplicationDraftStore struct {
+	store DeduplicationStore
+	ttl   time.Duration
+}
+
+// NewApplicationDraftStore creates a draft store whose drafts expire ttl
+// after they were last saved
+func NewApplicationDraftStore(store DeduplicationStore, ttl time.Duration) *ApplicationDraftStore {
+	return &ApplicationDraftStore{store: store, ttl: ttl}
+}
+
+// Save stores data as a draft. An empty id starts a new draft; otherwise the
+// existing draft is replaced and its expiry pushed back.
+func (s *ApplicationDraftStore) Save(ctx context.Context, id string, data map[string]interface{}) (ApplicationDraft, error) {
+	if id == "" {
+		id = uuid.NewString()
+	} else if _, err := s.Get(ctx, id); err != nil {
+		return ApplicationDraft{}, err
+	}
+
+	draft := ApplicationDraft{ID: id, Data: data, ExpiresAt: time.Now().Add(s.ttl).UTC()}
+	value, err := json.Marshal(draft)
+	if err != nil {
+		return ApplicationDraft{}, err
+	}
+	if err := s.store.Set(ctx, draftKey(id), string(value), s.ttl); err != nil {
+		return ApplicationDraft{}, fmt.Errorf("failed to save application draft: %w", err)
+	}
+	return draft, nil
+}
+
+// Get returns a saved draft
+func (s *ApplicationDraftStore) Get(ctx context.Context, id string) (ApplicationDraft, error) {
+	if _, err := uuid.Parse(id); err != nil {
+		return ApplicationDraft{}, ErrDraftNotFound
+	}
+
+	value, found, err := s.store.Get(ctx, draftKey(id))
+	if err != nil {
+		return ApplicationDraft{}, fmt.Errorf("failed to load application draft: %w", err)
+	}
+	if !found {
+		return ApplicationDraft{}, ErrDraftNotFound
+	}
+
+	var draft ApplicationDraft
+	if err := json.Unmarshal([]byte(value), &draft); err != nil {
+		return ApplicationDraft{}, fmt.Errorf("invalid application draft %s: %w", id, err)
+	}
+	return draft, nil
+}
+
+// Delete discards a draft. Discarding a draft that is already gone is not
+// an error.
+func (s *ApplicationDraftStore) Delete(ctx context.Context, id string) error {
+	if _, err := uuid.Parse(id); err != nil {
+		return nil
+	}
+	if err := s.store.Delete(ctx, draftKey(id)); err != nil {
+		return fmt.Errorf("failed to delete application draft: %w", err)
+	}
+	return nil
+}
+
+// MergeDraft returns the draft's fields overlaid with submitted ones, so
+// values the candidate submits win over those saved earlier
+func MergeDraft(draft, submitted map[string]interface{}) map[string]interface{} {
+	merged := make(map[string]interface{}, len(draft)+len(submitted))
+	for field, value := range draft {
+		merged[field] = value
+	}
+	for field, value := range submitted {
+		merged[field] = value
+	}
+	return merged
+}
+
+func draftKey(id string) string {
+	return "draft:application:" + id
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+)
+
+func TestApplicationDraftStore(t *testing.T) {
+	store := NewMemoryDeduplicationStore()
+	defer store.Close()
+	drafts := NewApplicationDraftStore(store, time.Hour)
+	ctx := context.Background()
+
+	saved, err := drafts.Save(ctx, "", map[string]interface{}{"jobId": "job-1", "firstName": "Ada"})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if saved.ID == "" || time.Until(saved.ExpiresAt) < 59*time.Minute {
+		t.Fatalf("Save() = %+v, want a new draft expiring in an hour", saved)
+	}
+
+	got, err := drafts.Get(ctx, saved.ID)
+	if err != nil || !reflect.DeepEqual(got.Data, saved.Data) || !got.ExpiresAt.Equal(saved.ExpiresAt) {
+		t.Fatalf("Get() = %+v, %v, want %+v", got, err, saved)
+	}
+
+	// Saving again under the same id replaces the draft
+	updated, err := drafts.Save(ctx, saved.ID, map[string]interface{}{"jobId": "job-1", "firstName": "Ada", "lastName": "Lovelace"})
+	if err != nil || updated.ID != saved.ID {
+		t.Fatalf("Save(existing) = %+v, %v, want the same draft", updated, err)
+	}
+	if got, _ := drafts.Get(ctx, saved.ID); got.Data["lastName"] != "Lovelace" {
+		t.Fatalf("Get() = %+v, want the replaced data", got)
+	}
+
+	for name, id := range map[string]string{
+		"unknown draft": "0b9f7c1e-2f4a-4c55-9d6e-0b4b2b7f8a11",
+		"not a uuid":    "../../admin",
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := drafts.Get(ctx, id); !errors.Is(err, ErrDraftNotFound) {
+				t.Fatalf("Get() error = %v, want ErrDraftNotFound", err)
+			}
+			// A draft cannot be created under an id the client chose
+			if _, err := drafts.Save(ctx, id, map[string]interface{}{}); !errors.Is(err, ErrDraftNotFound) {
+				t.Fatalf("Save() error = %v, want ErrDraftNotFound", err)
+			}
+			if err := drafts.Delete(ctx, id); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+		})
+	}
+
+	if err := drafts.Delete(ctx, saved.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := drafts.Get(ctx, saved.ID); !errors.Is(err, ErrDraftNotFound) {
+		t.Fatalf("Get(deleted) error = %v, want ErrDraftNotFound", err)
+	}
+}
+
+func TestApplicationDraftStore_Expiry(t *testing.T) {
+	t.Run("memory", func(t *testing.T) {
+		store := NewMemoryDeduplicationStore()
+		defer store.Close()
+		drafts := NewApplicationDraftStore(store, 20*time.Millisecond)
+		ctx := context.Background()
+
+		draft, err := drafts.Save(ctx, "", map[string]interface{}{"firstName": "Ada"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(30 * time.Millisecond)
+		if _, err := drafts.Get(ctx, draft.ID); !errors.Is(err, ErrDraftNotFound) {
+			t.Fatalf("Get(expired) error = %v, want ErrDraftNotFound", err)
+		}
+		if _, err := drafts.Save(ctx, draft.ID, map[string]interface{}{}); !errors.Is(err, ErrDraftNotFound) {
+			t.Fatalf("Save(expired) error = %v, want ErrDraftNotFound", err)
+		}
+	})
+
+	t.Run("redis", func(t *testing.T) {
+		server := miniredis.RunT(t)
+		store, err := NewDeduplicationStore("redis://" + server.Addr() + "/0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer store.Close()
+		drafts := NewApplicationDraftStore(store, ApplicationDraftTTL)
+		ctx := context.Background()
+
+		draft, err := drafts.Save(ctx, "", map[string]interface{}{"firstName": "Ada"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if ttl := server.TTL(draftKey(draft.ID)); ttl != 24*time.Hour {
+			t.Fatalf("Redis TTL = %v, want 24h", ttl)
+		}
+
+		// Saving again pushes the expiry back
+		server.FastForward(23 * time.Hour)
+		if _, err := drafts.Save(ctx, draft.ID, map[string]interface{}{"firstName": "Ada", "lastName": "Lovelace"}); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		server.FastForward(23 * time.Hour)
+		if _, err := drafts.Get(ctx, draft.ID); err != nil {
+			t.Fatalf("Get() error = %v, want the draft kept 24h after its last save", err)
+		}
+
+		server.FastForward(time.Hour)
+		if _, err := drafts.Get(ctx, draft.ID); !errors.Is(err, ErrDraftNotFound) {
+			t.Fatalf("Get(expired) error = %v, want ErrDraftNotFound", err)
+		}
+	})
+}
+
+func TestMergeDraft(t *testing.T) {
+	draft := map[string]interface{}{"jobId": "job-1", "firstName": "Ada", "phone": "+44 20 7946 0000", "coverLetter": "Draft letter"}
+	submitted := map[string]interface{}{"firstName": "Augusta", "email": "ada@example.com", "coverLetter": ""}
+
+	want := map[string]interface{}{
+		"jobId":       "job-1",
+		"firstName":   "Augusta",
+		"phone":       "+44 20 7946 0000",
+		"email":       "ada@example.com",
+		"coverLetter": "",
+	}
+	if got := MergeDraft(draft, submitted); !reflect.DeepEqual(got, want) {
+		t.Fatalf("MergeDraft() = %v, want %v", got, want)
+	}
+	if draft["firstName"] != "Ada" {
+		t.Fatal("MergeDraft() modified the draft")
+	}
+	if got := MergeDraft(nil, submitted); !reflect.DeepEqual(got, submitted) {
+		t.Fatalf("MergeDraft(nil) = %v, want the submission", got)
+	}
+}
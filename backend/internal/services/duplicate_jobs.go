@@ -0,0 +1,98 @@
+package services
+
+import (
+	"sort"
+	"strings"
+)
+
+// duplicateEditRatio is the share of a title that may differ, in
+// Levenshtein edits, for two postings to count as possible duplicates
+const duplicateEditRatio = 0.3
+
+// JobInput is the part of a new posting compared against existing jobs
+type JobInput struct {
+	Title      string
+	Department string
+}
+
+// Job is an existing posting checked for duplicates
+type Job struct {
+	ID         string
+	Title      string
+	Department string
+}
+
+// DuplicateCandidate is an existing job that may be the same role as a new
+// posting. Similarity runs from 0 to 1, where 1 is an identical title.
+type DuplicateCandidate struct {
+	ID         string  `json:"id"`
+	Title      string  `json:"title"`
+	Similarity float64 `json:"similarity"`
+}
+
+// DetectDuplicates returns the existing jobs in newJob's department whose
+// titles are within the edit ratio of its title, most similar first. Titles
+// are compared ignoring case and extra whitespace.
+func DetectDuplicates(newJob JobInput, existingJobs []Job) []DuplicateCandidate {
+	title := normalizeJobTitle(newJob.Title)
+	if title == "" {
+		return nil
+	}
+
+	var duplicates []DuplicateCandidate
+	for _, job := range existingJobs {
+		if job.Department != newJob.Department {
+			continue
+		}
+		similarity := titleSimilarity(title, normalizeJobTitle(job.Title))
+		if 1-similarity < duplicateEditRatio {
+			duplicates = append(duplicates, DuplicateCandidate{ID: job.ID, Title: job.Title, Similarity: similarity})
+		}
+	}
+
+	sort.SliceStable(duplicates, func(i, j int) bool {
+		return duplicates[i].Similarity > duplicates[j].Similarity
+	})
+	return duplicates
+}
+
+func normalizeJobTitle(title string) string {
+	return strings.Join(strings.Fields(strings.ToLower(title)), " ")
+}
+
+// titleSimilarity is one minus the edit distance between a and b relative to
+// the longer of the two
+func titleSimilarity(a, b string) float64 {
+	ra, rb := []rune(a), []rune(b)
+	longest := len(ra)
+	if len(rb) > longest {
+		longest = len(rb)
+	}
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(levenshtein(ra, rb))/float64(longest)
+}
+
+// levenshtein counts the single-rune insertions, deletions and
+// substitutions needed to turn a into b
+func levenshtein(a, b []rune) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
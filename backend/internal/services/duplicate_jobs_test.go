@@ -0,0 +1,87 @@
+package services
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{a: "senior go engineer", b: "senior go engineer", want: 1},
+		{a: "senior go engineer", b: "senior golang engineer", want: 1 - 4.0/22},
+		{a: "backend engineer", b: "frontend engineer", want: 1 - 5.0/17},
+		{a: "data analyst", b: "office manager", want: 1 - 11.0/14},
+		{a: "", b: "", want: 1},
+		{a: "qa", b: "", want: 0},
+		// Runes, not bytes, are compared
+		{a: "ingénieur", b: "ingenieur", want: 1 - 1.0/9},
+	}
+	for _, tt := range tests {
+		if got := titleSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("titleSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+		if got := titleSimilarity(tt.b, tt.a); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("titleSimilarity(%q, %q) = %v, want it symmetric", tt.b, tt.a, got)
+		}
+	}
+}
+
+func TestDetectDuplicates(t *testing.T) {
+	tests := []struct {
+		name      string
+		title     string
+		existing  string
+		wantFound bool
+	}{
+		{name: "identical", title: "Senior Go Engineer", existing: "Senior Go Engineer", wantFound: true},
+		{name: "case and spacing", title: "senior  GO engineer ", existing: "Senior Go Engineer", wantFound: true},
+		{name: "synonym", title: "Senior Go Engineer", existing: "Senior Golang Engineer", wantFound: true},
+		{name: "typo", title: "Product Manager", existing: "Prodcut Manager", wantFound: true},
+		{name: "different role", title: "Senior Go Engineer", existing: "Engineering Manager"},
+		{name: "different stack", title: "Backend Engineer", existing: "Frontend Designer"},
+		// Ten-rune titles: two edits are a 0.2 ratio, three are exactly 0.3
+		{name: "just under the ratio", title: "abcdefghij", existing: "abcdefghxy", wantFound: true},
+		{name: "at the ratio", title: "abcdefghij", existing: "abcdefgxyz"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DetectDuplicates(JobInput{Title: tt.title, Department: "Engineering"}, []Job{
+				{ID: "job-1", Title: tt.existing, Department: "Engineering"},
+			})
+			if found := len(got) == 1; found != tt.wantFound {
+				t.Fatalf("DetectDuplicates(%q, %q) = %+v, want found = %v", tt.title, tt.existing, got, tt.wantFound)
+			}
+			if tt.wantFound && (got[0].ID != "job-1" || got[0].Title != tt.existing) {
+				t.Fatalf("duplicate = %+v", got[0])
+			}
+		})
+	}
+}
+
+func TestDetectDuplicates_DepartmentAndOrder(t *testing.T) {
+	existing := []Job{
+		{ID: "job-1", Title: "Senior Golang Engineer", Department: "Engineering"},
+		{ID: "job-2", Title: "Senior Go Engineer", Department: "Data"},
+		{ID: "job-3", Title: "Senior Go Engineer", Department: "Engineering"},
+		{ID: "job-4", Title: "Engineering Manager", Department: "Engineering"},
+	}
+	got := DetectDuplicates(JobInput{Title: "Senior Go Engineer", Department: "Engineering"}, existing)
+
+	var ids []string
+	for _, duplicate := range got {
+		ids = append(ids, duplicate.ID)
+	}
+	if len(ids) != 2 || ids[0] != "job-3" || ids[1] != "job-1" {
+		t.Fatalf("duplicates = %v, want job-3 then job-1 from the same department", ids)
+	}
+	if got[0].Similarity != 1 {
+		t.Fatalf("identical title similarity = %v, want 1", got[0].Similarity)
+	}
+
+	if got := DetectDuplicates(JobInput{Title: " ", Department: "Engineering"}, existing); got != nil {
+		t.Fatalf("blank title matched %+v", got)
+	}
+}
@@ -2,10 +2,12 @@ package services
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
+	"time"
 )
 
 // EmailService handles email sending
@@ -14,132 +16,288 @@ type EmailService struct {
 	fromEmail   string
 	fromName    string
 	client      *http.Client
+	templates   *TemplateEngine
+}
+
+// EmailJob is a single email: a recipient, a subject and the template that
+// renders its body
+type EmailJob struct {
+	To          string
+	Subject     string
+	Template    string
+	Data        map[string]interface{}
+	Attachments []EmailAttachment
+}
+
+// EmailAttachment is a file sent along with an email
+type EmailAttachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// SendGridError is returned when SendGrid rejects a send
+type SendGridError struct {
+	StatusCode int
+}
+
+func (e *SendGridError) Error() string {
+	return fmt.Sprintf("SendGrid returned status %d", e.StatusCode)
 }
 
 // NewEmailService creates a new email service
 func NewEmailService(sendGridKey string) *EmailService {
+	templates, err := NewTemplateEngine(emailTemplateFS)
+	if err != nil {
+		// The templates are embedded in the binary, so this is a build defect
+		panic(err)
+	}
+
 	return &EmailService{
 		sendGridKey: sendGridKey,
 		fromEmail:   "noreply@company.com",
 		fromName:    "HR Recruiting",
 		client:      &http.Client{},
+		templates:   templates,
 	}
 }
 
-// SendApplicationConfirmation sends a confirmation email to the applicant
-func (s *EmailService) SendApplicationConfirmation(email, firstName, jobID string) error {
+// Templates returns the engine used to render email bodies
+func (s *EmailService) Templates() *TemplateEngine {
+	return s.templates
+}
+
+// Send renders and sends a job synchronously
+func (s *EmailService) Send(job EmailJob) error {
 	if s.sendGridKey == "" {
-		log.Println("SendGrid API key not configured, skipping email")
+		slog.Warn("SendGrid API key not configured, skipping email", "template", job.Template)
 		return nil
 	}
 
-	subject := "Application Received - Thank You for Applying!"
-	htmlContent := fmt.Sprintf(`
-		<html>
-		<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
-			<h2>Thank you for your application, %s!</h2>
-			<p>We've successfully received your application for the position.</p>
-			<p>Our recruiting team will review your application and get back to you soon.</p>
-			<p>In the meantime, you can:</p>
-			<ul>
-				<li>Track your application status in your dashboard</li>
-				<li>Explore other open positions</li>
-				<li>Connect with us on LinkedIn</li>
-			</ul>
-			<p>Best regards,<br>The Recruiting Team</p>
-		</body>
-		</html>
-	`, firstName)
+	htmlContent, err := s.templates.Render(job.Template, job.Data)
+	if err != nil {
+		return err
+	}
 
-	return s.sendEmail(email, subject, htmlContent)
+	return s.sendEmail(job.To, job.Subject, htmlContent, job.Attachments)
 }
 
-// SendStatusUpdate sends a status update email
-func (s *EmailService) SendStatusUpdate(applicationID, status string) error {
-	if s.sendGridKey == "" {
-		log.Println("SendGrid API key not configured, skipping email")
-		return nil
+// ApplicationConfirmationEmail thanks an applicant for applying
+func ApplicationConfirmationEmail(email, firstName, jobID string) EmailJob {
+	return EmailJob{
+		To:       email,
+		Subject:  "Application Received - Thank You for Applying!",
+		Template: "application_confirmation",
+		Data: map[string]interface{}{
+			"FirstName": firstName,
+			"JobID":     jobID,
+		},
 	}
+}
 
-	// In a real implementation, you would fetch the application details
-	// from the database to get the candidate's email and job title
-	log.Printf("Would send status update email for application %s: %s", applicationID, status)
-	return nil
+// StatusUpdateEmail tells a candidate their application moved stage
+func StatusUpdateEmail(email, candidateName, jobTitle, status string) EmailJob {
+	return EmailJob{
+		To:       email,
+		Subject:  fmt.Sprintf("Application Update - %s", jobTitle),
+		Template: "status_update",
+		Data: map[string]interface{}{
+			"CandidateName": candidateName,
+			"JobTitle":      jobTitle,
+			"Status":        status,
+		},
+	}
 }
 
-// SendInterviewInvitation sends an interview invitation
-func (s *EmailService) SendInterviewInvitation(email, candidateName, jobTitle, interviewDate string) error {
-	if s.sendGridKey == "" {
-		log.Println("SendGrid API key not configured, skipping email")
+// InterviewInvitationEmail invites a candidate to interview, attaching the
+// calendar invitation when ics is non-empty
+func InterviewInvitationEmail(email, candidateName, jobTitle, interviewDate string, ics []byte) EmailJob {
+	return EmailJob{
+		To:       email,
+		Subject:  fmt.Sprintf("Interview Invitation - %s", jobTitle),
+		Template: "interview_invitation",
+		Data: map[string]interface{}{
+			"CandidateName": candidateName,
+			"JobTitle":      jobTitle,
+			"InterviewDate": interviewDate,
+		},
+		Attachments: calendarAttachment(ics),
+	}
+}
+
+// InterviewerInvitationEmail tells an interviewer they are on a candidate's
+// interview panel
+func InterviewerInvitationEmail(email, interviewerName, candidateName, jobTitle, interviewDate string, ics []byte) EmailJob {
+	return EmailJob{
+		To:       email,
+		Subject:  fmt.Sprintf("Interview Scheduled - %s for %s", candidateName, jobTitle),
+		Template: "interviewer_invitation",
+		Data: map[string]interface{}{
+			"InterviewerName": interviewerName,
+			"CandidateName":   candidateName,
+			"JobTitle":        jobTitle,
+			"InterviewDate":   interviewDate,
+		},
+		Attachments: calendarAttachment(ics),
+	}
+}
+
+// calendarAttachment wraps an ICS file as an invite.ics attachment
+func calendarAttachment(ics []byte) []EmailAttachment {
+	if len(ics) == 0 {
 		return nil
 	}
+	return []EmailAttachment{{
+		Filename:    "invite.ics",
+		ContentType: "text/calendar; method=REQUEST",
+		Content:     ics,
+	}}
+}
 
-	subject := fmt.Sprintf("Interview Invitation - %s", jobTitle)
-	htmlContent := fmt.Sprintf(`
-		<html>
-		<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
-			<h2>Great news, %s!</h2>
-			<p>We'd like to invite you for an interview for the <strong>%s</strong> position.</p>
-			<p><strong>Interview Date:</strong> %s</p>
-			<p>Please confirm your availability by replying to this email.</p>
-			<p>We look forward to speaking with you!</p>
-			<p>Best regards,<br>The Recruiting Team</p>
-		</body>
-		</html>
-	`, candidateName, jobTitle, interviewDate)
+// InterviewReminderEmail reminds a candidate or interviewer of an upcoming
+// interview. startsIn describes the lead time, such as "24 hours".
+func InterviewReminderEmail(email, name, jobTitle, interviewDate, startsIn string) EmailJob {
+	return EmailJob{
+		To:       email,
+		Subject:  fmt.Sprintf("Reminder: Interview in %s - %s", startsIn, jobTitle),
+		Template: "interview_reminder",
+		Data: map[string]interface{}{
+			"Name":          name,
+			"JobTitle":      jobTitle,
+			"InterviewDate": interviewDate,
+			"StartsIn":      startsIn,
+		},
+	}
+}
 
-	return s.sendEmail(email, subject, htmlContent)
+// OfferLetterEmail extends an offer to a candidate, attaching the signed
+// letter when letter is non-empty
+func OfferLetterEmail(email, candidateName, jobTitle string, letter []byte) EmailJob {
+	job := EmailJob{
+		To:       email,
+		Subject:  fmt.Sprintf("Job Offer - %s", jobTitle),
+		Template: "offer_letter",
+		Data: map[string]interface{}{
+			"CandidateName": candidateName,
+			"JobTitle":      jobTitle,
+		},
+	}
+	if len(letter) > 0 {
+		job.Attachments = []EmailAttachment{{
+			Filename:    "offer-letter.pdf",
+			ContentType: "application/pdf",
+			Content:     letter,
+		}}
+	}
+	return job
 }
 
-// SendOfferLetter sends an offer letter
-func (s *EmailService) SendOfferLetter(email, candidateName, jobTitle string) error {
-	if s.sendGridKey == "" {
-		log.Println("SendGrid API key not configured, skipping email")
-		return nil
+// CounterOfferUpdateEmail tells the candidate where their counter offer stands
+func CounterOfferUpdateEmail(email, candidateName, jobTitle, status string) EmailJob {
+	return EmailJob{
+		To:       email,
+		Subject:  fmt.Sprintf("Counter Offer Update - %s", jobTitle),
+		Template: "counter_offer_update",
+		Data: map[string]interface{}{
+			"CandidateName": candidateName,
+			"JobTitle":      jobTitle,
+			"Status":        status,
+		},
 	}
+}
 
-	subject := fmt.Sprintf("Job Offer - %s", jobTitle)
-	htmlContent := fmt.Sprintf(`
-		<html>
-		<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
-			<h2>Congratulations, %s!</h2>
-			<p>We're excited to extend an offer for the <strong>%s</strong> position.</p>
-			<p>Please review the attached offer letter and let us know if you have any questions.</p>
-			<p>We look forward to welcoming you to our team!</p>
-			<p>Best regards,<br>The Recruiting Team</p>
-		</body>
-		</html>
-	`, candidateName, jobTitle)
+// CounterOfferNoticeEmail tells the recruiter where a counter offer stands
+func CounterOfferNoticeEmail(email, recruiterName, candidateName, jobTitle, status string) EmailJob {
+	return EmailJob{
+		To:       email,
+		Subject:  fmt.Sprintf("Counter Offer %s - %s", status, jobTitle),
+		Template: "counter_offer_notice",
+		Data: map[string]interface{}{
+			"RecruiterName": recruiterName,
+			"CandidateName": candidateName,
+			"JobTitle":      jobTitle,
+			"Status":        status,
+		},
+	}
+}
 
-	return s.sendEmail(email, subject, htmlContent)
+// RejectionEmail lets a candidate know they were not selected
+func RejectionEmail(email, candidateName, jobTitle string) EmailJob {
+	return EmailJob{
+		To:       email,
+		Subject:  fmt.Sprintf("Application Update - %s", jobTitle),
+		Template: "rejection",
+		Data: map[string]interface{}{
+			"CandidateName": candidateName,
+			"JobTitle":      jobTitle,
+		},
+	}
 }
 
-// SendRejection sends a rejection email
-func (s *EmailService) SendRejection(email, candidateName, jobTitle string) error {
-	if s.sendGridKey == "" {
-		log.Println("SendGrid API key not configured, skipping email")
-		return nil
+// DataExportLinkEmail sends a candidate the link to download their data
+func DataExportLinkEmail(email, firstName, link string, expiresIn time.Duration) EmailJob {
+	return EmailJob{
+		To:       email,
+		Subject:  "Your Personal Data Export",
+		Template: "data_export_link",
+		Data: map[string]interface{}{
+			"FirstName": firstName,
+			"Link":      link,
+			"ExpiresIn": expiresIn.String(),
+		},
 	}
+}
 
-	subject := fmt.Sprintf("Application Update - %s", jobTitle)
-	htmlContent := fmt.Sprintf(`
-		<html>
-		<body style="font-family: Arial, sans-serif; line-height: 1.6; color: #333;">
-			<p>Dear %s,</p>
-			<p>Thank you for your interest in the <strong>%s</strong> position and for taking the time to apply.</p>
-			<p>After careful consideration, we have decided to move forward with other candidates whose qualifications more closely match our current needs.</p>
-			<p>We appreciate your interest in our company and encourage you to apply for future positions that match your skills and experience.</p>
-			<p>We wish you the best in your job search.</p>
-			<p>Best regards,<br>The Recruiting Team</p>
-		</body>
-		</html>
-	`, candidateName, jobTitle)
+// ErasureConfirmationEmail confirms a candidate's personal data was erased
+func ErasureConfirmationEmail(email, firstName, requestID string) EmailJob {
+	return EmailJob{
+		To:       email,
+		Subject:  "Your Personal Data Has Been Deleted",
+		Template: "erasure_confirmation",
+		Data: map[string]interface{}{
+			"FirstName": firstName,
+			"RequestID": requestID,
+		},
+	}
+}
 
-	return s.sendEmail(email, subject, htmlContent)
+// SendApplicationConfirmation sends a confirmation email to the applicant
+func (s *EmailService) SendApplicationConfirmation(email, firstName, jobID string) error {
+	return s.Send(ApplicationConfirmationEmail(email, firstName, jobID))
+}
+
+// SendStatusUpdate sends a status update email
+func (s *EmailService) SendStatusUpdate(email, candidateName, jobTitle, status string) error {
+	return s.Send(StatusUpdateEmail(email, candidateName, jobTitle, status))
+}
+
+// SendInterviewInvitation sends an interview invitation with its calendar file
+func (s *EmailService) SendInterviewInvitation(email, candidateName, jobTitle, interviewDate string, ics []byte) error {
+	return s.Send(InterviewInvitationEmail(email, candidateName, jobTitle, interviewDate, ics))
+}
+
+// SendOfferLetter sends an offer letter with the letter PDF attached
+func (s *EmailService) SendOfferLetter(email, candidateName, jobTitle string, letter []byte) error {
+	return s.Send(OfferLetterEmail(email, candidateName, jobTitle, letter))
+}
+
+// SendCounterOfferUpdate tells the candidate where their counter offer stands
+func (s *EmailService) SendCounterOfferUpdate(email, candidateName, jobTitle, status string) error {
+	return s.Send(CounterOfferUpdateEmail(email, candidateName, jobTitle, status))
+}
+
+// SendCounterOfferNotice tells the recruiter where a counter offer stands
+func (s *EmailService) SendCounterOfferNotice(email, recruiterName, candidateName, jobTitle, status string) error {
+	return s.Send(CounterOfferNoticeEmail(email, recruiterName, candidateName, jobTitle, status))
+}
+
+// SendRejection sends a rejection email
+func (s *EmailService) SendRejection(email, candidateName, jobTitle string) error {
+	return s.Send(RejectionEmail(email, candidateName, jobTitle))
 }
 
 // sendEmail sends an email using SendGrid API
-func (s *EmailService) sendEmail(to, subject, htmlContent string) error {
+func (s *EmailService) sendEmail(to, subject, htmlContent string, attachments []EmailAttachment) error {
 	if s.sendGridKey == "" {
 		return fmt.Errorf("SendGrid API key not configured")
 	}
@@ -165,6 +323,19 @@ func (s *EmailService) sendEmail(to, subject, htmlContent string) error {
 		},
 	}
 
+	if len(attachments) > 0 {
+		encoded := make([]map[string]string, 0, len(attachments))
+		for _, attachment := range attachments {
+			encoded = append(encoded, map[string]string{
+				"content":     base64.StdEncoding.EncodeToString(attachment.Content),
+				"type":        attachment.ContentType,
+				"filename":    attachment.Filename,
+				"disposition": "attachment",
+			})
+		}
+		payload["attachments"] = encoded
+	}
+
 	jsonData, err := json.Marshal(payload)
 	if err != nil {
 		return fmt.Errorf("failed to marshal email payload: %w", err)
@@ -185,9 +356,9 @@ func (s *EmailService) sendEmail(to, subject, htmlContent string) error {
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
-		return fmt.Errorf("SendGrid returned status %d", resp.StatusCode)
+		return &SendGridError{StatusCode: resp.StatusCode}
 	}
 
-	log.Printf("Email sent successfully to %s", to)
+	slog.Info("email sent", "to", to)
 	return nil
-}
\ No newline at end of file
+}
@@ -0,0 +1,151 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrEmailQueueFull is returned when the queue buffer has no room left
+	ErrEmailQueueFull = errors.New("email queue is full")
+	// ErrEmailQueueClosed is returned when enqueueing after shutdown began
+	ErrEmailQueueClosed = errors.New("email queue is closed")
+)
+
+const (
+	emailMaxAttempts = 3
+	emailRetryDelay  = time.Second
+)
+
+// EmailEnqueuer schedules emails for delivery
+type EmailEnqueuer interface {
+	Enqueue(job EmailJob) error
+}
+
+// EmailQueue sends emails on a pool of background workers, retrying
+// transient SendGrid failures
+type EmailQueue struct {
+	service *EmailService
+	jobs    chan EmailJob
+
+	mu     sync.RWMutex
+	closed bool
+	wg     sync.WaitGroup
+
+	retryDelay time.Duration
+}
+
+// NewEmailQueue starts workerCount workers draining a buffer of capacity jobs
+func NewEmailQueue(service *EmailService, workerCount, capacity int) *EmailQueue {
+	if workerCount < 1 {
+		workerCount = 1
+	}
+
+	q := &EmailQueue{
+		service:    service,
+		jobs:       make(chan EmailJob, capacity),
+		retryDelay: emailRetryDelay,
+	}
+
+	q.wg.Add(workerCount)
+	for i := 0; i < workerCount; i++ {
+		go q.work()
+	}
+
+	return q
+}
+
+// Enqueue schedules a job without blocking. Jobs are dropped with an error
+// rather than stalling the request when the buffer is full.
+func (q *EmailQueue) Enqueue(job EmailJob) error {
+	q.mu.RLock()
+	defer q.mu.RUnlock()
+
+	if q.closed {
+		return ErrEmailQueueClosed
+	}
+
+	select {
+	case q.jobs <- job:
+		return nil
+	default:
+		slog.Error("email queue full, dropping email", "to", job.To, "template", job.Template)
+		return ErrEmailQueueFull
+	}
+}
+
+// Depth returns the number of jobs waiting for a worker
+func (q *EmailQueue) Depth() int {
+	return len(q.jobs)
+}
+
+// Shutdown stops accepting jobs and waits for queued ones to be sent, or for
+// ctx to expire
+func (q *EmailQueue) Shutdown(ctx context.Context) error {
+	q.mu.Lock()
+	if !q.closed {
+		q.closed = true
+		close(q.jobs)
+	}
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		slog.Error("email queue shutdown timed out", "pending", q.Depth())
+		return ctx.Err()
+	}
+}
+
+func (q *EmailQueue) work() {
+	defer q.wg.Done()
+
+	for job := range q.jobs {
+		q.deliver(job)
+	}
+}
+
+// deliver sends a job, backing off between attempts on retryable errors
+func (q *EmailQueue) deliver(job EmailJob) {
+	var err error
+	for attempt := 1; attempt <= emailMaxAttempts; attempt++ {
+		if err = q.service.Send(job); err == nil {
+			return
+		}
+		if !retryableEmailError(err) {
+			break
+		}
+		if attempt < emailMaxAttempts {
+			slog.Warn("email send failed, retrying",
+				"to", job.To, "template", job.Template, "attempt", attempt, "error", err)
+			time.Sleep(q.retryDelay << (attempt - 1))
+		}
+	}
+
+	slog.Error("email permanently failed",
+		"to", job.To, "subject", job.Subject, "template", job.Template, "error", err)
+}
+
+// retryableEmailError reports whether a send may succeed if tried again:
+// network failures, throttling and SendGrid server errors. Any other 4xx, or
+// a template that fails to render, will fail the same way every time.
+func retryableEmailError(err error) bool {
+	var sgErr *SendGridError
+	if errors.As(err, &sgErr) {
+		return sgErr.StatusCode == http.StatusTooManyRequests || sgErr.StatusCode >= 500
+	}
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
@@ -0,0 +1,211 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeSendGrid answers sends with the status codes in responses, in turn,
+// repeating the last one once they run out. When release is set, each send
+// waits for it to be closed.
+type fakeSendGrid struct {
+	mu        sync.Mutex
+	responses []int
+	sends     int
+
+	started chan struct{}
+	release chan struct{}
+}
+
+func (f *fakeSendGrid) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.mu.Lock()
+	status := f.responses[min(f.sends, len(f.responses)-1)]
+	f.sends++
+	f.mu.Unlock()
+
+	if f.started != nil {
+		f.started <- struct{}{}
+	}
+	if f.release != nil {
+		<-f.release
+	}
+	return &http.Response{
+		StatusCode: status,
+		Body:       io.NopCloser(strings.NewReader("")),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+func (f *fakeSendGrid) sendCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.sends
+}
+
+// newTestEmailQueue starts a queue sending through fake without real delays
+func newTestEmailQueue(fake *fakeSendGrid, workerCount, capacity int) *EmailQueue {
+	service := NewEmailService("SG.test")
+	service.client = &http.Client{Transport: fake}
+
+	q := NewEmailQueue(service, workerCount, capacity)
+	q.retryDelay = time.Millisecond
+	return q
+}
+
+func testEmail() EmailJob {
+	return StatusUpdateEmail("ada@example.com", "Ada", "Engineer", "INTERVIEW")
+}
+
+func shutdown(t *testing.T, q *EmailQueue) {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := q.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+}
+
+func TestEmailQueue_Retry(t *testing.T) {
+	tests := []struct {
+		name      string
+		responses []int
+		wantSends int
+	}{
+		{name: "sent first time", responses: []int{http.StatusAccepted}, wantSends: 1},
+		{name: "retried after throttling and server error", responses: []int{http.StatusTooManyRequests, http.StatusServiceUnavailable, http.StatusAccepted}, wantSends: 3},
+		{name: "gives up after three attempts", responses: []int{http.StatusInternalServerError}, wantSends: emailMaxAttempts},
+		{name: "client error not retried", responses: []int{http.StatusBadRequest}, wantSends: 1},
+		{name: "unauthorized not retried", responses: []int{http.StatusUnauthorized}, wantSends: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeSendGrid{responses: tt.responses}
+			q := newTestEmailQueue(fake, 1, 10)
+
+			if err := q.Enqueue(testEmail()); err != nil {
+				t.Fatalf("Enqueue() error = %v", err)
+			}
+			shutdown(t, q)
+
+			if got := fake.sendCount(); got != tt.wantSends {
+				t.Fatalf("sends = %d, want %d", got, tt.wantSends)
+			}
+		})
+	}
+}
+
+func TestEmailQueue_ShutdownDrains(t *testing.T) {
+	fake := &fakeSendGrid{
+		responses: []int{http.StatusAccepted},
+		started:   make(chan struct{}, 32),
+		release:   make(chan struct{}),
+	}
+	q := newTestEmailQueue(fake, 2, 20)
+
+	for i := 0; i < 20; i++ {
+		if err := q.Enqueue(testEmail()); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+	<-fake.started
+	<-fake.started
+	if depth := q.Depth(); depth != 18 {
+		t.Fatalf("Depth() = %d, want 18 waiting behind the busy workers", depth)
+	}
+
+	done := make(chan error, 1)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		done <- q.Shutdown(ctx)
+	}()
+
+	// Enqueueing fails as soon as shutdown begins, while queued emails are
+	// still being sent. Emails accepted before then are sent too.
+	queued := 20
+	deadline := time.Now().Add(time.Second)
+	for {
+		err := q.Enqueue(testEmail())
+		if errors.Is(err, ErrEmailQueueClosed) {
+			break
+		}
+		if err == nil {
+			queued++
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("Enqueue() kept accepting emails after Shutdown()")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	close(fake.release)
+	if err := <-done; err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if got := fake.sendCount(); got != queued {
+		t.Fatalf("sends = %d, want all %d queued emails sent", got, queued)
+	}
+}
+
+func TestEmailQueue_ShutdownTimeout(t *testing.T) {
+	fake := &fakeSendGrid{
+		responses: []int{http.StatusAccepted},
+		started:   make(chan struct{}, 1),
+		release:   make(chan struct{}),
+	}
+	q := newTestEmailQueue(fake, 1, 1)
+	if err := q.Enqueue(testEmail()); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	<-fake.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := q.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	close(fake.release)
+	shutdown(t, q)
+}
+
+func TestEmailQueue_Overflow(t *testing.T) {
+	fake := &fakeSendGrid{
+		responses: []int{http.StatusAccepted},
+		started:   make(chan struct{}, 4),
+		release:   make(chan struct{}),
+	}
+	q := newTestEmailQueue(fake, 1, 2)
+
+	// The only worker takes the first job and blocks on it, so the next two
+	// fill the buffer and the fourth does not fit
+	if err := q.Enqueue(testEmail()); err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+	<-fake.started
+	for i := 0; i < 2; i++ {
+		if err := q.Enqueue(testEmail()); err != nil {
+			t.Fatalf("Enqueue() error = %v", err)
+		}
+	}
+	if depth := q.Depth(); depth != 2 {
+		t.Fatalf("Depth() = %d, want 2", depth)
+	}
+	if err := q.Enqueue(testEmail()); !errors.Is(err, ErrEmailQueueFull) {
+		t.Fatalf("Enqueue() on a full queue error = %v, want %v", err, ErrEmailQueueFull)
+	}
+
+	close(fake.release)
+	shutdown(t, q)
+	if got := fake.sendCount(); got != 3 {
+		t.Fatalf("sends = %d, want the 3 accepted emails", got)
+	}
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"html/template"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed templates/email/*.html
+var emailTemplateFS embed.FS
+
+// ErrTemplateNotFound is returned when rendering an unknown template
+var ErrTemplateNotFound = errors.New("email template not found")
+
+// TemplateEngine renders HTML email bodies. Values are escaped by
+// html/template, so candidate-supplied text is safe to interpolate.
+type TemplateEngine struct {
+	templates map[string]*template.Template
+}
+
+// NewTemplateEngine parses every *.html file under templates/email in fsys.
+// Templates are addressed by file name without the extension.
+func NewTemplateEngine(fsys fs.FS) (*TemplateEngine, error) {
+	files, err := fs.Glob(fsys, "templates/email/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	engine := &TemplateEngine{templates: make(map[string]*template.Template)}
+	for _, file := range files {
+		tmpl, err := template.ParseFS(fsys, file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse email template %s: %w", file, err)
+		}
+		engine.templates[strings.TrimSuffix(path.Base(file), ".html")] = tmpl
+	}
+
+	return engine, nil
+}
+
+// Render executes the named template with data
+func (e *TemplateEngine) Render(name string, data interface{}) (string, error) {
+	tmpl, ok := e.templates[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrTemplateNotFound, name)
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("failed to render email template %s: %w", name, err)
+	}
+	return buf.String(), nil
+}
+
+// Names lists the available templates
+func (e *TemplateEngine) Names() []string {
+	names := make([]string, 0, len(e.templates))
+	for name := range e.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestTemplateEngine_EscapesHTML(t *testing.T) {
+	engine, err := NewTemplateEngine(emailTemplateFS)
+	if err != nil {
+		t.Fatalf("NewTemplateEngine() error = %v", err)
+	}
+
+	hostile := `<script>alert("x")</script> & <b>Co</b>`
+	data := map[string]interface{}{
+		"FirstName":     hostile,
+		"CandidateName": hostile,
+		"JobTitle":      hostile,
+		"Status":        hostile,
+		"InterviewDate": hostile,
+	}
+	for _, name := range []string{"application_confirmation", "status_update", "interview_invitation", "offer_letter", "rejection"} {
+		t.Run(name, func(t *testing.T) {
+			html, err := engine.Render(name, data)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			if strings.Contains(html, "<script>") || strings.Contains(html, "<b>Co") {
+				t.Fatalf("Render() kept markup from the data:\n%s", html)
+			}
+			if !strings.Contains(html, "&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt; &amp; &lt;b&gt;Co&lt;/b&gt;") {
+				t.Fatalf("Render() did not escape the data:\n%s", html)
+			}
+		})
+	}
+}
+
+func TestTemplateEngine_Render(t *testing.T) {
+	engine, err := NewTemplateEngine(emailTemplateFS)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	html, err := engine.Render("status_update", map[string]interface{}{"Status": "INTERVIEW"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(html, "Hello,") || !strings.Contains(html, "<strong>INTERVIEW</strong>") || strings.Contains(html, "position") {
+		t.Fatalf("Render() without a name or job =\n%s", html)
+	}
+
+	if _, err := engine.Render("missing", nil); !errors.Is(err, ErrTemplateNotFound) {
+		t.Fatalf("Render(missing) error = %v, want ErrTemplateNotFound", err)
+	}
+
+	names := engine.Names()
+	if !slices.IsSorted(names) || !slices.Contains(names, "rejection") || slices.Contains(names, "rejection.html") {
+		t.Fatalf("Names() = %v", names)
+	}
+}
+
+func TestNewTemplateEngine_Errors(t *testing.T) {
+	fsys := fstest.MapFS{
+		"templates/email/broken.html": {Data: []byte("<p>{{.Name</p>")},
+	}
+	if _, err := NewTemplateEngine(fsys); err == nil || !strings.Contains(err.Error(), "broken.html") {
+		t.Fatalf("NewTemplateEngine() error = %v, want the broken template named", err)
+	}
+
+	engine, err := NewTemplateEngine(fstest.MapFS{
+		"templates/email/greeting.html": {Data: []byte("<p>Hi {{.Name.First}}</p>")},
+		"templates/email/notes.txt":     {Data: []byte("ignored")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := engine.Names(); len(names) != 1 || names[0] != "greeting" {
+		t.Fatalf("Names() = %v, want only the HTML template", names)
+	}
+	if _, err := engine.Render("greeting", map[string]interface{}{"Name": "Ada"}); err == nil {
+		t.Fatal("Render() with data the template cannot use succeeded")
+	}
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// pipelineSubscriberBuffer is how many events a subscriber may fall behind by
+// before further events are dropped for it
+const pipelineSubscriberBuffer = 32
+
+// PipelineEvent describes an application moving between pipeline stages
+type PipelineEvent struct {
+	ApplicationID string `json:"applicationId"`
+	OldStatus     string `json:"oldStatus,omitempty"`
+	NewStatus     string `json:"newStatus"`
+	JobID         string `json:"jobId,omitempty"`
+}
+
+// PipelineEventBus fans pipeline events out to in-process subscribers, each
+// with its own buffered channel
+type PipelineEventBus struct {
+	mu          sync.RWMutex
+	subscribers map[chan PipelineEvent]struct{}
+}
+
+// NewPipelineEventBus creates an event bus with no subscribers
+func NewPipelineEventBus() *PipelineEventBus {
+	return &PipelineEventBus{
+		subscribers: make(map[chan PipelineEvent]struct{}),
+	}
+}
+
+// Subscribe registers a new subscriber. The returned function unsubscribes
+// and closes the channel; it is safe to call more than once.
+func (b *PipelineEventBus) Subscribe() (<-chan PipelineEvent, func()) {
+	ch := make(chan PipelineEvent, pipelineSubscriberBuffer)
+
+	b.mu.Lock()
+	b.subscribers[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subscribers, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
+
+// Publish delivers event to every subscriber without blocking. A subscriber
+// whose buffer is full misses the event rather than stalling the publisher.
+func (b *PipelineEventBus) Publish(event PipelineEvent) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+			slog.Warn("pipeline subscriber too slow, dropping event", "application_id", event.ApplicationID)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+)
+
+func TestPipelineEventBus_Publish(t *testing.T) {
+	bus := NewPipelineEventBus()
+	first, unsubscribeFirst := bus.Subscribe()
+	defer unsubscribeFirst()
+	second, unsubscribeSecond := bus.Subscribe()
+	defer unsubscribeSecond()
+
+	event := PipelineEvent{ApplicationID: "app-1", OldStatus: "APPLIED", NewStatus: "SCREENING", JobID: "job-1"}
+	bus.Publish(event)
+
+	for i, ch := range []<-chan PipelineEvent{first, second} {
+		select {
+		case got := <-ch:
+			if got != event {
+				t.Fatalf("subscriber %d got %+v, want %+v", i+1, got, event)
+			}
+		default:
+			t.Fatalf("subscriber %d got no event", i+1)
+		}
+	}
+}
+
+func TestPipelineEventBus_Unsubscribe(t *testing.T) {
+	bus := NewPipelineEventBus()
+	events, unsubscribe := bus.Subscribe()
+
+	unsubscribe()
+	unsubscribe()
+
+	if _, ok := <-events; ok {
+		t.Fatal("channel still open after unsubscribing")
+	}
+	// Publishing to a bus whose subscriber left must not panic on the
+	// closed channel
+	bus.Publish(PipelineEvent{ApplicationID: "app-1", NewStatus: "SCREENING"})
+	if len(bus.subscribers) != 0 {
+		t.Fatalf("%d subscribers left, want 0", len(bus.subscribers))
+	}
+}
+
+func TestPipelineEventBus_SlowSubscriber(t *testing.T) {
+	bus := NewPipelineEventBus()
+	slow, unsubscribeSlow := bus.Subscribe()
+	defer unsubscribeSlow()
+
+	// Publishing past the buffer drops events instead of blocking
+	for i := 0; i < pipelineSubscriberBuffer+5; i++ {
+		bus.Publish(PipelineEvent{ApplicationID: "app-1", NewStatus: "SCREENING"})
+	}
+	if len(slow) != pipelineSubscriberBuffer {
+		t.Fatalf("slow subscriber holds %d events, want %d", len(slow), pipelineSubscriberBuffer)
+	}
+
+	// A subscriber that keeps up still gets later events
+	fresh, unsubscribeFresh := bus.Subscribe()
+	defer unsubscribeFresh()
+	bus.Publish(PipelineEvent{ApplicationID: "app-2", NewStatus: "OFFER"})
+	if got := <-fresh; got.ApplicationID != "app-2" {
+		t.Fatalf("fresh subscriber got %+v, want app-2", got)
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ExchangeRates is a set of rates relative to a single base currency
+type ExchangeRates struct {
+	Base      string             `json:"base"`
+	Rates     map[string]float64 `json:"rates"`
+	Timestamp time.Time          `json:"timestamp"`
+	Source    string             `json:"source"`
+}
+
+// ExchangeRateService fetches daily exchange rates and caches them
+type ExchangeRateService struct {
+	apiURL string
+	apiKey string
+	ttl    time.Duration
+	client *http.Client
+
+	mu        sync.Mutex
+	rates     *ExchangeRates
+	fetchedAt time.Time
+}
+
+// NewExchangeRateService creates a new exchange rate service. apiURL should
+// return an OpenExchangeRates-compatible JSON body.
+func NewExchangeRateService(apiURL, apiKey string) *ExchangeRateService {
+	return &ExchangeRateService{
+		apiURL: apiURL,
+		apiKey: apiKey,
+		ttl:    24 * time.Hour,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Rates returns cached rates, refreshing them once they are a day old
+func (s *ExchangeRateService) Rates(ctx context.Context) (*ExchangeRates, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.rates != nil && time.Since(s.fetchedAt) < s.ttl {
+		return s.rates, nil
+	}
+
+	rates, err := s.fetch(ctx)
+	if err != nil {
+		// Stale rates beat no rates while the provider is down
+		if s.rates != nil {
+			return s.rates, nil
+		}
+		return nil, err
+	}
+
+	s.rates = rates
+	s.fetchedAt = time.Now()
+	return rates, nil
+}
+
+// Convert converts amount between currencies using the given rates
+func (r *ExchangeRates) Convert(amount float64, from, to string) (float64, error) {
+	from, to = strings.ToUpper(from), strings.ToUpper(to)
+	if from == to {
+		return amount, nil
+	}
+
+	fromRate, ok := r.rate(from)
+	if !ok {
+		return 0, fmt.Errorf("no exchange rate for %s", from)
+	}
+	toRate, ok := r.rate(to)
+	if !ok {
+		return 0, fmt.Errorf("no exchange rate for %s", to)
+	}
+
+	return amount / fromRate * toRate, nil
+}
+
+func (r *ExchangeRates) rate(currency string) (float64, bool) {
+	if currency == r.Base {
+		return 1, true
+	}
+	rate, ok := r.Rates[currency]
+	return rate, ok && rate > 0
+}
+
+func (s *ExchangeRateService) fetch(ctx context.Context) (*ExchangeRates, error) {
+	if s.apiURL == "" {
+		return nil, fmt.Errorf("exchange rate API not configured")
+	}
+
+	u, err := url.Parse(s.apiURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exchange rate API URL: %w", err)
+	}
+	if s.apiKey != "" {
+		q := u.Query()
+		q.Set("app_id", s.apiKey)
+		u.RawQuery = q.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exchange rates: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("exchange rate API returned status %d", resp.StatusCode)
+	}
+
+	var body struct {
+		Base      string             `json:"base"`
+		Rates     map[string]float64 `json:"rates"`
+		Timestamp int64              `json:"timestamp"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("failed to decode exchange rates: %w", err)
+	}
+
+	timestamp := time.Now()
+	if body.Timestamp > 0 {
+		timestamp = time.Unix(body.Timestamp, 0)
+	}
+
+	return &ExchangeRates{
+		Base:      strings.ToUpper(body.Base),
+		Rates:     body.Rates,
+		Timestamp: timestamp,
+		Source:    u.Host,
+	}, nil
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newFakeRatesAPI starts an OpenExchangeRates-style API and counts the
+// requests it serves. Requests fail while failing is set.
+func newFakeRatesAPI(t *testing.T, failing *atomic.Bool) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if failing != nil && failing.Load() {
+			http.Error(w, "down", http.StatusBadGateway)
+			return
+		}
+		if r.URL.Query().Get("app_id") != "key-1" {
+			http.Error(w, "missing app_id", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprint(w, `{"base":"usd","timestamp":1791500400,"rates":{"GBP":0.8,"EUR":0.9,"JPY":150}}`)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func TestExchangeRateService_Rates(t *testing.T) {
+	server, requests := newFakeRatesAPI(t, nil)
+	service := NewExchangeRateService(server.URL+"/latest.json", "key-1")
+
+	rates, err := service.Rates(context.Background())
+	if err != nil {
+		t.Fatalf("Rates() error = %v", err)
+	}
+	if rates.Base != "USD" || rates.Rates["GBP"] != 0.8 || !rates.Timestamp.Equal(time.Unix(1791500400, 0)) {
+		t.Fatalf("rates = %+v", rates)
+	}
+	if rates.Source != strings.TrimPrefix(server.URL, "http://") {
+		t.Fatalf("Source = %q, want the API host", rates.Source)
+	}
+
+	// Rates are cached for a day
+	if _, err := service.Rates(context.Background()); err != nil || requests.Load() != 1 {
+		t.Fatalf("second Rates() made %d requests, err = %v; want the cached rates", requests.Load(), err)
+	}
+	service.fetchedAt = time.Now().Add(-25 * time.Hour)
+	if _, err := service.Rates(context.Background()); err != nil || requests.Load() != 2 {
+		t.Fatalf("Rates() after a day made %d requests, err = %v; want a refresh", requests.Load(), err)
+	}
+}
+
+func TestExchangeRateService_Rates_ProviderDown(t *testing.T) {
+	var failing atomic.Bool
+	server, _ := newFakeRatesAPI(t, &failing)
+	service := NewExchangeRateService(server.URL, "key-1")
+
+	failing.Store(true)
+	if _, err := service.Rates(context.Background()); err == nil || !strings.Contains(err.Error(), "status 502") {
+		t.Fatalf("Rates() with nothing cached error = %v, want the provider's status", err)
+	}
+
+	failing.Store(false)
+	fresh, err := service.Rates(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Stale rates are served while the provider is down
+	failing.Store(true)
+	service.fetchedAt = time.Now().Add(-25 * time.Hour)
+	stale, err := service.Rates(context.Background())
+	if err != nil || stale != fresh {
+		t.Fatalf("Rates() = %v, %v; want the stale rates", stale, err)
+	}
+
+	if _, err := NewExchangeRateService("", "").Rates(context.Background()); err == nil {
+		t.Fatal("Rates() without an API URL succeeded")
+	}
+}
+
+func TestExchangeRates_Convert(t *testing.T) {
+	rates := &ExchangeRates{Base: "USD", Rates: map[string]float64{"GBP": 0.8, "EUR": 0.9, "XXX": 0}}
+
+	tests := []struct {
+		amount   float64
+		from, to string
+		want     float64
+		wantErr  bool
+	}{
+		{amount: 100, from: "USD", to: "GBP", want: 80},
+		{amount: 80, from: "gbp", to: "usd", want: 100},
+		{amount: 80, from: "GBP", to: "EUR", want: 90},
+		{amount: 50, from: "CHF", to: "chf", want: 50},
+		{amount: 100, from: "CHF", to: "USD", wantErr: true},
+		{amount: 100, from: "USD", to: "XXX", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := rates.Convert(tt.amount, tt.from, tt.to)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Convert(%v, %s, %s) error = %v, wantErr %v", tt.amount, tt.from, tt.to, err, tt.wantErr)
+			continue
+		}
+		if math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("Convert(%v, %s, %s) = %v, want %v", tt.amount, tt.from, tt.to, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package services
+
+import (
+	"context"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"golang.org/x/oauth2/google"
+	"google.golang.org/api/calendar/v3"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/api/option"
+)
+
+// ErrInvalidServiceAccount is returned for service account key files that
+// cannot be used to sign in to Google
+var ErrInvalidServiceAccount = errors.New("invalid Google service account key")
+
+// errCalendarDisabled is returned by calls made with no service account
+var errCalendarDisabled = errors.New("google calendar is not configured")
+
+// GoogleCalendarService adds interviews to a Google Calendar, signing in as a
+// service account. Events carry the same UID as the emailed invitation, so
+// calendars that receive both show one event.
+type GoogleCalendarService struct {
+	calendarID string
+	events     *calendar.EventsService
+}
+
+// NewGoogleCalendarService creates a service adding events to calendarID
+// with the service account key in serviceAccountFile. delegatedUser, when
+// set, is the Workspace user the service account acts for, which Google
+// requires before a service account may invite attendees. With no key file
+// the service is disabled. opts are passed on to the Calendar client, e.g.
+// to point it at another endpoint.
+func NewGoogleCalendarService(ctx context.Context, serviceAccountFile, calendarID, delegatedUser string, opts ...option.ClientOption) (*GoogleCalendarService, error) {
+	s := &GoogleCalendarService{calendarID: calendarID}
+	if serviceAccountFile == "" {
+		return s, nil
+	}
+
+	data, err := os.ReadFile(serviceAccountFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Google service account key: %w", err)
+	}
+	jwtConfig, err := google.JWTConfigFromJSON(data, calendar.CalendarEventsScope)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidServiceAccount, err)
+	}
+	// The key is only parsed when a token is first fetched, so a key that is
+	// not even PEM is caught here instead
+	if block, _ := pem.Decode(jwtConfig.PrivateKey); block == nil {
+		return nil, fmt.Errorf("%w: private_key is not PEM encoded", ErrInvalidServiceAccount)
+	}
+	jwtConfig.Subject = delegatedUser
+
+	opts = append([]option.ClientOption{option.WithTokenSource(jwtConfig.TokenSource(ctx))}, opts...)
+	service, err := calendar.NewService(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Google Calendar client: %w", err)
+	}
+
+	s.events = calendar.NewEventsService(service)
+	return s, nil
+}
+
+// Enabled reports whether a service account is configured
+func (s *GoogleCalendarService) Enabled() bool {
+	return s.events != nil
+}
+
+// CreateInterviewEvent adds event to the calendar with its attendees and
+// returns the Google Calendar event ID. Google sends no invitations of its
+// own, since attendees already get one by email.
+func (s *GoogleCalendarService) CreateInterviewEvent(ctx context.Context, event InterviewEvent) (string, error) {
+	if !s.Enabled() {
+		return "", errCalendarDisabled
+	}
+
+	body := &calendar.Event{
+		ICalUID:     event.UID,
+		Summary:     event.Summary,
+		Description: event.Description,
+		Location:    event.Location,
+		Start:       &calendar.EventDateTime{DateTime: event.Start.UTC().Format(time.RFC3339)},
+		End:         &calendar.EventDateTime{DateTime: event.Start.Add(event.Duration).UTC().Format(time.RFC3339)},
+	}
+	for _, attendee := range event.Attendees {
+		body.Attendees = append(body.Attendees, &calendar.EventAttendee{Email: attendee.Email, DisplayName: attendee.Name})
+	}
+
+	created, err := s.events.Insert(s.calendarID, body).SendUpdates("none").Context(ctx).Do()
+	if err != nil {
+		return "", fmt.Errorf("failed to create calendar event: %w", err)
+	}
+	return created.Id, nil
+}
+
+// CancelEvent removes an event from the calendar. An event that is already
+// gone is not an error.
+func (s *GoogleCalendarService) CancelEvent(ctx context.Context, eventID string) error {
+	if !s.Enabled() {
+		return errCalendarDisabled
+	}
+
+	err := s.events.Delete(s.calendarID, eventID).SendUpdates("none").Context(ctx).Do()
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) && (apiErr.Code == http.StatusNotFound || apiErr.Code == http.StatusGone) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to cancel calendar event: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,221 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/api/option"
+)
+
+// fakeGoogle serves the OAuth2 token endpoint and the Calendar events API
+// for one calendar, recording the events inserted
+type fakeGoogle struct {
+	t *testing.T
+
+	mu         sync.Mutex
+	assertions []map[string]interface{}
+	inserted   []map[string]interface{}
+	deleted    []string
+}
+
+func (f *fakeGoogle) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if r.URL.Path == "/token" {
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("grant_type") != "urn:ietf:params:oauth:grant-type:jwt-bearer" {
+			http.Error(w, `{"error":"unsupported_grant_type"}`, http.StatusBadRequest)
+			return
+		}
+		parts := strings.Split(r.PostForm.Get("assertion"), ".")
+		claims := make(map[string]interface{})
+		if payload, err := base64.RawURLEncoding.DecodeString(parts[1]); err != nil || json.Unmarshal(payload, &claims) != nil {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusBadRequest)
+			return
+		}
+		f.assertions = append(f.assertions, claims)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"test-access-token","token_type":"Bearer","expires_in":3600}`))
+		return
+	}
+
+	if r.Header.Get("Authorization") != "Bearer test-access-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":{"code":401,"message":"Request had invalid authentication credentials."}}`))
+		return
+	}
+	if r.URL.Query().Get("sendUpdates") != "none" {
+		f.t.Errorf("%s %s: sendUpdates = %q, want none", r.Method, r.URL.Path, r.URL.Query().Get("sendUpdates"))
+	}
+
+	const events = "/calendars/interviews@example.com/events"
+	switch {
+	case r.Method == http.MethodPost && r.URL.Path == events:
+		var event map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&event)
+		f.inserted = append(f.inserted, event)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id":"evt-123","status":"confirmed"}`))
+	case r.Method == http.MethodDelete && r.URL.Path == events+"/evt-123":
+		f.deleted = append(f.deleted, "evt-123")
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete && r.URL.Path == events+"/evt-gone":
+		w.WriteHeader(http.StatusGone)
+		w.Write([]byte(`{"error":{"code":410,"message":"Resource has been deleted"}}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"code":404,"message":"Not Found"}}`))
+	}
+}
+
+// newFakeGoogleCalendar returns a service signed in with a fresh service
+// account key whose token endpoint, like the Calendar API, is a fake server
+func newFakeGoogleCalendar(t *testing.T) (*GoogleCalendarService, *fakeGoogle) {
+	t.Helper()
+	fake := &fakeGoogle{t: t}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account, _ := json.Marshal(map[string]string{
+		"type":           "service_account",
+		"client_email":   "scheduler@hr-recruiting.iam.gserviceaccount.com",
+		"private_key_id": "key-1",
+		"private_key":    string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":      server.URL + "/token",
+	})
+	keyFile := filepath.Join(t.TempDir(), "service-account.json")
+	if err := os.WriteFile(keyFile, account, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	service, err := NewGoogleCalendarService(context.Background(), keyFile, "interviews@example.com", "recruiting@example.com",
+		option.WithEndpoint(server.URL+"/"))
+	if err != nil {
+		t.Fatalf("NewGoogleCalendarService() error = %v", err)
+	}
+	return service, fake
+}
+
+func TestGoogleCalendarService_CreateInterviewEvent(t *testing.T) {
+	service, fake := newFakeGoogleCalendar(t)
+	if !service.Enabled() {
+		t.Fatal("service with a key file is not enabled")
+	}
+
+	start := time.Date(2026, 3, 2, 15, 0, 0, 0, time.FixedZone("CET", 3600))
+	eventID, err := service.CreateInterviewEvent(context.Background(), InterviewEvent{
+		UID:      "interview-42@hr-recruiting",
+		Summary:  "Interview: Ada Lovelace for Backend Engineer",
+		Location: "Room 4",
+		Start:    start,
+		Duration: 45 * time.Minute,
+		Attendees: []Attendee{
+			{Name: "Ada Lovelace", Email: "ada@example.com"},
+			{Name: "Grace Hopper", Email: "grace@example.com"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("CreateInterviewEvent() error = %v", err)
+	}
+	if eventID != "evt-123" {
+		t.Fatalf("event ID = %q, want evt-123", eventID)
+	}
+
+	if len(fake.assertions) != 1 {
+		t.Fatalf("token endpoint called %d times, want 1", len(fake.assertions))
+	}
+	claims := fake.assertions[0]
+	if claims["iss"] != "scheduler@hr-recruiting.iam.gserviceaccount.com" || claims["sub"] != "recruiting@example.com" ||
+		claims["scope"] != "https://www.googleapis.com/auth/calendar.events" {
+		t.Fatalf("service account assertion claims = %v", claims)
+	}
+
+	event := fake.inserted[0]
+	if event["iCalUID"] != "interview-42@hr-recruiting" || event["summary"] != "Interview: Ada Lovelace for Backend Engineer" {
+		t.Fatalf("inserted event = %v", event)
+	}
+	if got := event["start"].(map[string]interface{})["dateTime"]; got != "2026-03-02T14:00:00Z" {
+		t.Fatalf("start = %v, want 2026-03-02T14:00:00Z", got)
+	}
+	if got := event["end"].(map[string]interface{})["dateTime"]; got != "2026-03-02T14:45:00Z" {
+		t.Fatalf("end = %v, want 2026-03-02T14:45:00Z", got)
+	}
+	attendees := event["attendees"].([]interface{})
+	if len(attendees) != 2 || attendees[0].(map[string]interface{})["email"] != "ada@example.com" ||
+		attendees[1].(map[string]interface{})["displayName"] != "Grace Hopper" {
+		t.Fatalf("attendees = %v", attendees)
+	}
+
+	// The access token is reused for later calls
+	if _, err := service.CreateInterviewEvent(context.Background(), InterviewEvent{Start: start}); err != nil {
+		t.Fatalf("second CreateInterviewEvent() error = %v", err)
+	}
+	if len(fake.assertions) != 1 {
+		t.Fatalf("token endpoint called %d times, want the token cached", len(fake.assertions))
+	}
+}
+
+func TestGoogleCalendarService_CancelEvent(t *testing.T) {
+	service, fake := newFakeGoogleCalendar(t)
+	ctx := context.Background()
+
+	if err := service.CancelEvent(ctx, "evt-123"); err != nil {
+		t.Fatalf("CancelEvent() error = %v", err)
+	}
+	if len(fake.deleted) != 1 {
+		t.Fatalf("deleted %v, want evt-123", fake.deleted)
+	}
+
+	// Events already removed from the calendar are not an error
+	if err := service.CancelEvent(ctx, "evt-gone"); err != nil {
+		t.Fatalf("CancelEvent(gone) error = %v", err)
+	}
+	if err := service.CancelEvent(ctx, "evt-missing"); err != nil {
+		t.Fatalf("CancelEvent(missing) error = %v", err)
+	}
+}
+
+func TestGoogleCalendarService_Disabled(t *testing.T) {
+	service, err := NewGoogleCalendarService(context.Background(), "", "primary", "")
+	if err != nil {
+		t.Fatalf("NewGoogleCalendarService() error = %v", err)
+	}
+	if service.Enabled() {
+		t.Fatal("service without a key file is enabled")
+	}
+	if _, err := service.CreateInterviewEvent(context.Background(), InterviewEvent{}); !errors.Is(err, errCalendarDisabled) {
+		t.Fatalf("CreateInterviewEvent() error = %v, want errCalendarDisabled", err)
+	}
+}
+
+func TestNewGoogleCalendarService_InvalidKey(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "service-account.json")
+	if err := os.WriteFile(keyFile, []byte(`{"type":"service_account","client_email":"x@example.com","private_key":"not a key"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := NewGoogleCalendarService(context.Background(), keyFile, "primary", ""); !errors.Is(err, ErrInvalidServiceAccount) {
+		t.Fatalf("NewGoogleCalendarService() error = %v, want ErrInvalidServiceAccount", err)
+	}
+}
@@ -0,0 +1,24 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// decodeField decodes the named field of a GraphQL response's data into v,
+// leaving v unchanged and returning false when the field is null
+func decodeField(data interface{}, field string, v interface{}) (bool, error) {
+	fields, _ := data.(map[string]interface{})
+	if fields[field] == nil {
+		return false, nil
+	}
+
+	raw, err := json.Marshal(fields[field])
+	if err != nil {
+		return false, err
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return false, fmt.Errorf("invalid %s in Hub-HRMS response: %w", field, err)
+	}
+	return true, nil
+}
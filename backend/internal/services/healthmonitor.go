@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+const (
+	// HealthProbeInterval is how often the health monitor probes Hub-HRMS
+	HealthProbeInterval = 30 * time.Second
+	// DegradedLatency is the p99 probe latency past which Hub-HRMS is
+	// reported as degraded
+	DegradedLatency = 5 * time.Second
+
+	// healthProbeWindow is how many recent probe durations are kept
+	healthProbeWindow = 100
+	// healthProbeTimeout bounds a single probe
+	healthProbeTimeout = 10 * time.Second
+)
+
+// HubHRMSHealth is the health monitor's view of Hub-HRMS
+type HubHRMSHealth struct {
+	Healthy             bool      `json:"healthy"`
+	P50Ms               float64   `json:"p50ms"`
+	P95Ms               float64   `json:"p95ms"`
+	P99Ms               float64   `json:"p99ms"`
+	LastCheckAt         time.Time `json:"lastCheckAt"`
+	ConsecutiveFailures int       `json:"consecutiveFailures"`
+	LastError           string    `json:"lastError,omitempty"`
+}
+
+// Degraded reports whether Hub-HRMS answers, but too slowly
+func (h HubHRMSHealth) Degraded() bool {
+	return h.P99Ms > float64(DegradedLatency.Milliseconds())
+}
+
+// HealthMonitor probes Hub-HRMS in the background and keeps latency
+// percentiles over the most recent probes
+type HealthMonitor struct {
+	*periodicTask
+
+	client    *gateway.HubHRMSClient
+	startedAt time.Time
+
+	mu        sync.Mutex
+	durations [healthProbeWindow]time.Duration
+	count     int
+	next      int
+	state     HubHRMSHealth
+}
+
+// NewHealthMonitor creates a monitor probing Hub-HRMS every interval
+func NewHealthMonitor(client *gateway.HubHRMSClient, interval time.Duration) *HealthMonitor {
+	m := &HealthMonitor{client: client, startedAt: time.Now()}
+	m.periodicTask = newPeriodicTask(interval, m.probe)
+	return m
+}
+
+// Start probes once straight away, then every interval until Shutdown
+func (m *HealthMonitor) Start() {
+	m.periodicTask.Start()
+	go m.probe(context.Background())
+}
+
+// Uptime is how long the monitor has been running
+func (m *HealthMonitor) Uptime() time.Duration {
+	return time.Since(m.startedAt)
+}
+
+// Snapshot returns the result of the latest probe and the latency
+// percentiles over the recent ones
+func (m *HealthMonitor) Snapshot() HubHRMSHealth {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	state := m.state
+	recent := append([]time.Duration(nil), m.durations[:m.count]...)
+	state.P50Ms = percentileMs(recent, 50)
+	state.P95Ms = percentileMs(recent, 95)
+	state.P99Ms = percentileMs(recent, 99)
+	return state
+}
+
+func (m *HealthMonitor) probe(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, healthProbeTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := m.client.Health(ctx)
+	m.record(start, time.Since(start), err)
+	if err != nil {
+		slog.Warn("hub-hrms health probe failed", "error", err)
+	}
+}
+
+// record adds a probe's duration to the window and updates the state
+func (m *HealthMonitor) record(at time.Time, duration time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.durations[m.next] = duration
+	m.next = (m.next + 1) % healthProbeWindow
+	if m.count < healthProbeWindow {
+		m.count++
+	}
+
+	m.state.LastCheckAt = at.UTC()
+	if err != nil {
+		m.state.Healthy = false
+		m.state.ConsecutiveFailures++
+		m.state.LastError = err.Error()
+		return
+	}
+	m.state.Healthy = true
+	m.state.ConsecutiveFailures = 0
+	m.state.LastError = ""
+}
+
+// percentileMs returns the nearest-rank percentile of durations in
+// milliseconds, or 0 when there are none
+func percentileMs(durations []time.Duration, percentile float64) float64 {
+	if len(durations) == 0 {
+		return 0
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+
+	rank := int(math.Ceil(percentile / 100 * float64(len(durations))))
+	if rank < 1 {
+		rank = 1
+	}
+	return float64(durations[rank-1].Microseconds()) / 1000
+}
@@ -0,0 +1,92 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// msDurations converts milliseconds to durations
+func msDurations(ms ...int) []time.Duration {
+	durations := make([]time.Duration, len(ms))
+	for i, m := range ms {
+		durations[i] = time.Duration(m) * time.Millisecond
+	}
+	return durations
+}
+
+func TestPercentileMs(t *testing.T) {
+	// 1ms to 100ms, shuffled
+	hundred := make([]int, 100)
+	for i := range hundred {
+		hundred[i] = (i*37)%100 + 1
+	}
+
+	tests := []struct {
+		name       string
+		durations  []time.Duration
+		percentile float64
+		want       float64
+	}{
+		{name: "none", percentile: 50, want: 0},
+		{name: "one probe", durations: msDurations(120), percentile: 99, want: 120},
+		{name: "p50 of a hundred", durations: msDurations(hundred...), percentile: 50, want: 50},
+		{name: "p95 of a hundred", durations: msDurations(hundred...), percentile: 95, want: 95},
+		{name: "p99 of a hundred", durations: msDurations(hundred...), percentile: 99, want: 99},
+		{name: "p50 of four rounds up", durations: msDurations(40, 10, 30, 20), percentile: 50, want: 20},
+		{name: "p99 of four is the slowest", durations: msDurations(40, 10, 30, 20), percentile: 99, want: 40},
+		{name: "sub-millisecond", durations: []time.Duration{1500 * time.Microsecond}, percentile: 50, want: 1.5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := percentileMs(tt.durations, tt.percentile); got != tt.want {
+				t.Fatalf("percentileMs(%v) = %v, want %v", tt.percentile, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHealthMonitor_Record(t *testing.T) {
+	m := NewHealthMonitor(nil, time.Hour)
+	at := time.Date(2026, 10, 16, 9, 0, 0, 0, time.UTC)
+
+	if state := m.Snapshot(); state.Healthy || state.P99Ms != 0 {
+		t.Fatalf("snapshot before any probe = %+v, want nothing known", state)
+	}
+
+	// 90 fast probes and 10 slow ones: only p99 sees the slow tail
+	for i := 0; i < 90; i++ {
+		m.record(at, 100*time.Millisecond, nil)
+	}
+	for i := 0; i < 10; i++ {
+		m.record(at, 6*time.Second, nil)
+	}
+	state := m.Snapshot()
+	if state.P50Ms != 100 || state.P95Ms != 6000 || state.P99Ms != 6000 {
+		t.Fatalf("percentiles = %v/%v/%v, want 100/6000/6000", state.P50Ms, state.P95Ms, state.P99Ms)
+	}
+	if !state.Healthy || !state.Degraded() || !state.LastCheckAt.Equal(at) {
+		t.Fatalf("state = %+v, want healthy but degraded", state)
+	}
+
+	// The window keeps the latest 100 probes, so fast probes push the slow
+	// ones out
+	for i := 0; i < healthProbeWindow; i++ {
+		m.record(at, 200*time.Millisecond, nil)
+	}
+	if state := m.Snapshot(); state.P99Ms != 200 || state.Degraded() {
+		t.Fatalf("p99 = %v, want 200 once the slow probes left the window", state.P99Ms)
+	}
+
+	// Failures are counted until a probe succeeds
+	m.record(at, time.Second, errors.New("connection refused"))
+	m.record(at, time.Second, errors.New("connection refused"))
+	state = m.Snapshot()
+	if state.Healthy || state.ConsecutiveFailures != 2 || state.LastError != "connection refused" {
+		t.Fatalf("state = %+v, want two consecutive failures", state)
+	}
+	m.record(at, time.Second, nil)
+	if state := m.Snapshot(); !state.Healthy || state.ConsecutiveFailures != 0 || state.LastError != "" {
+		t.Fatalf("state = %+v, want recovered", state)
+	}
+}
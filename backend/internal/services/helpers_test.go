@@ -0,0 +1,50 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/SebastiaanKlippert/go-wkhtmltopdf"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// newFakeHubHRMS starts a fake Hub-HRMS answering each GraphQL request with
+// the data respond returns for it, and returns a client for it
+func newFakeHubHRMS(t *testing.T, respond func(req gateway.GraphQLRequest) interface{}) *gateway.HubHRMSClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req gateway.GraphQLRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": respond(req)})
+	}))
+	t.Cleanup(server.Close)
+
+	client := gateway.NewHubHRMSClient(server.URL, "")
+	t.Cleanup(client.Close)
+	return client
+}
+
+// echoPDFConverter stands in for wkhtmltopdf: it writes a PDF header, its
+// arguments on one line and then the HTML it was given
+const echoPDFConverter = `printf '%%PDF-1.4\n%s\n' "$*"; cat`
+
+// fakePDFConverter installs a shell script running body as the wkhtmltopdf
+// executable for the rest of the test
+func fakePDFConverter(t *testing.T, body string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "wkhtmltopdf")
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	previous := wkhtmltopdf.GetPath()
+	SetPDFConverterPath(path)
+	t.Cleanup(func() { wkhtmltopdf.SetPath(previous) })
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed data/filler_phrases.yaml
+var fillerPhrasesYAML []byte
+
+// Quality thresholds for a well-filled posting
+const (
+	minDescriptionLength = 500
+	minListItems         = 3
+)
+
+// Points awarded per criterion; they add up to 100
+const (
+	descriptionPoints      = 25
+	salaryPoints           = 20
+	benefitsPoints         = 15
+	responsibilitiesPoints = 15
+	skillsPoints           = 10
+	fillerPoints           = 15
+	fillerPenalty          = 5
+)
+
+// QualityReport scores a job posting out of 100 and explains what lowered
+// the score
+type QualityReport struct {
+	Score       int      `json:"score"`
+	Issues      []string `json:"issues"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// JobQualityScorer rates how complete and specific a job posting is. Scoring
+// is local and does not call Hub-HRMS.
+type JobQualityScorer struct {
+	phrases  []string
+	patterns []*regexp.Regexp
+}
+
+// NewJobQualityScorer creates a scorer using the built-in filler phrase list
+func NewJobQualityScorer() *JobQualityScorer {
+	var phrases []string
+	if err := yaml.Unmarshal(fillerPhrasesYAML, &phrases); err != nil {
+		panic(fmt.Sprintf("invalid filler phrase list: %v", err))
+	}
+
+	s := &JobQualityScorer{phrases: phrases}
+	for _, phrase := range phrases {
+		s.patterns = append(s.patterns, regexp.MustCompile(`(?i)\b`+regexp.QuoteMeta(phrase)+`\b`))
+	}
+	return s
+}
+
+// qualityJob holds the fields of a GetJobQuery job that affect its score
+type qualityJob struct {
+	Description string `json:"description"`
+	SalaryRange *struct {
+		Min *float64 `json:"min"`
+		Max *float64 `json:"max"`
+	} `json:"salaryRange"`
+	Requirements     []string `json:"requirements"`
+	Responsibilities []string `json:"responsibilities"`
+	Benefits         []string `json:"benefits"`
+	Skills           []string `json:"skills"`
+}
+
+// Score rates the job object returned by GetJobQuery
+func (s *JobQualityScorer) Score(job interface{}) (QualityReport, error) {
+	var posting qualityJob
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return QualityReport{}, err
+	}
+	if err := json.Unmarshal(raw, &posting); err != nil {
+		return QualityReport{}, fmt.Errorf("invalid job: %w", err)
+	}
+
+	report := QualityReport{Issues: []string{}, Suggestions: []string{}}
+	flag := func(issue, suggestion string) {
+		report.Issues = append(report.Issues, issue)
+		report.Suggestions = append(report.Suggestions, suggestion)
+	}
+
+	description := strings.TrimSpace(posting.Description)
+	if len([]rune(description)) > minDescriptionLength {
+		report.Score += descriptionPoints
+	} else {
+		flag(fmt.Sprintf("Description is %d characters long", len([]rune(description))),
+			fmt.Sprintf("Expand the description to more than %d characters covering the team, the work and how success is measured", minDescriptionLength))
+	}
+
+	if posting.SalaryRange != nil && posting.SalaryRange.Min != nil && posting.SalaryRange.Max != nil {
+		report.Score += salaryPoints
+	} else {
+		flag("No salary range", "Publish a salary range; postings with pay information attract more applicants")
+	}
+
+	for _, list := range []struct {
+		name   string
+		items  []string
+		points int
+		advice string
+	}{
+		{"benefits", posting.Benefits, benefitsPoints, "List the benefits offered, such as leave, health cover and learning budget"},
+		{"responsibilities", posting.Responsibilities, responsibilitiesPoints, "Describe the day-to-day responsibilities of the role"},
+		{"required skills", posting.Skills, skillsPoints, "List the specific skills the role requires"},
+	} {
+		count := countNonEmpty(list.items)
+		switch {
+		case count >= minListItems:
+			report.Score += list.points
+		case count > 0:
+			report.Score += list.points / 2
+			flag(fmt.Sprintf("Only %d %s listed", count, list.name), list.advice)
+		default:
+			flag(fmt.Sprintf("No %s listed", list.name), list.advice)
+		}
+	}
+
+	sections := []string{posting.Description}
+	sections = append(sections, posting.Requirements...)
+	sections = append(sections, posting.Responsibilities...)
+	sections = append(sections, posting.Benefits...)
+	text := strings.Join(sections, "\n")
+
+	fillerScore := fillerPoints
+	for i, pattern := range s.patterns {
+		if !pattern.MatchString(text) {
+			continue
+		}
+		if fillerScore -= fillerPenalty; fillerScore < 0 {
+			fillerScore = 0
+		}
+		flag(fmt.Sprintf("Uses the filler phrase %q", s.phrases[i]),
+			fmt.Sprintf("Replace %q with the concrete behaviour or condition it stands for", s.phrases[i]))
+	}
+	report.Score += fillerScore
+
+	return report, nil
+}
+
+func countNonEmpty(items []string) int {
+	count := 0
+	for _, item := range items {
+		if strings.TrimSpace(item) != "" {
+			count++
+		}
+	}
+	return count
+}
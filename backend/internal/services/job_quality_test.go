@@ -0,0 +1,126 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+// wellFilledJob is a GetJobQuery job that meets every quality criterion
+func wellFilledJob() map[string]interface{} {
+	return map[string]interface{}{
+		"description": strings.Repeat("You will build the services that match candidates to open roles. ", 10),
+		"salaryRange": map[string]interface{}{"min": 70000, "max": 90000, "currency": "EUR"},
+		"responsibilities": []interface{}{
+			"Design and run the matching service",
+			"Review code and mentor two engineers",
+			"Own the on-call rota for the team",
+		},
+		"requirements": []interface{}{"Four years of backend experience"},
+		"benefits":     []interface{}{"30 days leave", "Private health cover", "Learning budget"},
+		"skills":       []interface{}{"Go", "PostgreSQL", "Kubernetes"},
+	}
+}
+
+func TestJobQualityScorer_Score(t *testing.T) {
+	scorer := NewJobQualityScorer()
+
+	t.Run("minimal job", func(t *testing.T) {
+		report, err := scorer.Score(map[string]interface{}{"title": "Engineer", "description": "Join us."})
+		if err != nil {
+			t.Fatalf("Score() error = %v", err)
+		}
+		// Only the filler criterion is met
+		if report.Score != fillerPoints {
+			t.Fatalf("Score = %d, want %d", report.Score, fillerPoints)
+		}
+		if len(report.Issues) != 5 || len(report.Suggestions) != len(report.Issues) {
+			t.Fatalf("issues = %v, suggestions = %v", report.Issues, report.Suggestions)
+		}
+	})
+
+	t.Run("well-filled job", func(t *testing.T) {
+		report, err := scorer.Score(wellFilledJob())
+		if err != nil {
+			t.Fatalf("Score() error = %v", err)
+		}
+		if report.Score != 100 || len(report.Issues) != 0 {
+			t.Fatalf("report = %+v, want 100 with no issues", report)
+		}
+	})
+
+	t.Run("short lists earn half", func(t *testing.T) {
+		job := wellFilledJob()
+		job["benefits"] = []interface{}{"Pension", " "}
+		report, _ := scorer.Score(job)
+		if report.Score != 100-benefitsPoints+benefitsPoints/2 || report.Issues[0] != "Only 1 benefits listed" {
+			t.Fatalf("report = %+v", report)
+		}
+	})
+
+	t.Run("open-ended salary range", func(t *testing.T) {
+		job := wellFilledJob()
+		job["salaryRange"] = map[string]interface{}{"min": 70000}
+		if report, _ := scorer.Score(job); report.Score != 100-salaryPoints {
+			t.Fatalf("Score = %d, want %d", report.Score, 100-salaryPoints)
+		}
+	})
+
+	t.Run("not a job", func(t *testing.T) {
+		if _, err := scorer.Score([]string{"job"}); err == nil {
+			t.Fatal("Score() accepted a list")
+		}
+	})
+}
+
+func TestJobQualityScorer_FillerPhrases(t *testing.T) {
+	scorer := NewJobQualityScorer()
+	if len(scorer.phrases) == 0 {
+		t.Fatal("no filler phrases loaded from the built-in list")
+	}
+
+	tests := []struct {
+		name        string
+		description string
+		benefits    []interface{}
+		wantPhrases []string
+	}{
+		{name: "none", description: "You will own the billing service."},
+		{name: "one", description: "We need a Self-Starter for billing.", wantPhrases: []string{"self-starter"}},
+		{name: "case and spacing of the phrase", description: "HIT THE GROUND RUNNING on day one.", wantPhrases: []string{"hit the ground running"}},
+		{name: "inside other words", description: "Our steam player cabinet is a synergyless zone."},
+		{name: "found in lists", description: "Billing.", benefits: []interface{}{"A team that is like a family"}, wantPhrases: []string{"like a family"}},
+		{
+			name:        "penalty is capped",
+			description: "A team player and self-starter, a go-getter who can wear many hats and think outside the box.",
+			wantPhrases: []string{"self-starter", "team player", "go-getter", "wear many hats", "think outside the box"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job := wellFilledJob()
+			job["description"] = tt.description + strings.Repeat(" Concrete detail about the role.", 20)
+			if tt.benefits != nil {
+				job["benefits"] = append(tt.benefits, "Pension", "Learning budget")
+			}
+			report, err := scorer.Score(job)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var found []string
+			for _, issue := range report.Issues {
+				if phrase, ok := strings.CutPrefix(issue, "Uses the filler phrase "); ok {
+					found = append(found, strings.Trim(phrase, `"`))
+				}
+			}
+			if strings.Join(found, ",") != strings.Join(tt.wantPhrases, ",") {
+				t.Fatalf("filler phrases found = %v, want %v", found, tt.wantPhrases)
+			}
+
+			wantScore := 100 - min(len(tt.wantPhrases)*fillerPenalty, fillerPoints)
+			if report.Score != wantScore {
+				t.Fatalf("Score = %d, want %d", report.Score, wantScore)
+			}
+		})
+	}
+}
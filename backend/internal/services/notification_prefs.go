@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// NotificationPreferenceTTL is how long a user's preferences are cached
+const NotificationPreferenceTTL = 5 * time.Minute
+
+// NotificationPreference records which emails a recruiter's actions send
+type NotificationPreference struct {
+	StatusChanges      bool `json:"statusChanges"`
+	NewApplications    bool `json:"newApplications"`
+	ScoreReady         bool `json:"scoreReady"`
+	InterviewReminders bool `json:"interviewReminders"`
+}
+
+// DefaultNotificationPreference enables every notification, for users who
+// have not chosen otherwise
+func DefaultNotificationPreference() NotificationPreference {
+	return NotificationPreference{
+		StatusChanges:      true,
+		NewApplications:    true,
+		ScoreReady:         true,
+		InterviewReminders: true,
+	}
+}
+
+// NotificationType is a category of email a preference controls
+type NotificationType int
+
+// Notification types, one per preference
+const (
+	NotifyStatusChanges NotificationType = iota
+	NotifyNewApplications
+	NotifyScoreReady
+	NotifyInterviewReminders
+)
+
+// Allows reports whether emails of type t are enabled
+func (p NotificationPreference) Allows(t NotificationType) bool {
+	switch t {
+	case NotifyStatusChanges:
+		return p.StatusChanges
+	case NotifyNewApplications:
+		return p.NewApplications
+	case NotifyScoreReady:
+		return p.ScoreReady
+	case NotifyInterviewReminders:
+		return p.InterviewReminders
+	}
+	return true
+}
+
+// cachedPreference holds a user's preferences until expiresAt
+type cachedPreference struct {
+	preference NotificationPreference
+	expiresAt  time.Time
+}
+
+// NotificationPreferenceStore reads and writes preferences in Hub-HRMS,
+// caching reads so checking them before each email stays cheap
+type NotificationPreferenceStore struct {
+	client *gateway.HubHRMSClient
+	ttl    time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedPreference
+}
+
+// NewNotificationPreferenceStore creates a store caching preferences for ttl
+func NewNotificationPreferenceStore(client *gateway.HubHRMSClient, ttl time.Duration) *NotificationPreferenceStore {
+	return &NotificationPreferenceStore{
+		client: client,
+		ttl:    ttl,
+		cache:  make(map[string]cachedPreference),
+	}
+}
+
+// Get returns a user's preferences. Users without saved preferences get
+// DefaultNotificationPreference.
+func (s *NotificationPreferenceStore) Get(ctx context.Context, userID string) (NotificationPreference, error) {
+	s.mu.Lock()
+	cached, ok := s.cache[userID]
+	s.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.preference, nil
+	}
+
+	resp, err := s.client.Query(ctx, gateway.GetNotificationPreferencesQuery, map[string]interface{}{
+		"userId": userID,
+	})
+	if err != nil {
+		return NotificationPreference{}, fmt.Errorf("failed to fetch notification preferences: %w", err)
+	}
+
+	preference := DefaultNotificationPreference()
+	if _, err := decodeField(resp.Data, "notificationPreferences", &preference); err != nil {
+		return NotificationPreference{}, err
+	}
+
+	s.store(userID, preference)
+	return preference, nil
+}
+
+// Update saves a user's preferences
+func (s *NotificationPreferenceStore) Update(ctx context.Context, userID string, preference NotificationPreference) (NotificationPreference, error) {
+	resp, err := s.client.Mutate(ctx, gateway.UpdateNotificationPreferencesMutation, map[string]interface{}{
+		"userId": userID,
+		"input":  preference,
+	})
+	if err != nil {
+		return NotificationPreference{}, fmt.Errorf("failed to update notification preferences: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return NotificationPreference{}, fmt.Errorf("failed to update notification preferences: %s", resp.Errors[0].Message)
+	}
+
+	if _, err := decodeField(resp.Data, "updateNotificationPreferences", &preference); err != nil {
+		return NotificationPreference{}, err
+	}
+
+	s.store(userID, preference)
+	return preference, nil
+}
+
+func (s *NotificationPreferenceStore) store(userID string, preference NotificationPreference) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache[userID] = cachedPreference{preference: preference, expiresAt: time.Now().Add(s.ttl)}
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ChatEvent identifies what happened to an application in a chat
+// notification
+type ChatEvent string
+
+// Chat notification events
+const (
+	ChatNewApplication     ChatEvent = "new_application"
+	ChatStatusChanged      ChatEvent = "status_changed"
+	ChatInterviewScheduled ChatEvent = "interview_scheduled"
+	ChatAssigned           ChatEvent = "assigned"
+)
+
+// ApplicationNotification describes an application event posted to chat
+type ApplicationNotification struct {
+	Event         ChatEvent
+	ApplicationID string
+	CandidateName string
+	JobTitle      string
+	Status        string
+	// InterviewAt is set for ChatInterviewScheduled
+	InterviewAt time.Time
+	// Recruiter is set for ChatAssigned
+	Recruiter string
+	// URL links to the application in the recruiting app
+	URL string
+}
+
+// Notifier posts application events to a chat service. Notify must not
+// block; delivery failures are logged, not returned.
+type Notifier interface {
+	Notify(notification ApplicationNotification)
+}
+
+// NotificationBroadcaster fans application events out to every configured
+// chat notifier
+type NotificationBroadcaster struct {
+	notifiers []Notifier
+}
+
+// NewNotificationBroadcaster creates a broadcaster for notifiers. With none,
+// Notify does nothing.
+func NewNotificationBroadcaster(notifiers ...Notifier) *NotificationBroadcaster {
+	return &NotificationBroadcaster{notifiers: notifiers}
+}
+
+// Notify passes notification to every notifier
+func (b *NotificationBroadcaster) Notify(notification ApplicationNotification) {
+	for _, notifier := range b.notifiers {
+		notifier.Notify(notification)
+	}
+}
+
+// chatStatuses is the set of statuses whose changes a chat notifier posts
+type chatStatuses map[string]bool
+
+func newChatStatuses(statuses []string) chatStatuses {
+	set := make(chatStatuses, len(statuses))
+	for _, status := range statuses {
+		if status = strings.ToUpper(strings.TrimSpace(status)); status != "" {
+			set[status] = true
+		}
+	}
+	return set
+}
+
+// wants reports whether notification should be posted. New applications,
+// interviews and assignments always are; status changes only for the listed
+// statuses.
+func (s chatStatuses) wants(notification ApplicationNotification) bool {
+	if notification.Event == ChatStatusChanged {
+		return s[strings.ToUpper(notification.Status)]
+	}
+	return true
+}
+
+// chatFact is one labelled value shown in a chat message
+type chatFact struct {
+	Title string
+	Value string
+}
+
+// chatSummary returns the headline, one-line fallback text and facts shared
+// by every chat message for notification
+func chatSummary(notification ApplicationNotification) (string, string, []chatFact) {
+	facts := []chatFact{
+		{Title: "Candidate", Value: notification.CandidateName},
+		{Title: "Job", Value: notification.JobTitle},
+	}
+
+	switch notification.Event {
+	case ChatNewApplication:
+		return "New application",
+			fmt.Sprintf("%s applied for %s", notification.CandidateName, notification.JobTitle),
+			facts
+	case ChatInterviewScheduled:
+		when := notification.InterviewAt.UTC().Format(InterviewDateFormat)
+		return "Interview scheduled",
+			fmt.Sprintf("Interview with %s for %s on %s", notification.CandidateName, notification.JobTitle, when),
+			append(facts, chatFact{Title: "Interview", Value: when})
+	case ChatAssigned:
+		return "Application assigned",
+			fmt.Sprintf("%s's application for %s was assigned to %s", notification.CandidateName, notification.JobTitle, notification.Recruiter),
+			append(facts, chatFact{Title: "Recruiter", Value: notification.Recruiter})
+	default:
+		return "Application moved to " + notification.Status,
+			fmt.Sprintf("%s moved to %s for %s", notification.CandidateName, notification.Status, notification.JobTitle),
+			append(facts, chatFact{Title: "Status", Value: notification.Status})
+	}
+}
+
+// postChatMessage sends a JSON message to a chat webhook, which answers with
+// a 2xx status when it is accepted
+func postChatMessage(ctx context.Context, client *http.Client, webhookURL string, message interface{}) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhookURL, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
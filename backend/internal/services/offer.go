@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidOfferTerms is returned for offers missing terms the letter states
+var ErrInvalidOfferTerms = errors.New("invalid offer terms")
+
+// CandidateData identifies the candidate an offer letter is addressed to
+type CandidateData struct {
+	FirstName string
+	LastName  string
+	Email     string
+}
+
+// JobData describes the position being offered
+type JobData struct {
+	Title          string
+	Department     string
+	Location       string
+	EmploymentType string
+}
+
+// OfferTerms are the terms stated in an offer letter
+type OfferTerms struct {
+	StartDate      time.Time
+	Salary         float64
+	Currency       string
+	Benefits       []string
+	SignatoryName  string
+	SignatoryTitle string
+}
+
+// offerLetterTimeout bounds converting an offer letter to PDF
+const offerLetterTimeout = 30 * time.Second
+
+// GenerateOfferLetterPDF renders an offer letter for candidate from its HTML
+// template and converts it to PDF
+func GenerateOfferLetterPDF(candidate CandidateData, job JobData, offer OfferTerms) ([]byte, error) {
+	switch {
+	case strings.TrimSpace(candidate.FirstName) == "":
+		return nil, fmt.Errorf("%w: candidate name is required", ErrInvalidOfferTerms)
+	case strings.TrimSpace(job.Title) == "":
+		return nil, fmt.Errorf("%w: job title is required", ErrInvalidOfferTerms)
+	case offer.StartDate.IsZero():
+		return nil, fmt.Errorf("%w: start date is required", ErrInvalidOfferTerms)
+	case offer.Salary <= 0:
+		return nil, fmt.Errorf("%w: salary must be positive", ErrInvalidOfferTerms)
+	case len(offer.Currency) != 3:
+		return nil, fmt.Errorf("%w: currency must be a three-letter code", ErrInvalidOfferTerms)
+	case strings.TrimSpace(offer.SignatoryName) == "":
+		return nil, fmt.Errorf("%w: signatory name is required", ErrInvalidOfferTerms)
+	}
+
+	startDate := offer.StartDate.Format("January 2, 2006")
+	salary := strings.ToUpper(offer.Currency) + " " + formatAmount(offer.Salary)
+
+	ctx, cancel := context.WithTimeout(context.Background(), offerLetterTimeout)
+	defer cancel()
+	return renderPDF(ctx, "offer_letter.html", map[string]interface{}{
+		"Date":          time.Now().Format("January 2, 2006"),
+		"CandidateName": strings.TrimSpace(candidate.FirstName + " " + candidate.LastName),
+		"Candidate":     candidate,
+		"Job":           job,
+		"StartDate":     startDate,
+		"Salary":        salary,
+		"Terms": []ReportField{
+			{Label: "Position", Value: job.Title},
+			{Label: "Department", Value: job.Department},
+			{Label: "Location", Value: job.Location},
+			{Label: "Employment type", Value: job.EmploymentType},
+			{Label: "Start date", Value: startDate},
+			{Label: "Annual salary", Value: salary},
+		},
+		"Benefits":       offer.Benefits,
+		"SignatoryName":  offer.SignatoryName,
+		"SignatoryTitle": offer.SignatoryTitle,
+	})
+}
+
+// formatAmount writes a money amount with thousands separators, keeping
+// cents only when there are some
+func formatAmount(amount float64) string {
+	total := int64(math.Round(amount * 100))
+	digits := strconv.FormatInt(total/100, 10)
+	var b strings.Builder
+	for i, d := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte(',')
+		}
+		b.WriteRune(d)
+	}
+	if cents := total % 100; cents > 0 {
+		fmt.Fprintf(&b, ".%02d", cents)
+	}
+	return b.String()
+}
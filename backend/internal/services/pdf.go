@@ -0,0 +1,78 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"embed"
+	"fmt"
+	"html/template"
+
+	"github.com/SebastiaanKlippert/go-wkhtmltopdf"
+)
+
+//go:embed templates/pdf/*.html
+var pdfTemplateFS embed.FS
+
+// pdfTemplates are the HTML documents rendered to PDF, addressed by file
+// name. Values are escaped by html/template, so candidate-supplied text is
+// safe to interpolate.
+var pdfTemplates = template.Must(template.ParseFS(pdfTemplateFS, "templates/pdf/*.html"))
+
+// SetPDFConverterPath sets the wkhtmltopdf executable used to convert HTML
+// to PDF. When it is never set, wkhtmltopdf is looked up on PATH and in the
+// WKHTMLTOPDF_PATH directory.
+func SetPDFConverterPath(path string) {
+	if path != "" {
+		wkhtmltopdf.SetPath(path)
+	}
+}
+
+// renderPDF executes the named PDF template with data and converts the
+// resulting HTML to a Letter-sized PDF
+func renderPDF(ctx context.Context, name string, data interface{}) ([]byte, error) {
+	html, err := renderPDFHTML(name, data)
+	if err != nil {
+		return nil, err
+	}
+	return htmlToPDF(ctx, html)
+}
+
+// renderPDFHTML executes the named PDF template with data
+func renderPDFHTML(name string, data interface{}) ([]byte, error) {
+	var html bytes.Buffer
+	if err := pdfTemplates.ExecuteTemplate(&html, name, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return html.Bytes(), nil
+}
+
+// htmlToPDF converts an HTML document to PDF with wkhtmltopdf. The converter
+// is killed when ctx is done, and ctx's error is returned.
+func htmlToPDF(ctx context.Context, html []byte) ([]byte, error) {
+	pdfg, err := wkhtmltopdf.NewPDFGenerator()
+	if err != nil {
+		return nil, fmt.Errorf("PDF converter unavailable: %w", err)
+	}
+	pdfg.PageSize.Set(wkhtmltopdf.PageSizeLetter)
+	pdfg.Quiet.Set(true)
+	// 0.75in margins, in millimetres
+	pdfg.MarginTop.Set(19)
+	pdfg.MarginRight.Set(19)
+	pdfg.MarginBottom.Set(19)
+	pdfg.MarginLeft.Set(19)
+
+	page := wkhtmltopdf.NewPageReader(bytes.NewReader(html))
+	page.Encoding.Set("utf-8")
+	page.DisableJavascript.Set(true)
+	page.FooterFontSize.Set(8)
+	page.FooterLeft.Set("Page [page] of [topage]")
+	pdfg.AddPage(page)
+
+	if err := pdfg.CreateContext(ctx); err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, fmt.Errorf("failed to convert HTML to PDF: %w", err)
+	}
+	return pdfg.Bytes(), nil
+}
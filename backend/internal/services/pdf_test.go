@@ -0,0 +1,222 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os/exec"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// readPDF parses data with an independent PDF reader and returns its page
+// count and plain text
+func readPDF(t *testing.T, data []byte) (int, string) {
+	t.Helper()
+	reader, err := pdf.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("output is not a readable PDF: %v", err)
+	}
+	plain, err := reader.GetPlainText()
+	if err != nil {
+		t.Fatalf("GetPlainText() error = %v", err)
+	}
+	text, err := io.ReadAll(plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return reader.NumPage(), string(text)
+}
+
+func TestGenerateOfferLetterPDF(t *testing.T) {
+	fakePDFConverter(t, echoPDFConverter)
+
+	candidate := CandidateData{FirstName: "Ada", LastName: "Lovelace", Email: "ada@example.com"}
+	job := JobData{Title: "Staff Engineer", Department: "Platform", Location: "London"}
+	offer := OfferTerms{
+		StartDate:      time.Date(2026, 11, 2, 0, 0, 0, 0, time.UTC),
+		Salary:         125000.5,
+		Currency:       "gbp",
+		Benefits:       []string{"Private healthcare", "25 days holiday & gym"},
+		SignatoryName:  "Grace Hopper",
+		SignatoryTitle: "VP Engineering",
+	}
+
+	data, err := GenerateOfferLetterPDF(candidate, job, offer)
+	if err != nil {
+		t.Fatalf("GenerateOfferLetterPDF() error = %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte("%PDF-")) {
+		t.Fatalf("output = %.40q, want the converter's PDF", data)
+	}
+	html := string(data)
+	for _, want := range []string{
+		"<title>Offer of Employment</title>",
+		"<p>Ada Lovelace</p>",
+		"<p>Dear Ada,</p>",
+		"the position of Staff Engineer in our Platform department, based in London.",
+		"will begin on November 2, 2026, with an annual base salary of GBP 125,000.50,",
+		"<th>Start date:</th><td>November 2, 2026</td>",
+		"<th>Annual salary:</th><td>GBP 125,000.50</td>",
+		"<li>25 days holiday &amp; gym</li>",
+		"<p>Grace Hopper<br>VP Engineering</p>",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("offer letter is missing %q", want)
+		}
+	}
+	if strings.Contains(html, "Employment type:") {
+		t.Error("offer letter lists an empty employment type")
+	}
+
+	for name, change := range map[string]func(*CandidateData, *JobData, *OfferTerms){
+		"no candidate name": func(c *CandidateData, _ *JobData, _ *OfferTerms) { c.FirstName = " " },
+		"no job title":      func(_ *CandidateData, j *JobData, _ *OfferTerms) { j.Title = "" },
+		"no start date":     func(_ *CandidateData, _ *JobData, o *OfferTerms) { o.StartDate = time.Time{} },
+		"no salary":         func(_ *CandidateData, _ *JobData, o *OfferTerms) { o.Salary = 0 },
+		"bad currency":      func(_ *CandidateData, _ *JobData, o *OfferTerms) { o.Currency = "pounds" },
+		"no signatory":      func(_ *CandidateData, _ *JobData, o *OfferTerms) { o.SignatoryName = "" },
+	} {
+		c, j, o := candidate, job, offer
+		change(&c, &j, &o)
+		if _, err := GenerateOfferLetterPDF(c, j, o); !errors.Is(err, ErrInvalidOfferTerms) {
+			t.Errorf("%s: error = %v, want %v", name, err, ErrInvalidOfferTerms)
+		}
+	}
+}
+
+func TestFormatAmount(t *testing.T) {
+	for amount, want := range map[float64]string{
+		0:          "0",
+		999:        "999",
+		1000:       "1,000",
+		125000.5:   "125,000.50",
+		1234567.89: "1,234,567.89",
+		99.999:     "100",
+	} {
+		if got := formatAmount(amount); got != want {
+			t.Errorf("formatAmount(%v) = %q, want %q", amount, got, want)
+		}
+	}
+}
+
+// testApplicationReport is a report with every section filled in
+func testApplicationReport() ApplicationReport {
+	return ApplicationReport{
+		CandidateName: "Zoë O'Brien",
+		Summary: []ReportField{
+			{Label: "Position", Value: "Backend Engineer (Go)"},
+			{Label: "Status", Value: "INTERVIEW"},
+		},
+		Candidate: []ReportField{
+			{Label: "Email", Value: "zoe@example.com"},
+			{Label: "Phone", Value: ""},
+		},
+		Job:          []ReportField{{Label: "Department", Value: "Platform"}},
+		Requirements: []string{"Four years of Go"},
+		CoverLetter:  "I build <script>alert(1)</script> services.\nSecond line.",
+		Score: &ReportScore{
+			Fields: []ReportField{{Label: "Overall", Value: "87"}},
+			Lists:  []ReportList{{Label: "Strengths", Items: []string{"Shipped a payments service"}}, {Label: "Concerns"}},
+		},
+		Notes: []ReportNote{
+			{Author: "Grace Hopper", Date: "Mar 1, 2026 09:00 UTC", Content: "Strong system design.", Internal: true},
+		},
+		Timeline: []ReportEvent{{Date: "Feb 27, 2026 10:00 UTC", Description: "Application submitted", PerformedBy: "Zoë O'Brien"}},
+	}
+}
+
+func TestGenerateApplicationReportPDF(t *testing.T) {
+	fakePDFConverter(t, echoPDFConverter)
+
+	data, err := GenerateApplicationReportPDF(context.Background(), testApplicationReport())
+	if err != nil {
+		t.Fatalf("GenerateApplicationReportPDF() error = %v", err)
+	}
+	args, html, _ := strings.Cut(strings.TrimPrefix(string(data), "%PDF-1.4\n"), "\n")
+	if !strings.HasPrefix(string(data), "%PDF-1.4\n") {
+		t.Fatalf("output = %.40q, want the converter's PDF", data)
+	}
+	if !strings.Contains(args, "--page-size Letter") || !strings.Contains(args, "--disable-javascript") {
+		t.Fatalf("converter arguments = %q", args)
+	}
+
+	for _, want := range []string{
+		"<title>Application Report: Zoë O&#39;Brien</title>",
+		"<th>Position:</th><td>Backend Engineer (Go)</td>",
+		"<th>Department:</th><td>Platform</td>",
+		"<li>Four years of Go</li>",
+		"I build &lt;script&gt;alert(1)&lt;/script&gt; services.\nSecond line.",
+		"<th>Overall:</th><td>87</td>",
+		"<li>Shipped a payments service</li>",
+		"Grace Hopper, Mar 1, 2026 09:00 UTC (internal)",
+		"Strong system design.",
+		"Application submitted (Zoë O&#39;Brien)",
+	} {
+		if !strings.Contains(html, want) {
+			t.Errorf("report HTML is missing %q", want)
+		}
+	}
+	for _, unwanted := range []string{"Phone:", "Concerns", "<script>"} {
+		if strings.Contains(html, unwanted) {
+			t.Errorf("report HTML contains %q", unwanted)
+		}
+	}
+
+	// Sections without content are left out
+	data, err = GenerateApplicationReportPDF(context.Background(), ApplicationReport{CandidateName: "Ada"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, heading := range []string{"Cover Letter", "AI Score", "Notes", "Timeline", "Requirements"} {
+		if strings.Contains(string(data), heading) {
+			t.Errorf("empty report has a %s section", heading)
+		}
+	}
+}
+
+func TestHTMLToPDF_Errors(t *testing.T) {
+	t.Run("converter fails", func(t *testing.T) {
+		fakePDFConverter(t, "echo 'Exit with code 1 due to network error' >&2; exit 1")
+		_, err := htmlToPDF(context.Background(), []byte("<html></html>"))
+		if err == nil || !strings.Contains(err.Error(), "network error") {
+			t.Fatalf("htmlToPDF() error = %v, want the converter's message", err)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		fakePDFConverter(t, "exec sleep 5")
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		if _, err := htmlToPDF(ctx, []byte("<html></html>")); !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("htmlToPDF() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Fatalf("converter ran for %v after the deadline", elapsed)
+		}
+	})
+}
+
+// TestGenerateApplicationReportPDF_Readable converts a report with the
+// installed wkhtmltopdf, when there is one
+func TestGenerateApplicationReportPDF_Readable(t *testing.T) {
+	if _, err := exec.LookPath("wkhtmltopdf"); err != nil {
+		t.Skip("wkhtmltopdf is not installed")
+	}
+
+	data, err := GenerateApplicationReportPDF(context.Background(), testApplicationReport())
+	if err != nil {
+		t.Fatalf("GenerateApplicationReportPDF() error = %v", err)
+	}
+	_, text := readPDF(t, data)
+	for _, want := range []string{"Application Report: Zoë O'Brien", "Backend Engineer (Go)", "Strong system design.", "Page 1 of 1"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("PDF text is missing %q", want)
+		}
+	}
+}
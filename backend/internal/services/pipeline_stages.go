@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// PipelineStageTTL is how long the pipeline configuration is cached
+const PipelineStageTTL = 5 * time.Minute
+
+// PipelineStageService reads and writes the pipeline configuration in
+// Hub-HRMS, caching it so status changes can be validated without a round
+// trip each time
+type PipelineStageService struct {
+	client *gateway.HubHRMSClient
+	ttl    time.Duration
+
+	mu        sync.Mutex
+	stages    []PipelineStage
+	expiresAt time.Time
+}
+
+// NewPipelineStageService creates a service caching the pipeline for ttl
+func NewPipelineStageService(client *gateway.HubHRMSClient, ttl time.Duration) *PipelineStageService {
+	return &PipelineStageService{client: client, ttl: ttl}
+}
+
+// Stages returns the configured pipeline, or DefaultPipelineStages if none
+// has been configured
+func (s *PipelineStageService) Stages(ctx context.Context) ([]PipelineStage, error) {
+	s.mu.Lock()
+	stages, expiresAt := s.stages, s.expiresAt
+	s.mu.Unlock()
+	if stages != nil && time.Now().Before(expiresAt) {
+		return stages, nil
+	}
+
+	resp, err := s.client.Query(ctx, gateway.GetPipelineStagesQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch pipeline stages: %w", err)
+	}
+
+	if _, err := decodeField(resp.Data, "pipelineStages", &stages); err != nil {
+		return nil, err
+	}
+	if len(stages) == 0 {
+		stages = DefaultPipelineStages
+	}
+
+	s.store(stages)
+	return stages, nil
+}
+
+// Update replaces the pipeline configuration
+func (s *PipelineStageService) Update(ctx context.Context, stages []PipelineStage) ([]PipelineStage, error) {
+	if err := ValidateStages(stages); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Mutate(ctx, gateway.UpdatePipelineStagesMutation, map[string]interface{}{
+		"stages": stages,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update pipeline stages: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("failed to update pipeline stages: %s", resp.Errors[0].Message)
+	}
+
+	var saved []PipelineStage
+	if _, err := decodeField(resp.Data, "updatePipelineStages", &saved); err != nil {
+		return nil, err
+	}
+
+	s.store(saved)
+	return saved, nil
+}
+
+// ValidateTransition reports whether the configured pipeline allows an
+// application to move from one status to another
+func (s *PipelineStageService) ValidateTransition(ctx context.Context, from, to string) error {
+	stages, err := s.Stages(ctx)
+	if err != nil {
+		return err
+	}
+	return ValidateTransition(stages, from, to)
+}
+
+// ValidateStage reports whether status is a stage of the configured pipeline
+func (s *PipelineStageService) ValidateStage(ctx context.Context, status string) error {
+	stages, err := s.Stages(ctx)
+	if err != nil {
+		return err
+	}
+	return ValidateStage(stages, status)
+}
+
+func (s *PipelineStageService) store(stages []PipelineStage) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.stages, s.expiresAt = stages, time.Now().Add(s.ttl)
+}
@@ -0,0 +1,95 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// customStages is a pipeline with stages the default one does not have
+var customStages = []PipelineStage{
+	{ID: "APPLIED", Name: "Applied", Order: 1},
+	{ID: "PHONE_SCREEN", Name: "Phone screen", Order: 2},
+	{ID: "TAKE_HOME", Name: "Take-home task", Order: 3},
+	{ID: "HIRED", Name: "Hired", Order: 4, FinalStage: true},
+	{ID: "REJECTED", Name: "Rejected", Order: 5, FinalStage: true},
+}
+
+// newStageServer fakes Hub-HRMS answering GetPipelineStagesQuery with stages
+func newStageServer(t *testing.T, stages []PipelineStage) *gateway.HubHRMSClient {
+	return newFakeHubHRMS(t, func(gateway.GraphQLRequest) interface{} {
+		return map[string]interface{}{"pipelineStages": stages}
+	})
+}
+
+func TestPipelineStageService_ValidateStage(t *testing.T) {
+	service := NewPipelineStageService(newStageServer(t, customStages), time.Minute)
+
+	tests := []struct {
+		status  string
+		wantErr error
+	}{
+		{status: "PHONE_SCREEN"},
+		{status: "take_home"},
+		{status: "REJECTED"},
+		{status: "INTERVIEW", wantErr: ErrUnknownStatus},
+		{status: "BOGUS", wantErr: ErrUnknownStatus},
+		{status: "", wantErr: ErrUnknownStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.status, func(t *testing.T) {
+			err := service.ValidateStage(context.Background(), tt.status)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("ValidateStage(%q) error = %v, want nil", tt.status, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateStage(%q) error = %v, want %v", tt.status, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPipelineStageService_ValidateTransition(t *testing.T) {
+	service := NewPipelineStageService(newStageServer(t, customStages), time.Minute)
+
+	tests := []struct {
+		from, to string
+		wantErr  error
+	}{
+		{from: "APPLIED", to: "PHONE_SCREEN"},
+		{from: "PHONE_SCREEN", to: "TAKE_HOME"},
+		{from: "TAKE_HOME", to: "HIRED"},
+		{from: "PHONE_SCREEN", to: "REJECTED"},
+		{from: "APPLIED", to: "TAKE_HOME", wantErr: ErrInvalidTransition},
+		{from: "HIRED", to: "APPLIED", wantErr: ErrInvalidTransition},
+		{from: "APPLIED", to: "SCREENING", wantErr: ErrUnknownStatus},
+		{from: "INTERVIEW", to: "HIRED", wantErr: ErrUnknownStatus},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.from+"->"+tt.to, func(t *testing.T) {
+			err := service.ValidateTransition(context.Background(), tt.from, tt.to)
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("ValidateTransition(%q, %q) error = %v, want nil", tt.from, tt.to, err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("ValidateTransition(%q, %q) error = %v, want %v", tt.from, tt.to, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestPipelineStageService_DefaultsWithoutConfiguration(t *testing.T) {
+	service := NewPipelineStageService(newStageServer(t, nil), time.Minute)
+
+	if err := service.ValidateStage(context.Background(), "INTERVIEW"); err != nil {
+		t.Fatalf("ValidateStage(INTERVIEW) error = %v, want nil", err)
+	}
+	if err := service.ValidateStage(context.Background(), "PHONE_SCREEN"); !errors.Is(err, ErrUnknownStatus) {
+		t.Fatalf("ValidateStage(PHONE_SCREEN) error = %v, want %v", err, ErrUnknownStatus)
+	}
+}
@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// maxPoolCandidates caps the candidates a pool query collects
+const maxPoolCandidates = 1000
+
+var (
+	// ErrCandidatePoolNotFound is returned for pools that do not exist
+	ErrCandidatePoolNotFound = errors.New("candidate pool not found")
+	// ErrInvalidCandidatePool is returned for pools without a name or criteria
+	ErrInvalidCandidatePool = errors.New("invalid candidate pool")
+)
+
+// CandidateSearchQuery is a saved candidate search. Every set criterion must
+// match.
+type CandidateSearchQuery struct {
+	Keywords           string   `json:"keywords,omitempty"`
+	Skills             []string `json:"skills,omitempty"`
+	Location           string   `json:"location,omitempty"`
+	WillingToRelocate  *bool    `json:"willingToRelocate,omitempty"`
+	MinYearsExperience *int     `json:"minYearsExperience,omitempty"`
+}
+
+// empty reports whether the query has no criteria, which would match every
+// candidate
+func (q CandidateSearchQuery) empty() bool {
+	return strings.TrimSpace(q.Keywords) == "" && len(q.Skills) == 0 && strings.TrimSpace(q.Location) == "" &&
+		q.WillingToRelocate == nil && q.MinYearsExperience == nil
+}
+
+// CandidatePool is a saved search and the candidates it matched when last
+// refreshed, kept for outreach
+type CandidatePool struct {
+	ID           string               `json:"id"`
+	Name         string               `json:"name"`
+	Query        CandidateSearchQuery `json:"query"`
+	CandidateIDs []string             `json:"candidateIds"`
+	CreatedBy    string               `json:"createdBy,omitempty"`
+	UpdatedAt    time.Time            `json:"updatedAt"`
+}
+
+// CandidatePoolService stores candidate pools in Hub-HRMS
+type CandidatePoolService struct {
+	client *gateway.HubHRMSClient
+}
+
+// NewCandidatePoolService creates a new candidate pool service
+func NewCandidatePoolService(client *gateway.HubHRMSClient) *CandidatePoolService {
+	return &CandidatePoolService{client: client}
+}
+
+// List returns every pool
+func (s *CandidatePoolService) List(ctx context.Context) ([]CandidatePool, error) {
+	resp, err := s.client.Query(ctx, gateway.GetCandidatePoolsQuery, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch candidate pools: %w", err)
+	}
+
+	pools := []CandidatePool{}
+	if _, err := decodeField(resp.Data, "candidatePools", &pools); err != nil {
+		return nil, err
+	}
+	return pools, nil
+}
+
+// Get returns one pool
+func (s *CandidatePoolService) Get(ctx context.Context, id string) (*CandidatePool, error) {
+	resp, err := s.client.Query(ctx, gateway.GetCandidatePoolQuery, map[string]interface{}{"id": id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch candidate pool: %w", err)
+	}
+	return decodePool(resp, "candidatePool")
+}
+
+// Create saves a pool, filling it with the candidates the query matches now
+func (s *CandidatePoolService) Create(ctx context.Context, name string, query CandidateSearchQuery, createdBy string) (*CandidatePool, error) {
+	if err := validatePool(name, query); err != nil {
+		return nil, err
+	}
+	candidateIDs, err := s.search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := s.client.Mutate(ctx, gateway.CreateCandidatePoolMutation, map[string]interface{}{
+		"input": map[string]interface{}{
+			"name":         name,
+			"query":        query,
+			"candidateIds": candidateIDs,
+			"createdBy":    createdBy,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create candidate pool: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("failed to create candidate pool: %s", resp.Errors[0].Message)
+	}
+	return decodePool(resp, "createCandidatePool")
+}
+
+// Update renames a pool and replaces its query. The candidates are kept
+// until the next Refresh.
+func (s *CandidatePoolService) Update(ctx context.Context, id, name string, query CandidateSearchQuery) (*CandidatePool, error) {
+	if err := validatePool(name, query); err != nil {
+		return nil, err
+	}
+	pool, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	pool.Name = name
+	pool.Query = query
+	return s.save(ctx, pool)
+}
+
+// Refresh re-runs a pool's query and replaces its candidates with the
+// current matches
+func (s *CandidatePoolService) Refresh(ctx context.Context, id string) (*CandidatePool, error) {
+	pool, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	pool.CandidateIDs, err = s.search(ctx, pool.Query)
+	if err != nil {
+		return nil, err
+	}
+	return s.save(ctx, pool)
+}
+
+// Delete removes a pool
+func (s *CandidatePoolService) Delete(ctx context.Context, id string) error {
+	resp, err := s.client.Mutate(ctx, gateway.DeleteCandidatePoolMutation, map[string]interface{}{"id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete candidate pool: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("failed to delete candidate pool: %s", resp.Errors[0].Message)
+	}
+	data, _ := resp.Data.(map[string]interface{})
+	if deleted, _ := data["deleteCandidatePool"].(bool); !deleted {
+		return ErrCandidatePoolNotFound
+	}
+	return nil
+}
+
+func (s *CandidatePoolService) save(ctx context.Context, pool *CandidatePool) (*CandidatePool, error) {
+	resp, err := s.client.Mutate(ctx, gateway.UpdateCandidatePoolMutation, map[string]interface{}{
+		"id": pool.ID,
+		"input": map[string]interface{}{
+			"name":         pool.Name,
+			"query":        pool.Query,
+			"candidateIds": pool.CandidateIDs,
+			"createdBy":    pool.CreatedBy,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update candidate pool: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("failed to update candidate pool: %s", resp.Errors[0].Message)
+	}
+	return decodePool(resp, "updateCandidatePool")
+}
+
+// search returns the IDs of candidates matching query
+func (s *CandidatePoolService) search(ctx context.Context, query CandidateSearchQuery) ([]string, error) {
+	resp, err := s.client.Query(ctx, gateway.SearchCandidatesQuery, map[string]interface{}{
+		"filters": query,
+		"limit":   maxPoolCandidates,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search candidates: %w", err)
+	}
+
+	var candidates []struct {
+		ID string `json:"id"`
+	}
+	if _, err := decodeField(resp.Data, "searchCandidates", &candidates); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(candidates))
+	for _, candidate := range candidates {
+		ids = append(ids, candidate.ID)
+	}
+	return ids, nil
+}
+
+func validatePool(name string, query CandidateSearchQuery) error {
+	if strings.TrimSpace(name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidCandidatePool)
+	}
+	if query.empty() {
+		return fmt.Errorf("%w: query needs at least one criterion", ErrInvalidCandidatePool)
+	}
+	return nil
+}
+
+// decodePool reads the pool under field, treating null as not found
+func decodePool(resp *gateway.GraphQLResponse, field string) (*CandidatePool, error) {
+	var pool CandidatePool
+	found, err := decodeField(resp.Data, field, &pool)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, ErrCandidatePoolNotFound
+	}
+	return &pool, nil
+}
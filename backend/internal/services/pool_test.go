@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// poolServer fakes Hub-HRMS storing candidate pools, with a candidate search
+// that matches whoever is in matches at the time
+type poolServer struct {
+	mu       sync.Mutex
+	pools    map[string]map[string]interface{}
+	nextID   int
+	matches  []string
+	searches []map[string]interface{}
+}
+
+func newPoolService(t *testing.T, matches ...string) (*CandidatePoolService, *poolServer) {
+	server := &poolServer{pools: make(map[string]map[string]interface{}), matches: matches}
+	return NewCandidatePoolService(newFakeHubHRMS(t, server.respond)), server
+}
+
+func (s *poolServer) setMatches(ids ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.matches = ids
+}
+
+func (s *poolServer) respond(req gateway.GraphQLRequest) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id, _ := req.Variables["id"].(string)
+	input, _ := req.Variables["input"].(map[string]interface{})
+	switch req.Query {
+	case gateway.SearchCandidatesQuery:
+		filters, _ := req.Variables["filters"].(map[string]interface{})
+		s.searches = append(s.searches, filters)
+		candidates := []interface{}{}
+		for _, match := range s.matches {
+			candidates = append(candidates, map[string]interface{}{"id": match})
+		}
+		return map[string]interface{}{"searchCandidates": candidates}
+	case gateway.GetCandidatePoolsQuery:
+		pools := []interface{}{}
+		for _, pool := range s.pools {
+			pools = append(pools, pool)
+		}
+		return map[string]interface{}{"candidatePools": pools}
+	case gateway.GetCandidatePoolQuery:
+		if pool, ok := s.pools[id]; ok {
+			return map[string]interface{}{"candidatePool": pool}
+		}
+		return map[string]interface{}{"candidatePool": nil}
+	case gateway.CreateCandidatePoolMutation:
+		s.nextID++
+		pool := poolRecord(fmt.Sprintf("pool-%d", s.nextID), input)
+		s.pools[pool["id"].(string)] = pool
+		return map[string]interface{}{"createCandidatePool": pool}
+	case gateway.UpdateCandidatePoolMutation:
+		if _, ok := s.pools[id]; !ok {
+			return map[string]interface{}{"updateCandidatePool": nil}
+		}
+		s.pools[id] = poolRecord(id, input)
+		return map[string]interface{}{"updateCandidatePool": s.pools[id]}
+	case gateway.DeleteCandidatePoolMutation:
+		_, ok := s.pools[id]
+		delete(s.pools, id)
+		return map[string]interface{}{"deleteCandidatePool": ok}
+	}
+	return map[string]interface{}{}
+}
+
+// poolRecord is the pool Hub-HRMS stores for input
+func poolRecord(id string, input map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"id":           id,
+		"name":         input["name"],
+		"query":        input["query"],
+		"candidateIds": input["candidateIds"],
+		"createdBy":    input["createdBy"],
+		"updatedAt":    time.Now().UTC().Format(time.RFC3339),
+	}
+}
+
+func TestCandidatePoolService_CRUD(t *testing.T) {
+	ctx := context.Background()
+	pools, server := newPoolService(t, "cand-1", "cand-2")
+	relocate := true
+	query := CandidateSearchQuery{Skills: []string{"Go"}, Location: "Berlin", WillingToRelocate: &relocate}
+
+	created, err := pools.Create(ctx, "Go engineers in Berlin", query, "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if created.ID != "pool-1" || created.Name != "Go engineers in Berlin" || created.CreatedBy != "user-1" ||
+		!slices.Equal(created.CandidateIDs, []string{"cand-1", "cand-2"}) {
+		t.Fatalf("created = %+v, want the pool filled with the current matches", created)
+	}
+	if len(server.searches) != 1 || server.searches[0]["location"] != "Berlin" || server.searches[0]["willingToRelocate"] != true {
+		t.Fatalf("searched with %v, want the pool's query", server.searches)
+	}
+
+	got, err := pools.Get(ctx, "pool-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Query.Location != "Berlin" || !slices.Equal(got.Query.Skills, []string{"Go"}) || got.Query.WillingToRelocate == nil || !*got.Query.WillingToRelocate {
+		t.Fatalf("query = %+v, want it stored as saved", got.Query)
+	}
+
+	list, err := pools.List(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(list) != 1 || list[0].ID != "pool-1" {
+		t.Fatalf("List = %+v, want the created pool", list)
+	}
+
+	// Updating changes the query but keeps the candidates until a refresh
+	server.setMatches("cand-3")
+	updated, err := pools.Update(ctx, "pool-1", "Go engineers in Munich", CandidateSearchQuery{Skills: []string{"Go"}, Location: "Munich"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Name != "Go engineers in Munich" || updated.Query.Location != "Munich" || updated.CreatedBy != "user-1" ||
+		!slices.Equal(updated.CandidateIDs, []string{"cand-1", "cand-2"}) {
+		t.Fatalf("updated = %+v, want the new name and query with the old candidates", updated)
+	}
+	if len(server.searches) != 1 {
+		t.Fatal("update re-ran the search")
+	}
+
+	if err := pools.Delete(ctx, "pool-1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pools.Get(ctx, "pool-1"); !errors.Is(err, ErrCandidatePoolNotFound) {
+		t.Fatalf("Get after Delete error = %v, want ErrCandidatePoolNotFound", err)
+	}
+	if err := pools.Delete(ctx, "pool-1"); !errors.Is(err, ErrCandidatePoolNotFound) {
+		t.Fatalf("second Delete error = %v, want ErrCandidatePoolNotFound", err)
+	}
+}
+
+func TestCandidatePoolService_Refresh(t *testing.T) {
+	ctx := context.Background()
+	pools, server := newPoolService(t, "cand-1", "cand-2")
+
+	if _, err := pools.Create(ctx, "Go engineers", CandidateSearchQuery{Skills: []string{"Go"}}, "user-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	server.setMatches("cand-2", "cand-4")
+	refreshed, err := pools.Refresh(ctx, "pool-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(refreshed.CandidateIDs, []string{"cand-2", "cand-4"}) {
+		t.Fatalf("candidates = %v, want the current matches", refreshed.CandidateIDs)
+	}
+	if refreshed.Name != "Go engineers" || !slices.Equal(refreshed.Query.Skills, []string{"Go"}) || refreshed.CreatedBy != "user-1" {
+		t.Fatalf("refreshed = %+v, want the rest of the pool unchanged", refreshed)
+	}
+	if stored, _ := pools.Get(ctx, "pool-1"); !slices.Equal(stored.CandidateIDs, []string{"cand-2", "cand-4"}) {
+		t.Fatalf("stored candidates = %v, want the refresh saved", stored.CandidateIDs)
+	}
+
+	server.setMatches()
+	if refreshed, err := pools.Refresh(ctx, "pool-1"); err != nil || len(refreshed.CandidateIDs) != 0 {
+		t.Fatalf("refresh with no matches = %+v, %v; want an empty pool", refreshed, err)
+	}
+
+	if _, err := pools.Refresh(ctx, "pool-9"); !errors.Is(err, ErrCandidatePoolNotFound) {
+		t.Fatalf("refresh of a missing pool error = %v, want ErrCandidatePoolNotFound", err)
+	}
+}
+
+func TestCandidatePoolService_Validation(t *testing.T) {
+	ctx := context.Background()
+	pools, server := newPoolService(t, "cand-1")
+	years := 0
+
+	tests := []struct {
+		name    string
+		pool    string
+		query   CandidateSearchQuery
+		wantErr bool
+	}{
+		{name: "no name", pool: " ", query: CandidateSearchQuery{Skills: []string{"Go"}}, wantErr: true},
+		{name: "no criteria", pool: "Everyone", query: CandidateSearchQuery{}, wantErr: true},
+		{name: "blank criteria", pool: "Everyone", query: CandidateSearchQuery{Keywords: "  ", Location: " "}, wantErr: true},
+		{name: "zero is a criterion", pool: "Graduates", query: CandidateSearchQuery{MinYearsExperience: &years}},
+		{name: "keywords only", pool: "Kubernetes", query: CandidateSearchQuery{Keywords: "kubernetes"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := pools.Create(ctx, tt.pool, tt.query, "user-1")
+			if tt.wantErr != errors.Is(err, ErrInvalidCandidatePool) {
+				t.Fatalf("Create error = %v, want invalid %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := pools.Update(ctx, "pool-1", "", CandidateSearchQuery{Keywords: "go"}); !errors.Is(err, ErrInvalidCandidatePool) {
+		t.Fatalf("Update error = %v, want ErrInvalidCandidatePool", err)
+	}
+	if len(server.searches) != 2 {
+		t.Fatalf("ran %d searches, want only the valid pools searched", len(server.searches))
+	}
+}
@@ -0,0 +1,214 @@
+package services
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// maxSentenceWords is the sentence length past which readers lose the thread
+const maxSentenceWords = 30
+
+var (
+	// sentenceEnd splits text into sentences at terminal punctuation and at
+	// line breaks, since requirements and bullet points rarely end in a stop
+	sentenceEnd = regexp.MustCompile(`[.!?]+(\s+|$)|\n+`)
+	wordPattern = regexp.MustCompile(`[A-Za-z][A-Za-z'’-]*`)
+	// passiveVoice matches a form of "to be" followed by a past participle,
+	// regular or one of the common irregular ones
+	passiveVoice = regexp.MustCompile(`(?i)\b(am|is|are|was|were|be|been|being)\s+(\w+ly\s+)?(\w+ed|born|brought|built|done|driven|given|held|kept|known|led|made|paid|seen|sent|shown|taken|taught|told|understood|written)\b`)
+	acronym      = regexp.MustCompile(`\b[A-Z][A-Z0-9]{1,5}s?\b`)
+)
+
+// commonAcronyms are understood without being spelled out
+var commonAcronyms = map[string]bool{
+	"CV": true, "EU": true, "HR": true, "ID": true, "IT": true, "OK": true,
+	"UK": true, "US": true, "USA": true,
+}
+
+// ReadabilityReport rates how easy a text is to read and points out what
+// makes it harder
+type ReadabilityReport struct {
+	FleschScore float64  `json:"fleschScore"`
+	GunningFog  float64  `json:"gunningFog"`
+	Grade       string   `json:"grade"`
+	Suggestions []string `json:"suggestions"`
+}
+
+// AnalyzeReadability scores text with FleschReadingEase and GunningFog and
+// suggests splitting long sentences, rewording passive ones and spelling out
+// acronyms. It runs locally. Text without words gets no scores.
+func AnalyzeReadability(text string) ReadabilityReport {
+	report := ReadabilityReport{Suggestions: []string{}}
+	if words, _, _, _ := textCounts(text); words == 0 {
+		return report
+	}
+
+	flesch := FleschReadingEase(text)
+	report.FleschScore = math.Round(flesch*10) / 10
+	report.GunningFog = math.Round(GunningFog(text)*10) / 10
+	report.Grade = ReadingGrade(flesch)
+
+	for _, sentence := range sentences(text) {
+		if words := len(wordPattern.FindAllString(sentence, -1)); words > maxSentenceWords {
+			report.Suggestions = append(report.Suggestions,
+				fmt.Sprintf("Split the %d-word sentence starting %q", words, sentenceStart(sentence)))
+		}
+		if match := passiveVoice.FindString(sentence); match != "" {
+			report.Suggestions = append(report.Suggestions,
+				fmt.Sprintf("Rewrite %q in the active voice, saying who does what", match))
+		}
+	}
+
+	for _, term := range undefinedAcronyms(text) {
+		report.Suggestions = append(report.Suggestions,
+			fmt.Sprintf("Spell out %s the first time it is used", term))
+	}
+	return report
+}
+
+// FleschReadingEase scores text from about 0 (very hard) to 100 (very easy)
+// by its words per sentence and syllables per word. Text without words
+// scores 0.
+func FleschReadingEase(text string) float64 {
+	words, sentenceCount, syllables, _ := textCounts(text)
+	if words == 0 {
+		return 0
+	}
+	return 206.835 - 1.015*float64(words)/float64(sentenceCount) - 84.6*float64(syllables)/float64(words)
+}
+
+// GunningFog estimates the years of schooling needed to read text on first
+// pass from its words per sentence and share of words of three or more
+// syllables. Text without words scores 0.
+func GunningFog(text string) float64 {
+	words, sentenceCount, _, complexWords := textCounts(text)
+	if words == 0 {
+		return 0
+	}
+	return 0.4 * (float64(words)/float64(sentenceCount) + 100*float64(complexWords)/float64(words))
+}
+
+// ReadingGrade names the school level a Flesch reading ease score suits
+func ReadingGrade(flesch float64) string {
+	switch {
+	case flesch >= 90:
+		return "5th grade"
+	case flesch >= 80:
+		return "6th grade"
+	case flesch >= 70:
+		return "7th grade"
+	case flesch >= 60:
+		return "8th-9th grade"
+	case flesch >= 50:
+		return "10th-12th grade"
+	case flesch >= 30:
+		return "College"
+	default:
+		return "College graduate"
+	}
+}
+
+// textCounts counts the words, sentences, syllables and words of three or
+// more syllables in text
+func textCounts(text string) (words, sentenceCount, syllables, complexWords int) {
+	for _, sentence := range sentences(text) {
+		found := wordPattern.FindAllString(sentence, -1)
+		if len(found) == 0 {
+			continue
+		}
+		sentenceCount++
+		for _, word := range found {
+			n := CountSyllables(word)
+			words++
+			syllables += n
+			if n >= 3 {
+				complexWords++
+			}
+		}
+	}
+	return words, sentenceCount, syllables, complexWords
+}
+
+// sentences splits text into its non-blank sentences
+func sentences(text string) []string {
+	var found []string
+	for _, sentence := range sentenceEnd.Split(text, -1) {
+		if sentence = strings.TrimSpace(sentence); sentence != "" {
+			found = append(found, sentence)
+		}
+	}
+	return found
+}
+
+// sentenceStart is the first few words of a sentence, to point at it
+func sentenceStart(sentence string) string {
+	words := strings.Fields(sentence)
+	if len(words) <= 6 {
+		return sentence
+	}
+	return strings.Join(words[:6], " ") + "…"
+}
+
+// CountSyllables estimates the syllables in an English word by counting its
+// vowel groups, discounting a silent final e and -es or -ed endings that do
+// not add a syllable
+func CountSyllables(word string) int {
+	word = strings.ToLower(strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) {
+			return r
+		}
+		return -1
+	}, word))
+	if len(word) <= 3 {
+		return 1
+	}
+
+	switch {
+	case strings.HasSuffix(word, "ed") && !strings.HasSuffix(word, "ted") && !strings.HasSuffix(word, "ded"):
+		word = strings.TrimSuffix(word, "ed")
+	case strings.HasSuffix(word, "es") && !strings.HasSuffix(word, "ses") && !strings.HasSuffix(word, "xes") &&
+		!strings.HasSuffix(word, "zes") && !strings.HasSuffix(word, "ces") && !strings.HasSuffix(word, "ges") &&
+		!strings.HasSuffix(word, "ches") && !strings.HasSuffix(word, "shes"):
+		word = strings.TrimSuffix(word, "es")
+	case strings.HasSuffix(word, "e") && !strings.HasSuffix(word, "le"):
+		word = strings.TrimSuffix(word, "e")
+	}
+
+	count := 0
+	inVowel := false
+	for _, r := range word {
+		vowel := strings.ContainsRune("aeiouy", r)
+		if vowel && !inVowel {
+			count++
+		}
+		inVowel = vowel
+	}
+	return max(count, 1)
+}
+
+// undefinedAcronyms lists, in order of first use, the acronyms in text that
+// are never defined as "Full Name (ACRONYM)" or "ACRONYM (Full Name)". Lines
+// without lowercase letters are taken for headings and skipped.
+func undefinedAcronyms(text string) []string {
+	var undefined []string
+	seen := make(map[string]bool)
+	for _, line := range strings.Split(text, "\n") {
+		if strings.ToUpper(line) == line {
+			continue
+		}
+		for _, match := range acronym.FindAllString(line, -1) {
+			term := strings.TrimSuffix(match, "s")
+			if len(term) < 2 || seen[term] || commonAcronyms[term] {
+				continue
+			}
+			seen[term] = true
+			if !strings.Contains(text, "("+term+")") && !strings.Contains(text, term+" (") {
+				undefined = append(undefined, term)
+			}
+		}
+	}
+	return undefined
+}
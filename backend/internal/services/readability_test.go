@@ -0,0 +1,147 @@
+package services
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestAnalyzeReadability(t *testing.T) {
+	tests := []struct {
+		fixture         string
+		minFlesch       float64
+		maxFlesch       float64
+		minFog          float64
+		maxFog          float64
+		wantGrade       string
+		wantSuggestions []string
+	}{
+		{
+			fixture:   "simple.txt",
+			minFlesch: 90, maxFlesch: 110, minFog: 3, maxFog: 6,
+			wantGrade:       "5th grade",
+			wantSuggestions: []string{},
+		},
+		{
+			fixture:   "complex.txt",
+			minFlesch: -100, maxFlesch: 0, minFog: 20, maxFog: 40,
+			wantGrade: "College graduate",
+			wantSuggestions: []string{
+				`Split the 35-word sentence starting "The successful candidate will be responsible…"`,
+				`Rewrite "are reviewed" in the active voice, saying who does what`,
+				`Rewrite "is required" in the active voice, saying who does what`,
+				"Spell out SDLC the first time it is used",
+				"Spell out PMO the first time it is used",
+				"Spell out KPI the first time it is used",
+				"Spell out CI the first time it is used",
+				"Spell out CD the first time it is used",
+			},
+		},
+		{
+			// A defined acronym and one in a heading are not flagged
+			fixture:   "mixed.txt",
+			minFlesch: 60, maxFlesch: 70, minFog: 9, maxFog: 12,
+			wantGrade: "8th-9th grade",
+			wantSuggestions: []string{
+				`Rewrite "are processed" in the active voice, saying who does what`,
+				`Split the 33-word sentence starting "Experience with Go and PostgreSQL, ideally…"`,
+				"Spell out AWS the first time it is used",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			report := AnalyzeReadability(string(readFixture(t, "readability/"+tt.fixture)))
+			if report.FleschScore < tt.minFlesch || report.FleschScore > tt.maxFlesch {
+				t.Errorf("FleschScore = %v, want %v to %v", report.FleschScore, tt.minFlesch, tt.maxFlesch)
+			}
+			if report.GunningFog < tt.minFog || report.GunningFog > tt.maxFog {
+				t.Errorf("GunningFog = %v, want %v to %v", report.GunningFog, tt.minFog, tt.maxFog)
+			}
+			if report.Grade != tt.wantGrade {
+				t.Errorf("Grade = %q, want %q", report.Grade, tt.wantGrade)
+			}
+			if !reflect.DeepEqual(report.Suggestions, tt.wantSuggestions) {
+				t.Errorf("Suggestions = %q, want %q", report.Suggestions, tt.wantSuggestions)
+			}
+		})
+	}
+
+	t.Run("no words", func(t *testing.T) {
+		for _, text := range []string{"", "  \n\n", "42. 1,000!"} {
+			report := AnalyzeReadability(text)
+			if report.FleschScore != 0 || report.GunningFog != 0 || report.Grade != "" || report.Suggestions == nil || len(report.Suggestions) != 0 {
+				t.Fatalf("AnalyzeReadability(%q) = %+v, want no scores", text, report)
+			}
+		}
+	})
+}
+
+func TestReadabilityFormulas(t *testing.T) {
+	// One six-word sentence of one-syllable words
+	if got := FleschReadingEase("The cat sat on the mat."); math.Abs(got-116.145) > 0.001 {
+		t.Errorf("FleschReadingEase() = %v, want 116.145", got)
+	}
+	if got := GunningFog("The cat sat on the mat."); math.Abs(got-2.4) > 0.001 {
+		t.Errorf("GunningFog() = %v, want 2.4", got)
+	}
+
+	// Two sentences of three words, two of them of three or more syllables
+	text := "Engineers value simplicity. Teams ship often."
+	wantFlesch := 206.835 - 1.015*6.0/2 - 84.6*float64(3+2+4+1+1+2)/6
+	if got := FleschReadingEase(text); math.Abs(got-wantFlesch) > 0.001 {
+		t.Errorf("FleschReadingEase(%q) = %v, want %v", text, got, wantFlesch)
+	}
+	wantFog := 0.4 * (6.0/2 + 100*2.0/6)
+	if got := GunningFog(text); math.Abs(got-wantFog) > 0.001 {
+		t.Errorf("GunningFog(%q) = %v, want %v", text, got, wantFog)
+	}
+
+	if FleschReadingEase("") != 0 || GunningFog("") != 0 {
+		t.Error("text without words should score 0")
+	}
+}
+
+func TestCountSyllables(t *testing.T) {
+	tests := map[string]int{
+		"a":           1,
+		"the":         1,
+		"code":        1,
+		"table":       2,
+		"shipped":     1,
+		"tested":      2,
+		"boxes":       2,
+		"makes":       1,
+		"engineer":    3,
+		"simplicity":  4,
+		"Engineers":   3,
+		"responsible": 4,
+		"rhythm":      1,
+		"don't":       1,
+		"well-known":  2,
+	}
+	for word, want := range tests {
+		if got := CountSyllables(word); got != want {
+			t.Errorf("CountSyllables(%q) = %d, want %d", word, got, want)
+		}
+	}
+}
+
+func TestReadingGrade(t *testing.T) {
+	tests := map[float64]string{
+		100: "5th grade",
+		90:  "5th grade",
+		85:  "6th grade",
+		70:  "7th grade",
+		65:  "8th-9th grade",
+		50:  "10th-12th grade",
+		30:  "College",
+		29:  "College graduate",
+		-20: "College graduate",
+	}
+	for flesch, want := range tests {
+		if got := ReadingGrade(flesch); got != want {
+			t.Errorf("ReadingGrade(%v) = %q, want %q", flesch, got, want)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package services
+
+import "context"
+
+// ApplicationReport is the content of an application's PDF report. Fields
+// with empty values and empty sections are left out.
+type ApplicationReport struct {
+	CandidateName string
+	// Summary is listed under the title, before the first section
+	Summary      []ReportField
+	Candidate    []ReportField
+	Job          []ReportField
+	Requirements []string
+	CoverLetter  string
+	Score        *ReportScore
+	Notes        []ReportNote
+	Timeline     []ReportEvent
+}
+
+// ReportField is a labelled value in a report
+type ReportField struct {
+	Label string
+	Value string
+}
+
+// ReportList is a labelled list of items in a report
+type ReportList struct {
+	Label string
+	Items []string
+}
+
+// ReportScore summarizes an application's AI score
+type ReportScore struct {
+	Fields []ReportField
+	Lists  []ReportList
+}
+
+// ReportNote is a note on an application
+type ReportNote struct {
+	Author   string
+	Date     string
+	Content  string
+	Internal bool
+}
+
+// ReportEvent is an entry in an application's timeline
+type ReportEvent struct {
+	Date        string
+	Description string
+	PerformedBy string
+}
+
+// GenerateApplicationReportPDF renders report from its HTML template and
+// converts it to PDF
+func GenerateApplicationReportPDF(ctx context.Context, report ApplicationReport) ([]byte, error) {
+	return renderPDF(ctx, "application_report.html", report)
+}
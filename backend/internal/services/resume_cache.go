@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// ResumeCache keeps recently downloaded S3 objects on local disk so repeated
+// downloads of the same resume are served without another S3 round trip
+type ResumeCache struct {
+	dir string
+	ttl time.Duration
+
+	// mu serializes fetches so concurrent misses for a key download it once
+	mu sync.Mutex
+}
+
+// NewResumeCache stores cached files under dir, refetching them after ttl
+func NewResumeCache(dir string, ttl time.Duration) *ResumeCache {
+	return &ResumeCache{dir: dir, ttl: ttl}
+}
+
+// Open returns the cached copy of key and its content type, calling fetch to
+// fill the cache when the copy is missing or older than the TTL. The caller
+// must close the returned file.
+func (c *ResumeCache) Open(ctx context.Context, key string, fetch func(context.Context, string) (io.ReadCloser, string, error)) (*os.File, string, error) {
+	sum := sha256.Sum256([]byte(key))
+	path := filepath.Join(c.dir, hex.EncodeToString(sum[:]))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if file, contentType, ok := c.lookup(path); ok {
+		return file, contentType, nil
+	}
+
+	body, contentType, err := fetch(ctx, key)
+	if err != nil {
+		return nil, "", err
+	}
+	defer body.Close()
+
+	if err := c.store(path, body, contentType); err != nil {
+		return nil, "", err
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", err
+	}
+	return file, contentType, nil
+}
+
+// lookup opens a fresh cached file, removing it if it has expired
+func (c *ResumeCache) lookup(path string) (*os.File, string, bool) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, "", false
+	}
+	if time.Since(info.ModTime()) > c.ttl {
+		os.Remove(path)
+		os.Remove(path + ".type")
+		return nil, "", false
+	}
+
+	contentType, err := os.ReadFile(path + ".type")
+	if err != nil {
+		return nil, "", false
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, "", false
+	}
+	return file, string(contentType), true
+}
+
+// store writes body to path via a temp file, so a failed download never
+// leaves a truncated copy behind
+func (c *ResumeCache) store(path string, body io.Reader, contentType string) error {
+	if err := os.MkdirAll(c.dir, 0o700); err != nil {
+		return fmt.Errorf("failed to create resume cache: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(c.dir, "download-*")
+	if err != nil {
+		return fmt.Errorf("failed to create resume cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to download resume: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.WriteFile(path+".type", []byte(contentType), 0o600); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestResumeCache_Open(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewResumeCache(dir, time.Hour)
+
+	fetches := 0
+	fetch := func(ctx context.Context, key string) (io.ReadCloser, string, error) {
+		fetches++
+		return io.NopCloser(strings.NewReader("resume of " + key)), "application/pdf", nil
+	}
+	open := func(key string) string {
+		t.Helper()
+		file, contentType, err := cache.Open(context.Background(), key, fetch)
+		if err != nil {
+			t.Fatalf("Open(%s) error = %v", key, err)
+		}
+		defer file.Close()
+		if contentType != "application/pdf" {
+			t.Fatalf("content type = %q", contentType)
+		}
+		body, _ := io.ReadAll(file)
+		return string(body)
+	}
+
+	if got := open("resumes/a.pdf"); got != "resume of resumes/a.pdf" || fetches != 1 {
+		t.Fatalf("Open() = %q after %d fetches", got, fetches)
+	}
+	if got := open("resumes/a.pdf"); got != "resume of resumes/a.pdf" || fetches != 1 {
+		t.Fatalf("cached Open() = %q after %d fetches, want no refetch", got, fetches)
+	}
+	if open("resumes/b.pdf"); fetches != 2 {
+		t.Fatalf("another key made %d fetches, want 2", fetches)
+	}
+
+	// Copies older than the TTL are fetched again
+	entries, _ := filepath.Glob(filepath.Join(dir, "*"))
+	old := time.Now().Add(-2 * time.Hour)
+	for _, entry := range entries {
+		os.Chtimes(entry, old, old)
+	}
+	if open("resumes/a.pdf"); fetches != 3 {
+		t.Fatalf("expired copy made %d fetches, want 3", fetches)
+	}
+}
+
+func TestResumeCache_FailedFetch(t *testing.T) {
+	dir := t.TempDir()
+	cache := NewResumeCache(dir, time.Hour)
+
+	failed := errors.New("no such key")
+	_, _, err := cache.Open(context.Background(), "resumes/a.pdf", func(context.Context, string) (io.ReadCloser, string, error) {
+		return nil, "", failed
+	})
+	if !errors.Is(err, failed) {
+		t.Fatalf("Open() error = %v, want the fetch error", err)
+	}
+
+	// A download cut short leaves nothing behind to be served later
+	_, _, err = cache.Open(context.Background(), "resumes/a.pdf", func(context.Context, string) (io.ReadCloser, string, error) {
+		return io.NopCloser(io.MultiReader(strings.NewReader("partial"), iotest.ErrReader(errors.New("connection reset")))), "application/pdf", nil
+	})
+	if err == nil {
+		t.Fatal("Open() of a broken download succeeded")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("cache holds %d files after failed downloads, want none", len(entries))
+	}
+}
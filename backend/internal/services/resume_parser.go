@@ -0,0 +1,453 @@
+package services
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"hr-recruiting/internal/gateway"
+)
+
+var (
+	// ErrEncryptedPDF is returned for password-protected resumes, whose text
+	// cannot be read
+	ErrEncryptedPDF = errors.New("resume PDF is encrypted")
+	// ErrInvalidPDF is returned for resumes that are not well-formed PDFs
+	ErrInvalidPDF = errors.New("resume is not a readable PDF")
+)
+
+// ResumeParser extracts the text of uploaded resumes and indexes it in
+// Hub-HRMS for full-text search
+type ResumeParser struct {
+	client  *gateway.HubHRMSClient
+	uploads *UploadService
+}
+
+// NewResumeParser creates a new resume parser
+func NewResumeParser(client *gateway.HubHRMSClient, uploads *UploadService) *ResumeParser {
+	return &ResumeParser{client: client, uploads: uploads}
+}
+
+// ExtractTextFromPDF downloads the PDF at s3Key and returns its text, one
+// line per line of text on the page
+func (p *ResumeParser) ExtractTextFromPDF(ctx context.Context, s3Key string) (string, error) {
+	body, contentType, err := p.uploads.GetObject(ctx, s3Key)
+	if err != nil {
+		return "", fmt.Errorf("failed to download resume: %w", err)
+	}
+	defer body.Close()
+
+	if contentType != "" && contentType != "application/pdf" {
+		return "", fmt.Errorf("%w: resume is %s", ErrInvalidPDF, contentType)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, maxResumeSize+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to download resume: %w", err)
+	}
+	if len(data) > maxResumeSize {
+		return "", fmt.Errorf("%w: larger than %d bytes", ErrInvalidPDF, maxResumeSize)
+	}
+
+	return extractPDFText(data)
+}
+
+// IndexResume extracts the text of the resume at s3Key and stores it
+// against the application, returning the text
+func (p *ResumeParser) IndexResume(ctx context.Context, applicationID, s3Key string) (string, error) {
+	text, err := p.ExtractTextFromPDF(ctx, s3Key)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := p.client.Mutate(ctx, gateway.IndexResumeTextMutation, map[string]interface{}{
+		"applicationId": applicationID,
+		"text":          text,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to index resume text: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return "", fmt.Errorf("failed to index resume text: %s", resp.Errors[0].Message)
+	}
+	return text, nil
+}
+
+var (
+	// pdfEncryptPattern matches the trailer entry of an encrypted document
+	pdfEncryptPattern = regexp.MustCompile(`/Encrypt\s*(\d+\s+\d+\s+R|<<)`)
+	// pdfFilterPattern matches a stream's filter or filter array
+	pdfFilterPattern = regexp.MustCompile(`/Filter\s*(\[[^\]]*\]|/[A-Za-z0-9]+)`)
+	pdfNamePattern   = regexp.MustCompile(`/[A-Za-z0-9]+`)
+)
+
+// pdfSkippedStreams mark streams that hold images, fonts or cross
+// references rather than page content
+var pdfSkippedStreams = []string{"/Image", "/XRef", "/ObjStm", "/Length1", "/Type1C", "/CIDFontType0C", "/OpenType"}
+
+// extractPDFText returns the text drawn by the content streams of a PDF.
+// Only uncompressed and Flate-compressed streams are read, and strings are
+// decoded as Latin-1 or, with a byte order mark, UTF-16; text set in fonts
+// with custom encodings, which is rare in exported resumes, comes out
+// garbled.
+func extractPDFText(data []byte) (string, error) {
+	if !bytes.HasPrefix(bytes.TrimLeft(data, "\x00\t\n\r "), []byte("%PDF-")) {
+		return "", fmt.Errorf("%w: missing PDF header", ErrInvalidPDF)
+	}
+	if pdfEncryptPattern.Match(data) {
+		return "", ErrEncryptedPDF
+	}
+
+	var text pdfText
+	found := false
+	for pos := 0; ; {
+		i := bytes.Index(data[pos:], []byte("stream"))
+		if i < 0 {
+			break
+		}
+		keyword := pos + i
+		pos = keyword + len("stream")
+		if bytes.HasSuffix(data[:keyword], []byte("end")) {
+			continue
+		}
+
+		begin := pos
+		if begin < len(data) && data[begin] == '\r' {
+			begin++
+		}
+		if begin < len(data) && data[begin] == '\n' {
+			begin++
+		}
+		end := bytes.Index(data[begin:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		pos = begin + end + len("endstream")
+		found = true
+
+		dict := data[:keyword]
+		if obj := bytes.LastIndex(dict, []byte(" obj")); obj >= 0 {
+			dict = dict[obj:]
+		}
+		content, ok := decodePDFStream(dict, data[begin:begin+end])
+		if !ok {
+			continue
+		}
+		text.parseContent(content)
+		text.newline()
+	}
+
+	if !found {
+		return "", fmt.Errorf("%w: no content streams", ErrInvalidPDF)
+	}
+	return text.String(), nil
+}
+
+// decodePDFStream returns the decoded bytes of a stream that may hold page
+// content, or false for streams that cannot or need not be read
+func decodePDFStream(dict, raw []byte) ([]byte, bool) {
+	for _, marker := range pdfSkippedStreams {
+		if bytes.Contains(dict, []byte(marker)) {
+			return nil, false
+		}
+	}
+
+	var filters [][]byte
+	if match := pdfFilterPattern.FindSubmatch(dict); match != nil {
+		filters = pdfNamePattern.FindAll(match[1], -1)
+	}
+	switch {
+	case len(filters) == 0:
+		return raw, true
+	case len(filters) == 1 && string(filters[0]) == "/FlateDecode":
+		reader, err := zlib.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, false
+		}
+		defer reader.Close()
+		content, err := io.ReadAll(reader)
+		if err != nil && len(content) == 0 {
+			return nil, false
+		}
+		return content, true
+	default:
+		return nil, false
+	}
+}
+
+// pdfText accumulates the text shown by content stream operators
+type pdfText struct {
+	b           strings.Builder
+	lineHasText bool
+}
+
+func (t *pdfText) write(s string) {
+	if s == "" {
+		return
+	}
+	t.b.WriteString(s)
+	t.lineHasText = true
+}
+
+func (t *pdfText) newline() {
+	if t.lineHasText {
+		t.b.WriteByte('\n')
+		t.lineHasText = false
+	}
+}
+
+// String returns the text with runs of spaces collapsed and blank lines
+// dropped
+func (t *pdfText) String() string {
+	var lines []string
+	for _, line := range strings.Split(t.b.String(), "\n") {
+		if line = strings.Join(strings.Fields(line), " "); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// pdfTJSpace is the kerning adjustment in a TJ array, in thousandths of an
+// em, past which the gap is taken to be a word break
+const pdfTJSpace = -200
+
+// parseContent runs the text operators of a content stream: strings shown
+// by Tj, TJ, ' and " are written, and moves to a new line start a new line
+func (t *pdfText) parseContent(content []byte) {
+	var operands []interface{}
+	var arrays [][]interface{}
+	push := func(operand interface{}) {
+		if len(arrays) > 0 {
+			arrays[len(arrays)-1] = append(arrays[len(arrays)-1], operand)
+			return
+		}
+		operands = append(operands, operand)
+	}
+
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case isPDFSpace(c):
+			i++
+		case c == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case c == '(':
+			s, next := readPDFLiteral(content, i+1)
+			push(s)
+			i = next
+		case c == '<' && i+1 < len(content) && content[i+1] == '<', c == '>' && i+1 < len(content) && content[i+1] == '>':
+			i += 2
+		case c == '<':
+			s, next := readPDFHex(content, i+1)
+			push(s)
+			i = next
+		case c == '[':
+			arrays = append(arrays, nil)
+			i++
+		case c == ']':
+			if len(arrays) > 0 {
+				array := arrays[len(arrays)-1]
+				arrays = arrays[:len(arrays)-1]
+				push(array)
+			}
+			i++
+		case c == '{' || c == '}' || c == ')' || c == '>':
+			i++
+		default:
+			start := i
+			i++
+			for i < len(content) && !isPDFSpace(content[i]) && !isPDFDelimiter(content[i]) {
+				i++
+			}
+			token := string(content[start:i])
+			if c == '/' {
+				push(token)
+				continue
+			}
+			if n, err := strconv.ParseFloat(token, 64); err == nil {
+				push(n)
+				continue
+			}
+
+			if token == "ID" {
+				// Inline image data is binary and runs to the EI operator
+				if end := bytes.Index(content[i:], []byte("EI")); end >= 0 {
+					i += end + len("EI")
+				} else {
+					i = len(content)
+				}
+			}
+			t.operator(token, operands)
+			operands = operands[:0]
+			arrays = arrays[:0]
+		}
+	}
+}
+
+// operator applies one text operator to its operands
+func (t *pdfText) operator(op string, operands []interface{}) {
+	switch op {
+	case "Td", "TD":
+		if len(operands) == 2 {
+			if ty, ok := operands[1].(float64); ok && ty != 0 {
+				t.newline()
+			}
+		}
+	case "T*", "Tm":
+		t.newline()
+	case "Tj":
+		t.showString(operands)
+	case "'", "\"":
+		t.newline()
+		t.showString(operands)
+	case "TJ":
+		if len(operands) == 0 {
+			return
+		}
+		array, _ := operands[len(operands)-1].([]interface{})
+		for _, element := range array {
+			switch v := element.(type) {
+			case pdfStringOperand:
+				t.write(v.text())
+			case float64:
+				if v < pdfTJSpace {
+					t.write(" ")
+				}
+			}
+		}
+	}
+}
+
+// showString writes the last operand, the string shown by Tj, ' and "
+func (t *pdfText) showString(operands []interface{}) {
+	if len(operands) == 0 {
+		return
+	}
+	if s, ok := operands[len(operands)-1].(pdfStringOperand); ok {
+		t.write(s.text())
+	}
+}
+
+// pdfStringOperand is the raw bytes of a string operand
+type pdfStringOperand []byte
+
+// text decodes the string as UTF-16 when it starts with a byte order mark
+// and as Latin-1 otherwise, dropping control characters
+func (s pdfStringOperand) text() string {
+	if len(s) >= 2 && s[0] == 0xfe && s[1] == 0xff {
+		units := make([]uint16, 0, len(s)/2)
+		for i := 2; i+1 < len(s); i += 2 {
+			units = append(units, uint16(s[i])<<8|uint16(s[i+1]))
+		}
+		return string(utf16.Decode(units))
+	}
+
+	var b strings.Builder
+	for _, c := range s {
+		switch {
+		case c == '\t' || c == '\n' || c == '\r':
+			b.WriteByte(' ')
+		case c >= 0x20 && c < 0x7f || c >= 0xa0:
+			b.WriteRune(rune(c))
+		}
+	}
+	return b.String()
+}
+
+// readPDFLiteral reads a (literal) string starting after its opening
+// parenthesis, returning it and the offset after the closing one
+func readPDFLiteral(content []byte, i int) (pdfStringOperand, int) {
+	var s []byte
+	depth := 1
+	for i < len(content) {
+		c := content[i]
+		i++
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth--; depth == 0 {
+				return s, i
+			}
+		case '\\':
+			if i >= len(content) {
+				return s, i
+			}
+			c = content[i]
+			i++
+			switch c {
+			case 'n':
+				c = '\n'
+			case 'r':
+				c = '\r'
+			case 't':
+				c = '\t'
+			case 'b':
+				c = '\b'
+			case 'f':
+				c = '\f'
+			case '\r', '\n':
+				// A backslash at the end of a line continues the string
+				if c == '\r' && i < len(content) && content[i] == '\n' {
+					i++
+				}
+				continue
+			default:
+				if c >= '0' && c <= '7' {
+					n := int(c - '0')
+					for j := 0; j < 2 && i < len(content) && content[i] >= '0' && content[i] <= '7'; j++ {
+						n = n*8 + int(content[i]-'0')
+						i++
+					}
+					c = byte(n)
+				}
+			}
+		}
+		s = append(s, c)
+	}
+	return s, i
+}
+
+// readPDFHex reads a <hex> string starting after its opening bracket,
+// returning it and the offset after the closing one
+func readPDFHex(content []byte, i int) (pdfStringOperand, int) {
+	var digits []byte
+	for i < len(content) && content[i] != '>' {
+		if c := content[i]; isHexDigit(c) {
+			digits = append(digits, c)
+		}
+		i++
+	}
+	if len(digits)%2 == 1 {
+		digits = append(digits, '0')
+	}
+
+	s := make(pdfStringOperand, len(digits)/2)
+	for j := range s {
+		n, _ := strconv.ParseUint(string(digits[2*j:2*j+2]), 16, 8)
+		s[j] = byte(n)
+	}
+	return s, i + 1
+}
+
+func isPDFSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == '\f' || c == 0
+}
+
+func isPDFDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
+
+func isHexDigit(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
@@ -0,0 +1,173 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// readFixture returns the contents of a file in testdata
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// storedObject is an object held by the fake S3
+type storedObject struct {
+	contentType string
+	body        []byte
+}
+
+// newFakeS3Objects returns an upload service backed by a fake S3 serving
+// objects by key from the "uploads" bucket
+func newFakeS3Objects(t *testing.T, objects map[string]storedObject) *UploadService {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		object, ok := objects[strings.TrimPrefix(r.URL.Path, "/uploads/")]
+		if r.Method != http.MethodGet || !ok {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`<Error><Code>NoSuchKey</Code></Error>`))
+			return
+		}
+		w.Header().Set("Content-Type", object.contentType)
+		w.Write(object.body)
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+	})
+	return &UploadService{client: client, bucket: "uploads"}
+}
+
+func TestExtractPDFText(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    []byte
+		want    string
+		wantErr error
+	}{
+		{
+			name: "uncompressed",
+			data: readFixture(t, "resume.pdf"),
+			want: "Ada Lovelace\nSenior Backend Engineer\nSkills: Go, PostgreSQL (v16)",
+		},
+		{
+			name: "flate compressed with UTF-16 text",
+			data: readFixture(t, "resume_flate.pdf"),
+			want: "Ada Lovelace\nSenior Backend Engineer\nSkills: Go, PostgreSQL (v16)\nÉcole",
+		},
+		{
+			name:    "encrypted",
+			data:    readFixture(t, "resume_encrypted.pdf"),
+			wantErr: ErrEncryptedPDF,
+		},
+		{
+			name:    "not a PDF",
+			data:    []byte("PK\x03\x04 a docx"),
+			wantErr: ErrInvalidPDF,
+		},
+		{
+			name:    "no content streams",
+			data:    []byte("%PDF-1.4\n1 0 obj\n<< /Type /Catalog >>\nendobj\n%%EOF\n"),
+			wantErr: ErrInvalidPDF,
+		},
+		{
+			name: "truncated stream",
+			data: []byte("%PDF-1.4\n4 0 obj\n<< /Length 40 >>\nstream\nBT (Ada"),
+			// A stream without its end is never read
+			wantErr: ErrInvalidPDF,
+		},
+		{
+			name: "octal escapes and nested parentheses",
+			data: []byte("%PDF-1.4\n4 0 obj\n<< /Length 40 >>\nstream\nBT (Caf\\351 \\(Paris (FR)\\)) Tj ET\nendstream\nendobj\n"),
+			want: "Café (Paris (FR))",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := extractPDFText(tt.data)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("extractPDFText() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Fatalf("extractPDFText() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResumeParser_IndexResume(t *testing.T) {
+	uploads := newFakeS3Objects(t, map[string]storedObject{
+		"resumes/ada.pdf":       {contentType: "application/pdf", body: readFixture(t, "resume_flate.pdf")},
+		"resumes/locked.pdf":    {contentType: "application/pdf", body: readFixture(t, "resume_encrypted.pdf")},
+		"resumes/ada.docx":      {contentType: docxContentType, body: zipFile(t, "word/document.xml")},
+		"resumes/oversized.pdf": {contentType: "application/pdf", body: paddedPDF(maxResumeSize + 1)},
+	})
+
+	var indexed []map[string]interface{}
+	client := newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.IndexResumeTextMutation {
+			return map[string]interface{}{}
+		}
+		indexed = append(indexed, req.Variables)
+		return map[string]interface{}{"indexResumeText": map[string]interface{}{"applicationId": req.Variables["applicationId"]}}
+	})
+	parser := NewResumeParser(client, uploads)
+
+	text, err := parser.IndexResume(context.Background(), "app-1", "resumes/ada.pdf")
+	if err != nil {
+		t.Fatalf("IndexResume() error = %v", err)
+	}
+	if !strings.HasPrefix(text, "Ada Lovelace\n") {
+		t.Fatalf("text = %q, want the resume's text", text)
+	}
+	if len(indexed) != 1 || indexed[0]["applicationId"] != "app-1" || indexed[0]["text"] != text {
+		t.Fatalf("indexed = %v, want app-1's text", indexed)
+	}
+
+	tests := []struct {
+		key     string
+		wantErr error
+	}{
+		{key: "resumes/locked.pdf", wantErr: ErrEncryptedPDF},
+		{key: "resumes/ada.docx", wantErr: ErrInvalidPDF},
+		{key: "resumes/oversized.pdf", wantErr: ErrInvalidPDF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if _, err := parser.IndexResume(context.Background(), "app-2", tt.key); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("IndexResume() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	t.Run("missing object", func(t *testing.T) {
+		_, err := parser.IndexResume(context.Background(), "app-2", "resumes/missing.pdf")
+		if err == nil || errors.Is(err, ErrInvalidPDF) {
+			t.Fatalf("IndexResume() error = %v, want a download error", err)
+		}
+	})
+
+	if len(indexed) != 1 {
+		t.Fatalf("indexed %d resumes, want only the readable one", len(indexed))
+	}
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Salary ranges outside these multiples of the market range are flagged
+const (
+	salaryLowFactor  = 0.5 // more than 50% below the market minimum
+	salaryHighFactor = 3.0 // more than 200% above the market maximum
+)
+
+// SalaryBenchmark is the market salary range for a role at one experience
+// level
+type SalaryBenchmark struct {
+	JobTitle        string  `json:"jobTitle" yaml:"jobTitle"`
+	ExperienceLevel string  `json:"experienceLevel" yaml:"experienceLevel"`
+	Currency        string  `json:"currency" yaml:"currency"`
+	MinMarket       float64 `json:"minMarket" yaml:"minMarket"`
+	MaxMarket       float64 `json:"maxMarket" yaml:"maxMarket"`
+}
+
+// SalaryRange is a job's advertised salary range
+type SalaryRange struct {
+	Min      float64 `json:"min"`
+	Max      float64 `json:"max"`
+	Currency string  `json:"currency"`
+}
+
+// SalaryValidator compares advertised salary ranges with market benchmarks
+// to catch ranges that are likely typos or unrealistic
+type SalaryValidator struct {
+	benchmarks []SalaryBenchmark
+}
+
+// NewSalaryValidator creates a validator using benchmarks
+func NewSalaryValidator(benchmarks []SalaryBenchmark) *SalaryValidator {
+	return &SalaryValidator{benchmarks: benchmarks}
+}
+
+// LoadSalaryValidator reads benchmarks from a YAML list (JSON also parses).
+// An empty path yields a validator without benchmarks, which never warns.
+func LoadSalaryValidator(path string) (*SalaryValidator, error) {
+	if path == "" {
+		return NewSalaryValidator(nil), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read salary benchmarks: %w", err)
+	}
+
+	var benchmarks []SalaryBenchmark
+	if err := yaml.Unmarshal(data, &benchmarks); err != nil {
+		return nil, fmt.Errorf("invalid salary benchmarks file %s: %w", path, err)
+	}
+	for i, benchmark := range benchmarks {
+		if benchmark.JobTitle == "" || benchmark.Currency == "" || benchmark.MinMarket <= 0 || benchmark.MaxMarket < benchmark.MinMarket {
+			return nil, fmt.Errorf("salary benchmark %d needs a jobTitle, a currency and a valid market range", i)
+		}
+	}
+	return NewSalaryValidator(benchmarks), nil
+}
+
+// Validate returns warnings for a salary range far outside the market range
+// for the role. Roles without a benchmark in the range's currency are not
+// checked.
+func (v *SalaryValidator) Validate(title, experienceLevel string, salaryRange SalaryRange) []string {
+	warnings := []string{}
+	benchmark := v.benchmark(title, experienceLevel, salaryRange.Currency)
+	if benchmark == nil {
+		return warnings
+	}
+
+	if salaryRange.Min > 0 && salaryRange.Min < benchmark.MinMarket*salaryLowFactor {
+		warnings = append(warnings, fmt.Sprintf(
+			"Minimum salary %.0f %s is more than 50%% below the market minimum of %.0f %s for %s",
+			salaryRange.Min, salaryRange.Currency, benchmark.MinMarket, benchmark.Currency, describeBenchmark(benchmark)))
+	}
+	if salaryRange.Max > benchmark.MaxMarket*salaryHighFactor {
+		warnings = append(warnings, fmt.Sprintf(
+			"Maximum salary %.0f %s is more than 200%% above the market maximum of %.0f %s for %s",
+			salaryRange.Max, salaryRange.Currency, benchmark.MaxMarket, benchmark.Currency, describeBenchmark(benchmark)))
+	}
+	return warnings
+}
+
+// benchmark finds the benchmark for a job. Titles match when the job title
+// contains the benchmark title, so "Senior Backend Engineer (Remote)" uses a
+// "Backend Engineer" benchmark; the longest matching title wins.
+func (v *SalaryValidator) benchmark(title, experienceLevel, currency string) *SalaryBenchmark {
+	title = strings.ToLower(title)
+	var best *SalaryBenchmark
+	for i := range v.benchmarks {
+		benchmark := &v.benchmarks[i]
+		if !strings.EqualFold(benchmark.Currency, currency) ||
+			!strings.EqualFold(benchmark.ExperienceLevel, experienceLevel) ||
+			!strings.Contains(title, strings.ToLower(benchmark.JobTitle)) {
+			continue
+		}
+		if best == nil || len(benchmark.JobTitle) > len(best.JobTitle) {
+			best = benchmark
+		}
+	}
+	return best
+}
+
+func describeBenchmark(benchmark *SalaryBenchmark) string {
+	if benchmark.ExperienceLevel == "" {
+		return benchmark.JobTitle
+	}
+	return strings.ToLower(benchmark.ExperienceLevel) + " " + benchmark.JobTitle
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// testSalaryBenchmarks is a sample benchmark file: backend engineers at two
+// levels and in two currencies, a broader engineer benchmark and a recruiter
+const testSalaryBenchmarks = `
+- jobTitle: Backend Engineer
+  experienceLevel: JUNIOR
+  currency: USD
+  minMarket: 60000
+  maxMarket: 90000
+- jobTitle: Backend Engineer
+  experienceLevel: SENIOR
+  currency: USD
+  minMarket: 130000
+  maxMarket: 180000
+- jobTitle: Backend Engineer
+  experienceLevel: SENIOR
+  currency: EUR
+  minMarket: 90000
+  maxMarket: 130000
+- jobTitle: Engineer
+  experienceLevel: SENIOR
+  currency: USD
+  minMarket: 100000
+  maxMarket: 150000
+- jobTitle: Recruiter
+  experienceLevel: MID
+  currency: GBP
+  minMarket: 35000
+  maxMarket: 50000
+`
+
+// writeSalaryBenchmarks writes benchmarks to a file and returns its path
+func writeSalaryBenchmarks(t *testing.T, benchmarks string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "salary_benchmarks.yaml")
+	if err := os.WriteFile(path, []byte(benchmarks), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestSalaryValidator_Validate(t *testing.T) {
+	validator, err := LoadSalaryValidator(writeSalaryBenchmarks(t, testSalaryBenchmarks))
+	if err != nil {
+		t.Fatalf("LoadSalaryValidator() error = %v", err)
+	}
+
+	tests := []struct {
+		name            string
+		title           string
+		experienceLevel string
+		salary          SalaryRange
+		want            []string
+	}{
+		{
+			name: "senior within market", title: "Backend Engineer", experienceLevel: "SENIOR",
+			salary: SalaryRange{Min: 120000, Max: 200000, Currency: "USD"},
+		},
+		{
+			name: "senior far below market", title: "Backend Engineer", experienceLevel: "SENIOR",
+			salary: SalaryRange{Min: 20000, Max: 150000, Currency: "USD"},
+			want:   []string{"Minimum salary 20000 USD is more than 50% below the market minimum of 130000 USD for senior Backend Engineer"},
+		},
+		{
+			name: "senior at half the market minimum", title: "Backend Engineer", experienceLevel: "SENIOR",
+			salary: SalaryRange{Min: 65000, Max: 150000, Currency: "USD"},
+		},
+		{
+			name: "senior far above market", title: "Backend Engineer", experienceLevel: "SENIOR",
+			salary: SalaryRange{Min: 150000, Max: 600000, Currency: "USD"},
+			want:   []string{"Maximum salary 600000 USD is more than 200% above the market maximum of 180000 USD for senior Backend Engineer"},
+		},
+		{
+			name: "senior at three times the market maximum", title: "Backend Engineer", experienceLevel: "SENIOR",
+			salary: SalaryRange{Min: 150000, Max: 540000, Currency: "USD"},
+		},
+		{
+			name: "both ends out of range", title: "Backend Engineer", experienceLevel: "SENIOR",
+			salary: SalaryRange{Min: 10000, Max: 1000000, Currency: "USD"},
+			want: []string{
+				"Minimum salary 10000 USD is more than 50% below the market minimum of 130000 USD for senior Backend Engineer",
+				"Maximum salary 1000000 USD is more than 200% above the market maximum of 180000 USD for senior Backend Engineer",
+			},
+		},
+		{
+			name: "junior uses its own benchmark", title: "Backend Engineer", experienceLevel: "JUNIOR",
+			salary: SalaryRange{Min: 45000, Max: 80000, Currency: "USD"},
+		},
+		{
+			name: "junior far below market", title: "Backend Engineer", experienceLevel: "JUNIOR",
+			salary: SalaryRange{Min: 25000, Max: 80000, Currency: "USD"},
+			want:   []string{"Minimum salary 25000 USD is more than 50% below the market minimum of 60000 USD for junior Backend Engineer"},
+		},
+		{
+			name: "level and currency are case-insensitive", title: "Backend Engineer", experienceLevel: "senior",
+			salary: SalaryRange{Min: 20000, Max: 150000, Currency: "usd"},
+			want:   []string{"Minimum salary 20000 usd is more than 50% below the market minimum of 130000 USD for senior Backend Engineer"},
+		},
+		{
+			name: "longest matching title wins", title: "Senior Backend Engineer (Remote)", experienceLevel: "SENIOR",
+			salary: SalaryRange{Min: 60000, Max: 150000, Currency: "USD"},
+			want:   []string{"Minimum salary 60000 USD is more than 50% below the market minimum of 130000 USD for senior Backend Engineer"},
+		},
+		{
+			name: "broader title benchmark", title: "Frontend Engineer", experienceLevel: "SENIOR",
+			salary: SalaryRange{Min: 60000, Max: 150000, Currency: "USD"},
+		},
+		{
+			name: "benchmark in the range's currency", title: "Backend Engineer", experienceLevel: "SENIOR",
+			salary: SalaryRange{Min: 40000, Max: 120000, Currency: "EUR"},
+			want:   []string{"Minimum salary 40000 EUR is more than 50% below the market minimum of 90000 EUR for senior Backend Engineer"},
+		},
+		{
+			name: "no benchmark in the currency", title: "Backend Engineer", experienceLevel: "SENIOR",
+			salary: SalaryRange{Min: 1000, Max: 9000000, Currency: "JPY"},
+		},
+		{
+			name: "no benchmark for the level", title: "Recruiter", experienceLevel: "SENIOR",
+			salary: SalaryRange{Min: 1000, Max: 1000000, Currency: "GBP"},
+		},
+		{
+			name: "no minimum", title: "Recruiter", experienceLevel: "MID",
+			salary: SalaryRange{Max: 60000, Currency: "GBP"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validator.Validate(tt.title, tt.experienceLevel, tt.salary)
+			if got == nil {
+				t.Fatal("Validate() = nil, want an empty list when there is nothing to warn about")
+			}
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Fatalf("Validate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadSalaryValidator(t *testing.T) {
+	t.Run("no file", func(t *testing.T) {
+		validator, err := LoadSalaryValidator("")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if warnings := validator.Validate("Backend Engineer", "SENIOR", SalaryRange{Min: 1, Max: 1e9, Currency: "USD"}); len(warnings) != 0 {
+			t.Fatalf("Validate() = %v, want no warnings without benchmarks", warnings)
+		}
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		validator, err := LoadSalaryValidator(writeSalaryBenchmarks(t,
+			`[{"jobTitle":"Recruiter","experienceLevel":"MID","currency":"GBP","minMarket":35000,"maxMarket":50000}]`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if warnings := validator.Validate("Recruiter", "MID", SalaryRange{Min: 10000, Max: 40000, Currency: "GBP"}); len(warnings) != 1 {
+			t.Fatalf("Validate() = %v, want the low minimum flagged", warnings)
+		}
+	})
+
+	tests := []struct {
+		name       string
+		benchmarks string
+		wantErr    string
+	}{
+		{name: "not YAML", benchmarks: "- jobTitle: [Recruiter", wantErr: "invalid salary benchmarks file"},
+		{name: "not a sequence", benchmarks: "jobTitle: Recruiter", wantErr: "invalid salary benchmarks file"},
+		{name: "missing title", benchmarks: "- currency: GBP\n  minMarket: 1\n  maxMarket: 2", wantErr: "salary benchmark 0"},
+		{name: "missing currency", benchmarks: "- jobTitle: Recruiter\n  minMarket: 1\n  maxMarket: 2", wantErr: "salary benchmark 0"},
+		{name: "inverted range", benchmarks: testSalaryBenchmarks + "- jobTitle: Recruiter\n  currency: GBP\n  minMarket: 50000\n  maxMarket: 35000", wantErr: "salary benchmark 5"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadSalaryValidator(writeSalaryBenchmarks(t, tt.benchmarks)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("LoadSalaryValidator() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := LoadSalaryValidator(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatal("LoadSalaryValidator() accepted a missing file")
+	}
+}
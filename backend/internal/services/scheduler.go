@@ -0,0 +1,285 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// expiredJobsBatch is how many expired jobs are closed per tick
+const expiredJobsBatch = 100
+
+// periodicTask runs a function on a fixed interval in the background
+type periodicTask struct {
+	interval time.Duration
+	run      func(ctx context.Context)
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+func newPeriodicTask(interval time.Duration, run func(ctx context.Context)) *periodicTask {
+	return &periodicTask{
+		interval: interval,
+		run:      run,
+		done:     make(chan struct{}),
+	}
+}
+
+// Start runs the task in the background until Shutdown is called
+func (t *periodicTask) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.cancel = cancel
+
+	go func() {
+		defer close(t.done)
+
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				t.run(ctx)
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+}
+
+// Shutdown stops the task, waiting for an in-flight run to finish or for ctx
+// to expire
+func (t *periodicTask) Shutdown(ctx context.Context) error {
+	if t.cancel == nil {
+		return nil
+	}
+	t.once.Do(t.cancel)
+
+	select {
+	case <-t.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// JobScheduler periodically closes published jobs past their closing date
+type JobScheduler struct {
+	*periodicTask
+
+	client   *gateway.HubHRMSClient
+	webhooks *WebhookService
+}
+
+// NewJobScheduler creates a scheduler that checks for expired jobs every
+// interval
+func NewJobScheduler(client *gateway.HubHRMSClient, webhooks *WebhookService, interval time.Duration) *JobScheduler {
+	s := &JobScheduler{
+		client:   client,
+		webhooks: webhooks,
+	}
+	s.periodicTask = newPeriodicTask(interval, s.CloseExpiredJobs)
+	return s
+}
+
+// CloseExpiredJobs closes every published job whose closing date has passed.
+// A failure on one job is logged and does not stop the others.
+func (s *JobScheduler) CloseExpiredJobs(ctx context.Context) {
+	resp, err := s.client.Query(ctx, gateway.GetExpiredJobsQuery, map[string]interface{}{
+		"closingBefore": time.Now().UTC().Format(time.RFC3339),
+		"limit":         expiredJobsBatch,
+	})
+	if err != nil {
+		slog.Error("failed to fetch expired jobs", "error", err)
+		return
+	}
+
+	data, _ := resp.Data.(map[string]interface{})
+	jobs, _ := data["jobs"].([]interface{})
+
+	closed := 0
+	for _, item := range jobs {
+		job, _ := item.(map[string]interface{})
+		jobID, _ := job["id"].(string)
+		if jobID == "" {
+			continue
+		}
+
+		if _, err := s.client.Mutate(ctx, gateway.CloseJobMutation, map[string]interface{}{"id": jobID}); err != nil {
+			slog.Error("failed to close expired job", "job_id", jobID, "error", err)
+			continue
+		}
+		closed++
+
+		title, _ := job["title"].(string)
+		s.webhooks.Publish(EventJobClosed, JobEventData{JobID: jobID, Title: title, Status: "CLOSED"})
+	}
+
+	if len(jobs) > 0 {
+		slog.Info("closed expired jobs", "closed", closed, "expired", len(jobs))
+	}
+}
+
+// interviewReminderInterval is how often upcoming interviews are checked
+const interviewReminderInterval = time.Hour
+
+// upcomingInterviewsBatch is how many interviews are reminded per tick
+const upcomingInterviewsBatch = 500
+
+// reminderThreshold is a lead time at which a reminder goes out
+type reminderThreshold struct {
+	key      string
+	lead     time.Duration
+	startsIn string
+}
+
+// reminderThresholds are checked shortest first, so an interview booked at
+// short notice only gets the most urgent reminder that applies
+var reminderThresholds = []reminderThreshold{
+	{key: "1h", lead: time.Hour, startsIn: "1 hour"},
+	{key: "24h", lead: 24 * time.Hour, startsIn: "24 hours"},
+}
+
+// InterviewReminderScheduler emails candidates and interviewers ahead of
+// scheduled interviews
+type InterviewReminderScheduler struct {
+	*periodicTask
+
+	client *gateway.HubHRMSClient
+	emails *EmailQueue
+
+	// sent holds interviewId+threshold keys already reminded, mapped to the
+	// interview start so past entries can be pruned
+	sent sync.Map
+}
+
+// NewInterviewReminderScheduler creates a scheduler that checks for upcoming
+// interviews every hour
+func NewInterviewReminderScheduler(client *gateway.HubHRMSClient, emails *EmailQueue) *InterviewReminderScheduler {
+	s := &InterviewReminderScheduler{
+		client: client,
+		emails: emails,
+	}
+	s.periodicTask = newPeriodicTask(interviewReminderInterval, s.SendReminders)
+	return s
+}
+
+// SendReminders sends the 24 hour and 1 hour reminders that have come due.
+// Reminders already recorded in Hub-HRMS or sent by this process are skipped.
+func (s *InterviewReminderScheduler) SendReminders(ctx context.Context) {
+	now := time.Now()
+	s.prune(now)
+
+	longest := reminderThresholds[len(reminderThresholds)-1].lead
+	resp, err := s.client.Query(ctx, gateway.GetUpcomingInterviewsQuery, map[string]interface{}{
+		"scheduledAfter":  now.UTC().Format(time.RFC3339),
+		"scheduledBefore": now.Add(longest).UTC().Format(time.RFC3339),
+		"limit":           upcomingInterviewsBatch,
+	})
+	if err != nil {
+		slog.Error("failed to fetch upcoming interviews", "error", err)
+		return
+	}
+
+	data, _ := resp.Data.(map[string]interface{})
+	interviews, _ := data["interviews"].([]interface{})
+
+	sent := 0
+	for _, item := range interviews {
+		interview, _ := item.(map[string]interface{})
+		interviewID, _ := interview["id"].(string)
+		scheduledAt, _ := interview["scheduledAt"].(string)
+		start, err := time.Parse(time.RFC3339, scheduledAt)
+		if interviewID == "" || err != nil {
+			continue
+		}
+
+		threshold, ok := dueThreshold(start.Sub(now))
+		if !ok || s.alreadySent(interview, interviewID, threshold.key) {
+			continue
+		}
+
+		s.remind(interview, start, threshold)
+		s.sent.Store(interviewID+threshold.key, start)
+		sent++
+
+		if _, err := s.client.Mutate(ctx, gateway.MarkReminderSentMutation, map[string]interface{}{
+			"interviewId": interviewID,
+			"reminder":    threshold.key,
+		}); err != nil {
+			slog.Error("failed to record interview reminder", "interview_id", interviewID, "reminder", threshold.key, "error", err)
+		}
+	}
+
+	if sent > 0 {
+		slog.Info("sent interview reminders", "sent", sent, "upcoming", len(interviews))
+	}
+}
+
+// dueThreshold returns the shortest threshold an interview starting in
+// until falls within
+func dueThreshold(until time.Duration) (reminderThreshold, bool) {
+	if until <= 0 {
+		return reminderThreshold{}, false
+	}
+	for _, threshold := range reminderThresholds {
+		if until <= threshold.lead {
+			return threshold, true
+		}
+	}
+	return reminderThreshold{}, false
+}
+
+// alreadySent reports whether the reminder went out on an earlier tick or,
+// before a restart, was recorded against the interview in Hub-HRMS
+func (s *InterviewReminderScheduler) alreadySent(interview map[string]interface{}, interviewID, key string) bool {
+	if _, ok := s.sent.Load(interviewID + key); ok {
+		return true
+	}
+	recorded, _ := interview["remindersSent"].([]interface{})
+	for _, r := range recorded {
+		if r == key {
+			return true
+		}
+	}
+	return false
+}
+
+// remind queues the reminder for the candidate and each interviewer
+func (s *InterviewReminderScheduler) remind(interview map[string]interface{}, start time.Time, threshold reminderThreshold) {
+	application, _ := interview["application"].(map[string]interface{})
+	candidate, _ := application["candidate"].(map[string]interface{})
+	job, _ := application["job"].(map[string]interface{})
+
+	jobTitle, _ := job["title"].(string)
+	interviewDate := start.UTC().Format(InterviewDateFormat)
+
+	if email, _ := candidate["email"].(string); email != "" {
+		firstName, _ := candidate["firstName"].(string)
+		s.emails.Enqueue(InterviewReminderEmail(email, firstName, jobTitle, interviewDate, threshold.startsIn))
+	}
+
+	interviewers, _ := interview["interviewers"].([]interface{})
+	for _, item := range interviewers {
+		interviewer, _ := item.(map[string]interface{})
+		if email, _ := interviewer["email"].(string); email != "" {
+			name, _ := interviewer["name"].(string)
+			s.emails.Enqueue(InterviewReminderEmail(email, name, jobTitle, interviewDate, threshold.startsIn))
+		}
+	}
+}
+
+// prune forgets reminders for interviews that have already started
+func (s *InterviewReminderScheduler) prune(now time.Time) {
+	s.sent.Range(func(key, value interface{}) bool {
+		if start, _ := value.(time.Time); start.Before(now) {
+			s.sent.Delete(key)
+		}
+		return true
+	})
+}
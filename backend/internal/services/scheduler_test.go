@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// fakeJobBoard is a fake Hub-HRMS holding published jobs. It answers
+// GetExpiredJobsQuery by comparing closing dates, and CloseJobMutation by
+// closing the job, except for jobs in locked, which it answers with an
+// error.
+type fakeJobBoard struct {
+	mu     sync.Mutex
+	jobs   map[string]time.Time
+	locked map[string]bool
+	closed []string
+}
+
+func (b *fakeJobBoard) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req gateway.GraphQLRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch req.Query {
+	case gateway.GetExpiredJobsQuery:
+		before, err := time.Parse(time.RFC3339, req.Variables["closingBefore"].(string))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		jobs := []interface{}{}
+		for id, closing := range b.jobs {
+			if closing.Before(before) {
+				jobs = append(jobs, map[string]interface{}{"id": id, "title": "Job " + id, "closingDate": closing.Format(time.RFC3339)})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{"jobs": jobs}})
+	case gateway.CloseJobMutation:
+		id := req.Variables["id"].(string)
+		if b.locked[id] {
+			json.NewEncoder(w).Encode(map[string]interface{}{"errors": []map[string]string{{"message": "job is locked"}}})
+			return
+		}
+		delete(b.jobs, id)
+		b.closed = append(b.closed, id)
+		json.NewEncoder(w).Encode(map[string]interface{}{"data": map[string]interface{}{
+			"closeJob": map[string]interface{}{"id": id, "status": "CLOSED"},
+		}})
+	default:
+		http.Error(w, "unexpected query", http.StatusBadRequest)
+	}
+}
+
+func (b *fakeJobBoard) closedJobs() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	closed := slices.Clone(b.closed)
+	slices.Sort(closed)
+	return closed
+}
+
+func newFakeJobBoard(t *testing.T, jobs map[string]time.Time, locked ...string) (*fakeJobBoard, *gateway.HubHRMSClient) {
+	t.Helper()
+	board := &fakeJobBoard{jobs: jobs, locked: make(map[string]bool)}
+	for _, id := range locked {
+		board.locked[id] = true
+	}
+	server := httptest.NewServer(board)
+	t.Cleanup(server.Close)
+
+	client := gateway.NewHubHRMSClient(server.URL, "")
+	t.Cleanup(client.Close)
+	return board, client
+}
+
+func TestJobScheduler_CloseExpiredJobs(t *testing.T) {
+	now := time.Now()
+	board, client := newFakeJobBoard(t, map[string]time.Time{
+		"expired-1": now.Add(-48 * time.Hour),
+		"expired-2": now.Add(-time.Minute),
+		"locked":    now.Add(-time.Hour),
+		"active-1":  now.Add(time.Hour),
+		"active-2":  now.AddDate(0, 1, 0),
+	}, "locked")
+	scheduler := NewJobScheduler(client, NewWebhookService(func() bool { return false }), time.Hour)
+
+	scheduler.CloseExpiredJobs(context.Background())
+
+	// The locked job fails to close without stopping the others
+	if closed := board.closedJobs(); !slices.Equal(closed, []string{"expired-1", "expired-2"}) {
+		t.Fatalf("closed %v, want only the expired jobs", closed)
+	}
+	board.mu.Lock()
+	_, active1 := board.jobs["active-1"]
+	_, active2 := board.jobs["active-2"]
+	board.mu.Unlock()
+	if !active1 || !active2 {
+		t.Fatal("an active job was closed")
+	}
+}
+
+func TestJobScheduler_RunsOnInterval(t *testing.T) {
+	board, client := newFakeJobBoard(t, map[string]time.Time{
+		"expired": time.Now().Add(-time.Hour),
+	})
+	scheduler := NewJobScheduler(client, NewWebhookService(func() bool { return false }), 10*time.Millisecond)
+	scheduler.Start()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for len(board.closedJobs()) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("expired job was not closed on a tick")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := scheduler.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+	if err := scheduler.Shutdown(ctx); err != nil {
+		t.Fatalf("second Shutdown() error = %v", err)
+	}
+	if closed := board.closedJobs(); len(closed) != 1 {
+		t.Fatalf("closed %v, want the expired job closed once", closed)
+	}
+}
+
+// fakeInterviewCalendar is a fake Hub-HRMS answering
+// GetUpcomingInterviewsQuery with its interviews and recording the reminders
+// marked sent
+type fakeInterviewCalendar struct {
+	mu         sync.Mutex
+	interviews []interface{}
+	marked     []string
+	windows    []time.Duration
+}
+
+func (c *fakeInterviewCalendar) respond(req gateway.GraphQLRequest) interface{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch req.Query {
+	case gateway.GetUpcomingInterviewsQuery:
+		after, _ := time.Parse(time.RFC3339, req.Variables["scheduledAfter"].(string))
+		before, _ := time.Parse(time.RFC3339, req.Variables["scheduledBefore"].(string))
+		c.windows = append(c.windows, before.Sub(after))
+		return map[string]interface{}{"interviews": c.interviews}
+	case gateway.MarkReminderSentMutation:
+		c.marked = append(c.marked, req.Variables["interviewId"].(string)+"/"+req.Variables["reminder"].(string))
+	}
+	return map[string]interface{}{}
+}
+
+func (c *fakeInterviewCalendar) set(interviews ...interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.interviews = interviews
+}
+
+func (c *fakeInterviewCalendar) markedReminders() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	marked := slices.Clone(c.marked)
+	slices.Sort(marked)
+	return marked
+}
+
+// upcomingInterview is an interview starting at start with one interviewer,
+// listing the reminders Hub-HRMS has recorded for it
+func upcomingInterview(id string, start time.Time, remindersSent ...interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"id":            id,
+		"scheduledAt":   start.UTC().Format(time.RFC3339),
+		"remindersSent": remindersSent,
+		"interviewers": []interface{}{
+			map[string]interface{}{"name": "Grace", "email": "grace+" + id + "@example.com"},
+		},
+		"application": map[string]interface{}{
+			"candidate": map[string]interface{}{"firstName": "Ada", "email": "ada+" + id + "@example.com"},
+			"job":       map[string]interface{}{"title": "Backend Engineer"},
+		},
+	}
+}
+
+// newTestReminderScheduler returns a scheduler whose queue holds emails
+// instead of sending them, and a function draining what it holds as
+// "to/startsIn" entries
+func newTestReminderScheduler(t *testing.T, calendar *fakeInterviewCalendar) (*InterviewReminderScheduler, func() []string) {
+	t.Helper()
+	queue := &EmailQueue{jobs: make(chan EmailJob, 100)}
+	scheduler := NewInterviewReminderScheduler(newFakeHubHRMS(t, calendar.respond), queue)
+
+	drain := func() []string {
+		var sent []string
+		for len(queue.jobs) > 0 {
+			job := <-queue.jobs
+			if job.Template != "interview_reminder" {
+				t.Fatalf("template = %s, want interview_reminder", job.Template)
+			}
+			sent = append(sent, job.To+"/"+job.Data["StartsIn"].(string))
+		}
+		slices.Sort(sent)
+		return sent
+	}
+	return scheduler, drain
+}
+
+func TestInterviewReminderScheduler_SendReminders(t *testing.T) {
+	now := time.Now()
+	calendar := &fakeInterviewCalendar{}
+	calendar.set(
+		upcomingInterview("tomorrow", now.Add(23*time.Hour)),
+		upcomingInterview("soon", now.Add(30*time.Minute)),
+		upcomingInterview("later", now.Add(30*time.Hour)),
+		upcomingInterview("started", now.Add(-time.Minute)),
+		upcomingInterview("recorded", now.Add(20*time.Hour), "24h"),
+	)
+	scheduler, drain := newTestReminderScheduler(t, calendar)
+
+	scheduler.SendReminders(context.Background())
+
+	// Interviews within 1 hour get only the 1 hour reminder, those within
+	// 24 hours the 24 hour one; later, started and recorded ones get none
+	want := []string{
+		"ada+soon@example.com/1 hour",
+		"ada+tomorrow@example.com/24 hours",
+		"grace+soon@example.com/1 hour",
+		"grace+tomorrow@example.com/24 hours",
+	}
+	if sent := drain(); !slices.Equal(sent, want) {
+		t.Fatalf("sent %v, want %v", sent, want)
+	}
+	if marked := calendar.markedReminders(); !slices.Equal(marked, []string{"soon/1h", "tomorrow/24h"}) {
+		t.Fatalf("marked %v, want soon/1h and tomorrow/24h", marked)
+	}
+	calendar.mu.Lock()
+	window := calendar.windows[0]
+	calendar.mu.Unlock()
+	if window < 24*time.Hour-time.Second || window > 24*time.Hour+time.Second {
+		t.Fatalf("queried a %v window, want 24h", window)
+	}
+
+	t.Run("not duplicated on the next tick", func(t *testing.T) {
+		scheduler.SendReminders(context.Background())
+		if sent := drain(); len(sent) != 0 {
+			t.Fatalf("sent %v again", sent)
+		}
+		if marked := calendar.markedReminders(); len(marked) != 2 {
+			t.Fatalf("marked %v, want no new reminders", marked)
+		}
+	})
+
+	t.Run("1 hour reminder follows the 24 hour one", func(t *testing.T) {
+		calendar.set(
+			upcomingInterview("tomorrow", now.Add(45*time.Minute), "24h"),
+			upcomingInterview("soon", now.Add(30*time.Minute), "1h"),
+		)
+		scheduler.SendReminders(context.Background())
+
+		want := []string{"ada+tomorrow@example.com/1 hour", "grace+tomorrow@example.com/1 hour"}
+		if sent := drain(); !slices.Equal(sent, want) {
+			t.Fatalf("sent %v, want %v", sent, want)
+		}
+	})
+}
+
+func TestInterviewReminderScheduler_RecordedRemindersSurviveRestart(t *testing.T) {
+	calendar := &fakeInterviewCalendar{}
+	calendar.set(upcomingInterview("soon", time.Now().Add(30*time.Minute), "1h"))
+	scheduler, drain := newTestReminderScheduler(t, calendar)
+
+	scheduler.SendReminders(context.Background())
+
+	if sent := drain(); len(sent) != 0 {
+		t.Fatalf("sent %v for a reminder Hub-HRMS already recorded", sent)
+	}
+	if marked := calendar.markedReminders(); len(marked) != 0 {
+		t.Fatalf("marked %v, want none", marked)
+	}
+}
+
+func TestInterviewReminderScheduler_PrunesStartedInterviews(t *testing.T) {
+	calendar := &fakeInterviewCalendar{}
+	scheduler, _ := newTestReminderScheduler(t, calendar)
+	now := time.Now()
+	scheduler.sent.Store("started1h", now.Add(-time.Minute))
+	scheduler.sent.Store("upcoming1h", now.Add(time.Minute))
+
+	scheduler.prune(now)
+
+	if _, ok := scheduler.sent.Load("started1h"); ok {
+		t.Fatal("reminder for a started interview was kept")
+	}
+	if _, ok := scheduler.sent.Load("upcoming1h"); !ok {
+		t.Fatal("reminder for an upcoming interview was pruned")
+	}
+}
+
+func TestDueThreshold(t *testing.T) {
+	tests := []struct {
+		until time.Duration
+		want  string
+	}{
+		{until: 30 * time.Minute, want: "1h"},
+		{until: time.Hour, want: "1h"},
+		{until: time.Hour + time.Minute, want: "24h"},
+		{until: 24 * time.Hour, want: "24h"},
+		{until: 24*time.Hour + time.Minute},
+		{until: 0},
+		{until: -time.Hour},
+	}
+	for _, tt := range tests {
+		threshold, ok := dueThreshold(tt.until)
+		if ok != (tt.want != "") || threshold.key != tt.want {
+			t.Errorf("dueThreshold(%v) = %q, %v, want %q", tt.until, threshold.key, ok, tt.want)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// AI score recommendations
+const (
+	RecommendationStrongProceed = "STRONG_PROCEED"
+	RecommendationProceed       = "PROCEED"
+	RecommendationDoNotProceed  = "DO_NOT_PROCEED"
+)
+
+// scoringFeedbackPageSize is how much feedback Accuracy fetches per request
+const scoringFeedbackPageSize = 500
+
+// ErrInvalidScoringFeedback is returned for disagreements without a reason
+var ErrInvalidScoringFeedback = errors.New("invalid scoring feedback")
+
+// ScoringFeedback is a recruiter's verdict on an application's AI score.
+// Automatic feedback comes from a status change that went against the
+// recommendation.
+type ScoringFeedback struct {
+	ApplicationID   string `json:"applicationId"`
+	AgreesWithScore bool   `json:"agreesWithScore"`
+	OverrideReason  string `json:"overrideReason,omitempty"`
+	Automatic       bool   `json:"automatic"`
+	SubmittedBy     string `json:"submittedBy,omitempty"`
+}
+
+// ScoringAgreement counts feedback and the share of it agreeing with the score
+type ScoringAgreement struct {
+	Feedback      int     `json:"feedback"`
+	Agreed        int     `json:"agreed"`
+	AgreementRate float64 `json:"agreementRate"`
+}
+
+func (a *ScoringAgreement) add(agrees bool) {
+	a.Feedback++
+	if agrees {
+		a.Agreed++
+	}
+	a.AgreementRate = float64(a.Agreed) / float64(a.Feedback)
+}
+
+// JobScoringAgreement is the agreement with AI scores for one job
+type JobScoringAgreement struct {
+	JobID    string `json:"jobId"`
+	JobTitle string `json:"jobTitle,omitempty"`
+	ScoringAgreement
+}
+
+// LevelScoringAgreement is the agreement with AI scores for candidates of one
+// experience level
+type LevelScoringAgreement struct {
+	Level string `json:"level"`
+	ScoringAgreement
+}
+
+// ScoringAccuracy is how often recruiters agreed with AI scores, overall and
+// by job and experience level
+type ScoringAccuracy struct {
+	Overall ScoringAgreement        `json:"overall"`
+	ByJob   []JobScoringAgreement   `json:"byJob"`
+	ByLevel []LevelScoringAgreement `json:"byExperienceLevel"`
+}
+
+// ScoringFeedbackRecord is stored feedback with what it is grouped by
+type ScoringFeedbackRecord struct {
+	AgreesWithScore bool `json:"agreesWithScore"`
+	Application     struct {
+		Job struct {
+			ID    string `json:"id"`
+			Title string `json:"title"`
+		} `json:"job"`
+		Candidate struct {
+			YearsOfExperience *float64 `json:"yearsOfExperience"`
+		} `json:"candidate"`
+	} `json:"application"`
+}
+
+// ScoringFeedbackService records feedback on AI scores in Hub-HRMS, so
+// scoring can learn from recruiters overriding it
+type ScoringFeedbackService struct {
+	client *gateway.HubHRMSClient
+}
+
+// NewScoringFeedbackService creates a new scoring feedback service
+func NewScoringFeedbackService(client *gateway.HubHRMSClient) *ScoringFeedbackService {
+	return &ScoringFeedbackService{client: client}
+}
+
+// Submit records feedback on an application's AI score. Disagreeing
+// feedback given by hand needs a reason.
+func (s *ScoringFeedbackService) Submit(ctx context.Context, feedback ScoringFeedback) error {
+	if !feedback.AgreesWithScore && !feedback.Automatic && feedback.OverrideReason == "" {
+		return fmt.Errorf("%w: overrideReason is required when disagreeing with the score", ErrInvalidScoringFeedback)
+	}
+
+	input := map[string]interface{}{
+		"applicationId":   feedback.ApplicationID,
+		"agreesWithScore": feedback.AgreesWithScore,
+		"automatic":       feedback.Automatic,
+	}
+	if feedback.OverrideReason != "" {
+		input["overrideReason"] = feedback.OverrideReason
+	}
+	if feedback.SubmittedBy != "" {
+		input["submittedBy"] = feedback.SubmittedBy
+	}
+
+	resp, err := s.client.Mutate(ctx, gateway.SubmitScoringFeedbackMutation, map[string]interface{}{
+		"input": input,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to submit scoring feedback: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return fmt.Errorf("failed to submit scoring feedback: %s", resp.Errors[0].Message)
+	}
+	return nil
+}
+
+// Accuracy aggregates the feedback given between start and end
+func (s *ScoringFeedbackService) Accuracy(ctx context.Context, start, end time.Time) (*ScoringAccuracy, error) {
+	var records []ScoringFeedbackRecord
+	for offset := 0; ; offset += scoringFeedbackPageSize {
+		resp, err := s.client.Query(ctx, gateway.GetScoringFeedbackQuery, map[string]interface{}{
+			"dateRange": map[string]string{
+				"start": start.Format(time.RFC3339),
+				"end":   end.Format(time.RFC3339),
+			},
+			"limit":  scoringFeedbackPageSize,
+			"offset": offset,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch scoring feedback: %w", err)
+		}
+
+		var page []ScoringFeedbackRecord
+		if _, err := decodeField(resp.Data, "scoringFeedback", &page); err != nil {
+			return nil, err
+		}
+		records = append(records, page...)
+		if len(page) < scoringFeedbackPageSize {
+			break
+		}
+	}
+
+	accuracy := SummarizeScoringAccuracy(records)
+	return &accuracy, nil
+}
+
+// SummarizeScoringAccuracy groups feedback by job and by experience level.
+// Jobs are listed by most feedback first; levels from junior to lead.
+func SummarizeScoringAccuracy(records []ScoringFeedbackRecord) ScoringAccuracy {
+	var accuracy ScoringAccuracy
+	byJob := make(map[string]*JobScoringAgreement)
+	byLevel := make(map[string]*ScoringAgreement)
+
+	for _, record := range records {
+		accuracy.Overall.add(record.AgreesWithScore)
+
+		job := record.Application.Job
+		if job.ID != "" {
+			agreement, ok := byJob[job.ID]
+			if !ok {
+				agreement = &JobScoringAgreement{JobID: job.ID, JobTitle: job.Title}
+				byJob[job.ID] = agreement
+			}
+			agreement.add(record.AgreesWithScore)
+		}
+
+		level := ExperienceLevel(record.Application.Candidate.YearsOfExperience)
+		if byLevel[level] == nil {
+			byLevel[level] = &ScoringAgreement{}
+		}
+		byLevel[level].add(record.AgreesWithScore)
+	}
+
+	accuracy.ByJob = make([]JobScoringAgreement, 0, len(byJob))
+	for _, agreement := range byJob {
+		accuracy.ByJob = append(accuracy.ByJob, *agreement)
+	}
+	sort.Slice(accuracy.ByJob, func(i, j int) bool {
+		a, b := accuracy.ByJob[i], accuracy.ByJob[j]
+		if a.Feedback != b.Feedback {
+			return a.Feedback > b.Feedback
+		}
+		return a.JobID < b.JobID
+	})
+
+	accuracy.ByLevel = []LevelScoringAgreement{}
+	for _, level := range experienceLevels {
+		if agreement, ok := byLevel[level]; ok {
+			accuracy.ByLevel = append(accuracy.ByLevel, LevelScoringAgreement{Level: level, ScoringAgreement: *agreement})
+		}
+	}
+	return accuracy
+}
+
+// experienceLevels lists ExperienceLevel's results in order
+var experienceLevels = []string{"junior", "mid", "senior", "lead", "unknown"}
+
+// ExperienceLevel buckets years of experience: up to 2 years is junior, up to
+// 5 mid, up to 9 senior and 10 or more lead
+func ExperienceLevel(years *float64) string {
+	switch {
+	case years == nil:
+		return "unknown"
+	case *years < 3:
+		return "junior"
+	case *years < 6:
+		return "mid"
+	case *years < 10:
+		return "senior"
+	default:
+		return "lead"
+	}
+}
+
+// ContradictsRecommendation reports whether moving an application to status
+// goes against its AI recommendation: advancing a candidate the score said
+// not to proceed with, or rejecting one it recommended. A withdrawal is the
+// candidate's choice and says nothing about the score.
+func ContradictsRecommendation(recommendation, status string) bool {
+	switch recommendation {
+	case RecommendationDoNotProceed:
+		switch status {
+		case "SCREENING", "INTERVIEW", "OFFER", "HIRED":
+			return true
+		}
+	case RecommendationProceed, RecommendationStrongProceed:
+		return status == "REJECTED"
+	}
+	return false
+}
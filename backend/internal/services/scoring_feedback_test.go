@@ -0,0 +1,227 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+func TestContradictsRecommendation(t *testing.T) {
+	tests := []struct {
+		recommendation string
+		status         string
+		want           bool
+	}{
+		{recommendation: RecommendationDoNotProceed, status: "SCREENING", want: true},
+		{recommendation: RecommendationDoNotProceed, status: "INTERVIEW", want: true},
+		{recommendation: RecommendationDoNotProceed, status: "OFFER", want: true},
+		{recommendation: RecommendationDoNotProceed, status: "HIRED", want: true},
+		{recommendation: RecommendationDoNotProceed, status: "REJECTED"},
+		{recommendation: RecommendationDoNotProceed, status: "WITHDRAWN"},
+		{recommendation: RecommendationProceed, status: "REJECTED", want: true},
+		{recommendation: RecommendationStrongProceed, status: "REJECTED", want: true},
+		{recommendation: RecommendationProceed, status: "INTERVIEW"},
+		{recommendation: RecommendationStrongProceed, status: "WITHDRAWN"},
+		{recommendation: "", status: "REJECTED"},
+		{recommendation: "", status: "OFFER"},
+		{recommendation: "MAYBE", status: "REJECTED"},
+	}
+	for _, tt := range tests {
+		if got := ContradictsRecommendation(tt.recommendation, tt.status); got != tt.want {
+			t.Errorf("ContradictsRecommendation(%q, %q) = %v, want %v", tt.recommendation, tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestExperienceLevel(t *testing.T) {
+	years := func(y float64) *float64 { return &y }
+	tests := []struct {
+		years *float64
+		want  string
+	}{
+		{years: nil, want: "unknown"},
+		{years: years(0), want: "junior"},
+		{years: years(2.5), want: "junior"},
+		{years: years(3), want: "mid"},
+		{years: years(5), want: "mid"},
+		{years: years(6), want: "senior"},
+		{years: years(9.5), want: "senior"},
+		{years: years(10), want: "lead"},
+		{years: years(25), want: "lead"},
+	}
+	for _, tt := range tests {
+		if got := ExperienceLevel(tt.years); got != tt.want {
+			t.Errorf("ExperienceLevel(%v) = %q, want %q", tt.years, got, tt.want)
+		}
+	}
+}
+
+// feedbackRecord is stored feedback for jobID from a candidate with years of
+// experience, or with no experience recorded when years is negative
+func feedbackRecord(agrees bool, jobID string, years float64) map[string]interface{} {
+	candidate := map[string]interface{}{"yearsOfExperience": nil}
+	if years >= 0 {
+		candidate["yearsOfExperience"] = years
+	}
+	return map[string]interface{}{
+		"agreesWithScore": agrees,
+		"application": map[string]interface{}{
+			"job":       map[string]interface{}{"id": jobID, "title": "Title of " + jobID},
+			"candidate": candidate,
+		},
+	}
+}
+
+// feedbackService returns a scoring feedback service whose Hub-HRMS stores
+// records, and the inputs of the feedback submitted to it
+func feedbackService(t *testing.T, records []interface{}) (*ScoringFeedbackService, func() []map[string]interface{}) {
+	var mu sync.Mutex
+	var submitted []map[string]interface{}
+	client := newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+		switch req.Query {
+		case gateway.SubmitScoringFeedbackMutation:
+			input, _ := req.Variables["input"].(map[string]interface{})
+			mu.Lock()
+			submitted = append(submitted, input)
+			mu.Unlock()
+			return map[string]interface{}{"submitScoringFeedback": input}
+		case gateway.GetScoringFeedbackQuery:
+			offset := int(req.Variables["offset"].(float64))
+			limit := int(req.Variables["limit"].(float64))
+			page := records[min(offset, len(records)):min(offset+limit, len(records))]
+			return map[string]interface{}{"scoringFeedback": page}
+		}
+		return map[string]interface{}{}
+	})
+	return NewScoringFeedbackService(client), func() []map[string]interface{} {
+		mu.Lock()
+		defer mu.Unlock()
+		return submitted
+	}
+}
+
+func TestScoringFeedbackService_Submit(t *testing.T) {
+	tests := []struct {
+		name      string
+		feedback  ScoringFeedback
+		wantErr   bool
+		wantInput map[string]interface{}
+	}{
+		{
+			name:      "agrees",
+			feedback:  ScoringFeedback{ApplicationID: "app-1", AgreesWithScore: true, SubmittedBy: "user-1"},
+			wantInput: map[string]interface{}{"applicationId": "app-1", "agreesWithScore": true, "automatic": false, "submittedBy": "user-1"},
+		},
+		{
+			name:      "disagrees with a reason",
+			feedback:  ScoringFeedback{ApplicationID: "app-1", OverrideReason: "Strong portfolio", SubmittedBy: "user-1"},
+			wantInput: map[string]interface{}{"applicationId": "app-1", "agreesWithScore": false, "automatic": false, "overrideReason": "Strong portfolio", "submittedBy": "user-1"},
+		},
+		{
+			name:     "disagrees without a reason",
+			feedback: ScoringFeedback{ApplicationID: "app-1", SubmittedBy: "user-1"},
+			wantErr:  true,
+		},
+		{
+			name:      "automatic disagreement needs no reason",
+			feedback:  ScoringFeedback{ApplicationID: "app-1", Automatic: true},
+			wantInput: map[string]interface{}{"applicationId": "app-1", "agreesWithScore": false, "automatic": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, submitted := feedbackService(t, nil)
+			err := service.Submit(context.Background(), tt.feedback)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidScoringFeedback) {
+					t.Fatalf("Submit() error = %v, want ErrInvalidScoringFeedback", err)
+				}
+				if len(submitted()) != 0 {
+					t.Fatal("invalid feedback was sent to Hub-HRMS")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Submit() error = %v", err)
+			}
+			if got := submitted(); len(got) != 1 || !reflect.DeepEqual(got[0], tt.wantInput) {
+				t.Fatalf("submitted %v, want %v", got, tt.wantInput)
+			}
+		})
+	}
+}
+
+func TestScoringFeedbackService_Accuracy(t *testing.T) {
+	// More than a page of feedback: job-1 agreed with three times in four,
+	// the rest on job-2
+	var records []interface{}
+	for i := 0; i < scoringFeedbackPageSize; i++ {
+		records = append(records, feedbackRecord(i%4 != 0, "job-1", 4))
+	}
+	records = append(records, feedbackRecord(false, "job-2", -1), feedbackRecord(true, "job-2", 12))
+
+	service, _ := feedbackService(t, records)
+	accuracy, err := service.Accuracy(context.Background(), time.Now().AddDate(0, 0, -90), time.Now())
+	if err != nil {
+		t.Fatalf("Accuracy() error = %v", err)
+	}
+	if accuracy.Overall.Feedback != len(records) {
+		t.Fatalf("overall = %+v, want every page counted", accuracy.Overall)
+	}
+	if job := accuracy.ByJob[0]; job.JobID != "job-1" || job.JobTitle != "Title of job-1" || job.Agreed != 375 || job.AgreementRate != 0.75 {
+		t.Fatalf("job-1 = %+v, want 375 of 500 agreeing", job)
+	}
+}
+
+func TestSummarizeScoringAccuracy(t *testing.T) {
+	raw := []interface{}{
+		feedbackRecord(true, "job-1", 1),
+		feedbackRecord(false, "job-1", 4),
+		feedbackRecord(true, "job-2", 1),
+		feedbackRecord(true, "job-2", 12),
+		feedbackRecord(false, "job-3", -1),
+		feedbackRecord(true, "job-2", 7),
+		feedbackRecord(true, "", 2),
+	}
+	records := make([]ScoringFeedbackRecord, len(raw))
+	for i, record := range raw {
+		if _, err := decodeField(map[string]interface{}{"record": record}, "record", &records[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	accuracy := SummarizeScoringAccuracy(records)
+	if accuracy.Overall != (ScoringAgreement{Feedback: 7, Agreed: 5, AgreementRate: 5.0 / 7}) {
+		t.Fatalf("overall = %+v", accuracy.Overall)
+	}
+
+	// Most feedback first; feedback without a job only counts overall and
+	// by level
+	var jobs []string
+	for _, job := range accuracy.ByJob {
+		jobs = append(jobs, fmt.Sprintf("%s %d/%d", job.JobID, job.Agreed, job.Feedback))
+	}
+	if want := []string{"job-2 3/3", "job-1 1/2", "job-3 0/1"}; !reflect.DeepEqual(jobs, want) {
+		t.Fatalf("by job = %v, want %v", jobs, want)
+	}
+
+	// Levels from junior to lead, with unknown experience last
+	var levels []string
+	for _, level := range accuracy.ByLevel {
+		levels = append(levels, fmt.Sprintf("%s %d/%d", level.Level, level.Agreed, level.Feedback))
+	}
+	if want := []string{"junior 3/3", "mid 0/1", "senior 1/1", "lead 1/1", "unknown 0/1"}; !reflect.DeepEqual(levels, want) {
+		t.Fatalf("by level = %v, want %v", levels, want)
+	}
+
+	empty := SummarizeScoringAccuracy(nil)
+	if empty.Overall.Feedback != 0 || empty.ByJob == nil || empty.ByLevel == nil {
+		t.Fatalf("SummarizeScoringAccuracy(nil) = %+v, want zeroes and empty lists", empty)
+	}
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+var (
+	// ErrShareLinkNotFound is returned for unknown or tampered share tokens
+	ErrShareLinkNotFound = errors.New("share link not found")
+	// ErrShareLinkExpired is returned for share links past their expiry
+	ErrShareLinkExpired = errors.New("share link has expired")
+)
+
+// ShareLink grants read-only access to one application until ExpiresAt,
+// for reviewers without an account
+type ShareLink struct {
+	Token         string    `json:"token"`
+	ApplicationID string    `json:"applicationId"`
+	ExpiresAt     time.Time `json:"expiresAt"`
+	Note          string    `json:"note,omitempty"`
+	CreatedBy     string    `json:"createdBy,omitempty"`
+}
+
+// ShareLinkService issues and resolves application share links. Links are
+// saved in Hub-HRMS and cached in memory; each token is an HMAC of the
+// application ID and expiry, so a link edited in storage no longer resolves.
+type ShareLinkService struct {
+	client *gateway.HubHRMSClient
+	secret []byte
+
+	mu    sync.Mutex
+	links map[string]ShareLink
+}
+
+// NewShareLinkService creates a share link service. An empty secret falls
+// back to a random per-process secret, so links stop working on restart.
+func NewShareLinkService(client *gateway.HubHRMSClient, secret string) *ShareLinkService {
+	key := []byte(secret)
+	if secret == "" {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			panic("failed to generate share link secret: " + err.Error())
+		}
+	}
+	return &ShareLinkService{
+		client: client,
+		secret: key,
+		links:  make(map[string]ShareLink),
+	}
+}
+
+// Create issues a link to applicationID that expires after ttl
+func (s *ShareLinkService) Create(ctx context.Context, applicationID string, ttl time.Duration, note, createdBy string) (*ShareLink, error) {
+	link := ShareLink{
+		ApplicationID: applicationID,
+		ExpiresAt:     time.Now().Add(ttl).UTC().Truncate(time.Second),
+		Note:          note,
+		CreatedBy:     createdBy,
+	}
+	link.Token = s.sign(link.ApplicationID, link.ExpiresAt)
+
+	resp, err := s.client.Mutate(ctx, gateway.CreateShareLinkMutation, map[string]interface{}{
+		"input": map[string]interface{}{
+			"token":         link.Token,
+			"applicationId": link.ApplicationID,
+			"expiresAt":     link.ExpiresAt.Format(time.RFC3339),
+			"note":          link.Note,
+			"createdBy":     link.CreatedBy,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save share link: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("failed to save share link: %s", resp.Errors[0].Message)
+	}
+
+	s.mu.Lock()
+	s.pruneLocked()
+	s.links[link.Token] = link
+	s.mu.Unlock()
+	return &link, nil
+}
+
+// Resolve returns the link for token, checking its signature and expiry
+func (s *ShareLinkService) Resolve(ctx context.Context, token string) (*ShareLink, error) {
+	s.mu.Lock()
+	link, ok := s.links[token]
+	s.mu.Unlock()
+
+	if !ok {
+		resp, err := s.client.Query(ctx, gateway.GetShareLinkQuery, map[string]interface{}{"token": token})
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch share link: %w", err)
+		}
+		found, err := decodeField(resp.Data, "shareLink", &link)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			return nil, ErrShareLinkNotFound
+		}
+	}
+
+	if !hmac.Equal([]byte(token), []byte(s.sign(link.ApplicationID, link.ExpiresAt))) {
+		return nil, ErrShareLinkNotFound
+	}
+	if !time.Now().Before(link.ExpiresAt) {
+		return nil, ErrShareLinkExpired
+	}
+
+	s.mu.Lock()
+	s.links[token] = link
+	s.mu.Unlock()
+	return &link, nil
+}
+
+// sign derives the token for a link from what it grants
+func (s *ShareLinkService) sign(applicationID string, expiresAt time.Time) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(applicationID + "." + strconv.FormatInt(expiresAt.Unix(), 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// pruneLocked drops expired links from the cache
+func (s *ShareLinkService) pruneLocked() {
+	now := time.Now()
+	for token, link := range s.links {
+		if !now.Before(link.ExpiresAt) {
+			delete(s.links, token)
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// shareLinkServer fakes Hub-HRMS storing share links by token
+type shareLinkServer struct {
+	mu      sync.Mutex
+	links   map[string]map[string]interface{}
+	lookups int
+}
+
+func newShareLinkServer() *shareLinkServer {
+	return &shareLinkServer{links: make(map[string]map[string]interface{})}
+}
+
+func (s *shareLinkServer) respond(req gateway.GraphQLRequest) interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch req.Query {
+	case gateway.CreateShareLinkMutation:
+		input, _ := req.Variables["input"].(map[string]interface{})
+		s.links[input["token"].(string)] = input
+		return map[string]interface{}{"createShareLink": input}
+	case gateway.GetShareLinkQuery:
+		s.lookups++
+		token, _ := req.Variables["token"].(string)
+		if link, ok := s.links[token]; ok {
+			return map[string]interface{}{"shareLink": link}
+		}
+		return map[string]interface{}{"shareLink": nil}
+	}
+	return map[string]interface{}{}
+}
+
+// edit changes a stored link's field, as someone with storage access might
+func (s *shareLinkServer) edit(token, field string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.links[token][field] = value
+}
+
+func TestShareLinkService_Resolve(t *testing.T) {
+	ctx := context.Background()
+	server := newShareLinkServer()
+	client := newFakeHubHRMS(t, server.respond)
+	links := NewShareLinkService(client, "share-secret")
+
+	link, err := links.Create(ctx, "app-1", 7*24*time.Hour, "For the panel", "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(link.Token) != 64 || link.ApplicationID != "app-1" || link.ExpiresAt.Sub(time.Now()) < 7*24*time.Hour-time.Minute {
+		t.Fatalf("link = %+v, want a signed token expiring in 7 days", link)
+	}
+
+	t.Run("valid token", func(t *testing.T) {
+		resolved, err := links.Resolve(ctx, link.Token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resolved.ApplicationID != "app-1" || resolved.Note != "For the panel" {
+			t.Fatalf("resolved = %+v", resolved)
+		}
+		server.mu.Lock()
+		defer server.mu.Unlock()
+		if server.lookups != 0 {
+			t.Fatal("cached link was fetched from Hub-HRMS")
+		}
+	})
+
+	t.Run("valid token after a restart", func(t *testing.T) {
+		restarted := NewShareLinkService(client, "share-secret")
+		resolved, err := restarted.Resolve(ctx, link.Token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resolved.ApplicationID != "app-1" || !resolved.ExpiresAt.Equal(link.ExpiresAt) {
+			t.Fatalf("resolved = %+v, want the stored link", resolved)
+		}
+	})
+
+	t.Run("another secret", func(t *testing.T) {
+		other := NewShareLinkService(client, "other-secret")
+		if _, err := other.Resolve(ctx, link.Token); !errors.Is(err, ErrShareLinkNotFound) {
+			t.Fatalf("Resolve error = %v, want ErrShareLinkNotFound", err)
+		}
+	})
+
+	t.Run("tampered token", func(t *testing.T) {
+		tampered := []byte(link.Token)
+		tampered[0] ^= 1
+		if _, err := links.Resolve(ctx, string(tampered)); !errors.Is(err, ErrShareLinkNotFound) {
+			t.Fatalf("Resolve error = %v, want ErrShareLinkNotFound", err)
+		}
+		if _, err := links.Resolve(ctx, ""); !errors.Is(err, ErrShareLinkNotFound) {
+			t.Fatalf("Resolve of an empty token error = %v, want ErrShareLinkNotFound", err)
+		}
+	})
+
+	t.Run("stored link edited", func(t *testing.T) {
+		for _, edit := range []struct {
+			field string
+			value interface{}
+		}{
+			{field: "applicationId", value: "app-2"},
+			{field: "expiresAt", value: time.Now().Add(365 * 24 * time.Hour).UTC().Format(time.RFC3339)},
+		} {
+			edited, err := links.Create(ctx, "app-1", time.Hour, "", "user-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			server.edit(edited.Token, edit.field, edit.value)
+
+			restarted := NewShareLinkService(client, "share-secret")
+			if _, err := restarted.Resolve(ctx, edited.Token); !errors.Is(err, ErrShareLinkNotFound) {
+				t.Fatalf("Resolve with %s edited error = %v, want ErrShareLinkNotFound", edit.field, err)
+			}
+		}
+	})
+}
+
+func TestShareLinkService_Expiry(t *testing.T) {
+	ctx := context.Background()
+	server := newShareLinkServer()
+	client := newFakeHubHRMS(t, server.respond)
+	links := NewShareLinkService(client, "share-secret")
+
+	expired, err := links.Create(ctx, "app-1", -time.Minute, "", "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := links.Resolve(ctx, expired.Token); !errors.Is(err, ErrShareLinkExpired) {
+		t.Fatalf("Resolve error = %v, want ErrShareLinkExpired", err)
+	}
+	if _, err := NewShareLinkService(client, "share-secret").Resolve(ctx, expired.Token); !errors.Is(err, ErrShareLinkExpired) {
+		t.Fatalf("Resolve from storage error = %v, want ErrShareLinkExpired", err)
+	}
+
+	// Expired links are dropped from the cache when the next link is made
+	if _, err := links.Create(ctx, "app-2", time.Hour, "", "user-1"); err != nil {
+		t.Fatal(err)
+	}
+	links.mu.Lock()
+	_, cached := links.links[expired.Token]
+	links.mu.Unlock()
+	if cached {
+		t.Fatal("expired link still cached")
+	}
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	_ "embed"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+//go:embed data/skills.yaml
+var defaultSkillTaxonomy []byte
+
+// Skill is a canonical skill name and the other names candidates use for it
+type Skill struct {
+	Name     string   `json:"name" yaml:"name"`
+	Synonyms []string `json:"synonyms" yaml:"synonyms"`
+}
+
+// SkillNormalizer maps free-text skills onto canonical names, so "Golang"
+// and "go-lang" are both recorded as "Go"
+type SkillNormalizer struct {
+	taxonomy []Skill
+	aliases  map[string]string
+}
+
+// NewSkillNormalizer creates a normalizer for taxonomy
+func NewSkillNormalizer(taxonomy []Skill) (*SkillNormalizer, error) {
+	n := &SkillNormalizer{taxonomy: taxonomy, aliases: make(map[string]string)}
+	for _, skill := range taxonomy {
+		if strings.TrimSpace(skill.Name) == "" {
+			return nil, fmt.Errorf("skill taxonomy has an entry without a name")
+		}
+		for _, alias := range append([]string{skill.Name}, skill.Synonyms...) {
+			key := skillKey(alias)
+			if existing, ok := n.aliases[key]; ok && existing != skill.Name {
+				return nil, fmt.Errorf("skill alias %q maps to both %s and %s", alias, existing, skill.Name)
+			}
+			n.aliases[key] = skill.Name
+		}
+	}
+	return n, nil
+}
+
+// LoadSkillNormalizer reads a YAML taxonomy from path, or uses the built-in
+// taxonomy when path is empty. The taxonomy is a sequence of skills:
+//
+//   - name: Go
+//     synonyms: [Golang, go-lang]
+func LoadSkillNormalizer(path string) (*SkillNormalizer, error) {
+	data := defaultSkillTaxonomy
+	if path != "" {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, fmt.Errorf("failed to read skill taxonomy: %w", err)
+		}
+	}
+
+	var taxonomy []Skill
+	if err := yaml.Unmarshal(data, &taxonomy); err != nil {
+		return nil, fmt.Errorf("invalid skill taxonomy: %w", err)
+	}
+	return NewSkillNormalizer(taxonomy)
+}
+
+// Normalize returns the canonical name for skill. Unknown skills are
+// returned trimmed but otherwise unchanged.
+func (n *SkillNormalizer) Normalize(skill string) string {
+	skill = strings.Join(strings.Fields(skill), " ")
+	if canonical, ok := n.aliases[skillKey(skill)]; ok {
+		return canonical
+	}
+	return skill
+}
+
+// NormalizeAll normalizes skills, dropping blanks and any duplicates the
+// normalization produces
+func (n *SkillNormalizer) NormalizeAll(skills []string) []string {
+	normalized := make([]string, 0, len(skills))
+	seen := make(map[string]bool, len(skills))
+	for _, skill := range skills {
+		skill = n.Normalize(skill)
+		if skill == "" || seen[strings.ToLower(skill)] {
+			continue
+		}
+		seen[strings.ToLower(skill)] = true
+		normalized = append(normalized, skill)
+	}
+	return normalized
+}
+
+// Taxonomy returns every canonical skill with its synonyms
+func (n *SkillNormalizer) Taxonomy() []Skill {
+	return n.taxonomy
+}
+
+// skillKey folds the spellings of a skill name together: case, spacing and
+// punctuation are ignored, except + and # which distinguish C, C++ and C#
+func skillKey(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '+' || r == '#' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSkillNormalizer_Normalize(t *testing.T) {
+	normalizer, err := LoadSkillNormalizer("")
+	if err != nil {
+		t.Fatalf("LoadSkillNormalizer() error = %v", err)
+	}
+
+	tests := []struct {
+		skill string
+		want  string
+	}{
+		{skill: "Golang", want: "Go"},
+		{skill: "golang", want: "Go"},
+		{skill: "go-lang", want: "Go"},
+		{skill: "  GO Programming   Language ", want: "Go"},
+		{skill: "js", want: "JavaScript"},
+		{skill: "Python 3", want: "Python"},
+		{skill: "CSharp", want: "C#"},
+		{skill: "c#", want: "C#"},
+		{skill: "cpp", want: "C++"},
+		{skill: "RoR", want: "Ruby on Rails"},
+		{skill: "Kotlin", want: "Kotlin"},
+		// Unknown skills pass through with their spacing tidied
+		{skill: "COBOL", want: "COBOL"},
+		{skill: "  Underwater   basket weaving ", want: "Underwater basket weaving"},
+		{skill: "", want: ""},
+	}
+	for _, tt := range tests {
+		if got := normalizer.Normalize(tt.skill); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.skill, got, tt.want)
+		}
+	}
+}
+
+func TestSkillNormalizer_NormalizeAll(t *testing.T) {
+	normalizer, err := LoadSkillNormalizer("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got := normalizer.NormalizeAll([]string{"Golang", "go", " ", "COBOL", "cobol", "TS", "TypeScript"})
+	want := []string{"Go", "COBOL", "TypeScript"}
+	if !slices.Equal(got, want) {
+		t.Fatalf("NormalizeAll() = %v, want %v", got, want)
+	}
+}
+
+func TestLoadSkillNormalizer(t *testing.T) {
+	write := func(t *testing.T, taxonomy string) string {
+		t.Helper()
+		path := filepath.Join(t.TempDir(), "skills.yaml")
+		if err := os.WriteFile(path, []byte(taxonomy), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	t.Run("custom taxonomy", func(t *testing.T) {
+		normalizer, err := LoadSkillNormalizer(write(t, `
+- name: Go
+  synonyms: [Golang]
+- name: "C#"
+  synonyms:
+    - C Sharp
+- name: Elixir
+  synonyms: []
+`))
+		if err != nil {
+			t.Fatalf("LoadSkillNormalizer() error = %v", err)
+		}
+		if got := normalizer.Normalize("c sharp"); got != "C#" {
+			t.Fatalf("Normalize(c sharp) = %q, want C#", got)
+		}
+		// Skills only in the built-in taxonomy are unknown here
+		if got := normalizer.Normalize("JS"); got != "JS" {
+			t.Fatalf("Normalize(JS) = %q, want it passed through", got)
+		}
+		if taxonomy := normalizer.Taxonomy(); len(taxonomy) != 3 || taxonomy[2].Name != "Elixir" {
+			t.Fatalf("Taxonomy() = %+v", taxonomy)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		taxonomy string
+		wantErr  string
+	}{
+		{name: "not YAML", taxonomy: "- name: [Go", wantErr: "invalid skill taxonomy"},
+		{name: "not a sequence", taxonomy: "name: Go", wantErr: "invalid skill taxonomy"},
+		{name: "missing name", taxonomy: "- synonyms: [Golang]", wantErr: "without a name"},
+		{name: "alias of two skills", taxonomy: "- name: Go\n  synonyms: [GL]\n- name: GraphQL\n  synonyms: [gl]", wantErr: "maps to both"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := LoadSkillNormalizer(write(t, tt.taxonomy)); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("LoadSkillNormalizer() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	if _, err := LoadSkillNormalizer(filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Fatal("LoadSkillNormalizer() accepted a missing file")
+	}
+}
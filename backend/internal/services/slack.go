@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SlackNotifier posts application events to a Slack incoming webhook
+type SlackNotifier struct {
+	webhookURL string
+	channel    string
+	statuses   chatStatuses
+	client     *http.Client
+}
+
+// NewSlackNotifier creates a notifier posting to webhookURL. channel, when
+// set, overrides the webhook's default channel. Status changes are posted
+// only for the statuses listed in notifyOnStatuses. With no webhook URL
+// nothing is sent.
+func NewSlackNotifier(webhookURL, channel string, notifyOnStatuses []string) *SlackNotifier {
+	return &SlackNotifier{
+		webhookURL: webhookURL,
+		channel:    channel,
+		statuses:   newChatStatuses(notifyOnStatuses),
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// slackMessage is a Slack webhook payload built from Block Kit blocks. Text
+// is the fallback shown in notifications.
+type slackMessage struct {
+	Channel string       `json:"channel,omitempty"`
+	Text    string       `json:"text"`
+	Blocks  []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string        `json:"type"`
+	Text     *slackText    `json:"text,omitempty"`
+	Fields   []slackText   `json:"fields,omitempty"`
+	Elements []slackButton `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackButton struct {
+	Type string    `json:"type"`
+	Text slackText `json:"text"`
+	URL  string    `json:"url"`
+}
+
+// Notify posts notification unless it is a status change the notifier is
+// not configured for. The message is sent in the background and a failure
+// is only logged.
+func (n *SlackNotifier) Notify(notification ApplicationNotification) {
+	if n.webhookURL == "" || !n.statuses.wants(notification) {
+		return
+	}
+
+	message := n.message(notification)
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := postChatMessage(ctx, n.client, n.webhookURL, message); err != nil {
+			slog.Warn("failed to post slack notification",
+				"application_id", notification.ApplicationID, "event", notification.Event, "error", err)
+		}
+	}()
+}
+
+// message lays out a notification as a header, a section of fields and a
+// button linking to the application
+func (n *SlackNotifier) message(notification ApplicationNotification) slackMessage {
+	headline, summary, facts := chatSummary(notification)
+
+	fields := make([]slackText, len(facts))
+	for i, fact := range facts {
+		fields[i] = slackText{Type: "mrkdwn", Text: "*" + fact.Title + "*\n" + slackEscape(fact.Value)}
+	}
+	blocks := []slackBlock{
+		{Type: "header", Text: &slackText{Type: "plain_text", Text: headline}},
+		{Type: "section", Fields: fields},
+	}
+	if notification.URL != "" {
+		blocks = append(blocks, slackBlock{
+			Type: "actions",
+			Elements: []slackButton{{
+				Type: "button",
+				Text: slackText{Type: "plain_text", Text: "View application"},
+				URL:  notification.URL,
+			}},
+		})
+	}
+
+	return slackMessage{Channel: n.channel, Text: slackEscape(summary), Blocks: blocks}
+}
+
+// slackEscape escapes the characters Slack treats as markup in mrkdwn text
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newChatWebhook fakes a chat incoming webhook that answers every post with
+// status and passes each body on to the returned channel
+func newChatWebhook(t *testing.T, status int) (string, <-chan []byte) {
+	t.Helper()
+	messages := make(chan []byte, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.Header.Get("Content-Type") != "application/json" {
+			t.Errorf("webhook got %s with Content-Type %q", r.Method, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		messages <- body
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	return server.URL, messages
+}
+
+// nextChatMessage waits for the webhook to receive a message
+func nextChatMessage(t *testing.T, messages <-chan []byte) []byte {
+	t.Helper()
+	select {
+	case message := <-messages:
+		return message
+	case <-time.After(5 * time.Second):
+		t.Fatal("no message posted to the webhook")
+		return nil
+	}
+}
+
+func TestSlackNotifier_StatusChange(t *testing.T) {
+	webhookURL, messages := newChatWebhook(t, http.StatusOK)
+	notifier := NewSlackNotifier(webhookURL, "#hiring", []string{"offer", " HIRED "})
+
+	notifier.Notify(ApplicationNotification{
+		Event:         ChatStatusChanged,
+		ApplicationID: "app-1",
+		CandidateName: "Ada <Lovelace>",
+		JobTitle:      "Backend Engineer",
+		Status:        "OFFER",
+		URL:           "https://careers.example.com/applications/app-1",
+	})
+
+	var message slackMessage
+	if err := json.Unmarshal(nextChatMessage(t, messages), &message); err != nil {
+		t.Fatalf("message is not JSON: %v", err)
+	}
+	want := slackMessage{
+		Channel: "#hiring",
+		Text:    "Ada &lt;Lovelace&gt; moved to OFFER for Backend Engineer",
+		Blocks: []slackBlock{
+			{Type: "header", Text: &slackText{Type: "plain_text", Text: "Application moved to OFFER"}},
+			{Type: "section", Fields: []slackText{
+				{Type: "mrkdwn", Text: "*Candidate*\nAda &lt;Lovelace&gt;"},
+				{Type: "mrkdwn", Text: "*Job*\nBackend Engineer"},
+				{Type: "mrkdwn", Text: "*Status*\nOFFER"},
+			}},
+			{Type: "actions", Elements: []slackButton{{
+				Type: "button",
+				Text: slackText{Type: "plain_text", Text: "View application"},
+				URL:  "https://careers.example.com/applications/app-1",
+			}}},
+		},
+	}
+	if !reflect.DeepEqual(message, want) {
+		t.Fatalf("message = %+v, want %+v", message, want)
+	}
+}
+
+func TestSlackNotifier_Filtering(t *testing.T) {
+	webhookURL, messages := newChatWebhook(t, http.StatusOK)
+	notifier := NewSlackNotifier(webhookURL, "", []string{"OFFER"})
+
+	// Unlisted status changes are dropped before anything is sent, so the
+	// only message to arrive is the new application
+	notifier.Notify(ApplicationNotification{Event: ChatStatusChanged, ApplicationID: "app-1", Status: "SCREENING"})
+	notifier.Notify(ApplicationNotification{Event: ChatNewApplication, ApplicationID: "app-2", CandidateName: "Grace Hopper", JobTitle: "Engineer"})
+
+	var message slackMessage
+	if err := json.Unmarshal(nextChatMessage(t, messages), &message); err != nil {
+		t.Fatal(err)
+	}
+	if message.Text != "Grace Hopper applied for Engineer" || message.Channel != "" {
+		t.Fatalf("message = %+v, want the new application on the webhook's channel", message)
+	}
+	// Without a URL there is no button
+	if len(message.Blocks) != 2 {
+		t.Fatalf("got %d blocks, want a header and fields only", len(message.Blocks))
+	}
+	select {
+	case extra := <-messages:
+		t.Fatalf("unexpected message %s", extra)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	t.Run("no webhook", func(t *testing.T) {
+		// Must neither panic nor post anywhere
+		NewSlackNotifier("", "", []string{"OFFER"}).Notify(ApplicationNotification{Event: ChatStatusChanged, Status: "OFFER"})
+	})
+}
+
+func TestPostChatMessage(t *testing.T) {
+	client := &http.Client{Timeout: time.Second}
+
+	okURL, messages := newChatWebhook(t, http.StatusOK)
+	if err := postChatMessage(context.Background(), client, okURL, map[string]string{"text": "hello"}); err != nil {
+		t.Fatalf("postChatMessage() error = %v", err)
+	}
+	if body := string(nextChatMessage(t, messages)); body != `{"text":"hello"}` {
+		t.Fatalf("body = %s", body)
+	}
+
+	failingURL, _ := newChatWebhook(t, http.StatusInternalServerError)
+	err := postChatMessage(context.Background(), client, failingURL, map[string]string{"text": "hello"})
+	if err == nil || !strings.Contains(err.Error(), "status 500") {
+		t.Fatalf("postChatMessage() error = %v, want the webhook's status", err)
+	}
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"strings"
+	"unicode"
+)
+
+// maxSlugLength keeps slugs readable in URLs and search results
+const maxSlugLength = 80
+
+// slugFolds spells accented Latin letters and ligatures in plain ASCII, and
+// keeps the + of titles like C++ from being lost
+var slugFolds = map[rune]string{
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'į': "i", 'ı': "i",
+	'ł': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o", 'œ': "oe",
+	'ř': "r", 'ś': "s", 'š': "s", 'ş': "s", 'ß': "ss", 'ť': "t", 'ţ': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u", 'ų': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+	'+': "plus",
+}
+
+// GenerateSlug derives a URL-safe slug from a job title: lowercase ASCII
+// letters and digits separated by single hyphens, e.g. "Senior C++ Engineer
+// (Remote)" becomes "senior-cplusplus-engineer-remote". Accented Latin letters
+// are folded to ASCII and other characters act as separators. Titles with
+// nothing usable yield "job".
+func GenerateSlug(title string) string {
+	var b strings.Builder
+	hyphen := false
+	var previous rune
+	for _, r := range strings.ToLower(title) {
+		var part string
+		switch {
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			part = string(r)
+		case slugFolds[r] != "":
+			part = slugFolds[r]
+		case r == '#' && unicode.IsLetter(previous):
+			// C# and F#, but not "#5"
+			part = "sharp"
+		default:
+			hyphen = b.Len() > 0
+			previous = r
+			continue
+		}
+		previous = r
+		if hyphen {
+			b.WriteByte('-')
+			hyphen = false
+		}
+		b.WriteString(part)
+	}
+
+	slug := b.String()
+	if len(slug) > maxSlugLength {
+		// Cut at a word boundary where possible
+		cut := maxSlugLength
+		if slug[cut] != '-' {
+			if boundary := strings.LastIndexByte(slug[:cut], '-'); boundary > 0 {
+				cut = boundary
+			}
+		}
+		slug = slug[:cut]
+	}
+	if slug == "" {
+		return "job"
+	}
+	return slug
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGenerateSlug(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		{name: "plain title", title: "Senior Go Engineer", want: "senior-go-engineer"},
+		{name: "accents", title: "Développeur Full-Stack", want: "developpeur-full-stack"},
+		{name: "uppercase accents", title: "ÉCOLE Señor", want: "ecole-senor"},
+		{name: "multi-letter folds", title: "Straße Œuvre Æsthetics", want: "strasse-oeuvre-aesthetics"},
+		{name: "unfoldable script dropped", title: "数据 Engineer", want: "engineer"},
+		{name: "only unfoldable script", title: "Ελληνικά", want: "job"},
+		{name: "plus", title: "Senior C++ Engineer (Remote)", want: "senior-cplusplus-engineer-remote"},
+		{name: "sharp after a letter", title: "C# / F# Developer", want: "csharp-fsharp-developer"},
+		{name: "hash before a number", title: "Issue #5 Triage", want: "issue-5-triage"},
+		{name: "punctuation collapses", title: "Head of ***Sales!!, EMEA", want: "head-of-sales-emea"},
+		{name: "dots and slashes", title: "Node.js/Go Developer", want: "node-js-go-developer"},
+		{name: "leading and trailing separators", title: "  --Recruiter--  ", want: "recruiter"},
+		{name: "digits kept", title: "Level 3 Support 24/7", want: "level-3-support-24-7"},
+		{name: "empty", title: "", want: "job"},
+		{name: "only punctuation", title: "!!! ???", want: "job"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GenerateSlug(tt.title); got != tt.want {
+				t.Fatalf("GenerateSlug(%q) = %q, want %q", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGenerateSlug_Length(t *testing.T) {
+	tests := []struct {
+		name  string
+		title string
+		want  string
+	}{
+		// 16 four-letter words fill 79 characters; the 17th would pass 80
+		{name: "cut at a word boundary", title: strings.Repeat("word ", 30), want: strings.TrimSuffix(strings.Repeat("word-", 16), "-")},
+		// The hyphen after 80 characters is dropped along with what follows
+		{name: "boundary at the limit", title: strings.Repeat("a", 80) + " tail", want: strings.Repeat("a", 80)},
+		{name: "no boundary", title: strings.Repeat("x", 100), want: strings.Repeat("x", 80)},
+		{name: "exactly the limit", title: strings.Repeat("y", 80), want: strings.Repeat("y", 80)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := GenerateSlug(tt.title)
+			if got != tt.want {
+				t.Fatalf("GenerateSlug = %q (%d characters), want %q", got, len(got), tt.want)
+			}
+			if len(got) > maxSlugLength || strings.HasSuffix(got, "-") {
+				t.Fatalf("GenerateSlug = %q, want at most %d characters and no trailing hyphen", got, maxSlugLength)
+			}
+		})
+	}
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// SNSMessage is an Amazon SNS HTTP(S) delivery
+type SNSMessage struct {
+	Type             string `json:"Type"`
+	MessageID        string `json:"MessageId"`
+	Token            string `json:"Token,omitempty"`
+	TopicArn         string `json:"TopicArn"`
+	Subject          string `json:"Subject,omitempty"`
+	Message          string `json:"Message"`
+	Timestamp        string `json:"Timestamp"`
+	SignatureVersion string `json:"SignatureVersion"`
+	Signature        string `json:"Signature"`
+	SigningCertURL   string `json:"SigningCertURL"`
+	SubscribeURL     string `json:"SubscribeURL,omitempty"`
+}
+
+// snsCertHost matches the hosts AWS serves SNS signing certificates from
+var snsCertHost = regexp.MustCompile(`^sns\.[a-z0-9-]+\.amazonaws\.com(\.cn)?$`)
+
+// SNSVerifier verifies the signature on SNS deliveries
+type SNSVerifier struct {
+	topicArn string
+	client   *http.Client
+
+	mu    sync.Mutex
+	certs map[string]*x509.Certificate
+}
+
+// NewSNSVerifier creates a verifier; if topicArn is set, messages from any
+// other topic are rejected
+func NewSNSVerifier(topicArn string) *SNSVerifier {
+	return &SNSVerifier{
+		topicArn: topicArn,
+		client:   &http.Client{Timeout: 10 * time.Second},
+		certs:    make(map[string]*x509.Certificate),
+	}
+}
+
+// Verify checks the message came from SNS and from the expected topic
+func (v *SNSVerifier) Verify(msg *SNSMessage) error {
+	if v.topicArn != "" && msg.TopicArn != v.topicArn {
+		return fmt.Errorf("unexpected topic %q", msg.TopicArn)
+	}
+
+	cert, err := v.certificate(msg.SigningCertURL)
+	if err != nil {
+		return err
+	}
+
+	signature, err := base64.StdEncoding.DecodeString(msg.Signature)
+	if err != nil {
+		return fmt.Errorf("invalid signature encoding: %w", err)
+	}
+
+	pub, ok := cert.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return fmt.Errorf("unsupported signing key type")
+	}
+
+	payload := []byte(stringToSign(msg))
+	switch msg.SignatureVersion {
+	case "1":
+		digest := sha1.Sum(payload)
+		err = rsa.VerifyPKCS1v15(pub, crypto.SHA1, digest[:], signature)
+	case "2":
+		digest := sha256.Sum256(payload)
+		err = rsa.VerifyPKCS1v15(pub, crypto.SHA256, digest[:], signature)
+	default:
+		return fmt.Errorf("unsupported signature version %q", msg.SignatureVersion)
+	}
+	if err != nil {
+		return fmt.Errorf("signature mismatch: %w", err)
+	}
+
+	return nil
+}
+
+// ConfirmSubscription visits the SubscribeURL of a verified confirmation message
+func (v *SNSVerifier) ConfirmSubscription(msg *SNSMessage) error {
+	if !isSNSURL(msg.SubscribeURL) {
+		return fmt.Errorf("untrusted subscribe URL")
+	}
+
+	resp, err := v.client.Get(msg.SubscribeURL)
+	if err != nil {
+		return fmt.Errorf("failed to confirm subscription: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("SNS returned status %d confirming subscription", resp.StatusCode)
+	}
+	return nil
+}
+
+// certificate fetches and caches the signing certificate
+func (v *SNSVerifier) certificate(certURL string) (*x509.Certificate, error) {
+	if !isSNSURL(certURL) {
+		return nil, fmt.Errorf("untrusted signing certificate URL")
+	}
+
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if cert, ok := v.certs[certURL]; ok {
+		return cert, nil
+	}
+
+	resp, err := v.client.Get(certURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch signing certificate: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing certificate: %w", err)
+	}
+
+	block, _ := pem.Decode(body)
+	if block == nil {
+		return nil, fmt.Errorf("signing certificate is not PEM encoded")
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse signing certificate: %w", err)
+	}
+
+	v.certs[certURL] = cert
+	return cert, nil
+}
+
+// isSNSURL reports whether raw is an HTTPS URL on an SNS host
+func isSNSURL(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return false
+	}
+	return u.Scheme == "https" && snsCertHost.MatchString(u.Hostname())
+}
+
+// stringToSign builds the canonical string SNS signs for each message type
+func stringToSign(msg *SNSMessage) string {
+	fields := [][2]string{{"Message", msg.Message}, {"MessageId", msg.MessageID}}
+
+	if msg.Type == "Notification" {
+		if msg.Subject != "" {
+			fields = append(fields, [2]string{"Subject", msg.Subject})
+		}
+	} else {
+		fields = append(fields, [2]string{"SubscribeURL", msg.SubscribeURL})
+	}
+
+	fields = append(fields, [2]string{"Timestamp", msg.Timestamp})
+	if msg.Type != "Notification" {
+		fields = append(fields, [2]string{"Token", msg.Token})
+	}
+	fields = append(fields, [2]string{"TopicArn", msg.TopicArn}, [2]string{"Type", msg.Type})
+
+	var b strings.Builder
+	for _, field := range fields {
+		b.WriteString(field[0] + "\n" + field[1] + "\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testTopicArn = "arn:aws:sns:eu-west-1:123456789012:resume-uploads"
+	testCertURL  = "https://sns.eu-west-1.amazonaws.com/SimpleNotificationService-test.pem"
+)
+
+// newTestSNSVerifier returns a verifier that trusts a generated certificate
+// as the one served at testCertURL, and the key to sign messages with
+func newTestSNSVerifier(t *testing.T, topicArn string) (*SNSVerifier, *rsa.PrivateKey) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sns.amazonaws.com"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifier := NewSNSVerifier(topicArn)
+	verifier.certs[testCertURL] = cert
+	return verifier, key
+}
+
+// signSNSMessage signs msg with key as SNS does for its signature version
+func signSNSMessage(t *testing.T, key *rsa.PrivateKey, msg *SNSMessage) {
+	t.Helper()
+	payload := []byte(stringToSign(msg))
+	var signature []byte
+	var err error
+	if msg.SignatureVersion == "1" {
+		digest := sha1.Sum(payload)
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA1, digest[:])
+	} else {
+		digest := sha256.Sum256(payload)
+		signature, err = rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg.Signature = base64.StdEncoding.EncodeToString(signature)
+}
+
+func testSNSNotification(version string) *SNSMessage {
+	return &SNSMessage{
+		Type:             "Notification",
+		MessageID:        "msg-1",
+		TopicArn:         testTopicArn,
+		Subject:          "Amazon S3 Notification",
+		Message:          `{"Records":[{"s3":{"object":{"key":"resumes/cand-1/cv.pdf"}}}]}`,
+		Timestamp:        "2026-10-16T12:00:00.000Z",
+		SignatureVersion: version,
+		SigningCertURL:   testCertURL,
+	}
+}
+
+func TestSNSVerifier_Verify(t *testing.T) {
+	verifier, key := newTestSNSVerifier(t, testTopicArn)
+
+	for _, version := range []string{"1", "2"} {
+		msg := testSNSNotification(version)
+		signSNSMessage(t, key, msg)
+		if err := verifier.Verify(msg); err != nil {
+			t.Fatalf("Verify() of a version %s signature error = %v", version, err)
+		}
+	}
+
+	confirmation := &SNSMessage{
+		Type:             "SubscriptionConfirmation",
+		MessageID:        "msg-2",
+		Token:            "token-1",
+		TopicArn:         testTopicArn,
+		Message:          "You have chosen to subscribe",
+		SubscribeURL:     "https://sns.eu-west-1.amazonaws.com/?Action=ConfirmSubscription",
+		Timestamp:        "2026-10-16T12:00:00.000Z",
+		SignatureVersion: "2",
+		SigningCertURL:   testCertURL,
+	}
+	signSNSMessage(t, key, confirmation)
+	if err := verifier.Verify(confirmation); err != nil {
+		t.Fatalf("Verify() of a subscription confirmation error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		change  func(msg *SNSMessage)
+		wantErr string
+	}{
+		{name: "tampered message", change: func(msg *SNSMessage) { msg.Message = strings.Replace(msg.Message, "cand-1", "cand-2", 1) }, wantErr: "signature mismatch"},
+		{name: "other topic", change: func(msg *SNSMessage) { msg.TopicArn = "arn:aws:sns:eu-west-1:123456789012:other" }, wantErr: "unexpected topic"},
+		{name: "untrusted certificate host", change: func(msg *SNSMessage) { msg.SigningCertURL = "https://attacker.example.com/cert.pem" }, wantErr: "untrusted"},
+		{name: "certificate over HTTP", change: func(msg *SNSMessage) { msg.SigningCertURL = "http://sns.eu-west-1.amazonaws.com/cert.pem" }, wantErr: "untrusted"},
+		{name: "signature not base64", change: func(msg *SNSMessage) { msg.Signature = "%%%" }, wantErr: "invalid signature encoding"},
+		{name: "unknown version", change: func(msg *SNSMessage) { msg.SignatureVersion = "3" }, wantErr: "unsupported signature version"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := testSNSNotification("2")
+			signSNSMessage(t, key, msg)
+			tt.change(msg)
+			if err := verifier.Verify(msg); err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("Verify() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+
+	// Without a topic, messages from any topic are accepted
+	anyTopic, key := newTestSNSVerifier(t, "")
+	msg := testSNSNotification("2")
+	msg.TopicArn = "arn:aws:sns:us-east-1:123456789012:other"
+	signSNSMessage(t, key, msg)
+	if err := anyTopic.Verify(msg); err != nil {
+		t.Fatalf("Verify() without a topic error = %v", err)
+	}
+}
+
+func TestSNSVerifier_ConfirmSubscription_UntrustedURL(t *testing.T) {
+	verifier := NewSNSVerifier(testTopicArn)
+	for _, subscribeURL := range []string{"https://example.com/confirm", "http://sns.eu-west-1.amazonaws.com/confirm", ""} {
+		if err := verifier.ConfirmSubscription(&SNSMessage{SubscribeURL: subscribeURL}); err == nil {
+			t.Errorf("ConfirmSubscription(%q) was visited", subscribeURL)
+		}
+	}
+}
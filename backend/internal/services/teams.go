@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Adaptive Card schema details for Teams incoming webhooks
+const (
+	adaptiveCardSchema      = "http://adaptivecards.io/schemas/adaptive-card.json"
+	adaptiveCardVersion     = "1.4"
+	adaptiveCardContentType = "application/vnd.microsoft.card.adaptive"
+)
+
+// TeamsNotifier posts application events to a Microsoft Teams incoming
+// webhook as Adaptive Cards
+type TeamsNotifier struct {
+	webhookURL string
+	statuses   chatStatuses
+	client     *http.Client
+}
+
+// NewTeamsNotifier creates a notifier posting to webhookURL. Status changes
+// are posted only for the statuses listed in notifyOnStatuses. With no
+// webhook URL nothing is sent.
+func NewTeamsNotifier(webhookURL string, notifyOnStatuses []string) *TeamsNotifier {
+	return &TeamsNotifier{
+		webhookURL: webhookURL,
+		statuses:   newChatStatuses(notifyOnStatuses),
+		client:     &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// TeamsMessage is the webhook payload wrapping an Adaptive Card
+type TeamsMessage struct {
+	Type        string            `json:"type"`
+	Attachments []TeamsAttachment `json:"attachments"`
+}
+
+// TeamsAttachment carries one card in a TeamsMessage
+type TeamsAttachment struct {
+	ContentType string    `json:"contentType"`
+	Content     TeamsCard `json:"content"`
+}
+
+// TeamsCard is an Adaptive Card
+type TeamsCard struct {
+	Schema  string             `json:"$schema"`
+	Type    string             `json:"type"`
+	Version string             `json:"version"`
+	Body    []TeamsCardElement `json:"body"`
+	Actions []TeamsCardAction  `json:"actions,omitempty"`
+}
+
+// TeamsCardElement is a TextBlock or FactSet in a card body
+type TeamsCardElement struct {
+	Type   string      `json:"type"`
+	Text   string      `json:"text,omitempty"`
+	Size   string      `json:"size,omitempty"`
+	Weight string      `json:"weight,omitempty"`
+	Wrap   bool        `json:"wrap,omitempty"`
+	Facts  []TeamsFact `json:"facts,omitempty"`
+}
+
+// TeamsFact is one title and value row of a FactSet
+type TeamsFact struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+}
+
+// TeamsCardAction is a button on a card
+type TeamsCardAction struct {
+	Type  string `json:"type"`
+	Title string `json:"title"`
+	URL   string `json:"url"`
+}
+
+// Notify posts notification unless it is a status change the notifier is
+// not configured for. The message is sent in the background and a failure
+// is only logged.
+func (n *TeamsNotifier) Notify(notification ApplicationNotification) {
+	if n.webhookURL == "" || !n.statuses.wants(notification) {
+		return
+	}
+
+	message := TeamsMessage{
+		Type: "message",
+		Attachments: []TeamsAttachment{{
+			ContentType: adaptiveCardContentType,
+			Content:     NewTeamsCard(notification),
+		}},
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+
+		if err := postChatMessage(ctx, n.client, n.webhookURL, message); err != nil {
+			slog.Warn("failed to post teams notification",
+				"application_id", notification.ApplicationID, "event", notification.Event, "error", err)
+		}
+	}()
+}
+
+// NewTeamsCard lays out a notification as a headline, a fact set and a
+// button linking to the application
+func NewTeamsCard(notification ApplicationNotification) TeamsCard {
+	headline, _, facts := chatSummary(notification)
+
+	factSet := TeamsCardElement{Type: "FactSet", Facts: make([]TeamsFact, len(facts))}
+	for i, fact := range facts {
+		factSet.Facts[i] = TeamsFact{Title: fact.Title, Value: fact.Value}
+	}
+	card := TeamsCard{
+		Schema:  adaptiveCardSchema,
+		Type:    "AdaptiveCard",
+		Version: adaptiveCardVersion,
+		Body: []TeamsCardElement{
+			{Type: "TextBlock", Text: headline, Size: "Medium", Weight: "Bolder", Wrap: true},
+			factSet,
+		},
+	}
+	if notification.URL != "" {
+		card.Actions = []TeamsCardAction{{Type: "Action.OpenUrl", Title: "View application", URL: notification.URL}}
+	}
+	return card
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewTeamsCard(t *testing.T) {
+	interviewAt := time.Date(2026, 10, 20, 14, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		notification ApplicationNotification
+		wantHeadline string
+		wantFacts    []TeamsFact
+	}{
+		{
+			name:         "new application",
+			notification: ApplicationNotification{Event: ChatNewApplication, CandidateName: "Ada Lovelace", JobTitle: "Backend Engineer"},
+			wantHeadline: "New application",
+			wantFacts:    []TeamsFact{{Title: "Candidate", Value: "Ada Lovelace"}, {Title: "Job", Value: "Backend Engineer"}},
+		},
+		{
+			name:         "status change",
+			notification: ApplicationNotification{Event: ChatStatusChanged, CandidateName: "Ada Lovelace", JobTitle: "Backend Engineer", Status: "OFFER"},
+			wantHeadline: "Application moved to OFFER",
+			wantFacts: []TeamsFact{
+				{Title: "Candidate", Value: "Ada Lovelace"}, {Title: "Job", Value: "Backend Engineer"}, {Title: "Status", Value: "OFFER"},
+			},
+		},
+		{
+			name:         "interview scheduled",
+			notification: ApplicationNotification{Event: ChatInterviewScheduled, CandidateName: "Ada Lovelace", JobTitle: "Backend Engineer", InterviewAt: interviewAt},
+			wantHeadline: "Interview scheduled",
+			wantFacts: []TeamsFact{
+				{Title: "Candidate", Value: "Ada Lovelace"}, {Title: "Job", Value: "Backend Engineer"},
+				{Title: "Interview", Value: interviewAt.Format(InterviewDateFormat)},
+			},
+		},
+		{
+			name:         "assigned",
+			notification: ApplicationNotification{Event: ChatAssigned, CandidateName: "Ada Lovelace", JobTitle: "Backend Engineer", Recruiter: "Grace"},
+			wantHeadline: "Application assigned",
+			wantFacts: []TeamsFact{
+				{Title: "Candidate", Value: "Ada Lovelace"}, {Title: "Job", Value: "Backend Engineer"}, {Title: "Recruiter", Value: "Grace"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.notification.URL = "https://careers.example.com/applications/app-1"
+			want := TeamsCard{
+				Schema:  "http://adaptivecards.io/schemas/adaptive-card.json",
+				Type:    "AdaptiveCard",
+				Version: "1.4",
+				Body: []TeamsCardElement{
+					{Type: "TextBlock", Text: tt.wantHeadline, Size: "Medium", Weight: "Bolder", Wrap: true},
+					{Type: "FactSet", Facts: tt.wantFacts},
+				},
+				Actions: []TeamsCardAction{
+					{Type: "Action.OpenUrl", Title: "View application", URL: "https://careers.example.com/applications/app-1"},
+				},
+			}
+			if card := NewTeamsCard(tt.notification); !reflect.DeepEqual(card, want) {
+				t.Fatalf("card = %+v, want %+v", card, want)
+			}
+		})
+	}
+
+	t.Run("no link", func(t *testing.T) {
+		card := NewTeamsCard(ApplicationNotification{Event: ChatNewApplication})
+		payload, _ := json.Marshal(card)
+		var fields map[string]interface{}
+		json.Unmarshal(payload, &fields)
+		if _, ok := fields["actions"]; ok {
+			t.Fatalf("card %s has actions without a URL", payload)
+		}
+	})
+}
+
+func TestTeamsNotifier_Notify(t *testing.T) {
+	webhookURL, messages := newChatWebhook(t, http.StatusOK)
+	notifier := NewTeamsNotifier(webhookURL, []string{"OFFER"})
+
+	// Unlisted status changes are dropped before anything is sent
+	notifier.Notify(ApplicationNotification{Event: ChatStatusChanged, Status: "SCREENING"})
+	notifier.Notify(ApplicationNotification{Event: ChatStatusChanged, CandidateName: "Ada Lovelace", JobTitle: "Backend Engineer", Status: "offer"})
+
+	// The payload as Teams expects it, checked field by field in JSON
+	var message struct {
+		Type        string `json:"type"`
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Schema  string `json:"$schema"`
+				Type    string `json:"type"`
+				Version string `json:"version"`
+				Body    []struct {
+					Type  string      `json:"type"`
+					Text  string      `json:"text"`
+					Facts []TeamsFact `json:"facts"`
+				} `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(nextChatMessage(t, messages), &message); err != nil {
+		t.Fatalf("message is not JSON: %v", err)
+	}
+	if message.Type != "message" || len(message.Attachments) != 1 {
+		t.Fatalf("message = %+v, want one attachment", message)
+	}
+	attachment := message.Attachments[0]
+	card := attachment.Content
+	if attachment.ContentType != "application/vnd.microsoft.card.adaptive" ||
+		card.Schema != "http://adaptivecards.io/schemas/adaptive-card.json" || card.Type != "AdaptiveCard" || card.Version != "1.4" {
+		t.Fatalf("attachment = %+v, want an Adaptive Card", attachment)
+	}
+	if len(card.Body) != 2 || card.Body[0].Text != "Application moved to offer" || len(card.Body[1].Facts) != 3 {
+		t.Fatalf("body = %+v, want the headline and facts", card.Body)
+	}
+
+	select {
+	case extra := <-messages:
+		t.Fatalf("unexpected message %s", extra)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+// recordingNotifier records the notifications it is given
+type recordingNotifier struct {
+	mu            sync.Mutex
+	notifications []ApplicationNotification
+}
+
+func (n *recordingNotifier) Notify(notification ApplicationNotification) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.notifications = append(n.notifications, notification)
+}
+
+func TestNotificationBroadcaster(t *testing.T) {
+	first, second := &recordingNotifier{}, &recordingNotifier{}
+	broadcaster := NewNotificationBroadcaster(first, second)
+
+	notification := ApplicationNotification{Event: ChatNewApplication, ApplicationID: "app-1"}
+	broadcaster.Notify(notification)
+
+	for i, notifier := range []*recordingNotifier{first, second} {
+		if !reflect.DeepEqual(notifier.notifications, []ApplicationNotification{notification}) {
+			t.Fatalf("notifier %d got %v, want the notification", i, notifier.notifications)
+		}
+	}
+
+	// Slack and Teams both satisfy Notifier, and with no notifiers nothing
+	// happens
+	NewNotificationBroadcaster(NewSlackNotifier("", "", nil), NewTeamsNotifier("", nil)).Notify(notification)
+	NewNotificationBroadcaster().Notify(notification)
+}
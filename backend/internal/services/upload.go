@@ -1,10 +1,17 @@
 package services
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
 	"net/http"
+	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -12,17 +19,29 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/gabriel-vasile/mimetype"
 	"github.com/google/uuid"
+
+	"hr-recruiting/internal/gateway"
 )
 
+// resumeCacheTTL is how long a downloaded resume is served from disk
+const resumeCacheTTL = time.Hour
+
 // UploadService handles file uploads to S3
 type UploadService struct {
-	client *s3.Client
-	bucket string
+	client  *s3.Client
+	bucket  string
+	cache   *ResumeCache
+	hubhrms *gateway.HubHRMSClient
+	// maxFiles caps the resumes one candidate may upload; 0 means no limit
+	maxFiles int
 }
 
-// NewUploadService creates a new upload service
-func NewUploadService(bucket, region string) *UploadService {
+// NewUploadService creates a new upload service. Upload counts are read
+// from Hub-HRMS to hold each candidate to maxFilesPerCandidate resumes.
+func NewUploadService(bucket, region string, hubhrms *gateway.HubHRMSClient, maxFilesPerCandidate int) *UploadService {
 	cfg, err := config.LoadDefaultConfig(context.TODO(),
 		config.WithRegion(region),
 	)
@@ -31,80 +50,246 @@ func NewUploadService(bucket, region string) *UploadService {
 	}
 
 	return &UploadService{
-		client: s3.NewFromConfig(cfg),
-		bucket: bucket,
+		client:   s3.NewFromConfig(cfg),
+		bucket:   bucket,
+		cache:    NewResumeCache(filepath.Join(os.TempDir(), "hr-recruiting-resumes"), resumeCacheTTL),
+		hubhrms:  hubhrms,
+		maxFiles: maxFilesPerCandidate,
+	}
+}
+
+// ErrUploadQuotaExceeded is returned when a candidate has already uploaded
+// as many files as they are allowed
+var ErrUploadQuotaExceeded = errors.New("upload quota exceeded")
+
+// CheckQuota returns how many files candidateEmail has uploaded. It returns
+// ErrUploadQuotaExceeded, with the count, once that reaches maxFiles. A
+// maxFiles of 0 or less means no limit.
+func (s *UploadService) CheckQuota(ctx context.Context, candidateEmail string, maxFiles int) (int, error) {
+	resp, err := s.hubhrms.Query(ctx, gateway.CountCandidateUploadsQuery, map[string]interface{}{
+		"email": NormalizeEmail(candidateEmail),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to count uploads: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return 0, fmt.Errorf("failed to count uploads: %s", resp.Errors[0].Message)
+	}
+
+	data, _ := resp.Data.(map[string]interface{})
+	count, _ := data["candidateUploadCount"].(float64)
+	if maxFiles > 0 && int(count) >= maxFiles {
+		return int(count), fmt.Errorf("%w: %d of %d files uploaded", ErrUploadQuotaExceeded, int(count), maxFiles)
+	}
+	return int(count), nil
+}
+
+// checkQuota enforces the configured quota for an upload request, writing
+// the error response when the upload must not go ahead. Uploads that do not
+// name a candidate are not counted.
+func (s *UploadService) checkQuota(ctx context.Context, w http.ResponseWriter, candidateEmail string) bool {
+	if strings.TrimSpace(candidateEmail) == "" {
+		return true
+	}
+
+	_, err := s.CheckQuota(ctx, candidateEmail, s.maxFiles)
+	if errors.Is(err, ErrUploadQuotaExceeded) {
+		http.Error(w, fmt.Sprintf("Upload limit reached. At most %d files can be uploaded per candidate", s.maxFiles), http.StatusTooManyRequests)
+		return false
+	}
+	if err != nil {
+		slog.Error("failed to check upload quota", "error", err)
+		http.Error(w, "Failed to check upload quota", http.StatusInternalServerError)
+		return false
 	}
+	return true
+}
+
+// UploadedFile is a file a candidate has uploaded
+type UploadedFile struct {
+	Key         string    `json:"key"`
+	Filename    string    `json:"filename"`
+	ContentType string    `json:"contentType"`
+	Size        int64     `json:"size"`
+	UploadedAt  time.Time `json:"uploadedAt"`
+	URL         string    `json:"url"`
+}
+
+// ListFiles returns the files uploaded under candidateEmail
+func (s *UploadService) ListFiles(ctx context.Context, candidateEmail string) ([]UploadedFile, error) {
+	resp, err := s.hubhrms.Query(ctx, gateway.GetCandidateUploadsQuery, map[string]interface{}{
+		"email": NormalizeEmail(candidateEmail),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list uploads: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return nil, fmt.Errorf("failed to list uploads: %s", resp.Errors[0].Message)
+	}
+
+	var uploads []UploadedFile
+	if _, err := decodeField(resp.Data, "candidateUploads", &uploads); err != nil {
+		return nil, err
+	}
+
+	files := make([]UploadedFile, 0, len(uploads))
+	for _, file := range uploads {
+		file.URL = s.GetFileURL(file.Key)
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+const (
+	// maxResumeSize is the largest resume accepted
+	maxResumeSize = 10 << 20
+	// multipartThreshold is the size above which resumes are uploaded in
+	// parts. It doubles as the part size, the smallest S3 allows.
+	multipartThreshold = 5 << 20
+	// maxEmailFieldSize bounds the candidate email read from an upload form
+	maxEmailFieldSize = 320
+)
+
+// errFileTooLarge is returned while streaming a file past maxResumeSize
+var errFileTooLarge = errors.New("file exceeds maximum size")
+
+// sizeCounter counts bytes written to it, failing once limit is passed. Used
+// with io.TeeReader it measures a stream as it is read.
+type sizeCounter struct {
+	n     int64
+	limit int64
 }
 
-// UploadResume handles direct resume file uploads
+func (c *sizeCounter) Write(p []byte) (int, error) {
+	c.n += int64(len(p))
+	if c.n > c.limit {
+		return 0, errFileTooLarge
+	}
+	return len(p), nil
+}
+
+// UploadResume handles direct resume file uploads. The file is streamed from
+// the request to S3 rather than parsed into memory first.
 func (s *UploadService) UploadResume(w http.ResponseWriter, r *http.Request) {
-	// Parse multipart form (max 10MB)
-	if err := r.ParseMultipartForm(10 << 20); err != nil {
+	ctx := r.Context()
+
+	// Leave room for the multipart framing around the file
+	r.Body = http.MaxBytesReader(w, r.Body, maxResumeSize+(1<<20))
+
+	reader, err := r.MultipartReader()
+	if err != nil {
 		http.Error(w, "Failed to parse form", http.StatusBadRequest)
 		return
 	}
 
-	// Get file from form
-	file, header, err := r.FormFile("file")
-	if err != nil {
-		http.Error(w, "Failed to get file from form", http.StatusBadRequest)
+	// Get file from form. The file is streamed, so a candidate email field
+	// only counts toward the upload quota when it comes before the file.
+	var part *multipart.Part
+	var candidateEmail string
+	for {
+		part, err = reader.NextPart()
+		if err != nil {
+			http.Error(w, "Failed to get file from form", http.StatusBadRequest)
+			return
+		}
+		if part.FormName() == "file" {
+			break
+		}
+		if part.FormName() == "email" {
+			value, _ := io.ReadAll(io.LimitReader(part, maxEmailFieldSize))
+			candidateEmail = string(value)
+		}
+		part.Close()
+	}
+	defer part.Close()
+	originalFilename := part.FileName()
+
+	if !s.checkQuota(ctx, w, candidateEmail) {
 		return
 	}
-	defer file.Close()
 
 	// Validate file type
-	ext := strings.ToLower(filepath.Ext(header.Filename))
+	ext := strings.ToLower(filepath.Ext(originalFilename))
 	allowedExts := map[string]string{
-		".pdf":  "application/pdf",
-		".doc":  "application/msword",
-		".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document",
+		".pdf":  pdfContentType,
+		".doc":  docContentType,
+		".docx": docxContentType,
 	}
-	
+
 	contentType, allowed := allowedExts[ext]
 	if !allowed {
 		http.Error(w, "Invalid file type. Only PDF, DOC, and DOCX are allowed", http.StatusBadRequest)
 		return
 	}
 
-	// Validate file size (max 10MB)
-	if header.Size > 10<<20 {
-		http.Error(w, "File too large. Maximum size is 10MB", http.StatusBadRequest)
-		return
-	}
-
 	// Generate unique filename
-	filename := fmt.Sprintf("resumes/%s/%s%s", 
-		time.Now().Format("2006/01"), 
-		uuid.New().String(), 
+	filename := fmt.Sprintf("resumes/%s/%s%s",
+		time.Now().Format("2006/01"),
+		uuid.New().String(),
 		ext,
 	)
+	metadata := map[string]string{
+		"original-filename": originalFilename,
+		"uploaded-at":       time.Now().Format(time.RFC3339),
+	}
+	if candidateEmail != "" {
+		metadata["candidate-email"] = NormalizeEmail(candidateEmail)
+	}
 
-	// Upload to S3
-	_, err = s.client.PutObject(context.TODO(), &s3.PutObjectInput{
-		Bucket:      aws.String(s.bucket),
-		Key:         aws.String(filename),
-		Body:        file,
-		ContentType: aws.String(contentType),
-		Metadata: map[string]string{
-			"original-filename": header.Filename,
-			"uploaded-at":       time.Now().Format(time.RFC3339),
-		},
-	})
+	// Validate file size (max 10MB) as the body streams through
+	size := &sizeCounter{limit: maxResumeSize}
+	body := io.TeeReader(part, size)
+
+	// Read up to the threshold to choose between a single PUT and a
+	// multipart upload; only larger files are read past it
+	head := make([]byte, multipartThreshold+1)
+	n, err := io.ReadFull(body, head)
+	large := err == nil
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+
+	// The extension is only a claim; check it against the file's content.
+	// The head is already buffered, so the stream needs no rewinding.
+	if err == nil {
+		detected, detectErr := detectContentType(bytes.NewReader(head[:n]))
+		if detectErr != nil || detected != contentType {
+			http.Error(w, "File content does not match an allowed type. Only PDF, DOC, and DOCX are allowed", http.StatusUnsupportedMediaType)
+			return
+		}
+
+		if large {
+			err = s.uploadMultipart(ctx, io.MultiReader(bytes.NewReader(head[:n]), body), filename, contentType, metadata)
+		} else {
+			_, err = s.client.PutObject(ctx, &s3.PutObjectInput{
+				Bucket:      aws.String(s.bucket),
+				Key:         aws.String(filename),
+				Body:        bytes.NewReader(head[:n]),
+				ContentType: aws.String(contentType),
+				Metadata:    metadata,
+			})
+		}
+	}
+	var maxBytesErr *http.MaxBytesError
+	if errors.Is(err, errFileTooLarge) || errors.As(err, &maxBytesErr) {
+		http.Error(w, "File too large. Maximum size is 10MB", http.StatusBadRequest)
+		return
+	}
 	if err != nil {
 		http.Error(w, fmt.Sprintf("Failed to upload file: %v", err), http.StatusInternalServerError)
 		return
 	}
 
 	// Generate public URL
-	url := fmt.Sprintf("https://%s.s3.amazonaws.com/%s", s.bucket, filename)
+	url := s.GetFileURL(filename)
 
 	// Return response
 	response := map[string]interface{}{
 		"success":          true,
 		"url":              url,
 		"filename":         filename,
-		"originalFilename": header.Filename,
-		"size":             header.Size,
+		"originalFilename": originalFilename,
+		"size":             size.n,
 		"contentType":      contentType,
 	}
 
@@ -113,11 +298,137 @@ func (s *UploadService) UploadResume(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// errUnsupportedContentType is returned for files that are not a PDF or
+// Word document, whatever their name says
+var errUnsupportedContentType = errors.New("unsupported content type")
+
+// Resume MIME types, as named by mimetype
+const (
+	pdfContentType  = "application/pdf"
+	docContentType  = "application/msword"
+	docxContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	oleContentType  = "application/x-ole-storage"
+)
+
+// sniffLength is how much of a file mimetype reads to detect its type
+const sniffLength = 3072
+
+// detectContentType sniffs the head of r with mimetype and returns the resume
+// MIME type it contains. A legacy .doc is only named as such when its OLE2
+// directory falls inside the sniffed bytes, so any OLE2 compound document
+// counts as one; a ZIP only counts as DOCX when it has a word/ part.
+func detectContentType(r io.Reader) (string, error) {
+	detected, err := mimetype.DetectReader(r)
+	if err != nil {
+		return "", err
+	}
+
+	for m := detected; m != nil; m = m.Parent() {
+		switch {
+		case m.Is(pdfContentType):
+			return pdfContentType, nil
+		case m.Is(docxContentType):
+			return docxContentType, nil
+		case m.Is(docContentType), m.Is(oleContentType):
+			return docContentType, nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s", errUnsupportedContentType, detected)
+}
+
+// UploadResumeMultipart streams reader to S3 under filename as a multipart
+// upload and returns the file's URL. Memory use is bounded by the part size
+// regardless of the file's length.
+func (s *UploadService) UploadResumeMultipart(ctx context.Context, reader io.Reader, filename, contentType string) (string, error) {
+	if err := s.uploadMultipart(ctx, reader, filename, contentType, nil); err != nil {
+		return "", err
+	}
+	return s.GetFileURL(filename), nil
+}
+
+// uploadMultipart uploads reader in multipartThreshold-sized parts, aborting
+// the upload if any part fails so S3 does not keep the orphaned parts
+func (s *UploadService) uploadMultipart(ctx context.Context, reader io.Reader, key, contentType string, metadata map[string]string) error {
+	created, err := s.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		ContentType: aws.String(contentType),
+		Metadata:    metadata,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to start multipart upload: %w", err)
+	}
+
+	parts, err := s.uploadParts(ctx, reader, key, created.UploadId)
+	if err == nil {
+		_, err = s.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+			Bucket:          aws.String(s.bucket),
+			Key:             aws.String(key),
+			UploadId:        created.UploadId,
+			MultipartUpload: &types.CompletedMultipartUpload{Parts: parts},
+		})
+	}
+	if err != nil {
+		// The request may already be cancelled, so abort on a fresh context
+		abortCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer cancel()
+		if _, abortErr := s.client.AbortMultipartUpload(abortCtx, &s3.AbortMultipartUploadInput{
+			Bucket:   aws.String(s.bucket),
+			Key:      aws.String(key),
+			UploadId: created.UploadId,
+		}); abortErr != nil {
+			slog.Warn("failed to abort multipart upload", "key", key, "error", abortErr)
+		}
+		return err
+	}
+
+	return nil
+}
+
+// uploadParts reads reader one part at a time and uploads each
+func (s *UploadService) uploadParts(ctx context.Context, reader io.Reader, key string, uploadID *string) ([]types.CompletedPart, error) {
+	var parts []types.CompletedPart
+	buf := make([]byte, multipartThreshold)
+
+	for partNumber := int32(1); ; partNumber++ {
+		n, err := io.ReadFull(reader, buf)
+		if err == io.EOF {
+			break
+		}
+		if err != nil && err != io.ErrUnexpectedEOF {
+			return nil, err
+		}
+
+		out, uploadErr := s.client.UploadPart(ctx, &s3.UploadPartInput{
+			Bucket:     aws.String(s.bucket),
+			Key:        aws.String(key),
+			UploadId:   uploadID,
+			PartNumber: aws.Int32(partNumber),
+			Body:       bytes.NewReader(buf[:n]),
+		})
+		if uploadErr != nil {
+			return nil, fmt.Errorf("failed to upload part %d: %w", partNumber, uploadErr)
+		}
+		parts = append(parts, types.CompletedPart{
+			ETag:       out.ETag,
+			PartNumber: aws.Int32(partNumber),
+		})
+
+		if err == io.ErrUnexpectedEOF {
+			break
+		}
+	}
+
+	return parts, nil
+}
+
 // GetPresignedURL generates a presigned URL for direct upload
 func (s *UploadService) GetPresignedURL(w http.ResponseWriter, r *http.Request) {
 	var input struct {
-		Filename    string `json:"filename"`
-		ContentType string `json:"contentType"`
+		Filename       string `json:"filename"`
+		ContentType    string `json:"contentType"`
+		CandidateID    string `json:"candidateId,omitempty"`
+		CandidateEmail string `json:"candidateEmail,omitempty"`
 	}
 	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
 		http.Error(w, "Invalid request body", http.StatusBadRequest)
@@ -127,7 +438,7 @@ func (s *UploadService) GetPresignedURL(w http.ResponseWriter, r *http.Request)
 
 	// Validate content type
 	allowedTypes := map[string]bool{
-		"application/pdf": true,
+		"application/pdf":    true,
 		"application/msword": true,
 		"application/vnd.openxmlformats-officedocument.wordprocessingml.document": true,
 	}
@@ -136,23 +447,36 @@ func (s *UploadService) GetPresignedURL(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	if !s.checkQuota(r.Context(), w, input.CandidateEmail) {
+		return
+	}
+
 	// Generate unique key
 	ext := filepath.Ext(input.Filename)
-	key := fmt.Sprintf("resumes/%s/%s%s", 
-		time.Now().Format("2006/01"), 
-		uuid.New().String(), 
+	key := fmt.Sprintf("resumes/%s/%s%s",
+		time.Now().Format("2006/01"),
+		uuid.New().String(),
 		ext,
 	)
 
+	// The upload-complete webhook reads candidate-id back to link the resume
+	metadata := map[string]string{
+		"original-filename": input.Filename,
+	}
+	if input.CandidateID != "" {
+		metadata["candidate-id"] = input.CandidateID
+	}
+	if input.CandidateEmail != "" {
+		metadata["candidate-email"] = NormalizeEmail(input.CandidateEmail)
+	}
+
 	// Create presigned request
 	presignClient := s3.NewPresignClient(s.client)
 	presignedReq, err := presignClient.PresignPutObject(context.TODO(), &s3.PutObjectInput{
 		Bucket:      aws.String(s.bucket),
 		Key:         aws.String(key),
 		ContentType: aws.String(input.ContentType),
-		Metadata: map[string]string{
-			"original-filename": input.Filename,
-		},
+		Metadata:    metadata,
 	}, s3.WithPresignExpires(15*time.Minute))
 
 	if err != nil {
@@ -177,6 +501,43 @@ func (s *UploadService) GetPresignedURL(w http.ResponseWriter, r *http.Request)
 	json.NewEncoder(w).Encode(response)
 }
 
+// ErrInvalidResumeKey is returned for keys outside the resumes prefix
+var ErrInvalidResumeKey = errors.New("invalid resume key")
+
+// GetPresignedDownloadURL generates a presigned URL for reading a resume,
+// valid for ttl. Only keys under resumes/ are signed.
+func (s *UploadService) GetPresignedDownloadURL(ctx context.Context, key string, ttl time.Duration) (string, error) {
+	if !strings.HasPrefix(key, "resumes/") || path.Clean(key) != key {
+		return "", ErrInvalidResumeKey
+	}
+
+	presignClient := s3.NewPresignClient(s.client)
+	presignedReq, err := presignClient.PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket:                     aws.String(s.bucket),
+		Key:                        aws.String(key),
+		ResponseContentDisposition: aws.String("inline"),
+	}, s3.WithPresignExpires(ttl))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate presigned URL: %w", err)
+	}
+
+	return presignedReq.URL, nil
+}
+
+// PutFile stores content in S3 under key and returns the file's URL
+func (s *UploadService) PutFile(ctx context.Context, key, contentType string, content []byte) (string, error) {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(content),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", err
+	}
+	return s.GetFileURL(key), nil
+}
+
 // DeleteFile deletes a file from S3
 func (s *UploadService) DeleteFile(ctx context.Context, key string) error {
 	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
@@ -186,7 +547,48 @@ func (s *UploadService) DeleteFile(ctx context.Context, key string) error {
 	return err
 }
 
+// GetObject opens a file in S3, returning its body and content type. The
+// caller must close the body.
+func (s *UploadService) GetObject(ctx context.Context, key string) (io.ReadCloser, string, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return out.Body, aws.ToString(out.ContentType), nil
+}
+
+// OpenResume returns a local copy of the resume at key and its content type,
+// downloading it from S3 if it is not cached. The caller must close the file.
+func (s *UploadService) OpenResume(ctx context.Context, key string) (*os.File, string, error) {
+	return s.cache.Open(ctx, key, s.GetObject)
+}
+
+// GetFileMetadata returns the user metadata stored on an uploaded file
+func (s *UploadService) GetFileMetadata(ctx context.Context, key string) (map[string]string, error) {
+	out, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return out.Metadata, nil
+}
+
 // GetFileURL returns the public URL for a file
 func (s *UploadService) GetFileURL(key string) string {
 	return fmt.Sprintf("https://%s.s3.amazonaws.com/%s", s.bucket, key)
+}
+
+// KeyFromURL returns the object key for a URL produced by GetFileURL. It
+// reports false for URLs that point outside the bucket.
+func (s *UploadService) KeyFromURL(fileURL string) (string, bool) {
+	key, ok := strings.CutPrefix(fileURL, s.GetFileURL(""))
+	if !ok || key == "" {
+		return "", false
+	}
+	return key, true
 }
\ No newline at end of file
@@ -0,0 +1,433 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// newQuotaService returns an upload service whose candidates have each
+// uploaded count files, recording the emails counts were asked for
+func newQuotaService(t *testing.T, count, maxFiles int) (*UploadService, *[]string) {
+	var asked []string
+	client := newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.CountCandidateUploadsQuery {
+			t.Errorf("unexpected query %s", req.Query)
+		}
+		email, _ := req.Variables["email"].(string)
+		asked = append(asked, email)
+		return map[string]interface{}{"candidateUploadCount": count}
+	})
+	return &UploadService{hubhrms: client, maxFiles: maxFiles}, &asked
+}
+
+func TestUploadService_CheckQuota(t *testing.T) {
+	const maxFiles = 10
+	tests := []struct {
+		name    string
+		count   int
+		wantErr error
+	}{
+		{name: "no uploads", count: 0},
+		{name: "under the limit", count: maxFiles - 1},
+		{name: "at the limit", count: maxFiles, wantErr: ErrUploadQuotaExceeded},
+		{name: "over the limit", count: maxFiles + 2, wantErr: ErrUploadQuotaExceeded},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, asked := newQuotaService(t, tt.count, maxFiles)
+			count, err := s.CheckQuota(context.Background(), " Ada@Example.com ", maxFiles)
+
+			if count != tt.count {
+				t.Fatalf("CheckQuota() count = %d, want %d", count, tt.count)
+			}
+			if tt.wantErr == nil && err != nil {
+				t.Fatalf("CheckQuota() error = %v, want nil", err)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CheckQuota() error = %v, want %v", err, tt.wantErr)
+			}
+			if len(*asked) != 1 || (*asked)[0] != "ada@example.com" {
+				t.Fatalf("counted uploads for %q, want the normalized email", *asked)
+			}
+		})
+	}
+}
+
+func TestUploadService_CheckQuota_NoLimit(t *testing.T) {
+	s, _ := newQuotaService(t, 500, 0)
+	if count, err := s.CheckQuota(context.Background(), "ada@example.com", 0); err != nil || count != 500 {
+		t.Fatalf("CheckQuota() = %d, %v, want 500 and no error", count, err)
+	}
+}
+
+func TestUploadService_checkQuota(t *testing.T) {
+	tests := []struct {
+		name       string
+		count      int
+		email      string
+		wantOK     bool
+		wantStatus int
+	}{
+		{name: "under the limit", count: 2, email: "ada@example.com", wantOK: true, wantStatus: http.StatusOK},
+		{name: "at the limit", count: 3, email: "ada@example.com", wantOK: false, wantStatus: http.StatusTooManyRequests},
+		{name: "no candidate named", count: 3, email: " ", wantOK: true, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, _ := newQuotaService(t, tt.count, 3)
+			rec := httptest.NewRecorder()
+			if ok := s.checkQuota(context.Background(), rec, tt.email); ok != tt.wantOK {
+				t.Fatalf("checkQuota() = %v, want %v", ok, tt.wantOK)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+		})
+	}
+}
+
+// newFakeS3Service returns an upload service backed by a fake S3 that
+// records the key of every object put to it
+func newFakeS3Service(t *testing.T) (*UploadService, func() []string) {
+	t.Helper()
+	var mu sync.Mutex
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			t.Errorf("unexpected S3 request %s %s", r.Method, r.URL)
+		}
+		mu.Lock()
+		keys = append(keys, strings.TrimPrefix(r.URL.Path, "/uploads/"))
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+	})
+	return &UploadService{client: client, bucket: "uploads"}, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), keys...)
+	}
+}
+
+// zipFile builds a ZIP archive holding the named files
+func zipFile(t *testing.T, names ...string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, name := range names {
+		f, err := archive.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		f.Write([]byte("<xml/>"))
+	}
+	if err := archive.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// uploadResume posts content to UploadResume as a file named filename
+func uploadResume(t *testing.T, s *UploadService, filename string, content []byte) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	part, err := form.CreateFormFile("file", filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	part.Write(content)
+	form.Close()
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/upload/resume", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := httptest.NewRecorder()
+	s.UploadResume(rec, req)
+	return rec
+}
+
+func TestUploadResume_RejectsDisguisedExecutable(t *testing.T) {
+	// An x86-64 ELF executable header, e_type 2 (ET_EXEC)
+	elf := append([]byte("\x7fELF\x02\x01\x01\x00"), make([]byte, 56)...)
+	elf[16] = 2
+	// A DOS/PE executable stub
+	pe := append([]byte("MZ\x90\x00\x03\x00\x00\x00"), make([]byte, 120)...)
+
+	tests := []struct {
+		name     string
+		filename string
+		content  []byte
+	}{
+		{name: "ELF renamed to .pdf", filename: "resume.pdf", content: elf},
+		{name: "PE renamed to .docx", filename: "resume.docx", content: pe},
+		{name: "PE renamed to .doc", filename: "Resume.DOC", content: pe},
+		{name: "ZIP without a Word document", filename: "resume.docx", content: zipFile(t, "[Content_Types].xml", "payload.exe")},
+		{name: "PDF renamed to .docx", filename: "resume.docx", content: []byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, puts := newFakeS3Service(t)
+			rec := uploadResume(t, s, tt.filename, tt.content)
+			if rec.Code != http.StatusUnsupportedMediaType {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusUnsupportedMediaType, rec.Body)
+			}
+			if keys := puts(); len(keys) != 0 {
+				t.Fatalf("rejected file was stored as %v", keys)
+			}
+		})
+	}
+}
+
+func TestUploadResume_AcceptsResumeFormats(t *testing.T) {
+	pdf := []byte("%PDF-1.4\n1 0 obj << /Type /Catalog >> endobj\ntrailer << /Root 1 0 R >>\n%%EOF\n")
+	ole := append([]byte{0xD0, 0xCF, 0x11, 0xE0, 0xA1, 0xB1, 0x1A, 0xE1}, make([]byte, 504)...)
+
+	tests := []struct {
+		filename string
+		content  []byte
+		wantType string
+	}{
+		{filename: "resume.pdf", content: pdf, wantType: pdfContentType},
+		{filename: "resume.docx", content: zipFile(t, "[Content_Types].xml", "_rels/.rels", "word/document.xml"), wantType: docxContentType},
+		{filename: "resume.doc", content: ole, wantType: docContentType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			s, puts := newFakeS3Service(t)
+			rec := uploadResume(t, s, tt.filename, tt.content)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d, want %d: %s", rec.Code, http.StatusOK, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), `"contentType":"`+tt.wantType+`"`) {
+				t.Fatalf("response = %s, want content type %s", rec.Body, tt.wantType)
+			}
+			if keys := puts(); len(keys) != 1 || !strings.HasPrefix(keys[0], "resumes/") {
+				t.Fatalf("stored keys = %v, want one resume", keys)
+			}
+		})
+	}
+}
+
+// s3Call is one request made to the fake multipart S3
+type s3Call struct {
+	op   string
+	key  string
+	size int
+}
+
+// newMultipartS3Service returns an upload service backed by a fake S3 that
+// supports multipart uploads, records each call and fails the parts listed
+// in failParts
+func newMultipartS3Service(t *testing.T, failParts ...string) (*UploadService, func() []s3Call) {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []s3Call
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		query := r.URL.Query()
+		call := s3Call{key: strings.TrimPrefix(r.URL.Path, "/uploads/"), size: len(body)}
+		switch {
+		case r.Method == http.MethodPost && query.Has("uploads"):
+			call.op = "CreateMultipartUpload"
+			fmt.Fprintf(w, `<InitiateMultipartUploadResult><Bucket>uploads</Bucket><Key>%s</Key><UploadId>upload-1</UploadId></InitiateMultipartUploadResult>`, call.key)
+		case r.Method == http.MethodPut && query.Has("partNumber"):
+			call.op = "UploadPart " + query.Get("partNumber")
+			if slices.Contains(failParts, query.Get("partNumber")) {
+				w.WriteHeader(http.StatusBadRequest)
+				fmt.Fprint(w, `<Error><Code>InvalidArgument</Code><Message>part failed</Message></Error>`)
+				break
+			}
+			w.Header().Set("ETag", `"etag-`+query.Get("partNumber")+`"`)
+		case r.Method == http.MethodPost && query.Get("uploadId") == "upload-1":
+			call.op = "CompleteMultipartUpload"
+			fmt.Fprintf(w, `<CompleteMultipartUploadResult><Bucket>uploads</Bucket><Key>%s</Key><ETag>"etag"</ETag></CompleteMultipartUploadResult>`, call.key)
+		case r.Method == http.MethodDelete && query.Get("uploadId") == "upload-1":
+			call.op = "AbortMultipartUpload"
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPut:
+			call.op = "PutObject"
+		default:
+			t.Errorf("unexpected S3 request %s %s", r.Method, r.URL)
+		}
+		mu.Lock()
+		calls = append(calls, call)
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+
+	client := s3.New(s3.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL),
+		UsePathStyle: true,
+		Credentials:  credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+	})
+	return &UploadService{client: client, bucket: "uploads"}, func() []s3Call {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]s3Call(nil), calls...)
+	}
+}
+
+// s3Ops lists the operations of calls in order
+func s3Ops(calls []s3Call) []string {
+	ops := make([]string, len(calls))
+	for i, call := range calls {
+		ops[i] = call.op
+	}
+	return ops
+}
+
+// paddedPDF returns a PDF header padded out to size bytes
+func paddedPDF(size int) []byte {
+	pdf := []byte("%PDF-1.4\n")
+	return append(pdf, bytes.Repeat([]byte{' '}, size-len(pdf))...)
+}
+
+func TestUploadResume_ChoosesUploadPath(t *testing.T) {
+	tests := []struct {
+		name    string
+		size    int
+		wantOps []string
+		// wantParts is the body size of each call, -1 where it is not checked
+		wantParts []int
+	}{
+		{name: "small file", size: 1 << 20, wantOps: []string{"PutObject"}, wantParts: []int{1 << 20}},
+		{name: "at the threshold", size: multipartThreshold, wantOps: []string{"PutObject"}, wantParts: []int{multipartThreshold}},
+		{
+			name:      "large file",
+			size:      multipartThreshold + 1<<20,
+			wantOps:   []string{"CreateMultipartUpload", "UploadPart 1", "UploadPart 2", "CompleteMultipartUpload"},
+			wantParts: []int{-1, multipartThreshold, 1 << 20, -1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, calls := newMultipartS3Service(t)
+			rec := uploadResume(t, s, "resume.pdf", paddedPDF(tt.size))
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status = %d: %s", rec.Code, rec.Body)
+			}
+			if !strings.Contains(rec.Body.String(), fmt.Sprintf(`"size":%d`, tt.size)) {
+				t.Fatalf("response = %s, want size %d", rec.Body, tt.size)
+			}
+
+			got := calls()
+			if !slices.Equal(s3Ops(got), tt.wantOps) {
+				t.Fatalf("S3 calls = %v, want %v", s3Ops(got), tt.wantOps)
+			}
+			for i, call := range got {
+				if tt.wantParts[i] >= 0 && call.size != tt.wantParts[i] {
+					t.Errorf("%s sent %d bytes, want %d", call.op, call.size, tt.wantParts[i])
+				}
+				if !strings.HasPrefix(call.key, "resumes/") || call.key != got[0].key {
+					t.Errorf("%s used key %q", call.op, call.key)
+				}
+			}
+		})
+	}
+}
+
+func TestUploadResume_TooLargeAbortsMultipart(t *testing.T) {
+	s, calls := newMultipartS3Service(t)
+	rec := uploadResume(t, s, "resume.pdf", paddedPDF(maxResumeSize+1))
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), "File too large") {
+		t.Fatalf("status = %d, body = %s; want 400", rec.Code, rec.Body)
+	}
+	ops := s3Ops(calls())
+	if len(ops) == 0 || ops[len(ops)-1] != "AbortMultipartUpload" || slices.Contains(ops, "CompleteMultipartUpload") {
+		t.Fatalf("S3 calls = %v, want the upload aborted", ops)
+	}
+}
+
+func TestUploadService_UploadResumeMultipart(t *testing.T) {
+	s, calls := newMultipartS3Service(t)
+	url, err := s.UploadResumeMultipart(context.Background(), bytes.NewReader(paddedPDF(2*multipartThreshold+10)), "resumes/cv.pdf", pdfContentType)
+	if err != nil {
+		t.Fatalf("UploadResumeMultipart() error = %v", err)
+	}
+	if url != s.GetFileURL("resumes/cv.pdf") {
+		t.Fatalf("URL = %q", url)
+	}
+	want := []string{"CreateMultipartUpload", "UploadPart 1", "UploadPart 2", "UploadPart 3", "CompleteMultipartUpload"}
+	if ops := s3Ops(calls()); !slices.Equal(ops, want) {
+		t.Fatalf("S3 calls = %v, want %v", ops, want)
+	}
+
+	t.Run("failed part", func(t *testing.T) {
+		s, calls := newMultipartS3Service(t, "2")
+		_, err := s.UploadResumeMultipart(context.Background(), bytes.NewReader(paddedPDF(2*multipartThreshold+10)), "resumes/cv.pdf", pdfContentType)
+		if err == nil || !strings.Contains(err.Error(), "part 2") {
+			t.Fatalf("UploadResumeMultipart() error = %v, want part 2 to fail", err)
+		}
+		want := []string{"CreateMultipartUpload", "UploadPart 1", "UploadPart 2", "AbortMultipartUpload"}
+		if ops := s3Ops(calls()); !slices.Equal(ops, want) {
+			t.Fatalf("S3 calls = %v, want %v", ops, want)
+		}
+	})
+}
+
+func TestUploadService_GetPresignedDownloadURL(t *testing.T) {
+	client := s3.New(s3.Options{
+		Region:      "eu-west-1",
+		Credentials: credentials.NewStaticCredentialsProvider("AKIDEXAMPLE", "secret", ""),
+	})
+	s := &UploadService{client: client, bucket: "uploads"}
+
+	presigned, err := s.GetPresignedDownloadURL(context.Background(), "resumes/2026/10/cv.pdf", 15*time.Minute)
+	if err != nil {
+		t.Fatalf("GetPresignedDownloadURL() error = %v", err)
+	}
+	u, err := url.Parse(presigned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := u.Query()
+	if u.Host != "uploads.s3.eu-west-1.amazonaws.com" || u.Path != "/resumes/2026/10/cv.pdf" {
+		t.Fatalf("URL = %s, want the object in the bucket", presigned)
+	}
+	if query.Get("X-Amz-Expires") != "900" || query.Get("X-Amz-Signature") == "" || query.Get("response-content-disposition") != "inline" {
+		t.Fatalf("URL = %s, want a 15 minute signed inline link", presigned)
+	}
+
+	for _, key := range []string{
+		"resumes/../offer-letters/app-1.pdf",
+		"resumes/./cv.pdf",
+		"resumes//cv.pdf",
+		"offer-letters/app-1.pdf",
+		"/resumes/cv.pdf",
+		"",
+	} {
+		if _, err := s.GetPresignedDownloadURL(context.Background(), key, time.Minute); !errors.Is(err, ErrInvalidResumeKey) {
+			t.Errorf("GetPresignedDownloadURL(%q) error = %v, want ErrInvalidResumeKey", key, err)
+		}
+	}
+}
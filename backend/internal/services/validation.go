@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// FieldType is the kind of value an application field holds
+type FieldType string
+
+// Supported application field types
+const (
+	FieldString  FieldType = "string"
+	FieldInteger FieldType = "integer"
+	FieldBoolean FieldType = "boolean"
+	FieldURL     FieldType = "url"
+	FieldURLList FieldType = "urlList"
+)
+
+// FieldRule constrains one application field. For lists, MaxLength is the
+// most items allowed.
+type FieldRule struct {
+	Name      string    `json:"name"`
+	Required  bool      `json:"required"`
+	Type      FieldType `json:"type"`
+	MaxLength int       `json:"maxLength,omitempty"`
+}
+
+// JobApplicationSchema lists the fields an application to a job must satisfy
+type JobApplicationSchema struct {
+	Fields []FieldRule `json:"fields"`
+}
+
+// baseApplicationFields apply to every job, ahead of any job-specific rules
+var baseApplicationFields = []FieldRule{
+	{Name: "jobId", Required: true, Type: FieldString},
+	{Name: "firstName", Required: true, Type: FieldString, MaxLength: 100},
+	{Name: "lastName", Required: true, Type: FieldString, MaxLength: 100},
+	{Name: "email", Required: true, Type: FieldString, MaxLength: 254},
+	{Name: "phone", Required: true, Type: FieldString, MaxLength: 50},
+	{Name: "resumeUrl", Required: true, Type: FieldURL},
+	{Name: "attachmentUrls", Type: FieldURLList, MaxLength: MaxApplicationAttachments},
+	{Name: "currentLocation", Required: true, Type: FieldString, MaxLength: 200},
+	{Name: "availability", Required: true, Type: FieldString},
+	{Name: "willingToRelocate", Type: FieldBoolean},
+}
+
+// FieldError describes why one field failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationErrors is every field error found in an input
+type ValidationErrors []FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Field + ": " + fieldErr.Message
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// ApplicationValidator checks applications against their job's schema
+type ApplicationValidator struct {
+	client *gateway.HubHRMSClient
+}
+
+// NewApplicationValidator creates a validator that loads job schemas from
+// Hub-HRMS
+func NewApplicationValidator(client *gateway.HubHRMSClient) *ApplicationValidator {
+	return &ApplicationValidator{client: client}
+}
+
+// Schema returns the rules for applications to jobID: the base fields every
+// application needs, followed by the job's own. A job-specific rule replaces
+// a base rule of the same name.
+func (v *ApplicationValidator) Schema(ctx context.Context, jobID string) (JobApplicationSchema, error) {
+	schema := JobApplicationSchema{Fields: append([]FieldRule(nil), baseApplicationFields...)}
+	if jobID == "" {
+		return schema, nil
+	}
+
+	resp, err := v.client.Query(ctx, gateway.GetJobApplicationSchemaQuery, map[string]interface{}{
+		"jobId": jobID,
+	})
+	if err != nil {
+		return schema, fmt.Errorf("failed to fetch application schema: %w", err)
+	}
+
+	// Round-trip through JSON to decode the generic response into rules
+	raw, err := json.Marshal(lookupField(resp.Data, "jobApplicationSchema"))
+	if err != nil {
+		return schema, err
+	}
+	var custom JobApplicationSchema
+	if err := json.Unmarshal(raw, &custom); err != nil {
+		return schema, fmt.Errorf("invalid application schema: %w", err)
+	}
+
+	for _, rule := range custom.Fields {
+		replaced := false
+		for i := range schema.Fields {
+			if schema.Fields[i].Name == rule.Name {
+				schema.Fields[i] = rule
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			schema.Fields = append(schema.Fields, rule)
+		}
+	}
+
+	return schema, nil
+}
+
+// ValidateAgainstSchema checks input against every rule in schema, returning
+// all failures rather than stopping at the first
+func (v *ApplicationValidator) ValidateAgainstSchema(schema JobApplicationSchema, input map[string]interface{}) ValidationErrors {
+	var errs ValidationErrors
+	for _, rule := range schema.Fields {
+		value, present := input[rule.Name]
+		if !present || value == nil || value == "" {
+			if rule.Required {
+				errs = append(errs, FieldError{Field: rule.Name, Message: "is required"})
+			}
+			continue
+		}
+
+		if msg := checkFieldType(rule, value); msg != "" {
+			errs = append(errs, FieldError{Field: rule.Name, Message: msg})
+		}
+	}
+	return errs
+}
+
+// checkFieldType returns why value does not satisfy rule, or "" if it does
+func checkFieldType(rule FieldRule, value interface{}) string {
+	switch rule.Type {
+	case FieldInteger:
+		n, ok := value.(float64)
+		if !ok || n != math.Trunc(n) {
+			return "must be an integer"
+		}
+		return ""
+	case FieldBoolean:
+		if _, ok := value.(bool); !ok {
+			return "must be true or false"
+		}
+		return ""
+	case FieldURLList:
+		items, ok := value.([]interface{})
+		if !ok {
+			return "must be a list of URLs"
+		}
+		if rule.MaxLength > 0 && len(items) > rule.MaxLength {
+			return fmt.Sprintf("must have at most %d items", rule.MaxLength)
+		}
+		for _, item := range items {
+			if s, ok := item.(string); !ok || !isWebURL(s) {
+				return "must contain only http or https URLs"
+			}
+		}
+		return ""
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return "must be a string"
+	}
+	if rule.MaxLength > 0 && utf8.RuneCountInString(s) > rule.MaxLength {
+		return fmt.Sprintf("must be at most %d characters", rule.MaxLength)
+	}
+	if rule.Type == FieldURL && !isWebURL(s) {
+		return "must be an http or https URL"
+	}
+	return ""
+}
+
+// isWebURL reports whether s is an absolute http or https URL
+func isWebURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// lookupField returns the value at key if data is a JSON object
+func lookupField(data interface{}, key string) interface{} {
+	m, _ := data.(map[string]interface{})
+	return m[key]
+}
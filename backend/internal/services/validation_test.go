@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"hr-recruiting/internal/gateway"
+)
+
+// schemaServer fakes Hub-HRMS answering GetJobApplicationSchemaQuery with
+// fields for every job
+func schemaServer(t *testing.T, fields []FieldRule) *gateway.HubHRMSClient {
+	return newFakeHubHRMS(t, func(req gateway.GraphQLRequest) interface{} {
+		if req.Query != gateway.GetJobApplicationSchemaQuery {
+			return map[string]interface{}{}
+		}
+		return map[string]interface{}{"jobApplicationSchema": map[string]interface{}{"fields": fields}}
+	})
+}
+
+// baseApplication fills in every base field with a valid value
+func baseApplication() map[string]interface{} {
+	return map[string]interface{}{
+		"jobId":           "job-1",
+		"firstName":       "Ada",
+		"lastName":        "Lovelace",
+		"email":           "ada@example.com",
+		"phone":           "+44 20 7946 0000",
+		"resumeUrl":       "https://cdn.example.com/ada.pdf",
+		"currentLocation": "London",
+		"availability":    "immediately",
+	}
+}
+
+// errorFields returns the fields errs are about
+func errorFields(errs ValidationErrors) []string {
+	fields := make([]string, len(errs))
+	for i, err := range errs {
+		fields[i] = err.Field
+	}
+	return fields
+}
+
+func TestApplicationValidator_Schema(t *testing.T) {
+	validator := NewApplicationValidator(schemaServer(t, []FieldRule{
+		{Name: "githubUrl", Required: true, Type: FieldURL},
+		{Name: "phone", Type: FieldString, MaxLength: 20},
+	}))
+
+	schema, err := validator.Schema(context.Background(), "job-1")
+	if err != nil {
+		t.Fatalf("Schema() error = %v", err)
+	}
+	if len(schema.Fields) != len(baseApplicationFields)+1 {
+		t.Fatalf("schema has %d fields, want the %d base fields and githubUrl", len(schema.Fields), len(baseApplicationFields))
+	}
+	if last := schema.Fields[len(schema.Fields)-1]; last.Name != "githubUrl" || !last.Required {
+		t.Fatalf("last field = %+v, want required githubUrl", last)
+	}
+
+	// The job's phone rule replaces the base one in place
+	i := slices.IndexFunc(schema.Fields, func(rule FieldRule) bool { return rule.Name == "phone" })
+	if rule := schema.Fields[i]; rule.Required || rule.MaxLength != 20 {
+		t.Fatalf("phone rule = %+v, want optional with MaxLength 20", rule)
+	}
+	if baseApplicationFields[i].Name != "phone" || !baseApplicationFields[i].Required {
+		t.Fatal("overriding a rule changed the base fields")
+	}
+
+	t.Run("no job", func(t *testing.T) {
+		schema, err := validator.Schema(context.Background(), "")
+		if err != nil || len(schema.Fields) != len(baseApplicationFields) {
+			t.Fatalf("Schema(\"\") = %d fields, %v, want the base fields", len(schema.Fields), err)
+		}
+	})
+}
+
+func TestApplicationValidator_ValidateAgainstSchema(t *testing.T) {
+	schema := JobApplicationSchema{Fields: append(slices.Clone(baseApplicationFields),
+		FieldRule{Name: "githubUrl", Required: true, Type: FieldURL},
+		FieldRule{Name: "yearsExperience", Required: true, Type: FieldInteger},
+		FieldRule{Name: "territory", Type: FieldString, MaxLength: 10},
+		FieldRule{Name: "hasVisa", Type: FieldBoolean},
+	)}
+	validator := NewApplicationValidator(nil)
+
+	tests := []struct {
+		name  string
+		set   map[string]interface{}
+		unset []string
+		want  []string
+	}{
+		{
+			name: "required custom fields present",
+			set:  map[string]interface{}{"githubUrl": "https://github.com/ada", "yearsExperience": 5.0},
+		},
+		{
+			name: "optional custom fields valid",
+			set: map[string]interface{}{
+				"githubUrl": "https://github.com/ada", "yearsExperience": 5.0,
+				"territory": "EMEA", "hasVisa": true,
+			},
+		},
+		{
+			name: "required custom fields missing",
+			set:  map[string]interface{}{"githubUrl": ""},
+			want: []string{"githubUrl", "yearsExperience"},
+		},
+		{
+			name:  "base and custom fields missing together",
+			unset: []string{"email", "resumeUrl"},
+			want:  []string{"email", "resumeUrl", "githubUrl", "yearsExperience"},
+		},
+		{
+			name: "type constraints",
+			set: map[string]interface{}{
+				"githubUrl": "ftp://github.com/ada", "yearsExperience": 2.5,
+				"territory": "Asia Pacific", "hasVisa": "yes",
+			},
+			want: []string{"githubUrl", "yearsExperience", "territory", "hasVisa"},
+		},
+		{
+			name: "integer given as a string",
+			set:  map[string]interface{}{"githubUrl": "https://github.com/ada", "yearsExperience": "5"},
+			want: []string{"yearsExperience"},
+		},
+		{
+			name: "url list",
+			set: map[string]interface{}{
+				"githubUrl": "https://github.com/ada", "yearsExperience": 5.0,
+				"attachmentUrls": []interface{}{"https://cdn.example.com/a.pdf", "javascript:alert(1)"},
+			},
+			want: []string{"attachmentUrls"},
+		},
+		{
+			name: "url list at the attachment limit",
+			set: map[string]interface{}{
+				"githubUrl": "https://github.com/ada", "yearsExperience": 5.0,
+				"attachmentUrls": []interface{}{
+					"https://cdn.example.com/1.pdf", "https://cdn.example.com/2.pdf", "https://cdn.example.com/3.pdf",
+					"https://cdn.example.com/4.pdf", "https://cdn.example.com/5.pdf",
+				},
+			},
+		},
+		{
+			name: "url list over the attachment limit",
+			set: map[string]interface{}{
+				"githubUrl": "https://github.com/ada", "yearsExperience": 5.0,
+				"attachmentUrls": []interface{}{
+					"https://cdn.example.com/1.pdf", "https://cdn.example.com/2.pdf", "https://cdn.example.com/3.pdf",
+					"https://cdn.example.com/4.pdf", "https://cdn.example.com/5.pdf", "https://cdn.example.com/6.pdf",
+				},
+			},
+			want: []string{"attachmentUrls"},
+		},
+		{
+			name: "url list given as a string",
+			set: map[string]interface{}{
+				"githubUrl": "https://github.com/ada", "yearsExperience": 5.0,
+				"attachmentUrls": "https://cdn.example.com/a.pdf",
+			},
+			want: []string{"attachmentUrls"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := baseApplication()
+			for field, value := range tt.set {
+				input[field] = value
+			}
+			for _, field := range tt.unset {
+				delete(input, field)
+			}
+
+			errs := validator.ValidateAgainstSchema(schema, input)
+			if got := errorFields(errs); !slices.Equal(got, tt.want) {
+				t.Fatalf("errors on %v, want %v: %v", got, tt.want, errs)
+			}
+		})
+	}
+}
@@ -0,0 +1,270 @@
+package services
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Webhook event types
+const (
+	EventApplicationSubmitted     = "application.submitted"
+	EventApplicationStatusChanged = "application.status_changed"
+	EventApplicationScored        = "application.scored"
+	EventJobPublished             = "job.published"
+	EventJobClosed                = "job.closed"
+)
+
+// WebhookEventTypes lists every event a subscriber may filter on
+var WebhookEventTypes = []string{
+	EventApplicationSubmitted,
+	EventApplicationStatusChanged,
+	EventApplicationScored,
+	EventJobPublished,
+	EventJobClosed,
+}
+
+var (
+	// ErrWebhookNotFound is returned when unregistering an unknown subscription
+	ErrWebhookNotFound = errors.New("webhook not found")
+	// ErrInvalidWebhook is returned for subscriptions with a bad URL or event
+	ErrInvalidWebhook = errors.New("invalid webhook subscription")
+)
+
+// WebhookSubscription is a registered event receiver
+type WebhookSubscription struct {
+	ID        string    `json:"id"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	Secret    string    `json:"secret,omitempty"`
+	CreatedBy string    `json:"createdBy,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// WebhookEvent is the payload POSTed to subscribers
+type WebhookEvent struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	OccurredAt time.Time   `json:"occurredAt"`
+	Data       interface{} `json:"data"`
+}
+
+// ApplicationEventData is the data for application.* events
+type ApplicationEventData struct {
+	ApplicationID string `json:"applicationId"`
+	JobID         string `json:"jobId,omitempty"`
+	Status        string `json:"status,omitempty"`
+}
+
+// JobEventData is the data for job.* events
+type JobEventData struct {
+	JobID  string `json:"jobId"`
+	Title  string `json:"title,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+// WebhookService delivers events to registered subscribers
+type WebhookService struct {
+	mu            sync.RWMutex
+	subscriptions map[string]*WebhookSubscription
+
+	client      *http.Client
+	maxAttempts int
+	baseDelay   time.Duration
+
+	// enabled is consulted on every Publish; nil means always on
+	enabled func() bool
+}
+
+// NewWebhookService creates a new webhook service. Subscriptions are kept in
+// memory, so they must be registered again after a restart. enabled, when
+// not nil, switches delivery on and off at runtime.
+func NewWebhookService(enabled func() bool) *WebhookService {
+	return &WebhookService{
+		enabled:       enabled,
+		subscriptions: make(map[string]*WebhookSubscription),
+		client:        &http.Client{Timeout: 10 * time.Second},
+		maxAttempts:   5,
+		baseDelay:     time.Second,
+	}
+}
+
+// Register adds a subscription. A signing secret is generated when none is
+// given; it is only ever returned from this call.
+func (s *WebhookService) Register(targetURL string, events []string, secret, createdBy string) (*WebhookSubscription, error) {
+	u, err := url.Parse(targetURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return nil, fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidWebhook)
+	}
+	if len(events) == 0 {
+		return nil, fmt.Errorf("%w: at least one event is required", ErrInvalidWebhook)
+	}
+	for _, event := range events {
+		if !isWebhookEventType(event) {
+			return nil, fmt.Errorf("%w: unknown event %q", ErrInvalidWebhook, event)
+		}
+	}
+
+	if secret == "" {
+		buf := make([]byte, 32)
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+		}
+		secret = hex.EncodeToString(buf)
+	}
+
+	sub := &WebhookSubscription{
+		ID:        uuid.New().String(),
+		URL:       targetURL,
+		Events:    events,
+		Secret:    secret,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	s.mu.Lock()
+	s.subscriptions[sub.ID] = sub
+	s.mu.Unlock()
+
+	return sub, nil
+}
+
+// Unregister removes a subscription
+func (s *WebhookService) Unregister(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.subscriptions[id]; !ok {
+		return ErrWebhookNotFound
+	}
+	delete(s.subscriptions, id)
+	return nil
+}
+
+// List returns all subscriptions, oldest first, without their secrets
+func (s *WebhookService) List() []WebhookSubscription {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]WebhookSubscription, 0, len(s.subscriptions))
+	for _, sub := range s.subscriptions {
+		redacted := *sub
+		redacted.Secret = ""
+		list = append(list, redacted)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].CreatedAt.Before(list[j].CreatedAt)
+	})
+	return list
+}
+
+// Publish delivers an event to every matching subscriber in the background.
+// Nothing is sent while delivery is disabled.
+func (s *WebhookService) Publish(eventType string, data interface{}) {
+	if s.enabled != nil && !s.enabled() {
+		return
+	}
+
+	event := WebhookEvent{
+		ID:         uuid.New().String(),
+		Type:       eventType,
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		slog.Error("failed to marshal webhook event", "event", eventType, "error", err)
+		return
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, sub := range s.subscriptions {
+		if sub.subscribesTo(eventType) {
+			go s.deliver(*sub, event, payload)
+		}
+	}
+}
+
+// deliver POSTs payload to a subscriber, retrying with exponential backoff
+func (s *WebhookService) deliver(sub WebhookSubscription, event WebhookEvent, payload []byte) {
+	signature := ComputeWebhookSignature(sub.Secret, payload)
+
+	for attempt := 0; attempt < s.maxAttempts; attempt++ {
+		if attempt > 0 {
+			time.Sleep(s.baseDelay << (attempt - 1))
+		}
+
+		err := s.send(sub.URL, event, payload, signature)
+		if err == nil {
+			return
+		}
+		slog.Warn("webhook delivery failed",
+			"delivery_id", event.ID, "url", sub.URL, "attempt", attempt+1, "max_attempts", s.maxAttempts, "error", err)
+	}
+
+	slog.Error("webhook delivery abandoned", "delivery_id", event.ID, "url", sub.URL)
+}
+
+func (s *WebhookService) send(targetURL string, event WebhookEvent, payload []byte, signature string) error {
+	req, err := http.NewRequest("POST", targetURL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Event", event.Type)
+	req.Header.Set("X-Webhook-ID", event.ID)
+	req.Header.Set("X-Signature", signature)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("subscriber returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// ComputeWebhookSignature returns the X-Signature header value for payload,
+// "sha256=" followed by the hex encoded HMAC-SHA256 of the body
+func ComputeWebhookSignature(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func (sub *WebhookSubscription) subscribesTo(eventType string) bool {
+	for _, event := range sub.Events {
+		if event == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+func isWebhookEventType(event string) bool {
+	for _, known := range WebhookEventTypes {
+		if event == known {
+			return true
+		}
+	}
+	return false
+}
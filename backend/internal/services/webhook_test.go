@@ -0,0 +1,208 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestComputeWebhookSignature(t *testing.T) {
+	// RFC 4231-style known answer for HMAC-SHA256
+	got := ComputeWebhookSignature("key", []byte("The quick brown fox jumps over the lazy dog"))
+	want := "sha256=f7bc83f430538424b13298e6aa6fb143ef4d59a14946175997479dbc2d1a3cd8"
+	if got != want {
+		t.Fatalf("ComputeWebhookSignature() = %s, want %s", got, want)
+	}
+	if ComputeWebhookSignature("other", []byte("body")) == ComputeWebhookSignature("key", []byte("body")) {
+		t.Fatal("signature does not depend on the secret")
+	}
+}
+
+// webhookDelivery is one request received by a fake subscriber
+type webhookDelivery struct {
+	header http.Header
+	body   []byte
+}
+
+// newFakeSubscriber answers with statuses in turn, then 204, and sends every
+// request it receives on the returned channel
+func newFakeSubscriber(t *testing.T, statuses ...int) (*httptest.Server, chan webhookDelivery) {
+	t.Helper()
+	deliveries := make(chan webhookDelivery, 10)
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		deliveries <- webhookDelivery{header: r.Header.Clone(), body: body}
+
+		mu.Lock()
+		defer mu.Unlock()
+		if len(statuses) > 0 {
+			w.WriteHeader(statuses[0])
+			statuses = statuses[1:]
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	return server, deliveries
+}
+
+// receive waits for the next delivery
+func receive(t *testing.T, deliveries chan webhookDelivery) webhookDelivery {
+	t.Helper()
+	select {
+	case delivery := <-deliveries:
+		return delivery
+	case <-time.After(2 * time.Second):
+		t.Fatal("no webhook delivered")
+		return webhookDelivery{}
+	}
+}
+
+// assertNoDelivery fails when a delivery arrives within a short wait
+func assertNoDelivery(t *testing.T, deliveries chan webhookDelivery) {
+	t.Helper()
+	select {
+	case delivery := <-deliveries:
+		t.Fatalf("unexpected delivery %s", delivery.body)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestWebhookService_Publish(t *testing.T) {
+	subscriber, deliveries := newFakeSubscriber(t)
+	service := NewWebhookService(nil)
+	sub, err := service.Register(subscriber.URL, []string{EventApplicationSubmitted}, "s3cret", "u1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	service.Publish(EventApplicationSubmitted, ApplicationEventData{ApplicationID: "app-1", JobID: "job-1", Status: "NEW"})
+	delivery := receive(t, deliveries)
+
+	if got := delivery.header.Get("X-Signature"); got != ComputeWebhookSignature(sub.Secret, delivery.body) {
+		t.Fatalf("X-Signature = %s, want the HMAC of the body", got)
+	}
+	var event struct {
+		ID   string               `json:"id"`
+		Type string               `json:"type"`
+		Data ApplicationEventData `json:"data"`
+	}
+	if err := json.Unmarshal(delivery.body, &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Type != EventApplicationSubmitted || event.Data.ApplicationID != "app-1" || event.Data.Status != "NEW" {
+		t.Fatalf("event = %s", delivery.body)
+	}
+	if delivery.header.Get("X-Webhook-Event") != EventApplicationSubmitted || delivery.header.Get("X-Webhook-ID") != event.ID {
+		t.Fatalf("headers = %v", delivery.header)
+	}
+
+	// Events the subscriber did not ask for are not sent
+	service.Publish(EventJobClosed, JobEventData{JobID: "job-1"})
+	assertNoDelivery(t, deliveries)
+
+	if err := service.Unregister(sub.ID); err != nil {
+		t.Fatal(err)
+	}
+	service.Publish(EventApplicationSubmitted, ApplicationEventData{ApplicationID: "app-2"})
+	assertNoDelivery(t, deliveries)
+}
+
+func TestWebhookService_Publish_Disabled(t *testing.T) {
+	subscriber, deliveries := newFakeSubscriber(t)
+	service := NewWebhookService(func() bool { return false })
+	if _, err := service.Register(subscriber.URL, []string{EventJobPublished}, "", ""); err != nil {
+		t.Fatal(err)
+	}
+	service.Publish(EventJobPublished, JobEventData{JobID: "job-1"})
+	assertNoDelivery(t, deliveries)
+}
+
+func TestWebhookService_Retry(t *testing.T) {
+	t.Run("succeeds after failures", func(t *testing.T) {
+		subscriber, deliveries := newFakeSubscriber(t, http.StatusInternalServerError, http.StatusBadGateway)
+		service := NewWebhookService(nil)
+		service.baseDelay = time.Millisecond
+		if _, err := service.Register(subscriber.URL, []string{EventJobPublished}, "s3cret", ""); err != nil {
+			t.Fatal(err)
+		}
+
+		service.Publish(EventJobPublished, JobEventData{JobID: "job-1"})
+		first := receive(t, deliveries)
+		for i := 0; i < 2; i++ {
+			retry := receive(t, deliveries)
+			if string(retry.body) != string(first.body) || retry.header.Get("X-Signature") != first.header.Get("X-Signature") {
+				t.Fatal("retry sent a different delivery")
+			}
+		}
+		assertNoDelivery(t, deliveries)
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		failures := make([]int, 10)
+		for i := range failures {
+			failures[i] = http.StatusServiceUnavailable
+		}
+		subscriber, deliveries := newFakeSubscriber(t, failures...)
+		service := NewWebhookService(nil)
+		service.baseDelay = time.Millisecond
+		if _, err := service.Register(subscriber.URL, []string{EventJobClosed}, "", ""); err != nil {
+			t.Fatal(err)
+		}
+
+		service.Publish(EventJobClosed, JobEventData{JobID: "job-1"})
+		for i := 0; i < service.maxAttempts; i++ {
+			receive(t, deliveries)
+		}
+		assertNoDelivery(t, deliveries)
+	})
+}
+
+func TestWebhookService_Register(t *testing.T) {
+	service := NewWebhookService(nil)
+
+	tests := []struct {
+		name   string
+		url    string
+		events []string
+	}{
+		{name: "relative URL", url: "/hooks", events: []string{EventJobPublished}},
+		{name: "other scheme", url: "ftp://example.com/hooks", events: []string{EventJobPublished}},
+		{name: "no events", url: "https://example.com/hooks"},
+		{name: "unknown event", url: "https://example.com/hooks", events: []string{"job.deleted"}},
+	}
+	for _, tt := range tests {
+		if _, err := service.Register(tt.url, tt.events, "", ""); !errors.Is(err, ErrInvalidWebhook) {
+			t.Errorf("%s: Register() error = %v, want ErrInvalidWebhook", tt.name, err)
+		}
+	}
+
+	first, err := service.Register("https://example.com/a", []string{EventJobPublished}, "", "u1")
+	if err != nil || len(first.Secret) != 64 {
+		t.Fatalf("Register() = %+v, %v; want a generated secret", first, err)
+	}
+	second, _ := service.Register("https://example.com/b", []string{EventJobClosed}, "given", "u1")
+	if second.Secret != "given" {
+		t.Fatalf("Secret = %q, want the one given", second.Secret)
+	}
+
+	list := service.List()
+	if len(list) != 2 || list[0].ID != first.ID || list[1].ID != second.ID {
+		t.Fatalf("List() = %+v, want both, oldest first", list)
+	}
+	for _, sub := range list {
+		if sub.Secret != "" {
+			t.Fatalf("List() exposed the secret of %s", sub.ID)
+		}
+	}
+
+	if err := service.Unregister("missing"); !errors.Is(err, ErrWebhookNotFound) {
+		t.Fatalf("Unregister() error = %v, want ErrWebhookNotFound", err)
+	}
+}
@@ -0,0 +1,102 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSetup_ExportsOTLP(t *testing.T) {
+	exports := make(chan *coltracepb.ExportTraceServiceRequest, 10)
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/traces" || r.Header.Get("Content-Type") != "application/x-protobuf" {
+			t.Errorf("export request = %s %s (%s)", r.Method, r.URL.Path, r.Header.Get("Content-Type"))
+		}
+		body, _ := io.ReadAll(r.Body)
+		var export coltracepb.ExportTraceServiceRequest
+		if err := proto.Unmarshal(body, &export); err != nil {
+			t.Errorf("collector received an invalid export: %v", err)
+		}
+		exports <- &export
+	}))
+	defer collector.Close()
+
+	tp, shutdown, err := Setup(context.Background(), collector.URL+"/", "hr-recruiting-test")
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	if otel.GetTracerProvider() != tp {
+		t.Fatal("Setup() did not install the provider globally")
+	}
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "GET /jobs/{id}", trace.WithSpanKind(trace.SpanKindServer))
+	_, child := tp.Tracer("test").Start(ctx, "hubhrms.query", trace.WithSpanKind(trace.SpanKindClient))
+	child.End()
+	parent.End()
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := shutdown(shutdownCtx); err != nil {
+		t.Fatalf("shutdown() error = %v", err)
+	}
+
+	if len(exports) != 1 {
+		t.Fatalf("collector received %d exports, want 1", len(exports))
+	}
+	resourceSpans := (<-exports).ResourceSpans[0]
+	serviceName := ""
+	for _, attr := range resourceSpans.Resource.Attributes {
+		if attr.Key == "service.name" {
+			serviceName = attr.Value.GetStringValue()
+		}
+	}
+	if serviceName != "hr-recruiting-test" {
+		t.Fatalf("service.name = %q, want %q", serviceName, "hr-recruiting-test")
+	}
+
+	spans := resourceSpans.ScopeSpans[0].Spans
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	gotChild, gotParent := spans[0], spans[1]
+	if gotParent.Name != "GET /jobs/{id}" || gotChild.Name != "hubhrms.query" {
+		t.Fatalf("span names = %q, %q", gotParent.Name, gotChild.Name)
+	}
+	if string(gotChild.TraceId) != string(gotParent.TraceId) || string(gotChild.ParentSpanId) != string(gotParent.SpanId) {
+		t.Fatal("child span is not part of the parent's trace")
+	}
+}
+
+func TestSetup_Disabled(t *testing.T) {
+	tp, shutdown, err := Setup(context.Background(), "", "hr-recruiting-test")
+	if err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	_, span := tp.Tracer("test").Start(context.Background(), "GET /")
+	if span.IsRecording() {
+		t.Fatal("disabled tracing recorded a span")
+	}
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown() error = %v", err)
+	}
+
+	// Incoming trace context is still passed on to Hub-HRMS
+	const traceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	in := http.Header{"Traceparent": {traceparent}}
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(in))
+	out := make(http.Header)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(out))
+	if got := out.Get("traceparent"); got != traceparent {
+		t.Fatalf("traceparent = %q, want %q", got, traceparent)
+	}
+}
@@ -0,0 +1,115 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cursorVersion identifies the cursor payload format
+const cursorVersion = "v1"
+
+var (
+	// ErrInvalidCursor is returned for cursors that are malformed or tampered with
+	ErrInvalidCursor = errors.New("invalid cursor")
+	// ErrExpiredCursor is returned for cursors older than the configured TTL
+	ErrExpiredCursor = errors.New("cursor has expired")
+)
+
+// cursorPayload is the signed content of a cursor
+type cursorPayload struct {
+	Type      string `json:"type"`
+	Value     string `json:"value"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+var (
+	cursorMu     sync.RWMutex
+	cursorSecret = randomSecret()
+	cursorTTL    = 24 * time.Hour
+)
+
+// ConfigureCursors sets the signing secret and lifetime for pagination
+// cursors. An empty secret keeps the random per-process secret, which means
+// cursors do not survive a restart.
+func ConfigureCursors(secret string, ttl time.Duration) {
+	cursorMu.Lock()
+	defer cursorMu.Unlock()
+
+	if secret != "" {
+		cursorSecret = []byte(secret)
+	}
+	if ttl > 0 {
+		cursorTTL = ttl
+	}
+}
+
+// EncodeCursor wraps value in an opaque, signed cursor
+func EncodeCursor(value string) string {
+	payload, _ := json.Marshal(cursorPayload{
+		Type:      cursorVersion,
+		Value:     value,
+		Timestamp: time.Now().Unix(),
+	})
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return encoded + "." + base64.RawURLEncoding.EncodeToString(sign(payload))
+}
+
+// DecodeCursor verifies a cursor and returns the value it wraps
+func DecodeCursor(cursor string) (string, error) {
+	encoded, signature, ok := strings.Cut(cursor, ".")
+	if !ok {
+		return "", ErrInvalidCursor
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return "", ErrInvalidCursor
+	}
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil {
+		return "", ErrInvalidCursor
+	}
+	if !hmac.Equal(mac, sign(payload)) {
+		return "", ErrInvalidCursor
+	}
+
+	var decoded cursorPayload
+	if err := json.Unmarshal(payload, &decoded); err != nil || decoded.Type != cursorVersion {
+		return "", ErrInvalidCursor
+	}
+
+	cursorMu.RLock()
+	ttl := cursorTTL
+	cursorMu.RUnlock()
+
+	if time.Since(time.Unix(decoded.Timestamp, 0)) > ttl {
+		return "", ErrExpiredCursor
+	}
+
+	return decoded.Value, nil
+}
+
+func sign(payload []byte) []byte {
+	cursorMu.RLock()
+	defer cursorMu.RUnlock()
+
+	mac := hmac.New(sha256.New, cursorSecret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func randomSecret() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic("failed to generate signing secret: " + err.Error())
+	}
+	return secret
+}
@@ -0,0 +1,110 @@
+package util
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// issuedValues are the values of the seed cursors FuzzDecodeCursor starts
+// from; no other value can come out of a cursor without the secret
+var issuedValues = map[string]bool{"0": true, "50": true, "job-123": true, "": true}
+
+func init() {
+	ConfigureCursors("test-cursor-secret-0123456789abcdef", time.Hour)
+}
+
+// signedCursor builds a cursor for payload signed with the configured secret
+func signedCursor(t testing.TB, payload cursorPayload) string {
+	t.Helper()
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.RawURLEncoding.EncodeToString(data) + "." + base64.RawURLEncoding.EncodeToString(sign(data))
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	for value := range issuedValues {
+		got, err := DecodeCursor(EncodeCursor(value))
+		if err != nil || got != value {
+			t.Fatalf("DecodeCursor(EncodeCursor(%q)) = %q, %v", value, got, err)
+		}
+	}
+}
+
+func TestDecodeCursor_RejectsTampered(t *testing.T) {
+	valid := EncodeCursor("50")
+	encoded, signature, _ := strings.Cut(valid, ".")
+
+	forgedPayload, _ := json.Marshal(cursorPayload{Type: cursorVersion, Value: "5000", Timestamp: time.Now().Unix()})
+	forged := base64.RawURLEncoding.EncodeToString(forgedPayload)
+
+	altered := "A" + signature[1:]
+	if altered == signature {
+		altered = "B" + signature[1:]
+	}
+
+	mac := hmac.New(sha256.New, []byte("another-secret"))
+	mac.Write(forgedPayload)
+	otherSecret := mac.Sum(nil)
+
+	tests := map[string]string{
+		"value replaced, signature kept":    forged + "." + signature,
+		"value replaced, signed elsewhere":  forged + "." + base64.RawURLEncoding.EncodeToString(otherSecret),
+		"signature altered":                 encoded + "." + altered,
+		"signature removed":                 encoded + ".",
+		"signature truncated":               encoded + "." + signature[:len(signature)-4],
+		"no signature":                      encoded,
+		"payload not base64":                "!!!." + signature,
+		"signature not base64":              encoded + ".!!!",
+		"raw offset":                        "50",
+		"empty":                             "",
+		"signed payload of another version": signedCursor(t, cursorPayload{Type: "v0", Value: "50", Timestamp: time.Now().Unix()}),
+		"signed payload that is not JSON":   base64.RawURLEncoding.EncodeToString([]byte("50")) + "." + base64.RawURLEncoding.EncodeToString(sign([]byte("50"))),
+	}
+
+	for name, cursor := range tests {
+		t.Run(name, func(t *testing.T) {
+			if value, err := DecodeCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("DecodeCursor() = %q, %v, want %v", value, err, ErrInvalidCursor)
+			}
+		})
+	}
+}
+
+func TestDecodeCursor_RejectsExpired(t *testing.T) {
+	cursor := signedCursor(t, cursorPayload{Type: cursorVersion, Value: "50", Timestamp: time.Now().Add(-2 * time.Hour).Unix()})
+	if _, err := DecodeCursor(cursor); !errors.Is(err, ErrExpiredCursor) {
+		t.Fatalf("DecodeCursor() error = %v, want %v", err, ErrExpiredCursor)
+	}
+}
+
+// FuzzDecodeCursor tries to forge cursors by mutating genuine ones. Only the
+// values that were really issued may ever decode.
+func FuzzDecodeCursor(f *testing.F) {
+	for value := range issuedValues {
+		f.Add(EncodeCursor(value))
+	}
+	f.Add("")
+	f.Add(".")
+	f.Add("eyJ0eXBlIjoidjEiLCJ2YWx1ZSI6IjEwMDAiLCJ0aW1lc3RhbXAiOjB9.AAAA")
+
+	f.Fuzz(func(t *testing.T, cursor string) {
+		value, err := DecodeCursor(cursor)
+		if err != nil {
+			if !errors.Is(err, ErrInvalidCursor) && !errors.Is(err, ErrExpiredCursor) {
+				t.Fatalf("DecodeCursor(%q) returned unexpected error %v", cursor, err)
+			}
+			return
+		}
+		if !issuedValues[value] {
+			t.Fatalf("DecodeCursor(%q) accepted forged value %q", cursor, value)
+		}
+	})
+}
@@ -0,0 +1,87 @@
+package util
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTokenSigner(t *testing.T) {
+	signer := NewTokenSigner("test-secret", time.Hour)
+
+	t.Run("valid", func(t *testing.T) {
+		token := signer.Sign("cand-1", "candidate-data")
+		if err := signer.Verify(token, "cand-1", "candidate-data"); err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		// Another signer with the same secret, as after a restart
+		if err := NewTokenSigner("test-secret", time.Minute).Verify(token, "cand-1", "candidate-data"); err != nil {
+			t.Fatalf("Verify() with the same secret error = %v", err)
+		}
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		token := NewTokenSigner("test-secret", -time.Second).Sign("cand-1", "candidate-data")
+		if err := signer.Verify(token, "cand-1", "candidate-data"); !errors.Is(err, ErrExpiredToken) {
+			t.Fatalf("Verify() error = %v, want %v", err, ErrExpiredToken)
+		}
+	})
+
+	t.Run("tampered", func(t *testing.T) {
+		token := signer.Sign("cand-1", "candidate-data")
+		encoded, signature, _ := strings.Cut(token, ".")
+
+		// The payload rewritten for another candidate, keeping the signature
+		payload, _ := base64.RawURLEncoding.DecodeString(encoded)
+		var decoded tokenPayload
+		json.Unmarshal(payload, &decoded)
+		decoded.Subject = "cand-2"
+		rewritten, _ := json.Marshal(decoded)
+		forged := base64.RawURLEncoding.EncodeToString(rewritten) + "." + signature
+
+		// A flipped bit in the signature
+		mac, _ := base64.RawURLEncoding.DecodeString(signature)
+		mac[0] ^= 1
+		flipped := encoded + "." + base64.RawURLEncoding.EncodeToString(mac)
+
+		for name, token := range map[string]string{
+			"rewritten payload": forged,
+			"changed signature": flipped,
+			"other secret":      NewTokenSigner("other-secret", time.Hour).Sign("cand-1", "candidate-data"),
+			"no signature":      encoded,
+			"not base64":        "!!!.???",
+			"empty":             "",
+		} {
+			if err := signer.Verify(token, "cand-1", "candidate-data"); !errors.Is(err, ErrInvalidToken) {
+				t.Errorf("%s: Verify() error = %v, want %v", name, err, ErrInvalidToken)
+			}
+		}
+		if err := signer.Verify(forged, "cand-2", "candidate-data"); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("rewritten payload verified for its new subject: %v", err)
+		}
+	})
+
+	t.Run("other subject or purpose", func(t *testing.T) {
+		token := signer.Sign("cand-1", "candidate-data")
+		if err := signer.Verify(token, "cand-2", "candidate-data"); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("Verify() for another candidate error = %v, want %v", err, ErrInvalidToken)
+		}
+		if err := signer.Verify(token, "cand-1", "share-link"); !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("Verify() for another purpose error = %v, want %v", err, ErrInvalidToken)
+		}
+	})
+
+	t.Run("random secret", func(t *testing.T) {
+		a, b := NewTokenSigner("", time.Hour), NewTokenSigner("", time.Hour)
+		token := a.Sign("cand-1", "candidate-data")
+		if err := a.Verify(token, "cand-1", "candidate-data"); err != nil {
+			t.Fatalf("Verify() error = %v", err)
+		}
+		if err := b.Verify(token, "cand-1", "candidate-data"); !errors.Is(err, ErrInvalidToken) {
+			t.Fatalf("token verified by a signer with another random secret: %v", err)
+		}
+	})
+}
@@ -21,6 +21,7 @@ export interface Job {
   status: 'draft' | 'open' | 'closed';
   views: number;
   applications_count: number;
+  variant?: 'a' | 'b';
 }
 
 export interface Application {
@@ -57,17 +58,41 @@ class APIError extends Error {
   }
 }
 
+// Token echoed in X-CSRF-Token on mutations, matching the csrf_token cookie
+let csrfToken: string | null = null;
+
+async function getCSRFToken(): Promise<string> {
+  if (!csrfToken) {
+    const response = await fetch(`${API_BASE_URL}/csrf-token`, { credentials: 'include' });
+    if (!response.ok) {
+      throw new APIError(response.status, 'Failed to obtain CSRF token');
+    }
+    csrfToken = (await response.json()).token as string;
+  }
+  return csrfToken;
+}
+
 async function fetchAPI(endpoint: string, options: RequestInit = {}) {
   const url = `${API_BASE_URL}${endpoint}`;
-  
+  const method = (options.method || 'GET').toUpperCase();
+  const csrfHeader: Record<string, string> =
+    method === 'GET' || method === 'HEAD' ? {} : { 'X-CSRF-Token': await getCSRFToken() };
+
   const response = await fetch(url, {
     ...options,
+    credentials: 'include',
     headers: {
       'Content-Type': 'application/json',
+      ...csrfHeader,
       ...options.headers,
     },
   });
 
+  if (response.status === 403 && csrfToken) {
+    // The cookie may have been replaced; fetch a new token next time
+    csrfToken = null;
+  }
+
   if (!response.ok) {
     const errorData = await response.json().catch(() => ({}));
     throw new APIError(
@@ -91,16 +116,24 @@ export const jobsAPI = {
     const endpoint = queryString ? `/jobs?${queryString}` : '/jobs';
     
     const data = await fetchAPI(endpoint);
-    return data.jobs || [];
+    return data.items || [];
   },
 
-  async get(id: string): Promise<Job> {
-    const data = await fetchAPI(`/jobs/${id}`);
-    return data.job;
+  // Accepts a job ID or the slug from a canonical /jobs/<slug> URL
+  async get(idOrSlug: string): Promise<Job> {
+    try {
+      const data = await fetchAPI(`/jobs/${encodeURIComponent(idOrSlug)}`);
+      return data.job;
+    } catch (err) {
+      if (!(err instanceof APIError) || err.status !== 404) throw err;
+      const data = await fetchAPI(`/jobs/by-slug/${encodeURIComponent(idOrSlug)}`);
+      return data.job;
+    }
   },
 
-  async incrementView(id: string): Promise<void> {
-    await fetchAPI(`/jobs/${id}/view`, {
+  async incrementView(id: string, variant?: 'a' | 'b'): Promise<void> {
+    const query = variant ? `?variant=${variant}` : '';
+    await fetchAPI(`/jobs/${id}/view${query}`, {
       method: 'POST',
     });
   },